@@ -0,0 +1 @@
+ALTER TABLE `bkiam`.`policy` ADD COLUMN `effect` tinyint(1) NOT NULL DEFAULT 0 AFTER `template_id`;
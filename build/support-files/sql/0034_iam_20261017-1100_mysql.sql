@@ -0,0 +1,2 @@
+/* TEXT字段不支持默认值, 已存在的策略为空字符串, 表示没有关联其它资源类型 */
+ALTER TABLE `bkiam`.`rbac_group_resource_policy` ADD COLUMN `related_resources` TEXT NOT NULL AFTER `resource_id`;  /* JSON */
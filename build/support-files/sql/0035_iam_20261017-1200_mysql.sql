@@ -0,0 +1,12 @@
+CREATE TABLE `bkiam`.`department_tree` (
+  `pk` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `department_pk` int(10) unsigned NOT NULL,
+  `parent_pk` int(10) unsigned NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  UNIQUE KEY `idx_uk_department` (`department_pk`),
+  INDEX `idx_parent` (`parent_pk`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `bkiam`.`subject_relation` ADD COLUMN `include_sub_department` tinyint(1) NOT NULL DEFAULT 0 AFTER `policy_expired_at`;
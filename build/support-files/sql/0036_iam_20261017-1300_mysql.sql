@@ -0,0 +1,13 @@
+CREATE TABLE `bkiam`.`subject_attribute` (
+  `pk` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `subject_pk` int(10) unsigned NOT NULL,
+  `name` varchar(64) NOT NULL,
+  `value` varchar(255) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  UNIQUE KEY `idx_uk_subject_name` (`subject_pk`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- 存量action的related_subject_attributes为'', 读取时按未配置处理
+ALTER TABLE `bkiam`.`saas_action` ADD COLUMN `related_subject_attributes` text NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE `bkiam`.`subject_relation` ADD COLUMN `effective_at` int(10) unsigned NOT NULL DEFAULT 0 AFTER `policy_expired_at`;
+ALTER TABLE `bkiam`.`subject_relation` ADD INDEX `idx_effective_at` (`effective_at`);
+
+ALTER TABLE `bkiam`.`subject_template_group` ADD COLUMN `effective_at` int(10) unsigned NOT NULL DEFAULT 0 AFTER `expired_at`;
+ALTER TABLE `bkiam`.`subject_template_group` ADD INDEX `idx_effective_at` (`effective_at`);
+
+ALTER TABLE `bkiam`.`policy` ADD COLUMN `effective_at` int(10) unsigned NOT NULL DEFAULT 0 AFTER `expired_at`;
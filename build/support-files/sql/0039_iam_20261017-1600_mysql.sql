@@ -0,0 +1,13 @@
+CREATE TABLE `bkiam`.`subject_delegation` (
+  `pk` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `delegator_pk` bigint(20) unsigned NOT NULL,
+  `delegatee_pk` bigint(20) unsigned NOT NULL,
+  `system_id` varchar(32) NOT NULL,
+  `action_pks` text NOT NULL,
+  `expired_at` int(10) unsigned NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  KEY `idx_delegatee` (`delegatee_pk`, `expired_at`),
+  KEY `idx_delegator` (`delegator_pk`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
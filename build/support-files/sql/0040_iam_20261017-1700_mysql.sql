@@ -0,0 +1,26 @@
+CREATE TABLE `bkiam`.`subject_group_history` (
+  `pk` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `subject_pk` bigint(20) unsigned NOT NULL,
+  `group_pk` bigint(20) unsigned NOT NULL,
+  `template_id` bigint(20) unsigned NOT NULL DEFAULT 0,
+  `action` varchar(16) NOT NULL,
+  `source` varchar(16) NOT NULL,
+  `expired_at` int(10) unsigned NOT NULL DEFAULT 0,
+  `effective_at` int(10) unsigned NOT NULL DEFAULT 0,
+  `actor` varchar(255) NOT NULL DEFAULT '',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  KEY `idx_group` (`group_pk`, `created_at`),
+  KEY `idx_subject` (`subject_pk`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+/* 为已存在的成员关系补录加入记录, 人员模板带来的成员关系同时存在于subject_relation, 只按模板记录 */
+INSERT INTO `bkiam`.`subject_group_history` (`subject_pk`, `group_pk`, `template_id`, `action`, `source`, `expired_at`, `effective_at`, `actor`, `created_at`)
+SELECT `sr`.`subject_pk`, `sr`.`parent_pk`, 0, 'add', 'manual', `sr`.`policy_expired_at`, `sr`.`effective_at`, '', `sr`.`created_at` FROM `bkiam`.`subject_relation` `sr`
+WHERE NOT EXISTS (
+  SELECT 1 FROM `bkiam`.`subject_template_group` `stg`
+  WHERE `stg`.`subject_pk` = `sr`.`subject_pk` AND `stg`.`group_pk` = `sr`.`parent_pk`
+);
+
+INSERT INTO `bkiam`.`subject_group_history` (`subject_pk`, `group_pk`, `template_id`, `action`, `source`, `expired_at`, `effective_at`, `actor`, `created_at`)
+SELECT `subject_pk`, `group_pk`, `template_id`, 'add', 'template', `expired_at`, `effective_at`, '', `created_at` FROM `bkiam`.`subject_template_group`;
@@ -0,0 +1,17 @@
+CREATE TABLE `bkiam`.`policy_history` (
+  `pk` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `subject_pk` bigint(20) unsigned NOT NULL,
+  `system_id` varchar(32) NOT NULL,
+  `action_pk` bigint(20) unsigned NOT NULL,
+  `template_id` bigint(20) unsigned NOT NULL DEFAULT 0,
+  `policy_pk` bigint(20) unsigned NOT NULL DEFAULT 0,
+  `operation` varchar(16) NOT NULL,
+  `before_policy` mediumtext NOT NULL,
+  `after_policy` mediumtext NOT NULL,
+  `client_id` varchar(32) NOT NULL DEFAULT '',
+  `actor` varchar(255) NOT NULL DEFAULT '',
+  `request_id` varchar(64) NOT NULL DEFAULT '',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  KEY `idx_subject_system` (`subject_pk`, `system_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -103,6 +103,8 @@ func Start() {
 	initQuota()
 	initWorker()
 	initSwitch()
+	initPolicyEval()
+	initNestedGroup()
 
 	// 2. watch the signal
 	ctx, cancelFunc := context.WithCancel(context.Background())
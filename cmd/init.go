@@ -17,6 +17,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
+	"iam/pkg/abac/pdp/evaluation"
 	"iam/pkg/api/common"
 	"iam/pkg/cache/redis"
 	"iam/pkg/cacheimpls"
@@ -25,6 +26,7 @@ import (
 	"iam/pkg/database"
 	"iam/pkg/logging"
 	"iam/pkg/metric"
+	"iam/pkg/service"
 	"iam/pkg/task"
 	"iam/pkg/util"
 )
@@ -207,3 +209,14 @@ func initWorker() {
 func initSwitch() {
 	common.InitSwitch(globalConfig.Switch)
 }
+
+func initPolicyEval() {
+	err := evaluation.InitErrorStrategy(globalConfig.PolicyEval.ErrorStrategy)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func initNestedGroup() {
+	service.InitMaxGroupNestingDepth(globalConfig.NestedGroup.MaxDepth)
+}
@@ -15,6 +15,15 @@ sentry:
 # use comma ”,“ separated when multiple app_code
 superAppCode: "bk_iam,bk_iam_app"
 
+# error strategy when a single allow policy fails to evaluate: skip(default) / fail_closed / strict
+# a deny policy which fails to evaluate always makes the request fail
+# policyEval:
+#   errorStrategy: skip
+
+# max nesting depth of group members which are groups, default 3
+# nestedGroup:
+#   maxDepth: 3
+
 databases:
   - id: "iam"
     host: "127.0.0.1"
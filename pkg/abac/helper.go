@@ -64,6 +64,19 @@ func ParseResourceNode(resource types.Resource) ([]types.ResourceNode, error) {
 	return resourceNodes, nil
 }
 
+// ParseResourceNodes 按顺序解析多个资源实例的资源节点, 用于操作关联多个资源类型的RBAC鉴权
+func ParseResourceNodes(resources []types.Resource) ([][]types.ResourceNode, error) {
+	resourceNodesList := make([][]types.ResourceNode, 0, len(resources))
+	for _, resource := range resources {
+		resourceNodes, err := ParseResourceNode(resource)
+		if err != nil {
+			return nil, err
+		}
+		resourceNodesList = append(resourceNodesList, resourceNodes)
+	}
+	return resourceNodesList, nil
+}
+
 func parseIamPath(iamPaths interface{}) ([]types.ResourceNode, error) {
 	resourceNodes := make([]types.ResourceNode, 0, 2)
 	paths := make([]string, 0, 2)
@@ -253,5 +253,53 @@ var _ = Describe("rbac", func() {
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "not found")
 		})
+
+		It("ParseResourceNodes ok", func() {
+			resourceNodesList, err := abac.ParseResourceNodes([]types.Resource{
+				{
+					System:    "test",
+					Type:      "t1",
+					ID:        "id1",
+					Attribute: map[string]interface{}{},
+				},
+				{
+					System: "test",
+					Type:   "set",
+					ID:     "id2",
+					Attribute: map[string]interface{}{
+						"_bk_iam_path_": "/cmdb,biz,1/",
+					},
+				},
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), [][]types.ResourceNode{
+				{
+					{System: "test", Type: "t1", ID: "id1", TypePK: 1},
+				},
+				{
+					{System: "cmdb", Type: "biz", ID: "1", TypePK: 2},
+					{System: "test", Type: "set", ID: "id2", TypePK: 3},
+				},
+			}, resourceNodesList)
+		})
+
+		It("ParseResourceNodes error", func() {
+			_, err := abac.ParseResourceNodes([]types.Resource{
+				{
+					System:    "test",
+					Type:      "t1",
+					ID:        "id1",
+					Attribute: map[string]interface{}{},
+				},
+				{
+					System:    "test",
+					Type:      "t2",
+					ID:        "id2",
+					Attribute: map[string]interface{}{},
+				},
+			})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "not found")
+		})
 	})
 })
@@ -14,6 +14,7 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/TencentBlueKing/gopkg/collection/set"
 	"github.com/TencentBlueKing/gopkg/errorx"
 
 	"iam/pkg/cacheimpls"
@@ -31,19 +32,26 @@ type DepartmentController interface {
 	BulkCreate(subjectDepartments []SubjectDepartment) error
 	BulkUpdate(subjectDepartments []SubjectDepartment) error
 	BulkDelete(subjectIDs []string) error
+
+	BulkUpdateParents(departmentParents []DepartmentParent) error
+	BulkDeleteParents(departmentIDs []string) error
 }
 
 type departmentController struct {
 	service service.DepartmentService
 
-	subjectService service.SubjectService
+	subjectService         service.SubjectService
+	groupService           service.GroupService
+	groupAlterEventService service.GroupAlterEventService
 }
 
 func NewDepartmentController() DepartmentController {
 	return &departmentController{
 		service: service.NewDepartmentService(),
 
-		subjectService: service.NewSubjectService(),
+		subjectService:         service.NewSubjectService(),
+		groupService:           service.NewGroupService(),
+		groupAlterEventService: service.NewGroupAlterEventService(),
 	}
 }
 
@@ -163,6 +171,138 @@ func (c *departmentController) BulkDelete(subjectIDs []string) error {
 	return nil
 }
 
+// BulkUpdateParents 批量设置部门的上级部门
+func (c *departmentController) BulkUpdateParents(departmentParents []DepartmentParent) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(DepartmentCTL, "BulkUpdateParents")
+
+	departmentPKs := make([]int64, 0, len(departmentParents))
+	svcDepartmentParents := make([]types.DepartmentParent, 0, len(departmentParents))
+	for _, dp := range departmentParents {
+		departmentPK, err := cacheimpls.GetLocalSubjectPK(types.DepartmentType, dp.DepartmentID)
+		if err != nil {
+			return errorWrapf(err, "cacheimpls.GetLocalSubjectPK id=`%s` fail", dp.DepartmentID)
+		}
+
+		var parentPK int64
+		if dp.ParentID != "" {
+			parentPK, err = cacheimpls.GetLocalSubjectPK(types.DepartmentType, dp.ParentID)
+			if err != nil {
+				return errorWrapf(err, "cacheimpls.GetLocalSubjectPK id=`%s` fail", dp.ParentID)
+			}
+		}
+
+		departmentPKs = append(departmentPKs, departmentPK)
+		svcDepartmentParents = append(svcDepartmentParents, types.DepartmentParent{
+			DepartmentPK: departmentPK,
+			ParentPK:     parentPK,
+		})
+	}
+
+	err := c.alterDepartmentTree(departmentPKs, func() error {
+		return c.service.BulkUpdateParents(svcDepartmentParents)
+	})
+	if err != nil {
+		return errorWrapf(err, "alterDepartmentTree departmentParents=`%+v` fail", departmentParents)
+	}
+	return nil
+}
+
+// BulkDeleteParents 批量删除部门的上级部门
+func (c *departmentController) BulkDeleteParents(departmentIDs []string) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(DepartmentCTL, "BulkDeleteParents")
+
+	departmentPKs := make([]int64, 0, len(departmentIDs))
+	for _, departmentID := range departmentIDs {
+		departmentPK, err := cacheimpls.GetLocalSubjectPK(types.DepartmentType, departmentID)
+		if err != nil {
+			// 兼容不存在的情况
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+
+			return errorWrapf(err, "cacheimpls.GetLocalSubjectPK id=`%s` fail", departmentID)
+		}
+		departmentPKs = append(departmentPKs, departmentPK)
+	}
+
+	err := c.alterDepartmentTree(departmentPKs, func() error {
+		return c.service.BulkDeleteParents(departmentPKs)
+	})
+	if err != nil {
+		return errorWrapf(err, "alterDepartmentTree departmentIDs=`%+v` fail", departmentIDs)
+	}
+	return nil
+}
+
+// alterDepartmentTree 变更部门树, 部门及其下级部门的上级部门会变化
+// 需要清理上级部门缓存, 以及通过上级部门(包含子部门)继承的用户组相关缓存, 并创建group_alter_event
+func (c *departmentController) alterDepartmentTree(departmentPKs []int64, alter func() error) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(DepartmentCTL, "alterDepartmentTree")
+	if len(departmentPKs) == 0 {
+		return nil
+	}
+
+	oldAncestorPKs, err := c.service.ListAncestorDepartmentPKs(departmentPKs)
+	if err != nil {
+		return errorWrapf(err, "service.ListAncestorDepartmentPKs departmentPKs=`%+v` fail", departmentPKs)
+	}
+
+	err = alter()
+	if err != nil {
+		return err
+	}
+
+	descendantPKs, err := c.service.ListDescendantDepartmentPKs(departmentPKs)
+	if err != nil {
+		return errorWrapf(err, "service.ListDescendantDepartmentPKs departmentPKs=`%+v` fail", departmentPKs)
+	}
+	affectedPKs := append(descendantPKs, departmentPKs...)
+
+	cacheimpls.BatchDeleteDepartmentAncestorCache(affectedPKs)
+
+	newAncestorPKs, err := c.service.ListAncestorDepartmentPKs(departmentPKs)
+	if err != nil {
+		return errorWrapf(err, "service.ListAncestorDepartmentPKs departmentPKs=`%+v` fail", departmentPKs)
+	}
+
+	// 变更前后上级部门以包含子部门方式加入的用户组, 受影响部门继承的权限可能发生变化
+	ancestorPKs := append(oldAncestorPKs, newAncestorPKs...)
+	if len(ancestorPKs) == 0 {
+		return nil
+	}
+
+	groupPKs, err := c.groupService.ListSubDepartmentGroupPKs(ancestorPKs)
+	if err != nil {
+		return errorWrapf(err, "groupService.ListSubDepartmentGroupPKs fail")
+	}
+	if len(groupPKs) == 0 {
+		return nil
+	}
+
+	cacheimpls.BatchDeleteSubjectAllSystemGroupCache(affectedPKs)
+
+	// 嵌套的上级用户组同样被继承
+	groupPKSet := set.NewInt64SetWithValues(groupPKs)
+	for _, groupPK := range groupPKs {
+		ancestorGroupPKs, err := c.groupService.ListAncestorGroupPKs(groupPK)
+		if err != nil {
+			return errorWrapf(err, "groupService.ListAncestorGroupPKs groupPK=`%d` fail", groupPK)
+		}
+		groupPKSet.Append(ancestorGroupPKs...)
+	}
+
+	for _, groupPK := range groupPKSet.ToSlice() {
+		err = c.groupAlterEventService.CreateByGroupSubject(groupPK, affectedPKs)
+		if err != nil {
+			return errorWrapf(
+				err, "groupAlterEventService.CreateByGroupSubject groupPK=`%d`, subjectPKs=`%+v` fail",
+				groupPK, affectedPKs,
+			)
+		}
+	}
+	return nil
+}
+
 func convertToServiceSubjectDepartments(subjectDepartments []SubjectDepartment) ([]types.SubjectDepartment, error) {
 	serviceSubjectDepartments := make([]types.SubjectDepartment, 0, len(subjectDepartments))
 	for _, subjectDepartment := range subjectDepartments {
@@ -11,6 +11,7 @@
 package pap
 
 import (
+	"database/sql"
 	"errors"
 
 	"github.com/agiledragon/gomonkey/v2"
@@ -325,4 +326,145 @@ var _ = Describe("DepartmentController", func() {
 			assert.NoError(GinkgoT(), err)
 		})
 	})
+
+	Describe("BulkUpdateParents", func() {
+		var ctl *gomock.Controller
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+
+			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+				switch id {
+				case "1":
+					return int64(1), nil
+				case "2":
+					return int64(2), nil
+				}
+				return 0, errors.New("not found")
+			})
+			patches.ApplyFunc(cacheimpls.BatchDeleteDepartmentAncestorCache, func(pks []int64) error {
+				return nil
+			})
+			patches.ApplyFunc(cacheimpls.BatchDeleteSubjectAllSystemGroupCache, func(pks []int64) {})
+		})
+		AfterEach(func() {
+			ctl.Finish()
+			patches.Reset()
+		})
+
+		It("cacheimpls.GetLocalSubjectPK fail", func() {
+			manager := &departmentController{}
+
+			err := manager.BulkUpdateParents([]DepartmentParent{{DepartmentID: "1", ParentID: "3"}})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "GetLocalSubjectPK")
+		})
+
+		It("service.BulkUpdateParents fail", func() {
+			mockDepartmentService := mock.NewMockDepartmentService(ctl)
+			mockDepartmentService.EXPECT().ListAncestorDepartmentPKs([]int64{1}).Return(nil, nil)
+			mockDepartmentService.EXPECT().BulkUpdateParents(
+				[]types.DepartmentParent{{DepartmentPK: 1, ParentPK: 2}},
+			).Return(errors.New("error"))
+
+			manager := &departmentController{
+				service: mockDepartmentService,
+			}
+
+			err := manager.BulkUpdateParents([]DepartmentParent{{DepartmentID: "1", ParentID: "2"}})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "alterDepartmentTree")
+		})
+
+		It("ok, no sub department group", func() {
+			mockDepartmentService := mock.NewMockDepartmentService(ctl)
+			mockDepartmentService.EXPECT().ListAncestorDepartmentPKs([]int64{1}).Return(nil, nil)
+			mockDepartmentService.EXPECT().BulkUpdateParents(
+				[]types.DepartmentParent{{DepartmentPK: 1, ParentPK: 2}},
+			).Return(nil)
+			mockDepartmentService.EXPECT().ListDescendantDepartmentPKs([]int64{1}).Return(nil, nil)
+			mockDepartmentService.EXPECT().ListAncestorDepartmentPKs([]int64{1}).Return([]int64{2}, nil)
+
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListSubDepartmentGroupPKs([]int64{2}).Return(nil, nil)
+
+			manager := &departmentController{
+				service:      mockDepartmentService,
+				groupService: mockGroupService,
+			}
+
+			err := manager.BulkUpdateParents([]DepartmentParent{{DepartmentID: "1", ParentID: "2"}})
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("ok", func() {
+			mockDepartmentService := mock.NewMockDepartmentService(ctl)
+			mockDepartmentService.EXPECT().ListAncestorDepartmentPKs([]int64{1}).Return([]int64{2}, nil)
+			mockDepartmentService.EXPECT().BulkUpdateParents(
+				[]types.DepartmentParent{{DepartmentPK: 1, ParentPK: 0}},
+			).Return(nil)
+			mockDepartmentService.EXPECT().ListDescendantDepartmentPKs([]int64{1}).Return([]int64{3}, nil)
+			mockDepartmentService.EXPECT().ListAncestorDepartmentPKs([]int64{1}).Return(nil, nil)
+
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListSubDepartmentGroupPKs([]int64{2}).Return([]int64{10}, nil)
+			mockGroupService.EXPECT().ListAncestorGroupPKs(int64(10)).Return([]int64{20}, nil)
+
+			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
+			mockGroupAlterEventService.EXPECT().CreateByGroupSubject(int64(10), []int64{3, 1}).Return(nil)
+			mockGroupAlterEventService.EXPECT().CreateByGroupSubject(int64(20), []int64{3, 1}).Return(nil)
+
+			manager := &departmentController{
+				service:                mockDepartmentService,
+				groupService:           mockGroupService,
+				groupAlterEventService: mockGroupAlterEventService,
+			}
+
+			err := manager.BulkUpdateParents([]DepartmentParent{{DepartmentID: "1", ParentID: ""}})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("BulkDeleteParents", func() {
+		var ctl *gomock.Controller
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+
+			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+				if id == "1" {
+					return int64(1), nil
+				}
+				return 0, sql.ErrNoRows
+			})
+			patches.ApplyFunc(cacheimpls.BatchDeleteDepartmentAncestorCache, func(pks []int64) error {
+				return nil
+			})
+		})
+		AfterEach(func() {
+			ctl.Finish()
+			patches.Reset()
+		})
+
+		It("empty", func() {
+			manager := &departmentController{}
+
+			err := manager.BulkDeleteParents([]string{"2"})
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("ok", func() {
+			mockDepartmentService := mock.NewMockDepartmentService(ctl)
+			mockDepartmentService.EXPECT().ListAncestorDepartmentPKs([]int64{1}).Return(nil, nil).Times(2)
+			mockDepartmentService.EXPECT().BulkDeleteParents([]int64{1}).Return(nil)
+			mockDepartmentService.EXPECT().ListDescendantDepartmentPKs([]int64{1}).Return(nil, nil)
+
+			manager := &departmentController{
+				service: mockDepartmentService,
+			}
+
+			err := manager.BulkDeleteParents([]string{"1", "2"})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
 })
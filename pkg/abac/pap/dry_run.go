@@ -0,0 +1,336 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"sort"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service"
+	svctypes "iam/pkg/service/types"
+)
+
+// newGroupAlterEventPreview 生成的事件会按subject与action展开, 任一为空时不会生成事件
+func newGroupAlterEventPreview(groupPK int64, subjectCount, actionCount int) (GroupAlterEventPreview, bool, error) {
+	if subjectCount == 0 || actionCount == 0 {
+		return GroupAlterEventPreview{}, false, nil
+	}
+
+	group, err := cacheimpls.GetSubjectByPK(groupPK)
+	if err != nil {
+		return GroupAlterEventPreview{}, false, err
+	}
+
+	return GroupAlterEventPreview{
+		GroupID:            group.ID,
+		SubjectCount:       int64(subjectCount),
+		ActionCount:        int64(actionCount),
+		SubjectActionCount: int64(subjectCount * actionCount),
+	}, true, nil
+}
+
+// newGroupMembersDryRunResult 根据成员变更计划, 模拟事务提交后的事件生成, 计算受影响的subject
+func (c *groupController) newGroupMembersDryRunResult(
+	plan *groupMembersAlterPlan,
+) (result GroupMembersDryRunResult, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "newGroupMembersDryRunResult")
+
+	// subjectTemplateGroups与members一一对应
+	memberMap := make(map[int64]GroupMember, len(plan.members))
+	for i, relation := range plan.subjectTemplateGroups {
+		memberMap[relation.SubjectPK] = plan.members[i]
+	}
+	convertToMembers := func(relations []svctypes.SubjectTemplateGroup) []GroupMember {
+		members := make([]GroupMember, 0, len(relations))
+		for _, relation := range relations {
+			m := memberMap[relation.SubjectPK]
+			members = append(members, GroupMember{
+				Type:        m.Type,
+				ID:          m.ID,
+				ExpiredAt:   relation.ExpiredAt,
+				EffectiveAt: relation.EffectiveAt,
+			})
+		}
+		return members
+	}
+
+	result.TypeCount = plan.typeCount
+	result.CreatedMembers = convertToMembers(plan.createMembers)
+	result.UpdatedMembers = convertToMembers(plan.updateMembers)
+
+	// 1. 立即生效的新增与续期成员, 用户组成员需展开其下的所有user/department
+	affectedSubjectPKSet := set.NewInt64Set()
+	changedRelations := make([]svctypes.SubjectTemplateGroup, 0, len(plan.createMembers)+len(plan.updateMembers))
+	changedRelations = append(changedRelations, plan.createMembers...)
+	changedRelations = append(changedRelations, plan.updateMembers...)
+	needUpdateSubjectPKs := make([]int64, 0, len(changedRelations))
+	changedGroupPKs := make([]int64, 0, len(plan.memberGroupPKs))
+	for _, relation := range changedRelations {
+		if !relation.NeedUpdate {
+			continue
+		}
+
+		needUpdateSubjectPKs = append(needUpdateSubjectPKs, relation.SubjectPK)
+		if memberMap[relation.SubjectPK].Type == svctypes.GroupType {
+			changedGroupPKs = append(changedGroupPKs, relation.SubjectPK)
+		} else {
+			affectedSubjectPKSet.Add(relation.SubjectPK)
+		}
+	}
+	if len(changedGroupPKs) != 0 {
+		nestedSubjectPKs, err := c.service.ListNestedGroupMemberSubjectPKs(changedGroupPKs)
+		if err != nil {
+			return result, errorWrapf(
+				err, "service.ListNestedGroupMemberSubjectPKs groupPKs=`%+v` fail", changedGroupPKs,
+			)
+		}
+		affectedSubjectPKSet.Append(nestedSubjectPKs...)
+	}
+	result.AffectedSubjectCount = int64(affectedSubjectPKSet.Size())
+
+	// 2. 与deleteSubjectTemplateGroupCache, alterNestedGroupMembers生成的事件保持一致
+	events := newGroupAlterEventPreviews()
+	events.add(plan.groupPK, needUpdateSubjectPKs)
+
+	ancestorGroupPKs, err := c.service.ListAncestorGroupPKs(plan.groupPK)
+	if err != nil {
+		return result, errorWrapf(err, "service.ListAncestorGroupPKs groupPK=`%d` fail", plan.groupPK)
+	}
+	for _, ancestorGroupPK := range ancestorGroupPKs {
+		events.add(ancestorGroupPK, plan.subjectPKs)
+	}
+	if len(plan.memberGroupPKs) != 0 {
+		nestedSubjectPKs, err := c.service.ListNestedGroupMemberSubjectPKs(plan.memberGroupPKs)
+		if err != nil {
+			return result, errorWrapf(
+				err, "service.ListNestedGroupMemberSubjectPKs groupPKs=`%+v` fail", plan.memberGroupPKs,
+			)
+		}
+		for _, groupPK := range append([]int64{plan.groupPK}, ancestorGroupPKs...) {
+			events.add(groupPK, nestedSubjectPKs)
+		}
+	}
+
+	result.Events, err = events.build(c.groupAlterEventService)
+	if err != nil {
+		return result, errorWrapf(err, "build group alter event previews fail")
+	}
+	return result, nil
+}
+
+// newGroupMembersDeleteDryRunResult 根据将要删除的成员, 模拟DeleteGroupMembers事务提交后的事件生成
+func (c *groupController) newGroupMembersDeleteDryRunResult(
+	groupPK int64,
+	deletedMembers []GroupMember,
+	subjectPKs, memberGroupPKs, subDepartmentPKs []int64,
+) (result GroupMembersDryRunResult, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "newGroupMembersDeleteDryRunResult")
+
+	result.TypeCount = map[string]int64{
+		svctypes.UserType:       0,
+		svctypes.DepartmentType: 0,
+		svctypes.GroupType:      0,
+	}
+	for _, m := range deletedMembers {
+		result.TypeCount[m.Type]++
+	}
+	result.DeletedMembers = deletedMembers
+
+	var nestedSubjectPKs []int64
+	if len(memberGroupPKs) != 0 {
+		nestedSubjectPKs, err = c.service.ListNestedGroupMemberSubjectPKs(memberGroupPKs)
+		if err != nil {
+			return result, errorWrapf(err, "service.ListNestedGroupMemberSubjectPKs groupPKs=`%+v` fail", memberGroupPKs)
+		}
+	}
+
+	affectedSubjectPKSet := set.NewInt64Set()
+	affectedSubjectPKSet.Append(subjectPKs...)
+	affectedSubjectPKSet.Append(nestedSubjectPKs...)
+	affectedSubjectPKSet.Append(subDepartmentPKs...)
+	result.AffectedSubjectCount = int64(affectedSubjectPKSet.Size())
+
+	// 与DeleteGroupMembers中createGroupAlterEvent, alterNestedGroupMembers, alterSubDepartmentMembers保持一致
+	ancestorGroupPKs, err := c.service.ListAncestorGroupPKs(groupPK)
+	if err != nil {
+		return result, errorWrapf(err, "service.ListAncestorGroupPKs groupPK=`%d` fail", groupPK)
+	}
+
+	events := newGroupAlterEventPreviews()
+	for _, pk := range append([]int64{groupPK}, ancestorGroupPKs...) {
+		events.add(pk, subjectPKs)
+		events.add(pk, nestedSubjectPKs)
+		events.add(pk, subDepartmentPKs)
+	}
+
+	result.Events, err = events.build(c.groupAlterEventService)
+	if err != nil {
+		return result, errorWrapf(err, "build group alter event previews fail")
+	}
+	return result, nil
+}
+
+// groupAlterEventPreviews 按用户组汇总将会生成的group_alter_event的subject
+type groupAlterEventPreviews map[int64]*set.Int64Set
+
+func newGroupAlterEventPreviews() groupAlterEventPreviews {
+	return groupAlterEventPreviews{}
+}
+
+func (e groupAlterEventPreviews) add(groupPK int64, subjectPKs []int64) {
+	if len(subjectPKs) == 0 {
+		return
+	}
+
+	if _, ok := e[groupPK]; !ok {
+		e[groupPK] = set.NewInt64Set()
+	}
+	e[groupPK].Append(subjectPKs...)
+}
+
+func (e groupAlterEventPreviews) build(
+	groupAlterEventService service.GroupAlterEventService,
+) ([]GroupAlterEventPreview, error) {
+	groupPKs := make([]int64, 0, len(e))
+	for groupPK := range e {
+		groupPKs = append(groupPKs, groupPK)
+	}
+	sort.Slice(groupPKs, func(i, j int) bool { return groupPKs[i] < groupPKs[j] })
+
+	events := make([]GroupAlterEventPreview, 0, len(groupPKs))
+	for _, groupPK := range groupPKs {
+		actionPKs, err := groupAlterEventService.ListGroupActionPKs(groupPK)
+		if err != nil {
+			return nil, errorx.Wrapf(
+				err, GroupCTL, "groupAlterEventPreviews.build",
+				"groupAlterEventService.ListGroupActionPKs groupPK=`%d` fail", groupPK,
+			)
+		}
+
+		event, ok, err := newGroupAlterEventPreview(groupPK, e[groupPK].Size(), len(actionPKs))
+		if err != nil {
+			return nil, errorx.Wrapf(
+				err, GroupCTL, "groupAlterEventPreviews.build", "newGroupAlterEventPreview groupPK=`%d` fail", groupPK,
+			)
+		}
+		if ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// newPoliciesDryRunResult 根据事务中的策略变更, 计算受影响的subject与将会生成的RBAC事件
+func (c *policyController) newPoliciesDryRunResult(
+	alterResult groupPoliciesAlterResult,
+) (result PoliciesDryRunResult, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "newPoliciesDryRunResult")
+
+	actionIDMap := make(map[int64]string, len(alterResult.actionPKMap))
+	for actionID, actionPK := range alterResult.actionPKMap {
+		actionIDMap[actionPK] = actionID
+	}
+
+	fillPolicyChanges(&result, actionIDMap, alterResult.histories)
+
+	// 与createRBACGroupAlterEvent保持一致
+	actionPKSet := set.NewInt64Set()
+	for _, rcc := range alterResult.resourceChangedContents {
+		actionPKSet.Append(rcc.CreatedActionPKs...)
+		actionPKSet.Append(rcc.DeletedActionPKs...)
+	}
+
+	result.Events = []GroupAlterEventPreview{}
+	if len(alterResult.histories) == 0 && actionPKSet.Size() == 0 && !alterResult.authTypeChanged {
+		return result, nil
+	}
+
+	// 用户组的权限变更会影响所有成员
+	subjectPKs, err := c.groupAlterEventService.ListAffectedSubjectPKs(alterResult.subjectPK)
+	if err != nil {
+		return result, errorWrapf(
+			err, "groupAlterEventService.ListAffectedSubjectPKs groupPK=`%d` fail", alterResult.subjectPK,
+		)
+	}
+	result.AffectedSubjectCount = int64(len(subjectPKs))
+
+	event, ok, err := newGroupAlterEventPreview(alterResult.subjectPK, len(subjectPKs), actionPKSet.Size())
+	if err != nil {
+		return result, errorWrapf(err, "newGroupAlterEventPreview groupPK=`%d` fail", alterResult.subjectPK)
+	}
+	if ok {
+		result.Events = append(result.Events, event)
+	}
+
+	return result, nil
+}
+
+// newCustomPoliciesDryRunResult 自定义权限变更只影响ABAC鉴权, 不会生成group_alter_event
+func (c *policyController) newCustomPoliciesDryRunResult(
+	subjectType string,
+	subjectPK int64,
+	actionIDMap map[int64]string,
+	histories []svctypes.PolicyHistory,
+) (result PoliciesDryRunResult, err error) {
+	fillPolicyChanges(&result, actionIDMap, histories)
+
+	result.Events = []GroupAlterEventPreview{}
+	if len(histories) == 0 {
+		return result, nil
+	}
+
+	// 用户组的权限变更会影响所有成员
+	if subjectType != svctypes.GroupType {
+		result.AffectedSubjectCount = 1
+		return result, nil
+	}
+
+	subjectPKs, err := c.groupAlterEventService.ListAffectedSubjectPKs(subjectPK)
+	if err != nil {
+		return result, errorx.Wrapf(
+			err, PolicyCTL, "newCustomPoliciesDryRunResult",
+			"groupAlterEventService.ListAffectedSubjectPKs groupPK=`%d` fail", subjectPK,
+		)
+	}
+	result.AffectedSubjectCount = int64(len(subjectPKs))
+
+	return result, nil
+}
+
+func fillPolicyChanges(
+	result *PoliciesDryRunResult,
+	actionIDMap map[int64]string,
+	histories []svctypes.PolicyHistory,
+) {
+	result.CreatedPolicies = []PolicyChange{}
+	result.UpdatedPolicies = []PolicyChange{}
+	result.DeletedPolicies = []PolicyChange{}
+	for _, h := range histories {
+		change := PolicyChange{
+			ActionID:   actionIDMap[h.ActionPK],
+			TemplateID: h.TemplateID,
+			Before:     convertToPolicySnapshot(h.Before),
+			After:      convertToPolicySnapshot(h.After),
+		}
+
+		switch h.Operation {
+		case svctypes.PolicyHistoryOperationCreate:
+			result.CreatedPolicies = append(result.CreatedPolicies, change)
+		case svctypes.PolicyHistoryOperationUpdate:
+			result.UpdatedPolicies = append(result.UpdatedPolicies, change)
+		case svctypes.PolicyHistoryOperationDelete:
+			result.DeletedPolicies = append(result.DeletedPolicies, change)
+		}
+	}
+}
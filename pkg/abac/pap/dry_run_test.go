@@ -0,0 +1,323 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"reflect"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/types"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service/mock"
+	svctypes "iam/pkg/service/types"
+)
+
+var _ = Describe("DryRun", func() {
+	Describe("DryRunCreateOrUpdateGroupMembers", func() {
+		var ctl *gomock.Controller
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+				switch id {
+				case "1":
+					return int64(1), nil
+				case "2":
+					return int64(2), nil
+				case "3":
+					return int64(3), nil
+				}
+				return 0, nil
+			})
+			patches.ApplyFunc(cacheimpls.GetSubjectByPK, func(pk int64) (svctypes.Subject, error) {
+				return svctypes.Subject{Type: "group", ID: "1"}, nil
+			})
+			helper := &subjectGroupHelper{}
+			patches.ApplyPrivateMethod(reflect.TypeOf(helper), "getSubjectGroup", func(
+				_ *subjectGroupHelper, subjectPK, groupPK int64,
+			) (authorized bool, subjectGroup *svctypes.ThinSubjectGroup, err error) {
+				return true, nil, nil
+			})
+		})
+		AfterEach(func() {
+			ctl.Finish()
+			patches.Reset()
+		})
+
+		It("ok, no writes", func() {
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListGroupMember(int64(1)).Return([]svctypes.GroupMember{}, nil)
+			mockGroupService.EXPECT().CheckNestedGroupMembers(int64(1), []int64{3}).Return(nil)
+			mockGroupService.EXPECT().ListNestedGroupMemberSubjectPKs([]int64{3}).Return([]int64{4, 5}, nil).Times(2)
+			mockGroupService.EXPECT().ListAncestorGroupPKs(int64(1)).Return([]int64{}, nil)
+
+			mockSoDConstraintService := mock.NewMockSoDConstraintService(ctl)
+			mockSoDConstraintService.EXPECT().CheckGroupMembers(int64(1), []int64{2, 3}).Return(nil)
+			mockSoDConstraintService.EXPECT().CheckGroupMemberActions(int64(1), []int64{2, 3}).Return(nil)
+
+			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
+			mockGroupAlterEventService.EXPECT().ListGroupActionPKs(int64(1)).Return([]int64{7, 8}, nil)
+
+			manager := &groupController{
+				service:                mockGroupService,
+				groupAlterEventService: mockGroupAlterEventService,
+				sodConstraintService:   mockSoDConstraintService,
+			}
+
+			result, err := manager.DryRunCreateOrUpdateGroupMembers("group", "1", []GroupMember{
+				{Type: "user", ID: "2", ExpiredAt: 10},
+				{Type: "group", ID: "3", ExpiredAt: 10},
+			})
+			assert.NoError(GinkgoT(), err)
+
+			assert.Equal(GinkgoT(), map[string]int64{"user": 1, "department": 0, "group": 1}, result.TypeCount)
+			assert.Len(GinkgoT(), result.CreatedMembers, 2)
+			assert.Equal(GinkgoT(), "3", result.CreatedMembers[1].ID)
+			assert.Len(GinkgoT(), result.UpdatedMembers, 0)
+			// user 2 + group 3下的4, 5
+			assert.Equal(GinkgoT(), int64(3), result.AffectedSubjectCount)
+			// group 1: 2, 3, 4, 5 x 2 actions
+			assert.Equal(GinkgoT(), []GroupAlterEventPreview{{
+				GroupID:            "1",
+				SubjectCount:       4,
+				ActionCount:        2,
+				SubjectActionCount: 8,
+			}}, result.Events)
+		})
+	})
+
+	Describe("DryRunDeleteGroupMembers", func() {
+		var ctl *gomock.Controller
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+				switch id {
+				case "2":
+					return int64(2), nil
+				case "3":
+					return int64(3), nil
+				case "4":
+					return int64(4), nil
+				}
+				return int64(1), nil
+			})
+			patches.ApplyFunc(cacheimpls.GetSubjectByPK, func(pk int64) (svctypes.Subject, error) {
+				return svctypes.Subject{Type: "group", ID: "g1"}, nil
+			})
+		})
+		AfterEach(func() {
+			ctl.Finish()
+			patches.Reset()
+		})
+
+		It("ok, only existing members", func() {
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListGroupMember(int64(1)).Return([]svctypes.GroupMember{
+				{SubjectPK: 2, ExpiredAt: 10},
+				{SubjectPK: 3, ExpiredAt: 20},
+			}, nil)
+			mockGroupService.EXPECT().ListSubDepartmentMemberPKs(int64(1), []int64{3}).Return([]int64{5}, nil)
+			mockGroupService.EXPECT().ListAncestorGroupPKs(int64(1)).Return([]int64{}, nil)
+
+			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
+			mockGroupAlterEventService.EXPECT().ListGroupActionPKs(int64(1)).Return([]int64{7}, nil)
+
+			manager := &groupController{
+				service:                mockGroupService,
+				groupAlterEventService: mockGroupAlterEventService,
+			}
+
+			result, err := manager.DryRunDeleteGroupMembers("group", "1", []Subject{
+				{Type: "user", ID: "2"},
+				{Type: "department", ID: "3"},
+				{Type: "user", ID: "4"},
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[string]int64{"user": 1, "department": 1, "group": 0}, result.TypeCount)
+			assert.Equal(GinkgoT(), []GroupMember{
+				{Type: "user", ID: "2", ExpiredAt: 10},
+				{Type: "department", ID: "3", ExpiredAt: 20},
+			}, result.DeletedMembers)
+			// user 2, department 3及其子部门5
+			assert.Equal(GinkgoT(), int64(3), result.AffectedSubjectCount)
+			assert.Equal(GinkgoT(), []GroupAlterEventPreview{{
+				GroupID:            "g1",
+				SubjectCount:       3,
+				ActionCount:        1,
+				SubjectActionCount: 3,
+			}}, result.Events)
+		})
+	})
+
+	Describe("DryRunAlterCustomPolicies", func() {
+		var ctl *gomock.Controller
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("ok, no writes", func() {
+			mockSubjectService := mock.NewMockSubjectService(ctl)
+			mockSubjectService.EXPECT().GetPK("user", "test").Return(int64(1), nil)
+
+			mockActionService := mock.NewMockActionService(ctl)
+			mockActionService.EXPECT().ListThinActionBySystem("test").Return([]svctypes.ThinAction{
+				{PK: 10, ID: "view"}, {PK: 11, ID: "edit"},
+			}, nil)
+			mockActionService.EXPECT().ListActionResourceTypeIDByActionSystem("test").Return(
+				[]svctypes.ActionResourceTypeID{}, nil,
+			)
+
+			mockSoDConstraintService := mock.NewMockSoDConstraintService(ctl)
+			mockSoDConstraintService.EXPECT().CheckSubjectActions(int64(1), []int64{10}, []int64{2}).Return(nil)
+
+			mockPolicyService := mock.NewMockPolicyService(ctl)
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{2}).Return([]svctypes.Policy{
+				{ID: 2, SubjectPK: 1, ActionPK: 11, Expression: "[]", ExpiredAt: 10},
+			}, nil)
+
+			c := &policyController{
+				subjectService:       mockSubjectService,
+				actionService:        mockActionService,
+				sodConstraintService: mockSoDConstraintService,
+				policyService:        mockPolicyService,
+			}
+
+			result, err := c.DryRunAlterCustomPolicies(
+				"test", "user", "test",
+				[]types.Policy{{Action: types.Action{ID: "view"}, Expression: "[]", ExpiredAt: 10}},
+				[]types.Policy{}, []int64{2}, Operator{},
+			)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), result.CreatedPolicies, 1)
+			assert.Equal(GinkgoT(), "view", result.CreatedPolicies[0].ActionID)
+			assert.Len(GinkgoT(), result.DeletedPolicies, 1)
+			assert.Equal(GinkgoT(), "edit", result.DeletedPolicies[0].ActionID)
+			assert.Equal(GinkgoT(), int64(1), result.AffectedSubjectCount)
+			assert.Empty(GinkgoT(), result.Events)
+		})
+	})
+
+	Describe("DryRunDeleteByIDs", func() {
+		var ctl *gomock.Controller
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("ok, group", func() {
+			mockSubjectService := mock.NewMockSubjectService(ctl)
+			mockSubjectService.EXPECT().GetPK("group", "g1").Return(int64(1), nil)
+
+			mockActionService := mock.NewMockActionService(ctl)
+			mockActionService.EXPECT().ListThinActionBySystem("test").Return([]svctypes.ThinAction{
+				{PK: 10, ID: "view"},
+			}, nil)
+
+			mockPolicyService := mock.NewMockPolicyService(ctl)
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{2, 3}).Return([]svctypes.Policy{
+				{ID: 2, SubjectPK: 1, ActionPK: 10, Expression: "[]", ExpiredAt: 10},
+			}, nil)
+
+			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
+			mockGroupAlterEventService.EXPECT().ListAffectedSubjectPKs(int64(1)).Return([]int64{4, 5}, nil)
+
+			c := &policyController{
+				subjectService:         mockSubjectService,
+				actionService:          mockActionService,
+				policyService:          mockPolicyService,
+				groupAlterEventService: mockGroupAlterEventService,
+			}
+
+			result, err := c.DryRunDeleteByIDs("test", "group", "g1", []int64{2, 3}, Operator{})
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), result.CreatedPolicies)
+			assert.Len(GinkgoT(), result.DeletedPolicies, 1)
+			assert.Equal(GinkgoT(), "view", result.DeletedPolicies[0].ActionID)
+			assert.Equal(GinkgoT(), int64(2), result.AffectedSubjectCount)
+			assert.Empty(GinkgoT(), result.Events)
+		})
+	})
+
+	Describe("newPoliciesDryRunResult", func() {
+		var ctl *gomock.Controller
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+			patches = gomonkey.ApplyFunc(cacheimpls.GetSubjectByPK, func(pk int64) (svctypes.Subject, error) {
+				return svctypes.Subject{Type: "group", ID: "g1"}, nil
+			})
+		})
+		AfterEach(func() {
+			ctl.Finish()
+			patches.Reset()
+		})
+
+		It("no change", func() {
+			c := &policyController{}
+			result, err := c.newPoliciesDryRunResult(groupPoliciesAlterResult{subjectPK: 1})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), int64(0), result.AffectedSubjectCount)
+			assert.Empty(GinkgoT(), result.CreatedPolicies)
+			assert.Empty(GinkgoT(), result.Events)
+		})
+
+		It("ok", func() {
+			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
+			mockGroupAlterEventService.EXPECT().ListAffectedSubjectPKs(int64(1)).Return([]int64{2, 3, 4}, nil)
+
+			c := &policyController{groupAlterEventService: mockGroupAlterEventService}
+			result, err := c.newPoliciesDryRunResult(groupPoliciesAlterResult{
+				subjectPK:   1,
+				actionPKMap: map[string]int64{"view": 10, "edit": 11},
+				histories: []svctypes.PolicyHistory{
+					{
+						ActionPK:  10,
+						Operation: svctypes.PolicyHistoryOperationCreate,
+						After:     &svctypes.PolicySnapshot{Expression: "[]", ExpiredAt: 10, Effect: 1},
+					},
+					{
+						ActionPK:  11,
+						Operation: svctypes.PolicyHistoryOperationDelete,
+						Before:    &svctypes.PolicySnapshot{Expression: "[]", ExpiredAt: 10, Effect: 1},
+					},
+				},
+				resourceChangedContents: []svctypes.ResourceChangedContent{
+					{CreatedActionPKs: []int64{10}, DeletedActionPKs: []int64{11}},
+					{CreatedActionPKs: []int64{10}},
+				},
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), result.CreatedPolicies, 1)
+			assert.Equal(GinkgoT(), "view", result.CreatedPolicies[0].ActionID)
+			assert.Nil(GinkgoT(), result.CreatedPolicies[0].Before)
+			assert.Len(GinkgoT(), result.UpdatedPolicies, 0)
+			assert.Len(GinkgoT(), result.DeletedPolicies, 1)
+			assert.Equal(GinkgoT(), "edit", result.DeletedPolicies[0].ActionID)
+			assert.Equal(GinkgoT(), int64(3), result.AffectedSubjectCount)
+			assert.Equal(GinkgoT(), []GroupAlterEventPreview{{
+				GroupID:            "g1",
+				SubjectCount:       3,
+				ActionCount:        2,
+				SubjectActionCount: 6,
+			}}, result.Events)
+		})
+	})
+})
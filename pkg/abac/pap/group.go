@@ -40,7 +40,8 @@ const GroupCTL = "GroupCTL"
 type GroupController interface {
 	GetSubjectGroupCountBeforeExpiredAt(_type, id string, beforeExpiredAt int64) (int64, error)
 	GetSubjectSystemGroupCountBeforeExpiredAt(_type, id, systemID string, expiredAt int64) (int64, error)
-	ListPagingSubjectGroups(_type, id string, beforeExpiredAt, limit, offset int64) ([]SubjectGroup, error)
+	ListPagingSubjectGroups(_type, id string, beforeExpiredAt, at, limit, offset int64) ([]SubjectGroup, error)
+	GetSubjectGroupCountAt(_type, id string, beforeExpiredAt, at int64) (int64, error)
 	ListPagingSubjectSystemGroups(
 		_type, id, systemID string, beforeExpiredAt, limit, offset int64,
 	) ([]SubjectGroup, error)
@@ -59,12 +60,17 @@ type GroupController interface {
 	GetTemplateGroupMemberCount(_type, id string, templateID int64) (int64, error)
 	ListPagingTemplateGroupMember(_type, id string, templateID int64, limit, offset int64) ([]GroupMember, error)
 
-	CreateOrUpdateGroupMembers(_type, id string, members []GroupMember) (map[string]int64, error)
-	UpdateGroupMembersExpiredAt(_type, id string, members []GroupMember) error
-	DeleteGroupMembers(_type, id string, members []Subject) (map[string]int64, error)
-	BulkCreateSubjectTemplateGroup(subjectTemplateGroups []SubjectTemplateGroup) error
-	BulkDeleteSubjectTemplateGroup(subjectTemplateGroups []SubjectTemplateGroup) error
-	UpdateSubjectTemplateGroupExpiredAt(subjectTemplateGroups []SubjectTemplateGroup) error
+	CreateOrUpdateGroupMembers(_type, id string, members []GroupMember, actor string) (map[string]int64, error)
+	DryRunCreateOrUpdateGroupMembers(_type, id string, members []GroupMember) (GroupMembersDryRunResult, error)
+	DryRunDeleteGroupMembers(_type, id string, members []Subject) (GroupMembersDryRunResult, error)
+	UpdateGroupMembersExpiredAt(_type, id string, members []GroupMember, actor string) error
+	DeleteGroupMembers(_type, id string, members []Subject, actor string) (map[string]int64, error)
+	BulkCreateSubjectTemplateGroup(subjectTemplateGroups []SubjectTemplateGroup, actor string) error
+	BulkDeleteSubjectTemplateGroup(subjectTemplateGroups []SubjectTemplateGroup, actor string) error
+	UpdateSubjectTemplateGroupExpiredAt(subjectTemplateGroups []SubjectTemplateGroup, actor string) error
+	UpdateDepartmentMembersIncludeSubDepartment(
+		_type, id string, departmentIDs []string, includeSubDepartment bool,
+	) error
 
 	ListRbacGroupByResource(systemID string, resource abacTypes.Resource) ([]Subject, error)
 	ListRbacGroupByActionResource(systemID, actionID string, resource abacTypes.Resource) ([]Subject, error)
@@ -76,6 +82,8 @@ type groupController struct {
 	subjectService             service.SubjectService
 	groupAlterEventService     service.GroupAlterEventService
 	groupResourcePolicyService service.GroupResourcePolicyService
+	sodConstraintService       service.SoDConstraintService
+	subjectGroupHistoryService service.SubjectGroupHistoryService
 }
 
 func NewGroupController() GroupController {
@@ -84,6 +92,8 @@ func NewGroupController() GroupController {
 		subjectService:             service.NewSubjectService(),
 		groupAlterEventService:     service.NewGroupAlterEventService(),
 		groupResourcePolicyService: service.NewGroupResourcePolicyService(),
+		sodConstraintService:       service.NewSoDConstraintService(),
+		subjectGroupHistoryService: service.NewSubjectGroupHistoryService(),
 	}
 }
 
@@ -319,9 +329,10 @@ func (c *groupController) CheckSubjectEffectGroups(
 }
 
 // ListPagingSubjectGroups ...
+// at大于0时, 通过成员变更记录查询subject在at时刻直接加入的用户组
 func (c *groupController) ListPagingSubjectGroups(
 	_type, id string,
-	beforeExpiredAt, limit, offset int64,
+	beforeExpiredAt, at, limit, offset int64,
 ) ([]SubjectGroup, error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "ListPagingSubjectGroups")
 	subjectPK, err := cacheimpls.GetLocalSubjectPK(_type, id)
@@ -329,12 +340,21 @@ func (c *groupController) ListPagingSubjectGroups(
 		return nil, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", _type, id)
 	}
 
-	svcSubjectGroups, err := c.service.ListPagingSubjectGroups(subjectPK, beforeExpiredAt, limit, offset)
-	if err != nil {
-		return nil, errorWrapf(
-			err, "service.ListPagingSubjectGroups subjectPK=`%s`, beforeExpiredAt=`%d`, limit=`%d`, offset=`%d` fail",
-			subjectPK, beforeExpiredAt, limit, offset,
-		)
+	var svcSubjectGroups []types.SubjectGroup
+	if at > 0 {
+		svcSubjectGroups, err = c.listSubjectGroupsAt(subjectPK, beforeExpiredAt, at)
+		if err != nil {
+			return nil, errorWrapf(err, "listSubjectGroupsAt subjectPK=`%d`, at=`%d` fail", subjectPK, at)
+		}
+		svcSubjectGroups = pagingSubjectGroups(svcSubjectGroups, limit, offset)
+	} else {
+		svcSubjectGroups, err = c.service.ListPagingSubjectGroups(subjectPK, beforeExpiredAt, limit, offset)
+		if err != nil {
+			return nil, errorWrapf(
+				err, "service.ListPagingSubjectGroups subjectPK=`%s`, beforeExpiredAt=`%d`, limit=`%d`, offset=`%d` fail",
+				subjectPK, beforeExpiredAt, limit, offset,
+			)
+		}
 	}
 
 	groups, err := convertToSubjectGroups(svcSubjectGroups)
@@ -345,6 +365,52 @@ func (c *groupController) ListPagingSubjectGroups(
 	return groups, nil
 }
 
+// GetSubjectGroupCountAt 查询subject在at时刻直接加入的用户组数量
+func (c *groupController) GetSubjectGroupCountAt(_type, id string, beforeExpiredAt, at int64) (int64, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "GetSubjectGroupCountAt")
+	subjectPK, err := cacheimpls.GetLocalSubjectPK(_type, id)
+	if err != nil {
+		return 0, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", _type, id)
+	}
+
+	svcSubjectGroups, err := c.listSubjectGroupsAt(subjectPK, beforeExpiredAt, at)
+	if err != nil {
+		return 0, errorWrapf(err, "listSubjectGroupsAt subjectPK=`%d`, at=`%d` fail", subjectPK, at)
+	}
+	return int64(len(svcSubjectGroups)), nil
+}
+
+func (c *groupController) listSubjectGroupsAt(subjectPK, beforeExpiredAt, at int64) ([]types.SubjectGroup, error) {
+	svcSubjectGroups, err := c.subjectGroupHistoryService.ListSubjectGroupsAt(subjectPK, at)
+	if err != nil {
+		return nil, err
+	}
+
+	if beforeExpiredAt == 0 {
+		return svcSubjectGroups, nil
+	}
+
+	subjectGroups := make([]types.SubjectGroup, 0, len(svcSubjectGroups))
+	for _, sg := range svcSubjectGroups {
+		if sg.ExpiredAt < beforeExpiredAt {
+			subjectGroups = append(subjectGroups, sg)
+		}
+	}
+	return subjectGroups, nil
+}
+
+func pagingSubjectGroups(subjectGroups []types.SubjectGroup, limit, offset int64) []types.SubjectGroup {
+	if offset >= int64(len(subjectGroups)) {
+		return []types.SubjectGroup{}
+	}
+
+	end := offset + limit
+	if end > int64(len(subjectGroups)) {
+		end = int64(len(subjectGroups))
+	}
+	return subjectGroups[offset:end]
+}
+
 // ListPagingSubjectSystemGroups ...
 func (c *groupController) ListPagingSubjectSystemGroups(
 	_type, id string,
@@ -597,7 +663,10 @@ func (h *subjectGroupHelper) getSubjectGroup(
 	return true, nil, nil
 }
 
-func (c *groupController) BulkCreateSubjectTemplateGroup(subjectTemplateGroups []SubjectTemplateGroup) error {
+func (c *groupController) BulkCreateSubjectTemplateGroup(
+	subjectTemplateGroups []SubjectTemplateGroup,
+	actor string,
+) error {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "BulkCreateSubjectTemplateGroup")
 
 	relations, err := c.convertToSubjectTemplateGroups(subjectTemplateGroups)
@@ -605,11 +674,37 @@ func (c *groupController) BulkCreateSubjectTemplateGroup(subjectTemplateGroups [
 		return errorWrapf(err, "convertToSubjectTemplateGroups subjectTemplateGroups=`%+v` fail", subjectTemplateGroups)
 	}
 
+	// 检查通过人员模板加入的成员是否会同时属于互斥的用户组, 或同时拥有互斥的操作
+	groupSubjectPKs := make(map[int64][]int64)
+	for _, relation := range relations {
+		groupSubjectPKs[relation.GroupPK] = append(groupSubjectPKs[relation.GroupPK], relation.SubjectPK)
+	}
+	for groupPK, subjectPKs := range groupSubjectPKs {
+		err = c.sodConstraintService.CheckGroupMembers(groupPK, subjectPKs)
+		if err != nil {
+			return errorWrapf(
+				err, "sodConstraintService.CheckGroupMembers groupPK=`%d`, subjectPKs=`%+v`", groupPK, subjectPKs,
+			)
+		}
+
+		err = c.sodConstraintService.CheckGroupMemberActions(groupPK, subjectPKs)
+		if err != nil {
+			return errorWrapf(
+				err, "sodConstraintService.CheckGroupMemberActions groupPK=`%d`, subjectPKs=`%+v`", groupPK, subjectPKs,
+			)
+		}
+	}
+
 	subjectGroupHelper := newSubjectGroupHelper(c.service)
 
 	for i := range relations {
 		relation := &relations[i]
 
+		// 0. 未到生效时间, 由checker在生效时更新
+		if relation.EffectiveAt > 0 {
+			continue
+		}
+
 		authorized, subjectGroup, err := subjectGroupHelper.getSubjectGroup(relation.SubjectPK, relation.GroupPK)
 		if err != nil {
 			return errorWrapf(
@@ -655,6 +750,15 @@ func (c *groupController) BulkCreateSubjectTemplateGroup(subjectTemplateGroups [
 		return errorWrapf(err, "updateSubjectGroupExpiredAtWithTx relations=`%+v` fail", relations)
 	}
 
+	// 记录成员变更
+	histories := newSubjectGroupHistories(
+		relations, types.SubjectGroupHistoryActionAdd, types.SubjectGroupHistorySourceTemplate, actor,
+	)
+	err = c.subjectGroupHistoryService.BulkCreateWithTx(tx, histories)
+	if err != nil {
+		return errorWrapf(err, "subjectGroupHistoryService.BulkCreateWithTx histories=`%+v` fail", histories)
+	}
+
 	// 提交事务
 	err = tx.Commit()
 	if err != nil {
@@ -667,7 +771,10 @@ func (c *groupController) BulkCreateSubjectTemplateGroup(subjectTemplateGroups [
 	return nil
 }
 
-func (c *groupController) UpdateSubjectTemplateGroupExpiredAt(subjectTemplateGroups []SubjectTemplateGroup) error {
+func (c *groupController) UpdateSubjectTemplateGroupExpiredAt(
+	subjectTemplateGroups []SubjectTemplateGroup,
+	actor string,
+) error {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "BulkRenewSubjectTemplateGroup")
 
 	relations, err := c.convertToSubjectTemplateGroups(subjectTemplateGroups)
@@ -697,12 +804,30 @@ func (c *groupController) UpdateSubjectTemplateGroupExpiredAt(subjectTemplateGro
 			continue
 		}
 
-		// 2. 如果已授权并且过期时间大于当前时间, 不需要更新
+		// 2. 未到生效时间, 只更新subject template group的过期时间
+		effectiveAt, err := c.service.GetSubjectTemplateGroupEffectiveAt(
+			relation.SubjectPK, relation.GroupPK, relation.TemplateID,
+		)
+		if err != nil {
+			return errorWrapf(
+				err,
+				"service.GetSubjectTemplateGroupEffectiveAt subjectPK=`%d`, groupPK=`%d`, templateID=`%d` fail",
+				relation.SubjectPK,
+				relation.GroupPK,
+				relation.TemplateID,
+			)
+		}
+		if effectiveAt > 0 {
+			noAuthorizedRelations = append(noAuthorizedRelations, *relation)
+			continue
+		}
+
+		// 3. 如果已授权并且过期时间大于当前时间, 不需要更新
 		if subjectGroup != nil && subjectGroup.ExpiredAt > relation.ExpiredAt {
 			continue
 		}
 
-		// 3. 其余场景需要更新
+		// 4. 其余场景需要更新
 		relation.NeedUpdate = true
 	}
 
@@ -738,6 +863,15 @@ func (c *groupController) UpdateSubjectTemplateGroupExpiredAt(subjectTemplateGro
 		return errorWrapf(err, "updateSubjectGroupExpiredAtWithTx relations=`%+v` fail", relations)
 	}
 
+	// 记录成员变更
+	histories := newSubjectGroupHistories(
+		relations, types.SubjectGroupHistoryActionRenew, types.SubjectGroupHistorySourceTemplate, actor,
+	)
+	err = c.subjectGroupHistoryService.BulkCreateWithTx(tx, histories)
+	if err != nil {
+		return errorWrapf(err, "subjectGroupHistoryService.BulkCreateWithTx histories=`%+v` fail", histories)
+	}
+
 	// 提交事务
 	err = tx.Commit()
 	if err != nil {
@@ -772,16 +906,20 @@ func (*groupController) convertToSubjectTemplateGroups(
 		}
 
 		relations = append(relations, types.SubjectTemplateGroup{
-			SubjectPK:  subjectPK,
-			TemplateID: stg.TemplateID,
-			GroupPK:    groupPK,
-			ExpiredAt:  stg.ExpiredAt,
+			SubjectPK:   subjectPK,
+			TemplateID:  stg.TemplateID,
+			GroupPK:     groupPK,
+			ExpiredAt:   stg.ExpiredAt,
+			EffectiveAt: normalizeEffectiveAt(stg.EffectiveAt),
 		})
 	}
 	return relations, nil
 }
 
-func (c *groupController) BulkDeleteSubjectTemplateGroup(subjectTemplateGroups []SubjectTemplateGroup) error {
+func (c *groupController) BulkDeleteSubjectTemplateGroup(
+	subjectTemplateGroups []SubjectTemplateGroup,
+	actor string,
+) error {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "BulkDeleteSubjectTemplateGroup")
 
 	relations, err := c.convertToSubjectTemplateGroups(subjectTemplateGroups)
@@ -826,6 +964,15 @@ func (c *groupController) BulkDeleteSubjectTemplateGroup(subjectTemplateGroups [
 		)
 	}
 
+	// 记录成员变更
+	histories := newSubjectGroupHistories(
+		relations, types.SubjectGroupHistoryActionRemove, types.SubjectGroupHistorySourceTemplate, actor,
+	)
+	err = c.subjectGroupHistoryService.BulkCreateWithTx(tx, histories)
+	if err != nil {
+		return errorWrapf(err, "subjectGroupHistoryService.BulkCreateWithTx histories=`%+v` fail", histories)
+	}
+
 	// 提交事务
 	err = tx.Commit()
 	if err != nil {
@@ -862,8 +1009,22 @@ func (c *groupController) deleteSubjectTemplateGroupCache(relations []types.Subj
 func (c *groupController) CreateOrUpdateGroupMembers(
 	_type, id string,
 	members []GroupMember,
+	actor string,
 ) (typeCount map[string]int64, err error) {
-	return c.alterGroupMembers(_type, id, members, true)
+	return c.alterGroupMembers(_type, id, members, true, actor)
+}
+
+// DryRunCreateOrUpdateGroupMembers 预演成员变更, 只执行校验并计算变更内容, 不写DB, 不清理缓存也不生成事件与变更记录
+func (c *groupController) DryRunCreateOrUpdateGroupMembers(
+	_type, id string,
+	members []GroupMember,
+) (result GroupMembersDryRunResult, err error) {
+	plan, err := c.planAlterGroupMembers(_type, id, members, true)
+	if err != nil {
+		return
+	}
+
+	return c.newGroupMembersDryRunResult(plan)
 }
 
 func (c *groupController) convertGroupMembersToSubjectTemplateGroups(
@@ -880,9 +1041,10 @@ func (c *groupController) convertGroupMembersToSubjectTemplateGroups(
 		}
 
 		relations = append(relations, types.SubjectTemplateGroup{
-			GroupPK:   groupPK,
-			SubjectPK: subjectPK,
-			ExpiredAt: m.ExpiredAt,
+			GroupPK:     groupPK,
+			SubjectPK:   subjectPK,
+			ExpiredAt:   m.ExpiredAt,
+			EffectiveAt: normalizeEffectiveAt(m.EffectiveAt),
 		})
 	}
 
@@ -893,8 +1055,73 @@ func (c *groupController) alterGroupMembers(
 	_type, id string,
 	members []GroupMember,
 	createIfNotExists bool,
+	actor string,
 ) (typeCount map[string]int64, err error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "alterGroupMembers")
+	plan, err := c.planAlterGroupMembers(_type, id, members, createIfNotExists)
+	if err != nil {
+		return nil, err
+	}
+
+	// 使用事务
+	tx, err := database.GenerateDefaultDBTx()
+	defer database.RollBackWithLog(tx)
+	if err != nil {
+		return nil, errorWrapf(err, "define tx error")
+	}
+
+	err = c.alterGroupMembersWithTx(tx, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	// 记录成员变更
+	histories := newSubjectGroupHistories(
+		plan.createMembers, types.SubjectGroupHistoryActionAdd, types.SubjectGroupHistorySourceManual, actor,
+	)
+	histories = append(histories, newSubjectGroupHistories(
+		plan.updateMembers, types.SubjectGroupHistoryActionRenew, types.SubjectGroupHistorySourceManual, actor,
+	)...)
+	err = c.subjectGroupHistoryService.BulkCreateWithTx(tx, histories)
+	if err != nil {
+		return nil, errorWrapf(err, "subjectGroupHistoryService.BulkCreateWithTx histories=`%+v` fail", histories)
+	}
+
+	// 提交事务
+	err = tx.Commit()
+	if err != nil {
+		return nil, errorWrapf(err, "tx commit error")
+	}
+
+	// 清理subject system group 缓存
+	c.deleteSubjectTemplateGroupCache(plan.subjectTemplateGroups)
+
+	// 清理通过嵌套用户组继承权限的subject缓存
+	c.alterNestedGroupMembers(plan.groupPK, plan.subjectPKs, plan.memberGroupPKs)
+
+	return plan.typeCount, nil
+}
+
+// groupMembersAlterPlan 校验通过后计算出的成员变更内容
+type groupMembersAlterPlan struct {
+	groupPK               int64
+	members               []GroupMember
+	subjectTemplateGroups []types.SubjectTemplateGroup
+	createMembers         []types.SubjectTemplateGroup
+	updateMembers         []types.SubjectTemplateGroup
+	// user/department成员与用户组成员
+	subjectPKs     []int64
+	memberGroupPKs []int64
+	typeCount      map[string]int64
+}
+
+// planAlterGroupMembers 执行所有的校验, 计算出需要新增与续期的成员
+func (c *groupController) planAlterGroupMembers(
+	_type, id string,
+	members []GroupMember,
+	createIfNotExists bool,
+) (plan *groupMembersAlterPlan, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "planAlterGroupMembers")
 	groupPK, err := cacheimpls.GetLocalSubjectPK(_type, id)
 	if err != nil {
 		return nil, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", _type, id)
@@ -918,9 +1145,10 @@ func (c *groupController) alterGroupMembers(
 	// 需要更新过期时间的member
 	updateMembers := make([]types.SubjectTemplateGroup, 0, len(members))
 
-	typeCount = map[string]int64{
+	typeCount := map[string]int64{
 		types.UserType:       0,
 		types.DepartmentType: 0,
+		types.GroupType:      0,
 	}
 
 	subjectTemplateGroups, err := c.convertGroupMembersToSubjectTemplateGroups(groupPK, members)
@@ -928,6 +1156,50 @@ func (c *groupController) alterGroupMembers(
 		return nil, err
 	}
 
+	// 区分user/department成员与用户组成员, 用户组成员需要检查嵌套关系
+	subjectPKs := make([]int64, 0, len(subjectTemplateGroups))
+	memberGroupPKs := make([]int64, 0, len(subjectTemplateGroups))
+	newMemberGroupPKs := make([]int64, 0, len(subjectTemplateGroups))
+	newMemberPKs := make([]int64, 0, len(subjectTemplateGroups))
+	for i, relation := range subjectTemplateGroups {
+		if _, ok := memberMap[relation.SubjectPK]; !ok && createIfNotExists {
+			newMemberPKs = append(newMemberPKs, relation.SubjectPK)
+		}
+
+		if members[i].Type != types.GroupType {
+			subjectPKs = append(subjectPKs, relation.SubjectPK)
+			continue
+		}
+
+		memberGroupPKs = append(memberGroupPKs, relation.SubjectPK)
+		if _, ok := memberMap[relation.SubjectPK]; !ok && createIfNotExists {
+			newMemberGroupPKs = append(newMemberGroupPKs, relation.SubjectPK)
+		}
+	}
+
+	if len(newMemberGroupPKs) != 0 {
+		err = c.service.CheckNestedGroupMembers(groupPK, newMemberGroupPKs)
+		if err != nil {
+			return nil, errorWrapf(
+				err, "service.CheckNestedGroupMembers groupPK=`%d`, memberGroupPKs=`%+v`", groupPK, newMemberGroupPKs,
+			)
+		}
+	}
+
+	// 检查新成员是否会同时属于互斥的用户组, 或同时拥有互斥的操作
+	err = c.sodConstraintService.CheckGroupMembers(groupPK, newMemberPKs)
+	if err != nil {
+		return nil, errorWrapf(
+			err, "sodConstraintService.CheckGroupMembers groupPK=`%d`, subjectPKs=`%+v`", groupPK, newMemberPKs,
+		)
+	}
+	err = c.sodConstraintService.CheckGroupMemberActions(groupPK, newMemberPKs)
+	if err != nil {
+		return nil, errorWrapf(
+			err, "sodConstraintService.CheckGroupMemberActions groupPK=`%d`, subjectPKs=`%+v`", groupPK, newMemberPKs,
+		)
+	}
+
 	subjectGroupHelper := newSubjectGroupHelper(c.service)
 	for i := range subjectTemplateGroups {
 		relation := &subjectTemplateGroups[i]
@@ -949,9 +1221,13 @@ func (c *groupController) alterGroupMembers(
 
 		// member已存在则不再添加
 		if oldMember, ok := memberMap[relation.SubjectPK]; ok {
+			// 已存在的成员保持原有的生效时间
+			relation.EffectiveAt = oldMember.EffectiveAt
+
 			// 如果过期时间大于已有的时间, 则更新过期时间
 			if relation.ExpiredAt > oldMember.ExpiredAt {
-				relation.NeedUpdate = true
+				// 未生效的成员只更新过期时间, 由checker在生效时更新subject system group
+				relation.NeedUpdate = oldMember.EffectiveAt == 0
 
 				updateMembers = append(updateMembers, *relation)
 			}
@@ -959,7 +1235,8 @@ func (c *groupController) alterGroupMembers(
 		}
 
 		if createIfNotExists {
-			if authorized && (subjectGroup == nil || subjectGroup.ExpiredAt < relation.ExpiredAt) {
+			if relation.EffectiveAt == 0 && authorized &&
+				(subjectGroup == nil || subjectGroup.ExpiredAt < relation.ExpiredAt) {
 				relation.NeedUpdate = true
 			}
 
@@ -968,50 +1245,124 @@ func (c *groupController) alterGroupMembers(
 		}
 	}
 
-	// 按照PK删除Subject所有相关的
-	// 使用事务
-	tx, err := database.GenerateDefaultDBTx()
-	defer database.RollBackWithLog(tx)
-	if err != nil {
-		return nil, errorWrapf(err, "define tx error")
-	}
+	return &groupMembersAlterPlan{
+		groupPK:               groupPK,
+		members:               members,
+		subjectTemplateGroups: subjectTemplateGroups,
+		createMembers:         createMembers,
+		updateMembers:         updateMembers,
+		subjectPKs:            subjectPKs,
+		memberGroupPKs:        memberGroupPKs,
+		typeCount:             typeCount,
+	}, nil
+}
 
-	if len(updateMembers) != 0 {
+func (c *groupController) alterGroupMembersWithTx(tx *sqlx.Tx, plan *groupMembersAlterPlan) (err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "alterGroupMembersWithTx")
+
+	if len(plan.updateMembers) != 0 {
 		// 更新成员过期时间
-		err = c.service.UpdateGroupMembersExpiredAtWithTx(tx, groupPK, updateMembers)
+		err = c.service.UpdateGroupMembersExpiredAtWithTx(tx, plan.groupPK, plan.updateMembers)
 		if err != nil {
-			err = errorWrapf(err, "service.UpdateGroupMembersExpiredAtWithTx members=`%+v`", updateMembers)
-			return
+			return errorWrapf(err, "service.UpdateGroupMembersExpiredAtWithTx members=`%+v`", plan.updateMembers)
 		}
 	}
 
-	// 无成员可添加，直接返回
-	if createIfNotExists && len(createMembers) != 0 {
+	// 无成员可添加则跳过
+	if len(plan.createMembers) != 0 {
 		// 添加成员
-		err = c.service.BulkCreateGroupMembersWithTx(tx, groupPK, createMembers)
+		err = c.service.BulkCreateGroupMembersWithTx(tx, plan.groupPK, plan.createMembers)
 		if err != nil {
-			err = errorWrapf(err, "service.BulkCreateGroupMembersWithTx relations=`%+v`", createMembers)
-			return nil, err
+			return errorWrapf(err, "service.BulkCreateGroupMembersWithTx relations=`%+v`", plan.createMembers)
 		}
 	}
 
 	// 更新subject template group过期时间
-	err = c.updateSubjectGroupExpiredAtWithTx(tx, subjectTemplateGroups, false)
+	err = c.updateSubjectGroupExpiredAtWithTx(tx, plan.subjectTemplateGroups, false)
 	if err != nil {
-		err = errorWrapf(err, "updateSubjectGroupExpiredAtWithTx subjectTemplateGroups=`%+v`", subjectTemplateGroups)
-		return
+		return errorWrapf(
+			err, "updateSubjectGroupExpiredAtWithTx subjectTemplateGroups=`%+v`", plan.subjectTemplateGroups,
+		)
 	}
 
-	// 提交事务
-	err = tx.Commit()
+	return nil
+}
+
+// UpdateDepartmentMembersIncludeSubDepartment 设置用户组的部门成员是否包含子部门
+func (c *groupController) UpdateDepartmentMembersIncludeSubDepartment(
+	_type, id string,
+	departmentIDs []string,
+	includeSubDepartment bool,
+) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "UpdateDepartmentMembersIncludeSubDepartment")
+
+	groupPK, err := cacheimpls.GetLocalSubjectPK(_type, id)
 	if err != nil {
-		return nil, errorWrapf(err, "tx commit error")
+		return errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", _type, id)
 	}
 
-	// 清理subject system group 缓存
-	c.deleteSubjectTemplateGroupCache(subjectTemplateGroups)
+	departmentPKs := make([]int64, 0, len(departmentIDs))
+	for _, departmentID := range departmentIDs {
+		departmentPK, err := cacheimpls.GetLocalSubjectPK(types.DepartmentType, departmentID)
+		if err != nil {
+			return errorWrapf(err, "cacheimpls.GetLocalSubjectPK id=`%s` fail", departmentID)
+		}
+		departmentPKs = append(departmentPKs, departmentPK)
+	}
 
-	return typeCount, nil
+	// 取消包含子部门时, 需要在变更前查询受影响的子部门
+	var subDepartmentPKs []int64
+	if !includeSubDepartment {
+		subDepartmentPKs, err = c.service.ListSubDepartmentMemberPKs(groupPK, departmentPKs)
+		if err != nil {
+			return errorWrapf(
+				err, "service.ListSubDepartmentMemberPKs groupPK=`%d`, departmentPKs=`%+v` fail",
+				groupPK, departmentPKs,
+			)
+		}
+	}
+
+	err = c.service.UpdateMembersIncludeSubDepartment(groupPK, departmentPKs, includeSubDepartment)
+	if err != nil {
+		return errorWrapf(
+			err, "service.UpdateMembersIncludeSubDepartment groupPK=`%d`, departmentPKs=`%+v`, include=`%t` fail",
+			groupPK, departmentPKs, includeSubDepartment,
+		)
+	}
+
+	if includeSubDepartment {
+		subDepartmentPKs, err = c.service.ListSubDepartmentMemberPKs(groupPK, departmentPKs)
+		if err != nil {
+			return errorWrapf(
+				err, "service.ListSubDepartmentMemberPKs groupPK=`%d`, departmentPKs=`%+v` fail",
+				groupPK, departmentPKs,
+			)
+		}
+	}
+
+	c.alterSubDepartmentMembers(groupPK, subDepartmentPKs)
+
+	return nil
+}
+
+// alterSubDepartmentMembers 子部门通过上级部门继承了groupPK及其上级用户组, 清理子部门的缓存并创建group_alter_event
+func (c *groupController) alterSubDepartmentMembers(groupPK int64, subDepartmentPKs []int64) {
+	if len(subDepartmentPKs) == 0 {
+		return
+	}
+
+	cacheimpls.BatchDeleteSubjectAllSystemGroupCache(subDepartmentPKs)
+
+	c.createGroupAlterEvent(groupPK, subDepartmentPKs)
+
+	ancestorGroupPKs, err := c.service.ListAncestorGroupPKs(groupPK)
+	if err != nil {
+		reportNestedGroupError("service.ListAncestorGroupPKs", groupPK, err)
+		return
+	}
+	for _, ancestorGroupPK := range ancestorGroupPKs {
+		c.createGroupAlterEvent(ancestorGroupPK, subDepartmentPKs)
+	}
 }
 
 func (c *groupController) updateSubjectGroupExpiredAtWithTx(
@@ -1033,8 +1384,12 @@ func (c *groupController) updateSubjectGroupExpiredAtWithTx(
 }
 
 // UpdateGroupMembersExpiredAt ...
-func (c *groupController) UpdateGroupMembersExpiredAt(_type, id string, members []GroupMember) (err error) {
-	_, err = c.alterGroupMembers(_type, id, members, false)
+func (c *groupController) UpdateGroupMembersExpiredAt(
+	_type, id string,
+	members []GroupMember,
+	actor string,
+) (err error) {
+	_, err = c.alterGroupMembers(_type, id, members, false, actor)
 	return
 }
 
@@ -1042,21 +1397,26 @@ func (c *groupController) UpdateGroupMembersExpiredAt(_type, id string, members
 func (c *groupController) DeleteGroupMembers(
 	_type, id string,
 	members []Subject,
+	actor string,
 ) (typeCount map[string]int64, err error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "DeleteGroupMembers")
 
 	userPKs := make([]int64, 0, len(members))
 	departmentPKs := make([]int64, 0, len(members))
+	memberGroupPKs := make([]int64, 0, len(members))
 	for _, m := range members {
 		pk, err := cacheimpls.GetLocalSubjectPK(m.Type, m.ID)
 		if err != nil {
 			return nil, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", m.Type, m.ID)
 		}
 
-		if m.Type == types.UserType {
+		switch m.Type {
+		case types.UserType:
 			userPKs = append(userPKs, pk)
-		} else if m.Type == types.DepartmentType {
+		case types.DepartmentType:
 			departmentPKs = append(departmentPKs, pk)
+		case types.GroupType:
+			memberGroupPKs = append(memberGroupPKs, pk)
 		}
 	}
 
@@ -1065,28 +1425,191 @@ func (c *groupController) DeleteGroupMembers(
 		return nil, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", _type, id)
 	}
 
-	typeCount, err = c.service.BulkDeleteGroupMembers(groupPK, userPKs, departmentPKs)
+	// 删除前查询通过部门成员(包含子部门)继承的子部门
+	subDepartmentPKs, err := c.service.ListSubDepartmentMemberPKs(groupPK, departmentPKs)
 	if err != nil {
 		return nil, errorWrapf(
-			err, "service.BulkDeleteGroupMembers groupPK=`%d`, userPKs=`%+v`, departmentPKs=`%+v` failed",
-			groupPK, userPKs, departmentPKs,
+			err, "service.ListSubDepartmentMemberPKs groupPK=`%d`, departmentPKs=`%+v` fail",
+			groupPK, departmentPKs,
+		)
+	}
+
+	// 使用事务
+	tx, err := database.GenerateDefaultDBTx()
+	defer database.RollBackWithLog(tx)
+	if err != nil {
+		return nil, errorWrapf(err, "define tx error")
+	}
+
+	typeCount, err = c.service.BulkDeleteGroupMembersWithTx(tx, groupPK, userPKs, departmentPKs, memberGroupPKs)
+	if err != nil {
+		return nil, errorWrapf(
+			err, "service.BulkDeleteGroupMembersWithTx groupPK=`%d`, userPKs=`%+v`, departmentPKs=`%+v`, "+
+				"memberGroupPKs=`%+v` failed",
+			groupPK, userPKs, departmentPKs, memberGroupPKs,
 		)
 	}
 
-	// 清理缓存
 	subjectPKs := make([]int64, 0, len(members))
 	subjectPKs = append(subjectPKs, userPKs...)
 	subjectPKs = append(subjectPKs, departmentPKs...)
 
+	// 记录成员变更
+	relations := make([]types.SubjectTemplateGroup, 0, len(members))
+	for _, pk := range append(subjectPKs, memberGroupPKs...) {
+		relations = append(relations, types.SubjectTemplateGroup{SubjectPK: pk, GroupPK: groupPK})
+	}
+	histories := newSubjectGroupHistories(
+		relations, types.SubjectGroupHistoryActionRemove, types.SubjectGroupHistorySourceManual, actor,
+	)
+	err = c.subjectGroupHistoryService.BulkCreateWithTx(tx, histories)
+	if err != nil {
+		return nil, errorWrapf(err, "subjectGroupHistoryService.BulkCreateWithTx histories=`%+v` fail", histories)
+	}
+
+	// 提交事务
+	err = tx.Commit()
+	if err != nil {
+		return nil, errorWrapf(err, "tx commit error")
+	}
+
+	// 清理缓存
+
 	// 创建group_alter_event
 	c.createGroupAlterEvent(groupPK, subjectPKs)
 
 	// group auth system
-	cacheimpls.BatchDeleteSubjectAuthSystemGroupCache(subjectPKs, groupPK)
+	cacheimpls.BatchDeleteSubjectAuthSystemGroupCache(append(subjectPKs, memberGroupPKs...), groupPK)
+
+	// 清理通过嵌套用户组继承权限的subject缓存
+	c.alterNestedGroupMembers(groupPK, subjectPKs, memberGroupPKs)
+
+	// 清理通过上级部门(包含子部门)继承权限的子部门缓存
+	c.alterSubDepartmentMembers(groupPK, subDepartmentPKs)
 
 	return typeCount, nil
 }
 
+// DryRunDeleteGroupMembers 预演删除成员, 只查询将被删除的成员并计算受影响的subject, 不写DB
+func (c *groupController) DryRunDeleteGroupMembers(
+	_type, id string,
+	members []Subject,
+) (result GroupMembersDryRunResult, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "DryRunDeleteGroupMembers")
+
+	groupPK, err := cacheimpls.GetLocalSubjectPK(_type, id)
+	if err != nil {
+		return result, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", _type, id)
+	}
+
+	relations, err := c.service.ListGroupMember(groupPK)
+	if err != nil {
+		return result, errorWrapf(err, "service.ListGroupMember groupPK=`%d` fail", groupPK)
+	}
+	memberMap := make(map[int64]types.GroupMember, len(relations))
+	for _, m := range relations {
+		memberMap[m.SubjectPK] = m
+	}
+
+	// 只有已存在的成员会被删除
+	deletedMembers := make([]GroupMember, 0, len(members))
+	subjectPKs := make([]int64, 0, len(members))
+	departmentPKs := make([]int64, 0, len(members))
+	memberGroupPKs := make([]int64, 0, len(members))
+	for _, m := range members {
+		pk, err := cacheimpls.GetLocalSubjectPK(m.Type, m.ID)
+		if err != nil {
+			return result, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", m.Type, m.ID)
+		}
+
+		relation, ok := memberMap[pk]
+		if !ok {
+			continue
+		}
+		delete(memberMap, pk)
+
+		deletedMembers = append(deletedMembers, GroupMember{
+			Type:        m.Type,
+			ID:          m.ID,
+			ExpiredAt:   relation.ExpiredAt,
+			EffectiveAt: relation.EffectiveAt,
+		})
+
+		switch m.Type {
+		case types.GroupType:
+			memberGroupPKs = append(memberGroupPKs, pk)
+		case types.DepartmentType:
+			departmentPKs = append(departmentPKs, pk)
+			subjectPKs = append(subjectPKs, pk)
+		default:
+			subjectPKs = append(subjectPKs, pk)
+		}
+	}
+
+	subDepartmentPKs, err := c.service.ListSubDepartmentMemberPKs(groupPK, departmentPKs)
+	if err != nil {
+		return result, errorWrapf(
+			err, "service.ListSubDepartmentMemberPKs groupPK=`%d`, departmentPKs=`%+v` fail",
+			groupPK, departmentPKs,
+		)
+	}
+
+	return c.newGroupMembersDeleteDryRunResult(groupPK, deletedMembers, subjectPKs, memberGroupPKs, subDepartmentPKs)
+}
+
+// alterNestedGroupMembers 成员变更会影响通过嵌套用户组继承的权限, 清理受影响subject的缓存并创建group_alter_event
+// subjectPKs为变更的user/department成员, memberGroupPKs为变更的用户组成员
+func (c *groupController) alterNestedGroupMembers(groupPK int64, subjectPKs, memberGroupPKs []int64) {
+	ancestorGroupPKs, err := c.service.ListAncestorGroupPKs(groupPK)
+	if err != nil {
+		reportNestedGroupError("service.ListAncestorGroupPKs", groupPK, err)
+		return
+	}
+
+	// 1. user/department成员通过groupPK继承了上级用户组
+	if len(ancestorGroupPKs) != 0 && len(subjectPKs) != 0 {
+		cacheimpls.BatchDeleteSubjectAllSystemGroupCache(subjectPKs)
+
+		for _, ancestorGroupPK := range ancestorGroupPKs {
+			c.createGroupAlterEvent(ancestorGroupPK, subjectPKs)
+		}
+	}
+
+	// 2. 用户组成员下的所有user/department继承了groupPK及其上级用户组
+	if len(memberGroupPKs) == 0 {
+		return
+	}
+
+	nestedSubjectPKs, err := c.service.ListNestedGroupMemberSubjectPKs(memberGroupPKs)
+	if err != nil {
+		reportNestedGroupError("service.ListNestedGroupMemberSubjectPKs", groupPK, err)
+		return
+	}
+	if len(nestedSubjectPKs) == 0 {
+		return
+	}
+
+	cacheimpls.BatchDeleteSubjectAllSystemGroupCache(nestedSubjectPKs)
+
+	c.createGroupAlterEvent(groupPK, nestedSubjectPKs)
+	for _, ancestorGroupPK := range ancestorGroupPKs {
+		c.createGroupAlterEvent(ancestorGroupPK, nestedSubjectPKs)
+	}
+}
+
+func reportNestedGroupError(function string, groupPK int64, err error) {
+	log.WithError(err).Errorf("alterNestedGroupMembers %s groupPK=%d fail", function, groupPK)
+
+	// report to sentry
+	util.ReportToSentry("alterNestedGroupMembers "+function+" fail",
+		map[string]interface{}{
+			"layer":   GroupCTL,
+			"groupPK": groupPK,
+			"error":   err.Error(),
+		},
+	)
+}
+
 func (c *groupController) createGroupAlterEvent(groupPK int64, subjectPKs []int64) {
 	err := c.groupAlterEventService.CreateByGroupSubject(groupPK, subjectPKs)
 	if err != nil {
@@ -1105,6 +1628,26 @@ func (c *groupController) createGroupAlterEvent(groupPK int64, subjectPKs []int6
 	}
 }
 
+func newSubjectGroupHistories(
+	relations []types.SubjectTemplateGroup,
+	action, source, actor string,
+) []types.SubjectGroupHistory {
+	histories := make([]types.SubjectGroupHistory, 0, len(relations))
+	for _, r := range relations {
+		histories = append(histories, types.SubjectGroupHistory{
+			SubjectPK:   r.SubjectPK,
+			GroupPK:     r.GroupPK,
+			TemplateID:  r.TemplateID,
+			Action:      action,
+			Source:      source,
+			ExpiredAt:   r.ExpiredAt,
+			EffectiveAt: r.EffectiveAt,
+			Actor:       actor,
+		})
+	}
+	return histories
+}
+
 // ListRbacGroupByResource ...
 func (c *groupController) ListRbacGroupByResource(systemID string, resource abacTypes.Resource) ([]Subject, error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupCTL, "ListRbacGroupByResource")
@@ -1125,7 +1668,7 @@ func (c *groupController) ListRbacGroupByResource(systemID string, resource abac
 	groupPKset := set.NewInt64Set()
 	// 查询有权限的用户组
 	for _, resourceNode := range resourceNodes {
-		actionGroupPKs, err := c.groupResourcePolicyService.GetAuthorizedActionGroupMap(
+		actionGroups, err := c.groupResourcePolicyService.GetAuthorizedActionGroupMap(
 			systemID, actionResourceTypePK, resourceNode.TypePK, resourceNode.ID,
 		)
 		if err != nil {
@@ -1138,8 +1681,10 @@ func (c *groupController) ListRbacGroupByResource(systemID string, resource abac
 			return nil, err
 		}
 
-		for _, groupPKs := range actionGroupPKs {
-			groupPKset.Append(groupPKs...)
+		for _, groups := range actionGroups {
+			for _, group := range groups {
+				groupPKset.Add(group.GroupPK)
+			}
 		}
 	}
 
@@ -1177,8 +1722,9 @@ func (c *groupController) ListRbacGroupByActionResource(
 	}
 
 	// 查询操作关联的资源类型id
+	// NOTE: 操作关联多个资源类型时, 使用第一个资源类型, 返回授权资源实例组合中包含该资源实例的用户组
 	actionResourceTypePK, err := cacheimpls.GetLocalResourceTypePK(
-		actionResourceTypes[0].System, actionResourceTypes[0].Type, // 配置rbac的操作一定只关联了1个资源类型
+		actionResourceTypes[0].System, actionResourceTypes[0].Type,
 	)
 	if err != nil {
 		err = errorWrapf(
@@ -1201,7 +1747,7 @@ func (c *groupController) ListRbacGroupByActionResource(
 	groupPKset := set.NewInt64Set()
 	// 查询有权限的用户组
 	for _, resourceNode := range resourceNodes {
-		groupPKs, err := cacheimpls.GetResourceActionAuthorizedGroupPKs(
+		groups, err := cacheimpls.GetResourceActionAuthorizedGroups(
 			systemID,
 			actionPK,
 			actionResourceTypePK,
@@ -1211,7 +1757,7 @@ func (c *groupController) ListRbacGroupByActionResource(
 		if err != nil {
 			err = errorWrapf(
 				err,
-				"cacheimpls.GetResourceActionAuthorizedGroupPKs fail, system=`%s` action_id=`%s` resource=`%+v`",
+				"cacheimpls.GetResourceActionAuthorizedGroups fail, system=`%s` action_id=`%s` resource=`%+v`",
 				systemID,
 				actionID,
 				resourceNode,
@@ -1219,7 +1765,9 @@ func (c *groupController) ListRbacGroupByActionResource(
 			return nil, err
 		}
 
-		groupPKset.Append(groupPKs...)
+		for _, group := range groups {
+			groupPKset.Add(group.GroupPK)
+		}
 	}
 
 	// 查询用户组信息
@@ -1309,12 +1857,13 @@ func convertToGroupMembers(svcGroupMembers []types.GroupMember) ([]GroupMember,
 		}
 
 		members = append(members, GroupMember{
-			PK:        m.PK,
-			Type:      subject.Type,
-			ID:        subject.ID,
-			Name:      subject.Name,
-			ExpiredAt: m.ExpiredAt,
-			CreatedAt: m.CreatedAt,
+			PK:          m.PK,
+			Type:        subject.Type,
+			ID:          subject.ID,
+			Name:        subject.Name,
+			ExpiredAt:   m.ExpiredAt,
+			EffectiveAt: m.EffectiveAt,
+			CreatedAt:   m.CreatedAt,
 		})
 	}
 
@@ -1367,3 +1916,11 @@ func convertToGroupSubjects(svcGroupSubjects []types.GroupSubject) ([]GroupSubje
 
 	return groupSubjects, nil
 }
+
+// normalizeEffectiveAt 生效时间不晚于当前时间的视为立即生效
+func normalizeEffectiveAt(effectiveAt int64) int64 {
+	if effectiveAt <= time.Now().Unix() {
+		return 0
+	}
+	return effectiveAt
+}
@@ -14,6 +14,7 @@ import (
 	"database/sql"
 	"errors"
 	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/agiledragon/gomonkey/v2"
@@ -35,8 +36,15 @@ var _ = Describe("GroupController", func() {
 	Describe("createOrUpdateGroupMembers", func() {
 		var ctl *gomock.Controller
 		var patches *gomonkey.Patches
+		var mockSubjectGroupHistoryService *mock.MockSubjectGroupHistoryService
+		var mockSoDConstraintService *mock.MockSoDConstraintService
 		BeforeEach(func() {
 			ctl = gomock.NewController(GinkgoT())
+			mockSubjectGroupHistoryService = mock.NewMockSubjectGroupHistoryService(ctl)
+			mockSubjectGroupHistoryService.EXPECT().BulkCreateWithTx(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockSoDConstraintService = mock.NewMockSoDConstraintService(ctl)
+			mockSoDConstraintService.EXPECT().CheckGroupMembers(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockSoDConstraintService.EXPECT().CheckGroupMemberActions(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
 				switch id {
@@ -61,7 +69,9 @@ var _ = Describe("GroupController", func() {
 			).AnyTimes()
 
 			manager := &groupController{
-				service: mockGroupService,
+				service:                    mockGroupService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			_, err := manager.alterGroupMembers("group", "1", []GroupMember{
@@ -70,7 +80,7 @@ var _ = Describe("GroupController", func() {
 					ID:        "2",
 					ExpiredAt: int64(3),
 				},
-			}, true)
+			}, true, "admin")
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "ListGroupMember")
 		})
@@ -107,7 +117,9 @@ var _ = Describe("GroupController", func() {
 			})
 
 			manager := &groupController{
-				service: mockGroupService,
+				service:                    mockGroupService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			_, err := manager.alterGroupMembers("group", "1", []GroupMember{
@@ -116,7 +128,7 @@ var _ = Describe("GroupController", func() {
 					ID:        "2",
 					ExpiredAt: int64(3),
 				},
-			}, true)
+			}, true, "admin")
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "UpdateGroupMembersExpiredAtWithTx")
 		})
@@ -154,7 +166,9 @@ var _ = Describe("GroupController", func() {
 			})
 
 			manager := &groupController{
-				service: mockGroupService,
+				service:                    mockGroupService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			_, err := manager.alterGroupMembers("group", "1", []GroupMember{
@@ -163,7 +177,7 @@ var _ = Describe("GroupController", func() {
 					ID:        "2",
 					ExpiredAt: int64(3),
 				},
-			}, true)
+			}, true, "admin")
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "BulkCreateGroupMembersWithTx")
 		})
@@ -182,6 +196,7 @@ var _ = Describe("GroupController", func() {
 			).
 				AnyTimes()
 			mockGroupService.EXPECT().ListGroupAuthSystemIDs(int64(1)).Return([]string{}, nil).AnyTimes()
+			mockGroupService.EXPECT().ListAncestorGroupPKs(int64(1)).Return([]int64{}, nil).AnyTimes()
 			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
 			mockGroupService.EXPECT().GetGroupOneAuthSystem(int64(1)).Return("", nil).AnyTimes()
 
@@ -199,8 +214,10 @@ var _ = Describe("GroupController", func() {
 			})
 
 			manager := &groupController{
-				service:                mockGroupService,
-				groupAlterEventService: mockGroupAlterEventService,
+				service:                    mockGroupService,
+				groupAlterEventService:     mockGroupAlterEventService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			_, err := manager.alterGroupMembers("group", "1", []GroupMember{
@@ -209,7 +226,7 @@ var _ = Describe("GroupController", func() {
 					ID:        "2",
 					ExpiredAt: int64(3),
 				},
-			}, false)
+			}, false, "admin")
 			assert.NoError(GinkgoT(), err)
 		})
 
@@ -238,6 +255,7 @@ var _ = Describe("GroupController", func() {
 				).
 				AnyTimes()
 			mockGroupService.EXPECT().ListGroupAuthSystemIDs(int64(1)).Return([]string{}, nil).AnyTimes()
+			mockGroupService.EXPECT().ListAncestorGroupPKs(int64(1)).Return([]int64{}, nil).AnyTimes()
 			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
 			mockGroupService.EXPECT().GetGroupOneAuthSystem(int64(1)).Return("", nil).AnyTimes()
 
@@ -255,8 +273,10 @@ var _ = Describe("GroupController", func() {
 			})
 
 			manager := &groupController{
-				service:                mockGroupService,
-				groupAlterEventService: mockGroupAlterEventService,
+				service:                    mockGroupService,
+				groupAlterEventService:     mockGroupAlterEventService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			typeCount, err := manager.alterGroupMembers("group", "1", []GroupMember{
@@ -265,17 +285,211 @@ var _ = Describe("GroupController", func() {
 					ID:        "2",
 					ExpiredAt: int64(3),
 				},
-			}, true)
+			}, true, "admin")
 			assert.NoError(GinkgoT(), err)
-			assert.Equal(GinkgoT(), map[string]int64{"user": 1, "department": 0}, typeCount)
+			assert.Equal(GinkgoT(), map[string]int64{"user": 1, "department": 0, "group": 0}, typeCount)
+		})
+
+		It("pending ok", func() {
+			effectiveAt := time.Now().Unix() + 100
+
+			helper := &subjectGroupHelper{}
+			patches.ApplyPrivateMethod(reflect.TypeOf(helper), "getSubjectGroup", func(
+				_ *subjectGroupHelper, subjectPK, groupPK int64,
+			) (authorized bool, subjectGroup *types.ThinSubjectGroup, err error) {
+				return true, nil, nil
+			})
+			patches.ApplyFunc(newSubjectGroupHelper, func(service service.GroupService) *subjectGroupHelper {
+				return helper
+			})
+
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListGroupMember(int64(1)).Return(
+				[]types.GroupMember{}, nil,
+			).AnyTimes()
+			mockGroupService.EXPECT().
+				BulkCreateGroupMembersWithTx(gomock.Any(), int64(1), []types.SubjectTemplateGroup{{
+					SubjectPK:   2,
+					GroupPK:     1,
+					ExpiredAt:   effectiveAt + 100,
+					EffectiveAt: effectiveAt,
+				}}).
+				Return(nil)
+			mockGroupService.EXPECT().ListAncestorGroupPKs(int64(1)).Return([]int64{}, nil).AnyTimes()
+			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
+
+			db, mock := database.NewMockSqlxDB()
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+			tx, _ := db.Beginx()
+
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return tx, nil
+			})
+
+			manager := &groupController{
+				service:                    mockGroupService,
+				groupAlterEventService:     mockGroupAlterEventService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
+			}
+
+			typeCount, err := manager.alterGroupMembers("group", "1", []GroupMember{
+				{
+					Type:        "user",
+					ID:          "2",
+					ExpiredAt:   effectiveAt + 100,
+					EffectiveAt: effectiveAt,
+				},
+			}, true, "admin")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[string]int64{"user": 1, "department": 0, "group": 0}, typeCount)
+		})
+
+		It("nested group cycle", func() {
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListGroupMember(int64(1)).Return(
+				[]types.GroupMember{}, nil,
+			).AnyTimes()
+			mockGroupService.EXPECT().CheckNestedGroupMembers(int64(1), []int64{2}).Return(
+				service.ErrGroupNestingCycle,
+			)
+
+			manager := &groupController{
+				service:                    mockGroupService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
+			}
+
+			_, err := manager.alterGroupMembers("group", "1", []GroupMember{
+				{
+					Type:      "group",
+					ID:        "2",
+					ExpiredAt: int64(3),
+				},
+			}, true, "admin")
+			assert.ErrorIs(GinkgoT(), err, service.ErrGroupNestingCycle)
+		})
+
+		It("sod constraint violation", func() {
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListGroupMember(int64(1)).Return(
+				[]types.GroupMember{}, nil,
+			).AnyTimes()
+
+			mockSoDService := mock.NewMockSoDConstraintService(ctl)
+			mockSoDService.EXPECT().CheckGroupMembers(int64(1), []int64{2}).Return(
+				service.ErrSoDConstraintViolation,
+			)
+
+			manager := &groupController{
+				service:                    mockGroupService,
+				sodConstraintService:       mockSoDService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
+			}
+
+			_, err := manager.alterGroupMembers("group", "1", []GroupMember{
+				{
+					Type:      "user",
+					ID:        "2",
+					ExpiredAt: int64(3),
+				},
+			}, true, "admin")
+			assert.ErrorIs(GinkgoT(), err, service.ErrSoDConstraintViolation)
+		})
+
+		It("sod action constraint violation", func() {
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListGroupMember(int64(1)).Return(
+				[]types.GroupMember{}, nil,
+			).AnyTimes()
+
+			mockSoDService := mock.NewMockSoDConstraintService(ctl)
+			mockSoDService.EXPECT().CheckGroupMembers(int64(1), []int64{2}).Return(nil)
+			mockSoDService.EXPECT().CheckGroupMemberActions(int64(1), []int64{2}).Return(
+				service.ErrSoDConstraintViolation,
+			)
+
+			manager := &groupController{
+				service:                    mockGroupService,
+				sodConstraintService:       mockSoDService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
+			}
+
+			_, err := manager.alterGroupMembers("group", "1", []GroupMember{
+				{
+					Type:      "user",
+					ID:        "2",
+					ExpiredAt: int64(3),
+				},
+			}, true, "admin")
+			assert.ErrorIs(GinkgoT(), err, service.ErrSoDConstraintViolation)
+			assert.Contains(GinkgoT(), err.Error(), "CheckGroupMemberActions")
+		})
+
+		It("nested group ok", func() {
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListGroupMember(int64(1)).Return(
+				[]types.GroupMember{}, nil,
+			).AnyTimes()
+			mockGroupService.EXPECT().CheckNestedGroupMembers(int64(1), []int64{2}).Return(nil)
+			mockGroupService.EXPECT().
+				BulkCreateGroupMembersWithTx(gomock.Any(), int64(1), []types.SubjectTemplateGroup{{
+					SubjectPK: 2,
+					GroupPK:   1,
+					ExpiredAt: int64(3),
+				}}).
+				Return(nil)
+			mockGroupService.EXPECT().GetGroupOneAuthSystem(int64(1)).Return("", nil).AnyTimes()
+			mockGroupService.EXPECT().ListAncestorGroupPKs(int64(1)).Return([]int64{5}, nil)
+			mockGroupService.EXPECT().ListNestedGroupMemberSubjectPKs([]int64{2}).Return([]int64{7}, nil)
+
+			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
+			mockGroupAlterEventService.EXPECT().CreateByGroupSubject(int64(1), []int64{7}).Return(nil)
+			mockGroupAlterEventService.EXPECT().CreateByGroupSubject(int64(5), []int64{7}).Return(nil)
+
+			var deletedSubjectPKs []int64
+			patches.ApplyFunc(cacheimpls.BatchDeleteSubjectAllSystemGroupCache, func(subjectPKs []int64) {
+				deletedSubjectPKs = subjectPKs
+			})
+
+			db, mock := database.NewMockSqlxDB()
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+			tx, _ := db.Beginx()
+
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return tx, nil
+			})
+
+			manager := &groupController{
+				service:                    mockGroupService,
+				groupAlterEventService:     mockGroupAlterEventService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
+			}
+
+			typeCount, err := manager.alterGroupMembers("group", "1", []GroupMember{
+				{
+					Type:      "group",
+					ID:        "2",
+					ExpiredAt: int64(3),
+				},
+			}, true, "admin")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[string]int64{"user": 0, "department": 0, "group": 1}, typeCount)
+			assert.Equal(GinkgoT(), []int64{7}, deletedSubjectPKs)
 		})
 	})
 
 	Describe("DeleteGroupMembers", func() {
 		var ctl *gomock.Controller
 		var patches *gomonkey.Patches
+		var mockSubjectGroupHistoryService *mock.MockSubjectGroupHistoryService
 		BeforeEach(func() {
 			ctl = gomock.NewController(GinkgoT())
+			mockSubjectGroupHistoryService = mock.NewMockSubjectGroupHistoryService(ctl)
+			mockSubjectGroupHistoryService.EXPECT().BulkCreateWithTx(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
 				switch id {
@@ -289,20 +503,33 @@ var _ = Describe("GroupController", func() {
 
 				return 0, nil
 			})
+
+			db, mock := database.NewMockSqlxDB()
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+			tx, _ := db.Beginx()
+
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return tx, nil
+			})
 		})
 		AfterEach(func() {
 			ctl.Finish()
 			patches.Reset()
 		})
 
-		It("service.BulkDeleteGroupMembers fail", func() {
+		It("service.BulkDeleteGroupMembersWithTx fail", func() {
 			mockGroupService := mock.NewMockGroupService(ctl)
-			mockGroupService.EXPECT().BulkDeleteGroupMembers(int64(1), []int64{2}, []int64{3}).Return(
-				nil, errors.New("error"),
-			).AnyTimes()
+			mockGroupService.EXPECT().ListSubDepartmentMemberPKs(int64(1), []int64{3}).Return(nil, nil).AnyTimes()
+			mockGroupService.EXPECT().
+				BulkDeleteGroupMembersWithTx(gomock.Any(), int64(1), []int64{2}, []int64{3}, []int64{}).
+				Return(
+					nil, errors.New("error"),
+				).AnyTimes()
 
 			manager := &groupController{
-				service: mockGroupService,
+				service:                    mockGroupService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			_, err := manager.DeleteGroupMembers("group", "1", []Subject{
@@ -314,17 +541,51 @@ var _ = Describe("GroupController", func() {
 					Type: "department",
 					ID:   "3",
 				},
-			})
+			}, "admin")
 			assert.Error(GinkgoT(), err)
-			assert.Contains(GinkgoT(), err.Error(), "BulkDeleteGroupMembers")
+			assert.Contains(GinkgoT(), err.Error(), "BulkDeleteGroupMembersWithTx")
+		})
+
+		It("subjectGroupHistoryService.BulkCreateWithTx fail", func() {
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListSubDepartmentMemberPKs(int64(1), []int64{3}).Return(nil, nil).AnyTimes()
+			mockGroupService.EXPECT().
+				BulkDeleteGroupMembersWithTx(gomock.Any(), int64(1), []int64{2}, []int64{3}, []int64{}).
+				Return(
+					map[string]int64{"user": 1, "department": 0}, nil,
+				).AnyTimes()
+			mockHistoryService := mock.NewMockSubjectGroupHistoryService(ctl)
+			mockHistoryService.EXPECT().BulkCreateWithTx(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+			manager := &groupController{
+				service:                    mockGroupService,
+				subjectGroupHistoryService: mockHistoryService,
+			}
+
+			_, err := manager.DeleteGroupMembers("group", "1", []Subject{
+				{
+					Type: "user",
+					ID:   "2",
+				},
+				{
+					Type: "department",
+					ID:   "3",
+				},
+			}, "admin")
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "BulkCreateWithTx")
 		})
 
 		It("ok", func() {
 			mockGroupService := mock.NewMockGroupService(ctl)
-			mockGroupService.EXPECT().BulkDeleteGroupMembers(int64(1), []int64{2}, []int64{3}).Return(
-				map[string]int64{"user": 1, "department": 0}, nil,
-			).AnyTimes()
+			mockGroupService.EXPECT().ListSubDepartmentMemberPKs(int64(1), []int64{3}).Return(nil, nil).AnyTimes()
+			mockGroupService.EXPECT().
+				BulkDeleteGroupMembersWithTx(gomock.Any(), int64(1), []int64{2}, []int64{3}, []int64{}).
+				Return(
+					map[string]int64{"user": 1, "department": 0}, nil,
+				).AnyTimes()
 			mockGroupService.EXPECT().ListGroupAuthSystemIDs(int64(1)).Return([]string{}, nil).AnyTimes()
+			mockGroupService.EXPECT().ListAncestorGroupPKs(int64(1)).Return([]int64{}, nil).AnyTimes()
 			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
 			mockGroupAlterEventService.EXPECT().
 				CreateByGroupSubject(gomock.Any(), gomock.Any()).
@@ -335,8 +596,9 @@ var _ = Describe("GroupController", func() {
 			})
 
 			manager := &groupController{
-				service:                mockGroupService,
-				groupAlterEventService: mockGroupAlterEventService,
+				service:                    mockGroupService,
+				groupAlterEventService:     mockGroupAlterEventService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			typeCount, err := manager.DeleteGroupMembers("group", "1", []Subject{
@@ -348,12 +610,149 @@ var _ = Describe("GroupController", func() {
 					Type: "department",
 					ID:   "3",
 				},
-			})
+			}, "admin")
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), map[string]int64{"user": 1, "department": 0}, typeCount)
 		})
 	})
 
+	Describe("UpdateDepartmentMembersIncludeSubDepartment", func() {
+		var ctl *gomock.Controller
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+
+			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+				switch id {
+				case "1":
+					return int64(1), nil
+				case "3":
+					return int64(3), nil
+				}
+
+				return 0, errors.New("not found")
+			})
+			patches.ApplyFunc(cacheimpls.BatchDeleteSubjectAllSystemGroupCache, func(pks []int64) {})
+		})
+		AfterEach(func() {
+			ctl.Finish()
+			patches.Reset()
+		})
+
+		It("cacheimpls.GetLocalSubjectPK fail", func() {
+			manager := &groupController{}
+
+			err := manager.UpdateDepartmentMembersIncludeSubDepartment("group", "1", []string{"4"}, true)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "GetLocalSubjectPK")
+		})
+
+		It("service.UpdateMembersIncludeSubDepartment fail", func() {
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().UpdateMembersIncludeSubDepartment(int64(1), []int64{3}, true).Return(
+				errors.New("error"),
+			)
+
+			manager := &groupController{
+				service: mockGroupService,
+			}
+
+			err := manager.UpdateDepartmentMembersIncludeSubDepartment("group", "1", []string{"3"}, true)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "UpdateMembersIncludeSubDepartment")
+		})
+
+		It("include ok", func() {
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().UpdateMembersIncludeSubDepartment(int64(1), []int64{3}, true).Return(nil)
+			mockGroupService.EXPECT().ListSubDepartmentMemberPKs(int64(1), []int64{3}).Return([]int64{5}, nil)
+			mockGroupService.EXPECT().ListAncestorGroupPKs(int64(1)).Return([]int64{2}, nil)
+
+			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
+			mockGroupAlterEventService.EXPECT().CreateByGroupSubject(int64(1), []int64{5}).Return(nil)
+			mockGroupAlterEventService.EXPECT().CreateByGroupSubject(int64(2), []int64{5}).Return(nil)
+
+			manager := &groupController{
+				service:                mockGroupService,
+				groupAlterEventService: mockGroupAlterEventService,
+			}
+
+			err := manager.UpdateDepartmentMembersIncludeSubDepartment("group", "1", []string{"3"}, true)
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("exclude ok", func() {
+			mockGroupService := mock.NewMockGroupService(ctl)
+			gomock.InOrder(
+				mockGroupService.EXPECT().ListSubDepartmentMemberPKs(int64(1), []int64{3}).Return([]int64{5}, nil),
+				mockGroupService.EXPECT().UpdateMembersIncludeSubDepartment(int64(1), []int64{3}, false).Return(nil),
+			)
+			mockGroupService.EXPECT().ListAncestorGroupPKs(int64(1)).Return(nil, nil)
+
+			mockGroupAlterEventService := mock.NewMockGroupAlterEventService(ctl)
+			mockGroupAlterEventService.EXPECT().CreateByGroupSubject(int64(1), []int64{5}).Return(nil)
+
+			manager := &groupController{
+				service:                mockGroupService,
+				groupAlterEventService: mockGroupAlterEventService,
+			}
+
+			err := manager.UpdateDepartmentMembersIncludeSubDepartment("group", "1", []string{"3"}, false)
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("ListPagingSubjectGroups at", func() {
+		var ctl *gomock.Controller
+		var patches *gomonkey.Patches
+		var mockSubjectGroupHistoryService *mock.MockSubjectGroupHistoryService
+		var manager *groupController
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+			mockSubjectGroupHistoryService = mock.NewMockSubjectGroupHistoryService(ctl)
+			manager = &groupController{subjectGroupHistoryService: mockSubjectGroupHistoryService}
+
+			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+				return 1, nil
+			})
+			patches.ApplyFunc(cacheimpls.BatchGetSubjectByPKs, func(pks []int64) ([]types.Subject, error) {
+				subjects := make([]types.Subject, 0, len(pks))
+				for _, pk := range pks {
+					subjects = append(subjects, types.Subject{PK: pk, Type: "group", ID: strconv.FormatInt(pk, 10)})
+				}
+				return subjects, nil
+			})
+		})
+		AfterEach(func() {
+			ctl.Finish()
+			patches.Reset()
+		})
+
+		It("fail", func() {
+			mockSubjectGroupHistoryService.EXPECT().ListSubjectGroupsAt(int64(1), int64(100)).Return(
+				nil, errors.New("error"),
+			)
+
+			_, err := manager.ListPagingSubjectGroups("user", "alice", 0, 100, 10, 0)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "listSubjectGroupsAt")
+		})
+
+		It("ok", func() {
+			mockSubjectGroupHistoryService.EXPECT().ListSubjectGroupsAt(int64(1), int64(100)).Return(
+				[]types.SubjectGroup{{GroupPK: 2, ExpiredAt: 300}, {GroupPK: 3, ExpiredAt: 200}}, nil,
+			).Times(2)
+
+			groups, err := manager.ListPagingSubjectGroups("user", "alice", 250, 100, 10, 0)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []SubjectGroup{{Type: "group", ID: "3", ExpiredAt: 200}}, groups)
+
+			count, err := manager.GetSubjectGroupCountAt("user", "alice", 0, 100)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), int64(2), count)
+		})
+	})
+
 	Describe("ListSubjectGroupDetails", func() {
 		var ctl *gomock.Controller
 		var patches *gomonkey.Patches
@@ -657,8 +1056,8 @@ var _ = Describe("GroupController", func() {
 			mockGroupResourcePolicyService.EXPECT().
 				GetAuthorizedActionGroupMap("system", int64(1), int64(1), "id").
 				Return(
-					map[int64][]int64{
-						1: {1, 2},
+					map[int64][]types.AuthorizedGroup{
+						1: {{GroupPK: 1}, {GroupPK: 2}},
 					}, nil,
 				).
 				AnyTimes()
@@ -696,8 +1095,8 @@ var _ = Describe("GroupController", func() {
 			mockGroupResourcePolicyService.EXPECT().
 				GetAuthorizedActionGroupMap("system", int64(1), int64(1), "id").
 				Return(
-					map[int64][]int64{
-						1: {1},
+					map[int64][]types.AuthorizedGroup{
+						1: {{GroupPK: 1}},
 					}, nil,
 				).
 				AnyTimes()
@@ -812,7 +1211,7 @@ var _ = Describe("GroupController", func() {
 			assert.Contains(GinkgoT(), err.Error(), "abac.ParseResourceNode")
 		})
 
-		It("cacheimpls.GetResourceActionAuthorizedGroupPKs error", func() {
+		It("cacheimpls.GetResourceActionAuthorizedGroups error", func() {
 			patches = gomonkey.ApplyFunc(
 				pip.GetActionDetail,
 				func(system, id string) (pk int64, authType int64, arts []abacTypes.ActionResourceType, err error) {
@@ -824,11 +1223,11 @@ var _ = Describe("GroupController", func() {
 				return 1, nil
 			})
 
-			patches.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroupPKs, func(
+			patches.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroups, func(
 				systemID string,
 				actionPK, actionResourceTypePK, resourceTypePK int64,
 				resourceID string,
-			) ([]int64, error) {
+			) ([]types.AuthorizedGroup, error) {
 				return nil, errors.New("err")
 			})
 
@@ -842,7 +1241,7 @@ var _ = Describe("GroupController", func() {
 			})
 
 			assert.Error(GinkgoT(), err)
-			assert.Contains(GinkgoT(), err.Error(), "cacheimpls.GetResourceActionAuthorizedGroupPKs")
+			assert.Contains(GinkgoT(), err.Error(), "cacheimpls.GetResourceActionAuthorizedGroups")
 		})
 
 		It("cacheimpls.GetSubjectByPK error", func() {
@@ -857,12 +1256,12 @@ var _ = Describe("GroupController", func() {
 				return 1, nil
 			})
 
-			patches.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroupPKs, func(
+			patches.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroups, func(
 				systemID string,
 				actionPK, actionResourceTypePK, resourceTypePK int64,
 				resourceID string,
-			) ([]int64, error) {
-				return []int64{1}, nil
+			) ([]types.AuthorizedGroup, error) {
+				return []types.AuthorizedGroup{{GroupPK: 1}}, nil
 			})
 
 			patches.ApplyFunc(cacheimpls.BatchGetSubjectByPKs, func(pks []int64) (subjects []types.Subject, err error) {
@@ -894,12 +1293,12 @@ var _ = Describe("GroupController", func() {
 				return 1, nil
 			})
 
-			patches.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroupPKs, func(
+			patches.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroups, func(
 				systemID string,
 				actionPK, actionResourceTypePK, resourceTypePK int64,
 				resourceID string,
-			) ([]int64, error) {
-				return []int64{1}, nil
+			) ([]types.AuthorizedGroup, error) {
+				return []types.AuthorizedGroup{{GroupPK: 1}}, nil
 			})
 
 			patches.ApplyFunc(cacheimpls.BatchGetSubjectByPKs, func(pks []int64) (subjects []types.Subject, err error) {
@@ -923,8 +1322,15 @@ var _ = Describe("GroupController", func() {
 	Describe("BulkCreateSubjectTemplateGroup", func() {
 		var ctl *gomock.Controller
 		var patches *gomonkey.Patches
+		var mockSubjectGroupHistoryService *mock.MockSubjectGroupHistoryService
+		var mockSoDConstraintService *mock.MockSoDConstraintService
 		BeforeEach(func() {
 			ctl = gomock.NewController(GinkgoT())
+			mockSubjectGroupHistoryService = mock.NewMockSubjectGroupHistoryService(ctl)
+			mockSubjectGroupHistoryService.EXPECT().BulkCreateWithTx(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockSoDConstraintService = mock.NewMockSoDConstraintService(ctl)
+			mockSoDConstraintService.EXPECT().CheckGroupMembers(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockSoDConstraintService.EXPECT().CheckGroupMemberActions(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
 				switch id {
@@ -945,7 +1351,7 @@ var _ = Describe("GroupController", func() {
 		})
 
 		It("convertToSubjectTemplateGroups fail", func() {
-			manager := &groupController{}
+			manager := &groupController{subjectGroupHistoryService: mockSubjectGroupHistoryService}
 
 			err := manager.BulkCreateSubjectTemplateGroup([]SubjectTemplateGroup{
 				{
@@ -955,7 +1361,7 @@ var _ = Describe("GroupController", func() {
 					GroupID:    1,
 					ExpiredAt:  3,
 				},
-			})
+			}, "admin")
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "convertToSubjectTemplateGroups")
 		})
@@ -972,7 +1378,7 @@ var _ = Describe("GroupController", func() {
 				return helper
 			})
 
-			manager := &groupController{}
+			manager := &groupController{sodConstraintService: mockSoDConstraintService, subjectGroupHistoryService: mockSubjectGroupHistoryService}
 
 			err := manager.BulkCreateSubjectTemplateGroup([]SubjectTemplateGroup{
 				{
@@ -982,7 +1388,7 @@ var _ = Describe("GroupController", func() {
 					GroupID:    2,
 					ExpiredAt:  3,
 				},
-			})
+			}, "admin")
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "getSubjectGroup")
 		})
@@ -1014,7 +1420,9 @@ var _ = Describe("GroupController", func() {
 			})
 
 			manager := &groupController{
-				service: mockService,
+				service:                    mockService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			err := manager.BulkCreateSubjectTemplateGroup([]SubjectTemplateGroup{
@@ -1025,7 +1433,7 @@ var _ = Describe("GroupController", func() {
 					GroupID:    2,
 					ExpiredAt:  3,
 				},
-			})
+			}, "admin")
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "BulkCreateSubjectTemplateGroupWithTx")
 		})
@@ -1055,7 +1463,9 @@ var _ = Describe("GroupController", func() {
 			})
 
 			manager := &groupController{
-				service: mockService,
+				service:                    mockService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			patches.ApplyPrivateMethod(reflect.TypeOf(manager), "updateSubjectGroupExpiredAtWithTx", func(
@@ -1075,7 +1485,7 @@ var _ = Describe("GroupController", func() {
 					GroupID:    2,
 					ExpiredAt:  3,
 				},
-			})
+			}, "admin")
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "updateSubjectGroupExpiredAtWithTx")
 		})
@@ -1105,7 +1515,9 @@ var _ = Describe("GroupController", func() {
 			})
 
 			manager := &groupController{
-				service: mockService,
+				service:                    mockService,
+				sodConstraintService:       mockSoDConstraintService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			patches.ApplyPrivateMethod(reflect.TypeOf(manager), "updateSubjectGroupExpiredAtWithTx", func(
@@ -1125,7 +1537,7 @@ var _ = Describe("GroupController", func() {
 					GroupID:    2,
 					ExpiredAt:  3,
 				},
-			})
+			}, "admin")
 			assert.NoError(GinkgoT(), err)
 		})
 	})
@@ -1133,8 +1545,11 @@ var _ = Describe("GroupController", func() {
 	Describe("BulkDeleteSubjectTemplateGroup", func() {
 		var ctl *gomock.Controller
 		var patches *gomonkey.Patches
+		var mockSubjectGroupHistoryService *mock.MockSubjectGroupHistoryService
 		BeforeEach(func() {
 			ctl = gomock.NewController(GinkgoT())
+			mockSubjectGroupHistoryService = mock.NewMockSubjectGroupHistoryService(ctl)
+			mockSubjectGroupHistoryService.EXPECT().BulkCreateWithTx(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
 				switch id {
@@ -1155,7 +1570,7 @@ var _ = Describe("GroupController", func() {
 		})
 
 		It("convertToSubjectTemplateGroups fail", func() {
-			manager := &groupController{}
+			manager := &groupController{subjectGroupHistoryService: mockSubjectGroupHistoryService}
 
 			err := manager.BulkDeleteSubjectTemplateGroup([]SubjectTemplateGroup{
 				{
@@ -1165,7 +1580,7 @@ var _ = Describe("GroupController", func() {
 					GroupID:    1,
 					ExpiredAt:  3,
 				},
-			})
+			}, "admin")
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "convertToSubjectTemplateGroups")
 		})
@@ -1177,7 +1592,8 @@ var _ = Describe("GroupController", func() {
 				Return(int64(0), errors.New("err"))
 
 			manager := &groupController{
-				service: mockService,
+				service:                    mockService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			err := manager.BulkDeleteSubjectTemplateGroup([]SubjectTemplateGroup{
@@ -1188,7 +1604,7 @@ var _ = Describe("GroupController", func() {
 					GroupID:    2,
 					ExpiredAt:  3,
 				},
-			})
+			}, "admin")
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "GetMaxExpiredAtBySubjectGroup")
 		})
@@ -1212,7 +1628,8 @@ var _ = Describe("GroupController", func() {
 			})
 
 			manager := &groupController{
-				service: mockService,
+				service:                    mockService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			err := manager.BulkDeleteSubjectTemplateGroup([]SubjectTemplateGroup{
@@ -1223,7 +1640,7 @@ var _ = Describe("GroupController", func() {
 					GroupID:    2,
 					ExpiredAt:  3,
 				},
-			})
+			}, "admin")
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "BulkDeleteSubjectTemplateGroupWithTx")
 		})
@@ -1245,7 +1662,8 @@ var _ = Describe("GroupController", func() {
 			})
 
 			manager := &groupController{
-				service: mockService,
+				service:                    mockService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			err := manager.BulkDeleteSubjectTemplateGroup([]SubjectTemplateGroup{
@@ -1256,7 +1674,7 @@ var _ = Describe("GroupController", func() {
 					GroupID:    2,
 					ExpiredAt:  3,
 				},
-			})
+			}, "admin")
 			assert.NoError(GinkgoT(), err)
 		})
 	})
@@ -62,6 +62,20 @@ func (mr *MockDepartmentControllerMockRecorder) BulkDelete(subjectIDs interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDelete", reflect.TypeOf((*MockDepartmentController)(nil).BulkDelete), subjectIDs)
 }
 
+// BulkDeleteParents mocks base method.
+func (m *MockDepartmentController) BulkDeleteParents(departmentIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkDeleteParents", departmentIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkDeleteParents indicates an expected call of BulkDeleteParents.
+func (mr *MockDepartmentControllerMockRecorder) BulkDeleteParents(departmentIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteParents", reflect.TypeOf((*MockDepartmentController)(nil).BulkDeleteParents), departmentIDs)
+}
+
 // BulkUpdate mocks base method.
 func (m *MockDepartmentController) BulkUpdate(subjectDepartments []pap.SubjectDepartment) error {
 	m.ctrl.T.Helper()
@@ -76,6 +90,20 @@ func (mr *MockDepartmentControllerMockRecorder) BulkUpdate(subjectDepartments in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdate", reflect.TypeOf((*MockDepartmentController)(nil).BulkUpdate), subjectDepartments)
 }
 
+// BulkUpdateParents mocks base method.
+func (m *MockDepartmentController) BulkUpdateParents(departmentParents []pap.DepartmentParent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateParents", departmentParents)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkUpdateParents indicates an expected call of BulkUpdateParents.
+func (mr *MockDepartmentControllerMockRecorder) BulkUpdateParents(departmentParents interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateParents", reflect.TypeOf((*MockDepartmentController)(nil).BulkUpdateParents), departmentParents)
+}
+
 // ListPaging mocks base method.
 func (m *MockDepartmentController) ListPaging(limit, offset int64) ([]pap.SubjectDepartment, error) {
 	m.ctrl.T.Helper()
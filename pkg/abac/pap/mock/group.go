@@ -36,31 +36,31 @@ func (m *MockGroupController) EXPECT() *MockGroupControllerMockRecorder {
 }
 
 // BulkCreateSubjectTemplateGroup mocks base method.
-func (m *MockGroupController) BulkCreateSubjectTemplateGroup(subjectTemplateGroups []pap.SubjectTemplateGroup) error {
+func (m *MockGroupController) BulkCreateSubjectTemplateGroup(subjectTemplateGroups []pap.SubjectTemplateGroup, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BulkCreateSubjectTemplateGroup", subjectTemplateGroups)
+	ret := m.ctrl.Call(m, "BulkCreateSubjectTemplateGroup", subjectTemplateGroups, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // BulkCreateSubjectTemplateGroup indicates an expected call of BulkCreateSubjectTemplateGroup.
-func (mr *MockGroupControllerMockRecorder) BulkCreateSubjectTemplateGroup(subjectTemplateGroups interface{}) *gomock.Call {
+func (mr *MockGroupControllerMockRecorder) BulkCreateSubjectTemplateGroup(subjectTemplateGroups, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkCreateSubjectTemplateGroup", reflect.TypeOf((*MockGroupController)(nil).BulkCreateSubjectTemplateGroup), subjectTemplateGroups)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkCreateSubjectTemplateGroup", reflect.TypeOf((*MockGroupController)(nil).BulkCreateSubjectTemplateGroup), subjectTemplateGroups, actor)
 }
 
 // BulkDeleteSubjectTemplateGroup mocks base method.
-func (m *MockGroupController) BulkDeleteSubjectTemplateGroup(subjectTemplateGroups []pap.SubjectTemplateGroup) error {
+func (m *MockGroupController) BulkDeleteSubjectTemplateGroup(subjectTemplateGroups []pap.SubjectTemplateGroup, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BulkDeleteSubjectTemplateGroup", subjectTemplateGroups)
+	ret := m.ctrl.Call(m, "BulkDeleteSubjectTemplateGroup", subjectTemplateGroups, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // BulkDeleteSubjectTemplateGroup indicates an expected call of BulkDeleteSubjectTemplateGroup.
-func (mr *MockGroupControllerMockRecorder) BulkDeleteSubjectTemplateGroup(subjectTemplateGroups interface{}) *gomock.Call {
+func (mr *MockGroupControllerMockRecorder) BulkDeleteSubjectTemplateGroup(subjectTemplateGroups, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteSubjectTemplateGroup", reflect.TypeOf((*MockGroupController)(nil).BulkDeleteSubjectTemplateGroup), subjectTemplateGroups)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteSubjectTemplateGroup", reflect.TypeOf((*MockGroupController)(nil).BulkDeleteSubjectTemplateGroup), subjectTemplateGroups, actor)
 }
 
 // CheckSubjectEffectGroups mocks base method.
@@ -79,33 +79,63 @@ func (mr *MockGroupControllerMockRecorder) CheckSubjectEffectGroups(_type, id, g
 }
 
 // CreateOrUpdateGroupMembers mocks base method.
-func (m *MockGroupController) CreateOrUpdateGroupMembers(_type, id string, members []pap.GroupMember) (map[string]int64, error) {
+func (m *MockGroupController) CreateOrUpdateGroupMembers(_type, id string, members []pap.GroupMember, actor string) (map[string]int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrUpdateGroupMembers", _type, id, members)
+	ret := m.ctrl.Call(m, "CreateOrUpdateGroupMembers", _type, id, members, actor)
 	ret0, _ := ret[0].(map[string]int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateOrUpdateGroupMembers indicates an expected call of CreateOrUpdateGroupMembers.
-func (mr *MockGroupControllerMockRecorder) CreateOrUpdateGroupMembers(_type, id, members interface{}) *gomock.Call {
+func (mr *MockGroupControllerMockRecorder) CreateOrUpdateGroupMembers(_type, id, members, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateGroupMembers", reflect.TypeOf((*MockGroupController)(nil).CreateOrUpdateGroupMembers), _type, id, members)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateGroupMembers", reflect.TypeOf((*MockGroupController)(nil).CreateOrUpdateGroupMembers), _type, id, members, actor)
 }
 
 // DeleteGroupMembers mocks base method.
-func (m *MockGroupController) DeleteGroupMembers(_type, id string, members []pap.Subject) (map[string]int64, error) {
+func (m *MockGroupController) DeleteGroupMembers(_type, id string, members []pap.Subject, actor string) (map[string]int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteGroupMembers", _type, id, members)
+	ret := m.ctrl.Call(m, "DeleteGroupMembers", _type, id, members, actor)
 	ret0, _ := ret[0].(map[string]int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteGroupMembers indicates an expected call of DeleteGroupMembers.
-func (mr *MockGroupControllerMockRecorder) DeleteGroupMembers(_type, id, members interface{}) *gomock.Call {
+func (mr *MockGroupControllerMockRecorder) DeleteGroupMembers(_type, id, members, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupMembers", reflect.TypeOf((*MockGroupController)(nil).DeleteGroupMembers), _type, id, members)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupMembers", reflect.TypeOf((*MockGroupController)(nil).DeleteGroupMembers), _type, id, members, actor)
+}
+
+// DryRunCreateOrUpdateGroupMembers mocks base method.
+func (m *MockGroupController) DryRunCreateOrUpdateGroupMembers(_type, id string, members []pap.GroupMember) (pap.GroupMembersDryRunResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunCreateOrUpdateGroupMembers", _type, id, members)
+	ret0, _ := ret[0].(pap.GroupMembersDryRunResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunCreateOrUpdateGroupMembers indicates an expected call of DryRunCreateOrUpdateGroupMembers.
+func (mr *MockGroupControllerMockRecorder) DryRunCreateOrUpdateGroupMembers(_type, id, members interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunCreateOrUpdateGroupMembers", reflect.TypeOf((*MockGroupController)(nil).DryRunCreateOrUpdateGroupMembers), _type, id, members)
+}
+
+// DryRunDeleteGroupMembers mocks base method.
+func (m *MockGroupController) DryRunDeleteGroupMembers(_type, id string, members []pap.Subject) (pap.GroupMembersDryRunResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunDeleteGroupMembers", _type, id, members)
+	ret0, _ := ret[0].(pap.GroupMembersDryRunResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunDeleteGroupMembers indicates an expected call of DryRunDeleteGroupMembers.
+func (mr *MockGroupControllerMockRecorder) DryRunDeleteGroupMembers(_type, id, members interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunDeleteGroupMembers", reflect.TypeOf((*MockGroupController)(nil).DryRunDeleteGroupMembers), _type, id, members)
 }
 
 // GetGroupMemberCount mocks base method.
@@ -153,6 +183,21 @@ func (mr *MockGroupControllerMockRecorder) GetGroupSubjectCountBeforeExpiredAt(e
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupSubjectCountBeforeExpiredAt", reflect.TypeOf((*MockGroupController)(nil).GetGroupSubjectCountBeforeExpiredAt), expiredAt)
 }
 
+// GetSubjectGroupCountAt mocks base method.
+func (m *MockGroupController) GetSubjectGroupCountAt(_type, id string, beforeExpiredAt, at int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectGroupCountAt", _type, id, beforeExpiredAt, at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectGroupCountAt indicates an expected call of GetSubjectGroupCountAt.
+func (mr *MockGroupControllerMockRecorder) GetSubjectGroupCountAt(_type, id, beforeExpiredAt, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectGroupCountAt", reflect.TypeOf((*MockGroupController)(nil).GetSubjectGroupCountAt), _type, id, beforeExpiredAt, at)
+}
+
 // GetSubjectGroupCountBeforeExpiredAt mocks base method.
 func (m *MockGroupController) GetSubjectGroupCountBeforeExpiredAt(_type, id string, beforeExpiredAt int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -259,18 +304,18 @@ func (mr *MockGroupControllerMockRecorder) ListPagingGroupSubjectBeforeExpiredAt
 }
 
 // ListPagingSubjectGroups mocks base method.
-func (m *MockGroupController) ListPagingSubjectGroups(_type, id string, beforeExpiredAt, limit, offset int64) ([]pap.SubjectGroup, error) {
+func (m *MockGroupController) ListPagingSubjectGroups(_type, id string, beforeExpiredAt, at, limit, offset int64) ([]pap.SubjectGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPagingSubjectGroups", _type, id, beforeExpiredAt, limit, offset)
+	ret := m.ctrl.Call(m, "ListPagingSubjectGroups", _type, id, beforeExpiredAt, at, limit, offset)
 	ret0, _ := ret[0].([]pap.SubjectGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListPagingSubjectGroups indicates an expected call of ListPagingSubjectGroups.
-func (mr *MockGroupControllerMockRecorder) ListPagingSubjectGroups(_type, id, beforeExpiredAt, limit, offset interface{}) *gomock.Call {
+func (mr *MockGroupControllerMockRecorder) ListPagingSubjectGroups(_type, id, beforeExpiredAt, at, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPagingSubjectGroups", reflect.TypeOf((*MockGroupController)(nil).ListPagingSubjectGroups), _type, id, beforeExpiredAt, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPagingSubjectGroups", reflect.TypeOf((*MockGroupController)(nil).ListPagingSubjectGroups), _type, id, beforeExpiredAt, at, limit, offset)
 }
 
 // ListPagingSubjectSystemGroups mocks base method.
@@ -348,30 +393,44 @@ func (mr *MockGroupControllerMockRecorder) ListSubjectGroupDetails(_type, id, gr
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubjectGroupDetails", reflect.TypeOf((*MockGroupController)(nil).ListSubjectGroupDetails), _type, id, groupIDs)
 }
 
+// UpdateDepartmentMembersIncludeSubDepartment mocks base method.
+func (m *MockGroupController) UpdateDepartmentMembersIncludeSubDepartment(_type, id string, departmentIDs []string, includeSubDepartment bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDepartmentMembersIncludeSubDepartment", _type, id, departmentIDs, includeSubDepartment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDepartmentMembersIncludeSubDepartment indicates an expected call of UpdateDepartmentMembersIncludeSubDepartment.
+func (mr *MockGroupControllerMockRecorder) UpdateDepartmentMembersIncludeSubDepartment(_type, id, departmentIDs, includeSubDepartment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDepartmentMembersIncludeSubDepartment", reflect.TypeOf((*MockGroupController)(nil).UpdateDepartmentMembersIncludeSubDepartment), _type, id, departmentIDs, includeSubDepartment)
+}
+
 // UpdateGroupMembersExpiredAt mocks base method.
-func (m *MockGroupController) UpdateGroupMembersExpiredAt(_type, id string, members []pap.GroupMember) error {
+func (m *MockGroupController) UpdateGroupMembersExpiredAt(_type, id string, members []pap.GroupMember, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateGroupMembersExpiredAt", _type, id, members)
+	ret := m.ctrl.Call(m, "UpdateGroupMembersExpiredAt", _type, id, members, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateGroupMembersExpiredAt indicates an expected call of UpdateGroupMembersExpiredAt.
-func (mr *MockGroupControllerMockRecorder) UpdateGroupMembersExpiredAt(_type, id, members interface{}) *gomock.Call {
+func (mr *MockGroupControllerMockRecorder) UpdateGroupMembersExpiredAt(_type, id, members, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroupMembersExpiredAt", reflect.TypeOf((*MockGroupController)(nil).UpdateGroupMembersExpiredAt), _type, id, members)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroupMembersExpiredAt", reflect.TypeOf((*MockGroupController)(nil).UpdateGroupMembersExpiredAt), _type, id, members, actor)
 }
 
 // UpdateSubjectTemplateGroupExpiredAt mocks base method.
-func (m *MockGroupController) UpdateSubjectTemplateGroupExpiredAt(subjectTemplateGroups []pap.SubjectTemplateGroup) error {
+func (m *MockGroupController) UpdateSubjectTemplateGroupExpiredAt(subjectTemplateGroups []pap.SubjectTemplateGroup, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSubjectTemplateGroupExpiredAt", subjectTemplateGroups)
+	ret := m.ctrl.Call(m, "UpdateSubjectTemplateGroupExpiredAt", subjectTemplateGroups, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateSubjectTemplateGroupExpiredAt indicates an expected call of UpdateSubjectTemplateGroupExpiredAt.
-func (mr *MockGroupControllerMockRecorder) UpdateSubjectTemplateGroupExpiredAt(subjectTemplateGroups interface{}) *gomock.Call {
+func (mr *MockGroupControllerMockRecorder) UpdateSubjectTemplateGroupExpiredAt(subjectTemplateGroups, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubjectTemplateGroupExpiredAt", reflect.TypeOf((*MockGroupController)(nil).UpdateSubjectTemplateGroupExpiredAt), subjectTemplateGroups)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubjectTemplateGroupExpiredAt", reflect.TypeOf((*MockGroupController)(nil).UpdateSubjectTemplateGroupExpiredAt), subjectTemplateGroups, actor)
 }
@@ -5,6 +5,7 @@
 package mock
 
 import (
+	pap "iam/pkg/abac/pap"
 	types "iam/pkg/abac/types"
 	reflect "reflect"
 
@@ -35,31 +36,46 @@ func (m *MockPolicyController) EXPECT() *MockPolicyControllerMockRecorder {
 }
 
 // AlterCustomPolicies mocks base method.
-func (m *MockPolicyController) AlterCustomPolicies(system, subjectType, subjectID string, createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64) error {
+func (m *MockPolicyController) AlterCustomPolicies(system, subjectType, subjectID string, createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64, operator pap.Operator) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AlterCustomPolicies", system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs)
+	ret := m.ctrl.Call(m, "AlterCustomPolicies", system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs, operator)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AlterCustomPolicies indicates an expected call of AlterCustomPolicies.
-func (mr *MockPolicyControllerMockRecorder) AlterCustomPolicies(system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs interface{}) *gomock.Call {
+func (mr *MockPolicyControllerMockRecorder) AlterCustomPolicies(system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs, operator interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlterCustomPolicies", reflect.TypeOf((*MockPolicyController)(nil).AlterCustomPolicies), system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlterCustomPolicies", reflect.TypeOf((*MockPolicyController)(nil).AlterCustomPolicies), system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs, operator)
 }
 
 // AlterGroupPolicies mocks base method.
-func (m *MockPolicyController) AlterGroupPolicies(systemID, subjectType, subjectID string, templateID int64, createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64, resourceChangedActions []types.ResourceChangedAction, groupAuthType int64) error {
+func (m *MockPolicyController) AlterGroupPolicies(systemID, subjectType, subjectID string, templateID int64, createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64, resourceChangedActions []types.ResourceChangedAction, groupAuthType int64, operator pap.Operator) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AlterGroupPolicies", systemID, subjectType, subjectID, templateID, createPolicies, updatePolicies, deletePolicyIDs, resourceChangedActions, groupAuthType)
+	ret := m.ctrl.Call(m, "AlterGroupPolicies", systemID, subjectType, subjectID, templateID, createPolicies, updatePolicies, deletePolicyIDs, resourceChangedActions, groupAuthType, operator)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AlterGroupPolicies indicates an expected call of AlterGroupPolicies.
-func (mr *MockPolicyControllerMockRecorder) AlterGroupPolicies(systemID, subjectType, subjectID, templateID, createPolicies, updatePolicies, deletePolicyIDs, resourceChangedActions, groupAuthType interface{}) *gomock.Call {
+func (mr *MockPolicyControllerMockRecorder) AlterGroupPolicies(systemID, subjectType, subjectID, templateID, createPolicies, updatePolicies, deletePolicyIDs, resourceChangedActions, groupAuthType, operator interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlterGroupPolicies", reflect.TypeOf((*MockPolicyController)(nil).AlterGroupPolicies), systemID, subjectType, subjectID, templateID, createPolicies, updatePolicies, deletePolicyIDs, resourceChangedActions, groupAuthType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlterGroupPolicies", reflect.TypeOf((*MockPolicyController)(nil).AlterGroupPolicies), systemID, subjectType, subjectID, templateID, createPolicies, updatePolicies, deletePolicyIDs, resourceChangedActions, groupAuthType, operator)
+}
+
+// AnalyzePolicyRedundancy mocks base method.
+func (m *MockPolicyController) AnalyzePolicyRedundancy(system, subjectType, subjectID string, cleanup bool, operator pap.Operator) (pap.PolicyRedundancyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnalyzePolicyRedundancy", system, subjectType, subjectID, cleanup, operator)
+	ret0, _ := ret[0].(pap.PolicyRedundancyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnalyzePolicyRedundancy indicates an expected call of AnalyzePolicyRedundancy.
+func (mr *MockPolicyControllerMockRecorder) AnalyzePolicyRedundancy(system, subjectType, subjectID, cleanup, operator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyzePolicyRedundancy", reflect.TypeOf((*MockPolicyController)(nil).AnalyzePolicyRedundancy), system, subjectType, subjectID, cleanup, operator)
 }
 
 // CreateTemporaryPolicies mocks base method.
@@ -92,17 +108,17 @@ func (mr *MockPolicyControllerMockRecorder) DeleteByActionID(system, actionID in
 }
 
 // DeleteByIDs mocks base method.
-func (m *MockPolicyController) DeleteByIDs(system, subjectType, subjectID string, policyIDs []int64) error {
+func (m *MockPolicyController) DeleteByIDs(system, subjectType, subjectID string, policyIDs []int64, operator pap.Operator) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByIDs", system, subjectType, subjectID, policyIDs)
+	ret := m.ctrl.Call(m, "DeleteByIDs", system, subjectType, subjectID, policyIDs, operator)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByIDs indicates an expected call of DeleteByIDs.
-func (mr *MockPolicyControllerMockRecorder) DeleteByIDs(system, subjectType, subjectID, policyIDs interface{}) *gomock.Call {
+func (mr *MockPolicyControllerMockRecorder) DeleteByIDs(system, subjectType, subjectID, policyIDs, operator interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByIDs", reflect.TypeOf((*MockPolicyController)(nil).DeleteByIDs), system, subjectType, subjectID, policyIDs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByIDs", reflect.TypeOf((*MockPolicyController)(nil).DeleteByIDs), system, subjectType, subjectID, policyIDs, operator)
 }
 
 // DeleteTemporaryBeforeExpiredAt mocks base method.
@@ -133,6 +149,96 @@ func (mr *MockPolicyControllerMockRecorder) DeleteTemporaryByIDs(system, subject
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemporaryByIDs", reflect.TypeOf((*MockPolicyController)(nil).DeleteTemporaryByIDs), system, subjectType, subjectID, policyIDs)
 }
 
+// DiffPolicyHistory mocks base method.
+func (m *MockPolicyController) DiffPolicyHistory(system, subjectType, subjectID string, fromVersion, toVersion int64) ([]pap.PolicyChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffPolicyHistory", system, subjectType, subjectID, fromVersion, toVersion)
+	ret0, _ := ret[0].([]pap.PolicyChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffPolicyHistory indicates an expected call of DiffPolicyHistory.
+func (mr *MockPolicyControllerMockRecorder) DiffPolicyHistory(system, subjectType, subjectID, fromVersion, toVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffPolicyHistory", reflect.TypeOf((*MockPolicyController)(nil).DiffPolicyHistory), system, subjectType, subjectID, fromVersion, toVersion)
+}
+
+// DryRunAlterCustomPolicies mocks base method.
+func (m *MockPolicyController) DryRunAlterCustomPolicies(system, subjectType, subjectID string, createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64, operator pap.Operator) (pap.PoliciesDryRunResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunAlterCustomPolicies", system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs, operator)
+	ret0, _ := ret[0].(pap.PoliciesDryRunResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunAlterCustomPolicies indicates an expected call of DryRunAlterCustomPolicies.
+func (mr *MockPolicyControllerMockRecorder) DryRunAlterCustomPolicies(system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs, operator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunAlterCustomPolicies", reflect.TypeOf((*MockPolicyController)(nil).DryRunAlterCustomPolicies), system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs, operator)
+}
+
+// DryRunAlterGroupPolicies mocks base method.
+func (m *MockPolicyController) DryRunAlterGroupPolicies(systemID, subjectType, subjectID string, templateID int64, createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64, resourceChangedActions []types.ResourceChangedAction, groupAuthType int64, operator pap.Operator) (pap.PoliciesDryRunResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunAlterGroupPolicies", systemID, subjectType, subjectID, templateID, createPolicies, updatePolicies, deletePolicyIDs, resourceChangedActions, groupAuthType, operator)
+	ret0, _ := ret[0].(pap.PoliciesDryRunResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunAlterGroupPolicies indicates an expected call of DryRunAlterGroupPolicies.
+func (mr *MockPolicyControllerMockRecorder) DryRunAlterGroupPolicies(systemID, subjectType, subjectID, templateID, createPolicies, updatePolicies, deletePolicyIDs, resourceChangedActions, groupAuthType, operator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunAlterGroupPolicies", reflect.TypeOf((*MockPolicyController)(nil).DryRunAlterGroupPolicies), systemID, subjectType, subjectID, templateID, createPolicies, updatePolicies, deletePolicyIDs, resourceChangedActions, groupAuthType, operator)
+}
+
+// DryRunDeleteByIDs mocks base method.
+func (m *MockPolicyController) DryRunDeleteByIDs(system, subjectType, subjectID string, policyIDs []int64, operator pap.Operator) (pap.PoliciesDryRunResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunDeleteByIDs", system, subjectType, subjectID, policyIDs, operator)
+	ret0, _ := ret[0].(pap.PoliciesDryRunResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunDeleteByIDs indicates an expected call of DryRunDeleteByIDs.
+func (mr *MockPolicyControllerMockRecorder) DryRunDeleteByIDs(system, subjectType, subjectID, policyIDs, operator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunDeleteByIDs", reflect.TypeOf((*MockPolicyController)(nil).DryRunDeleteByIDs), system, subjectType, subjectID, policyIDs, operator)
+}
+
+// GetPolicyHistoryCount mocks base method.
+func (m *MockPolicyController) GetPolicyHistoryCount(system, subjectType, subjectID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicyHistoryCount", system, subjectType, subjectID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicyHistoryCount indicates an expected call of GetPolicyHistoryCount.
+func (mr *MockPolicyControllerMockRecorder) GetPolicyHistoryCount(system, subjectType, subjectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicyHistoryCount", reflect.TypeOf((*MockPolicyController)(nil).GetPolicyHistoryCount), system, subjectType, subjectID)
+}
+
+// ListPagingPolicyHistory mocks base method.
+func (m *MockPolicyController) ListPagingPolicyHistory(system, subjectType, subjectID string, limit, offset int64) ([]pap.PolicyHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPagingPolicyHistory", system, subjectType, subjectID, limit, offset)
+	ret0, _ := ret[0].([]pap.PolicyHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPagingPolicyHistory indicates an expected call of ListPagingPolicyHistory.
+func (mr *MockPolicyControllerMockRecorder) ListPagingPolicyHistory(system, subjectType, subjectID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPagingPolicyHistory", reflect.TypeOf((*MockPolicyController)(nil).ListPagingPolicyHistory), system, subjectType, subjectID, limit, offset)
+}
+
 // ListSaaSBySubjectSystemTemplate mocks base method.
 func (m *MockPolicyController) ListSaaSBySubjectSystemTemplate(system, subjectType, subjectID string, templateID int64) ([]types.SaaSPolicy, error) {
 	m.ctrl.T.Helper()
@@ -162,3 +268,17 @@ func (mr *MockPolicyControllerMockRecorder) ListSaaSBySubjectTemplateBeforeExpir
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSaaSBySubjectTemplateBeforeExpiredAt", reflect.TypeOf((*MockPolicyController)(nil).ListSaaSBySubjectTemplateBeforeExpiredAt), subjectType, subjectID, templateID, expiredAt)
 }
+
+// RollbackPolicies mocks base method.
+func (m *MockPolicyController) RollbackPolicies(system, subjectType, subjectID string, version int64, operator pap.Operator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackPolicies", system, subjectType, subjectID, version, operator)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackPolicies indicates an expected call of RollbackPolicies.
+func (mr *MockPolicyControllerMockRecorder) RollbackPolicies(system, subjectType, subjectID, version, operator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackPolicies", reflect.TypeOf((*MockPolicyController)(nil).RollbackPolicies), system, subjectType, subjectID, version, operator)
+}
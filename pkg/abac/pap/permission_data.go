@@ -0,0 +1,926 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/abac/types"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service"
+	svctypes "iam/pkg/service/types"
+)
+
+// PermissionDataCTL ...
+const PermissionDataCTL = "PermissionDataCTL"
+
+const (
+	PermissionRecordKindGroup           = "group"
+	PermissionRecordKindGroupMember     = "group_member"
+	PermissionRecordKindPolicy          = "policy"
+	PermissionRecordKindRBACPolicy      = "rbac_policy"
+	PermissionRecordKindTemporaryPolicy = "temporary_policy"
+)
+
+const exportPageSize int64 = 1000
+
+// PermissionDataController 系统权限数据的导出与导入, 用于系统拆分或跨环境迁移
+type PermissionDataController interface {
+	Export(systemID string, write func(record PermissionRecord) error) error
+	Import(systemID string, records []PermissionRecord, operator Operator) (PermissionImportReport, error)
+}
+
+type permissionDataController struct {
+	subjectService             service.SubjectService
+	actionService              service.ActionService
+	groupService               service.GroupService
+	policyService              service.PolicyService
+	openAbacPolicyService      service.OpenAbacPolicyService
+	temporaryPolicyService     service.TemporaryPolicyService
+	groupResourcePolicyService service.GroupResourcePolicyService
+
+	// 导入通过PAP的变更逻辑执行, 保证校验, 事件, 缓存清理与页面操作一致
+	subjectController SubjectController
+	groupController   GroupController
+	policyController  PolicyController
+}
+
+func NewPermissionDataController() PermissionDataController {
+	return &permissionDataController{
+		subjectService:             service.NewSubjectService(),
+		actionService:              service.NewActionService(),
+		groupService:               service.NewGroupService(),
+		policyService:              service.NewPolicyService(),
+		openAbacPolicyService:      service.NewOpenAbacPolicyService(),
+		temporaryPolicyService:     service.NewTemporaryPolicyService(),
+		groupResourcePolicyService: service.NewGroupResourcePolicyService(),
+
+		subjectController: NewSubjectController(),
+		groupController:   NewGroupController(),
+		policyController:  NewPolicyController(),
+	}
+}
+
+// getSubject 查询subject, 已删除的subject返回found=false
+func getSubject(pk int64) (subject Subject, found bool, err error) {
+	s, err := cacheimpls.GetSubjectByPK(pk)
+	if errors.Is(err, sql.ErrNoRows) {
+		return subject, false, nil
+	}
+	if err != nil {
+		return subject, false, err
+	}
+	return Subject{Type: s.Type, ID: s.ID, Name: s.Name}, true, nil
+}
+
+// Export 按行输出系统下未过期(包含未到生效时间)的自定义/模板权限, RBAC授权, 临时权限, 以及相关用户组及其成员
+func (c *permissionDataController) Export(systemID string, write func(record PermissionRecord) error) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PermissionDataCTL, "Export")
+
+	actions, err := c.actionService.ListThinActionBySystem(systemID)
+	if err != nil {
+		return errorWrapf(err, "actionService.ListThinActionBySystem system=`%s` fail", systemID)
+	}
+	actionIDMap := make(map[int64]string, len(actions))
+	actionPKs := make([]int64, 0, len(actions))
+	for _, a := range actions {
+		actionIDMap[a.PK] = a.ID
+		actionPKs = append(actionPKs, a.PK)
+	}
+
+	now := time.Now().Unix()
+	// 记录有权限的用户组, 最后导出用户组及其成员
+	groupPKSet := set.NewInt64Set()
+
+	for _, a := range actions {
+		err = c.exportActionPolicies(a, now, groupPKSet, write)
+		if err != nil {
+			return errorWrapf(err, "exportActionPolicies system=`%s` action=`%s` fail", systemID, a.ID)
+		}
+	}
+
+	err = c.exportRBACPolicies(systemID, actionIDMap, groupPKSet, write)
+	if err != nil {
+		return errorWrapf(err, "exportRBACPolicies system=`%s` fail", systemID)
+	}
+
+	err = c.exportTemporaryPolicies(actionPKs, actionIDMap, now, write)
+	if err != nil {
+		return errorWrapf(err, "exportTemporaryPolicies system=`%s` fail", systemID)
+	}
+
+	groupPKs := groupPKSet.ToSlice()
+	sort.Slice(groupPKs, func(i, j int) bool { return groupPKs[i] < groupPKs[j] })
+	err = c.exportGroups(systemID, groupPKs, now, write)
+	if err != nil {
+		return errorWrapf(err, "exportGroups system=`%s` fail", systemID)
+	}
+	return nil
+}
+
+func (c *permissionDataController) exportActionPolicies(
+	action svctypes.ThinAction, now int64, groupPKSet *set.Int64Set, write func(record PermissionRecord) error,
+) error {
+	for offset := int64(0); ; offset += exportPageSize {
+		policies, err := c.openAbacPolicyService.ListAllPagingByActionBeforeExpiredAt(
+			action.PK, now, offset, exportPageSize,
+		)
+		if err != nil {
+			return err
+		}
+		if len(policies) == 0 {
+			return nil
+		}
+
+		expressionPKs := make([]int64, 0, len(policies))
+		for _, p := range policies {
+			expressionPKs = append(expressionPKs, p.ExpressionPK)
+		}
+		expressions, err := c.policyService.ListExpressionByPKs(expressionPKs)
+		if err != nil {
+			return err
+		}
+		expressionMap := make(map[int64]string, len(expressions))
+		for _, e := range expressions {
+			expressionMap[e.PK] = e.Expression
+		}
+
+		for _, p := range policies {
+			subject, found, err := getSubject(p.SubjectPK)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+			if subject.Type == svctypes.GroupType {
+				groupPKSet.Add(p.SubjectPK)
+			}
+
+			err = write(PermissionRecord{
+				Kind:        PermissionRecordKindPolicy,
+				Subject:     subject,
+				ActionID:    action.ID,
+				Expression:  expressionMap[p.ExpressionPK],
+				Effect:      svctypes.ConvertToPolicyEffectStr(p.Effect),
+				TemplateID:  p.TemplateID,
+				ExpiredAt:   p.ExpiredAt,
+				EffectiveAt: normalizeEffectiveAt(p.EffectiveAt),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if int64(len(policies)) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+func (c *permissionDataController) exportRBACPolicies(
+	systemID string, actionIDMap map[int64]string, groupPKSet *set.Int64Set,
+	write func(record PermissionRecord) error,
+) error {
+	var lastPK int64
+	for {
+		policies, err := c.groupResourcePolicyService.ListPagingBySystem(systemID, lastPK, exportPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range policies {
+			lastPK = p.PK
+
+			// 忽略已删除的操作
+			actionIDs := make([]string, 0, len(p.ActionPKs))
+			for _, actionPK := range p.ActionPKs {
+				if actionID, ok := actionIDMap[actionPK]; ok {
+					actionIDs = append(actionIDs, actionID)
+				}
+			}
+			if len(actionIDs) == 0 {
+				continue
+			}
+
+			group, found, err := getSubject(p.GroupPK)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+
+			resources, err := convertToPermissionResources(p)
+			if err != nil {
+				return err
+			}
+
+			groupPKSet.Add(p.GroupPK)
+			err = write(PermissionRecord{
+				Kind:       PermissionRecordKindRBACPolicy,
+				Subject:    group,
+				ActionIDs:  actionIDs,
+				Resources:  resources,
+				TemplateID: p.TemplateID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if int64(len(policies)) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+func convertToPermissionResources(policy svctypes.GroupResourcePolicy) ([]PermissionResource, error) {
+	resourceTypePKs := make([]int64, 0, 1+len(policy.RelatedResources))
+	resourceIDs := make([]string, 0, 1+len(policy.RelatedResources))
+	resourceTypePKs = append(resourceTypePKs, policy.ResourceTypePK)
+	resourceIDs = append(resourceIDs, policy.ResourceID)
+	for _, rr := range policy.RelatedResources {
+		resourceTypePKs = append(resourceTypePKs, rr.ResourceTypePK)
+		resourceIDs = append(resourceIDs, rr.ResourceID)
+	}
+
+	resources := make([]PermissionResource, 0, len(resourceTypePKs))
+	for i, pk := range resourceTypePKs {
+		resourceType, err := cacheimpls.GetThinResourceType(pk)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, PermissionResource{
+			System: resourceType.System,
+			Type:   resourceType.ID,
+			ID:     resourceIDs[i],
+		})
+	}
+	return resources, nil
+}
+
+func (c *permissionDataController) exportTemporaryPolicies(
+	actionPKs []int64, actionIDMap map[int64]string, now int64, write func(record PermissionRecord) error,
+) error {
+	policies, err := c.temporaryPolicyService.ListByActionPKsBeforeExpiredAt(actionPKs, now)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range policies {
+		subject, found, err := getSubject(p.SubjectPK)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		err = write(PermissionRecord{
+			Kind:       PermissionRecordKindTemporaryPolicy,
+			Subject:    subject,
+			ActionID:   actionIDMap[p.ActionPK],
+			Expression: p.Expression,
+			ExpiredAt:  p.ExpiredAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportGroups 导出用户组及其直接成员, 人员模板带来的成员由模板同步, 不导出
+func (c *permissionDataController) exportGroups(
+	systemID string, groupPKs []int64, now int64, write func(record PermissionRecord) error,
+) error {
+	if len(groupPKs) == 0 {
+		return nil
+	}
+
+	groupAuthTypes, err := c.groupService.ListGroupAuthBySystemGroupPKs(systemID, groupPKs)
+	if err != nil {
+		return err
+	}
+	authTypeMap := make(map[int64]int64, len(groupAuthTypes))
+	for _, g := range groupAuthTypes {
+		authTypeMap[g.GroupPK] = g.AuthType
+	}
+
+	for _, groupPK := range groupPKs {
+		group, found, err := getSubject(groupPK)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		err = write(PermissionRecord{
+			Kind:     PermissionRecordKindGroup,
+			Subject:  group,
+			AuthType: svctypes.ConvertToAuthTypeStr(authTypeMap[groupPK]),
+		})
+		if err != nil {
+			return err
+		}
+
+		members, err := c.groupService.ListGroupMember(groupPK)
+		if err != nil {
+			return err
+		}
+		for _, m := range members {
+			if m.ExpiredAt <= now {
+				continue
+			}
+
+			member, found, err := getSubject(m.SubjectPK)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+
+			err = write(PermissionRecord{
+				Kind:        PermissionRecordKindGroupMember,
+				Subject:     Subject{Type: group.Type, ID: group.ID},
+				Member:      &Subject{Type: member.Type, ID: member.ID},
+				ExpiredAt:   m.ExpiredAt,
+				EffectiveAt: normalizeEffectiveAt(m.EffectiveAt),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// indexedRecord 导入的记录及其行号
+type indexedRecord struct {
+	line   int
+	record PermissionRecord
+}
+
+// recordBatches 按key分组的导入记录, 保持key首次出现的顺序
+type recordBatches struct {
+	keys    []string
+	records map[string][]indexedRecord
+}
+
+func newRecordBatches() *recordBatches {
+	return &recordBatches{records: map[string][]indexedRecord{}}
+}
+
+func (b *recordBatches) add(key string, r indexedRecord) {
+	if _, ok := b.records[key]; !ok {
+		b.keys = append(b.keys, key)
+	}
+	b.records[key] = append(b.records[key], r)
+}
+
+// permissionImport 一次导入的上下文
+type permissionImport struct {
+	systemID    string
+	operator    Operator
+	now         int64
+	actionPKMap map[string]int64
+	// 导入数据中用户组记录的auth type, 变更用户组权限时使用
+	groupAuthTypes map[string]int64
+
+	report PermissionImportReport
+}
+
+func (im *permissionImport) conflict(r indexedRecord, reason string) {
+	im.report.Conflicts = append(im.report.Conflicts, PermissionImportConflict{
+		Line:    r.line,
+		Kind:    r.record.Kind,
+		Subject: r.record.Subject,
+		Reason:  reason,
+	})
+}
+
+func (im *permissionImport) conflictAll(records []indexedRecord, reason string) {
+	for _, r := range records {
+		im.conflict(r, reason)
+	}
+}
+
+// Import 幂等导入系统权限数据, 已存在且一致的记录跳过, 无法应用的记录作为冲突返回, 不中断导入
+// NOTE: 导入按 用户组 -> 用户组成员 -> 自定义/模板权限 -> RBAC授权 -> 临时权限 的顺序执行, 与记录在数据中的顺序无关
+func (c *permissionDataController) Import(
+	systemID string, records []PermissionRecord, operator Operator,
+) (PermissionImportReport, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PermissionDataCTL, "Import")
+
+	actions, err := c.actionService.ListThinActionBySystem(systemID)
+	if err != nil {
+		return PermissionImportReport{}, errorWrapf(
+			err, "actionService.ListThinActionBySystem system=`%s` fail", systemID,
+		)
+	}
+	actionPKMap := make(map[string]int64, len(actions))
+	for _, a := range actions {
+		actionPKMap[a.ID] = a.PK
+	}
+
+	im := &permissionImport{
+		systemID:       systemID,
+		operator:       operator,
+		now:            time.Now().Unix(),
+		actionPKMap:    actionPKMap,
+		groupAuthTypes: map[string]int64{},
+		report: PermissionImportReport{
+			Total:     len(records),
+			Conflicts: []PermissionImportConflict{},
+		},
+	}
+
+	steps := []struct {
+		kind string
+		fn   func(im *permissionImport, records []indexedRecord) error
+	}{
+		{PermissionRecordKindGroup, c.importGroups},
+		{PermissionRecordKindGroupMember, c.importGroupMembers},
+		{PermissionRecordKindPolicy, c.importPolicies},
+		{PermissionRecordKindRBACPolicy, c.importRBACPolicies},
+		{PermissionRecordKindTemporaryPolicy, c.importTemporaryPolicies},
+	}
+
+	kindRecords := make(map[string][]indexedRecord, len(steps))
+	for _, step := range steps {
+		kindRecords[step.kind] = []indexedRecord{}
+	}
+	for i, record := range records {
+		r := indexedRecord{line: i + 1, record: record}
+		if _, ok := kindRecords[record.Kind]; !ok {
+			im.conflict(r, fmt.Sprintf("unknown kind `%s`", record.Kind))
+			continue
+		}
+		kindRecords[record.Kind] = append(kindRecords[record.Kind], r)
+	}
+
+	for _, step := range steps {
+		err = step.fn(im, kindRecords[step.kind])
+		if err != nil {
+			return im.report, errorWrapf(err, "import `%s` records system=`%s` fail", step.kind, systemID)
+		}
+	}
+	return im.report, nil
+}
+
+func (c *permissionDataController) importGroups(im *permissionImport, records []indexedRecord) error {
+	createdGroupIDs := set.NewStringSet()
+	groups := make([]Subject, 0, len(records))
+	for _, r := range records {
+		group := r.record.Subject
+		if group.Type != svctypes.GroupType || group.ID == "" {
+			im.conflict(r, "subject should be a group")
+			continue
+		}
+		if r.record.AuthType != "" {
+			im.groupAuthTypes[group.ID] = svctypes.ConvertToAuthTypeInt(r.record.AuthType)
+		}
+		if createdGroupIDs.Has(group.ID) {
+			im.report.Skipped++
+			continue
+		}
+
+		_, err := c.subjectService.GetPK(group.Type, group.ID)
+		if err == nil {
+			im.report.Skipped++
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		createdGroupIDs.Add(group.ID)
+		groups = append(groups, group)
+	}
+
+	if len(groups) == 0 {
+		return nil
+	}
+	err := c.subjectController.BulkCreate(groups)
+	if err != nil {
+		return err
+	}
+	im.report.Applied += len(groups)
+	return nil
+}
+
+func (c *permissionDataController) importGroupMembers(im *permissionImport, records []indexedRecord) error {
+	batches := newRecordBatches()
+	for _, r := range records {
+		if r.record.Subject.Type != svctypes.GroupType || r.record.Member == nil {
+			im.conflict(r, "subject should be a group with a member")
+			continue
+		}
+		if r.record.ExpiredAt <= im.now {
+			im.report.Skipped++
+			continue
+		}
+		batches.add(r.record.Subject.ID, r)
+	}
+
+	for _, groupID := range batches.keys {
+		rs := batches.records[groupID]
+		members := make([]GroupMember, 0, len(rs))
+		for _, r := range rs {
+			members = append(members, GroupMember{
+				Type:        r.record.Member.Type,
+				ID:          r.record.Member.ID,
+				ExpiredAt:   r.record.ExpiredAt,
+				EffectiveAt: r.record.EffectiveAt,
+			})
+		}
+
+		_, err := c.groupController.CreateOrUpdateGroupMembers(
+			svctypes.GroupType, groupID, members, im.operator.Actor,
+		)
+		if err == nil {
+			im.report.Applied += len(rs)
+			continue
+		}
+
+		// 批量添加失败时逐个添加, 以定位冲突的成员
+		for i, r := range rs {
+			_, err = c.groupController.CreateOrUpdateGroupMembers(
+				svctypes.GroupType, groupID, members[i:i+1], im.operator.Actor,
+			)
+			if err != nil {
+				im.conflict(r, err.Error())
+				continue
+			}
+			im.report.Applied++
+		}
+	}
+	return nil
+}
+
+// getSubjectPKForImport 查询导入记录的subject, 不存在时记录冲突并返回found=false
+func (c *permissionDataController) getSubjectPKForImport(
+	im *permissionImport, subject Subject, records []indexedRecord,
+) (pk int64, found bool, err error) {
+	pk, err = c.subjectService.GetPK(subject.Type, subject.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		im.conflictAll(records, fmt.Sprintf("subject `%s:%s` not exists", subject.Type, subject.ID))
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return pk, true, nil
+}
+
+// groupAuthType 变更用户组权限时的auth type, 优先使用导入数据中的值, 并保证包含本次变更需要的类型
+func (c *permissionDataController) groupAuthType(
+	im *permissionImport, groupID string, groupPK, required int64,
+) (int64, error) {
+	authType, ok := im.groupAuthTypes[groupID]
+	if !ok {
+		groupAuthTypes, err := c.groupService.ListGroupAuthBySystemGroupPKs(im.systemID, []int64{groupPK})
+		if err != nil {
+			return 0, err
+		}
+
+		authType = svctypes.AuthTypeNone
+		if len(groupAuthTypes) > 0 {
+			authType = groupAuthTypes[0].AuthType
+		}
+	}
+
+	switch authType {
+	case required, svctypes.AuthTypeAll:
+		return authType, nil
+	case svctypes.AuthTypeNone:
+		return required, nil
+	}
+	return svctypes.AuthTypeAll, nil
+}
+
+func (c *permissionDataController) importPolicies(im *permissionImport, records []indexedRecord) error {
+	batches := newRecordBatches()
+	for _, r := range records {
+		if _, ok := im.actionPKMap[r.record.ActionID]; !ok {
+			im.conflict(r, fmt.Sprintf("action `%s` not exists", r.record.ActionID))
+			continue
+		}
+		if r.record.TemplateID != 0 && r.record.Subject.Type != svctypes.GroupType {
+			im.conflict(r, "template policy is only supported for group")
+			continue
+		}
+		key := fmt.Sprintf("%s:%s:%d", r.record.Subject.Type, r.record.Subject.ID, r.record.TemplateID)
+		batches.add(key, r)
+	}
+
+	for _, key := range batches.keys {
+		err := c.importSubjectPolicies(im, batches.records[key])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// actionEffectKey 同一subject同一模板下策略的唯一标识
+type actionEffectKey struct {
+	actionPK int64
+	effect   int64
+}
+
+// importSubjectPolicies 导入同一subject同一模板下的策略, 不存在则创建, 不一致则更新
+func (c *permissionDataController) importSubjectPolicies(im *permissionImport, records []indexedRecord) error {
+	subject := records[0].record.Subject
+	templateID := records[0].record.TemplateID
+
+	subjectPK, found, err := c.getSubjectPKForImport(im, subject, records)
+	if err != nil || !found {
+		return err
+	}
+
+	actionPKs := make([]int64, 0, len(records))
+	for _, r := range records {
+		actionPKs = append(actionPKs, im.actionPKMap[r.record.ActionID])
+	}
+	thinPolicies, err := c.policyService.ListThinBySubjectActionTemplate(subjectPK, actionPKs, templateID)
+	if err != nil {
+		return err
+	}
+	// 同一操作下允许同时存在allow与deny策略, 按(操作, effect)区分
+	existingPolicies := make(map[actionEffectKey]svctypes.Policy, len(thinPolicies))
+	if len(thinPolicies) > 0 {
+		policyIDs := make([]int64, 0, len(thinPolicies))
+		for _, p := range thinPolicies {
+			policyIDs = append(policyIDs, p.ID)
+		}
+		policies, err := c.policyService.ListBySubjectPKAndPKs(subjectPK, policyIDs)
+		if err != nil {
+			return err
+		}
+		for _, p := range policies {
+			existingPolicies[actionEffectKey{actionPK: p.ActionPK, effect: p.Effect}] = p
+		}
+	}
+
+	createPolicies := make([]types.Policy, 0, len(records))
+	updatePolicies := make([]types.Policy, 0, len(records))
+	changedRecords := make([]indexedRecord, 0, len(records))
+	keySet := make(map[actionEffectKey]struct{}, len(records))
+	for _, r := range records {
+		effect := svctypes.ConvertToPolicyEffectInt(r.record.Effect)
+		key := actionEffectKey{actionPK: im.actionPKMap[r.record.ActionID], effect: effect}
+		if _, ok := keySet[key]; ok {
+			im.conflict(r, fmt.Sprintf(
+				"duplicated `%s` policy of action `%s`", svctypes.ConvertToPolicyEffectStr(effect), r.record.ActionID,
+			))
+			continue
+		}
+		keySet[key] = struct{}{}
+
+		effectiveAt := normalizeEffectiveAt(r.record.EffectiveAt)
+		old, found := existingPolicies[key]
+		if found && old.Expression == r.record.Expression && old.ExpiredAt == r.record.ExpiredAt &&
+			normalizeEffectiveAt(old.EffectiveAt) == effectiveAt {
+			im.report.Skipped++
+			continue
+		}
+
+		policy := types.Policy{
+			Version: service.PolicyVersion,
+			System:  im.systemID,
+			Subject: types.Subject{Type: subject.Type, ID: subject.ID},
+			Action: types.Action{
+				ID:        r.record.ActionID,
+				Attribute: types.NewActionAttribute(),
+			},
+			Expression:  r.record.Expression,
+			ExpiredAt:   r.record.ExpiredAt,
+			TemplateID:  templateID,
+			Effect:      effect,
+			EffectiveAt: effectiveAt,
+		}
+		if found {
+			policy.ID = old.ID
+			updatePolicies = append(updatePolicies, policy)
+		} else {
+			createPolicies = append(createPolicies, policy)
+		}
+		changedRecords = append(changedRecords, r)
+	}
+
+	if len(changedRecords) == 0 {
+		return nil
+	}
+
+	if subject.Type == svctypes.GroupType {
+		authType, err := c.groupAuthType(im, subject.ID, subjectPK, svctypes.AuthTypeABAC)
+		if err != nil {
+			return err
+		}
+		err = c.policyController.AlterGroupPolicies(
+			im.systemID, subject.Type, subject.ID, templateID,
+			createPolicies, updatePolicies, nil, nil, authType, im.operator,
+		)
+	} else {
+		err = c.policyController.AlterCustomPolicies(
+			im.systemID, subject.Type, subject.ID, createPolicies, updatePolicies, nil, im.operator,
+		)
+	}
+	if err != nil {
+		im.conflictAll(changedRecords, err.Error())
+		return nil
+	}
+	im.report.Applied += len(changedRecords)
+	return nil
+}
+
+// importRBACPolicies 导入RBAC授权, 授权按操作集合合并, 重复导入不会产生变更
+func (c *permissionDataController) importRBACPolicies(im *permissionImport, records []indexedRecord) error {
+	batches := newRecordBatches()
+	for _, r := range records {
+		if r.record.Subject.Type != svctypes.GroupType {
+			im.conflict(r, "subject should be a group")
+			continue
+		}
+		if len(r.record.ActionIDs) == 0 || len(r.record.Resources) == 0 {
+			im.conflict(r, "action_ids and resources are required")
+			continue
+		}
+		if actionID := missingAction(im.actionPKMap, r.record.ActionIDs); actionID != "" {
+			im.conflict(r, fmt.Sprintf("action `%s` not exists", actionID))
+			continue
+		}
+		batches.add(fmt.Sprintf("%s:%d", r.record.Subject.ID, r.record.TemplateID), r)
+	}
+
+	for _, key := range batches.keys {
+		rs := batches.records[key]
+		group := rs[0].record.Subject
+		templateID := rs[0].record.TemplateID
+
+		groupPK, found, err := c.getSubjectPKForImport(im, group, rs)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		resourceChangedActions := make([]types.ResourceChangedAction, 0, len(rs))
+		for _, r := range rs {
+			nodes := make([]types.ThinResourceNode, 0, len(r.record.Resources))
+			for _, resource := range r.record.Resources {
+				nodes = append(nodes, types.ThinResourceNode{
+					System: resource.System,
+					Type:   resource.Type,
+					ID:     resource.ID,
+				})
+			}
+			resourceChangedActions = append(resourceChangedActions, types.ResourceChangedAction{
+				Resource:         nodes[0],
+				CreatedActionIDs: r.record.ActionIDs,
+				DeletedActionIDs: []string{},
+				RelatedResources: nodes[1:],
+			})
+		}
+
+		authType, err := c.groupAuthType(im, group.ID, groupPK, svctypes.AuthTypeRBAC)
+		if err != nil {
+			return err
+		}
+		err = c.policyController.AlterGroupPolicies(
+			im.systemID, group.Type, group.ID, templateID,
+			nil, nil, nil, resourceChangedActions, authType, im.operator,
+		)
+		if err != nil {
+			im.conflictAll(rs, err.Error())
+			continue
+		}
+		im.report.Applied += len(rs)
+	}
+	return nil
+}
+
+// missingAction 返回第一个不存在的操作, 全部存在时返回空字符串
+func missingAction(actionPKMap map[string]int64, actionIDs []string) string {
+	for _, actionID := range actionIDs {
+		if _, ok := actionPKMap[actionID]; !ok {
+			return actionID
+		}
+	}
+	return ""
+}
+
+// importTemporaryPolicies 导入未过期的临时权限, 已存在相同表达式及过期时间的临时权限则跳过
+func (c *permissionDataController) importTemporaryPolicies(im *permissionImport, records []indexedRecord) error {
+	batches := newRecordBatches()
+	for _, r := range records {
+		if _, ok := im.actionPKMap[r.record.ActionID]; !ok {
+			im.conflict(r, fmt.Sprintf("action `%s` not exists", r.record.ActionID))
+			continue
+		}
+		if r.record.ExpiredAt <= im.now {
+			im.report.Skipped++
+			continue
+		}
+		batches.add(r.record.Subject.Type+":"+r.record.Subject.ID, r)
+	}
+
+	for _, key := range batches.keys {
+		rs := batches.records[key]
+		subject := rs[0].record.Subject
+
+		subjectPK, found, err := c.getSubjectPKForImport(im, subject, rs)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		policies := make([]types.Policy, 0, len(rs))
+		changedRecords := make([]indexedRecord, 0, len(rs))
+		for _, r := range rs {
+			exists, err := c.temporaryPolicyExists(subjectPK, im.actionPKMap[r.record.ActionID], r.record)
+			if err != nil {
+				return err
+			}
+			if exists {
+				im.report.Skipped++
+				continue
+			}
+
+			policies = append(policies, types.Policy{
+				Version: service.PolicyVersion,
+				System:  im.systemID,
+				Subject: types.Subject{Type: subject.Type, ID: subject.ID},
+				Action: types.Action{
+					ID:        r.record.ActionID,
+					Attribute: types.NewActionAttribute(),
+				},
+				Expression: r.record.Expression,
+				ExpiredAt:  r.record.ExpiredAt,
+			})
+			changedRecords = append(changedRecords, r)
+		}
+
+		if len(policies) == 0 {
+			continue
+		}
+		_, err = c.policyController.CreateTemporaryPolicies(im.systemID, subject.Type, subject.ID, policies)
+		if err != nil {
+			im.conflictAll(changedRecords, err.Error())
+			continue
+		}
+		im.report.Applied += len(changedRecords)
+	}
+	return nil
+}
+
+func (c *permissionDataController) temporaryPolicyExists(
+	subjectPK, actionPK int64, record PermissionRecord,
+) (bool, error) {
+	thinPolicies, err := c.temporaryPolicyService.ListThinBySubjectAction(subjectPK, actionPK)
+	if err != nil {
+		return false, err
+	}
+	if len(thinPolicies) == 0 {
+		return false, nil
+	}
+
+	pks := make([]int64, 0, len(thinPolicies))
+	for _, p := range thinPolicies {
+		pks = append(pks, p.PK)
+	}
+	policies, err := c.temporaryPolicyService.ListByPKs(pks)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range policies {
+		if p.Expression == record.Expression && p.ExpiredAt == record.ExpiredAt {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,376 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/types"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service/mock"
+	svctypes "iam/pkg/service/types"
+)
+
+type fakeImportSubjectController struct {
+	SubjectController
+	created []Subject
+}
+
+func (c *fakeImportSubjectController) BulkCreate(subjects []Subject) error {
+	c.created = append(c.created, subjects...)
+	return nil
+}
+
+type fakeImportGroupController struct {
+	GroupController
+	members map[string][]GroupMember
+}
+
+func (c *fakeImportGroupController) CreateOrUpdateGroupMembers(
+	_type, id string, members []GroupMember, actor string,
+) (map[string]int64, error) {
+	for _, m := range members {
+		if m.ID == "missing" {
+			return nil, errors.New("subject not exists")
+		}
+	}
+	c.members[id] = append(c.members[id], members...)
+	return map[string]int64{}, nil
+}
+
+type fakeImportPolicyController struct {
+	PolicyController
+
+	customCreated, customUpdated []types.Policy
+	groupUpdated                 []types.Policy
+	groupAuthTypes               []int64
+	resourceChangedActions       []types.ResourceChangedAction
+	temporaryCreated             []types.Policy
+}
+
+func (c *fakeImportPolicyController) AlterCustomPolicies(
+	system, subjectType, subjectID string,
+	createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
+	operator Operator,
+) error {
+	c.customCreated = append(c.customCreated, createPolicies...)
+	c.customUpdated = append(c.customUpdated, updatePolicies...)
+	return nil
+}
+
+func (c *fakeImportPolicyController) AlterGroupPolicies(
+	systemID, subjectType, subjectID string, templateID int64,
+	createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
+	resourceChangedActions []types.ResourceChangedAction,
+	groupAuthType int64,
+	operator Operator,
+) error {
+	c.groupUpdated = append(c.groupUpdated, updatePolicies...)
+	c.resourceChangedActions = append(c.resourceChangedActions, resourceChangedActions...)
+	c.groupAuthTypes = append(c.groupAuthTypes, groupAuthType)
+	return nil
+}
+
+func (c *fakeImportPolicyController) CreateTemporaryPolicies(
+	system, subjectType, subjectID string, policies []types.Policy,
+) ([]int64, error) {
+	c.temporaryCreated = append(c.temporaryCreated, policies...)
+	return []int64{1}, nil
+}
+
+var _ = Describe("PermissionDataController", func() {
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+	var mockActionService *mock.MockActionService
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockActionService = mock.NewMockActionService(ctl)
+		mockActionService.EXPECT().ListThinActionBySystem("test").Return([]svctypes.ThinAction{
+			{PK: 1, System: "test", ID: "view"},
+			{PK: 2, System: "test", ID: "edit"},
+		}, nil)
+
+		patches = gomonkey.ApplyFunc(cacheimpls.GetSubjectByPK, func(pk int64) (svctypes.Subject, error) {
+			switch pk {
+			case 10:
+				return svctypes.Subject{PK: 10, Type: "user", ID: "admin", Name: "admin"}, nil
+			case 20:
+				return svctypes.Subject{PK: 20, Type: "group", ID: "1", Name: "g1"}, nil
+			}
+			return svctypes.Subject{}, sql.ErrNoRows
+		})
+	})
+	AfterEach(func() {
+		ctl.Finish()
+		patches.Reset()
+	})
+
+	Describe("Export", func() {
+		It("ok", func() {
+			patches.ApplyFunc(cacheimpls.GetThinResourceType, func(pk int64) (svctypes.ThinResourceType, error) {
+				return svctypes.ThinResourceType{PK: pk, System: "test", ID: "host"}, nil
+			})
+
+			mockOpenAbacPolicyService := mock.NewMockOpenAbacPolicyService(ctl)
+			mockOpenAbacPolicyService.EXPECT().ListAllPagingByActionBeforeExpiredAt(
+				int64(1), gomock.Any(), int64(0), exportPageSize,
+			).Return([]svctypes.OpenAbacPolicy{
+				{PK: 1, SubjectPK: 10, ActionPK: 1, ExpressionPK: 100, ExpiredAt: 4102444800, EffectiveAt: 4102444000},
+				// 已删除的subject
+				{PK: 2, SubjectPK: 99, ActionPK: 1, ExpressionPK: 100, ExpiredAt: 4102444800},
+			}, nil)
+			mockOpenAbacPolicyService.EXPECT().ListAllPagingByActionBeforeExpiredAt(
+				int64(2), gomock.Any(), int64(0), exportPageSize,
+			).Return([]svctypes.OpenAbacPolicy{}, nil)
+
+			mockPolicyService := mock.NewMockPolicyService(ctl)
+			mockPolicyService.EXPECT().ListExpressionByPKs([]int64{100, 100}).Return([]svctypes.AuthExpression{
+				{PK: 100, Expression: "[]"},
+			}, nil)
+
+			mockGroupResourcePolicyService := mock.NewMockGroupResourcePolicyService(ctl)
+			mockGroupResourcePolicyService.EXPECT().ListPagingBySystem("test", int64(0), exportPageSize).Return(
+				[]svctypes.GroupResourcePolicy{{
+					PK:             5,
+					GroupPK:        20,
+					ActionPKs:      []int64{2, 3},
+					ResourceTypePK: 1,
+					ResourceID:     "h1",
+				}}, nil,
+			)
+
+			mockTemporaryPolicyService := mock.NewMockTemporaryPolicyService(ctl)
+			mockTemporaryPolicyService.EXPECT().ListByActionPKsBeforeExpiredAt(
+				[]int64{1, 2}, gomock.Any(),
+			).Return([]svctypes.TemporaryPolicy{
+				{PK: 1, SubjectPK: 10, ActionPK: 2, Expression: "[]", ExpiredAt: 4102444800},
+			}, nil)
+
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListGroupAuthBySystemGroupPKs("test", []int64{20}).Return(
+				[]svctypes.GroupAuthType{{GroupPK: 20, AuthType: svctypes.AuthTypeRBAC}}, nil,
+			)
+			mockGroupService.EXPECT().ListGroupMember(int64(20)).Return([]svctypes.GroupMember{
+				{SubjectPK: 10, ExpiredAt: 4102444800, EffectiveAt: 4102444000},
+				// 已过期的成员
+				{SubjectPK: 10, ExpiredAt: 1},
+			}, nil)
+
+			c := &permissionDataController{
+				actionService:              mockActionService,
+				groupService:               mockGroupService,
+				policyService:              mockPolicyService,
+				openAbacPolicyService:      mockOpenAbacPolicyService,
+				temporaryPolicyService:     mockTemporaryPolicyService,
+				groupResourcePolicyService: mockGroupResourcePolicyService,
+			}
+
+			records := []PermissionRecord{}
+			err := c.Export("test", func(record PermissionRecord) error {
+				records = append(records, record)
+				return nil
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []PermissionRecord{
+				{
+					Kind:        PermissionRecordKindPolicy,
+					Subject:     Subject{Type: "user", ID: "admin", Name: "admin"},
+					ActionID:    "view",
+					Expression:  "[]",
+					Effect:      "allow",
+					ExpiredAt:   4102444800,
+					EffectiveAt: 4102444000,
+				},
+				{
+					Kind:      PermissionRecordKindRBACPolicy,
+					Subject:   Subject{Type: "group", ID: "1", Name: "g1"},
+					ActionIDs: []string{"edit"},
+					Resources: []PermissionResource{{System: "test", Type: "host", ID: "h1"}},
+				},
+				{
+					Kind:       PermissionRecordKindTemporaryPolicy,
+					Subject:    Subject{Type: "user", ID: "admin", Name: "admin"},
+					ActionID:   "edit",
+					Expression: "[]",
+					ExpiredAt:  4102444800,
+				},
+				{
+					Kind:     PermissionRecordKindGroup,
+					Subject:  Subject{Type: "group", ID: "1", Name: "g1"},
+					AuthType: "rbac",
+				},
+				{
+					Kind:        PermissionRecordKindGroupMember,
+					Subject:     Subject{Type: "group", ID: "1"},
+					Member:      &Subject{Type: "user", ID: "admin"},
+					ExpiredAt:   4102444800,
+					EffectiveAt: 4102444000,
+				},
+			}, records)
+		})
+
+		It("write fail", func() {
+			mockOpenAbacPolicyService := mock.NewMockOpenAbacPolicyService(ctl)
+			mockOpenAbacPolicyService.EXPECT().ListAllPagingByActionBeforeExpiredAt(
+				int64(1), gomock.Any(), int64(0), exportPageSize,
+			).Return([]svctypes.OpenAbacPolicy{{PK: 1, SubjectPK: 10, ActionPK: 1, ExpressionPK: 100}}, nil)
+
+			mockPolicyService := mock.NewMockPolicyService(ctl)
+			mockPolicyService.EXPECT().ListExpressionByPKs([]int64{100}).Return([]svctypes.AuthExpression{}, nil)
+
+			c := &permissionDataController{
+				actionService:         mockActionService,
+				policyService:         mockPolicyService,
+				openAbacPolicyService: mockOpenAbacPolicyService,
+			}
+
+			err := c.Export("test", func(record PermissionRecord) error {
+				return errors.New("broken pipe")
+			})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "broken pipe")
+		})
+	})
+
+	Describe("Import", func() {
+		It("ok", func() {
+			expiredAt := time.Now().Unix() + 3600
+			effectiveAt := time.Now().Unix() + 1800
+
+			mockSubjectService := mock.NewMockSubjectService(ctl)
+			mockSubjectService.EXPECT().GetPK("group", "1").Return(int64(20), nil).AnyTimes()
+			mockSubjectService.EXPECT().GetPK("group", "2").Return(int64(0), sql.ErrNoRows)
+			mockSubjectService.EXPECT().GetPK("user", "admin").Return(int64(10), nil).Times(2)
+			mockSubjectService.EXPECT().GetPK("user", "nobody").Return(int64(0), sql.ErrNoRows)
+
+			mockPolicyService := mock.NewMockPolicyService(ctl)
+			mockPolicyService.EXPECT().ListThinBySubjectActionTemplate(int64(10), []int64{1, 2, 1}, int64(0)).Return(
+				[]svctypes.ThinPolicy{{ID: 1, ActionPK: 1}}, nil,
+			)
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(10), []int64{1}).Return([]svctypes.Policy{
+				{ID: 1, ActionPK: 1, Expression: "[]", ExpiredAt: expiredAt},
+			}, nil)
+			mockPolicyService.EXPECT().ListThinBySubjectActionTemplate(int64(20), []int64{1}, int64(3)).Return(
+				[]svctypes.ThinPolicy{{ID: 2, ActionPK: 1}}, nil,
+			)
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(20), []int64{2}).Return([]svctypes.Policy{
+				{ID: 2, ActionPK: 1, Expression: "[]", ExpiredAt: 1},
+			}, nil)
+
+			mockTemporaryPolicyService := mock.NewMockTemporaryPolicyService(ctl)
+			mockTemporaryPolicyService.EXPECT().ListThinBySubjectAction(int64(10), int64(1)).Return(
+				[]svctypes.ThinTemporaryPolicy{}, nil,
+			)
+
+			subjectCtl := &fakeImportSubjectController{}
+			groupCtl := &fakeImportGroupController{members: map[string][]GroupMember{}}
+			policyCtl := &fakeImportPolicyController{}
+			c := &permissionDataController{
+				subjectService:         mockSubjectService,
+				actionService:          mockActionService,
+				policyService:          mockPolicyService,
+				temporaryPolicyService: mockTemporaryPolicyService,
+
+				subjectController: subjectCtl,
+				groupController:   groupCtl,
+				policyController:  policyCtl,
+			}
+
+			group1 := Subject{Type: "group", ID: "1"}
+			admin := Subject{Type: "user", ID: "admin"}
+			report, err := c.Import("test", []PermissionRecord{
+				{Kind: PermissionRecordKindPolicy, Subject: admin, ActionID: "view", Expression: "[]", ExpiredAt: expiredAt},
+				{Kind: PermissionRecordKindPolicy, Subject: admin, ActionID: "edit", Expression: "[]", ExpiredAt: expiredAt},
+				{Kind: PermissionRecordKindPolicy, Subject: admin, ActionID: "delete", ExpiredAt: expiredAt},
+				{Kind: PermissionRecordKindPolicy, Subject: Subject{Type: "user", ID: "nobody"}, ActionID: "view"},
+				{
+					Kind: PermissionRecordKindPolicy, Subject: group1, ActionID: "view", TemplateID: 3,
+					Expression: "[]", ExpiredAt: expiredAt,
+				},
+				{
+					Kind: PermissionRecordKindRBACPolicy, Subject: group1, ActionIDs: []string{"edit"},
+					Resources: []PermissionResource{{System: "test", Type: "host", ID: "h1"}},
+				},
+				{
+					Kind: PermissionRecordKindGroupMember, Subject: group1, Member: &admin,
+					ExpiredAt: expiredAt, EffectiveAt: effectiveAt,
+				},
+				{
+					Kind: PermissionRecordKindGroupMember, Subject: group1,
+					Member: &Subject{Type: "user", ID: "missing"}, ExpiredAt: expiredAt,
+				},
+				{Kind: PermissionRecordKindGroupMember, Subject: group1, Member: &admin, ExpiredAt: 1},
+				{Kind: PermissionRecordKindGroup, Subject: group1, AuthType: "abac"},
+				{Kind: PermissionRecordKindGroup, Subject: Subject{Type: "group", ID: "2", Name: "g2"}},
+				{
+					Kind: PermissionRecordKindTemporaryPolicy, Subject: admin, ActionID: "view",
+					Expression: "[]", ExpiredAt: expiredAt,
+				},
+				{Kind: "unknown"},
+				// 同一操作的deny策略, 不影响已存在的allow策略
+				{
+					Kind: PermissionRecordKindPolicy, Subject: admin, ActionID: "view", Effect: "deny",
+					Expression: "[]", ExpiredAt: expiredAt, EffectiveAt: effectiveAt,
+				},
+			}, Operator{Actor: "admin"})
+			assert.NoError(GinkgoT(), err)
+
+			assert.Equal(GinkgoT(), 14, report.Total)
+			// group 2, admin member, edit policy, deny view policy, group template policy, rbac policy, temporary policy
+			assert.Equal(GinkgoT(), 7, report.Applied)
+			// group 1, expired member, same view policy
+			assert.Equal(GinkgoT(), 3, report.Skipped)
+			assert.Equal(GinkgoT(), []PermissionImportConflict{
+				{Line: 13, Kind: "unknown", Reason: "unknown kind `unknown`"},
+				{
+					Line: 8, Kind: PermissionRecordKindGroupMember, Subject: group1,
+					Reason: "subject not exists",
+				},
+				{Line: 3, Kind: PermissionRecordKindPolicy, Subject: admin, Reason: "action `delete` not exists"},
+				{
+					Line: 4, Kind: PermissionRecordKindPolicy, Subject: Subject{Type: "user", ID: "nobody"},
+					Reason: "subject `user:nobody` not exists",
+				},
+			}, report.Conflicts)
+
+			assert.Equal(GinkgoT(), []Subject{{Type: "group", ID: "2", Name: "g2"}}, subjectCtl.created)
+			assert.Equal(GinkgoT(), []GroupMember{
+				{Type: "user", ID: "admin", ExpiredAt: expiredAt, EffectiveAt: effectiveAt},
+			}, groupCtl.members["1"])
+
+			assert.Len(GinkgoT(), policyCtl.customCreated, 2)
+			assert.Equal(GinkgoT(), "edit", policyCtl.customCreated[0].Action.ID)
+			assert.Equal(GinkgoT(), "view", policyCtl.customCreated[1].Action.ID)
+			assert.Equal(GinkgoT(), svctypes.PolicyEffectDeny, policyCtl.customCreated[1].Effect)
+			assert.Equal(GinkgoT(), effectiveAt, policyCtl.customCreated[1].EffectiveAt)
+			assert.Len(GinkgoT(), policyCtl.customUpdated, 0)
+			assert.Len(GinkgoT(), policyCtl.groupUpdated, 1)
+			assert.Equal(GinkgoT(), int64(2), policyCtl.groupUpdated[0].ID)
+			// 导入数据中用户组auth type为abac, 导入RBAC授权时需要合并为all
+			assert.Equal(GinkgoT(), []int64{svctypes.AuthTypeABAC, svctypes.AuthTypeAll}, policyCtl.groupAuthTypes)
+			assert.Equal(GinkgoT(), []types.ResourceChangedAction{{
+				Resource:         types.ThinResourceNode{System: "test", Type: "host", ID: "h1"},
+				CreatedActionIDs: []string{"edit"},
+				DeletedActionIDs: []string{},
+				RelatedResources: []types.ThinResourceNode{},
+			}}, policyCtl.resourceChangedActions)
+			assert.Len(GinkgoT(), policyCtl.temporaryCreated, 1)
+		})
+	})
+})
@@ -34,9 +34,17 @@ type PolicyController interface {
 
 	AlterCustomPolicies(
 		system, subjectType, subjectID string,
-		createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64) error
+		createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
+		operator Operator) error
+	DryRunAlterCustomPolicies(
+		system, subjectType, subjectID string,
+		createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
+		operator Operator) (PoliciesDryRunResult, error)
 
-	DeleteByIDs(system string, subjectType, subjectID string, policyIDs []int64) error
+	DeleteByIDs(system string, subjectType, subjectID string, policyIDs []int64, operator Operator) error
+	DryRunDeleteByIDs(
+		system string, subjectType, subjectID string, policyIDs []int64, operator Operator,
+	) (PoliciesDryRunResult, error)
 
 	// temporary policy
 
@@ -53,9 +61,30 @@ type PolicyController interface {
 		createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
 		resourceChangedActions []types.ResourceChangedAction,
 		groupAuthType int64,
+		operator Operator,
 	) (err error)
+	DryRunAlterGroupPolicies(
+		systemID, subjectType, subjectID string, templateID int64,
+		createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
+		resourceChangedActions []types.ResourceChangedAction,
+		groupAuthType int64,
+		operator Operator,
+	) (PoliciesDryRunResult, error)
 
 	DeleteByActionID(system, actionID string) error
+
+	// policy history
+
+	GetPolicyHistoryCount(system, subjectType, subjectID string) (int64, error)
+	ListPagingPolicyHistory(system, subjectType, subjectID string, limit, offset int64) ([]PolicyHistory, error)
+	DiffPolicyHistory(system, subjectType, subjectID string, fromVersion, toVersion int64) ([]PolicyChange, error)
+	RollbackPolicies(system, subjectType, subjectID string, version int64, operator Operator) error
+
+	// policy redundancy
+
+	AnalyzePolicyRedundancy(
+		system, subjectType, subjectID string, cleanup bool, operator Operator,
+	) (PolicyRedundancyReport, error)
 }
 
 type policyController struct {
@@ -74,6 +103,9 @@ type policyController struct {
 	subjectActionGroupResourceService service.SubjectActionGroupResourceService
 	subjectActionExpressionService    service.SubjectActionExpressionService
 
+	sodConstraintService service.SoDConstraintService
+	policyHistoryService service.PolicyHistoryService
+
 	eventProducer event.PolicyEventProducer
 }
 
@@ -92,6 +124,9 @@ func NewPolicyController() PolicyController {
 		subjectActionGroupResourceService: service.NewSubjectActionGroupResourceService(),
 		subjectActionExpressionService:    service.NewSubjectActionExpressionService(),
 
+		sodConstraintService: service.NewSoDConstraintService(),
+		policyHistoryService: service.NewPolicyHistoryService(),
+
 		eventProducer: event.NewPolicyEventProducer(),
 	}
 }
@@ -20,6 +20,8 @@ import (
 	"iam/pkg/abac/prp/policy"
 	"iam/pkg/abac/prp/temporary"
 	"iam/pkg/abac/types"
+	"iam/pkg/database"
+	"iam/pkg/service"
 	svctypes "iam/pkg/service/types"
 )
 
@@ -43,13 +45,15 @@ func convertToServicePolicies(
 			return nil, err
 		}
 		svcPolicies = append(svcPolicies, svctypes.Policy{
-			Version:    p.Version,
-			ID:         p.ID,
-			SubjectPK:  subjectPK,
-			ActionPK:   actionPK,
-			Expression: p.Expression,
-			ExpiredAt:  p.ExpiredAt,
-			TemplateID: p.TemplateID,
+			Version:     p.Version,
+			ID:          p.ID,
+			SubjectPK:   subjectPK,
+			ActionPK:    actionPK,
+			Expression:  p.Expression,
+			ExpiredAt:   p.ExpiredAt,
+			TemplateID:  p.TemplateID,
+			Effect:      p.Effect,
+			EffectiveAt: p.EffectiveAt,
 		})
 	}
 	return svcPolicies, nil
@@ -116,7 +120,9 @@ func (c *policyController) querySubjectActionForAlterPolicies(
 }
 
 // DeleteByIDs 通过IDs批量删除策略
-func (c *policyController) DeleteByIDs(system string, subjectType, subjectID string, policyIDs []int64) error {
+func (c *policyController) DeleteByIDs(
+	system string, subjectType, subjectID string, policyIDs []int64, operator Operator,
+) error {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "DeletePoliciesByIDs")
 
 	// 1. 查询 subject pk
@@ -128,28 +134,169 @@ func (c *policyController) DeleteByIDs(system string, subjectType, subjectID str
 	}
 	// 判断policyIDs是否为空，避免执行无效SQL
 	if len(policyIDs) > 0 {
+		// 2. 查询变更前的策略, 用于记录变更历史
+		beforePolicies, err := c.listPoliciesBeforeAlter(pk, nil, policyIDs)
+		if err != nil {
+			return errorWrapf(err, "c.listPoliciesBeforeAlter pk=`%d`, policyIDs=`%+v` fail", pk, policyIDs)
+		}
+
+		tx, err := database.GenerateDefaultDBTx()
+		if err != nil {
+			return errorWrapf(err, "define tx fail")
+		}
+		defer database.RollBackWithLog(tx)
+
 		// NOTE: delete cache here => 可以查actionPK
 		defer policy.DeleteSystemSubjectPKsFromCache(system, []int64{pk})
 
-		err := c.policyService.DeleteByPKs(pk, policyIDs)
+		err = c.policyService.DeleteByPKsWithTx(tx, pk, policyIDs)
 		if err != nil {
-			err = errorWrapf(err, "policyService.DeleteByPKs pk=`%d`, policyIDs=`%+v` fail",
+			err = errorWrapf(err, "policyService.DeleteByPKsWithTx pk=`%d`, policyIDs=`%+v` fail",
 				pk, policyIDs)
 			return err
 		}
+
+		// 3. 记录变更历史
+		histories := newPolicyHistories(
+			pk, system, service.PolicyTemplateIDCustom, nil, nil, beforePolicies, policyIDs, nil, operator,
+		)
+		err = c.policyHistoryService.BulkCreateWithTx(tx, histories)
+		if err != nil {
+			return errorWrapf(err, "policyHistoryService.BulkCreateWithTx histories=`%+v` fail", histories)
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return errorWrapf(err, "tx commit fail")
+		}
+
 		c.eventProducer.PublishABACDeletePolicyEvent(policyIDs)
 	}
 	return nil
 }
 
+// DryRunDeleteByIDs 预演删除策略, 只查询将被删除的策略, 不写DB
+func (c *policyController) DryRunDeleteByIDs(
+	system string, subjectType, subjectID string, policyIDs []int64, operator Operator,
+) (result PoliciesDryRunResult, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "DryRunDeleteByIDs")
+
+	pk, actionIDMap, err := c.querySubjectActionIDMap(system, subjectType, subjectID)
+	if err != nil {
+		return result, errorWrapf(err, "c.querySubjectActionIDMap system=`%s` fail", system)
+	}
+
+	var histories []svctypes.PolicyHistory
+	if len(policyIDs) > 0 {
+		beforePolicies, err := c.listPoliciesBeforeAlter(pk, nil, policyIDs)
+		if err != nil {
+			return result, errorWrapf(err, "c.listPoliciesBeforeAlter pk=`%d`, policyIDs=`%+v` fail", pk, policyIDs)
+		}
+
+		histories = newPolicyHistories(
+			pk, system, service.PolicyTemplateIDCustom, nil, nil, beforePolicies, policyIDs, nil, operator,
+		)
+	}
+
+	return c.newCustomPoliciesDryRunResult(subjectType, pk, actionIDMap, histories)
+}
+
 // AlterCustomPolicies alter subject custom policies
 func (c *policyController) AlterCustomPolicies(
 	system, subjectType, subjectID string,
 	createPolicies, updatePolicies []types.Policy,
 	deletePolicyIDs []int64,
+	operator Operator,
 ) (err error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "AlterPolicies")
 
+	// 1. 校验并计算变更内容
+	plan, err := c.planAlterCustomPolicies(
+		system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs, operator,
+	)
+	if err != nil {
+		return
+	}
+	subjectPK := plan.subjectPK
+
+	tx, err := database.GenerateDefaultDBTx()
+	defer database.RollBackWithLog(tx)
+	if err != nil {
+		err = errorWrapf(err, "define tx fail")
+		return
+	}
+
+	// NOTE: delete the policy cache before leave => 可以查actionPK
+	defer policy.DeleteSystemSubjectPKsFromCache(system, []int64{subjectPK})
+
+	// 2. service执行 create, update, delete
+	updatedActionPKExpressionPKs, err := c.policyService.AlterCustomPoliciesWithTx(
+		tx, subjectPK, plan.createPolicies, plan.updatePolicies, deletePolicyIDs, plan.actionPKWithResourceTypeSet)
+	if err != nil {
+		err = errorWrapf(err, "policyService.AlterPolicies system=`%s`, subjectPK=`%d` fail", system, subjectPK)
+		return
+	}
+
+	// 3. 记录变更历史
+	err = c.policyHistoryService.BulkCreateWithTx(tx, plan.histories)
+	if err != nil {
+		err = errorWrapf(err, "policyHistoryService.BulkCreateWithTx histories=`%+v` fail", plan.histories)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = errorWrapf(err, "tx commit fail")
+		return
+	}
+
+	defer expression.BatchDeleteExpressionsFromCache(updatedActionPKExpressionPKs)
+	// NOTE: publish policy delete pk event
+	c.eventProducer.PublishABACDeletePolicyEvent(deletePolicyIDs)
+
+	return nil
+}
+
+// DryRunAlterCustomPolicies 预演自定义权限变更, 只执行校验并计算变更内容, 不写DB
+func (c *policyController) DryRunAlterCustomPolicies(
+	system, subjectType, subjectID string,
+	createPolicies, updatePolicies []types.Policy,
+	deletePolicyIDs []int64,
+	operator Operator,
+) (result PoliciesDryRunResult, err error) {
+	plan, err := c.planAlterCustomPolicies(
+		system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs, operator,
+	)
+	if err != nil {
+		return
+	}
+
+	actionIDMap := make(map[int64]string, len(plan.actionPKMap))
+	for actionID, actionPK := range plan.actionPKMap {
+		actionIDMap[actionPK] = actionID
+	}
+	return c.newCustomPoliciesDryRunResult(subjectType, plan.subjectPK, actionIDMap, plan.histories)
+}
+
+// customPoliciesAlterPlan 校验通过后计算出的自定义权限变更内容
+type customPoliciesAlterPlan struct {
+	subjectPK                   int64
+	actionPKMap                 map[string]int64
+	actionPKWithResourceTypeSet *set.Int64Set
+	createPolicies              []svctypes.Policy
+	updatePolicies              []svctypes.Policy
+	histories                   []svctypes.PolicyHistory
+}
+
+// planAlterCustomPolicies 执行所有的校验, 计算出需要变更的策略与变更记录
+func (c *policyController) planAlterCustomPolicies(
+	system, subjectType, subjectID string,
+	createPolicies, updatePolicies []types.Policy,
+	deletePolicyIDs []int64,
+	operator Operator,
+) (plan customPoliciesAlterPlan, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "planAlterCustomPolicies")
+
 	// 1. 查询subject action 相关的信息
 	subjectPK, actionPKMap, actionPKWithResourceTypeSet, err := c.querySubjectActionForAlterPolicies(
 		system, subjectType, subjectID)
@@ -182,22 +329,40 @@ func (c *policyController) AlterCustomPolicies(
 		return
 	}
 
-	// NOTE: delete the policy cache before leave => 可以查actionPK
-	defer policy.DeleteSystemSubjectPKsFromCache(system, []int64{subjectPK})
-
-	// 3. service执行 create, update, delete
-	updatedActionPKExpressionPKs, err := c.policyService.AlterCustomPolicies(
-		subjectPK, cps, ups, deletePolicyIDs, actionPKWithResourceTypeSet)
+	// 3. 检查新增的操作是否违反职责分离约束
+	createdActionPKs := make([]int64, 0, len(cps))
+	for _, p := range cps {
+		if p.Effect != svctypes.PolicyEffectDeny {
+			createdActionPKs = append(createdActionPKs, p.ActionPK)
+		}
+	}
+	err = c.sodConstraintService.CheckSubjectActions(subjectPK, createdActionPKs, deletePolicyIDs)
 	if err != nil {
-		err = errorWrapf(err, "policyService.AlterPolicies system=`%s`, subjectPK=`%d` fail", system, subjectPK)
+		err = errorWrapf(err, "sodConstraintService.CheckSubjectActions subjectPK=`%d`, actionPKs=`%+v` fail",
+			subjectPK, createdActionPKs)
 		return
 	}
 
-	defer expression.BatchDeleteExpressionsFromCache(updatedActionPKExpressionPKs)
-	// NOTE: publish policy delete pk event
-	c.eventProducer.PublishABACDeletePolicyEvent(deletePolicyIDs)
-
-	return nil
+	// 4. 查询变更前的策略, 生成变更记录
+	beforePolicies, err := c.listPoliciesBeforeAlter(subjectPK, ups, deletePolicyIDs)
+	if err != nil {
+		err = errorWrapf(err, "c.listPoliciesBeforeAlter subjectPK=`%d` fail", subjectPK)
+		return
+	}
+	histories := newPolicyHistories(
+		subjectPK, system, service.PolicyTemplateIDCustom,
+		cps, ups, beforePolicies, deletePolicyIDs,
+		actionPKWithResourceTypeSet, operator,
+	)
+
+	return customPoliciesAlterPlan{
+		subjectPK:                   subjectPK,
+		actionPKMap:                 actionPKMap,
+		actionPKWithResourceTypeSet: actionPKWithResourceTypeSet,
+		createPolicies:              cps,
+		updatePolicies:              ups,
+		histories:                   histories,
+	}, nil
 }
 
 // CreateTemporaryPolicies create subject temporary policies
@@ -17,11 +17,14 @@ import (
 	"iam/pkg/abac/prp/policy"
 	"iam/pkg/abac/prp/temporary"
 	"iam/pkg/abac/types"
+	"iam/pkg/database"
+	"iam/pkg/service"
 	"iam/pkg/service/mock"
 	svctypes "iam/pkg/service/types"
 
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/stretchr/testify/assert"
 )
@@ -50,36 +53,46 @@ var _ = Describe("PolicyCurd", func() {
 				subjectService: mockSubjectService,
 			}
 
-			err := policyCtl.DeleteByIDs("test", "user", "test", []int64{1, 2})
+			err := policyCtl.DeleteByIDs("test", "user", "test", []int64{1, 2}, Operator{})
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "subjectService.GetPK")
 		})
 
-		It("policyService.DeleteByPKs fail", func() {
+		It("policyService.DeleteByPKsWithTx fail", func() {
 			mockSubjectService := mock.NewMockSubjectService(ctl)
 			mockSubjectService.EXPECT().GetPK("user", "test").Return(
 				int64(1), nil,
 			).AnyTimes()
 			mockPolicyService := mock.NewMockPolicyService(ctl)
-			mockPolicyService.EXPECT().DeleteByPKs(
-				int64(1), []int64{1, 2},
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{1, 2}).Return(
+				[]svctypes.Policy{}, nil,
+			).AnyTimes()
+			mockPolicyService.EXPECT().DeleteByPKsWithTx(
+				gomock.Any(), int64(1), []int64{1, 2},
 			).Return(
 				errors.New("delete fail"),
 			).AnyTimes()
 
+			db, dbMock := database.NewMockSqlxDB()
+			dbMock.ExpectBegin()
+			tx, _ := db.Beginx()
+
 			patches = gomonkey.ApplyFunc(policy.DeleteSystemSubjectPKsFromCache,
 				func(systemID string, pks []int64) error {
 					return nil
 				})
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return tx, nil
+			})
 
 			policyCtl := &policyController{
 				subjectService: mockSubjectService,
 				policyService:  mockPolicyService,
 			}
 
-			err := policyCtl.DeleteByIDs("test", "user", "test", []int64{1, 2})
+			err := policyCtl.DeleteByIDs("test", "user", "test", []int64{1, 2}, Operator{})
 			assert.Error(GinkgoT(), err)
-			assert.Contains(GinkgoT(), err.Error(), "policyService.DeleteByPKs")
+			assert.Contains(GinkgoT(), err.Error(), "policyService.DeleteByPKsWithTx")
 		})
 
 		It("success", func() {
@@ -88,11 +101,33 @@ var _ = Describe("PolicyCurd", func() {
 				int64(1), nil,
 			).AnyTimes()
 			mockPolicyService := mock.NewMockPolicyService(ctl)
-			mockPolicyService.EXPECT().DeleteByPKs(
-				int64(1), []int64{1, 2},
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{1, 2}).Return(
+				[]svctypes.Policy{
+					{ID: 1, ActionPK: 3, Expression: "[]", ExpiredAt: 10},
+					// 模板权限不会被删除
+					{ID: 2, ActionPK: 4, TemplateID: 1},
+				}, nil,
+			).AnyTimes()
+			mockPolicyService.EXPECT().DeleteByPKsWithTx(
+				gomock.Any(), int64(1), []int64{1, 2},
 			).Return(
 				nil,
 			).AnyTimes()
+			mockPolicyHistoryService := mock.NewMockPolicyHistoryService(ctl)
+			mockPolicyHistoryService.EXPECT().BulkCreateWithTx(gomock.Any(), []svctypes.PolicyHistory{{
+				SubjectPK: 1,
+				System:    "test",
+				ActionPK:  3,
+				PolicyPK:  1,
+				Operation: svctypes.PolicyHistoryOperationDelete,
+				Before:    &svctypes.PolicySnapshot{Expression: "[]", ExpiredAt: 10},
+				Actor:     "admin",
+			}}).Return(nil)
+
+			db, dbMock := database.NewMockSqlxDB()
+			dbMock.ExpectBegin()
+			dbMock.ExpectCommit()
+			tx, _ := db.Beginx()
 
 			mockPolicyEventProducer := eventmock.NewMockPolicyEventProducer(ctl)
 			mockPolicyEventProducer.EXPECT().PublishABACDeletePolicyEvent(gomock.Any()).AnyTimes()
@@ -101,15 +136,19 @@ var _ = Describe("PolicyCurd", func() {
 				func(systemID string, pks []int64) error {
 					return nil
 				})
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return tx, nil
+			})
 
 			policyCtl := &policyController{
-				subjectService: mockSubjectService,
-				policyService:  mockPolicyService,
+				subjectService:       mockSubjectService,
+				policyService:        mockPolicyService,
+				policyHistoryService: mockPolicyHistoryService,
 
 				eventProducer: mockPolicyEventProducer,
 			}
 
-			err := policyCtl.DeleteByIDs("test", "user", "test", []int64{1, 2})
+			err := policyCtl.DeleteByIDs("test", "user", "test", []int64{1, 2}, Operator{Actor: "admin"})
 			assert.NoError(GinkgoT(), err)
 		})
 	})
@@ -117,8 +156,13 @@ var _ = Describe("PolicyCurd", func() {
 	Describe("AlterCustomPolicies", func() {
 		var ctl *gomock.Controller
 		var patches *gomonkey.Patches
+		var mockSoDConstraintService *mock.MockSoDConstraintService
 		BeforeEach(func() {
 			ctl = gomock.NewController(GinkgoT())
+			mockSoDConstraintService = mock.NewMockSoDConstraintService(ctl)
+			mockSoDConstraintService.EXPECT().CheckSubjectActions(
+				gomock.Any(), gomock.Any(), gomock.Any(),
+			).Return(nil).AnyTimes()
 		})
 		AfterEach(func() {
 			ctl.Finish()
@@ -134,10 +178,13 @@ var _ = Describe("PolicyCurd", func() {
 			).AnyTimes()
 
 			policyCtl := &policyController{
-				subjectService: mockSubjectService,
+				subjectService:       mockSubjectService,
+				sodConstraintService: mockSoDConstraintService,
 			}
 
-			err := policyCtl.AlterCustomPolicies("test", "user", "test", []types.Policy{}, []types.Policy{}, []int64{1})
+			err := policyCtl.AlterCustomPolicies(
+				"test", "user", "test", []types.Policy{}, []types.Policy{}, []int64{1}, Operator{},
+			)
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "subjectService.GetPK")
 		})
@@ -154,11 +201,14 @@ var _ = Describe("PolicyCurd", func() {
 			).AnyTimes()
 
 			policyCtl := &policyController{
-				subjectService: mockSubjectService,
-				actionService:  mockActionService,
+				subjectService:       mockSubjectService,
+				actionService:        mockActionService,
+				sodConstraintService: mockSoDConstraintService,
 			}
 
-			err := policyCtl.AlterCustomPolicies("test", "user", "test", []types.Policy{}, []types.Policy{}, []int64{1})
+			err := policyCtl.AlterCustomPolicies(
+				"test", "user", "test", []types.Policy{}, []types.Policy{}, []int64{1}, Operator{},
+			)
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "actionService.ListThinActionBySystem")
 		})
@@ -178,11 +228,14 @@ var _ = Describe("PolicyCurd", func() {
 			).AnyTimes()
 
 			policyCtl := &policyController{
-				subjectService: mockSubjectService,
-				actionService:  mockActionService,
+				subjectService:       mockSubjectService,
+				actionService:        mockActionService,
+				sodConstraintService: mockSoDConstraintService,
 			}
 
-			err := policyCtl.AlterCustomPolicies("test", "user", "test", []types.Policy{}, []types.Policy{}, []int64{1})
+			err := policyCtl.AlterCustomPolicies(
+				"test", "user", "test", []types.Policy{}, []types.Policy{}, []int64{1}, Operator{},
+			)
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "actionService.ListActionResourceTypeIDByActionSystem")
 		})
@@ -202,15 +255,16 @@ var _ = Describe("PolicyCurd", func() {
 			).AnyTimes()
 
 			policyCtl := &policyController{
-				subjectService: mockSubjectService,
-				actionService:  mockActionService,
+				subjectService:       mockSubjectService,
+				actionService:        mockActionService,
+				sodConstraintService: mockSoDConstraintService,
 			}
 
 			err := policyCtl.AlterCustomPolicies("test", "user", "test", []types.Policy{{
 				Action: types.Action{
 					ID: "test",
 				},
-			}}, []types.Policy{}, []int64{1})
+			}}, []types.Policy{}, []int64{1}, Operator{})
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "action not exists")
 		})
@@ -230,19 +284,53 @@ var _ = Describe("PolicyCurd", func() {
 			).AnyTimes()
 
 			policyCtl := &policyController{
-				subjectService: mockSubjectService,
-				actionService:  mockActionService,
+				subjectService:       mockSubjectService,
+				actionService:        mockActionService,
+				sodConstraintService: mockSoDConstraintService,
 			}
 
 			err := policyCtl.AlterCustomPolicies("test", "user", "test", []types.Policy{}, []types.Policy{{
 				Action: types.Action{
 					ID: "test",
 				},
-			}}, []int64{1})
+			}}, []int64{1}, Operator{})
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "action not exists")
 		})
 
+		It("sod constraint violation", func() {
+			mockSubjectService := mock.NewMockSubjectService(ctl)
+			mockSubjectService.EXPECT().GetPK("user", "test").Return(
+				int64(1), nil,
+			).AnyTimes()
+
+			mockActionService := mock.NewMockActionService(ctl)
+			mockActionService.EXPECT().ListThinActionBySystem("test").Return(
+				[]svctypes.ThinAction{{PK: 10, ID: "pay"}}, nil,
+			).AnyTimes()
+			mockActionService.EXPECT().ListActionResourceTypeIDByActionSystem("test").Return(
+				[]svctypes.ActionResourceTypeID{}, nil,
+			).AnyTimes()
+
+			mockSoDService := mock.NewMockSoDConstraintService(ctl)
+			mockSoDService.EXPECT().CheckSubjectActions(int64(1), []int64{10}, []int64{2}).Return(
+				service.ErrSoDConstraintViolation,
+			)
+
+			policyCtl := &policyController{
+				subjectService:       mockSubjectService,
+				actionService:        mockActionService,
+				sodConstraintService: mockSoDService,
+			}
+
+			err := policyCtl.AlterCustomPolicies("test", "user", "test", []types.Policy{{
+				Action: types.Action{
+					ID: "pay",
+				},
+			}}, []types.Policy{}, []int64{2}, Operator{})
+			assert.ErrorIs(GinkgoT(), err, service.ErrSoDConstraintViolation)
+		})
+
 		It("policyService.AlterCustomPolicies fail", func() {
 			mockSubjectService := mock.NewMockSubjectService(ctl)
 			mockSubjectService.EXPECT().GetPK("user", "test").Return(
@@ -257,24 +345,37 @@ var _ = Describe("PolicyCurd", func() {
 				[]svctypes.ActionResourceTypeID{}, nil,
 			).AnyTimes()
 			mockPolicyService := mock.NewMockPolicyService(ctl)
-			mockPolicyService.EXPECT().AlterCustomPolicies(
-				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{1}).Return(
+				[]svctypes.Policy{}, nil,
+			).AnyTimes()
+			mockPolicyService.EXPECT().AlterCustomPoliciesWithTx(
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			).Return(
 				map[int64][]int64{}, errors.New("alter policies fail"),
 			).AnyTimes()
 
+			db, dbMock := database.NewMockSqlxDB()
+			dbMock.ExpectBegin()
+			tx, _ := db.Beginx()
+
 			patches = gomonkey.ApplyFunc(policy.DeleteSystemSubjectPKsFromCache,
 				func(systemID string, pks []int64) error {
 					return nil
 				})
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return tx, nil
+			})
 
 			policyCtl := &policyController{
-				subjectService: mockSubjectService,
-				actionService:  mockActionService,
-				policyService:  mockPolicyService,
+				subjectService:       mockSubjectService,
+				actionService:        mockActionService,
+				policyService:        mockPolicyService,
+				sodConstraintService: mockSoDConstraintService,
 			}
 
-			err := policyCtl.AlterCustomPolicies("test", "user", "test", []types.Policy{}, []types.Policy{}, []int64{1})
+			err := policyCtl.AlterCustomPolicies(
+				"test", "user", "test", []types.Policy{}, []types.Policy{}, []int64{1}, Operator{},
+			)
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "policyService.AlterPolicies")
 		})
@@ -293,29 +394,46 @@ var _ = Describe("PolicyCurd", func() {
 				[]svctypes.ActionResourceTypeID{}, nil,
 			).AnyTimes()
 			mockPolicyService := mock.NewMockPolicyService(ctl)
-			mockPolicyService.EXPECT().AlterCustomPolicies(
-				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{1}).Return(
+				[]svctypes.Policy{}, nil,
+			).AnyTimes()
+			mockPolicyService.EXPECT().AlterCustomPoliciesWithTx(
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			).Return(
 				map[int64][]int64{}, nil,
 			).AnyTimes()
+			mockPolicyHistoryService := mock.NewMockPolicyHistoryService(ctl)
+			mockPolicyHistoryService.EXPECT().BulkCreateWithTx(gomock.Any(), []svctypes.PolicyHistory{}).Return(nil)
 
 			mockPolicyEventProducer := eventmock.NewMockPolicyEventProducer(ctl)
 			mockPolicyEventProducer.EXPECT().PublishABACDeletePolicyEvent(gomock.Any()).AnyTimes()
 
+			db, dbMock := database.NewMockSqlxDB()
+			dbMock.ExpectBegin()
+			dbMock.ExpectCommit()
+			tx, _ := db.Beginx()
+
 			patches = gomonkey.ApplyFunc(policy.DeleteSystemSubjectPKsFromCache,
 				func(systemID string, pks []int64) error {
 					return nil
 				})
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return tx, nil
+			})
 
 			policyCtl := &policyController{
-				subjectService: mockSubjectService,
-				actionService:  mockActionService,
-				policyService:  mockPolicyService,
+				subjectService:       mockSubjectService,
+				actionService:        mockActionService,
+				policyService:        mockPolicyService,
+				policyHistoryService: mockPolicyHistoryService,
 
-				eventProducer: mockPolicyEventProducer,
+				eventProducer:        mockPolicyEventProducer,
+				sodConstraintService: mockSoDConstraintService,
 			}
 
-			err := policyCtl.AlterCustomPolicies("test", "user", "test", []types.Policy{}, []types.Policy{}, []int64{1})
+			err := policyCtl.AlterCustomPolicies(
+				"test", "user", "test", []types.Policy{}, []types.Policy{}, []int64{1}, Operator{},
+			)
 			assert.NoError(GinkgoT(), err)
 		})
 	})
@@ -0,0 +1,611 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/abac/types"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service"
+	svctypes "iam/pkg/service/types"
+)
+
+// ErrInvalidPolicyVersion 回滚的版本不存在或不属于该subject与系统
+var ErrInvalidPolicyVersion = errors.New("invalid policy version")
+
+// listPoliciesBeforeAlter 查询变更前的策略, 用于记录变更历史
+func (c *policyController) listPoliciesBeforeAlter(
+	subjectPK int64, updatePolicies []svctypes.Policy, deletePolicyIDs []int64,
+) ([]svctypes.Policy, error) {
+	pks := make([]int64, 0, len(updatePolicies)+len(deletePolicyIDs))
+	for _, p := range updatePolicies {
+		pks = append(pks, p.ID)
+	}
+	pks = append(pks, deletePolicyIDs...)
+	if len(pks) == 0 {
+		return nil, nil
+	}
+
+	return c.policyService.ListBySubjectPKAndPKs(subjectPK, pks)
+}
+
+func newPolicySnapshot(p svctypes.Policy) *svctypes.PolicySnapshot {
+	return &svctypes.PolicySnapshot{
+		Expression:  p.Expression,
+		ExpiredAt:   p.ExpiredAt,
+		Effect:      p.Effect,
+		EffectiveAt: p.EffectiveAt,
+	}
+}
+
+// newPolicyHistories 根据变更前的策略与变更内容生成变更记录, 与service层的变更逻辑保持一致
+func newPolicyHistories(
+	subjectPK int64, system string, templateID int64,
+	createPolicies, updatePolicies, beforePolicies []svctypes.Policy,
+	deletePolicyIDs []int64,
+	actionPKWithResourceTypeSet *set.Int64Set,
+	operator Operator,
+) []svctypes.PolicyHistory {
+	// 只有当前模板下的策略会被变更
+	beforePolicyMap := make(map[int64]svctypes.Policy, len(beforePolicies))
+	for _, p := range beforePolicies {
+		if p.TemplateID == templateID {
+			beforePolicyMap[p.ID] = p
+		}
+	}
+
+	histories := make([]svctypes.PolicyHistory, 0, len(createPolicies)+len(updatePolicies)+len(deletePolicyIDs))
+	newHistory := func(
+		actionPK, policyPK int64, operation string, before, after *svctypes.PolicySnapshot,
+	) svctypes.PolicyHistory {
+		return svctypes.PolicyHistory{
+			SubjectPK:  subjectPK,
+			System:     system,
+			ActionPK:   actionPK,
+			TemplateID: templateID,
+			PolicyPK:   policyPK,
+			Operation:  operation,
+			Before:     before,
+			After:      after,
+			ClientID:   operator.ClientID,
+			Actor:      operator.Actor,
+			RequestID:  operator.RequestID,
+		}
+	}
+
+	for _, p := range createPolicies {
+		after := newPolicySnapshot(p)
+		// 操作未关联资源类型, 不保存expression
+		if !actionPKWithResourceTypeSet.Has(p.ActionPK) {
+			after.Expression = ""
+		}
+		histories = append(histories, newHistory(p.ActionPK, 0, svctypes.PolicyHistoryOperationCreate, nil, after))
+	}
+
+	for _, p := range updatePolicies {
+		bp, ok := beforePolicyMap[p.ID]
+		if !ok || bp.ActionPK != p.ActionPK {
+			continue
+		}
+
+		before := newPolicySnapshot(bp)
+		after := *before
+		if before.Expression != "" {
+			after.Expression = p.Expression
+		}
+		after.Effect = p.Effect
+		// 自定义权限只会延长过期时间, 模板权限不更新过期时间
+		if templateID == service.PolicyTemplateIDCustom && p.ExpiredAt > after.ExpiredAt {
+			after.ExpiredAt = p.ExpiredAt
+		}
+		if reflect.DeepEqual(after, *before) {
+			continue
+		}
+		// effect变化时记录为删除原effect的策略并新增新effect的策略, 保证同一effect的变化可以合并与回滚
+		if after.Effect != before.Effect {
+			histories = append(histories,
+				newHistory(p.ActionPK, p.ID, svctypes.PolicyHistoryOperationDelete, before, nil),
+				newHistory(p.ActionPK, p.ID, svctypes.PolicyHistoryOperationCreate, nil, &after),
+			)
+			continue
+		}
+		histories = append(histories, newHistory(p.ActionPK, p.ID, svctypes.PolicyHistoryOperationUpdate, before, &after))
+	}
+
+	for _, id := range deletePolicyIDs {
+		bp, ok := beforePolicyMap[id]
+		if !ok {
+			continue
+		}
+		histories = append(
+			histories,
+			newHistory(bp.ActionPK, id, svctypes.PolicyHistoryOperationDelete, newPolicySnapshot(bp), nil),
+		)
+	}
+	return histories
+}
+
+// newRBACPolicyHistories 根据RBAC策略实际发生变化的内容生成变更记录, 每个操作对资源实例的授权与回收各记录一条
+func newRBACPolicyHistories(
+	subjectPK int64, system string, templateID int64,
+	changedContents []svctypes.ResourceChangedContent,
+	operator Operator,
+) ([]svctypes.PolicyHistory, error) {
+	histories := make([]svctypes.PolicyHistory, 0, len(changedContents))
+	newHistory := func(actionPK int64, operation string, before, after *svctypes.PolicySnapshot) svctypes.PolicyHistory {
+		return svctypes.PolicyHistory{
+			SubjectPK:  subjectPK,
+			System:     system,
+			ActionPK:   actionPK,
+			TemplateID: templateID,
+			Operation:  operation,
+			Before:     before,
+			After:      after,
+			ClientID:   operator.ClientID,
+			Actor:      operator.Actor,
+			RequestID:  operator.RequestID,
+		}
+	}
+
+	for _, rcc := range changedContents {
+		resources, err := convertToPolicyResources(rcc)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, actionPK := range rcc.CreatedActionPKs {
+			after := &svctypes.PolicySnapshot{Resources: resources}
+			histories = append(histories, newHistory(actionPK, svctypes.PolicyHistoryOperationCreate, nil, after))
+		}
+		for _, actionPK := range rcc.DeletedActionPKs {
+			before := &svctypes.PolicySnapshot{Resources: resources}
+			histories = append(histories, newHistory(actionPK, svctypes.PolicyHistoryOperationDelete, before, nil))
+		}
+	}
+	return histories, nil
+}
+
+// convertToPolicyResources 将RBAC策略的资源实例(包括关联的其它资源实例)转换为可读的资源实例
+func convertToPolicyResources(rcc svctypes.ResourceChangedContent) ([]svctypes.PolicyResource, error) {
+	resourceTypePKs := make([]int64, 0, 1+len(rcc.RelatedResources))
+	resourceIDs := make([]string, 0, 1+len(rcc.RelatedResources))
+	resourceTypePKs = append(resourceTypePKs, rcc.ResourceTypePK)
+	resourceIDs = append(resourceIDs, rcc.ResourceID)
+	for _, rr := range rcc.RelatedResources {
+		resourceTypePKs = append(resourceTypePKs, rr.ResourceTypePK)
+		resourceIDs = append(resourceIDs, rr.ResourceID)
+	}
+
+	resources := make([]svctypes.PolicyResource, 0, len(resourceTypePKs))
+	for i, pk := range resourceTypePKs {
+		resourceType, err := cacheimpls.GetThinResourceType(pk)
+		if err != nil {
+			return nil, errorx.Wrapf(err, PolicyCTL, "convertToPolicyResources",
+				"cacheimpls.GetThinResourceType pk=`%d` fail", pk)
+		}
+		resources = append(resources, svctypes.PolicyResource{
+			System: resourceType.System,
+			Type:   resourceType.ID,
+			ID:     resourceIDs[i],
+		})
+	}
+	return resources, nil
+}
+
+func convertToPolicySnapshot(snapshot *svctypes.PolicySnapshot) *PolicySnapshot {
+	if snapshot == nil {
+		return nil
+	}
+
+	var resources []PolicyResource
+	for _, r := range snapshot.Resources {
+		resources = append(resources, PolicyResource{System: r.System, Type: r.Type, ID: r.ID})
+	}
+
+	return &PolicySnapshot{
+		Expression:  snapshot.Expression,
+		ExpiredAt:   snapshot.ExpiredAt,
+		Effect:      svctypes.ConvertToPolicyEffectStr(snapshot.Effect),
+		EffectiveAt: snapshot.EffectiveAt,
+		Resources:   resources,
+	}
+}
+
+func (c *policyController) querySubjectActionIDMap(
+	system, subjectType, subjectID string,
+) (subjectPK int64, actionIDMap map[int64]string, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "querySubjectActionIDMap")
+
+	subjectPK, err = c.subjectService.GetPK(subjectType, subjectID)
+	if err != nil {
+		err = errorWrapf(err, "subjectService.GetPK subjectType=`%s`, subjectID=`%s` fail", subjectType, subjectID)
+		return
+	}
+
+	actions, err := c.actionService.ListThinActionBySystem(system)
+	if err != nil {
+		err = errorWrapf(err, "actionService.ListThinActionBySystem system=`%s` fail", system)
+		return
+	}
+	actionIDMap = make(map[int64]string, len(actions))
+	for _, a := range actions {
+		actionIDMap[a.PK] = a.ID
+	}
+	return subjectPK, actionIDMap, nil
+}
+
+// GetPolicyHistoryCount ...
+func (c *policyController) GetPolicyHistoryCount(system, subjectType, subjectID string) (int64, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "GetPolicyHistoryCount")
+
+	subjectPK, err := c.subjectService.GetPK(subjectType, subjectID)
+	if err != nil {
+		return 0, errorWrapf(err, "subjectService.GetPK subjectType=`%s`, subjectID=`%s` fail",
+			subjectType, subjectID)
+	}
+
+	count, err := c.policyHistoryService.GetCount(subjectPK, system)
+	if err != nil {
+		return 0, errorWrapf(err, "policyHistoryService.GetCount subjectPK=`%d`, system=`%s` fail", subjectPK, system)
+	}
+	return count, nil
+}
+
+// ListPagingPolicyHistory 按版本倒序查询subject在系统下的策略变更记录
+func (c *policyController) ListPagingPolicyHistory(
+	system, subjectType, subjectID string, limit, offset int64,
+) ([]PolicyHistory, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "ListPagingPolicyHistory")
+
+	subjectPK, actionIDMap, err := c.querySubjectActionIDMap(system, subjectType, subjectID)
+	if err != nil {
+		return nil, errorWrapf(err, "c.querySubjectActionIDMap system=`%s` fail", system)
+	}
+
+	svcHistories, err := c.policyHistoryService.ListPaging(subjectPK, system, limit, offset)
+	if err != nil {
+		return nil, errorWrapf(err, "policyHistoryService.ListPaging subjectPK=`%d`, system=`%s` fail",
+			subjectPK, system)
+	}
+
+	histories := make([]PolicyHistory, 0, len(svcHistories))
+	for _, h := range svcHistories {
+		histories = append(histories, PolicyHistory{
+			Version:    h.Version,
+			ActionID:   actionIDMap[h.ActionPK],
+			TemplateID: h.TemplateID,
+			PolicyID:   h.PolicyPK,
+			Operation:  h.Operation,
+			Before:     convertToPolicySnapshot(h.Before),
+			After:      convertToPolicySnapshot(h.After),
+			ClientID:   h.ClientID,
+			Actor:      h.Actor,
+			RequestID:  h.RequestID,
+			CreatedAt:  h.CreatedAt,
+		})
+	}
+	return histories, nil
+}
+
+// DiffPolicyHistory 比较两个版本之间的策略变化, toVersion为0表示当前最新版本
+func (c *policyController) DiffPolicyHistory(
+	system, subjectType, subjectID string, fromVersion, toVersion int64,
+) ([]PolicyChange, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "DiffPolicyHistory")
+
+	subjectPK, actionIDMap, err := c.querySubjectActionIDMap(system, subjectType, subjectID)
+	if err != nil {
+		return nil, errorWrapf(err, "c.querySubjectActionIDMap system=`%s` fail", system)
+	}
+
+	svcChanges, err := c.policyHistoryService.ListChanges(subjectPK, system, fromVersion, toVersion)
+	if err != nil {
+		return nil, errorWrapf(
+			err, "policyHistoryService.ListChanges subjectPK=`%d`, system=`%s`, from=`%d`, to=`%d` fail",
+			subjectPK, system, fromVersion, toVersion,
+		)
+	}
+
+	changes := make([]PolicyChange, 0, len(svcChanges))
+	for _, change := range svcChanges {
+		changes = append(changes, PolicyChange{
+			ActionID:   actionIDMap[change.ActionPK],
+			TemplateID: change.TemplateID,
+			Before:     convertToPolicySnapshot(change.Before),
+			After:      convertToPolicySnapshot(change.After),
+		})
+	}
+	return changes, nil
+}
+
+// RollbackPolicies 将subject在系统下的策略恢复到指定版本, 通过正常的变更流程执行, 回滚本身也会记录变更历史
+// NOTE: 与正常变更一致, 更新策略不会缩短过期时间, 也不会修改生效时间
+func (c *policyController) RollbackPolicies(
+	system, subjectType, subjectID string, version int64, operator Operator,
+) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "RollbackPolicies")
+
+	// 1. 查询subject与操作
+	subjectPK, actionIDMap, err := c.querySubjectActionIDMap(system, subjectType, subjectID)
+	if err != nil {
+		return errorWrapf(err, "c.querySubjectActionIDMap system=`%s` fail", system)
+	}
+
+	// 2. 校验版本属于该subject与系统
+	history, err := c.policyHistoryService.Get(version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errorWrapf(ErrInvalidPolicyVersion, "version=`%d` not exists", version)
+	}
+	if err != nil {
+		return errorWrapf(err, "policyHistoryService.Get version=`%d` fail", version)
+	}
+	if history.SubjectPK != subjectPK || history.System != system {
+		return errorWrapf(ErrInvalidPolicyVersion, "version=`%d` not belong to subjectPK=`%d` system=`%s`",
+			version, subjectPK, system)
+	}
+
+	// 3. 查询指定版本之后的变化, 回滚即恢复到变化之前
+	changes, err := c.policyHistoryService.ListChanges(subjectPK, system, version, 0)
+	if err != nil {
+		return errorWrapf(err, "policyHistoryService.ListChanges subjectPK=`%d`, system=`%s`, from=`%d` fail",
+			subjectPK, system, version)
+	}
+
+	// 4. 按模板分别回滚
+	templateChanges := make(map[int64][]svctypes.PolicyChange)
+	for _, change := range changes {
+		templateChanges[change.TemplateID] = append(templateChanges[change.TemplateID], change)
+	}
+	templateIDs := make([]int64, 0, len(templateChanges))
+	for templateID := range templateChanges {
+		templateIDs = append(templateIDs, templateID)
+	}
+	sort.Slice(templateIDs, func(i, j int) bool { return templateIDs[i] < templateIDs[j] })
+
+	for _, templateID := range templateIDs {
+		err = c.rollbackTemplatePolicies(
+			system, subjectType, subjectID, subjectPK, templateID, templateChanges[templateID], actionIDMap, operator,
+		)
+		if err != nil {
+			return errorWrapf(err, "c.rollbackTemplatePolicies templateID=`%d` fail", templateID)
+		}
+	}
+	return nil
+}
+
+func (c *policyController) rollbackTemplatePolicies(
+	system, subjectType, subjectID string,
+	subjectPK, templateID int64,
+	changes []svctypes.PolicyChange,
+	actionIDMap map[int64]string,
+	operator Operator,
+) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "rollbackTemplatePolicies")
+
+	// 1. RBAC授权按资源实例恢复, 其余为ABAC策略
+	changes, resourceChangedActions := splitRBACPolicyChanges(changes, actionIDMap)
+
+	// 2. 查询当前的ABAC策略
+	actionPKs := make([]int64, 0, len(changes))
+	for _, change := range changes {
+		actionPKs = append(actionPKs, change.ActionPK)
+	}
+	// 同一操作下允许同时存在allow与deny策略, 按effect区分
+	type actionEffect struct {
+		actionPK int64
+		effect   int64
+	}
+	actionPolicyMap := make(map[actionEffect][]svctypes.ThinPolicy, len(actionPKs))
+	if len(actionPKs) > 0 {
+		policies, err := c.policyService.ListThinBySubjectActionTemplate(subjectPK, actionPKs, templateID)
+		if err != nil {
+			return errorWrapf(err,
+				"policyService.ListThinBySubjectActionTemplate subjectPK=`%d`, templateID=`%d` fail",
+				subjectPK, templateID)
+		}
+		for _, p := range policies {
+			key := actionEffect{actionPK: p.ActionPK, effect: p.Effect}
+			actionPolicyMap[key] = append(actionPolicyMap[key], p)
+		}
+	}
+
+	// 3. 计算恢复到变化之前需要的变更
+	subject := types.Subject{
+		Type:      subjectType,
+		ID:        subjectID,
+		Attribute: types.NewSubjectAttribute(),
+	}
+	createPolicies := make([]types.Policy, 0, len(changes))
+	updatePolicies := make([]types.Policy, 0, len(changes))
+	deletePolicyIDs := make([]int64, 0, len(changes))
+	for _, change := range changes {
+		actionID, ok := actionIDMap[change.ActionPK]
+		// 操作已被删除
+		if !ok {
+			continue
+		}
+
+		target := change.Before
+		if target == nil {
+			// 变化之前不存在, 只删除相同effect的策略
+			current := actionPolicyMap[actionEffect{actionPK: change.ActionPK, effect: change.After.Effect}]
+			for _, p := range current {
+				deletePolicyIDs = append(deletePolicyIDs, p.ID)
+			}
+			continue
+		}
+
+		p := types.Policy{
+			Version: service.PolicyVersion,
+			System:  system,
+			Subject: subject,
+			Action: types.Action{
+				ID:        actionID,
+				Attribute: types.NewActionAttribute(),
+			},
+			Expression:  target.Expression,
+			ExpiredAt:   target.ExpiredAt,
+			TemplateID:  templateID,
+			Effect:      target.Effect,
+			EffectiveAt: target.EffectiveAt,
+		}
+		current := actionPolicyMap[actionEffect{actionPK: change.ActionPK, effect: target.Effect}]
+		if len(current) == 0 {
+			createPolicies = append(createPolicies, p)
+			continue
+		}
+
+		p.ID = current[0].ID
+		updatePolicies = append(updatePolicies, p)
+		for _, extra := range current[1:] {
+			deletePolicyIDs = append(deletePolicyIDs, extra.ID)
+		}
+	}
+
+	if len(createPolicies) == 0 && len(updatePolicies) == 0 && len(deletePolicyIDs) == 0 &&
+		len(resourceChangedActions) == 0 {
+		return nil
+	}
+
+	// 4. 通过正常的变更流程执行, 保证缓存与RBAC变更事件一致
+	if subjectType != svctypes.GroupType {
+		err := c.AlterCustomPolicies(
+			system, subjectType, subjectID, createPolicies, updatePolicies, deletePolicyIDs, operator,
+		)
+		if err != nil {
+			return errorWrapf(err, "c.AlterCustomPolicies system=`%s`, subjectPK=`%d` fail", system, subjectPK)
+		}
+		return nil
+	}
+
+	authType, err := c.getGroupAuthTypeForRollback(
+		system, subjectPK, len(createPolicies)+len(updatePolicies) > 0, hasCreatedRBACActions(resourceChangedActions),
+	)
+	if err != nil {
+		return errorWrapf(err, "c.getGroupAuthTypeForRollback system=`%s`, groupPK=`%d` fail", system, subjectPK)
+	}
+	err = c.AlterGroupPolicies(
+		system, subjectType, subjectID, templateID,
+		createPolicies, updatePolicies, deletePolicyIDs,
+		resourceChangedActions, authType, operator,
+	)
+	if err != nil {
+		return errorWrapf(err, "c.AlterGroupPolicies system=`%s`, groupPK=`%d`, templateID=`%d` fail",
+			system, subjectPK, templateID)
+	}
+	return nil
+}
+
+// splitRBACPolicyChanges 将RBAC授权的变化转换为恢复到变化之前的资源实例授权变更, 返回其余的ABAC策略变化
+func splitRBACPolicyChanges(
+	changes []svctypes.PolicyChange, actionIDMap map[int64]string,
+) ([]svctypes.PolicyChange, []types.ResourceChangedAction) {
+	abacChanges := make([]svctypes.PolicyChange, 0, len(changes))
+	resourceChangedActions := make([]types.ResourceChangedAction, 0)
+	// 相同资源实例的授权合并为一个变更
+	resourceIndex := make(map[string]int)
+	for _, change := range changes {
+		snapshot := change.Before
+		if snapshot == nil {
+			snapshot = change.After
+		}
+		if snapshot == nil || len(snapshot.Resources) == 0 {
+			abacChanges = append(abacChanges, change)
+			continue
+		}
+
+		actionID, ok := actionIDMap[change.ActionPK]
+		// 操作已被删除
+		if !ok {
+			continue
+		}
+
+		key := fmt.Sprint(snapshot.Resources)
+		idx, ok := resourceIndex[key]
+		if !ok {
+			rca := types.ResourceChangedAction{
+				CreatedActionIDs: []string{},
+				DeletedActionIDs: []string{},
+			}
+			for i, r := range snapshot.Resources {
+				node := types.ThinResourceNode{System: r.System, Type: r.Type, ID: r.ID}
+				if i == 0 {
+					rca.Resource = node
+				} else {
+					rca.RelatedResources = append(rca.RelatedResources, node)
+				}
+			}
+
+			resourceChangedActions = append(resourceChangedActions, rca)
+			idx = len(resourceChangedActions) - 1
+			resourceIndex[key] = idx
+		}
+
+		// 变化之前未授权则回收, 已授权则重新授权
+		rca := &resourceChangedActions[idx]
+		if change.Before == nil {
+			rca.DeletedActionIDs = append(rca.DeletedActionIDs, actionID)
+		} else {
+			rca.CreatedActionIDs = append(rca.CreatedActionIDs, actionID)
+		}
+	}
+	return abacChanges, resourceChangedActions
+}
+
+func hasCreatedRBACActions(resourceChangedActions []types.ResourceChangedAction) bool {
+	for _, rca := range resourceChangedActions {
+		if len(rca.CreatedActionIDs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getGroupAuthTypeForRollback 回滚不改变用户组的授权类型, 仅在恢复ABAC/RBAC策略时确保授权类型包含对应的类型
+func (c *policyController) getGroupAuthTypeForRollback(
+	system string, groupPK int64, hasABACPolicy, hasRBACPolicy bool,
+) (int64, error) {
+	groupAuthTypes, err := c.groupService.ListGroupAuthBySystemGroupPKs(system, []int64{groupPK})
+	if err != nil {
+		return 0, err
+	}
+
+	authType := svctypes.AuthTypeNone
+	if len(groupAuthTypes) > 0 {
+		authType = groupAuthTypes[0].AuthType
+	}
+
+	if hasABACPolicy {
+		switch authType {
+		case svctypes.AuthTypeNone:
+			authType = svctypes.AuthTypeABAC
+		case svctypes.AuthTypeRBAC:
+			authType = svctypes.AuthTypeAll
+		}
+	}
+	if hasRBACPolicy {
+		switch authType {
+		case svctypes.AuthTypeNone:
+			authType = svctypes.AuthTypeRBAC
+		case svctypes.AuthTypeABAC:
+			authType = svctypes.AuthTypeAll
+		}
+	}
+	return authType, nil
+}
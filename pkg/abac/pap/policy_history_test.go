@@ -0,0 +1,392 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	eventmock "iam/pkg/abac/pap/event/mock"
+	"iam/pkg/abac/prp/expression"
+	"iam/pkg/abac/prp/policy"
+	"iam/pkg/abac/types"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/database"
+	"iam/pkg/service"
+	"iam/pkg/service/mock"
+	svctypes "iam/pkg/service/types"
+)
+
+var _ = Describe("PolicyHistory", func() {
+	Describe("newPolicyHistories", func() {
+		It("ok", func() {
+			actionPKWithResourceTypeSet := set.NewInt64Set()
+			actionPKWithResourceTypeSet.Add(1)
+
+			histories := newPolicyHistories(
+				1, "test", 0,
+				// 新增, 操作2未关联资源类型
+				[]svctypes.Policy{
+					{ActionPK: 1, Expression: "a", ExpiredAt: 10},
+					{ActionPK: 2, Expression: "[]", ExpiredAt: 10},
+				},
+				// 更新, 策略4无变化, 策略5为模板权限
+				[]svctypes.Policy{
+					{ID: 3, ActionPK: 1, Expression: "b", ExpiredAt: 5, Effect: 1},
+					{ID: 4, ActionPK: 1, Expression: "c", ExpiredAt: 10},
+					{ID: 5, ActionPK: 1, Expression: "d", ExpiredAt: 10},
+				},
+				[]svctypes.Policy{
+					{ID: 3, ActionPK: 1, Expression: "a", ExpiredAt: 10},
+					{ID: 4, ActionPK: 1, Expression: "c", ExpiredAt: 10},
+					{ID: 5, ActionPK: 1, Expression: "c", ExpiredAt: 10, TemplateID: 1},
+					{ID: 6, ActionPK: 2, ExpiredAt: 10},
+				},
+				[]int64{6, 7},
+				actionPKWithResourceTypeSet,
+				Operator{ClientID: "bk_iam", Actor: "admin", RequestID: "abc"},
+			)
+
+			newHistory := func(
+				actionPK, policyPK int64, operation string, before, after *svctypes.PolicySnapshot,
+			) svctypes.PolicyHistory {
+				return svctypes.PolicyHistory{
+					SubjectPK: 1, System: "test", ActionPK: actionPK, PolicyPK: policyPK, Operation: operation,
+					Before: before, After: after, ClientID: "bk_iam", Actor: "admin", RequestID: "abc",
+				}
+			}
+			assert.Equal(GinkgoT(), []svctypes.PolicyHistory{
+				newHistory(1, 0, "create", nil, &svctypes.PolicySnapshot{Expression: "a", ExpiredAt: 10}),
+				newHistory(2, 0, "create", nil, &svctypes.PolicySnapshot{ExpiredAt: 10}),
+				// effect变化, 记录为删除与新增
+				newHistory(1, 3, "delete", &svctypes.PolicySnapshot{Expression: "a", ExpiredAt: 10}, nil),
+				newHistory(1, 3, "create", nil, &svctypes.PolicySnapshot{Expression: "b", ExpiredAt: 10, Effect: 1}),
+				newHistory(2, 6, "delete", &svctypes.PolicySnapshot{ExpiredAt: 10}, nil),
+			}, histories)
+		})
+	})
+
+	Describe("newRBACPolicyHistories", func() {
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			patches = gomonkey.ApplyFunc(cacheimpls.GetThinResourceType,
+				func(pk int64) (svctypes.ThinResourceType, error) {
+					if pk == 2 {
+						return svctypes.ThinResourceType{PK: 2, System: "test", ID: "cluster"}, nil
+					}
+					return svctypes.ThinResourceType{PK: pk, System: "test", ID: "host"}, nil
+				})
+		})
+		AfterEach(func() {
+			patches.Reset()
+		})
+
+		It("ok", func() {
+			histories, err := newRBACPolicyHistories(1, "test", 0, []svctypes.ResourceChangedContent{{
+				ResourceTypePK:   1,
+				ResourceID:       "h1",
+				CreatedActionPKs: []int64{3},
+				DeletedActionPKs: []int64{4},
+				RelatedResources: []svctypes.RelatedResource{{ResourceTypePK: 2, ResourceID: "c1"}},
+			}}, Operator{Actor: "admin"})
+			assert.NoError(GinkgoT(), err)
+
+			resources := []svctypes.PolicyResource{
+				{System: "test", Type: "host", ID: "h1"},
+				{System: "test", Type: "cluster", ID: "c1"},
+			}
+			assert.Equal(GinkgoT(), []svctypes.PolicyHistory{
+				{
+					SubjectPK: 1, System: "test", ActionPK: 3, Operation: "create",
+					After: &svctypes.PolicySnapshot{Resources: resources}, Actor: "admin",
+				},
+				{
+					SubjectPK: 1, System: "test", ActionPK: 4, Operation: "delete",
+					Before: &svctypes.PolicySnapshot{Resources: resources}, Actor: "admin",
+				},
+			}, histories)
+		})
+
+		It("GetThinResourceType fail", func() {
+			patches.ApplyFunc(cacheimpls.GetThinResourceType,
+				func(pk int64) (svctypes.ThinResourceType, error) {
+					return svctypes.ThinResourceType{}, errors.New("error")
+				})
+
+			_, err := newRBACPolicyHistories(1, "test", 0, []svctypes.ResourceChangedContent{{
+				ResourceTypePK: 1, ResourceID: "h1", CreatedActionPKs: []int64{3},
+			}}, Operator{})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "GetThinResourceType")
+		})
+	})
+
+	Describe("splitRBACPolicyChanges", func() {
+		It("ok", func() {
+			host1 := []svctypes.PolicyResource{{System: "test", Type: "host", ID: "1"}}
+			host2 := []svctypes.PolicyResource{
+				{System: "test", Type: "host", ID: "2"},
+				{System: "test", Type: "cluster", ID: "3"},
+			}
+			abacChange := svctypes.PolicyChange{ActionPK: 1, Before: &svctypes.PolicySnapshot{Expression: "a"}}
+
+			abacChanges, resourceChangedActions := splitRBACPolicyChanges([]svctypes.PolicyChange{
+				abacChange,
+				// 授权被回收, 需要重新授权
+				{ActionPK: 1, Before: &svctypes.PolicySnapshot{Resources: host1}},
+				// 新增的授权, 需要回收
+				{ActionPK: 2, After: &svctypes.PolicySnapshot{Resources: host1}},
+				{ActionPK: 3, After: &svctypes.PolicySnapshot{Resources: host2}},
+				// 操作已被删除
+				{ActionPK: 4, After: &svctypes.PolicySnapshot{Resources: host2}},
+			}, map[int64]string{1: "view", 2: "edit", 3: "delete"})
+
+			assert.Equal(GinkgoT(), []svctypes.PolicyChange{abacChange}, abacChanges)
+			assert.Equal(GinkgoT(), []types.ResourceChangedAction{
+				{
+					Resource:         types.ThinResourceNode{System: "test", Type: "host", ID: "1"},
+					CreatedActionIDs: []string{"view"},
+					DeletedActionIDs: []string{"edit"},
+				},
+				{
+					Resource:         types.ThinResourceNode{System: "test", Type: "host", ID: "2"},
+					RelatedResources: []types.ThinResourceNode{{System: "test", Type: "cluster", ID: "3"}},
+					CreatedActionIDs: []string{},
+					DeletedActionIDs: []string{"delete"},
+				},
+			}, resourceChangedActions)
+		})
+	})
+
+	Describe("RollbackPolicies", func() {
+		var ctl *gomock.Controller
+		var patches *gomonkey.Patches
+		var mockPolicyService *mock.MockPolicyService
+		var mockPolicyHistoryService *mock.MockPolicyHistoryService
+		var policyCtl *policyController
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+
+			mockSubjectService := mock.NewMockSubjectService(ctl)
+			mockSubjectService.EXPECT().GetPK("user", "test").Return(int64(1), nil).AnyTimes()
+
+			mockActionService := mock.NewMockActionService(ctl)
+			mockActionService.EXPECT().ListThinActionBySystem("test").Return([]svctypes.ThinAction{
+				{PK: 1, System: "test", ID: "view"},
+				{PK: 2, System: "test", ID: "edit"},
+				{PK: 3, System: "test", ID: "delete"},
+			}, nil).AnyTimes()
+			mockActionService.EXPECT().ListActionResourceTypeIDByActionSystem("test").Return(
+				[]svctypes.ActionResourceTypeID{
+					{ActionID: "view"}, {ActionID: "edit"}, {ActionID: "delete"},
+				}, nil,
+			).AnyTimes()
+
+			mockSoDConstraintService := mock.NewMockSoDConstraintService(ctl)
+			mockSoDConstraintService.EXPECT().CheckSubjectActions(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(nil).AnyTimes()
+
+			mockPolicyEventProducer := eventmock.NewMockPolicyEventProducer(ctl)
+			mockPolicyEventProducer.EXPECT().PublishABACDeletePolicyEvent(gomock.Any()).AnyTimes()
+
+			mockPolicyService = mock.NewMockPolicyService(ctl)
+			mockPolicyHistoryService = mock.NewMockPolicyHistoryService(ctl)
+
+			policyCtl = &policyController{
+				subjectService:       mockSubjectService,
+				actionService:        mockActionService,
+				policyService:        mockPolicyService,
+				sodConstraintService: mockSoDConstraintService,
+				policyHistoryService: mockPolicyHistoryService,
+				eventProducer:        mockPolicyEventProducer,
+			}
+
+			patches = gomonkey.ApplyFunc(policy.DeleteSystemSubjectPKsFromCache,
+				func(systemID string, pks []int64) error {
+					return nil
+				})
+			patches.ApplyFunc(expression.BatchDeleteExpressionsFromCache,
+				func(updatedActionPKExpressionPKs map[int64][]int64) error {
+					return nil
+				})
+		})
+		AfterEach(func() {
+			ctl.Finish()
+			patches.Reset()
+		})
+
+		It("version not exists", func() {
+			mockPolicyHistoryService.EXPECT().Get(int64(10)).Return(svctypes.PolicyHistory{}, sql.ErrNoRows)
+
+			err := policyCtl.RollbackPolicies("test", "user", "test", 10, Operator{})
+			assert.ErrorIs(GinkgoT(), err, ErrInvalidPolicyVersion)
+		})
+
+		It("version not belong to subject", func() {
+			mockPolicyHistoryService.EXPECT().Get(int64(10)).Return(
+				svctypes.PolicyHistory{Version: 10, SubjectPK: 2, System: "test"}, nil,
+			)
+
+			err := policyCtl.RollbackPolicies("test", "user", "test", 10, Operator{})
+			assert.ErrorIs(GinkgoT(), err, ErrInvalidPolicyVersion)
+		})
+
+		It("ok", func() {
+			mockPolicyHistoryService.EXPECT().Get(int64(10)).Return(
+				svctypes.PolicyHistory{Version: 10, SubjectPK: 1, System: "test"}, nil,
+			)
+			mockPolicyHistoryService.EXPECT().ListChanges(int64(1), "test", int64(10), int64(0)).Return(
+				[]svctypes.PolicyChange{
+					// 被修改
+					{
+						ActionPK: 1,
+						Before:   &svctypes.PolicySnapshot{Expression: "a", ExpiredAt: 100},
+						After:    &svctypes.PolicySnapshot{Expression: "b", ExpiredAt: 100},
+					},
+					// 被删除
+					{ActionPK: 2, Before: &svctypes.PolicySnapshot{Expression: "c", ExpiredAt: 100, Effect: 1}},
+					// 被新增
+					{ActionPK: 3, After: &svctypes.PolicySnapshot{Expression: "d", ExpiredAt: 100}},
+				}, nil,
+			)
+			mockPolicyService.EXPECT().ListThinBySubjectActionTemplate(
+				int64(1), []int64{1, 2, 3}, int64(0),
+			).Return([]svctypes.ThinPolicy{
+				{ID: 5, ActionPK: 1},
+				{ID: 6, ActionPK: 3},
+			}, nil)
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{5, 6}).Return(
+				[]svctypes.Policy{
+					{ID: 5, SubjectPK: 1, ActionPK: 1, Expression: "b", ExpiredAt: 100},
+					{ID: 6, SubjectPK: 1, ActionPK: 3, Expression: "d", ExpiredAt: 100},
+				}, nil,
+			)
+			mockPolicyService.EXPECT().AlterCustomPoliciesWithTx(
+				gomock.Any(), int64(1),
+				[]svctypes.Policy{{
+					Version: service.PolicyVersion, SubjectPK: 1, ActionPK: 2, Expression: "c", ExpiredAt: 100, Effect: 1,
+				}},
+				[]svctypes.Policy{{
+					Version: service.PolicyVersion, ID: 5, SubjectPK: 1, ActionPK: 1, Expression: "a", ExpiredAt: 100,
+				}},
+				[]int64{6},
+				gomock.Any(),
+			).Return(map[int64][]int64{}, nil)
+			mockPolicyHistoryService.EXPECT().BulkCreateWithTx(gomock.Any(), gomock.Len(3)).Return(nil)
+
+			db, dbMock := database.NewMockSqlxDB()
+			dbMock.ExpectBegin()
+			dbMock.ExpectCommit()
+			tx, _ := db.Beginx()
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return tx, nil
+			})
+
+			err := policyCtl.RollbackPolicies("test", "user", "test", 10, Operator{Actor: "admin"})
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("deny only", func() {
+			mockPolicyHistoryService.EXPECT().Get(int64(10)).Return(
+				svctypes.PolicyHistory{Version: 10, SubjectPK: 1, System: "test"}, nil,
+			)
+			mockPolicyHistoryService.EXPECT().ListChanges(int64(1), "test", int64(10), int64(0)).Return(
+				[]svctypes.PolicyChange{
+					// 版本之后新增的deny策略, 同一操作的allow策略不受影响
+					{ActionPK: 1, After: &svctypes.PolicySnapshot{Expression: "b", ExpiredAt: 100, Effect: 1}},
+				}, nil,
+			)
+			mockPolicyService.EXPECT().ListThinBySubjectActionTemplate(
+				int64(1), []int64{1}, int64(0),
+			).Return([]svctypes.ThinPolicy{
+				{ID: 5, ActionPK: 1},
+				{ID: 6, ActionPK: 1, Effect: 1},
+			}, nil)
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{6}).Return(
+				[]svctypes.Policy{
+					{ID: 6, SubjectPK: 1, ActionPK: 1, Expression: "b", ExpiredAt: 100, Effect: 1},
+				}, nil,
+			)
+			mockPolicyService.EXPECT().AlterCustomPoliciesWithTx(
+				gomock.Any(), int64(1), []svctypes.Policy{}, []svctypes.Policy{}, []int64{6}, gomock.Any(),
+			).Return(map[int64][]int64{}, nil)
+			mockPolicyHistoryService.EXPECT().BulkCreateWithTx(gomock.Any(), gomock.Len(1)).Return(nil)
+
+			db, dbMock := database.NewMockSqlxDB()
+			dbMock.ExpectBegin()
+			dbMock.ExpectCommit()
+			tx, _ := db.Beginx()
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return tx, nil
+			})
+
+			err := policyCtl.RollbackPolicies("test", "user", "test", 10, Operator{Actor: "admin"})
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("group rbac", func() {
+			mockSubjectService := mock.NewMockSubjectService(ctl)
+			mockSubjectService.EXPECT().GetPK("group", "g1").Return(int64(2), nil)
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().ListGroupAuthBySystemGroupPKs("test", []int64{2}).Return(
+				[]svctypes.GroupAuthType{{GroupPK: 2, AuthType: svctypes.AuthTypeABAC}}, nil,
+			)
+			policyCtl.subjectService = mockSubjectService
+			policyCtl.groupService = mockGroupService
+
+			mockPolicyHistoryService.EXPECT().Get(int64(10)).Return(
+				svctypes.PolicyHistory{Version: 10, SubjectPK: 2, System: "test"}, nil,
+			)
+			mockPolicyHistoryService.EXPECT().ListChanges(int64(2), "test", int64(10), int64(0)).Return(
+				[]svctypes.PolicyChange{{
+					ActionPK:   1,
+					TemplateID: 3,
+					Before: &svctypes.PolicySnapshot{
+						Resources: []svctypes.PolicyResource{{System: "test", Type: "host", ID: "1"}},
+					},
+				}}, nil,
+			)
+
+			patches.ApplyMethod(reflect.TypeOf(policyCtl), "AlterGroupPolicies",
+				func(
+					_ *policyController,
+					systemID, subjectType, subjectID string, templateID int64,
+					createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
+					resourceChangedActions []types.ResourceChangedAction,
+					groupAuthType int64,
+					operator Operator,
+				) error {
+					assert.Equal(GinkgoT(), int64(3), templateID)
+					assert.Empty(GinkgoT(), createPolicies)
+					assert.Empty(GinkgoT(), updatePolicies)
+					assert.Empty(GinkgoT(), deletePolicyIDs)
+					assert.Equal(GinkgoT(), []types.ResourceChangedAction{{
+						Resource:         types.ThinResourceNode{System: "test", Type: "host", ID: "1"},
+						CreatedActionIDs: []string{"view"},
+						DeletedActionIDs: []string{},
+					}}, resourceChangedActions)
+					assert.Equal(GinkgoT(), svctypes.AuthTypeAll, groupAuthType)
+					return nil
+				})
+
+			err := policyCtl.RollbackPolicies("test", "group", "g1", 10, Operator{Actor: "admin"})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+})
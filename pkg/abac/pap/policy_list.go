@@ -119,11 +119,13 @@ func (c *policyController) convertToSaaSPolicies(
 	saasPolicies := make([]types.SaaSPolicy, 0, len(policies))
 	for _, p := range policies {
 		saasPolicies = append(saasPolicies, types.SaaSPolicy{
-			Version:   p.Version,
-			ID:        p.ID,
-			System:    actionMap[p.ActionPK].System,
-			ActionID:  actionMap[p.ActionPK].ID,
-			ExpiredAt: p.ExpiredAt,
+			Version:     p.Version,
+			ID:          p.ID,
+			System:      actionMap[p.ActionPK].System,
+			ActionID:    actionMap[p.ActionPK].ID,
+			ExpiredAt:   p.ExpiredAt,
+			Effect:      svctypes.ConvertToPolicyEffectStr(p.Effect),
+			EffectiveAt: p.EffectiveAt,
 		})
 	}
 	return saasPolicies
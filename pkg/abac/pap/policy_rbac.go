@@ -11,6 +11,8 @@
 package pap
 
 import (
+	"fmt"
+
 	"github.com/TencentBlueKing/gopkg/collection/set"
 	"github.com/TencentBlueKing/gopkg/errorx"
 	"github.com/jmoiron/sqlx"
@@ -31,8 +33,126 @@ func (c *policyController) AlterGroupPolicies(
 	createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
 	resourceChangedActions []types.ResourceChangedAction,
 	groupAuthType int64,
+	operator Operator,
 ) (err error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "Alter")
+	// 生成统一的DB事务
+	tx, err := database.GenerateDefaultDBTx()
+	defer database.RollBackWithLog(tx)
+	if err != nil {
+		err = errorWrapf(err, "define tx fail")
+		return
+	}
+
+	result, err := c.alterGroupPoliciesWithTx(
+		tx, systemID, subjectType, subjectID, templateID,
+		createPolicies, updatePolicies, deletePolicyIDs,
+		resourceChangedActions, groupAuthType, operator,
+	)
+	if err != nil {
+		return
+	}
+
+	// DB事务提交
+	err = tx.Commit()
+	if err != nil {
+		err = errorWrapf(err, "tx commit fail")
+		return
+	}
+
+	subjectPK := result.subjectPK
+
+	// publish policy delete event
+	if len(deletePolicyIDs) > 0 {
+		c.eventProducer.PublishABACDeletePolicyEvent(deletePolicyIDs)
+	}
+	// publish the rbac delete pks to the engine redis queue
+	if len(result.deletedRBACPolicyPKs) > 0 {
+		c.eventProducer.PublishRBACDeletePolicyEvent(result.deletedRBACPolicyPKs)
+	}
+
+	// 4. 创建RBAC变更事件
+	c.createRBACGroupAlterEvent(subjectPK, result.resourceChangedContents)
+
+	// 5. 清理缓存
+	// 5.1 ABAC相关缓存
+	if len(createPolicies) > 0 || len(updatePolicies) > 0 || len(deletePolicyIDs) > 0 {
+		policy.DeleteSystemSubjectPKsFromCache(systemID, []int64{subjectPK})
+		// 只有自定义权限才需要清理Expression，模板权限不需要清理Expression，因为模板的Expression是复用的，有定时任务自动清理
+		if templateID == 0 && len(result.updatedActionPKExpressionPKs) > 0 {
+			expression.BatchDeleteExpressionsFromCache(result.updatedActionPKExpressionPKs)
+		}
+	}
+
+	// 5.2 RBAC相关缓存
+	for _, rcc := range result.resourceChangedContents {
+		cacheimpls.DeleteResourceAuthorizedGroupPKsCache(
+			systemID, rcc.ActionRelatedResourceTypePK, rcc.ResourceTypePK, rcc.ResourceID,
+		)
+	}
+
+	// 5.3 GroupAuthType相关缓存
+	// 只有AuthType被改变了才会进行缓存的清理
+	if result.authTypeChanged {
+		group.DeleteGroupAuthTypeCache(systemID, subjectPK)
+		cacheimpls.BatchDeleteGroupMemberSubjectSystemGroupCache(systemID, subjectPK)
+	}
+
+	return err
+}
+
+// DryRunAlterGroupPolicies 预演用户组策略变更, 执行所有校验与DB变更后回滚事务, 不发布事件也不清理缓存
+// NOTE: RBAC策略的实际变更由groupResourcePolicyService.Alter在事务中计算, 无法脱离写操作得到,
+// 所以预演会与正常变更一样持有行锁直到回滚, 且回滚不会归还已分配的自增ID, 调用方需要控制预演的频率
+func (c *policyController) DryRunAlterGroupPolicies(
+	systemID, subjectType, subjectID string, templateID int64,
+	createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
+	resourceChangedActions []types.ResourceChangedAction,
+	groupAuthType int64,
+	operator Operator,
+) (result PoliciesDryRunResult, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "DryRunAlterGroupPolicies")
+	tx, err := database.GenerateDefaultDBTx()
+	defer database.RollBackWithLog(tx)
+	if err != nil {
+		err = errorWrapf(err, "define tx fail")
+		return
+	}
+
+	alterResult, err := c.alterGroupPoliciesWithTx(
+		tx, systemID, subjectType, subjectID, templateID,
+		createPolicies, updatePolicies, deletePolicyIDs,
+		resourceChangedActions, groupAuthType, operator,
+	)
+	if err != nil {
+		return
+	}
+
+	// Note: 不提交事务, 由defer回滚
+	return c.newPoliciesDryRunResult(alterResult)
+}
+
+// groupPoliciesAlterResult 用户组策略在事务中变更的结果, 用于事务提交后发布事件与清理缓存
+type groupPoliciesAlterResult struct {
+	subjectPK                    int64
+	actionPKMap                  map[string]int64
+	histories                    []svctypes.PolicyHistory
+	updatedActionPKExpressionPKs map[int64][]int64
+	resourceChangedContents      []svctypes.ResourceChangedContent
+	deletedRBACPolicyPKs         []int64
+	authTypeChanged              bool
+}
+
+// alterGroupPoliciesWithTx 执行所有的校验并在事务中变更用户组的ABAC/RBAC策略, 不提交事务
+func (c *policyController) alterGroupPoliciesWithTx(
+	tx *sqlx.Tx,
+	systemID, subjectType, subjectID string, templateID int64,
+	createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
+	resourceChangedActions []types.ResourceChangedAction,
+	groupAuthType int64,
+	operator Operator,
+) (result groupPoliciesAlterResult, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "alterGroupPoliciesWithTx")
 	// 0. 通用处理
 	// 查询subject、action 相关的信息
 	subjectPK, actionPKMap, actionPKWithResourceTypeSet, err := c.querySubjectActionForAlterPolicies(
@@ -42,19 +162,24 @@ func (c *policyController) AlterGroupPolicies(
 		err = errorWrapf(err, "c.querySubjectActionForAlterPolicies systemID=`%s` fail", systemID)
 		return
 	}
-	// 生成统一的DB事务
-	tx, err := database.GenerateDefaultDBTx()
-	defer database.RollBackWithLog(tx)
+	result.subjectPK = subjectPK
+	result.actionPKMap = actionPKMap
+
+	// 检查新增的操作是否违反职责分离约束
+	createdActionPKs := listCreatedActionPKs(createPolicies, resourceChangedActions, actionPKMap)
+	err = c.sodConstraintService.CheckSubjectActions(subjectPK, createdActionPKs, deletePolicyIDs)
 	if err != nil {
-		err = errorWrapf(err, "define tx fail")
+		err = errorWrapf(err, "sodConstraintService.CheckSubjectActions subjectPK=`%d`, actionPKs=`%+v` fail",
+			subjectPK, createdActionPKs)
 		return
 	}
 
 	// 1. 处理ABAC策略
-	updatedActionPKExpressionPKs, err := c.alterABACPolicies(
-		tx, subjectPK, templateID,
+	result.updatedActionPKExpressionPKs, result.histories, err = c.alterABACPolicies(
+		tx, systemID, subjectPK, templateID,
 		createPolicies, updatePolicies, deletePolicyIDs,
 		actionPKMap, actionPKWithResourceTypeSet,
+		operator,
 	)
 	if err != nil {
 		err = errorWrapf(err, "c.alterABACPolicies systemID=`%s` subjectPK=`%d` fail", systemID, subjectPK)
@@ -66,61 +191,56 @@ func (c *policyController) AlterGroupPolicies(
 	for _, actionPK := range actionPKMap {
 		systemActionPKSet.Add(actionPK)
 	}
-	resourceChangedContents, err := c.alterRBACPolicies(
+	var rbacHistories []svctypes.PolicyHistory
+	result.resourceChangedContents, result.deletedRBACPolicyPKs, rbacHistories, err = c.alterRBACPolicies(
 		tx,
 		subjectPK,
 		templateID,
 		systemID,
 		systemActionPKSet,
 		resourceChangedActions,
+		operator,
 	)
 	if err != nil {
 		err = errorWrapf(err, "c.alterRBACPolicy systemID=`%s` subjectPK=`%d` fail", systemID, subjectPK)
 		return
 	}
+	result.histories = append(result.histories, rbacHistories...)
 
 	// 3. 处理GroupAuthType
-	changed, err := c.groupService.AlterGroupAuthType(tx, systemID, subjectPK, groupAuthType)
+	result.authTypeChanged, err = c.groupService.AlterGroupAuthType(tx, systemID, subjectPK, groupAuthType)
 	if err != nil {
 		err = errorWrapf(err, "c.alterRBACPolicy systemID=`%s` subjectPK=`%d` fail", systemID, subjectPK)
 		return
 	}
 
-	// DB事务提交
-	err = tx.Commit()
-	if err != nil {
-		err = errorWrapf(err, "tx commit fail")
-		return
-	}
-
-	// 4. 创建RBAC变更事件
-	c.createRBACGroupAlterEvent(subjectPK, resourceChangedContents)
+	return result, nil
+}
 
-	// 5. 清理缓存
-	// 5.1 ABAC相关缓存
-	if len(createPolicies) > 0 || len(updatePolicies) > 0 || len(deletePolicyIDs) > 0 {
-		policy.DeleteSystemSubjectPKsFromCache(systemID, []int64{subjectPK})
-		// 只有自定义权限才需要清理Expression，模板权限不需要清理Expression，因为模板的Expression是复用的，有定时任务自动清理
-		if templateID == 0 && len(updatedActionPKExpressionPKs) > 0 {
-			expression.BatchDeleteExpressionsFromCache(updatedActionPKExpressionPKs)
+// listCreatedActionPKs 新增授权的操作, 包括ABAC策略(不含deny策略)与RBAC策略
+func listCreatedActionPKs(
+	createPolicies []types.Policy,
+	resourceChangedActions []types.ResourceChangedAction,
+	actionPKMap map[string]int64,
+) []int64 {
+	actionPKSet := set.NewInt64Set()
+	for _, p := range createPolicies {
+		if p.Effect == svctypes.PolicyEffectDeny {
+			continue
+		}
+		if actionPK, ok := actionPKMap[p.Action.ID]; ok {
+			actionPKSet.Add(actionPK)
 		}
 	}
 
-	// 5.2 RBAC相关缓存
-	for _, rcc := range resourceChangedContents {
-		cacheimpls.DeleteResourceAuthorizedGroupPKsCache(
-			systemID, rcc.ActionRelatedResourceTypePK, rcc.ResourceTypePK, rcc.ResourceID,
-		)
-	}
-
-	// 5.3 GroupAuthType相关缓存
-	// 只有AuthType被改变了才会进行缓存的清理
-	if changed {
-		group.DeleteGroupAuthTypeCache(systemID, subjectPK)
-		cacheimpls.BatchDeleteGroupMemberSubjectSystemGroupCache(systemID, subjectPK)
+	for _, rca := range resourceChangedActions {
+		for _, actionID := range rca.CreatedActionIDs {
+			if actionPK, ok := actionPKMap[actionID]; ok {
+				actionPKSet.Add(actionPK)
+			}
+		}
 	}
-
-	return err
+	return actionPKSet.ToSlice()
 }
 
 // createRBACGroupAlterEvent 创建用户组变更事件
@@ -159,13 +279,15 @@ func (c *policyController) createRBACGroupAlterEvent(
 
 func (c *policyController) alterABACPolicies(
 	tx *sqlx.Tx,
+	systemID string,
 	subjectPK, templateID int64,
 	createPolicies []types.Policy,
 	updatePolicies []types.Policy,
 	deletePolicyIDs []int64,
 	actionPKMap map[string]int64,
 	actionPKWithResourceTypeSet *set.Int64Set,
-) (updatedActionPKExpressionPKs map[int64][]int64, err error) {
+	operator Operator,
+) (updatedActionPKExpressionPKs map[int64][]int64, histories []svctypes.PolicyHistory, err error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "alterABACPolicies")
 
 	// 避免无需变更情况，也进行各种数据查询
@@ -197,6 +319,18 @@ func (c *policyController) alterABACPolicies(
 		return
 	}
 
+	// 查询变更前的策略, 用于记录变更历史
+	beforePolicies, err := c.listPoliciesBeforeAlter(subjectPK, ups, deletePolicyIDs)
+	if err != nil {
+		err = errorWrapf(err, "c.listPoliciesBeforeAlter subjectPK=`%d` fail", subjectPK)
+		return
+	}
+	histories = newPolicyHistories(
+		subjectPK, systemID, templateID,
+		cps, ups, beforePolicies, deletePolicyIDs,
+		actionPKWithResourceTypeSet, operator,
+	)
+
 	// 自定义权限
 	if templateID == 0 {
 		// service执行 create, update, delete
@@ -207,8 +341,12 @@ func (c *policyController) alterABACPolicies(
 			err = errorWrapf(err, "policyService.AlterPolicies subjectPK=`%d` fail", subjectPK)
 			return
 		}
-		// publish policy delete event
-		c.eventProducer.PublishABACDeletePolicyEvent(deletePolicyIDs)
+		// 记录变更历史
+		err = c.policyHistoryService.BulkCreateWithTx(tx, histories)
+		if err != nil {
+			err = errorWrapf(err, "policyHistoryService.BulkCreateWithTx histories=`%+v` fail", histories)
+			return
+		}
 		// Note: 这里必须直接返回，否则会走到模板权限逻辑
 		return
 	}
@@ -222,9 +360,6 @@ func (c *policyController) alterABACPolicies(
 			err = errorWrapf(err, "policyService.CreateAndDeleteTemplatePolicies subjectPK=`%d` fail", subjectPK)
 			return
 		}
-
-		// publish policy delete event
-		c.eventProducer.PublishABACDeletePolicyEvent(deletePolicyIDs)
 	}
 	// 更新
 	if len(updatePolicies) > 0 {
@@ -235,7 +370,14 @@ func (c *policyController) alterABACPolicies(
 		}
 	}
 
-	return updatedActionPKExpressionPKs, nil
+	// 记录变更历史
+	err = c.policyHistoryService.BulkCreateWithTx(tx, histories)
+	if err != nil {
+		err = errorWrapf(err, "policyHistoryService.BulkCreateWithTx histories=`%+v` fail", histories)
+		return
+	}
+
+	return updatedActionPKExpressionPKs, histories, nil
 }
 
 func (c *policyController) alterRBACPolicies(
@@ -244,7 +386,13 @@ func (c *policyController) alterRBACPolicies(
 	systemID string,
 	systemActionPKSet *set.Int64Set,
 	resourceChangedActions []types.ResourceChangedAction,
-) (resourceChangedContents []svctypes.ResourceChangedContent, err error) {
+	operator Operator,
+) (
+	resourceChangedContents []svctypes.ResourceChangedContent,
+	deletedPolicyPKs []int64,
+	histories []svctypes.PolicyHistory,
+	err error,
+) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "alterRBACPolicy")
 
 	// 避免无需变更情况，也进行各种数据查询
@@ -255,7 +403,7 @@ func (c *policyController) alterRBACPolicies(
 	// 1. 将ResourceChangedAction数据转换为service层函数需要的ResourceChangedContent数据
 	resourceChangedContents, err = c.convertToResourceChangedContent(systemID, resourceChangedActions)
 	if err != nil {
-		return resourceChangedContents, errorWrapf(
+		return resourceChangedContents, nil, nil, errorWrapf(
 			err,
 			"convertToResourceChangedContent systemID=`%s` resourceChangedActions=`%v` fail",
 			systemID, resourceChangedActions,
@@ -263,8 +411,7 @@ func (c *policyController) alterRBACPolicies(
 	}
 
 	// 2. 变更RBAC策略
-	var deletedPolicyPKs []int64
-	deletedPolicyPKs, err = c.groupResourcePolicyService.Alter(
+	deletedPolicyPKs, changedContents, err := c.groupResourcePolicyService.Alter(
 		tx,
 		groupPK,
 		templateID,
@@ -273,7 +420,7 @@ func (c *policyController) alterRBACPolicies(
 		resourceChangedContents,
 	)
 	if err != nil {
-		return resourceChangedContents, errorWrapf(
+		return resourceChangedContents, nil, nil, errorWrapf(
 			err,
 			"groupResourcePolicyService.Alter "+
 				"groupPK=`%d` templateID=`%d` system=`%s` resourceChangedContents=`%v` fail",
@@ -281,10 +428,21 @@ func (c *policyController) alterRBACPolicies(
 		)
 	}
 
-	// publish the rbac delete pks to the engine redis queue
-	c.eventProducer.PublishRBACDeletePolicyEvent(deletedPolicyPKs)
+	// 3. 记录变更历史, 只记录实际发生变化的授权
+	histories, err = newRBACPolicyHistories(groupPK, systemID, templateID, changedContents, operator)
+	if err != nil {
+		return resourceChangedContents, nil, nil, errorWrapf(
+			err, "newRBACPolicyHistories groupPK=`%d` changedContents=`%v` fail", groupPK, changedContents,
+		)
+	}
+	err = c.policyHistoryService.BulkCreateWithTx(tx, histories)
+	if err != nil {
+		return resourceChangedContents, nil, nil, errorWrapf(
+			err, "policyHistoryService.BulkCreateWithTx histories=`%+v` fail", histories,
+		)
+	}
 
-	return resourceChangedContents, nil
+	return resourceChangedContents, deletedPolicyPKs, histories, nil
 }
 
 func (c *policyController) queryResourceTypePK(
@@ -294,16 +452,24 @@ func (c *policyController) queryResourceTypePK(
 
 	resourceTypePKMap = make(map[string]int64, len(*resourceChangedActions))
 	for _, rca := range *resourceChangedActions {
-		pk, err := cacheimpls.GetLocalResourceTypePK(rca.Resource.System, rca.Resource.Type)
-		if err != nil {
-			return nil, errorWrapf(
-				err,
-				"cacheimpls.GetLocalResourceTypePK system=`%s` type=`%s` fail",
-				rca.Resource.System,
-				rca.Resource.Type,
-			)
+		resources := append([]types.ThinResourceNode{rca.Resource}, rca.RelatedResources...)
+		for _, resource := range resources {
+			key := resource.System + ":" + resource.Type
+			if _, ok := resourceTypePKMap[key]; ok {
+				continue
+			}
+
+			pk, err := cacheimpls.GetLocalResourceTypePK(resource.System, resource.Type)
+			if err != nil {
+				return nil, errorWrapf(
+					err,
+					"cacheimpls.GetLocalResourceTypePK system=`%s` type=`%s` fail",
+					resource.System,
+					resource.Type,
+				)
+			}
+			resourceTypePKMap[key] = pk
 		}
-		resourceTypePKMap[rca.Resource.System+":"+rca.Resource.Type] = pk
 	}
 
 	return resourceTypePKMap, nil
@@ -337,68 +503,74 @@ func (c *policyController) queryActionDetail(
 }
 
 type changedAction struct {
-	CreatedActionPKs []int64
-	DeletedActionPKs []int64
+	// 操作关联的资源类型PK, 按操作关联资源类型的顺序
+	RelatedResourceTypePKs []int64
+	CreatedActionPKs       []int64
+	DeletedActionPKs       []int64
 }
 
 func (c *policyController) groupByActionRelatedResourceTypePK(
 	createdActionIDs, deletedActionIDs []string,
 	actionDetailMap *map[string]svctypes.ActionDetail,
-) (relatedResourceTypePKToChangedActionMap map[int64]changedAction, err error) {
-	// 记录每个relatedResourceTypePK对应的changedAction
-	changedActions := make([]changedAction, 0, len(createdActionIDs)+len(deletedActionIDs))
-	// Note: relateResourceTypePKToIndex用于记录其对应ChangedAction在changedActions数组里的位置
-	relateResourceTypePKToIndex := map[int64]int{}
-
-	for _, actionID := range createdActionIDs {
+	resourceCount int,
+) (changedActions []changedAction, err error) {
+	// 记录每组relatedResourceTypePKs对应的changedAction
+	changedActions = make([]changedAction, 0, 2)
+	// Note: relateResourceTypePKsToIndex用于记录其对应ChangedAction在changedActions数组里的位置
+	relateResourceTypePKsToIndex := map[string]int{}
+
+	getIndex := func(actionID string) (int, error) {
 		detail := (*actionDetailMap)[actionID]
-		// Note: 由于只能关联一个资源类型的操作才可配置RBAC权限，所以这里直接取第一个关联的资源类型
-		// pk := detail.ResourceTypes[0].PK
-		resourceType := detail.ResourceTypes[0]
-		pk, err := cacheimpls.GetLocalResourceTypePK(resourceType.System, resourceType.ID)
-		if err != nil {
-			return nil, err
+		// Note: 授权的资源实例组合需要与操作关联的资源类型一一对应
+		if len(detail.ResourceTypes) != resourceCount {
+			return 0, fmt.Errorf(
+				"action `%s` related %d resource types, but got %d resources",
+				actionID, len(detail.ResourceTypes), resourceCount,
+			)
 		}
 
-		if _, ok := relateResourceTypePKToIndex[pk]; !ok {
+		pks := make([]int64, 0, len(detail.ResourceTypes))
+		for _, resourceType := range detail.ResourceTypes {
+			pk, err := cacheimpls.GetLocalResourceTypePK(resourceType.System, resourceType.ID)
+			if err != nil {
+				return 0, err
+			}
+			pks = append(pks, pk)
+		}
+
+		key := fmt.Sprint(pks)
+		if _, ok := relateResourceTypePKsToIndex[key]; !ok {
 			changedActions = append(changedActions, changedAction{
-				CreatedActionPKs: []int64{},
-				DeletedActionPKs: []int64{},
+				RelatedResourceTypePKs: pks,
+				CreatedActionPKs:       []int64{},
+				DeletedActionPKs:       []int64{},
 			})
-			relateResourceTypePKToIndex[pk] = len(changedActions) - 1
+			relateResourceTypePKsToIndex[key] = len(changedActions) - 1
 		}
-
-		idx := relateResourceTypePKToIndex[pk]
-		changedActions[idx].CreatedActionPKs = append(changedActions[idx].CreatedActionPKs, detail.PK)
+		return relateResourceTypePKsToIndex[key], nil
 	}
 
-	for _, actionID := range deletedActionIDs {
-		detail := (*actionDetailMap)[actionID]
-		// Note: 由于只能关联一个资源类型的操作才可配置RBAC权限，所以这里直接取第一个关联的资源类型
-		resourceType := detail.ResourceTypes[0]
-		pk, err := cacheimpls.GetLocalResourceTypePK(resourceType.System, resourceType.ID)
+	for _, actionID := range createdActionIDs {
+		idx, err := getIndex(actionID)
 		if err != nil {
 			return nil, err
 		}
-
-		if _, ok := relateResourceTypePKToIndex[pk]; !ok {
-			changedActions = append(changedActions, changedAction{
-				CreatedActionPKs: []int64{},
-				DeletedActionPKs: []int64{},
-			})
-			relateResourceTypePKToIndex[pk] = len(changedActions) - 1
-		}
-
-		idx := relateResourceTypePKToIndex[pk]
-		changedActions[idx].DeletedActionPKs = append(changedActions[idx].DeletedActionPKs, detail.PK)
+		changedActions[idx].CreatedActionPKs = append(
+			changedActions[idx].CreatedActionPKs, (*actionDetailMap)[actionID].PK,
+		)
 	}
 
-	relatedResourceTypePKToChangedActionMap = make(map[int64]changedAction, len(relateResourceTypePKToIndex))
-	for pk, idx := range relateResourceTypePKToIndex {
-		relatedResourceTypePKToChangedActionMap[pk] = changedActions[idx]
+	for _, actionID := range deletedActionIDs {
+		idx, err := getIndex(actionID)
+		if err != nil {
+			return nil, err
+		}
+		changedActions[idx].DeletedActionPKs = append(
+			changedActions[idx].DeletedActionPKs, (*actionDetailMap)[actionID].PK,
+		)
 	}
 
-	return relatedResourceTypePKToChangedActionMap, nil
+	return changedActions, nil
 }
 
 // DeleteByActionID 通过ActionID批量删除策略
@@ -486,9 +658,9 @@ func (c *policyController) convertToResourceChangedContent(
 	// 3. 组装数据
 	resourceChangedContents = make([]svctypes.ResourceChangedContent, 0, 3*len(resourceChangedActions))
 	for _, rca := range resourceChangedActions {
-		// 根据ActionRelatedResourceTypePK对Action进行分组
-		relatedResourceTypePKToChangedActionMap, err := c.groupByActionRelatedResourceTypePK(
-			rca.CreatedActionIDs, rca.DeletedActionIDs, &actionDetailMap,
+		// 根据操作关联的资源类型PK对Action进行分组
+		changedActions, err := c.groupByActionRelatedResourceTypePK(
+			rca.CreatedActionIDs, rca.DeletedActionIDs, &actionDetailMap, 1+len(rca.RelatedResources),
 		)
 		if err != nil {
 			return nil, errorWrapf(
@@ -498,13 +670,23 @@ func (c *policyController) convertToResourceChangedContent(
 
 		// 组织最终数据
 		resourceTypePK := resourceTypePKMap[rca.Resource.System+":"+rca.Resource.Type]
-		for relatedResourceTypePK, ca := range relatedResourceTypePKToChangedActionMap {
+		for _, ca := range changedActions {
+			var relatedResources []svctypes.RelatedResource
+			for i, rr := range rca.RelatedResources {
+				relatedResources = append(relatedResources, svctypes.RelatedResource{
+					ActionRelatedResourceTypePK: ca.RelatedResourceTypePKs[i+1],
+					ResourceTypePK:              resourceTypePKMap[rr.System+":"+rr.Type],
+					ResourceID:                  rr.ID,
+				})
+			}
+
 			resourceChangedContents = append(resourceChangedContents, svctypes.ResourceChangedContent{
 				ResourceTypePK:              resourceTypePK,
 				ResourceID:                  rca.Resource.ID,
-				ActionRelatedResourceTypePK: relatedResourceTypePK,
+				ActionRelatedResourceTypePK: ca.RelatedResourceTypePKs[0],
 				CreatedActionPKs:            ca.CreatedActionPKs,
 				DeletedActionPKs:            ca.DeletedActionPKs,
+				RelatedResources:            relatedResources,
 			})
 		}
 	}
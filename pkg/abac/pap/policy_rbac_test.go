@@ -0,0 +1,115 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"errors"
+
+	"github.com/agiledragon/gomonkey/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/types"
+	"iam/pkg/cacheimpls"
+	svctypes "iam/pkg/service/types"
+)
+
+var _ = Describe("PolicyRBAC", func() {
+	Describe("convertToResourceChangedContent", func() {
+		var patches *gomonkey.Patches
+		var c *policyController
+		BeforeEach(func() {
+			c = &policyController{}
+
+			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalResourceTypePK, func(_, _type string) (int64, error) {
+				switch _type {
+				case "app":
+					return 1, nil
+				case "cluster":
+					return 2, nil
+				case "project":
+					return 3, nil
+				default:
+					return 0, errors.New("not found")
+				}
+			})
+			patches.ApplyFunc(cacheimpls.GetActionDetail,
+				func(_, actionID string) (svctypes.ActionDetail, error) {
+					switch actionID {
+					case "view_app":
+						return svctypes.ActionDetail{
+							PK:            1,
+							ResourceTypes: []svctypes.ThinActionResourceType{{System: "test", ID: "app"}},
+						}, nil
+					case "deploy_app":
+						return svctypes.ActionDetail{
+							PK: 2,
+							ResourceTypes: []svctypes.ThinActionResourceType{
+								{System: "test", ID: "app"},
+								{System: "test", ID: "cluster"},
+							},
+						}, nil
+					default:
+						return svctypes.ActionDetail{}, errors.New("not found")
+					}
+				})
+		})
+		AfterEach(func() {
+			patches.Reset()
+		})
+
+		It("single resource type", func() {
+			rccs, err := c.convertToResourceChangedContent("test", []types.ResourceChangedAction{{
+				Resource:         types.ThinResourceNode{System: "test", Type: "app", ID: "a1"},
+				CreatedActionIDs: []string{"view_app"},
+				DeletedActionIDs: []string{},
+			}})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []svctypes.ResourceChangedContent{{
+				ResourceTypePK:              1,
+				ResourceID:                  "a1",
+				ActionRelatedResourceTypePK: 1,
+				CreatedActionPKs:            []int64{1},
+				DeletedActionPKs:            []int64{},
+			}}, rccs)
+		})
+
+		It("multiple resource types", func() {
+			rccs, err := c.convertToResourceChangedContent("test", []types.ResourceChangedAction{{
+				Resource:         types.ThinResourceNode{System: "test", Type: "app", ID: "a1"},
+				CreatedActionIDs: []string{"deploy_app"},
+				DeletedActionIDs: []string{},
+				RelatedResources: []types.ThinResourceNode{{System: "test", Type: "project", ID: "p1"}},
+			}})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []svctypes.ResourceChangedContent{{
+				ResourceTypePK:              1,
+				ResourceID:                  "a1",
+				ActionRelatedResourceTypePK: 1,
+				CreatedActionPKs:            []int64{2},
+				DeletedActionPKs:            []int64{},
+				RelatedResources: []svctypes.RelatedResource{
+					{ActionRelatedResourceTypePK: 2, ResourceTypePK: 3, ResourceID: "p1"},
+				},
+			}}, rccs)
+		})
+
+		It("resources count not match", func() {
+			_, err := c.convertToResourceChangedContent("test", []types.ResourceChangedAction{{
+				Resource:         types.ThinResourceNode{System: "test", Type: "app", ID: "a1"},
+				CreatedActionIDs: []string{"view_app", "deploy_app"},
+				DeletedActionIDs: []string{},
+			}})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "related 2 resource types, but got 1 resources")
+		})
+	})
+})
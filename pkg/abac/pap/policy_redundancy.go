@@ -0,0 +1,340 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/abac/pdp/translate"
+	"iam/pkg/abac/pip"
+	"iam/pkg/cacheimpls"
+	svctypes "iam/pkg/service/types"
+)
+
+const (
+	PolicyRedundancyRedundant   = "redundant"
+	PolicyRedundancyOverlapping = "overlapping"
+)
+
+// anyExpressionPK 操作不关联资源类型时策略的expression pk, 等价于any
+const anyExpressionPK int64 = -1
+
+// groupAuthPolicy 用户组的ABAC策略, ExpiredAt为策略与用户组成员关系过期时间中较早的一个
+type groupAuthPolicy struct {
+	GroupPK     int64
+	ExpiredAt   int64
+	EffectiveAt int64
+	Expression  translate.ExprCell
+}
+
+/*
+AnalyzePolicyRedundancy 分析subject在系统下的自定义权限与其生效的用户组权限的关系:
+
+1. 只分析未过期的allow自定义权限, 用户组权限包括直接加入以及通过部门继承的用户组的ABAC策略
+2. 自定义权限的表达式被用户组权限覆盖, 且用户组权限不晚于其生效, 不早于其过期时, 为冗余权限
+3. 否则与用户组权限存在交集时, 为重叠权限
+4. cleanup为true时通过DeleteByIDs删除冗余权限, 重叠权限只做报告
+
+NOTE: 表达式的覆盖判断是保守的, 无法确定时不会判断为冗余
+*/
+func (c *policyController) AnalyzePolicyRedundancy(
+	system, subjectType, subjectID string, cleanup bool, operator Operator,
+) (report PolicyRedundancyReport, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyCTL, "AnalyzePolicyRedundancy")
+	report = PolicyRedundancyReport{Policies: []PolicyRedundancy{}, DeletedPolicyIDs: []int64{}}
+
+	pk, err := c.subjectService.GetPK(subjectType, subjectID)
+	if err != nil {
+		err = errorWrapf(err, "subjectService.GetPK subjectType=`%s`, subjectID=`%s` fail", subjectType, subjectID)
+		return report, err
+	}
+
+	policies, actionIDMap, err := c.listEffectCustomPolicies(system, pk)
+	if err != nil {
+		err = errorWrapf(err, "listEffectCustomPolicies system=`%s`, subjectPK=`%d` fail", system, pk)
+		return report, err
+	}
+	if len(policies) == 0 {
+		return report, nil
+	}
+
+	groupExpiredAtMap, err := listEffectGroupExpiredAt(system, pk)
+	if err != nil {
+		err = errorWrapf(err, "listEffectGroupExpiredAt system=`%s`, subjectPK=`%d` fail", system, pk)
+		return report, err
+	}
+	if len(groupExpiredAtMap) == 0 {
+		return report, nil
+	}
+
+	actionPolicies := map[int64][]svctypes.Policy{}
+	actionPKs := make([]int64, 0, len(policies))
+	for _, p := range policies {
+		if _, ok := actionPolicies[p.ActionPK]; !ok {
+			actionPKs = append(actionPKs, p.ActionPK)
+		}
+		actionPolicies[p.ActionPK] = append(actionPolicies[p.ActionPK], p)
+	}
+
+	groupSubjects := map[int64]Subject{}
+	redundantPolicyIDs := make([]int64, 0, len(policies))
+	for _, actionPK := range actionPKs {
+		groupPolicies, err := c.listGroupAuthPolicies(actionPK, groupExpiredAtMap)
+		if err != nil {
+			err = errorWrapf(err, "listGroupAuthPolicies actionPK=`%d` fail", actionPK)
+			return report, err
+		}
+		if len(groupPolicies) == 0 {
+			continue
+		}
+
+		for _, p := range actionPolicies[actionPK] {
+			redundancy, ok, err := analyzePolicyRedundancy(p, groupPolicies, groupSubjects)
+			if err != nil {
+				err = errorWrapf(err, "analyzePolicyRedundancy policyID=`%d` fail", p.ID)
+				return report, err
+			}
+			if !ok {
+				continue
+			}
+
+			redundancy.ActionID = actionIDMap[actionPK]
+			report.Policies = append(report.Policies, redundancy)
+			if redundancy.Type == PolicyRedundancyRedundant {
+				redundantPolicyIDs = append(redundantPolicyIDs, p.ID)
+			}
+		}
+	}
+
+	if cleanup && len(redundantPolicyIDs) > 0 {
+		err = c.DeleteByIDs(system, subjectType, subjectID, redundantPolicyIDs, operator)
+		if err != nil {
+			err = errorWrapf(err, "DeleteByIDs system=`%s`, subjectType=`%s`, subjectID=`%s`, policyIDs=`%+v` fail",
+				system, subjectType, subjectID, redundantPolicyIDs)
+			return report, err
+		}
+		report.DeletedPolicyIDs = redundantPolicyIDs
+	}
+
+	return report, nil
+}
+
+// listEffectCustomPolicies 查询subject在系统下未过期的allow自定义权限, 以及系统操作的pk与id的映射
+func (c *policyController) listEffectCustomPolicies(
+	system string, subjectPK int64,
+) ([]svctypes.Policy, map[int64]string, error) {
+	actions, err := c.actionService.ListThinActionBySystem(system)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(actions) == 0 {
+		return nil, nil, nil
+	}
+
+	actionIDMap := make(map[int64]string, len(actions))
+	actionPKs := make([]int64, 0, len(actions))
+	for _, a := range actions {
+		actionIDMap[a.PK] = a.ID
+		actionPKs = append(actionPKs, a.PK)
+	}
+
+	thinPolicies, err := c.policyService.ListThinBySubjectActionTemplate(subjectPK, actionPKs, 0)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, actionIDMap, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nowUnix := time.Now().Unix()
+	pks := make([]int64, 0, len(thinPolicies))
+	for _, p := range thinPolicies {
+		if p.ExpiredAt > nowUnix && p.Effect == svctypes.PolicyEffectAllow {
+			pks = append(pks, p.ID)
+		}
+	}
+	if len(pks) == 0 {
+		return nil, actionIDMap, nil
+	}
+
+	policies, err := c.policyService.ListBySubjectPKAndPKs(subjectPK, pks)
+	if err != nil {
+		return nil, nil, err
+	}
+	return policies, actionIDMap, nil
+}
+
+// listEffectGroupExpiredAt 查询subject直接加入以及通过部门继承的, 在系统下有权限的用户组, 返回用户组pk与成员过期时间的映射
+func listEffectGroupExpiredAt(system string, subjectPK int64) (map[int64]int64, error) {
+	departments, ancestors, err := pip.GetSubjectDepartmentPKs(subjectPK)
+	if err != nil {
+		return nil, err
+	}
+
+	subjectPKs := make([]int64, 0, 1+len(departments)+len(ancestors))
+	subjectPKs = append(subjectPKs, subjectPK)
+	subjectPKs = append(subjectPKs, departments...)
+	subjectPKs = append(subjectPKs, ancestors...)
+
+	subjectGroups, err := cacheimpls.ListSystemSubjectEffectGroups(system, subjectPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 通过多个部门加入同一个用户组时, 取最晚的过期时间
+	groupExpiredAtMap := make(map[int64]int64, len(subjectGroups))
+	for _, sg := range subjectGroups {
+		if sg.ExpiredAt > groupExpiredAtMap[sg.GroupPK] {
+			groupExpiredAtMap[sg.GroupPK] = sg.ExpiredAt
+		}
+	}
+	return groupExpiredAtMap, nil
+}
+
+// listGroupAuthPolicies 查询用户组在操作下未过期的allow策略, 并转换表达式
+func (c *policyController) listGroupAuthPolicies(
+	actionPK int64, groupExpiredAtMap map[int64]int64,
+) ([]groupAuthPolicy, error) {
+	groupPKs := make([]int64, 0, len(groupExpiredAtMap))
+	for groupPK := range groupExpiredAtMap {
+		groupPKs = append(groupPKs, groupPK)
+	}
+
+	authPolicies, err := c.policyService.ListAuthBySubjectAction(groupPKs, actionPK)
+	if err != nil {
+		return nil, err
+	}
+
+	expressionPKs := make([]int64, 0, len(authPolicies))
+	for _, p := range authPolicies {
+		if p.Effect == svctypes.PolicyEffectAllow && p.ExpressionPK != anyExpressionPK {
+			expressionPKs = append(expressionPKs, p.ExpressionPK)
+		}
+	}
+
+	expressionMap := map[int64]string{anyExpressionPK: ""}
+	if len(expressionPKs) > 0 {
+		expressions, err := c.policyService.ListExpressionByPKs(expressionPKs)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range expressions {
+			expressionMap[e.PK] = e.Expression
+		}
+	}
+
+	translatedMap := make(map[int64]translate.ExprCell, len(expressionMap))
+	policies := make([]groupAuthPolicy, 0, len(authPolicies))
+	for _, p := range authPolicies {
+		if p.Effect != svctypes.PolicyEffectAllow {
+			continue
+		}
+
+		expr, ok := translatedMap[p.ExpressionPK]
+		if !ok {
+			expr, err = translate.PolicyExpressionTranslate(expressionMap[p.ExpressionPK], false)
+			if err != nil {
+				return nil, err
+			}
+			translatedMap[p.ExpressionPK] = expr
+		}
+
+		expiredAt := p.ExpiredAt
+		if groupExpiredAt := groupExpiredAtMap[p.SubjectPK]; groupExpiredAt < expiredAt {
+			expiredAt = groupExpiredAt
+		}
+		policies = append(policies, groupAuthPolicy{
+			GroupPK:     p.SubjectPK,
+			ExpiredAt:   expiredAt,
+			EffectiveAt: p.EffectiveAt,
+			Expression:  expr,
+		})
+	}
+	return policies, nil
+}
+
+// analyzePolicyRedundancy 分析单条自定义权限, ok为false表示与用户组权限无关
+func analyzePolicyRedundancy(
+	policy svctypes.Policy, groupPolicies []groupAuthPolicy, groupSubjects map[int64]Subject,
+) (redundancy PolicyRedundancy, ok bool, err error) {
+	expr, err := translate.PolicyExpressionTranslate(policy.Expression, false)
+	if err != nil {
+		return redundancy, false, err
+	}
+
+	// 只有生效时间覆盖自定义权限的用户组权限才能用于判断冗余
+	effectiveAt := time.Now().Unix()
+	if policy.EffectiveAt > effectiveAt {
+		effectiveAt = policy.EffectiveAt
+	}
+
+	coveringExprs := make([]translate.ExprCell, 0, len(groupPolicies))
+	coveringGroupPKs := make([]int64, 0, len(groupPolicies))
+	overlappingGroupPKs := make([]int64, 0, len(groupPolicies))
+	for _, gp := range groupPolicies {
+		if !translate.Overlaps(gp.Expression, expr) && !translate.Covers(gp.Expression, expr) {
+			continue
+		}
+		overlappingGroupPKs = appendUniqueInt64(overlappingGroupPKs, gp.GroupPK)
+
+		if gp.EffectiveAt <= effectiveAt && gp.ExpiredAt >= policy.ExpiredAt {
+			coveringExprs = append(coveringExprs, gp.Expression)
+			coveringGroupPKs = appendUniqueInt64(coveringGroupPKs, gp.GroupPK)
+		}
+	}
+	if len(overlappingGroupPKs) == 0 {
+		return redundancy, false, nil
+	}
+
+	// 多个用户组权限合并后覆盖自定义权限时, 同样为冗余
+	redundancy = PolicyRedundancy{
+		PolicyID:   policy.ID,
+		Expression: policy.Expression,
+		ExpiredAt:  policy.ExpiredAt,
+		Type:       PolicyRedundancyOverlapping,
+	}
+	groupPKs := overlappingGroupPKs
+	if len(coveringExprs) > 0 &&
+		translate.Covers(translate.ExprCell{"op": "OR", "content": coveringExprs}, expr) {
+		redundancy.Type = PolicyRedundancyRedundant
+		groupPKs = coveringGroupPKs
+	}
+
+	redundancy.Groups = make([]Subject, 0, len(groupPKs))
+	for _, groupPK := range groupPKs {
+		group, found := groupSubjects[groupPK]
+		if !found {
+			group, found, err = getSubject(groupPK)
+			if err != nil {
+				return redundancy, false, err
+			}
+			if !found {
+				continue
+			}
+			groupSubjects[groupPK] = group
+		}
+		redundancy.Groups = append(redundancy.Groups, group)
+	}
+	return redundancy, true, nil
+}
+
+func appendUniqueInt64(s []int64, v int64) []int64 {
+	for _, x := range s {
+		if x == v {
+			return s
+		}
+	}
+	return append(s, v)
+}
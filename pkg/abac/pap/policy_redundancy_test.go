@@ -0,0 +1,161 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/pip"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service/mock"
+	svctypes "iam/pkg/service/types"
+)
+
+var _ = Describe("PolicyRedundancy", func() {
+	pathExpression := func(prefix string) string {
+		return `[{"system":"bk_cmdb","type":"host","expression":{"StringPrefix":{"_bk_iam_path_":["` + prefix + `"]}}}]`
+	}
+
+	Describe("AnalyzePolicyRedundancy", func() {
+		var ctl *gomock.Controller
+		var patches *gomonkey.Patches
+		var policyCtl *policyController
+		var expiredAt int64
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+			expiredAt = time.Now().Unix() + 1000
+
+			mockSubjectService := mock.NewMockSubjectService(ctl)
+			mockSubjectService.EXPECT().GetPK("user", "admin").Return(int64(1), nil).AnyTimes()
+
+			mockActionService := mock.NewMockActionService(ctl)
+			mockActionService.EXPECT().ListThinActionBySystem("bk_cmdb").Return([]svctypes.ThinAction{
+				{PK: 1, System: "bk_cmdb", ID: "view_host"},
+			}, nil).AnyTimes()
+
+			mockPolicyService := mock.NewMockPolicyService(ctl)
+			mockPolicyService.EXPECT().ListThinBySubjectActionTemplate(int64(1), []int64{1}, int64(0)).Return(
+				[]svctypes.ThinPolicy{
+					{ID: 1, ActionPK: 1, ExpiredAt: expiredAt},
+					{ID: 2, ActionPK: 1, ExpiredAt: expiredAt},
+					{ID: 3, ActionPK: 1, ExpiredAt: expiredAt},
+					{ID: 4, ActionPK: 1, ExpiredAt: expiredAt + 1000},
+					{ID: 5, ActionPK: 1, ExpiredAt: expiredAt, Effect: svctypes.PolicyEffectDeny},
+				}, nil,
+			).AnyTimes()
+			mockPolicyService.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{1, 2, 3, 4}).Return(
+				[]svctypes.Policy{
+					// 被用户组的前缀覆盖
+					{ID: 1, ActionPK: 1, Expression: pathExpression("/biz,1/set,2/"), ExpiredAt: expiredAt},
+					// 前缀更短, 部分重叠
+					{ID: 2, ActionPK: 1, Expression: pathExpression("/biz,"), ExpiredAt: expiredAt},
+					// 其他业务, 无关
+					{ID: 3, ActionPK: 1, Expression: pathExpression("/biz,2/"), ExpiredAt: expiredAt},
+					// 过期时间晚于用户组成员关系
+					{ID: 4, ActionPK: 1, Expression: pathExpression("/biz,1/set,3/"), ExpiredAt: expiredAt + 1000},
+				}, nil,
+			).AnyTimes()
+			mockPolicyService.EXPECT().ListAuthBySubjectAction([]int64{10}, int64(1)).Return(
+				[]svctypes.AuthPolicy{
+					{PK: 100, SubjectPK: 10, ExpressionPK: 1000, ExpiredAt: expiredAt + 1000},
+				}, nil,
+			).AnyTimes()
+			mockPolicyService.EXPECT().ListExpressionByPKs([]int64{1000}).Return(
+				[]svctypes.AuthExpression{{PK: 1000, Expression: pathExpression("/biz,1/")}}, nil,
+			).AnyTimes()
+
+			policyCtl = &policyController{
+				subjectService: mockSubjectService,
+				actionService:  mockActionService,
+				policyService:  mockPolicyService,
+			}
+
+			patches = gomonkey.ApplyFunc(pip.GetSubjectDepartmentPKs, func(pk int64) ([]int64, []int64, error) {
+				return []int64{2}, nil, nil
+			})
+			patches.ApplyFunc(
+				cacheimpls.ListSystemSubjectEffectGroups,
+				func(systemID string, pks []int64) ([]svctypes.ThinSubjectGroup, error) {
+					assert.Equal(GinkgoT(), []int64{1, 2}, pks)
+					return []svctypes.ThinSubjectGroup{
+						{GroupPK: 10, ExpiredAt: expiredAt},
+						{GroupPK: 10, ExpiredAt: expiredAt - 10},
+					}, nil
+				},
+			)
+			patches.ApplyFunc(cacheimpls.GetSubjectByPK, func(pk int64) (svctypes.Subject, error) {
+				return svctypes.Subject{PK: pk, Type: "group", ID: "10", Name: "g10"}, nil
+			})
+		})
+
+		AfterEach(func() {
+			ctl.Finish()
+			patches.Reset()
+		})
+
+		It("ok", func() {
+			report, err := policyCtl.AnalyzePolicyRedundancy("bk_cmdb", "user", "admin", false, Operator{})
+			assert.NoError(GinkgoT(), err)
+
+			group := Subject{Type: "group", ID: "10", Name: "g10"}
+			assert.Equal(GinkgoT(), PolicyRedundancyReport{
+				Policies: []PolicyRedundancy{
+					{
+						PolicyID: 1, ActionID: "view_host", Expression: pathExpression("/biz,1/set,2/"),
+						ExpiredAt: expiredAt, Type: PolicyRedundancyRedundant, Groups: []Subject{group},
+					},
+					{
+						PolicyID: 2, ActionID: "view_host", Expression: pathExpression("/biz,"),
+						ExpiredAt: expiredAt, Type: PolicyRedundancyOverlapping, Groups: []Subject{group},
+					},
+					{
+						PolicyID: 4, ActionID: "view_host", Expression: pathExpression("/biz,1/set,3/"),
+						ExpiredAt: expiredAt + 1000, Type: PolicyRedundancyOverlapping, Groups: []Subject{group},
+					},
+				},
+				DeletedPolicyIDs: []int64{},
+			}, report)
+		})
+
+		It("cleanup", func() {
+			patches.ApplyMethod(reflect.TypeOf(policyCtl), "DeleteByIDs",
+				func(_ *policyController, system, subjectType, subjectID string, ids []int64, _ Operator) error {
+					assert.Equal(GinkgoT(), []int64{1}, ids)
+					return nil
+				},
+			)
+
+			report, err := policyCtl.AnalyzePolicyRedundancy("bk_cmdb", "user", "admin", true, Operator{})
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), report.Policies, 3)
+			assert.Equal(GinkgoT(), []int64{1}, report.DeletedPolicyIDs)
+		})
+
+		It("cleanup fail", func() {
+			patches.ApplyMethod(reflect.TypeOf(policyCtl), "DeleteByIDs",
+				func(_ *policyController, system, subjectType, subjectID string, ids []int64, _ Operator) error {
+					return errors.New("error")
+				},
+			)
+
+			_, err := policyCtl.AnalyzePolicyRedundancy("bk_cmdb", "user", "admin", true, Operator{})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "DeleteByIDs")
+		})
+	})
+})
@@ -0,0 +1,190 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service"
+	"iam/pkg/service/types"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+// SoDConstraintCTL ...
+const SoDConstraintCTL = "SoDConstraintCTL"
+
+type SoDConstraintController interface {
+	List() ([]SoDConstraint, error)
+	Create(constraint SoDConstraint) error
+	Delete(id int64) error
+	ListViolations() ([]SoDViolation, error)
+}
+
+type sodConstraintController struct {
+	service service.SoDConstraintService
+}
+
+func NewSoDConstraintController() SoDConstraintController {
+	return &sodConstraintController{
+		service: service.NewSoDConstraintService(),
+	}
+}
+
+// List ...
+func (c *sodConstraintController) List() ([]SoDConstraint, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SoDConstraintCTL, "List")
+
+	svcConstraints, err := c.service.List()
+	if err != nil {
+		return nil, errorWrapf(err, "service.List fail")
+	}
+
+	constraints := make([]SoDConstraint, 0, len(svcConstraints))
+	for _, sc := range svcConstraints {
+		constraint, err := convertToSoDConstraint(sc)
+		if err != nil {
+			return nil, errorWrapf(err, "convertToSoDConstraint constraint=`%+v` fail", sc)
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints, nil
+}
+
+// Create 创建约束, 用户组/操作ID转换为PK存储
+func (c *sodConstraintController) Create(constraint SoDConstraint) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SoDConstraintCTL, "Create")
+
+	itemPKs := make([]int64, 0, len(constraint.Items))
+	for _, id := range constraint.Items {
+		var pk int64
+		var err error
+		if constraint.Type == types.SoDConstraintTypeGroup {
+			pk, err = cacheimpls.GetLocalSubjectPK(types.GroupType, id)
+		} else {
+			pk, err = cacheimpls.GetActionPK(constraint.System, id)
+		}
+		if err != nil {
+			return errorWrapf(err, "get pk of type=`%s`, system=`%s`, id=`%s` fail",
+				constraint.Type, constraint.System, id)
+		}
+		itemPKs = append(itemPKs, pk)
+	}
+
+	svcConstraint := types.SoDConstraint{
+		Type:        constraint.Type,
+		SystemID:    constraint.System,
+		Name:        constraint.Name,
+		Description: constraint.Description,
+		ItemPKs:     itemPKs,
+	}
+	err := c.service.Create(svcConstraint)
+	if err != nil {
+		return errorWrapf(err, "service.Create constraint=`%+v` fail", svcConstraint)
+	}
+	return nil
+}
+
+// Delete ...
+func (c *sodConstraintController) Delete(id int64) error {
+	err := c.service.Delete(id)
+	if err != nil {
+		return errorx.Wrapf(err, SoDConstraintCTL, "Delete", "service.Delete id=`%d` fail", id)
+	}
+	return nil
+}
+
+// ListViolations 查询已存在的违反约束的授权
+func (c *sodConstraintController) ListViolations() ([]SoDViolation, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SoDConstraintCTL, "ListViolations")
+
+	svcViolations, err := c.service.ListViolations()
+	if err != nil {
+		return nil, errorWrapf(err, "service.ListViolations fail")
+	}
+
+	violations := make([]SoDViolation, 0, len(svcViolations))
+	for _, v := range svcViolations {
+		constraint, err := convertToSoDConstraint(v.Constraint)
+		if err != nil {
+			return nil, errorWrapf(err, "convertToSoDConstraint constraint=`%+v` fail", v.Constraint)
+		}
+
+		subject, err := cacheimpls.GetSubjectByPK(v.SubjectPK)
+		if err != nil {
+			return nil, errorWrapf(err, "cacheimpls.GetSubjectByPK pk=`%d` fail", v.SubjectPK)
+		}
+
+		items, err := convertSoDItemPKsToIDs(v.Constraint.Type, v.ItemPKs)
+		if err != nil {
+			return nil, errorWrapf(err, "convertSoDItemPKsToIDs itemPKs=`%+v` fail", v.ItemPKs)
+		}
+
+		violations = append(violations, SoDViolation{
+			Constraint: constraint,
+			Subject: Subject{
+				Type: subject.Type,
+				ID:   subject.ID,
+				Name: subject.Name,
+			},
+			Items: items,
+		})
+	}
+	return violations, nil
+}
+
+func convertToSoDConstraint(constraint types.SoDConstraint) (SoDConstraint, error) {
+	items, err := convertSoDItemPKsToIDs(constraint.Type, constraint.ItemPKs)
+	if err != nil {
+		return SoDConstraint{}, err
+	}
+
+	return SoDConstraint{
+		ID:          constraint.PK,
+		Type:        constraint.Type,
+		System:      constraint.SystemID,
+		Name:        constraint.Name,
+		Description: constraint.Description,
+		Items:       items,
+	}, nil
+}
+
+// convertSoDItemPKsToIDs 用户组/操作PK转换为ID, 已删除的用户组/操作忽略
+func convertSoDItemPKsToIDs(_type string, pks []int64) ([]string, error) {
+	ids := make([]string, 0, len(pks))
+	for _, pk := range pks {
+		if _type == types.SoDConstraintTypeGroup {
+			subject, err := cacheimpls.GetSubjectByPK(pk)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					continue
+				}
+				return nil, err
+			}
+			ids = append(ids, subject.ID)
+			continue
+		}
+
+		action, err := cacheimpls.GetAction(pk)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, err
+		}
+		ids = append(ids, action.ID)
+	}
+	return ids, nil
+}
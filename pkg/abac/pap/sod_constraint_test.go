@@ -0,0 +1,110 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("SoDConstraintController", func() {
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		patches = gomonkey.NewPatches()
+	})
+	AfterEach(func() {
+		ctl.Finish()
+		patches.Reset()
+	})
+
+	Describe("Create", func() {
+		It("cacheimpls.GetActionPK fail", func() {
+			patches.ApplyFunc(cacheimpls.GetActionPK, func(systemID, actionID string) (int64, error) {
+				return 0, errors.New("not found")
+			})
+
+			manager := &sodConstraintController{}
+			err := manager.Create(SoDConstraint{Type: "action", System: "test", Items: []string{"pay"}})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "get pk")
+		})
+
+		It("ok", func() {
+			patches.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (int64, error) {
+				if id == "1" {
+					return 1, nil
+				}
+				return 2, nil
+			})
+
+			mockService := mock.NewMockSoDConstraintService(ctl)
+			mockService.EXPECT().Create(types.SoDConstraint{
+				Type:    "group",
+				Name:    "test",
+				ItemPKs: []int64{1, 2},
+			}).Return(nil)
+
+			manager := &sodConstraintController{service: mockService}
+			err := manager.Create(SoDConstraint{Type: "group", Name: "test", Items: []string{"1", "2"}})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("ListViolations", func() {
+		It("service.ListViolations fail", func() {
+			mockService := mock.NewMockSoDConstraintService(ctl)
+			mockService.EXPECT().ListViolations().Return(nil, errors.New("error"))
+
+			manager := &sodConstraintController{service: mockService}
+			_, err := manager.ListViolations()
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "service.ListViolations")
+		})
+
+		It("ok", func() {
+			patches.ApplyFunc(cacheimpls.GetAction, func(pk int64) (types.ThinAction, error) {
+				if pk == 3 {
+					return types.ThinAction{}, sql.ErrNoRows
+				}
+				return types.ThinAction{PK: pk, ID: map[int64]string{1: "pay", 2: "approve"}[pk]}, nil
+			})
+			patches.ApplyFunc(cacheimpls.GetSubjectByPK, func(pk int64) (types.Subject, error) {
+				return types.Subject{PK: pk, Type: "user", ID: "admin", Name: "admin"}, nil
+			})
+
+			constraint := types.SoDConstraint{PK: 1, Type: "action", SystemID: "test", ItemPKs: []int64{1, 2, 3}}
+			mockService := mock.NewMockSoDConstraintService(ctl)
+			mockService.EXPECT().ListViolations().Return([]types.SoDViolation{
+				{Constraint: constraint, SubjectPK: 10, ItemPKs: []int64{1, 2}},
+			}, nil)
+
+			manager := &sodConstraintController{service: mockService}
+			violations, err := manager.ListViolations()
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []SoDViolation{{
+				Constraint: SoDConstraint{ID: 1, Type: "action", System: "test", Items: []string{"pay", "approve"}},
+				Subject:    Subject{Type: "user", ID: "admin", Name: "admin"},
+				Items:      []string{"pay", "approve"},
+			}}, violations)
+		})
+	})
+})
@@ -46,6 +46,9 @@ type subjectController struct {
 	subjectActionGroupResourceService service.SubjectActionGroupResourceService
 	groupResourcePolicyService        service.GroupResourcePolicyService
 	groupAlterEventService            service.GroupAlterEventService
+	subjectAttributeService           service.SubjectAttributeService
+	subjectDelegationService          service.SubjectDelegationService
+	subjectGroupHistoryService        service.SubjectGroupHistoryService
 
 	subjectEventProducer event.SubjectEventProducer
 }
@@ -62,6 +65,9 @@ func NewSubjectController() SubjectController {
 		subjectActionGroupResourceService: service.NewSubjectActionGroupResourceService(),
 		groupResourcePolicyService:        service.NewGroupResourcePolicyService(),
 		groupAlterEventService:            service.NewGroupAlterEventService(),
+		subjectAttributeService:           service.NewSubjectAttributeService(),
+		subjectDelegationService:          service.NewSubjectDelegationService(),
+		subjectGroupHistoryService:        service.NewSubjectGroupHistoryService(),
 		subjectEventProducer:              event.NewSubjectEventProducer(),
 	}
 }
@@ -230,7 +236,12 @@ func (c *subjectController) BulkDeleteUserAndDepartment(subjects []Subject) erro
 		return errorWrapf(err, "policyService.BulkDeleteBySubjectPKsWithTx pks=`%+v` failed", pks)
 	}
 
-	// 2. 删除subject relation
+	// 2. 删除subject relation, 删除前记录同步退出用户组
+	err = c.subjectGroupHistoryService.BulkCreateSyncRemoveBySubjectPKsWithTx(tx, pks)
+	if err != nil {
+		return errorWrapf(err, "subjectGroupHistoryService.BulkCreateSyncRemoveBySubjectPKsWithTx pks=`%+v` failed", pks)
+	}
+
 	err = c.groupService.BulkDeleteBySubjectPKsWithTx(tx, pks)
 	if err != nil {
 		return errorWrapf(err, "groupService.BulkDeleteBySubjectPKsWithTx pks=`%+v` failed", pks)
@@ -273,6 +284,18 @@ func (c *subjectController) BulkDeleteUserAndDepartment(subjects []Subject) erro
 		)
 	}
 
+	// 7. 删除subject attribute
+	err = c.subjectAttributeService.BulkDeleteBySubjectPKsWithTx(tx, pks)
+	if err != nil {
+		return errorWrapf(err, "subjectAttributeService.BulkDeleteBySubjectPKsWithTx pks=`%+v` failed", pks)
+	}
+
+	// 8. 删除subject delegation
+	err = c.subjectDelegationService.BulkDeleteBySubjectPKsWithTx(tx, pks)
+	if err != nil {
+		return errorWrapf(err, "subjectDelegationService.BulkDeleteBySubjectPKsWithTx pks=`%+v` failed", pks)
+	}
+
 	// 提交事务
 	err = tx.Commit()
 	if err != nil {
@@ -282,6 +305,8 @@ func (c *subjectController) BulkDeleteUserAndDepartment(subjects []Subject) erro
 	// 5. 清除缓存
 	// 清除涉及的所有缓存 [subjectGroup / subjectDetails]
 	cacheimpls.BatchDeleteSubjectDepartmentCache(pks)
+	cacheimpls.BatchDeleteSubjectAttributeCache(pks)
+	cacheimpls.BatchDeleteSubjectDelegationCache(pks)
 
 	for _, s := range subjects {
 		cacheimpls.DeleteSubjectPK(s.Type, s.ID)
@@ -0,0 +1,100 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service"
+	"iam/pkg/service/types"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+// SubjectAttributeCTL ...
+const SubjectAttributeCTL = "SubjectAttributeCTL"
+
+type SubjectAttributeController interface {
+	BulkUpdate(subjectAttributes []SubjectAttribute) error
+	BulkDelete(subjectIDs []string) error
+}
+
+type subjectAttributeController struct {
+	service service.SubjectAttributeService
+
+	subjectService service.SubjectService
+}
+
+func NewSubjectAttributeController() SubjectAttributeController {
+	return &subjectAttributeController{
+		service: service.NewSubjectAttributeService(),
+
+		subjectService: service.NewSubjectService(),
+	}
+}
+
+// BulkUpdate 全量覆盖用户的属性
+func (c *subjectAttributeController) BulkUpdate(subjectAttributes []SubjectAttribute) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectAttributeCTL, "BulkUpdate")
+
+	svcSubjectAttributes := make([]types.SubjectAttribute, 0, len(subjectAttributes))
+	subjectPKs := make([]int64, 0, len(subjectAttributes))
+	for _, sa := range subjectAttributes {
+		subjectPK, err := cacheimpls.GetLocalSubjectPK(types.UserType, sa.SubjectID)
+		if err != nil {
+			return errorWrapf(err, "cacheimpls.GetLocalSubjectPK id=`%s` fail", sa.SubjectID)
+		}
+
+		svcSubjectAttributes = append(svcSubjectAttributes, types.SubjectAttribute{
+			SubjectPK:  subjectPK,
+			Attributes: sa.Attributes,
+		})
+		subjectPKs = append(subjectPKs, subjectPK)
+	}
+
+	err := c.service.BulkUpdate(svcSubjectAttributes)
+	if err != nil {
+		return errorWrapf(err, "service.BulkUpdate subjectAttributes=`%+v` fail", svcSubjectAttributes)
+	}
+
+	// delete from cache
+	cacheimpls.BatchDeleteSubjectAttributeCache(subjectPKs)
+
+	return nil
+}
+
+// BulkDelete 删除用户的所有属性
+func (c *subjectAttributeController) BulkDelete(subjectIDs []string) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectAttributeCTL, "BulkDelete")
+	subjects := make([]types.Subject, 0, len(subjectIDs))
+	for _, subjectID := range subjectIDs {
+		subjects = append(subjects, types.Subject{
+			Type: types.UserType,
+			ID:   subjectID,
+		})
+	}
+
+	subjectPKs, err := c.subjectService.ListPKsBySubjects(subjects)
+	if err != nil {
+		return errorWrapf(err, "subjectService.ListPKsBySubjects subjects=`%+v` fail", subjects)
+	}
+
+	err = c.service.BulkDelete(subjectPKs)
+	if err != nil {
+		return errorWrapf(err, "service.BulkDelete subjectIDs=`%s` fail", subjectIDs)
+	}
+
+	// delete from cache
+	cacheimpls.BatchDeleteSubjectAttributeCache(subjectPKs)
+
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"errors"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("SubjectAttributeController", func() {
+	Describe("BulkUpdate", func() {
+		var ctl *gomock.Controller
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+			patches = gomonkey.ApplyFunc(cacheimpls.BatchDeleteSubjectAttributeCache, func(pks []int64) error {
+				return nil
+			})
+		})
+		AfterEach(func() {
+			ctl.Finish()
+			patches.Reset()
+		})
+
+		It("cacheimpls.GetLocalSubjectPK fail", func() {
+			patches.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+				return 0, errors.New("not found")
+			})
+
+			manager := &subjectAttributeController{}
+			err := manager.BulkUpdate([]SubjectAttribute{{SubjectID: "1"}})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "GetLocalSubjectPK")
+		})
+
+		It("service.BulkUpdate fail", func() {
+			patches.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+				return 1, nil
+			})
+
+			mockService := mock.NewMockSubjectAttributeService(ctl)
+			mockService.EXPECT().BulkUpdate(gomock.Any()).Return(errors.New("error"))
+
+			manager := &subjectAttributeController{service: mockService}
+			err := manager.BulkUpdate([]SubjectAttribute{{SubjectID: "1"}})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "service.BulkUpdate")
+		})
+
+		It("ok", func() {
+			patches.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+				return 1, nil
+			})
+
+			mockService := mock.NewMockSubjectAttributeService(ctl)
+			mockService.EXPECT().BulkUpdate([]types.SubjectAttribute{{
+				SubjectPK:  1,
+				Attributes: map[string]interface{}{"level": float64(3)},
+			}}).Return(nil)
+
+			manager := &subjectAttributeController{service: mockService}
+			err := manager.BulkUpdate([]SubjectAttribute{{
+				SubjectID:  "1",
+				Attributes: map[string]interface{}{"level": float64(3)},
+			}})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("BulkDelete", func() {
+		var ctl *gomock.Controller
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("subjectService.ListPKsBySubjects fail", func() {
+			mockSubjectService := mock.NewMockSubjectService(ctl)
+			mockSubjectService.EXPECT().ListPKsBySubjects([]types.Subject{{Type: "user", ID: "1"}}).Return(
+				nil, errors.New("error"),
+			)
+
+			manager := &subjectAttributeController{subjectService: mockSubjectService}
+			err := manager.BulkDelete([]string{"1"})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListPKsBySubjects")
+		})
+
+		It("ok", func() {
+			mockSubjectService := mock.NewMockSubjectService(ctl)
+			mockSubjectService.EXPECT().ListPKsBySubjects([]types.Subject{{Type: "user", ID: "1"}}).Return(
+				[]int64{1}, nil,
+			)
+
+			mockService := mock.NewMockSubjectAttributeService(ctl)
+			mockService.EXPECT().BulkDelete([]int64{1}).Return(nil)
+
+			patches := gomonkey.ApplyFunc(cacheimpls.BatchDeleteSubjectAttributeCache, func(pks []int64) error {
+				return nil
+			})
+			defer patches.Reset()
+
+			manager := &subjectAttributeController{
+				service:        mockService,
+				subjectService: mockSubjectService,
+			}
+			err := manager.BulkDelete([]string{"1"})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+})
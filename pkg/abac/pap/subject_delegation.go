@@ -0,0 +1,206 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service"
+	"iam/pkg/service/types"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+// SubjectDelegationCTL ...
+const SubjectDelegationCTL = "SubjectDelegationCTL"
+
+// ErrInvalidSubjectDelegation 委托人与被委托人相同/已过期/委托人已冻结
+var ErrInvalidSubjectDelegation = errors.New("invalid subject delegation")
+
+type SubjectDelegationController interface {
+	ListByDelegator(_type, id string) ([]SubjectDelegation, error)
+	Create(delegation SubjectDelegation) error
+	Delete(id int64) error
+	BulkRevokeByDelegators(delegatorPKs []int64) error
+}
+
+type subjectDelegationController struct {
+	service service.SubjectDelegationService
+}
+
+func NewSubjectDelegationController() SubjectDelegationController {
+	return &subjectDelegationController{
+		service: service.NewSubjectDelegationService(),
+	}
+}
+
+// ListByDelegator 查询委托人发起的所有委托
+func (c *subjectDelegationController) ListByDelegator(_type, id string) ([]SubjectDelegation, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectDelegationCTL, "ListByDelegator")
+
+	delegatorPK, err := cacheimpls.GetLocalSubjectPK(_type, id)
+	if err != nil {
+		return nil, errorWrapf(err, "cacheimpls.GetLocalSubjectPK type=`%s`, id=`%s` fail", _type, id)
+	}
+
+	svcDelegations, err := c.service.ListByDelegator(delegatorPK)
+	if err != nil {
+		return nil, errorWrapf(err, "service.ListByDelegator delegatorPK=`%d` fail", delegatorPK)
+	}
+
+	delegations := make([]SubjectDelegation, 0, len(svcDelegations))
+	for _, d := range svcDelegations {
+		delegation, err := convertToSubjectDelegation(d)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, errorWrapf(err, "convertToSubjectDelegation delegation=`%+v` fail", d)
+		}
+		delegations = append(delegations, delegation)
+	}
+	return delegations, nil
+}
+
+// Create 创建委托, 委托的权限在鉴权时按委托人当前的权限动态计算, 不会超出委托人的权限
+func (c *subjectDelegationController) Create(delegation SubjectDelegation) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectDelegationCTL, "Create")
+
+	delegatorPK, err := cacheimpls.GetLocalSubjectPK(delegation.Delegator.Type, delegation.Delegator.ID)
+	if err != nil {
+		return errorWrapf(err, "cacheimpls.GetLocalSubjectPK delegator=`%+v` fail", delegation.Delegator)
+	}
+
+	delegateePK, err := cacheimpls.GetLocalSubjectPK(delegation.Delegatee.Type, delegation.Delegatee.ID)
+	if err != nil {
+		return errorWrapf(err, "cacheimpls.GetLocalSubjectPK delegatee=`%+v` fail", delegation.Delegatee)
+	}
+
+	if delegatorPK == delegateePK {
+		return errorWrapf(ErrInvalidSubjectDelegation, "delegator and delegatee are the same")
+	}
+	if delegation.ExpiredAt <= time.Now().Unix() {
+		return errorWrapf(ErrInvalidSubjectDelegation, "expired_at=`%d` is expired", delegation.ExpiredAt)
+	}
+	if cacheimpls.IsSubjectInBlackList(delegation.Delegator.Type, delegation.Delegator.ID) {
+		return errorWrapf(ErrInvalidSubjectDelegation, "delegator=`%+v` is frozen", delegation.Delegator)
+	}
+
+	actionPKs := make([]int64, 0, len(delegation.Actions))
+	for _, actionID := range delegation.Actions {
+		actionPK, err := cacheimpls.GetActionPK(delegation.System, actionID)
+		if err != nil {
+			return errorWrapf(err, "cacheimpls.GetActionPK system=`%s`, action=`%s` fail",
+				delegation.System, actionID)
+		}
+		actionPKs = append(actionPKs, actionPK)
+	}
+
+	svcDelegation := types.SubjectDelegation{
+		DelegatorPK: delegatorPK,
+		DelegateePK: delegateePK,
+		SystemID:    delegation.System,
+		ActionPKs:   actionPKs,
+		ExpiredAt:   delegation.ExpiredAt,
+	}
+	err = c.service.Create(svcDelegation)
+	if err != nil {
+		return errorWrapf(err, "service.Create delegation=`%+v` fail", svcDelegation)
+	}
+
+	// delete from cache
+	cacheimpls.BatchDeleteSubjectDelegationCache([]int64{delegateePK})
+
+	return nil
+}
+
+// Delete 撤销委托
+func (c *subjectDelegationController) Delete(id int64) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectDelegationCTL, "Delete")
+
+	delegation, err := c.service.Get(id)
+	if err != nil {
+		return errorWrapf(err, "service.Get id=`%d` fail", id)
+	}
+
+	err = c.service.Delete(id)
+	if err != nil {
+		return errorWrapf(err, "service.Delete id=`%d` fail", id)
+	}
+
+	// delete from cache
+	cacheimpls.BatchDeleteSubjectDelegationCache([]int64{delegation.DelegateePK})
+
+	return nil
+}
+
+// BulkRevokeByDelegators 撤销委托人的所有委托, 用于委托人被冻结时
+func (c *subjectDelegationController) BulkRevokeByDelegators(delegatorPKs []int64) error {
+	delegateePKs, err := c.service.BulkDeleteByDelegatorPKs(delegatorPKs)
+	if err != nil {
+		return errorx.Wrapf(err, SubjectDelegationCTL, "BulkRevokeByDelegators",
+			"service.BulkDeleteByDelegatorPKs delegatorPKs=`%+v` fail", delegatorPKs)
+	}
+
+	// delete from cache
+	if len(delegateePKs) > 0 {
+		cacheimpls.BatchDeleteSubjectDelegationCache(delegateePKs)
+	}
+
+	return nil
+}
+
+// convertToSubjectDelegation 委托人/被委托人PK转换为subject, 已删除的操作忽略
+func convertToSubjectDelegation(delegation types.SubjectDelegation) (SubjectDelegation, error) {
+	delegator, err := cacheimpls.GetSubjectByPK(delegation.DelegatorPK)
+	if err != nil {
+		return SubjectDelegation{}, err
+	}
+
+	delegatee, err := cacheimpls.GetSubjectByPK(delegation.DelegateePK)
+	if err != nil {
+		return SubjectDelegation{}, err
+	}
+
+	actions := make([]string, 0, len(delegation.ActionPKs))
+	for _, actionPK := range delegation.ActionPKs {
+		action, err := cacheimpls.GetAction(actionPK)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return SubjectDelegation{}, err
+		}
+		actions = append(actions, action.ID)
+	}
+
+	return SubjectDelegation{
+		ID: delegation.PK,
+		Delegator: Subject{
+			Type: delegator.Type,
+			ID:   delegator.ID,
+			Name: delegator.Name,
+		},
+		Delegatee: Subject{
+			Type: delegatee.Type,
+			ID:   delegatee.ID,
+			Name: delegatee.Name,
+		},
+		System:    delegation.SystemID,
+		Actions:   actions,
+		ExpiredAt: delegation.ExpiredAt,
+	}, nil
+}
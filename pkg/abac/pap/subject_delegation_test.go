@@ -0,0 +1,148 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"errors"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("SubjectDelegationController", func() {
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+	var mockService *mock.MockSubjectDelegationService
+	var manager *subjectDelegationController
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockService = mock.NewMockSubjectDelegationService(ctl)
+		manager = &subjectDelegationController{service: mockService}
+		patches = gomonkey.ApplyFunc(cacheimpls.BatchDeleteSubjectDelegationCache, func(pks []int64) error {
+			return nil
+		})
+	})
+	AfterEach(func() {
+		ctl.Finish()
+		patches.Reset()
+	})
+
+	Describe("Create", func() {
+		var delegation SubjectDelegation
+		BeforeEach(func() {
+			delegation = SubjectDelegation{
+				Delegator: Subject{Type: types.UserType, ID: "manager"},
+				Delegatee: Subject{Type: types.UserType, ID: "deputy"},
+				System:    "test",
+				Actions:   []string{"view"},
+				ExpiredAt: time.Now().Unix() + 100,
+			}
+			patches.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+				if id == "manager" {
+					return 1, nil
+				}
+				return 2, nil
+			})
+			patches.ApplyFunc(cacheimpls.GetActionPK, func(system, id string) (int64, error) {
+				return 10, nil
+			})
+			patches.ApplyFunc(cacheimpls.IsSubjectInBlackList, func(_type, id string) bool {
+				return false
+			})
+		})
+
+		It("same subject", func() {
+			delegation.Delegatee = delegation.Delegator
+
+			err := manager.Create(delegation)
+			assert.ErrorIs(GinkgoT(), err, ErrInvalidSubjectDelegation)
+		})
+
+		It("expired", func() {
+			delegation.ExpiredAt = 1
+
+			err := manager.Create(delegation)
+			assert.ErrorIs(GinkgoT(), err, ErrInvalidSubjectDelegation)
+		})
+
+		It("delegator frozen", func() {
+			patches.ApplyFunc(cacheimpls.IsSubjectInBlackList, func(_type, id string) bool {
+				return true
+			})
+
+			err := manager.Create(delegation)
+			assert.ErrorIs(GinkgoT(), err, ErrInvalidSubjectDelegation)
+		})
+
+		It("service.Create fail", func() {
+			mockService.EXPECT().Create(gomock.Any()).Return(errors.New("error"))
+
+			err := manager.Create(delegation)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "service.Create")
+		})
+
+		It("ok", func() {
+			mockService.EXPECT().Create(types.SubjectDelegation{
+				DelegatorPK: 1,
+				DelegateePK: 2,
+				SystemID:    "test",
+				ActionPKs:   []int64{10},
+				ExpiredAt:   delegation.ExpiredAt,
+			}).Return(nil)
+
+			err := manager.Create(delegation)
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("Delete", func() {
+		It("service.Get fail", func() {
+			mockService.EXPECT().Get(int64(1)).Return(types.SubjectDelegation{}, errors.New("error"))
+
+			err := manager.Delete(1)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "service.Get")
+		})
+
+		It("ok", func() {
+			mockService.EXPECT().Get(int64(1)).Return(types.SubjectDelegation{PK: 1, DelegateePK: 2}, nil)
+			mockService.EXPECT().Delete(int64(1)).Return(nil)
+
+			err := manager.Delete(1)
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("BulkRevokeByDelegators", func() {
+		It("fail", func() {
+			mockService.EXPECT().BulkDeleteByDelegatorPKs([]int64{1}).Return(nil, errors.New("error"))
+
+			err := manager.BulkRevokeByDelegators([]int64{1})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "BulkDeleteByDelegatorPKs")
+		})
+
+		It("ok", func() {
+			mockService.EXPECT().BulkDeleteByDelegatorPKs([]int64{1}).Return([]int64{2}, nil)
+
+			err := manager.BulkRevokeByDelegators([]int64{1})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+})
@@ -0,0 +1,162 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service"
+	"iam/pkg/service/types"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+// SubjectGroupHistoryCTL ...
+const SubjectGroupHistoryCTL = "SubjectGroupHistoryCTL"
+
+type SubjectGroupHistoryController interface {
+	GetGroupHistoryCount(_type, id string) (int64, error)
+	ListPagingGroupHistory(_type, id string, limit, offset int64) ([]SubjectGroupHistory, error)
+	GetSubjectHistoryCount(_type, id string) (int64, error)
+	ListPagingSubjectHistory(_type, id string, limit, offset int64) ([]SubjectGroupHistory, error)
+}
+
+type subjectGroupHistoryController struct {
+	service service.SubjectGroupHistoryService
+}
+
+func NewSubjectGroupHistoryController() SubjectGroupHistoryController {
+	return &subjectGroupHistoryController{
+		service: service.NewSubjectGroupHistoryService(),
+	}
+}
+
+// GetGroupHistoryCount ...
+func (c *subjectGroupHistoryController) GetGroupHistoryCount(_type, id string) (int64, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectGroupHistoryCTL, "GetGroupHistoryCount")
+	groupPK, err := cacheimpls.GetLocalSubjectPK(_type, id)
+	if err != nil {
+		return 0, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", _type, id)
+	}
+
+	count, err := c.service.GetGroupHistoryCount(groupPK)
+	if err != nil {
+		return 0, errorWrapf(err, "service.GetGroupHistoryCount groupPK=`%d` fail", groupPK)
+	}
+	return count, nil
+}
+
+// ListPagingGroupHistory 查询用户组的成员变更记录
+func (c *subjectGroupHistoryController) ListPagingGroupHistory(
+	_type, id string,
+	limit, offset int64,
+) ([]SubjectGroupHistory, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectGroupHistoryCTL, "ListPagingGroupHistory")
+	groupPK, err := cacheimpls.GetLocalSubjectPK(_type, id)
+	if err != nil {
+		return nil, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", _type, id)
+	}
+
+	svcHistories, err := c.service.ListPagingGroupHistory(groupPK, limit, offset)
+	if err != nil {
+		return nil, errorWrapf(err, "service.ListPagingGroupHistory groupPK=`%d`, limit=`%d`, offset=`%d` fail",
+			groupPK, limit, offset)
+	}
+
+	histories, err := convertToSubjectGroupHistories(svcHistories)
+	if err != nil {
+		return nil, errorWrapf(err, "convertToSubjectGroupHistories histories=`%+v` fail", svcHistories)
+	}
+	return histories, nil
+}
+
+// GetSubjectHistoryCount ...
+func (c *subjectGroupHistoryController) GetSubjectHistoryCount(_type, id string) (int64, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectGroupHistoryCTL, "GetSubjectHistoryCount")
+	subjectPK, err := cacheimpls.GetLocalSubjectPK(_type, id)
+	if err != nil {
+		return 0, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", _type, id)
+	}
+
+	count, err := c.service.GetSubjectHistoryCount(subjectPK)
+	if err != nil {
+		return 0, errorWrapf(err, "service.GetSubjectHistoryCount subjectPK=`%d` fail", subjectPK)
+	}
+	return count, nil
+}
+
+// ListPagingSubjectHistory 查询subject的用户组变更记录
+func (c *subjectGroupHistoryController) ListPagingSubjectHistory(
+	_type, id string,
+	limit, offset int64,
+) ([]SubjectGroupHistory, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectGroupHistoryCTL, "ListPagingSubjectHistory")
+	subjectPK, err := cacheimpls.GetLocalSubjectPK(_type, id)
+	if err != nil {
+		return nil, errorWrapf(err, "cacheimpls.GetLocalSubjectPK _type=`%s`, id=`%s` fail", _type, id)
+	}
+
+	svcHistories, err := c.service.ListPagingSubjectHistory(subjectPK, limit, offset)
+	if err != nil {
+		return nil, errorWrapf(err, "service.ListPagingSubjectHistory subjectPK=`%d`, limit=`%d`, offset=`%d` fail",
+			subjectPK, limit, offset)
+	}
+
+	histories, err := convertToSubjectGroupHistories(svcHistories)
+	if err != nil {
+		return nil, errorWrapf(err, "convertToSubjectGroupHistories histories=`%+v` fail", svcHistories)
+	}
+	return histories, nil
+}
+
+// convertToSubjectGroupHistories subject/用户组PK转换为ID, 已删除的subject/用户组忽略
+func convertToSubjectGroupHistories(svcHistories []types.SubjectGroupHistory) ([]SubjectGroupHistory, error) {
+	pks := make([]int64, 0, 2*len(svcHistories))
+	for _, h := range svcHistories {
+		pks = append(pks, h.SubjectPK, h.GroupPK)
+	}
+
+	subjects, err := cacheimpls.BatchGetSubjectByPKs(pks)
+	if err != nil {
+		return nil, err
+	}
+
+	subjectMap := make(map[int64]types.Subject, len(subjects))
+	for _, subject := range subjects {
+		subjectMap[subject.PK] = subject
+	}
+
+	histories := make([]SubjectGroupHistory, 0, len(svcHistories))
+	for _, h := range svcHistories {
+		subject, ok := subjectMap[h.SubjectPK]
+		if !ok {
+			continue
+		}
+		group, ok := subjectMap[h.GroupPK]
+		if !ok {
+			continue
+		}
+
+		histories = append(histories, SubjectGroupHistory{
+			Subject:     Subject{Type: subject.Type, ID: subject.ID, Name: subject.Name},
+			Group:       Subject{Type: group.Type, ID: group.ID, Name: group.Name},
+			TemplateID:  h.TemplateID,
+			Action:      h.Action,
+			Source:      h.Source,
+			ExpiredAt:   h.ExpiredAt,
+			EffectiveAt: h.EffectiveAt,
+			Actor:       h.Actor,
+			CreatedAt:   h.CreatedAt,
+		})
+	}
+	return histories, nil
+}
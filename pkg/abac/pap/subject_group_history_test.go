@@ -0,0 +1,92 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pap
+
+import (
+	"errors"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("SubjectGroupHistoryController", func() {
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+	var mockService *mock.MockSubjectGroupHistoryService
+	var manager *subjectGroupHistoryController
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockService = mock.NewMockSubjectGroupHistoryService(ctl)
+		manager = &subjectGroupHistoryController{service: mockService}
+		patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectPK, func(_type, id string) (pk int64, err error) {
+			return 1, nil
+		})
+	})
+	AfterEach(func() {
+		ctl.Finish()
+		patches.Reset()
+	})
+
+	Describe("ListPagingGroupHistory", func() {
+		It("service.ListPagingGroupHistory fail", func() {
+			mockService.EXPECT().ListPagingGroupHistory(int64(1), int64(10), int64(0)).Return(
+				nil, errors.New("error"),
+			)
+
+			_, err := manager.ListPagingGroupHistory("group", "1", 10, 0)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "service.ListPagingGroupHistory")
+		})
+
+		It("ok", func() {
+			mockService.EXPECT().ListPagingGroupHistory(int64(1), int64(10), int64(0)).Return(
+				[]types.SubjectGroupHistory{
+					{SubjectPK: 2, GroupPK: 1, Action: "add", Source: "manual", ExpiredAt: 10, Actor: "admin"},
+					// subject已删除
+					{SubjectPK: 3, GroupPK: 1, Action: "remove", Source: "sync"},
+				}, nil,
+			)
+			patches.ApplyFunc(cacheimpls.BatchGetSubjectByPKs, func(pks []int64) ([]types.Subject, error) {
+				return []types.Subject{
+					{PK: 1, Type: "group", ID: "1", Name: "ops"},
+					{PK: 2, Type: "user", ID: "alice", Name: "alice"},
+				}, nil
+			})
+
+			histories, err := manager.ListPagingGroupHistory("group", "1", 10, 0)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []SubjectGroupHistory{{
+				Subject:   Subject{Type: "user", ID: "alice", Name: "alice"},
+				Group:     Subject{Type: "group", ID: "1", Name: "ops"},
+				Action:    "add",
+				Source:    "manual",
+				ExpiredAt: 10,
+				Actor:     "admin",
+			}}, histories)
+		})
+	})
+
+	Describe("GetSubjectHistoryCount", func() {
+		It("ok", func() {
+			mockService.EXPECT().GetSubjectHistoryCount(int64(1)).Return(int64(3), nil)
+
+			count, err := manager.GetSubjectHistoryCount("user", "alice")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), int64(3), count)
+		})
+	})
+})
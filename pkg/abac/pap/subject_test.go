@@ -129,8 +129,13 @@ var _ = Describe("SubjectController", func() {
 
 	Describe("BulkDeleteUserAndDepartment", func() {
 		var ctl *gomock.Controller
+		var mockSubjectGroupHistoryService *mock.MockSubjectGroupHistoryService
 		BeforeEach(func() {
 			ctl = gomock.NewController(GinkgoT())
+			mockSubjectGroupHistoryService = mock.NewMockSubjectGroupHistoryService(ctl)
+			mockSubjectGroupHistoryService.EXPECT().BulkCreateSyncRemoveBySubjectPKsWithTx(
+				gomock.Any(), gomock.Any(),
+			).Return(nil).AnyTimes()
 		})
 		AfterEach(func() {
 			ctl.Finish()
@@ -149,7 +154,8 @@ var _ = Describe("SubjectController", func() {
 			).AnyTimes()
 
 			manager := &subjectController{
-				service: mockSubjectService,
+				service:                    mockSubjectService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			err := manager.BulkDeleteUserAndDepartment([]Subject{{Type: "user", Name: "name", ID: "1"}})
@@ -185,8 +191,9 @@ var _ = Describe("SubjectController", func() {
 			defer patches.Reset()
 
 			manager := &subjectController{
-				service:       mockSubjectService,
-				policyService: mockPolicyService,
+				service:                    mockSubjectService,
+				policyService:              mockPolicyService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			err := manager.BulkDeleteUserAndDepartment([]Subject{{Type: "user", Name: "name", ID: "1"}})
@@ -227,9 +234,10 @@ var _ = Describe("SubjectController", func() {
 			defer patches.Reset()
 
 			manager := &subjectController{
-				service:       mockSubjectService,
-				policyService: mockPolicyService,
-				groupService:  mockGroupService,
+				service:                    mockSubjectService,
+				policyService:              mockPolicyService,
+				groupService:               mockGroupService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			err := manager.BulkDeleteUserAndDepartment([]Subject{{Type: "user", Name: "name", ID: "1"}})
@@ -275,10 +283,11 @@ var _ = Describe("SubjectController", func() {
 			defer patches.Reset()
 
 			manager := &subjectController{
-				service:           mockSubjectService,
-				policyService:     mockPolicyService,
-				groupService:      mockGroupService,
-				departmentService: mockDepartmentService,
+				service:                    mockSubjectService,
+				policyService:              mockPolicyService,
+				groupService:               mockGroupService,
+				departmentService:          mockDepartmentService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			err := manager.BulkDeleteUserAndDepartment([]Subject{{Type: "user", Name: "name", ID: "1"}})
@@ -327,10 +336,11 @@ var _ = Describe("SubjectController", func() {
 			defer patches.Reset()
 
 			manager := &subjectController{
-				service:           mockSubjectService,
-				policyService:     mockPolicyService,
-				groupService:      mockGroupService,
-				departmentService: mockDepartmentService,
+				service:                    mockSubjectService,
+				policyService:              mockPolicyService,
+				groupService:               mockGroupService,
+				departmentService:          mockDepartmentService,
+				subjectGroupHistoryService: mockSubjectGroupHistoryService,
 			}
 
 			err := manager.BulkDeleteUserAndDepartment([]Subject{{Type: "user", Name: "name", ID: "1"}})
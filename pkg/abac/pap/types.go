@@ -25,14 +25,68 @@ type SubjectDepartment struct {
 	DepartmentIDs []string `json:"departments"`
 }
 
+// DepartmentParent 部门的上级部门, ParentID为空表示根部门
+type DepartmentParent struct {
+	DepartmentID string `json:"id"`
+	ParentID     string `json:"parent_id"`
+}
+
+// SubjectAttribute 用户的属性, 用于条件中的 _bk_iam_subject_
+type SubjectAttribute struct {
+	SubjectID  string                 `json:"id"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// SoDConstraint 职责分离约束, Items为互斥的用户组ID/操作ID
+type SoDConstraint struct {
+	ID          int64    `json:"id"`
+	Type        string   `json:"type"`
+	System      string   `json:"system"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Items       []string `json:"items"`
+}
+
+// SoDViolation 违反职责分离约束的subject及其同时拥有的用户组ID/操作ID
+type SoDViolation struct {
+	Constraint SoDConstraint `json:"constraint"`
+	Subject    Subject       `json:"subject"`
+	Items      []string      `json:"items"`
+}
+
+// SubjectDelegation 用户间的权限委托, delegatee在有效期内获得delegator在指定操作上的权限
+type SubjectDelegation struct {
+	ID        int64    `json:"id"`
+	Delegator Subject  `json:"delegator"`
+	Delegatee Subject  `json:"delegatee"`
+	System    string   `json:"system"`
+	Actions   []string `json:"actions"`
+	ExpiredAt int64    `json:"expired_at"`
+}
+
 // GroupMember ...
 type GroupMember struct {
-	PK        int64     `json:"pk"`
-	Type      string    `json:"type"`
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	ExpiredAt int64     `json:"expired_at"`
-	CreatedAt time.Time `json:"created_at"`
+	PK        int64  `json:"pk"`
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ExpiredAt int64  `json:"expired_at"`
+	// 生效时间, 0表示已生效
+	EffectiveAt int64     `json:"effective_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SubjectGroupHistory 用户组成员变更记录
+type SubjectGroupHistory struct {
+	Subject     Subject   `json:"subject"`
+	Group       Subject   `json:"group"`
+	TemplateID  int64     `json:"template_id"`
+	Action      string    `json:"action"`
+	Source      string    `json:"source"`
+	ExpiredAt   int64     `json:"expired_at"`
+	EffectiveAt int64     `json:"effective_at"`
+	Actor       string    `json:"actor"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // SubjectGroup subject关联的组
@@ -57,4 +111,145 @@ type SubjectTemplateGroup struct {
 	TemplateID int64  `json:"template_id"`
 	GroupID    int64  `json:"group_pk"`
 	ExpiredAt  int64  `json:"expired_at"`
+	// 生效时间, 0表示已生效
+	EffectiveAt int64 `json:"effective_at"`
+}
+
+// Operator 发起变更的调用方, 用于记录变更历史
+type Operator struct {
+	ClientID  string
+	Actor     string
+	RequestID string
+}
+
+// PolicyResource RBAC授权的资源实例
+type PolicyResource struct {
+	System string `json:"system"`
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+}
+
+// PolicySnapshot 策略在某一版本的内容
+type PolicySnapshot struct {
+	Expression  string `json:"expression"`
+	ExpiredAt   int64  `json:"expired_at"`
+	Effect      string `json:"effect"`
+	EffectiveAt int64  `json:"effective_at"`
+	// RBAC授权的资源实例, 按操作关联资源类型的顺序, 为空表示ABAC策略
+	Resources []PolicyResource `json:"resources,omitempty"`
+}
+
+// PolicyHistory 策略变更记录, Before/After为nil表示策略不存在
+type PolicyHistory struct {
+	Version    int64           `json:"version"`
+	ActionID   string          `json:"action_id"`
+	TemplateID int64           `json:"template_id"`
+	PolicyID   int64           `json:"policy_id"`
+	Operation  string          `json:"operation"`
+	Before     *PolicySnapshot `json:"before"`
+	After      *PolicySnapshot `json:"after"`
+	ClientID   string          `json:"client_id"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// PolicyChange 两个版本之间同一操作(模板)下策略的变化, 按effect区分, RBAC授权按资源实例区分
+type PolicyChange struct {
+	ActionID   string          `json:"action_id"`
+	TemplateID int64           `json:"template_id"`
+	Before     *PolicySnapshot `json:"before"`
+	After      *PolicySnapshot `json:"after"`
+}
+
+// GroupAlterEventPreview 预演时将会生成的用户组变更事件, 事件会展开为subject-action维度的RBAC鉴权变更
+type GroupAlterEventPreview struct {
+	GroupID            string `json:"group_id"`
+	SubjectCount       int64  `json:"subject_count"`
+	ActionCount        int64  `json:"action_count"`
+	SubjectActionCount int64  `json:"subject_action_count"`
+}
+
+// GroupMembersDryRunResult 用户组成员变更预演的结果
+type GroupMembersDryRunResult struct {
+	TypeCount            map[string]int64         `json:"type_count"`
+	CreatedMembers       []GroupMember            `json:"created_members"`
+	UpdatedMembers       []GroupMember            `json:"updated_members"`
+	DeletedMembers       []GroupMember            `json:"deleted_members,omitempty"`
+	AffectedSubjectCount int64                    `json:"affected_subject_count"`
+	Events               []GroupAlterEventPreview `json:"events"`
+}
+
+// PoliciesDryRunResult 用户组策略变更预演的结果
+type PoliciesDryRunResult struct {
+	CreatedPolicies      []PolicyChange           `json:"created_policies"`
+	UpdatedPolicies      []PolicyChange           `json:"updated_policies"`
+	DeletedPolicies      []PolicyChange           `json:"deleted_policies"`
+	AffectedSubjectCount int64                    `json:"affected_subject_count"`
+	Events               []GroupAlterEventPreview `json:"events"`
+}
+
+// PermissionResource 导出的RBAC授权资源实例
+type PermissionResource struct {
+	System string `json:"system"`
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+}
+
+// PermissionRecord 系统权限数据导出/导入的一行记录, 使用subject/action的ID代替PK, 便于跨环境迁移
+type PermissionRecord struct {
+	Kind    string  `json:"kind"`
+	Subject Subject `json:"subject"`
+
+	// group
+	AuthType string `json:"auth_type,omitempty"`
+	// group_member
+	Member *Subject `json:"member,omitempty"`
+
+	// policy / temporary_policy
+	ActionID   string `json:"action_id,omitempty"`
+	Expression string `json:"expression,omitempty"`
+	Effect     string `json:"effect,omitempty"`
+
+	// rbac_policy, 第一个为授权的资源实例, 其余为操作关联多个资源类型时的其它资源实例
+	ActionIDs []string             `json:"action_ids,omitempty"`
+	Resources []PermissionResource `json:"resources,omitempty"`
+
+	TemplateID int64 `json:"template_id,omitempty"`
+	ExpiredAt  int64 `json:"expired_at,omitempty"`
+	// policy / group_member 的生效时间, 0表示已生效
+	EffectiveAt int64 `json:"effective_at,omitempty"`
+}
+
+// PermissionImportConflict 导入时无法应用的记录, Line为记录在导入数据中的行号(从1开始)
+type PermissionImportConflict struct {
+	Line    int     `json:"line"`
+	Kind    string  `json:"kind"`
+	Subject Subject `json:"subject"`
+	Reason  string  `json:"reason"`
+}
+
+// PermissionImportReport 导入结果
+type PermissionImportReport struct {
+	Total     int                        `json:"total"`
+	Applied   int                        `json:"applied"`
+	Skipped   int                        `json:"skipped"`
+	Conflicts []PermissionImportConflict `json:"conflicts"`
+}
+
+// PolicyRedundancy 被生效的用户组权限覆盖(redundant)或部分重叠(overlapping)的自定义权限
+type PolicyRedundancy struct {
+	PolicyID   int64  `json:"policy_id"`
+	ActionID   string `json:"action_id"`
+	Expression string `json:"expression"`
+	ExpiredAt  int64  `json:"expired_at"`
+	Type       string `json:"type"`
+	// Groups 覆盖或重叠该权限的用户组
+	Groups []Subject `json:"groups"`
+}
+
+// PolicyRedundancyReport 冗余权限分析结果, DeletedPolicyIDs为清理时删除的冗余权限
+type PolicyRedundancyReport struct {
+	Policies         []PolicyRedundancy `json:"policies"`
+	DeletedPolicyIDs []int64            `json:"deleted_policy_ids"`
 }
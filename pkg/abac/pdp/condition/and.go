@@ -89,7 +89,7 @@ func (c *AndCondition) PartialEval(ctx types.EvalContextor) (bool, Condition) {
 	remainedContent := make([]Condition, 0, len(c.content))
 	for _, condition := range c.content {
 		switch condition.GetName() {
-		case operator.AND, operator.OR:
+		case operator.AND, operator.OR, operator.NOT:
 			// if AND/OR, do PartialEval recursive
 			ok, ci := condition.(LogicalCondition).PartialEval(ctx)
 			// a AND b, if a false, return false
@@ -320,6 +320,46 @@ var _ = Describe("And", func() {
 			})
 		})
 
+		Describe("negated, has remain", func() {
+			var c Condition
+			BeforeEach(func() {
+				c = NewAndCondition([]Condition{
+					&StringNotEqualsCondition{
+						baseCondition: baseCondition{
+							Key:   "host.system",
+							Value: []interface{}{"windows"},
+						},
+					},
+					&StringNotPrefixCondition{
+						baseCondition: baseCondition{
+							Key:   "subject.path",
+							Value: []interface{}{"/biz,1/"},
+						},
+					},
+				})
+			})
+
+			It("one true, one remain", func() {
+				allowed, nc := c.(LogicalCondition).PartialEval(MapCtx{
+					"host.system": "linux",
+				})
+				assert.True(GinkgoT(), allowed)
+
+				ct, err := nc.Translate(true)
+				assert.NoError(GinkgoT(), err)
+				got := map[string]interface{}{"field": "subject.path", "op": "not_starts_with", "value": "/biz,1/"}
+				assert.Equal(GinkgoT(), got, ct)
+			})
+
+			It("one false, one remain", func() {
+				allowed, nc := c.(LogicalCondition).PartialEval(MapCtx{
+					"host.system": "windows",
+				})
+				assert.False(GinkgoT(), allowed)
+				assert.Nil(GinkgoT(), nc)
+			})
+		})
+
 		Describe("Nested AND", func() {
 			var c Condition
 			BeforeEach(func() {
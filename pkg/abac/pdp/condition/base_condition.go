@@ -68,3 +68,32 @@ func (c *baseCondition) forOr(ctx types.EvalContextor, fn func(interface{}, inte
 	}
 	return false
 }
+
+// forNone value之间nor关系遍历, 属性的所有值与表达式的所有值都不满足fn时返回true
+// NOTE: 属性不存在时返回false
+func (c *baseCondition) forNone(ctx types.EvalContextor, fn func(interface{}, interface{}) bool) bool {
+	attrValue, err := ctx.GetAttr(c.Key)
+	if err != nil {
+		return false
+	}
+
+	exprValues := c.GetValues()
+
+	switch vs := attrValue.(type) {
+	case []interface{}: // 处理属性为array的情况
+		for _, av := range vs {
+			for _, v := range exprValues {
+				if fn(av, v) {
+					return false
+				}
+			}
+		}
+	default:
+		for _, v := range exprValues {
+			if fn(attrValue, v) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -224,4 +224,40 @@ var _ = Describe("BaseCondition", func() {
 			assert.False(GinkgoT(), condition.forOr(listCtx{3, 4}, fn))
 		})
 	})
+
+	Describe("forNone", func() {
+		var fn func(interface{}, interface{}) bool
+		var condition *baseCondition
+		BeforeEach(func() {
+			fn = func(a interface{}, b interface{}) bool {
+				return a == b
+			}
+			condition = &baseCondition{
+				Key: "key",
+				Value: []interface{}{
+					1,
+					2,
+				},
+			}
+		})
+
+		It("GetAttr fail", func() {
+			assert.False(GinkgoT(), condition.forNone(errCtx(1), fn))
+		})
+
+		It("single, hit one", func() {
+			assert.False(GinkgoT(), condition.forNone(intCtx(1), fn))
+			assert.False(GinkgoT(), condition.forNone(intCtx(2), fn))
+		})
+		It("single, missing ", func() {
+			assert.True(GinkgoT(), condition.forNone(intCtx(3), fn))
+		})
+
+		It("list, hit one", func() {
+			assert.False(GinkgoT(), condition.forNone(listCtx{2, 3}, fn))
+		})
+		It("list, missing", func() {
+			assert.True(GinkgoT(), condition.forNone(listCtx{3, 4}, fn))
+		})
+	})
 })
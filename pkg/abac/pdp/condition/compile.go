@@ -0,0 +1,509 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+
+	"iam/pkg/abac/pdp/condition/operator"
+	"iam/pkg/abac/pdp/types"
+	abacTypes "iam/pkg/abac/types"
+)
+
+// 前缀数量超过该值时使用前缀树匹配, 否则线性匹配
+const prefixTrieThreshold = 4
+
+// evaluator 编译后的求值器
+type evaluator interface {
+	eval(ctx types.EvalContextor) bool
+}
+
+// CompiledCondition 编译后的条件
+// 预先拆分属性key, 预先解析表达式的值(字符串集合/数值/前缀树/网段), 避免每次求值时重复的类型判断和key拆分
+// NOTE: 只用于Eval, PartialEval/Translate等仍然使用原始的条件
+type CompiledCondition struct {
+	Condition
+	evaluator evaluator
+}
+
+// Compile 编译条件
+func Compile(c Condition) *CompiledCondition {
+	return &CompiledCondition{
+		Condition: c,
+		evaluator: compileCondition(c),
+	}
+}
+
+// Eval 求值
+func (c *CompiledCondition) Eval(ctx types.EvalContextor) bool {
+	return c.evaluator.eval(ctx)
+}
+
+func compileCondition(c Condition) evaluator {
+	switch cond := c.(type) {
+	case *AndCondition:
+		return andEvaluator(compileContent(cond.content))
+	case *OrCondition:
+		return orEvaluator(compileContent(cond.content))
+	case *NotCondition:
+		return notEvaluator{content: compileCondition(cond.content[0])}
+	case *AnyCondition:
+		return anyEvaluator{}
+	case *StringEqualsCondition:
+		return newStringEqualsEvaluator(cond.Key, cond.Value)
+	case *StringPrefixCondition:
+		return newStringPrefixEvaluator(cond.Key, cond.Value)
+	case *NumericCompareCondition:
+		if e, ok := newNumericEvaluator(cond); ok {
+			return e
+		}
+	case *IPInCIDRCondition:
+		if e, ok := newIPInCIDREvaluator(cond.Key, cond.Value); ok {
+			return e
+		}
+	}
+
+	// 其它操作符直接使用原始条件求值
+	return fallbackEvaluator{c: c}
+}
+
+func compileContent(content []Condition) []evaluator {
+	evaluators := make([]evaluator, 0, len(content))
+	for _, c := range content {
+		evaluators = append(evaluators, compileCondition(c))
+	}
+	return evaluators
+}
+
+type fallbackEvaluator struct {
+	c Condition
+}
+
+func (e fallbackEvaluator) eval(ctx types.EvalContextor) bool {
+	return e.c.Eval(ctx)
+}
+
+type anyEvaluator struct{}
+
+func (anyEvaluator) eval(ctx types.EvalContextor) bool {
+	return true
+}
+
+type andEvaluator []evaluator
+
+func (e andEvaluator) eval(ctx types.EvalContextor) bool {
+	for _, c := range e {
+		if !c.eval(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+type orEvaluator []evaluator
+
+func (e orEvaluator) eval(ctx types.EvalContextor) bool {
+	for _, c := range e {
+		if c.eval(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+type notEvaluator struct {
+	content evaluator
+}
+
+func (e notEvaluator) eval(ctx types.EvalContextor) bool {
+	return !e.content.eval(ctx)
+}
+
+// attrSlot 预先拆分的属性key: {system}.{resource_type}.{attr_key} => {system}.{resource_type} 和 {attr_key}
+type attrSlot struct {
+	key   string
+	_type string
+	name  string
+	split bool
+}
+
+func newAttrSlot(key string) attrSlot {
+	s := attrSlot{key: key}
+	if dotIdx := strings.LastIndexByte(key, '.'); dotIdx != -1 {
+		s._type = key[:dotIdx]
+		s.name = key[dotIdx+1:]
+		s.split = true
+	}
+	return s
+}
+
+func (s attrSlot) get(ctx types.EvalContextor) (interface{}, bool) {
+	if s.split {
+		if getter, ok := ctx.(types.ObjectAttrGetter); ok {
+			return getter.GetObjectAttr(s._type, s.name), true
+		}
+	}
+
+	value, err := ctx.GetAttr(s.key)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// forOr 属性值为array时, 任意一个值满足fn即返回true
+func (s attrSlot) forOr(ctx types.EvalContextor, fn func(interface{}) bool) bool {
+	attrValue, ok := s.get(ctx)
+	if !ok {
+		return false
+	}
+
+	if vs, ok := attrValue.([]interface{}); ok {
+		for _, av := range vs {
+			if fn(av) {
+				return true
+			}
+		}
+		return false
+	}
+	return fn(attrValue)
+}
+
+// stringEqualsEvaluator 字符串值使用集合查找, 非字符串值保持原有的 == 比较
+type stringEqualsEvaluator struct {
+	attr    attrSlot
+	strings map[string]struct{}
+	others  []interface{}
+}
+
+func newStringEqualsEvaluator(key string, values []interface{}) evaluator {
+	e := &stringEqualsEvaluator{
+		attr:    newAttrSlot(key),
+		strings: make(map[string]struct{}, len(values)),
+	}
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			e.strings[s] = struct{}{}
+		} else {
+			e.others = append(e.others, v)
+		}
+	}
+	return e
+}
+
+func (e *stringEqualsEvaluator) eval(ctx types.EvalContextor) bool {
+	return e.attr.forOr(ctx, e.match)
+}
+
+func (e *stringEqualsEvaluator) match(a interface{}) bool {
+	if s, ok := a.(string); ok {
+		_, exists := e.strings[s]
+		return exists
+	}
+
+	for _, v := range e.others {
+		if a == v {
+			return true
+		}
+	}
+	return false
+}
+
+// stringPrefixEvaluator 前缀匹配, 前缀数量较多时(例如大量的_bk_iam_path_)使用前缀树
+type stringPrefixEvaluator struct {
+	attr     attrSlot
+	prefixes []string
+	trie     *prefixTrie
+}
+
+func newStringPrefixEvaluator(key string, values []interface{}) evaluator {
+	isIamPath := strings.HasSuffix(key, abacTypes.IamPathSuffix)
+
+	prefixes := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		// 非字符串的表达式值永远不会匹配
+		if !ok {
+			continue
+		}
+
+		// 支持表达式中最后一个节点为任意
+		// /biz,1/set,*/ -> /biz,1/set,
+		if isIamPath && strings.HasSuffix(s, ",*/") {
+			s = s[0 : len(s)-2]
+		}
+		prefixes = append(prefixes, s)
+	}
+
+	e := &stringPrefixEvaluator{attr: newAttrSlot(key)}
+	if len(prefixes) > prefixTrieThreshold {
+		e.trie = newPrefixTrie(prefixes)
+	} else {
+		e.prefixes = prefixes
+	}
+	return e
+}
+
+func (e *stringPrefixEvaluator) eval(ctx types.EvalContextor) bool {
+	return e.attr.forOr(ctx, e.match)
+}
+
+func (e *stringPrefixEvaluator) match(a interface{}) bool {
+	s, ok := a.(string)
+	if !ok {
+		return false
+	}
+
+	if e.trie != nil {
+		return e.trie.hasPrefixOf(s)
+	}
+
+	for _, p := range e.prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixTrie 字节前缀树
+type prefixTrie struct {
+	end      bool
+	children map[byte]*prefixTrie
+}
+
+func newPrefixTrie(prefixes []string) *prefixTrie {
+	root := &prefixTrie{}
+	for _, p := range prefixes {
+		node := root
+		for i := 0; i < len(p); i++ {
+			if node.children == nil {
+				node.children = make(map[byte]*prefixTrie)
+			}
+			child, ok := node.children[p[i]]
+			if !ok {
+				child = &prefixTrie{}
+				node.children[p[i]] = child
+			}
+			node = child
+		}
+		node.end = true
+	}
+	return root
+}
+
+// hasPrefixOf 树中是否存在s的前缀
+func (t *prefixTrie) hasPrefixOf(s string) bool {
+	node := t
+	for i := 0; ; i++ {
+		if node.end {
+			return true
+		}
+		if i == len(s) {
+			return false
+		}
+		child, ok := node.children[s[i]]
+		if !ok {
+			return false
+		}
+		node = child
+	}
+}
+
+// numericValue 预先解析的数值, 与iam-go-sdk中eval的比较规则保持一致:
+// json.Number 含有`.`时为float64, 否则为int64; int与float比较时统一转为float64
+type numericValue struct {
+	isFloat bool
+	i       int64
+	f       float64
+}
+
+func toNumericValue(v interface{}) (numericValue, bool) {
+	switch n := v.(type) {
+	case int:
+		return numericValue{i: int64(n)}, true
+	case int64:
+		return numericValue{i: n}, true
+	case int32:
+		return numericValue{i: int64(n)}, true
+	case float64:
+		return numericValue{isFloat: true, f: n}, true
+	case json.Number:
+		if strings.IndexByte(n.String(), '.') != -1 {
+			f, err := n.Float64()
+			if err != nil {
+				return numericValue{}, false
+			}
+			return numericValue{isFloat: true, f: f}, true
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return numericValue{}, false
+		}
+		return numericValue{i: i}, true
+	default:
+		return numericValue{}, false
+	}
+}
+
+// compare 返回 -1/0/1
+func (a numericValue) compare(b numericValue) int {
+	if a.isFloat || b.isFloat {
+		af, bf := a.f, b.f
+		if !a.isFloat {
+			af = float64(a.i)
+		}
+		if !b.isFloat {
+			bf = float64(b.i)
+		}
+		switch {
+		case af > bf:
+			return 1
+		case af < bf:
+			return -1
+		default:
+			return 0
+		}
+	}
+
+	switch {
+	case a.i > b.i:
+		return 1
+	case a.i < b.i:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// numericEvaluator 数值比较, 属性值不是常见的数值类型时回退到原始的compareFunc
+type numericEvaluator struct {
+	attr        attrSlot
+	values      []numericValue
+	rawValues   []interface{}
+	accept      func(int) bool
+	compareFunc numericCompareFunc
+}
+
+func newNumericEvaluator(c *NumericCompareCondition) (evaluator, bool) {
+	var accept func(int) bool
+	switch c.name {
+	case operator.NumericEquals:
+		accept = func(r int) bool { return r == 0 }
+	case operator.NumericGt:
+		accept = func(r int) bool { return r > 0 }
+	case operator.NumericGte:
+		accept = func(r int) bool { return r >= 0 }
+	case operator.NumericLt:
+		accept = func(r int) bool { return r < 0 }
+	case operator.NumericLte:
+		accept = func(r int) bool { return r <= 0 }
+	default:
+		return nil, false
+	}
+
+	// NOTE: >/>=/</<=的表达式value只允许配置一个
+	if c.name != operator.NumericEquals && len(c.Value) != 1 {
+		return nil, false
+	}
+
+	values := make([]numericValue, 0, len(c.Value))
+	for _, v := range c.Value {
+		nv, ok := toNumericValue(v)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, nv)
+	}
+
+	return &numericEvaluator{
+		attr:        newAttrSlot(c.Key),
+		values:      values,
+		rawValues:   c.Value,
+		accept:      accept,
+		compareFunc: c.compareFunc,
+	}, true
+}
+
+func (e *numericEvaluator) eval(ctx types.EvalContextor) bool {
+	return e.attr.forOr(ctx, e.match)
+}
+
+func (e *numericEvaluator) match(a interface{}) bool {
+	av, ok := toNumericValue(a)
+	if !ok {
+		for _, v := range e.rawValues {
+			if e.compareFunc(a, v) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, v := range e.values {
+		if e.accept(av.compare(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipInCIDREvaluator 预先解析的网段
+type ipInCIDREvaluator struct {
+	attr attrSlot
+	nets []*net.IPNet
+}
+
+func newIPInCIDREvaluator(key string, values []interface{}) (evaluator, bool) {
+	nets := make([]*net.IPNet, 0, len(values))
+	for _, v := range values {
+		cidr, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+
+		ipNet, err := parseCIDR(cidr)
+		if err != nil {
+			return nil, false
+		}
+		nets = append(nets, ipNet)
+	}
+
+	return &ipInCIDREvaluator{
+		attr: newAttrSlot(key),
+		nets: nets,
+	}, true
+}
+
+func (e *ipInCIDREvaluator) eval(ctx types.EvalContextor) bool {
+	return e.attr.forOr(ctx, e.match)
+}
+
+func (e *ipInCIDREvaluator) match(a interface{}) bool {
+	s, ok := a.(string)
+	if !ok {
+		return false
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(s))
+	if ip == nil {
+		return false
+	}
+
+	for _, ipNet := range e.nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/pdp/types"
+	abacTypes "iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+)
+
+func newHostEvalContext(attrs map[string]interface{}) types.EvalContextor {
+	return types.NewEvalContext(&request.Request{
+		Resources: []abacTypes.Resource{
+			{
+				System:    "bk_cmdb",
+				Type:      "host",
+				ID:        "1",
+				Attribute: attrs,
+			},
+		},
+	})
+}
+
+func newLargePathCondition(n int) Condition {
+	prefixes := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		prefixes = append(prefixes, fmt.Sprintf("/biz,%d/set,*/", i))
+	}
+	ids := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		ids = append(ids, fmt.Sprintf("host%d", i))
+	}
+
+	return NewOrCondition([]Condition{
+		&StringEqualsCondition{baseCondition{Key: "bk_cmdb.host.id", Value: ids}},
+		NewAndCondition([]Condition{
+			&StringPrefixCondition{baseCondition{Key: "bk_cmdb.host._bk_iam_path_", Value: prefixes}},
+			&StringEqualsCondition{baseCondition{Key: "bk_cmdb.host.os", Value: []interface{}{"linux"}}},
+		}),
+	})
+}
+
+var _ = Describe("Compile", func() {
+	It("keep the origin condition", func() {
+		c := NewAnyCondition()
+		cc := Compile(c)
+		assert.Equal(GinkgoT(), c, cc.Condition)
+		assert.Equal(GinkgoT(), c.GetName(), cc.GetName())
+		assert.True(GinkgoT(), cc.Eval(strCtx("a")))
+	})
+
+	It("fallback for not optimized operator", func() {
+		c, err := newStringContainsCondition("k", []interface{}{"abc"})
+		assert.NoError(GinkgoT(), err)
+
+		cc := Compile(c)
+		assert.IsType(GinkgoT(), fallbackEvaluator{}, cc.evaluator)
+		assert.True(GinkgoT(), cc.Eval(strCtx("xabcx")))
+		assert.False(GinkgoT(), cc.Eval(strCtx("xyz")))
+	})
+
+	It("prefix trie", func() {
+		t := newPrefixTrie([]string{"/biz,1/", "/biz,2/set,", "/biz,22/"})
+		assert.True(GinkgoT(), t.hasPrefixOf("/biz,1/"))
+		assert.True(GinkgoT(), t.hasPrefixOf("/biz,1/set,3/"))
+		assert.True(GinkgoT(), t.hasPrefixOf("/biz,2/set,3/"))
+		assert.True(GinkgoT(), t.hasPrefixOf("/biz,22/set,3/"))
+		assert.False(GinkgoT(), t.hasPrefixOf("/biz,2/"))
+		assert.False(GinkgoT(), t.hasPrefixOf("/biz,3/"))
+		assert.False(GinkgoT(), t.hasPrefixOf(""))
+
+		assert.True(GinkgoT(), newPrefixTrie([]string{""}).hasPrefixOf("abc"))
+	})
+
+	DescribeTable("same result as the origin condition", func(c Condition, ctx types.EvalContextor) {
+		assert.Equal(GinkgoT(), c.Eval(ctx), Compile(c).Eval(ctx))
+	},
+		Entry("eq hit", &StringEqualsCondition{baseCondition{Key: "k", Value: []interface{}{"a", "b"}}}, strCtx("b")),
+		Entry("eq miss", &StringEqualsCondition{baseCondition{Key: "k", Value: []interface{}{"a", "b"}}}, strCtx("c")),
+		Entry("eq not string", &StringEqualsCondition{baseCondition{Key: "k", Value: []interface{}{1}}}, intCtx(1)),
+		Entry("eq list", &StringEqualsCondition{baseCondition{Key: "k", Value: []interface{}{"a"}}},
+			listCtx{"c", "a"}),
+		Entry("eq err", &StringEqualsCondition{baseCondition{Key: "k", Value: []interface{}{"a"}}}, errCtx(1)),
+
+		Entry("prefix hit", &StringPrefixCondition{baseCondition{Key: "k", Value: []interface{}{"/biz,1/"}}},
+			strCtx("/biz,1/set,2/")),
+		Entry("prefix miss", &StringPrefixCondition{baseCondition{Key: "k", Value: []interface{}{"/biz,1/"}}},
+			strCtx("/biz,2/")),
+		Entry("prefix not string", &StringPrefixCondition{baseCondition{Key: "k", Value: []interface{}{1}}},
+			intCtx(1)),
+		Entry("prefix iam path any", &StringPrefixCondition{baseCondition{
+			Key: "s.t._bk_iam_path_", Value: []interface{}{"/biz,1/set,*/"},
+		}}, strCtx("/biz,1/set,2/")),
+		Entry("prefix trie hit", newLargePathCondition(10).(*OrCondition).content[1].(*AndCondition).content[0],
+			strCtx("/biz,5/set,2/")),
+		Entry("prefix trie miss", newLargePathCondition(10).(*OrCondition).content[1].(*AndCondition).content[0],
+			strCtx("/biz,11/set,2/")),
+
+		Entry("numeric eq int", &NumericCompareCondition{
+			baseCondition: baseCondition{Key: "k", Value: []interface{}{1, 2}},
+			name:          "NumericEquals", compareFunc: numericEqualsFunc,
+		}, int64Ctx(2)),
+		Entry("numeric eq json number", &NumericCompareCondition{
+			baseCondition: baseCondition{Key: "k", Value: []interface{}{json.Number("2.0")}},
+			name:          "NumericEquals", compareFunc: numericEqualsFunc,
+		}, intCtx(2)),
+		Entry("numeric eq string attr", &NumericCompareCondition{
+			baseCondition: baseCondition{Key: "k", Value: []interface{}{1}},
+			name:          "NumericEquals", compareFunc: numericEqualsFunc,
+		}, strCtx("1")),
+		Entry("numeric gt", mustNewCondition(newNumericGreaterThanCondition("k", []interface{}{1})), intCtx(2)),
+		Entry("numeric gte", mustNewCondition(newNumericGreaterThanEqualsCondition("k", []interface{}{2.5})),
+			intCtx(2)),
+		Entry("numeric lt", mustNewCondition(newNumericLessThanCondition("k", []interface{}{3})), listCtx{5, 1}),
+		Entry("numeric lte", mustNewCondition(newNumericLessThanEqualsCondition("k", []interface{}{1})), intCtx(2)),
+		Entry("numeric not eq", mustNewCondition(newNumericNotEqualsCondition("k", []interface{}{1})), intCtx(2)),
+
+		Entry("ip hit", mustNewCondition(newIPInCIDRCondition("k", []interface{}{"10.0.0.0/8"})),
+			strCtx("10.1.1.1")),
+		Entry("ip miss", mustNewCondition(newIPInCIDRCondition("k", []interface{}{"10.0.0.0/8"})),
+			strCtx("11.1.1.1")),
+
+		Entry("not", NewNotCondition(&StringEqualsCondition{baseCondition{Key: "k", Value: []interface{}{"a"}}}),
+			strCtx("a")),
+
+		Entry("eval context hit id", newLargePathCondition(10),
+			newHostEvalContext(map[string]interface{}{"os": "windows"})),
+		Entry("eval context hit path", newLargePathCondition(10), newHostEvalContext(map[string]interface{}{
+			"os": "linux", "_bk_iam_path_": []interface{}{"/biz,100/", "/biz,3/set,1/"},
+		})),
+		Entry("eval context miss", newLargePathCondition(10), newHostEvalContext(map[string]interface{}{
+			"os": "windows", "_bk_iam_path_": []interface{}{"/biz,3/set,1/"},
+		})),
+		Entry("eval context missing attr", newLargePathCondition(10), newHostEvalContext(nil)),
+	)
+})
+
+var numericEqualsFunc = mustNewCondition(newNumericEqualsCondition("k", nil)).(*NumericCompareCondition).compareFunc
+
+func mustNewCondition(c Condition, err error) Condition {
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func BenchmarkEvalCondition(b *testing.B) {
+	c := newLargePathCondition(200)
+	ctx := newHostEvalContext(map[string]interface{}{
+		"os": "linux", "_bk_iam_path_": []interface{}{"/biz,199/set,1/"},
+	})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Eval(ctx)
+	}
+}
+
+func BenchmarkEvalCompiledCondition(b *testing.B) {
+	c := Compile(newLargePathCondition(200))
+	ctx := newHostEvalContext(map[string]interface{}{
+		"os": "linux", "_bk_iam_path_": []interface{}{"/biz,199/set,1/"},
+	})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Eval(ctx)
+	}
+}
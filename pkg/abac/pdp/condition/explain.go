@@ -0,0 +1,68 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	"iam/pkg/abac/pdp/condition/operator"
+	"iam/pkg/abac/pdp/types"
+	abacTypes "iam/pkg/abac/types"
+)
+
+// ExplainFailedConditions 返回求值不满足的条件节点, 条件满足时返回空
+// - AND: 返回所有不满足的子条件
+// - OR: 所有子条件都不满足, 返回所有子条件中不满足的节点
+func ExplainFailedConditions(c Condition, ctx types.EvalContextor) []abacTypes.ConditionExplanation {
+	if c.Eval(ctx) {
+		return nil
+	}
+
+	return explainFailedCondition(c, ctx)
+}
+
+// explainFailedCondition 调用方保证c求值为false
+func explainFailedCondition(c Condition, ctx types.EvalContextor) []abacTypes.ConditionExplanation {
+	var content []Condition
+	switch cond := c.(type) {
+	case *AndCondition:
+		content = cond.content
+	case *OrCondition:
+		content = cond.content
+	}
+
+	if content != nil {
+		explanations := make([]abacTypes.ConditionExplanation, 0, len(content))
+		for _, ci := range content {
+			if !ci.Eval(ctx) {
+				explanations = append(explanations, explainFailedCondition(ci, ctx)...)
+			}
+		}
+		return explanations
+	}
+
+	// NOT / 叶子节点, 记录属性的实际值与期望值
+	explanation := abacTypes.ConditionExplanation{
+		Operator: c.GetName(),
+	}
+
+	keys := c.GetKeys()
+	if len(keys) > 0 {
+		explanation.Key = keys[0]
+		explanation.Actual, _ = ctx.GetAttr(keys[0])
+	}
+
+	if c.GetName() != operator.NOT {
+		if vc, ok := c.(interface{ GetValues() []interface{} }); ok {
+			explanation.Expected = vc.GetValues()
+		}
+	}
+
+	return []abacTypes.ConditionExplanation{explanation}
+}
@@ -0,0 +1,88 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	abacTypes "iam/pkg/abac/types"
+)
+
+var _ = Describe("Explain", func() {
+	var eqCondition, prefixCondition Condition
+	BeforeEach(func() {
+		eqCondition, _ = newStringEqualsCondition("host.system", []interface{}{"linux"})
+		prefixCondition, _ = newStringPrefixCondition("host.path", []interface{}{"/biz,1/"})
+	})
+
+	It("pass, empty", func() {
+		c := NewAndCondition([]Condition{eqCondition, prefixCondition})
+		ctx := MapCtx{"host.system": "linux", "host.path": "/biz,1/set,2/"}
+		assert.Empty(GinkgoT(), ExplainFailedConditions(c, ctx))
+	})
+
+	It("leaf", func() {
+		ctx := MapCtx{"host.system": "windows"}
+		assert.Equal(GinkgoT(), []abacTypes.ConditionExplanation{
+			{
+				Operator: "StringEquals",
+				Key:      "host.system",
+				Expected: []interface{}{"linux"},
+				Actual:   "windows",
+			},
+		}, ExplainFailedConditions(eqCondition, ctx))
+	})
+
+	It("and, only failed content", func() {
+		c := NewAndCondition([]Condition{eqCondition, prefixCondition})
+		ctx := MapCtx{"host.system": "linux", "host.path": "/biz,2/"}
+		assert.Equal(GinkgoT(), []abacTypes.ConditionExplanation{
+			{
+				Operator: "StringPrefix",
+				Key:      "host.path",
+				Expected: []interface{}{"/biz,1/"},
+				Actual:   "/biz,2/",
+			},
+		}, ExplainFailedConditions(c, ctx))
+	})
+
+	It("or, all content", func() {
+		c := NewOrCondition([]Condition{eqCondition, NewAndCondition([]Condition{prefixCondition})})
+		ctx := MapCtx{"host.system": "windows"}
+		assert.Equal(GinkgoT(), []abacTypes.ConditionExplanation{
+			{
+				Operator: "StringEquals",
+				Key:      "host.system",
+				Expected: []interface{}{"linux"},
+				Actual:   "windows",
+			},
+			{
+				Operator: "StringPrefix",
+				Key:      "host.path",
+				Expected: []interface{}{"/biz,1/"},
+				Actual:   nil,
+			},
+		}, ExplainFailedConditions(c, ctx))
+	})
+
+	It("not", func() {
+		c := NewNotCondition(eqCondition)
+		ctx := MapCtx{"host.system": "linux"}
+		assert.Equal(GinkgoT(), []abacTypes.ConditionExplanation{
+			{
+				Operator: "NOT",
+				Key:      "host.system",
+				Actual:   "linux",
+			},
+		}, ExplainFailedConditions(c, ctx))
+	})
+})
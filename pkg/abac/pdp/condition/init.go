@@ -40,21 +40,39 @@ func init() {
 	//       2. https://bk.tencent.com/docs/document/6.0/160/8466
 
 	conditionFactories = map[string]conditionFunc{
-		operator.AND:            newAndCondition,
-		operator.OR:             newOrCondition,
-		operator.ANY:            newAnyCondition,
-		operator.StringEquals:   newStringEqualsCondition,
-		operator.StringPrefix:   newStringPrefixCondition,
-		operator.StringContains: newStringContainsCondition,
-		operator.Bool:           newBoolCondition,
-		operator.NumericEquals:  newNumericEqualsCondition,
-		operator.NumericGt:      newNumericGreaterThanCondition,
-		operator.NumericGte:     newNumericGreaterThanEqualsCondition,
-		operator.NumericLt:      newNumericLessThanCondition,
-		operator.NumericLte:     newNumericLessThanEqualsCondition,
+		operator.AND:               newAndCondition,
+		operator.OR:                newOrCondition,
+		operator.ANY:               newAnyCondition,
+		operator.StringEquals:      newStringEqualsCondition,
+		operator.StringPrefix:      newStringPrefixCondition,
+		operator.StringContains:    newStringContainsCondition,
+		operator.StringSuffix:      newStringSuffixCondition,
+		operator.StringMatch:       newStringMatchCondition,
+		operator.StringNotEquals:   newStringNotEqualsCondition,
+		operator.StringNotPrefix:   newStringNotPrefixCondition,
+		operator.StringNotContains: newStringNotContainsCondition,
+		operator.Bool:              newBoolCondition,
+		operator.NumericEquals:     newNumericEqualsCondition,
+		operator.NumericNotEquals:  newNumericNotEqualsCondition,
+		operator.NumericGt:         newNumericGreaterThanCondition,
+		operator.NumericGte:        newNumericGreaterThanEqualsCondition,
+		operator.NumericLt:         newNumericLessThanCondition,
+		operator.NumericLte:        newNumericLessThanEqualsCondition,
+		operator.IPInCIDR:          newIPInCIDRCondition,
 	}
 }
 
+// IsSupportedOperator 是否为支持的属性条件操作符, 不包括逻辑操作符AND/OR/Any
+func IsSupportedOperator(op string) bool {
+	switch op {
+	case operator.AND, operator.OR, operator.ANY:
+		return false
+	}
+
+	_, ok := conditionFactories[op]
+	return ok
+}
+
 // Condition 条件接口
 type Condition interface {
 	GetName() string
@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"iam/pkg/abac/pdp/condition/operator"
+	"iam/pkg/abac/pdp/types"
+)
+
+// IPInCIDRCondition IP地址属于网段
+// 表达式的值支持网段 `10.0.0.0/8` 或单个IP `10.0.0.1`, 单个IP等价于 /32 (IPv6 为 /128)
+type IPInCIDRCondition struct {
+	baseCondition
+}
+
+func newIPInCIDRCondition(key string, values []interface{}) (Condition, error) {
+	for _, v := range values {
+		cidr, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("cidr should be string, got %v", v)
+		}
+
+		if _, err := parseCIDR(cidr); err != nil {
+			return nil, err
+		}
+	}
+
+	return &IPInCIDRCondition{
+		baseCondition: baseCondition{
+			Key:   key,
+			Value: values,
+		},
+	}, nil
+}
+
+// GetName 名称
+func (c *IPInCIDRCondition) GetName() string {
+	return operator.IPInCIDR
+}
+
+// Eval 求值
+func (c *IPInCIDRCondition) Eval(ctx types.EvalContextor) bool {
+	return c.forOr(ctx, func(a, b interface{}) bool {
+		aStr, ok := a.(string)
+		if !ok {
+			return false
+		}
+
+		bStr, ok := b.(string)
+		if !ok {
+			return false
+		}
+
+		ip := net.ParseIP(strings.TrimSpace(aStr))
+		if ip == nil {
+			return false
+		}
+
+		ipNet, err := parseCIDR(bStr)
+		if err != nil {
+			return false
+		}
+
+		return ipNet.Contains(ip)
+	})
+}
+
+func (c *IPInCIDRCondition) Translate(withSystem bool) (map[string]interface{}, error) {
+	key := c.Key
+	if !withSystem {
+		key = removeSystemFromKey(key)
+	}
+
+	// NOTE: same as starts_with, multiple value should be `a in_cidr x OR a in_cidr y`
+	content := make([]map[string]interface{}, 0, len(c.Value))
+	for _, v := range c.Value {
+		content = append(content, map[string]interface{}{
+			"op":    "in_cidr",
+			"field": key,
+			"value": v,
+		})
+	}
+
+	switch len(content) {
+	case 0:
+		return nil, errMustNotEmpty
+	case 1:
+		return content[0], nil
+	default:
+		return map[string]interface{}{
+			"op":      "OR",
+			"content": content,
+		}, nil
+	}
+}
+
+// parseCIDR 解析网段, 单个IP转换为只包含自身的网段
+func parseCIDR(cidr string) (*net.IPNet, error) {
+	cidr = strings.TrimSpace(cidr)
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip %s", cidr)
+		}
+
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+			bits = 8 * net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cidr %s, %w", cidr, err)
+	}
+	return ipNet, nil
+}
@@ -0,0 +1,129 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("IPInCIDR", func() {
+	var c Condition
+	BeforeEach(func() {
+		var err error
+		c, err = newIPInCIDRCondition("ok", []interface{}{"10.0.0.0/8", "192.168.1.1", "fd00::/8"})
+		assert.NoError(GinkgoT(), err)
+	})
+
+	Describe("new", func() {
+		It("ok", func() {
+			condition, err := newIPInCIDRCondition("ok", []interface{}{"10.0.0.0/8"})
+			assert.NoError(GinkgoT(), err)
+			assert.NotNil(GinkgoT(), condition)
+		})
+
+		It("fail, not string", func() {
+			_, err := newIPInCIDRCondition("ok", []interface{}{1})
+			assert.Error(GinkgoT(), err)
+		})
+
+		It("fail, invalid cidr", func() {
+			_, err := newIPInCIDRCondition("ok", []interface{}{"10.0.0.0/33"})
+			assert.Error(GinkgoT(), err)
+
+			_, err = newIPInCIDRCondition("ok", []interface{}{"abc"})
+			assert.Error(GinkgoT(), err)
+		})
+	})
+
+	It("GetName", func() {
+		assert.Equal(GinkgoT(), "IPInCIDR", c.GetName())
+	})
+
+	Context("Eval", func() {
+		It("true", func() {
+			assert.True(GinkgoT(), c.Eval(strCtx("10.1.2.3")))
+			assert.True(GinkgoT(), c.Eval(strCtx("192.168.1.1")))
+			assert.True(GinkgoT(), c.Eval(strCtx("fd00::1")))
+		})
+
+		It("false", func() {
+			assert.False(GinkgoT(), c.Eval(strCtx("11.0.0.1")))
+			assert.False(GinkgoT(), c.Eval(strCtx("192.168.1.2")))
+			assert.False(GinkgoT(), c.Eval(strCtx("2001:db8::1")))
+		})
+
+		It("false, invalid ip", func() {
+			assert.False(GinkgoT(), c.Eval(strCtx("10.0.0")))
+			assert.False(GinkgoT(), c.Eval(intCtx(1)))
+		})
+
+		It("attr list", func() {
+			assert.True(GinkgoT(), c.Eval(listCtx{"11.0.0.1", "10.0.0.1"}))
+			assert.False(GinkgoT(), c.Eval(listCtx{"11.0.0.1", "127.0.0.1"}))
+		})
+	})
+
+	Describe("Translate", func() {
+		It("fail, empty value", func() {
+			c, err := newIPInCIDRCondition("key", []interface{}{})
+			assert.NoError(GinkgoT(), err)
+
+			_, err = c.Translate(true)
+			assert.Error(GinkgoT(), err)
+			assert.Equal(GinkgoT(), errMustNotEmpty, err)
+		})
+
+		It("ok, single", func() {
+			expected := map[string]interface{}{
+				"op":    "in_cidr",
+				"field": "key",
+				"value": "10.0.0.0/8",
+			}
+			c, err := newIPInCIDRCondition("key", []interface{}{"10.0.0.0/8"})
+			assert.NoError(GinkgoT(), err)
+			ec, err := c.Translate(true)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, ec)
+		})
+
+		It("ok, multiple or, withSystem=False", func() {
+			expected := map[string]interface{}{
+				"op": "OR",
+				"content": []map[string]interface{}{
+					{
+						"op":    "in_cidr",
+						"field": "_bk_iam_env_.client_ip",
+						"value": "10.0.0.0/8",
+					},
+					{
+						"op":    "in_cidr",
+						"field": "_bk_iam_env_.client_ip",
+						"value": "172.16.0.0/12",
+					},
+				},
+			}
+			c, err := newIPInCIDRCondition("bk_job._bk_iam_env_.client_ip", []interface{}{"10.0.0.0/8", "172.16.0.0/12"})
+			assert.NoError(GinkgoT(), err)
+			ec, err := c.Translate(false)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, ec)
+		})
+
+		It("ok, negated", func() {
+			c, err := newIPInCIDRCondition("key", []interface{}{"10.0.0.0/8"})
+			assert.NoError(GinkgoT(), err)
+			ec, err := NewNotCondition(c).Translate(true)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "not_in_cidr", ec["op"])
+		})
+	})
+})
@@ -0,0 +1,163 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"iam/pkg/abac/pdp/condition/operator"
+	"iam/pkg/abac/pdp/types"
+)
+
+var errNegateAny = errors.New("can not negate any")
+
+// negatedTranslateOperators 转换后的表达式操作符与其取反的操作符
+var negatedTranslateOperators = map[string]string{
+	"eq":                  "not_eq",
+	"not_eq":              "eq",
+	"in":                  "not_in",
+	"not_in":              "in",
+	"starts_with":         "not_starts_with",
+	"not_starts_with":     "starts_with",
+	"ends_with":           "not_ends_with",
+	"not_ends_with":       "ends_with",
+	"contains":            "not_contains",
+	"not_contains":        "contains",
+	"string_contains":     "not_string_contains",
+	"not_string_contains": "string_contains",
+	"match":               "not_match",
+	"not_match":           "match",
+	"in_cidr":             "not_in_cidr",
+	"not_in_cidr":         "in_cidr",
+	"lt":                  "gte",
+	"gte":                 "lt",
+	"lte":                 "gt",
+	"gt":                  "lte",
+}
+
+// NotCondition 逻辑NOT
+// NOTE: 只用于deny策略在query时生成取反的残留条件, 不支持从策略表达式中反序列化
+type NotCondition struct {
+	baseLogicalCondition
+}
+
+func NewNotCondition(content Condition) Condition {
+	return &NotCondition{
+		baseLogicalCondition{
+			content: []Condition{content},
+		},
+	}
+}
+
+// GetName 名称
+func (c *NotCondition) GetName() string {
+	return operator.NOT
+}
+
+// Eval 求值
+func (c *NotCondition) Eval(ctx types.EvalContextor) bool {
+	return !c.content[0].Eval(ctx)
+}
+
+// Translate 转换为取反的表达式, NOT(a AND b) => (NOT a) OR (NOT b)
+func (c *NotCondition) Translate(withSystem bool) (map[string]interface{}, error) {
+	expr, err := c.content[0].Translate(withSystem)
+	if err != nil {
+		return nil, err
+	}
+
+	return negateExprCell(expr)
+}
+
+// PartialEval 使用传递的部分资源执行表达式, 并返回剩余的部分
+func (c *NotCondition) PartialEval(ctx types.EvalContextor) (bool, Condition) {
+	content := c.content[0]
+
+	switch content.GetName() {
+	case operator.AND, operator.OR, operator.NOT:
+		ok, ci := content.(LogicalCondition).PartialEval(ctx)
+		// NOT false => true
+		if !ok {
+			return true, NewAnyCondition()
+		}
+		// NOT true => false
+		if ci.GetName() == operator.ANY {
+			return false, nil
+		}
+		return true, NewNotCondition(ci)
+	case operator.ANY:
+		return false, nil
+	default:
+		key := content.GetKeys()[0]
+		dotIdx := strings.LastIndexByte(key, '.')
+		if dotIdx == -1 {
+			return false, nil
+		}
+		_type := key[:dotIdx]
+
+		if ctx.HasResource(_type) {
+			if content.Eval(ctx) {
+				return false, nil
+			}
+			return true, NewAnyCondition()
+		}
+
+		// request has no resource, so return self
+		return true, c
+	}
+}
+
+// negateExprCell 对转换后的表达式取反
+func negateExprCell(expr map[string]interface{}) (map[string]interface{}, error) {
+	op, _ := expr["op"].(string)
+
+	switch op {
+	case "AND", "OR":
+		content, ok := expr["content"].([]map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid logical expression content %+v", expr["content"])
+		}
+
+		negatedContent := make([]map[string]interface{}, 0, len(content))
+		for _, ci := range content {
+			nci, err := negateExprCell(ci)
+			if err != nil {
+				return nil, err
+			}
+			negatedContent = append(negatedContent, nci)
+		}
+
+		negatedOp := "AND"
+		if op == "AND" {
+			negatedOp = "OR"
+		}
+
+		return map[string]interface{}{
+			"op":      negatedOp,
+			"content": negatedContent,
+		}, nil
+	case "any":
+		return nil, errNegateAny
+	default:
+		negatedOp, ok := negatedTranslateOperators[op]
+		if !ok {
+			return nil, fmt.Errorf("can not negate operator %s", op)
+		}
+
+		return map[string]interface{}{
+			"op":    negatedOp,
+			"field": expr["field"],
+			"value": expr["value"],
+		}, nil
+	}
+}
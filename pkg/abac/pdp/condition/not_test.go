@@ -0,0 +1,117 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/pdp/condition/operator"
+)
+
+var _ = Describe("Not", func() {
+	var eqCondition Condition
+	BeforeEach(func() {
+		eqCondition = &StringEqualsCondition{
+			baseCondition: baseCondition{
+				Key:   "host.system",
+				Value: []interface{}{"linux"},
+			},
+		}
+	})
+
+	It("New", func() {
+		c := NewNotCondition(eqCondition)
+		assert.NotNil(GinkgoT(), c)
+		assert.Equal(GinkgoT(), operator.NOT, c.GetName())
+	})
+
+	It("Eval", func() {
+		c := NewNotCondition(eqCondition)
+		assert.False(GinkgoT(), c.Eval(strCtx("linux")))
+		assert.True(GinkgoT(), c.Eval(strCtx("windows")))
+	})
+
+	Describe("Translate", func() {
+		It("ok, single", func() {
+			c := NewNotCondition(eqCondition)
+			ec, err := c.Translate(true)
+			assert.NoError(GinkgoT(), err)
+			want := map[string]interface{}{"field": "host.system", "op": "not_eq", "value": "linux"}
+			assert.Equal(GinkgoT(), want, ec)
+		})
+
+		It("ok, and", func() {
+			c := NewNotCondition(NewAndCondition([]Condition{
+				eqCondition,
+				&StringPrefixCondition{
+					baseCondition: baseCondition{
+						Key:   "host.path",
+						Value: []interface{}{"/biz,1/"},
+					},
+				},
+			}))
+			ec, err := c.Translate(true)
+			assert.NoError(GinkgoT(), err)
+			want := map[string]interface{}{
+				"op": "OR",
+				"content": []map[string]interface{}{
+					{"field": "host.system", "op": "not_eq", "value": "linux"},
+					{"field": "host.path", "op": "not_starts_with", "value": "/biz,1/"},
+				},
+			}
+			assert.Equal(GinkgoT(), want, ec)
+		})
+
+		It("fail, any", func() {
+			c := NewNotCondition(NewAnyCondition())
+			_, err := c.Translate(true)
+			assert.Error(GinkgoT(), err)
+		})
+	})
+
+	Describe("PartialEval", func() {
+		It("hit, true", func() {
+			c := NewNotCondition(eqCondition)
+			allowed, nc := c.(LogicalCondition).PartialEval(HitStrCtx("windows"))
+			assert.True(GinkgoT(), allowed)
+			assert.Equal(GinkgoT(), NewAnyCondition(), nc)
+		})
+
+		It("hit, false", func() {
+			c := NewNotCondition(eqCondition)
+			allowed, nc := c.(LogicalCondition).PartialEval(HitStrCtx("linux"))
+			assert.False(GinkgoT(), allowed)
+			assert.Nil(GinkgoT(), nc)
+		})
+
+		It("remain", func() {
+			c := NewNotCondition(eqCondition)
+			allowed, nc := c.(LogicalCondition).PartialEval(MissStrCtx("linux"))
+			assert.True(GinkgoT(), allowed)
+			assert.Equal(GinkgoT(), c, nc)
+		})
+
+		It("nested, remain", func() {
+			c := NewNotCondition(NewOrCondition([]Condition{eqCondition}))
+			allowed, nc := c.(LogicalCondition).PartialEval(MissStrCtx("linux"))
+			assert.True(GinkgoT(), allowed)
+			assert.Equal(GinkgoT(), NewNotCondition(eqCondition), nc)
+		})
+
+		It("any", func() {
+			c := NewNotCondition(NewAnyCondition())
+			allowed, nc := c.(LogicalCondition).PartialEval(HitStrCtx("linux"))
+			assert.False(GinkgoT(), allowed)
+			assert.Nil(GinkgoT(), nc)
+		})
+	})
+})
@@ -58,6 +58,11 @@ func newNumericEqualsCondition(key string, values []interface{}) (Condition, err
 		operator.NumericEquals, "eq", "in", eval.ValueEqual)
 }
 
+func newNumericNotEqualsCondition(key string, values []interface{}) (Condition, error) {
+	return newNumericCompareCondition(key, values,
+		operator.NumericNotEquals, "not_eq", "not_in", eval.ValueEqual)
+}
+
 func newNumericGreaterThanCondition(key string, values []interface{}) (Condition, error) {
 	return newNumericCompareCondition(key, values,
 		operator.NumericGt, "gt", "gt", eval.Greater)
@@ -91,6 +96,13 @@ func (c *NumericCompareCondition) Eval(ctx types.EvalContextor) bool {
 		})
 	}
 
+	// 不等于: 属性的所有值都不等于表达式中的任意值
+	if c.name == operator.NumericNotEquals {
+		return c.forNone(ctx, func(a, b interface{}) bool {
+			return c.compareFunc(a, b)
+		})
+	}
+
 	// NOTE: >/>=/</<=的表达式value只允许配置一个
 	exprValues := c.GetValues()
 	if len(exprValues) != 1 {
@@ -136,8 +148,8 @@ func (c *NumericCompareCondition) Translate(withSystem bool) (map[string]interfa
 		exprCell["value"] = c.Value[0]
 		return exprCell, nil
 	default:
-		// NOTE: >/>=/</<=的表达式value只允许配置一个, 只有eq可能有多个
-		if c.translateOperator != "eq" {
+		// NOTE: >/>=/</<=的表达式value只允许配置一个, 只有eq/not_eq可能有多个
+		if c.translateOperator != "eq" && c.translateOperator != "not_eq" {
 			return nil, fmt.Errorf("%s not support multi value %+v", c.translateOperator, c.Value)
 		}
 
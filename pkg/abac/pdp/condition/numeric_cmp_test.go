@@ -23,6 +23,7 @@ var _ = Describe("NumericCompare", func() {
 	var values []interface{}
 
 	var eqCondition Condition
+	var notEqCondition Condition
 	var gtCondition Condition
 	var gteCondition Condition
 	var ltCondition Condition
@@ -32,6 +33,7 @@ var _ = Describe("NumericCompare", func() {
 		key = "ok"
 		values = []interface{}{1, 2}
 		eqCondition, _ = newNumericEqualsCondition(key, values)
+		notEqCondition, _ = newNumericNotEqualsCondition(key, values)
 
 		singleValues := []interface{}{2}
 		gtCondition, _ = newNumericGreaterThanCondition(key, singleValues)
@@ -60,6 +62,13 @@ var _ = Describe("NumericCompare", func() {
 			assert.Equal(GinkgoT(), operator.NumericEquals, c.GetName())
 		})
 
+		It("not eq", func() {
+			c, err := newNumericNotEqualsCondition(key, values)
+			assert.NoError(GinkgoT(), err)
+			assert.NotNil(GinkgoT(), c)
+			assert.Equal(GinkgoT(), operator.NumericNotEquals, c.GetName())
+		})
+
 		It("gt", func() {
 			c, err := newNumericGreaterThanCondition(key, values)
 			assert.NoError(GinkgoT(), err)
@@ -152,6 +161,16 @@ var _ = Describe("NumericCompare", func() {
 			assert.False(GinkgoT(), lteCondition.Eval(strCtx("c")))
 		})
 
+		It("not eq", func() {
+			assert.False(GinkgoT(), notEqCondition.Eval(intCtx(1)))
+			assert.False(GinkgoT(), notEqCondition.Eval(int64Ctx(2)))
+			assert.True(GinkgoT(), notEqCondition.Eval(intCtx(3)))
+			assert.False(GinkgoT(), notEqCondition.Eval(errCtx(1)))
+
+			assert.False(GinkgoT(), notEqCondition.Eval(listCtx{2, 3}))
+			assert.True(GinkgoT(), notEqCondition.Eval(listCtx{3, 4}))
+		})
+
 		It("attr list", func() {
 			assert.True(GinkgoT(), eqCondition.Eval(listCtx{2, 3}))
 			assert.False(GinkgoT(), eqCondition.Eval(listCtx{3, 4}))
@@ -246,6 +265,34 @@ var _ = Describe("NumericCompare", func() {
 			assert.Contains(GinkgoT(), err.Error(), "not support multi value ")
 		})
 
+		It("ok, not_eq", func() {
+			expected := map[string]interface{}{
+				"op":    "not_eq",
+				"field": "key",
+				"value": 1,
+			}
+			c, err := newNumericNotEqualsCondition("key", []interface{}{1})
+			assert.NoError(GinkgoT(), err)
+
+			c1, err := c.Translate(true)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, c1)
+		})
+
+		It("ok, not_in", func() {
+			expected := map[string]interface{}{
+				"op":    "not_in",
+				"field": "bk_cmdb.host.id",
+				"value": []interface{}{1, 2},
+			}
+			c, err := newNumericNotEqualsCondition("bk_cmdb.host.id", []interface{}{1, 2})
+			assert.NoError(GinkgoT(), err)
+
+			c1, err := c.Translate(true)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, c1)
+		})
+
 		It("ok, in, withSystem=False", func() {
 			expected := map[string]interface{}{
 				"op":    "in",
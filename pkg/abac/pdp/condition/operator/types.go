@@ -5,15 +5,25 @@ package operator
 const (
 	AND            = "AND"
 	OR             = "OR"
+	NOT            = "NOT"
 	ANY            = "Any"
 	Bool           = "Bool"
 	StringPrefix   = "StringPrefix"
 	StringEquals   = "StringEquals"
 	StringContains = "StringContains"
+	StringSuffix   = "StringSuffix"
+	StringMatch    = "StringMatch"
 
-	NumericEquals = "NumericEquals"
-	NumericGt     = "NumericGt"
-	NumericGte    = "NumericGte"
-	NumericLt     = "NumericLt"
-	NumericLte    = "NumericLte"
+	StringNotEquals   = "StringNotEquals"
+	StringNotPrefix   = "StringNotPrefix"
+	StringNotContains = "StringNotContains"
+
+	NumericEquals    = "NumericEquals"
+	NumericNotEquals = "NumericNotEquals"
+	NumericGt        = "NumericGt"
+	NumericGte       = "NumericGte"
+	NumericLt        = "NumericLt"
+	NumericLte       = "NumericLte"
+
+	IPInCIDR = "IPInCIDR"
 )
@@ -92,7 +92,7 @@ func (c *OrCondition) PartialEval(ctx types.EvalContextor) (bool, Condition) {
 	remainedContent := make([]Condition, 0, len(c.content))
 	for _, condition := range c.content {
 		switch condition.GetName() {
-		case operator.AND, operator.OR:
+		case operator.AND, operator.OR, operator.NOT:
 			// NOTE: true的时候, 可能还有剩余的表达式
 			ok, ci := condition.(LogicalCondition).PartialEval(ctx)
 			if ok {
@@ -38,19 +38,22 @@ func (c *StringContainsCondition) GetName() string {
 
 // Eval 求值
 func (c *StringContainsCondition) Eval(ctx types.EvalContextor) bool {
-	return c.forOr(ctx, func(a, b interface{}) bool {
-		aStr, ok := a.(string)
-		if !ok {
-			return false
-		}
+	return c.forOr(ctx, stringContains)
+}
+
+// stringContains 判断属性值a是否包含表达式值b
+func stringContains(a, b interface{}) bool {
+	aStr, ok := a.(string)
+	if !ok {
+		return false
+	}
 
-		bStr, ok := b.(string)
-		if !ok {
-			return false
-		}
+	bStr, ok := b.(string)
+	if !ok {
+		return false
+	}
 
-		return strings.Contains(aStr, bStr)
-	})
+	return strings.Contains(aStr, bStr)
 }
 
 func (c *StringContainsCondition) Translate(withSystem bool) (map[string]interface{}, error) {
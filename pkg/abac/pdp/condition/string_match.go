@@ -0,0 +1,155 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	gocache "github.com/wklken/go-cache"
+
+	"iam/pkg/abac/pdp/condition/operator"
+	"iam/pkg/abac/pdp/types"
+)
+
+// MaxMatchPatternLength 通配符表达式的最大长度, 超过的表达式不会被编译, 求值总是false
+const MaxMatchPatternLength = 256
+
+var errPatternTooLong = fmt.Errorf("match pattern length should not be greater than %d", MaxMatchPatternLength)
+
+// matchRegexpCache 缓存通配符表达式编译后的正则, key为通配符表达式
+var matchRegexpCache = gocache.New(10*time.Minute, 20*time.Minute)
+
+// StringMatchCondition 字符串通配符匹配
+// 支持通配符: `*` 匹配任意长度的字符, `?` 匹配单个字符
+type StringMatchCondition struct {
+	baseCondition
+}
+
+func newStringMatchCondition(key string, values []interface{}) (Condition, error) {
+	for _, v := range values {
+		pattern, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("match pattern should be string, got %v", v)
+		}
+
+		if _, err := compileMatchPattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	return &StringMatchCondition{
+		baseCondition: baseCondition{
+			Key:   key,
+			Value: values,
+		},
+	}, nil
+}
+
+// GetName 名称
+func (c *StringMatchCondition) GetName() string {
+	return operator.StringMatch
+}
+
+// Eval 求值
+func (c *StringMatchCondition) Eval(ctx types.EvalContextor) bool {
+	return c.forOr(ctx, func(a, b interface{}) bool {
+		aStr, ok := a.(string)
+		if !ok {
+			return false
+		}
+
+		bStr, ok := b.(string)
+		if !ok {
+			return false
+		}
+
+		re, err := compileMatchPattern(bStr)
+		if err != nil {
+			return false
+		}
+
+		return re.MatchString(aStr)
+	})
+}
+
+func (c *StringMatchCondition) Translate(withSystem bool) (map[string]interface{}, error) {
+	key := c.Key
+	if !withSystem {
+		key = removeSystemFromKey(key)
+	}
+
+	// NOTE: same as starts_with, multiple value like: a match x OR a match y
+	content := make([]map[string]interface{}, 0, len(c.Value))
+	for _, v := range c.Value {
+		content = append(content, map[string]interface{}{
+			"op":    "match",
+			"field": key,
+			"value": v,
+		})
+	}
+
+	switch len(content) {
+	case 0:
+		return nil, errMustNotEmpty
+	case 1:
+		return content[0], nil
+	default:
+		return map[string]interface{}{
+			"op":      "OR",
+			"content": content,
+		}, nil
+	}
+}
+
+// compileMatchPattern 将通配符表达式编译为正则, 编译结果会被缓存
+// NOTE: 使用RE2实现的regexp, 匹配时间与输入长度线性相关, 不会有回溯爆炸的问题
+func compileMatchPattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) > MaxMatchPatternLength {
+		return nil, errPatternTooLong
+	}
+
+	if re, found := matchRegexpCache.Get(pattern); found {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(GlobToRegexp(pattern))
+	if err != nil {
+		return nil, errors.New("invalid match pattern: " + err.Error())
+	}
+
+	matchRegexpCache.SetDefault(pattern, re)
+	return re, nil
+}
+
+// GlobToRegexp 通配符表达式转换为正则, 除了`*`和`?`之外的字符都按字面量处理
+func GlobToRegexp(pattern string) string {
+	var b strings.Builder
+	b.Grow(len(pattern) + 8)
+
+	b.WriteByte('^')
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteByte('.')
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteByte('$')
+
+	return b.String()
+}
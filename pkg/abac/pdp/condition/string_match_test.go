@@ -0,0 +1,113 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("StringMatch", func() {
+	var c Condition
+	BeforeEach(func() {
+		c, _ = newStringMatchCondition("ok", []interface{}{"nginx:1.*", "repo/?/main"})
+	})
+
+	Describe("new", func() {
+		It("ok", func() {
+			condition, err := newStringMatchCondition("ok", []interface{}{"*.sh"})
+			assert.NoError(GinkgoT(), err)
+			assert.NotNil(GinkgoT(), condition)
+		})
+
+		It("fail, not string", func() {
+			_, err := newStringMatchCondition("ok", []interface{}{1})
+			assert.Error(GinkgoT(), err)
+		})
+
+		It("fail, too long", func() {
+			_, err := newStringMatchCondition("ok", []interface{}{strings.Repeat("a", MaxMatchPatternLength+1)})
+			assert.Error(GinkgoT(), err)
+			assert.Equal(GinkgoT(), errPatternTooLong, err)
+		})
+	})
+
+	It("GetName", func() {
+		assert.Equal(GinkgoT(), "StringMatch", c.GetName())
+	})
+
+	Context("Eval", func() {
+		It("true", func() {
+			assert.True(GinkgoT(), c.Eval(strCtx("nginx:1.21")))
+			assert.True(GinkgoT(), c.Eval(strCtx("repo/a/main")))
+		})
+
+		It("false", func() {
+			assert.False(GinkgoT(), c.Eval(strCtx("nginx:2.0")))
+			assert.False(GinkgoT(), c.Eval(strCtx("repo/ab/main")))
+			assert.False(GinkgoT(), c.Eval(strCtx("xnginx:1.21")))
+		})
+
+		It("regexp meta chars are literal", func() {
+			c, _ := newStringMatchCondition("ok", []interface{}{"a.b+(c)"})
+			assert.True(GinkgoT(), c.Eval(strCtx("a.b+(c)")))
+			assert.False(GinkgoT(), c.Eval(strCtx("axbb(c)")))
+		})
+
+		It("false, not string", func() {
+			assert.False(GinkgoT(), c.Eval(intCtx(1)))
+		})
+
+		It("attr list", func() {
+			assert.True(GinkgoT(), c.Eval(listCtx{"redis:6", "nginx:1.0"}))
+			assert.False(GinkgoT(), c.Eval(listCtx{"redis:6", "nginx:2.0"}))
+		})
+	})
+
+	Describe("Translate", func() {
+		It("ok, single", func() {
+			expected := map[string]interface{}{
+				"op":    "match",
+				"field": "key",
+				"value": "*.sh",
+			}
+			c, err := newStringMatchCondition("key", []interface{}{"*.sh"})
+			assert.NoError(GinkgoT(), err)
+			ec, err := c.Translate(true)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, ec)
+		})
+
+		It("ok, multiple or", func() {
+			ec, err := c.Translate(true)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "OR", ec["op"])
+			assert.Len(GinkgoT(), ec["content"], 2)
+		})
+	})
+
+	Describe("compileMatchPattern", func() {
+		It("cached", func() {
+			re1, err := compileMatchPattern("abc*")
+			assert.NoError(GinkgoT(), err)
+			re2, err := compileMatchPattern("abc*")
+			assert.NoError(GinkgoT(), err)
+			assert.Same(GinkgoT(), re1, re2)
+		})
+
+		It("too long", func() {
+			_, err := compileMatchPattern(strings.Repeat("a", MaxMatchPatternLength+1))
+			assert.Error(GinkgoT(), err)
+		})
+	})
+})
@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	"iam/pkg/abac/pdp/condition/operator"
+	"iam/pkg/abac/pdp/types"
+)
+
+// StringNotContainsCondition 字符串不包含
+type StringNotContainsCondition struct {
+	baseCondition
+}
+
+func newStringNotContainsCondition(key string, values []interface{}) (Condition, error) {
+	return &StringNotContainsCondition{
+		baseCondition: baseCondition{
+			Key:   key,
+			Value: values,
+		},
+	}, nil
+}
+
+// GetName 名称
+func (c *StringNotContainsCondition) GetName() string {
+	return operator.StringNotContains
+}
+
+// Eval 求值, 属性的所有值都不包含表达式中的任意值
+func (c *StringNotContainsCondition) Eval(ctx types.EvalContextor) bool {
+	return c.forNone(ctx, stringContains)
+}
+
+func (c *StringNotContainsCondition) Translate(withSystem bool) (map[string]interface{}, error) {
+	key := c.Key
+	if !withSystem {
+		key = removeSystemFromKey(key)
+	}
+
+	// NOTE: multiple value like: a not_string_contains x AND a not_string_contains y
+	content := make([]map[string]interface{}, 0, len(c.Value))
+	for _, v := range c.Value {
+		content = append(content, map[string]interface{}{
+			"op":    "not_string_contains",
+			"field": key,
+			"value": v,
+		})
+	}
+
+	switch len(content) {
+	case 0:
+		return nil, errMustNotEmpty
+	case 1:
+		return content[0], nil
+	default:
+		return map[string]interface{}{
+			"op":      "AND",
+			"content": content,
+		}, nil
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("StringNotEquals", func() {
+	var c *StringNotEqualsCondition
+	BeforeEach(func() {
+		c = &StringNotEqualsCondition{
+			baseCondition{
+				Key:   "ok",
+				Value: []interface{}{"a", "b"},
+			},
+		}
+	})
+
+	It("new", func() {
+		condition, err := newStringNotEqualsCondition("ok", []interface{}{"a", "b"})
+		assert.NoError(GinkgoT(), err)
+		assert.NotNil(GinkgoT(), condition)
+	})
+
+	It("GetName", func() {
+		assert.Equal(GinkgoT(), "StringNotEquals", c.GetName())
+	})
+
+	Context("Eval", func() {
+		It("true", func() {
+			assert.True(GinkgoT(), c.Eval(strCtx("c")))
+		})
+
+		It("false", func() {
+			assert.False(GinkgoT(), c.Eval(strCtx("a")))
+			assert.False(GinkgoT(), c.Eval(strCtx("b")))
+		})
+
+		It("false, attr not exists", func() {
+			assert.False(GinkgoT(), c.Eval(errCtx(1)))
+		})
+
+		It("attr list", func() {
+			assert.False(GinkgoT(), c.Eval(listCtx{"a", "d"}))
+			assert.True(GinkgoT(), c.Eval(listCtx{"e", "f"}))
+		})
+	})
+
+	Describe("Translate", func() {
+		It("fail, empty value", func() {
+			c, err := newStringNotEqualsCondition("key", []interface{}{})
+			assert.NoError(GinkgoT(), err)
+
+			_, err = c.Translate(true)
+			assert.Error(GinkgoT(), err)
+			assert.Equal(GinkgoT(), errMustNotEmpty, err)
+		})
+
+		It("ok, single not_eq", func() {
+			expected := map[string]interface{}{
+				"op":    "not_eq",
+				"field": "key",
+				"value": "a",
+			}
+			c, err := newStringNotEqualsCondition("key", []interface{}{"a"})
+			assert.NoError(GinkgoT(), err)
+			ec, err := c.Translate(true)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, ec)
+		})
+
+		It("ok, multiple not_in, withSystem=False", func() {
+			expected := map[string]interface{}{
+				"op":    "not_in",
+				"field": "host.path",
+				"value": []interface{}{"a", "b"},
+			}
+			c, err := newStringNotEqualsCondition("bk_cmdb.host.path", []interface{}{"a", "b"})
+			assert.NoError(GinkgoT(), err)
+			ec, err := c.Translate(false)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, ec)
+		})
+	})
+})
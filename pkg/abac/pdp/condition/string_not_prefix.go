@@ -0,0 +1,71 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	"iam/pkg/abac/pdp/condition/operator"
+	"iam/pkg/abac/pdp/types"
+)
+
+// StringNotPrefixCondition 字符串前缀不匹配
+type StringNotPrefixCondition struct {
+	baseCondition
+}
+
+func newStringNotPrefixCondition(key string, values []interface{}) (Condition, error) {
+	return &StringNotPrefixCondition{
+		baseCondition: baseCondition{
+			Key:   key,
+			Value: values,
+		},
+	}, nil
+}
+
+// GetName 名称
+func (c *StringNotPrefixCondition) GetName() string {
+	return operator.StringNotPrefix
+}
+
+// Eval 求值, 属性的所有值都不以表达式中的任意值为前缀
+func (c *StringNotPrefixCondition) Eval(ctx types.EvalContextor) bool {
+	return c.forNone(ctx, func(a, b interface{}) bool {
+		return hasPrefix(c.Key, a, b)
+	})
+}
+
+func (c *StringNotPrefixCondition) Translate(withSystem bool) (map[string]interface{}, error) {
+	key := c.Key
+	if !withSystem {
+		key = removeSystemFromKey(key)
+	}
+
+	// NOTE: multiple value like: a not_starts_with x AND a not_starts_with y
+	content := make([]map[string]interface{}, 0, len(c.Value))
+	for _, v := range c.Value {
+		content = append(content, map[string]interface{}{
+			"op":    "not_starts_with",
+			"field": key,
+			"value": v,
+		})
+	}
+
+	switch len(content) {
+	case 0:
+		return nil, errMustNotEmpty
+	case 1:
+		return content[0], nil
+	default:
+		return map[string]interface{}{
+			"op":      "AND",
+			"content": content,
+		}, nil
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("StringNotPrefix", func() {
+	var c *StringNotPrefixCondition
+	BeforeEach(func() {
+		c = &StringNotPrefixCondition{
+			baseCondition{
+				Key:   "ok",
+				Value: []interface{}{"/biz,1/", "/biz,2/"},
+			},
+		}
+	})
+
+	It("new", func() {
+		condition, err := newStringNotPrefixCondition("ok", []interface{}{"/biz,1/"})
+		assert.NoError(GinkgoT(), err)
+		assert.NotNil(GinkgoT(), condition)
+	})
+
+	It("GetName", func() {
+		assert.Equal(GinkgoT(), "StringNotPrefix", c.GetName())
+	})
+
+	Context("Eval", func() {
+		It("true", func() {
+			assert.True(GinkgoT(), c.Eval(strCtx("/biz,3/set,1/")))
+		})
+
+		It("false", func() {
+			assert.False(GinkgoT(), c.Eval(strCtx("/biz,1/set,1/")))
+			assert.False(GinkgoT(), c.Eval(strCtx("/biz,2/")))
+		})
+
+		It("not string", func() {
+			assert.True(GinkgoT(), c.Eval(intCtx(1)))
+			assert.False(GinkgoT(), c.Eval(errCtx(1)))
+		})
+
+		It("attr list", func() {
+			assert.False(GinkgoT(), c.Eval(listCtx{"/biz,3/", "/biz,1/set,1/"}))
+			assert.True(GinkgoT(), c.Eval(listCtx{"/biz,3/", "/biz,4/"}))
+		})
+
+		It("iam path with any", func() {
+			c, _ := newStringNotPrefixCondition("host._bk_iam_path_", []interface{}{"/biz,1/set,*/"})
+			assert.False(GinkgoT(), c.Eval(strCtx("/biz,1/set,2/")))
+			assert.True(GinkgoT(), c.Eval(strCtx("/biz,2/set,2/")))
+		})
+	})
+
+	Describe("Translate", func() {
+		It("fail, empty value", func() {
+			c, err := newStringNotPrefixCondition("key", []interface{}{})
+			assert.NoError(GinkgoT(), err)
+
+			_, err = c.Translate(true)
+			assert.Error(GinkgoT(), err)
+			assert.Equal(GinkgoT(), errMustNotEmpty, err)
+		})
+
+		It("ok, single", func() {
+			expected := map[string]interface{}{
+				"op":    "not_starts_with",
+				"field": "key",
+				"value": "a",
+			}
+			c, err := newStringNotPrefixCondition("key", []interface{}{"a"})
+			assert.NoError(GinkgoT(), err)
+			ec, err := c.Translate(true)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, ec)
+		})
+
+		It("ok, multiple and", func() {
+			expected := map[string]interface{}{
+				"op": "AND",
+				"content": []map[string]interface{}{
+					{
+						"op":    "not_starts_with",
+						"field": "host.path",
+						"value": "a",
+					},
+					{
+						"op":    "not_starts_with",
+						"field": "host.path",
+						"value": "b",
+					},
+				},
+			}
+			c, err := newStringNotPrefixCondition("bk_cmdb.host.path", []interface{}{"a", "b"})
+			assert.NoError(GinkgoT(), err)
+			ec, err := c.Translate(false)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, ec)
+		})
+	})
+})
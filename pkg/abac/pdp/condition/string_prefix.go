@@ -40,24 +40,29 @@ func (c *StringPrefixCondition) GetName() string {
 // Eval 求值
 func (c *StringPrefixCondition) Eval(ctx types.EvalContextor) bool {
 	return c.forOr(ctx, func(a, b interface{}) bool {
-		aStr, ok := a.(string)
-		if !ok {
-			return false
-		}
+		return hasPrefix(c.Key, a, b)
+	})
+}
 
-		bStr, ok := b.(string)
-		if !ok {
-			return false
-		}
+// hasPrefix 判断属性值a是否以表达式值b为前缀
+func hasPrefix(key string, a, b interface{}) bool {
+	aStr, ok := a.(string)
+	if !ok {
+		return false
+	}
 
-		// 支持表达式中最后一个节点为任意
-		// /biz,1/set,*/ -> /biz,1/set,
-		if strings.HasSuffix(c.Key, abacTypes.IamPathSuffix) && strings.HasSuffix(bStr, ",*/") {
-			bStr = bStr[0 : len(bStr)-2]
-		}
+	bStr, ok := b.(string)
+	if !ok {
+		return false
+	}
 
-		return strings.HasPrefix(aStr, bStr)
-	})
+	// 支持表达式中最后一个节点为任意
+	// /biz,1/set,*/ -> /biz,1/set,
+	if strings.HasSuffix(key, abacTypes.IamPathSuffix) && strings.HasSuffix(bStr, ",*/") {
+		bStr = bStr[0 : len(bStr)-2]
+	}
+
+	return strings.HasPrefix(aStr, bStr)
 }
 
 func (c *StringPrefixCondition) Translate(withSystem bool) (map[string]interface{}, error) {
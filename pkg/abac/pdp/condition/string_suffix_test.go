@@ -0,0 +1,105 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package condition
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("StringSuffix", func() {
+	var c *StringSuffixCondition
+	BeforeEach(func() {
+		c = &StringSuffixCondition{
+			baseCondition{
+				Key:   "ok",
+				Value: []interface{}{".sh", ".py"},
+			},
+		}
+	})
+
+	It("new", func() {
+		condition, err := newStringSuffixCondition("ok", []interface{}{".sh"})
+		assert.NoError(GinkgoT(), err)
+		assert.NotNil(GinkgoT(), condition)
+	})
+
+	It("GetName", func() {
+		assert.Equal(GinkgoT(), "StringSuffix", c.GetName())
+	})
+
+	Context("Eval", func() {
+		It("true", func() {
+			assert.True(GinkgoT(), c.Eval(strCtx("deploy.sh")))
+			assert.True(GinkgoT(), c.Eval(strCtx("main.py")))
+		})
+
+		It("false", func() {
+			assert.False(GinkgoT(), c.Eval(strCtx("main.go")))
+		})
+
+		It("false, not string", func() {
+			assert.False(GinkgoT(), c.Eval(intCtx(1)))
+		})
+
+		It("attr list", func() {
+			assert.True(GinkgoT(), c.Eval(listCtx{"main.go", "deploy.sh"}))
+			assert.False(GinkgoT(), c.Eval(listCtx{"main.go", "README"}))
+		})
+	})
+
+	Describe("Translate", func() {
+		It("fail, empty value", func() {
+			c, err := newStringSuffixCondition("key", []interface{}{})
+			assert.NoError(GinkgoT(), err)
+
+			_, err = c.Translate(true)
+			assert.Error(GinkgoT(), err)
+			assert.Equal(GinkgoT(), errMustNotEmpty, err)
+		})
+
+		It("ok, single", func() {
+			expected := map[string]interface{}{
+				"op":    "ends_with",
+				"field": "key",
+				"value": ".sh",
+			}
+			c, err := newStringSuffixCondition("key", []interface{}{".sh"})
+			assert.NoError(GinkgoT(), err)
+			ec, err := c.Translate(true)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, ec)
+		})
+
+		It("ok, multiple or, withSystem=False", func() {
+			expected := map[string]interface{}{
+				"op": "OR",
+				"content": []map[string]interface{}{
+					{
+						"op":    "ends_with",
+						"field": "script.name",
+						"value": ".sh",
+					},
+					{
+						"op":    "ends_with",
+						"field": "script.name",
+						"value": ".py",
+					},
+				},
+			}
+			c, err := newStringSuffixCondition("bk_job.script.name", []interface{}{".sh", ".py"})
+			assert.NoError(GinkgoT(), err)
+			ec, err := c.Translate(false)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, ec)
+		})
+	})
+})
@@ -0,0 +1,222 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pdp
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/abac/pdp/condition"
+	"iam/pkg/abac/pdp/evalctx"
+	"iam/pkg/abac/pdp/evaluation"
+	"iam/pkg/abac/pip"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/cacheimpls"
+)
+
+/*
+权限委托: delegatee在委托的系统操作上, 额外拥有delegator的用户组与自定义权限
+
+ - 每次鉴权实时获取delegator当前的用户组与策略, 委托的权限不会超出delegator当前的权限
+ - delegator失去权限时, 委托自然失效; delegator被冻结(黑名单)或删除时, 委托不生效
+ - 委托的权限使用delegator自身的策略单独计算(delegator的deny策略只限制委托的权限), 结果只作为delegatee额外的allow,
+   delegatee自身的deny策略优先
+*/
+
+// DelegatedAuthPolicies delegator及其在当前操作上的策略
+type DelegatedAuthPolicies struct {
+	Subject  types.Subject
+	Policies []types.AuthPolicy
+}
+
+// listDelegatorSubjects 获取委托给subject的当前操作的有效的delegator
+func listDelegatorSubjects(systemID string, subject types.Subject, action types.Action) ([]types.Subject, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDPHelper, "listDelegatorSubjects")
+
+	subjectPK, err := subject.Attribute.GetPK()
+	if err != nil {
+		return nil, errorWrapf(err, "subject.Attribute.GetPK subject=`%+v` fail", subject)
+	}
+
+	delegations, err := cacheimpls.ListLocalSubjectDelegations(subjectPK)
+	if err != nil {
+		return nil, errorWrapf(err, "cacheimpls.ListLocalSubjectDelegations subjectPK=`%d` fail", subjectPK)
+	}
+	if len(delegations) == 0 {
+		return nil, nil
+	}
+
+	actionPK, err := action.Attribute.GetPK()
+	if err != nil {
+		return nil, errorWrapf(err, "action.Attribute.GetPK action=`%+v` fail", action)
+	}
+
+	now := time.Now().Unix()
+	delegatorPKSet := set.NewInt64Set()
+	delegators := make([]types.Subject, 0, len(delegations))
+	for _, d := range delegations {
+		if d.SystemID != systemID || d.ExpiredAt <= now || delegatorPKSet.Has(d.DelegatorPK) {
+			continue
+		}
+		if !set.NewInt64SetWithValues(d.ActionPKs).Has(actionPK) {
+			continue
+		}
+
+		delegator, err := cacheimpls.GetSubjectByPK(d.DelegatorPK)
+		if err != nil {
+			// delegator已被删除, 委托不生效
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, errorWrapf(err, "cacheimpls.GetSubjectByPK pk=`%d` fail", d.DelegatorPK)
+		}
+
+		if cacheimpls.IsSubjectInBlackList(delegator.Type, delegator.ID) {
+			continue
+		}
+
+		departments, ancestors, err := pip.GetSubjectDepartmentPKs(d.DelegatorPK)
+		if err != nil {
+			return nil, errorWrapf(err, "pip.GetSubjectDepartmentPKs pk=`%d` fail", d.DelegatorPK)
+		}
+
+		delegatorSubject := types.NewSubject()
+		delegatorSubject.Type = delegator.Type
+		delegatorSubject.ID = delegator.ID
+		delegatorSubject.FillAttributes(d.DelegatorPK, departments, ancestors)
+
+		delegatorPKSet.Add(d.DelegatorPK)
+		delegators = append(delegators, delegatorSubject)
+	}
+
+	return delegators, nil
+}
+
+// newDelegatorRequest 将请求的subject替换为delegator, 用于计算delegator自身的权限
+func newDelegatorRequest(r *request.Request, delegator types.Subject) (*request.Request, error) {
+	delegatorRequest := *r
+	delegatorRequest.Subject = delegator
+
+	err := fillSubjectAttributes(&delegatorRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &delegatorRequest, nil
+}
+
+// evalDelegations 计算委托的权限, 任一delegator自身鉴权通过则通过, 返回通过的delegator pk
+// NOTE: 需要在subject自身没有权限且没有被deny时调用
+func evalDelegations(r *request.Request, withoutCache bool) (isPass bool, delegatorPK int64, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDPHelper, "evalDelegations")
+
+	delegators, err := listDelegatorSubjects(r.System, r.Subject, r.Action)
+	if err != nil {
+		return false, 0, errorWrapf(err, "listDelegatorSubjects subject=`%+v` fail", r.Subject)
+	}
+
+	for _, delegator := range delegators {
+		delegatorRequest, err := newDelegatorRequest(r, delegator)
+		if err != nil {
+			return false, 0, errorWrapf(err, "newDelegatorRequest delegator=`%+v` fail", delegator)
+		}
+
+		isPass, _, err = evalSubject(delegatorRequest, nil, withoutCache)
+		if err != nil {
+			return false, 0, errorWrapf(err, "evalSubject delegator=`%+v` fail", delegator)
+		}
+		if isPass {
+			delegatorPK, _ = delegator.Attribute.GetPK()
+			return true, delegatorPK, nil
+		}
+	}
+	return false, 0, nil
+}
+
+// queryDelegatedConditions 查询委托的权限的残留条件, 每个delegator的残留条件已经受其自身deny策略的限制
+func queryDelegatedConditions(r *request.Request, withoutCache bool) ([]condition.Condition, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDPHelper, "queryDelegatedConditions")
+
+	delegators, err := listDelegatorSubjects(r.System, r.Subject, r.Action)
+	if err != nil {
+		return nil, errorWrapf(err, "listDelegatorSubjects subject=`%+v` fail", r.Subject)
+	}
+
+	conditions := make([]condition.Condition, 0, len(delegators))
+	for _, delegator := range delegators {
+		delegatorRequest, err := newDelegatorRequest(r, delegator)
+		if err != nil {
+			return nil, errorWrapf(err, "newDelegatorRequest delegator=`%+v` fail", delegator)
+		}
+
+		delegatorConditions, err := partialEvalSubjectConditions(delegatorRequest, nil, nil, withoutCache)
+		if err != nil {
+			return nil, errorWrapf(err, "partialEvalSubjectConditions delegator=`%+v` fail", delegator)
+		}
+		conditions = append(conditions, delegatorConditions...)
+	}
+	return conditions, nil
+}
+
+// QueryDelegatedAuthPolicies 查询委托给subject的当前操作的delegator及其策略, 需要在QueryAuthPolicies之后调用
+func QueryDelegatedAuthPolicies(r *request.Request, withoutCache bool) ([]DelegatedAuthPolicies, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDP, "QueryDelegatedAuthPolicies")
+
+	delegators, err := listDelegatorSubjects(r.System, r.Subject, r.Action)
+	if err != nil {
+		return nil, errorWrapf(err, "listDelegatorSubjects subject=`%+v` fail", r.Subject)
+	}
+
+	delegatedPolicies := make([]DelegatedAuthPolicies, 0, len(delegators))
+	for _, delegator := range delegators {
+		delegatorRequest, err := newDelegatorRequest(r, delegator)
+		if err != nil {
+			return nil, errorWrapf(err, "newDelegatorRequest delegator=`%+v` fail", delegator)
+		}
+
+		policies, err := querySubjectAuthPolicies(delegatorRequest, nil, withoutCache)
+		if err != nil {
+			if errors.Is(err, ErrNoPolicies) {
+				continue
+			}
+			return nil, errorWrapf(err, "querySubjectAuthPolicies delegator=`%+v` fail", delegator)
+		}
+
+		delegatedPolicies = append(delegatedPolicies, DelegatedAuthPolicies{
+			Subject:  delegatorRequest.Subject,
+			Policies: policies,
+		})
+	}
+	return delegatedPolicies, nil
+}
+
+// EvalDelegatedAuthPolicies 计算委托的权限, 任一delegator使用自身的策略鉴权通过则通过
+// NOTE: 需要在subject自身没有权限且没有被deny时调用
+func EvalDelegatedAuthPolicies(r *request.Request, delegatedPolicies []DelegatedAuthPolicies) (bool, error) {
+	for _, d := range delegatedPolicies {
+		delegatorRequest := *r
+		delegatorRequest.Subject = d.Subject
+
+		isPass, _, err := evaluation.EvalPolicies(evalctx.NewEvalContext(&delegatorRequest), d.Policies)
+		if err != nil {
+			return false, errorx.Wrapf(err, PDP, "EvalDelegatedAuthPolicies",
+				"evaluation.EvalPolicies delegator=`%+v` fail", d.Subject)
+		}
+		if isPass {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,243 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pdp
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/pip"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/logging/debug"
+	svctypes "iam/pkg/service/types"
+)
+
+var _ = Describe("Delegation", func() {
+	var patches *gomonkey.Patches
+	var expiredAt int64
+	BeforeEach(func() {
+		expiredAt = time.Now().Unix() + 100
+
+		patches = gomonkey.ApplyFunc(cacheimpls.GetSubjectByPK, func(pk int64) (svctypes.Subject, error) {
+			if pk == 3 {
+				return svctypes.Subject{}, sql.ErrNoRows
+			}
+			return svctypes.Subject{PK: pk, Type: "user", ID: "manager"}, nil
+		})
+		patches.ApplyFunc(pip.GetSubjectDepartmentPKs, func(pk int64) ([]int64, []int64, error) {
+			return []int64{20}, nil, nil
+		})
+		patches.ApplyFunc(cacheimpls.IsSubjectInBlackList, func(_type, id string) bool {
+			return false
+		})
+	})
+	AfterEach(func() {
+		patches.Reset()
+	})
+
+	Describe("listDelegatorSubjects", func() {
+		var subject types.Subject
+		var action types.Action
+		BeforeEach(func() {
+			subject = types.NewSubject()
+			subject.Attribute.SetPK(1)
+			action = types.NewAction()
+			action.Attribute.SetPK(10)
+		})
+
+		It("list delegations fail", func() {
+			patches.ApplyFunc(cacheimpls.ListLocalSubjectDelegations, func(pk int64) ([]svctypes.SubjectDelegation, error) {
+				return nil, errors.New("error")
+			})
+
+			_, err := listDelegatorSubjects("test", subject, action)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListLocalSubjectDelegations")
+		})
+
+		It("not match", func() {
+			patches.ApplyFunc(cacheimpls.ListLocalSubjectDelegations, func(pk int64) ([]svctypes.SubjectDelegation, error) {
+				return []svctypes.SubjectDelegation{
+					{DelegatorPK: 2, SystemID: "other", ActionPKs: []int64{10}, ExpiredAt: expiredAt},
+					{DelegatorPK: 2, SystemID: "test", ActionPKs: []int64{11}, ExpiredAt: expiredAt},
+					{DelegatorPK: 2, SystemID: "test", ActionPKs: []int64{10}, ExpiredAt: 1},
+					{DelegatorPK: 3, SystemID: "test", ActionPKs: []int64{10}, ExpiredAt: expiredAt},
+				}, nil
+			})
+
+			delegators, err := listDelegatorSubjects("test", subject, action)
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), delegators)
+		})
+
+		It("delegator in black list", func() {
+			patches.ApplyFunc(cacheimpls.ListLocalSubjectDelegations, func(pk int64) ([]svctypes.SubjectDelegation, error) {
+				return []svctypes.SubjectDelegation{
+					{DelegatorPK: 2, SystemID: "test", ActionPKs: []int64{10}, ExpiredAt: expiredAt},
+				}, nil
+			})
+			patches.ApplyFunc(cacheimpls.IsSubjectInBlackList, func(_type, id string) bool {
+				return true
+			})
+
+			delegators, err := listDelegatorSubjects("test", subject, action)
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), delegators)
+		})
+
+		It("ok", func() {
+			patches.ApplyFunc(cacheimpls.ListLocalSubjectDelegations, func(pk int64) ([]svctypes.SubjectDelegation, error) {
+				return []svctypes.SubjectDelegation{
+					{DelegatorPK: 2, SystemID: "test", ActionPKs: []int64{10, 11}, ExpiredAt: expiredAt},
+				}, nil
+			})
+
+			delegators, err := listDelegatorSubjects("test", subject, action)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), delegators, 1)
+			assert.Equal(GinkgoT(), "manager", delegators[0].ID)
+
+			pk, _ := delegators[0].Attribute.GetPK()
+			assert.Equal(GinkgoT(), int64(2), pk)
+			departments, _ := delegators[0].GetDepartmentPKs()
+			assert.Equal(GinkgoT(), []int64{20}, departments)
+		})
+	})
+
+	// subject 1 被委托了 subject 2 的操作, 操作不关联资源类型, 策略总是满足
+	Describe("eval with delegation", func() {
+		var req *request.Request
+		var subjectPolicies map[int64][]types.AuthPolicy
+		allowPolicy := types.AuthPolicy{ID: 1}
+		denyPolicy := types.AuthPolicy{ID: 2, Effect: svctypes.PolicyEffectDeny}
+		BeforeEach(func() {
+			req = request.NewRequest()
+			req.System = "test"
+			req.Subject.Type = "user"
+			req.Subject.ID = "admin"
+			subjectPolicies = map[int64][]types.AuthPolicy{}
+
+			patches.ApplyFunc(fillActionDetail, func(r *request.Request) error {
+				r.Action.FillAttributes(10, 0, []types.ActionResourceType{})
+				return nil
+			})
+			patches.ApplyMethod(reflect.TypeOf(req), "ValidateActionResource", func(_ *request.Request) bool {
+				return true
+			})
+			patches.ApplyFunc(fillSubjectDepartments, func(r *request.Request) error {
+				r.Subject.FillAttributes(1, nil, nil)
+				return nil
+			})
+			patches.ApplyFunc(cacheimpls.ListLocalSubjectDelegations, func(pk int64) ([]svctypes.SubjectDelegation, error) {
+				return []svctypes.SubjectDelegation{
+					{DelegatorPK: 2, SystemID: "test", ActionPKs: []int64{10}, ExpiredAt: expiredAt},
+				}, nil
+			})
+			patches.ApplyFunc(getEffectAuthTypeGroupPKs, func(
+				system string, subject types.Subject, action types.Action,
+			) ([]int64, []int64, error) {
+				return nil, nil, nil
+			})
+			patches.ApplyFunc(queryPolicies, func(
+				system string, subject types.Subject, action types.Action, effectGroupPKs []int64,
+				withRbacPolicies bool, withoutCache bool, entry *debug.Entry,
+			) ([]types.AuthPolicy, error) {
+				pk, _ := subject.Attribute.GetPK()
+				if len(subjectPolicies[pk]) == 0 {
+					return nil, ErrNoPolicies
+				}
+				return subjectPolicies[pk], nil
+			})
+		})
+
+		It("delegated allow", func() {
+			subjectPolicies[2] = []types.AuthPolicy{allowPolicy}
+
+			ok, err := Eval(req, nil, false)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), ok)
+
+			conditions, err := queryAndPartialEvalConditions(req, nil, false, false)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), conditions, 1)
+		})
+
+		It("delegator has deny policy, delegated allow is capped", func() {
+			subjectPolicies[2] = []types.AuthPolicy{allowPolicy, denyPolicy}
+
+			ok, err := Eval(req, nil, false)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), ok)
+
+			conditions, err := queryAndPartialEvalConditions(req, nil, false, false)
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), conditions)
+		})
+
+		It("delegator has deny policy, delegatee own allow not affected", func() {
+			subjectPolicies[1] = []types.AuthPolicy{allowPolicy}
+			subjectPolicies[2] = []types.AuthPolicy{denyPolicy}
+
+			ok, err := Eval(req, nil, false)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), ok)
+
+			conditions, err := queryAndPartialEvalConditions(req, nil, false, false)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), conditions, 1)
+		})
+
+		It("delegatee own deny takes priority", func() {
+			subjectPolicies[1] = []types.AuthPolicy{denyPolicy}
+			subjectPolicies[2] = []types.AuthPolicy{allowPolicy}
+
+			ok, err := Eval(req, nil, false)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), ok)
+
+			conditions, err := queryAndPartialEvalConditions(req, nil, false, false)
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), conditions)
+		})
+
+		It("auth policies, delegator has deny policy", func() {
+			subjectPolicies[2] = []types.AuthPolicy{allowPolicy, denyPolicy}
+
+			policies, err := QueryAuthPolicies(req, nil, false)
+			assert.ErrorIs(GinkgoT(), err, ErrNoPolicies)
+			assert.Empty(GinkgoT(), policies)
+
+			delegatedPolicies, err := QueryDelegatedAuthPolicies(req, false)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), delegatedPolicies, 1)
+
+			ok, err := EvalDelegatedAuthPolicies(req, delegatedPolicies)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), ok)
+
+			subjectPolicies[2] = []types.AuthPolicy{allowPolicy}
+			delegatedPolicies, err = QueryDelegatedAuthPolicies(req, false)
+			assert.NoError(GinkgoT(), err)
+
+			ok, err = EvalDelegatedAuthPolicies(req, delegatedPolicies)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), ok)
+		})
+	})
+})
@@ -73,6 +73,7 @@ func Eval(
 			"subject":      r.Subject,
 			"action":       r.Action,
 			"resources":    r.Resources,
+			"environment":  r.Environment,
 			"cacheEnabled": !withoutCache,
 		})
 	}
@@ -95,9 +96,9 @@ func Eval(
 	debug.AddStep(entry, "Validate action resource")
 	if !r.ValidateActionResource() {
 		err = errorWrapf(ErrInvalidActionResource,
-			"ValidateActionResource systemID=`%s`, actionID=`%d`, resources=`%+v` fail, "+
-				"request resources not match action",
-			r.System, r.Action.ID, r.Resources)
+			"ValidateActionResource systemID=`%s`, actionID=`%d`, resources=`%+v`, environment=`%+v` fail, "+
+				"request resources not match action, or environment not declared by action",
+			r.System, r.Action.ID, r.Resources, r.Environment)
 		return false, err
 	}
 
@@ -115,9 +116,43 @@ func Eval(
 
 		return
 	}
+	err = fillSubjectAttributes(r)
+	if err != nil {
+		err = errorWrapf(err, "request fillSubjectAttributes subject=`%+v`", r.Subject)
+		return
+	}
 	debug.WithValue(entry, "subject", r.Subject)
 
-	// 3. 查询关联的group pks
+	// 4. 使用subject自身的用户组与策略鉴权
+	isPass, isDeny, err := evalSubject(r, entry, withoutCache)
+	if err != nil {
+		return false, errorWrapf(err, "evalSubject subject=`%+v` fail", r.Subject)
+	}
+	if isPass || isDeny {
+		return isPass, nil
+	}
+
+	// 5. subject自身没有权限且没有被deny时, 计算委托的权限
+	debug.AddStep(entry, "Eval Delegations")
+	isPass, delegatorPK, err := evalDelegations(r, withoutCache)
+	if err != nil {
+		return false, errorWrapf(err, "evalDelegations subject=`%+v` fail", r.Subject)
+	}
+	if isPass {
+		debug.WithValue(entry, "delegatorPK", delegatorPK)
+	}
+	return isPass, nil
+}
+
+// evalSubject 使用subject自身的用户组与策略鉴权, isDeny表示命中了deny策略
+func evalSubject(
+	r *request.Request,
+	entry *debug.Entry,
+	withoutCache bool,
+) (isPass, isDeny bool, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDP, "evalSubject")
+
+	// 1. 查询关联的group pks
 	debug.AddStep(entry, "Get Effect AuthType Group PKs")
 	abacGroupPKs, rbacGroupPKs, err := getEffectAuthTypeGroupPKs(r.System, r.Subject, r.Action)
 	if err != nil {
@@ -128,46 +163,62 @@ func Eval(
 			r.Subject,
 			r.Action,
 		)
-		return false, err
+		return false, false, err
 	}
 	debug.WithValue(entry, "abacGroupPks", abacGroupPKs)
 	debug.WithValue(entry, "rbacGroupPks", rbacGroupPKs)
 
-	// 4. actionAuthType为rbac优先走rbac鉴权逻辑
+	// 2. actionAuthType为rbac优先走rbac鉴权逻辑
+	var rbacPass bool
 	if len(rbacGroupPKs) > 0 {
 		debug.AddStep(entry, "RBAC Eval")
-		isPass, err = rbacEval(r.System, r.Action, r.Resources, rbacGroupPKs, withoutCache, entry)
+		rbacPass, err = rbacEval(r.System, r.Action, r.Resources, rbacGroupPKs, withoutCache, entry)
 		if err != nil {
 			err = errorWrapf(err, "rbacEval systemID=`%s`, actionID=`%d`, resources=`%+v`, groupPKs=`%v` fail",
 				r.System, r.Action.ID, r.Resources, rbacGroupPKs)
-			return false, err
-		}
-
-		if isPass {
-			return isPass, nil
+			return false, false, err
 		}
 	}
 
-	// 5. PRP查询subject-action相关的policies: 根据 system / subject / action 获取策略列表
+	// 3. PRP查询subject-action相关的policies: 根据 system / subject / action 获取策略列表
 	debug.AddStep(entry, "Query Policies")
 	policies, err := queryPolicies(r.System, r.Subject, r.Action, abacGroupPKs, false, withoutCache, entry)
 	if err != nil {
 		if errors.Is(err, ErrNoPolicies) {
-			return false, nil
+			return rbacPass, false, nil
 		}
 
+		// NOTE: deny优先, 即使rbac已通过, 查询策略失败时无法确认是否存在deny策略, 只能返回错误, 不能放行
 		err = errorWrapf(err, "queryPolicies system=`%s`, subject=`%+v`, action=`%+v`, withoutCache=`%t` fail",
 			r.System, r.Subject, r.Action, withoutCache)
-		return false, err
+		return false, false, err
 	}
 	debug.WithValue(entry, "policies", policies)
 	debug.WithUnknownEvalPolicies(entry, policies)
 
+	// rbac已通过, 只需要检查是否有deny策略命中
+	if rbacPass {
+		debug.AddStep(entry, "Eval Deny")
+		ctx := evalctx.NewEvalContext(r)
+		var denyPolicyID int64
+		isDeny, denyPolicyID, err = evaluation.EvalDenyPolicies(ctx, policies)
+		debug.WithErrorEvalPolicyIDs(entry, ctx.FailedPolicyIDs())
+		if err != nil {
+			err = errorWrapf(err, "evaluation.EvalDenyPolicies policies=`%+v`, request=`%+v` fail", policies, *r)
+			return false, false, err
+		}
+		if isDeny {
+			debug.WithDenyEvalPolicy(entry, denyPolicyID)
+			return false, true, nil
+		}
+		return true, false, nil
+	}
+
 	// NOTE: debug mode, do translate, for understanding easier
 	if entry != nil {
 		debug.WithValue(entry, "expression", "set fail")
 
-		expr, err1 := cacheimpls.PoliciesTranslate(policies)
+		expr, err1 := cacheimpls.PoliciesTranslate(policies, r.WithSystem)
 		if err1 == nil {
 			debug.WithValue(entry, "expression", expr)
 		}
@@ -178,23 +229,33 @@ func Eval(
 		envs, _ := evalctx.GenTimeEnvsFromCache(DefaultTz, time.Now())
 		debug.WithValue(entry, "env", envs)
 	}
-	var passPolicyID int64
-	isPass, passPolicyID, err = evaluation.EvalPolicies(evalctx.NewEvalContext(r), policies)
+	var policyID int64
+	ctx := evalctx.NewEvalContext(r)
+	isPass, policyID, err = evaluation.EvalPolicies(ctx, policies)
 	if err != nil {
+		debug.WithNoPassEvalPolicies(entry, policies)
+		debug.WithErrorEvalPolicyIDs(entry, ctx.FailedPolicyIDs())
+
 		err = errorWrapf(err, "single local evaluation.EvalPolicies policies=`%+v`, request=`%+v` fail",
 			policies, *r)
 
-		return false, err
+		return false, false, err
 	}
 	if !isPass {
 		// if isPass is false, update all to `no pass`
 		debug.WithNoPassEvalPolicies(entry, policies)
+		debug.WithErrorEvalPolicyIDs(entry, ctx.FailedPolicyIDs())
+		// if denied by a deny policy, policyID is the deny policy
+		if policyID != -1 {
+			debug.WithDenyEvalPolicy(entry, policyID)
+			isDeny = true
+		}
 	} else {
 		// if isPass is true, how to know which policy?
-		debug.WithPassEvalPolicy(entry, passPolicyID)
+		debug.WithPassEvalPolicy(entry, policyID)
 	}
 
-	return isPass, err
+	return isPass, isDeny, nil
 }
 
 // Query 查询请求相关的Policy
@@ -218,7 +279,7 @@ func Query(
 	}
 
 	// 2. policies表达式转换
-	expr, err := translate.ConditionsTranslate(conditions)
+	expr, err := translate.ConditionsTranslate(conditions, r.WithSystem)
 	if err != nil {
 		err = errorWrapf(err, "translate.ConditionsTranslate conditions=`%+v` fail", conditions)
 		return nil, err
@@ -286,7 +347,7 @@ func QueryByExtResources(
 
 	// 3. policies表达式转换
 	var expr map[string]interface{}
-	expr, err = translate.ConditionsTranslate(conditions)
+	expr, err = translate.ConditionsTranslate(conditions, r.WithSystem)
 	if err != nil {
 		err = errorWrapf(err, "translate.ConditionsTranslate conditions=`%+v` fail", conditions)
 		return nil, nil, err
@@ -344,9 +405,25 @@ func QueryAuthPolicies(
 		err = errorWrapf(err, "request fillSubjectDetail subject=`%+v`", r.Subject)
 		return
 	}
+	err = fillSubjectAttributes(r)
+	if err != nil {
+		err = errorWrapf(err, "request fillSubjectAttributes subject=`%+v`", r.Subject)
+		return
+	}
 	debug.WithValue(entry, "subject", r.Subject)
 
-	// 3. 查询关联的group pks
+	return querySubjectAuthPolicies(r, entry, withoutCache)
+}
+
+// querySubjectAuthPolicies 查询subject自身相关的策略, 包括RBAC用户组的策略
+func querySubjectAuthPolicies(
+	r *request.Request,
+	entry *debug.Entry,
+	withoutCache bool,
+) (policies []types.AuthPolicy, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDP, "querySubjectAuthPolicies")
+
+	// 1. 查询关联的group pks
 	debug.AddStep(entry, "Get Effect AuthType Group PKs")
 	abacGroupPKs, rbacGroupPKs, err := getEffectAuthTypeGroupPKs(r.System, r.Subject, r.Action)
 	if err != nil {
@@ -362,7 +439,7 @@ func QueryAuthPolicies(
 	debug.WithValue(entry, "abacGroupPks", abacGroupPKs)
 	debug.WithValue(entry, "rbacGroupPks", rbacGroupPKs)
 
-	// 4. PRP查询subject-action相关的policies: 根据 system / subject / action 获取策略列表
+	// 2. PRP查询subject-action相关的policies: 根据 system / subject / action 获取策略列表
 	debug.AddStep(entry, "Query Policies")
 	withRbacPolicies := false
 	if len(rbacGroupPKs) > 0 {
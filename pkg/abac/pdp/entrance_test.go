@@ -52,6 +52,11 @@ var _ = Describe("Entrance", func() {
 			}
 
 			patches = gomonkey.NewPatches()
+			patches.ApplyFunc(listDelegatorSubjects, func(
+				systemID string, subject types.Subject, action types.Action,
+			) ([]types.Subject, error) {
+				return nil, nil
+			})
 		})
 		AfterEach(func() {
 			ctl.Finish()
@@ -335,6 +340,95 @@ var _ = Describe("Entrance", func() {
 			assert.False(GinkgoT(), ok)
 			assert.Error(GinkgoT(), err, "test")
 		})
+
+		It("fail, rbac pass and EvalDenyPolicies error", func() {
+			patches.ApplyFunc(fillActionDetail, func(req *request.Request) error {
+				req.Action = types.NewAction()
+				req.Action.FillAttributes(123, 1, nil)
+				return nil
+			})
+			patches.ApplyMethod(reflect.TypeOf(req), "ValidateActionResource",
+				func(_ *request.Request) bool {
+					return true
+				})
+			patches.ApplyFunc(fillSubjectDepartments, func(req *request.Request) error {
+				return nil
+			})
+			patches.ApplyFunc(getEffectAuthTypeGroupPKs, func(
+				system string,
+				subject types.Subject,
+				action types.Action,
+			) (abacGroupPKs []int64, rbacGroupPKs []int64, err error) {
+				return []int64{1}, []int64{2}, nil
+			})
+			patches.ApplyFunc(rbacEval, func(
+				system string, action types.Action, resources []types.Resource, effectGroupPKs []int64,
+				withoutCache bool, parentEntry *debug.Entry,
+			) (bool, error) {
+				return true, nil
+			})
+			patches.ApplyFunc(queryPolicies, func(system string,
+				subject types.Subject,
+				action types.Action,
+				effectGroupPKs []int64,
+				withRbacPolicies bool,
+				withoutCache bool,
+				entry *debug.Entry,
+			) (policies []types.AuthPolicy, err error) {
+				return []types.AuthPolicy{{ID: 3, Effect: 1}}, nil
+			})
+			patches.ApplyFunc(evaluation.EvalDenyPolicies, func(
+				ctx *evalctx.EvalContext, policies []types.AuthPolicy,
+			) (bool, int64, error) {
+				return false, 3, evaluation.ErrPolicyEvalFail
+			})
+
+			ok, err := Eval(req, entry, false)
+			assert.False(GinkgoT(), ok)
+			assert.ErrorIs(GinkgoT(), err, evaluation.ErrPolicyEvalFail)
+		})
+		It("fail, rbac pass and QueryPolicies error", func() {
+			patches.ApplyFunc(fillActionDetail, func(req *request.Request) error {
+				req.Action = types.NewAction()
+				req.Action.FillAttributes(123, 1, nil)
+				return nil
+			})
+			patches.ApplyMethod(reflect.TypeOf(req), "ValidateActionResource",
+				func(_ *request.Request) bool {
+					return true
+				})
+			patches.ApplyFunc(fillSubjectDepartments, func(req *request.Request) error {
+				return nil
+			})
+			patches.ApplyFunc(getEffectAuthTypeGroupPKs, func(
+				system string,
+				subject types.Subject,
+				action types.Action,
+			) (abacGroupPKs []int64, rbacGroupPKs []int64, err error) {
+				return []int64{1}, []int64{2}, nil
+			})
+			patches.ApplyFunc(rbacEval, func(
+				system string, action types.Action, resources []types.Resource, effectGroupPKs []int64,
+				withoutCache bool, parentEntry *debug.Entry,
+			) (bool, error) {
+				return true, nil
+			})
+			patches.ApplyFunc(queryPolicies, func(system string,
+				subject types.Subject,
+				action types.Action,
+				effectGroupPKs []int64,
+				withRbacPolicies bool,
+				withoutCache bool,
+				entry *debug.Entry,
+			) (policies []types.AuthPolicy, err error) {
+				return nil, errors.New("query policies fail")
+			})
+
+			ok, err := Eval(req, entry, false)
+			assert.False(GinkgoT(), ok)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "query policies fail")
+		})
 		//
 		It("ok, EvalPolicies success", func() {
 			patches.ApplyFunc(fillActionDetail, func(req *request.Request) error {
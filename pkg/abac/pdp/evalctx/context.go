@@ -31,6 +31,9 @@ PDP模块表达式求值
 type EvalContext struct {
 	*request.Request
 	objSet pdptypes.ObjectSetInterface
+
+	// 计算出错的策略ID
+	failedPolicyIDs []int64
 }
 
 // NewEvalContext new context
@@ -60,6 +63,13 @@ func NewEvalContext(req *request.Request) *EvalContext {
 		_type := r.System + "." + r.Type
 		objSet.Set(_type, r.Attribute)
 	}
+
+	// subject的自定义属性 => {system}._bk_iam_subject_
+	if req.Subject.Attribute != nil {
+		if attrs, ok := req.Subject.Attribute.GetAttributes(); ok {
+			objSet.Set(req.System+types.IamSubjectSuffix, attrs)
+		}
+	}
 	// TODO: 需要限制接入系统资源id字段不能配置为attribute; 因为会被覆盖
 	return &EvalContext{
 		Request: req,
@@ -135,6 +145,25 @@ func (c *EvalContext) GetAttr(name string) (interface{}, error) {
 	return c.objSet.GetAttribute(name), nil
 }
 
+// GetObjectAttr 获取资源的属性值, _type should be {system}.{resource_type}
+func (c *EvalContext) GetObjectAttr(_type, name string) interface{} {
+	obj, exists := c.objSet.Get(_type)
+	if !exists {
+		return nil
+	}
+	return obj[name]
+}
+
+// AddFailedPolicyID 记录计算出错的策略
+func (c *EvalContext) AddFailedPolicyID(policyID int64) {
+	c.failedPolicyIDs = append(c.failedPolicyIDs, policyID)
+}
+
+// FailedPolicyIDs 计算出错的策略ID列表
+func (c *EvalContext) FailedPolicyIDs() []int64 {
+	return c.failedPolicyIDs
+}
+
 func (c *EvalContext) HasResource(_type string) bool {
 	// has {system}.{resource_type}
 	return c.objSet.Has(_type)
@@ -152,6 +181,10 @@ func (c *EvalContext) HasEnv() bool {
 	return c.objSet.Has(c.System + types.IamEnvSuffix)
 }
 
+func (c *EvalContext) HasSubjectAttributes() bool {
+	return c.objSet.Has(c.System + types.IamSubjectSuffix)
+}
+
 func (c *EvalContext) InitEnvironments(cond condition.Condition, currentTime time.Time) error {
 	// build envs
 	c.UnsetEnv()
@@ -165,6 +198,8 @@ func (c *EvalContext) InitEnvironments(cond condition.Condition, currentTime tim
 	}
 
 	if cond.HasKey(hasEnvFunc) {
+		envs := make(map[string]interface{}, len(c.Environment)+7)
+
 		// NOTE: 开启环境属性, 不一定会有tz, 而是 有配置时间相关环境属性, 一定会配置tz
 		if tzValues, exists := cond.GetFirstMatchKeyValues(hasEnvTzFunc); exists {
 			if len(tzValues) != 1 {
@@ -176,29 +211,41 @@ func (c *EvalContext) InitEnvironments(cond condition.Condition, currentTime tim
 				return fmt.Errorf("pdp ctx initEnvironments got tz not a string")
 			}
 
-			envs, err := GenTimeEnvsFromCache(tz, currentTime)
+			timeEnvs, err := GenTimeEnvsFromCache(tz, currentTime)
 			if err != nil {
 				return fmt.Errorf("pdp gen envs fail, %w", err)
 			}
-			c.SetEnv(envs)
+			// NOTE: timeEnvs is cached and shared, do not change it, copy to envs
+			for k, v := range timeEnvs {
+				envs[k] = v
+			}
 		}
 
-		// NOTE: if got more envs, build it here before set
+		// 请求方传入的环境属性, e.g. client_ip / network_zone / device
+		for k, v := range c.Environment {
+			envs[k] = v
+		}
 
 		// e.g.
 		/*
 				{
 					// basic, should all have tz field
 					"tz": "Asia/Shanghai",
-					// now
 					"hms": 172910,
-					// later:
 					"ts": 1638523704,
 					"weekday": 3,
 					"monthday": 29,
 			        "month": 12,
+					"date": 20211229,
+
+					// from request
+					"client_ip": "10.0.0.1",
+					"network_zone": "office",
 				}
 		*/
+		if len(envs) > 0 {
+			c.SetEnv(envs)
+		}
 	}
 	return nil
 }
@@ -237,10 +284,23 @@ func genTimeEnvs(tz string, currentTime time.Time) (map[string]interface{}, erro
 	// hms means hour-minute-second, transfer 08:30:20 to 83020; 10:41:21 to 104121
 	hms := int64(10000*t.Hour() + 100*t.Minute() + t.Second())
 
+	// weekday: Monday=1 ... Sunday=7, so that `Mon-Fri` is a continuous range [1, 5]
+	weekday := int64(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+
+	// date means year-month-day, transfer 2021-12-03 to 20211203, for date range like [20211201, 20211211]
+	date := int64(10000*t.Year() + 100*int(t.Month()) + t.Day())
+
 	envs := map[string]interface{}{
-		"tz":  tz,
-		"hms": hms,
-		// "ts":  t.Unix(),
+		"tz":       tz,
+		"hms":      hms,
+		"ts":       t.Unix(),
+		"weekday":  weekday,
+		"monthday": int64(t.Day()),
+		"month":    int64(t.Month()),
+		"date":     date,
 	}
 	return envs, nil
 }
@@ -76,6 +76,18 @@ var _ = Describe("Context", func() {
 			ec := NewEvalContext(req)
 			assert.NotNil(GinkgoT(), ec)
 		})
+
+		It("ok, has subject attributes", func() {
+			req.Subject.Attribute = types.NewSubjectAttribute()
+			req.Subject.Attribute.SetAttributes(map[string]interface{}{"level": float64(3)})
+
+			ec := NewEvalContext(req)
+			assert.True(GinkgoT(), ec.HasSubjectAttributes())
+
+			level, err := ec.GetAttr("iam._bk_iam_subject_.level")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), float64(3), level)
+		})
 	})
 
 	Describe("GetAttr", func() {
@@ -134,6 +146,12 @@ var _ = Describe("Context", func() {
 		})
 	})
 
+	Describe("HasSubjectAttributes", func() {
+		It("ok", func() {
+			assert.False(GinkgoT(), c.HasSubjectAttributes())
+		})
+	})
+
 	Describe("InitEnvironments", func() {
 		var noEnvCond condition.Condition
 		var notEnvTimeCond condition.Condition
@@ -233,6 +251,105 @@ var _ = Describe("Context", func() {
 			assert.Equal(GinkgoT(), hms, hmsA)
 		})
 
+		It("has time-related env, weekday and date range", func() {
+			c4 := pdptypes.PolicyCondition{
+				"AND": map[string][]interface{}{
+					"content": {
+						map[string]interface{}{
+							"StringEquals": map[string]interface{}{
+								"iam._bk_iam_env_.tz": []interface{}{"Asia/Shanghai"},
+							},
+						},
+						map[string]interface{}{
+							"NumericGte": map[string]interface{}{"iam._bk_iam_env_.weekday": []interface{}{1}},
+						},
+						map[string]interface{}{
+							"NumericLte": map[string]interface{}{"iam._bk_iam_env_.weekday": []interface{}{5}},
+						},
+						map[string]interface{}{
+							"NumericGte": map[string]interface{}{"iam._bk_iam_env_.hms": []interface{}{90000}},
+						},
+						map[string]interface{}{
+							"NumericLt": map[string]interface{}{"iam._bk_iam_env_.hms": []interface{}{180000}},
+						},
+						map[string]interface{}{
+							"NumericGte": map[string]interface{}{"iam._bk_iam_env_.date": []interface{}{20211201}},
+						},
+						map[string]interface{}{
+							"NumericLte": map[string]interface{}{"iam._bk_iam_env_.date": []interface{}{20211211}},
+						},
+					},
+				},
+			}
+			cond, err := condition.NewConditionFromPolicyCondition(c4)
+			assert.NoError(GinkgoT(), err)
+
+			loc, _ := time.LoadLocation("Asia/Shanghai")
+			// Friday
+			t, _ := time.ParseInLocation("2006-01-02 15:04:05", "2021-12-03 15:54:06", loc)
+			err = c.InitEnvironments(cond, t)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), cond.Eval(c))
+
+			// Saturday
+			t, _ = time.ParseInLocation("2006-01-02 15:04:05", "2021-12-04 15:54:06", loc)
+			err = c.InitEnvironments(cond, t)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), cond.Eval(c))
+
+			// out of date range
+			t, _ = time.ParseInLocation("2006-01-02 15:04:05", "2021-12-13 15:54:06", loc)
+			err = c.InitEnvironments(cond, t)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), cond.Eval(c))
+		})
+
+		It("has request env, ip in cidr", func() {
+			c5 := pdptypes.PolicyCondition{
+				"IPInCIDR": map[string][]interface{}{
+					"iam._bk_iam_env_.client_ip": {"10.0.0.0/8"},
+				},
+			}
+			cond, err := condition.NewConditionFromPolicyCondition(c5)
+			assert.NoError(GinkgoT(), err)
+
+			c.Environment = map[string]interface{}{"client_ip": "10.1.1.1"}
+			err = c.InitEnvironments(cond, time.Now())
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), c.HasResource("iam._bk_iam_env_"))
+			assert.True(GinkgoT(), cond.Eval(c))
+
+			c.Environment = map[string]interface{}{"client_ip": "192.168.1.1"}
+			err = c.InitEnvironments(cond, time.Now())
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), cond.Eval(c))
+
+			// no env from request
+			c.Environment = nil
+			err = c.InitEnvironments(cond, time.Now())
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), c.HasResource("iam._bk_iam_env_"))
+			assert.False(GinkgoT(), cond.Eval(c))
+		})
+
+		It("has time-related env and request env, cache not changed", func() {
+			c.Environment = map[string]interface{}{"network_zone": "office"}
+
+			loc, _ := time.LoadLocation("Asia/Shanghai")
+			t, _ := time.ParseInLocation("2006-01-02 15:04:05", "2021-12-03 15:54:06", loc)
+			err := c.InitEnvironments(envTimeCond, t)
+			assert.NoError(GinkgoT(), err)
+
+			zone, _ := c.GetAttr("iam._bk_iam_env_.network_zone")
+			assert.Equal(GinkgoT(), "office", zone)
+			tz, _ := c.GetAttr("iam._bk_iam_env_.tz")
+			assert.Equal(GinkgoT(), "Asia/Shanghai", tz)
+
+			cachedEnvs, err := GenTimeEnvsFromCache("Asia/Shanghai", t)
+			assert.NoError(GinkgoT(), err)
+			assert.NotContains(GinkgoT(), cachedEnvs, "network_zone")
+		})
+
 		It("has time-related env, 2 tz", func() {
 			c3 := pdptypes.PolicyCondition{
 				"AND": map[string][]interface{}{
@@ -307,7 +424,7 @@ var _ = Describe("Context", func() {
 				envs, err := GenTimeEnvsFromCache(tz, t)
 				assert.NoError(GinkgoT(), err)
 
-				assert.Len(GinkgoT(), envs, 2)
+				assert.Len(GinkgoT(), envs, 7)
 				assert.Equal(GinkgoT(), tz, envs["tz"])
 				assert.Equal(GinkgoT(), hms, envs["hms"])
 
@@ -328,9 +445,24 @@ var _ = Describe("Context", func() {
 				envs, err := genTimeEnvs(tz, t)
 				assert.NoError(GinkgoT(), err)
 
-				assert.Len(GinkgoT(), envs, 2)
+				assert.Len(GinkgoT(), envs, 7)
 				assert.Equal(GinkgoT(), tz, envs["tz"])
 				assert.Equal(GinkgoT(), hms, envs["hms"])
+				assert.Equal(GinkgoT(), t.Unix(), envs["ts"])
+				assert.Equal(GinkgoT(), int64(5), envs["weekday"])
+				assert.Equal(GinkgoT(), int64(3), envs["monthday"])
+				assert.Equal(GinkgoT(), int64(12), envs["month"])
+				assert.Equal(GinkgoT(), int64(20211203), envs["date"])
+			})
+
+			It("ok, sunday", func() {
+				loc, _ := time.LoadLocation(tz)
+				sunday, _ := time.ParseInLocation("2006-01-02 15:04:05", "2021-12-05 01:02:03", loc)
+				envs, err := genTimeEnvs(tz, sunday)
+				assert.NoError(GinkgoT(), err)
+
+				assert.Equal(GinkgoT(), int64(7), envs["weekday"])
+				assert.Equal(GinkgoT(), int64(10203), envs["hms"])
 			})
 
 			It("fail", func() {
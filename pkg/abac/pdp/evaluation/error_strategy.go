@@ -0,0 +1,94 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	gocache "github.com/wklken/go-cache"
+
+	"iam/pkg/abac/pdp/evalctx"
+	"iam/pkg/abac/types"
+	"iam/pkg/metric"
+	svctypes "iam/pkg/service/types"
+	"iam/pkg/util"
+)
+
+// 单条allow策略计算出错(表达式解析失败/环境属性初始化失败等)时的处理策略
+// NOTE: deny策略出错时无法确定是否需要拒绝, 任何策略下都直接返回错误, 避免deny失效
+const (
+	// ErrorStrategySkip 跳过出错的allow策略继续计算其它策略, 上报sentry
+	ErrorStrategySkip = "skip"
+	// ErrorStrategyFailClosed 跳过出错的allow策略继续计算, 最终没有通过时返回错误
+	ErrorStrategyFailClosed = "fail_closed"
+	// ErrorStrategyStrict 任意一条allow策略出错, 直接返回错误
+	ErrorStrategyStrict = "strict"
+)
+
+// ErrPolicyEvalFail 策略计算出错
+var ErrPolicyEvalFail = errors.New("policy eval fail")
+
+var errorStrategy = ErrorStrategySkip
+
+// policyErrorReportInterval 同一条策略出错时, 在该时间内只记录一次error日志并上报sentry, 出错次数通过metric统计
+const policyErrorReportInterval = 10 * time.Minute
+
+// reportedPolicyErrors 时间窗口内已上报的出错策略ID
+var reportedPolicyErrors = gocache.New(policyErrorReportInterval, 5*time.Minute)
+
+// InitErrorStrategy 初始化策略计算出错时的处理策略, 为空时默认skip
+func InitErrorStrategy(strategy string) error {
+	switch strategy {
+	case "":
+		errorStrategy = ErrorStrategySkip
+	case ErrorStrategySkip, ErrorStrategyFailClosed, ErrorStrategyStrict:
+		errorStrategy = strategy
+	default:
+		return fmt.Errorf("unsupported policy eval error strategy %s", strategy)
+	}
+	return nil
+}
+
+// handlePolicyError 记录出错的策略并计数, 返回包装后的错误
+func handlePolicyError(ctx *evalctx.EvalContext, policy types.AuthPolicy, err error) error {
+	ctx.AddFailedPolicyID(policy.ID)
+	metric.PolicyEvalErrorCount.WithLabelValues(ctx.System, ctx.Action.ID).Inc()
+
+	// 同一条出错的策略会影响所有相关的请求, 需要去重, 避免日志与sentry被刷屏
+	if shouldReportPolicyError(policy.ID) {
+		log.Errorf("pdp eval policy fail, system=%s, action=%s, policy id=%d, strategy=%s, error=%v",
+			ctx.System, ctx.Action.ID, policy.ID, errorStrategy, err)
+
+		// skip时出错的allow策略不会返回错误给调用方, 需要上报
+		if errorStrategy == ErrorStrategySkip && policy.Effect != svctypes.PolicyEffectDeny {
+			util.ReportToSentry("pdp eval policy fail, skip the policy", map[string]interface{}{
+				"system":    ctx.System,
+				"action":    ctx.Action.ID,
+				"policy_id": policy.ID,
+				"error":     err.Error(),
+			})
+		}
+	} else {
+		log.Debugf("pdp eval policy fail, system=%s, action=%s, policy id=%d, error=%v",
+			ctx.System, ctx.Action.ID, policy.ID, err)
+	}
+
+	return fmt.Errorf("%w: policy id=%d, %s", ErrPolicyEvalFail, policy.ID, err.Error())
+}
+
+// shouldReportPolicyError 策略在时间窗口内是否第一次出错
+func shouldReportPolicyError(policyID int64) bool {
+	return reportedPolicyErrors.Add(strconv.FormatInt(policyID, 10), struct{}{}, gocache.DefaultExpiration) == nil
+}
@@ -0,0 +1,206 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package evaluation
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	gocache "github.com/wklken/go-cache"
+
+	"iam/pkg/abac/pdp/evalctx"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/cacheimpls"
+	svctypes "iam/pkg/service/types"
+)
+
+var _ = Describe("ErrorStrategy", func() {
+	passExpression := `[{"system": "iam", "type": "job", "expression": {"StringEquals": {"system": ["linux"]}}}]`
+	errorExpression := `[{"system": "iam", "type": "job", "expression": {"NotExists": {}}}]`
+
+	passPolicy := types.AuthPolicy{ID: 1, Expression: passExpression, ExpressionSignature: "pass"}
+	errorPolicy := types.AuthPolicy{ID: 2, Expression: errorExpression, ExpressionSignature: "error"}
+	errorDenyPolicy := types.AuthPolicy{
+		ID:                  3,
+		Expression:          errorExpression,
+		ExpressionSignature: "error",
+		Effect:              svctypes.PolicyEffectDeny,
+	}
+
+	var c *evalctx.EvalContext
+	BeforeEach(func() {
+		r := &request.Request{
+			System:  "iam",
+			Subject: types.Subject{Type: "user", ID: "admin"},
+			Action:  types.Action{ID: "execute_job", Attribute: types.NewActionAttribute()},
+			Resources: []types.Resource{
+				{
+					System:    "iam",
+					Type:      "job",
+					ID:        "job1",
+					Attribute: map[string]interface{}{"system": "linux"},
+				},
+			},
+		}
+		r.Action.Attribute.SetResourceTypes([]types.ActionResourceType{{System: "iam", Type: "job"}})
+		c = evalctx.NewEvalContext(r)
+
+		cacheimpls.LocalUnmarshaledExpressionCache = gocache.New(1*time.Minute, 5*time.Minute)
+		cacheimpls.LocalCompiledExpressionCache = gocache.New(1*time.Minute, 5*time.Minute)
+	})
+	AfterEach(func() {
+		errorStrategy = ErrorStrategySkip
+	})
+
+	Describe("InitErrorStrategy", func() {
+		It("empty, default skip", func() {
+			errorStrategy = ErrorStrategyStrict
+			assert.NoError(GinkgoT(), InitErrorStrategy(""))
+			assert.Equal(GinkgoT(), ErrorStrategySkip, errorStrategy)
+		})
+
+		It("ok", func() {
+			assert.NoError(GinkgoT(), InitErrorStrategy(ErrorStrategyFailClosed))
+			assert.Equal(GinkgoT(), ErrorStrategyFailClosed, errorStrategy)
+		})
+
+		It("unsupported", func() {
+			assert.Error(GinkgoT(), InitErrorStrategy("unknown"))
+			assert.Equal(GinkgoT(), ErrorStrategySkip, errorStrategy)
+		})
+	})
+
+	It("shouldReportPolicyError", func() {
+		reportedPolicyErrors = gocache.New(policyErrorReportInterval, 5*time.Minute)
+
+		assert.True(GinkgoT(), shouldReportPolicyError(10))
+		assert.False(GinkgoT(), shouldReportPolicyError(10))
+		assert.True(GinkgoT(), shouldReportPolicyError(11))
+
+		_, _, err := EvalPolicies(c, []types.AuthPolicy{errorPolicy})
+		assert.NoError(GinkgoT(), err)
+		assert.False(GinkgoT(), shouldReportPolicyError(errorPolicy.ID))
+	})
+
+	Describe("skip", func() {
+		It("EvalPolicies, error then pass", func() {
+			allowed, policyID, err := EvalPolicies(c, []types.AuthPolicy{errorPolicy, passPolicy})
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), allowed)
+			assert.Equal(GinkgoT(), int64(1), policyID)
+			assert.Equal(GinkgoT(), []int64{2}, c.FailedPolicyIDs())
+		})
+
+		It("EvalPolicies, deny error with matched allow", func() {
+			allowed, policyID, err := EvalPolicies(c, []types.AuthPolicy{errorDenyPolicy, passPolicy})
+			assert.False(GinkgoT(), allowed)
+			assert.Equal(GinkgoT(), int64(3), policyID)
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+			assert.Equal(GinkgoT(), []int64{3}, c.FailedPolicyIDs())
+		})
+
+		It("EvalDenyPolicies, deny error", func() {
+			isDeny, policyID, err := EvalDenyPolicies(c, []types.AuthPolicy{errorDenyPolicy})
+			assert.False(GinkgoT(), isDeny)
+			assert.Equal(GinkgoT(), int64(3), policyID)
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+		})
+
+		It("PartialEvalPolicies, deny error with matched allow", func() {
+			_, _, err := PartialEvalPolicies(c, []types.AuthPolicy{errorDenyPolicy, passPolicy})
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+		})
+
+		It("PartialEvalPolicies, error only", func() {
+			conds, ids, err := PartialEvalPolicies(c, []types.AuthPolicy{errorPolicy})
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), conds)
+			assert.Empty(GinkgoT(), ids)
+		})
+	})
+
+	Describe("fail_closed", func() {
+		BeforeEach(func() {
+			errorStrategy = ErrorStrategyFailClosed
+		})
+
+		It("EvalPolicies, error then pass", func() {
+			allowed, policyID, err := EvalPolicies(c, []types.AuthPolicy{errorPolicy, passPolicy})
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), allowed)
+			assert.Equal(GinkgoT(), int64(1), policyID)
+		})
+
+		It("EvalPolicies, error only", func() {
+			allowed, _, err := EvalPolicies(c, []types.AuthPolicy{errorPolicy})
+			assert.False(GinkgoT(), allowed)
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+		})
+
+		It("EvalPolicies, deny error", func() {
+			allowed, policyID, err := EvalPolicies(c, []types.AuthPolicy{errorDenyPolicy, passPolicy})
+			assert.False(GinkgoT(), allowed)
+			assert.Equal(GinkgoT(), int64(3), policyID)
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+		})
+
+		It("EvalDenyPolicies, deny error", func() {
+			isDeny, policyID, err := EvalDenyPolicies(c, []types.AuthPolicy{errorDenyPolicy})
+			assert.False(GinkgoT(), isDeny)
+			assert.Equal(GinkgoT(), int64(3), policyID)
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+		})
+
+		It("PartialEvalPolicies, error then pass", func() {
+			conds, ids, err := PartialEvalPolicies(c, []types.AuthPolicy{errorPolicy, passPolicy})
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), conds, 1)
+			assert.Equal(GinkgoT(), []int64{1}, ids)
+		})
+
+		It("PartialEvalPolicies, error only", func() {
+			_, _, err := PartialEvalPolicies(c, []types.AuthPolicy{errorPolicy})
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+		})
+	})
+
+	Describe("strict", func() {
+		BeforeEach(func() {
+			errorStrategy = ErrorStrategyStrict
+		})
+
+		It("EvalPolicies, error then pass", func() {
+			allowed, _, err := EvalPolicies(c, []types.AuthPolicy{errorPolicy, passPolicy})
+			assert.False(GinkgoT(), allowed)
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+			assert.Equal(GinkgoT(), []int64{2}, c.FailedPolicyIDs())
+		})
+
+		It("PartialEvalPolicies, error then pass", func() {
+			_, _, err := PartialEvalPolicies(c, []types.AuthPolicy{errorPolicy, passPolicy})
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+		})
+
+		It("EvalDenyPolicies, deny error", func() {
+			isDeny, _, err := EvalDenyPolicies(c, []types.AuthPolicy{errorDenyPolicy})
+			assert.False(GinkgoT(), isDeny)
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+		})
+
+		It("PartialEvalPolicies, deny error", func() {
+			_, _, err := PartialEvalPolicies(c, []types.AuthPolicy{errorDenyPolicy, passPolicy})
+			assert.True(GinkgoT(), errors.Is(err, ErrPolicyEvalFail))
+		})
+	})
+})
@@ -22,6 +22,7 @@ import (
 	"iam/pkg/abac/pdp/evalctx"
 	"iam/pkg/abac/types"
 	"iam/pkg/cacheimpls"
+	svctypes "iam/pkg/service/types"
 )
 
 // NOTE: 目前所有的 query/eval都在这个文件中, 两个主要入口:
@@ -29,24 +30,86 @@ import (
 // - query: PartialEvalPolicies
 
 // EvalPolicies 计算是否满足
+// NOTE: deny优先, 任意一条deny策略满足则直接返回不通过, 此时policyID为命中的deny策略ID
+// 单条allow策略计算出错时, 按errorStrategy处理; deny策略计算出错时, 无论何种errorStrategy都返回错误, 出错的策略ID记录在ctx中
 func EvalPolicies(ctx *evalctx.EvalContext, policies []types.AuthPolicy) (isPass bool, policyID int64, err error) {
 	currentTime := time.Now()
 
-	for _, policy := range policies {
+	allowPolicies, denyPolicies := splitPoliciesByEffect(policies)
+
+	for _, policy := range denyPolicies {
+		isMatch, err := evalPolicy(ctx, policy, currentTime)
+		if err != nil {
+			// 无法确定deny策略是否满足, 不通过, 避免deny失效
+			return false, policy.ID, handlePolicyError(ctx, policy, err)
+		}
+
+		if isMatch {
+			log.Debugf("pdp evalPolicy: ctx=`%+v`, policy=`%+v`, deny", ctx, policy)
+			return false, policy.ID, nil
+		}
+	}
+
+	var evalErr error
+	for _, policy := range allowPolicies {
 		isPass, err = evalPolicy(ctx, policy, currentTime)
 		if err != nil {
-			log.Debugf("pdp evalPolicy: ctx=`%+v`, policy=`%+v`, error=`%s`", ctx, policy, err)
+			evalErr = handlePolicyError(ctx, policy, err)
+			if errorStrategy == ErrorStrategyStrict {
+				return false, -1, evalErr
+			}
+			continue
 		}
 
 		if isPass {
 			log.Debugf("pdp evalPolicy: ctx=`%+v`, policy=`%+v`, pass", ctx, policy)
-			return isPass, policy.ID, err
+			return isPass, policy.ID, nil
+		}
+	}
+
+	// 没有策略通过, 并且有策略出错
+	if evalErr != nil && errorStrategy == ErrorStrategyFailClosed {
+		return false, -1, evalErr
+	}
+	return false, -1, nil
+}
+
+// EvalDenyPolicies 计算是否有deny策略满足, 返回命中的deny策略ID
+// NOTE: deny策略计算出错返回错误, 此时policyID为出错的deny策略ID
+func EvalDenyPolicies(
+	ctx *evalctx.EvalContext, policies []types.AuthPolicy,
+) (isDeny bool, policyID int64, err error) {
+	currentTime := time.Now()
+
+	_, denyPolicies := splitPoliciesByEffect(policies)
+	for _, policy := range denyPolicies {
+		isMatch, err := evalPolicy(ctx, policy, currentTime)
+		if err != nil {
+			// 无法确定deny策略是否满足, 返回错误
+			return false, policy.ID, handlePolicyError(ctx, policy, err)
+		}
+
+		if isMatch {
+			return true, policy.ID, nil
 		}
 	}
-	// TODO: 如果一条报错, 整体结果如何???
+
 	return false, -1, nil
 }
 
+// splitPoliciesByEffect 按effect拆分为allow与deny策略
+func splitPoliciesByEffect(policies []types.AuthPolicy) (allowPolicies, denyPolicies []types.AuthPolicy) {
+	allowPolicies = make([]types.AuthPolicy, 0, len(policies))
+	for _, policy := range policies {
+		if policy.Effect == svctypes.PolicyEffectDeny {
+			denyPolicies = append(denyPolicies, policy)
+		} else {
+			allowPolicies = append(allowPolicies, policy)
+		}
+	}
+	return
+}
+
 // evalPolicy 计算单个policy是否满足
 func evalPolicy(ctx *evalctx.EvalContext, policy types.AuthPolicy, currentTime time.Time) (bool, error) {
 	// action 不关联资源类型时, 直接返回true
@@ -55,49 +118,158 @@ func evalPolicy(ctx *evalctx.EvalContext, policy types.AuthPolicy, currentTime t
 		return true, nil
 	}
 
+	cond, err := initPolicyCondition(ctx, policy, currentTime, true)
+	if err != nil {
+		return false, err
+	}
+
+	isPass := cond.Eval(ctx)
+	return isPass, err
+}
+
+// initPolicyCondition 解析policy的表达式, 并初始化求值上下文的环境属性
+// compiled为true时返回编译后的条件, 只用于Eval
+func initPolicyCondition(
+	ctx *evalctx.EvalContext,
+	policy types.AuthPolicy,
+	currentTime time.Time,
+	compiled bool,
+) (cond condition.Condition, err error) {
 	// 需要传递resource却没有传, 此时直接false
 	if !ctx.HasResources() {
-		return false, fmt.Errorf("evalPolicy action: %s get not resource in request", ctx.Action.ID)
+		return nil, fmt.Errorf("evalPolicy action: %s get not resource in request", ctx.Action.ID)
 	}
 
-	cond, err := cacheimpls.GetUnmarshalledResourceExpression(
-		policy.Expression,
-		policy.ExpressionSignature,
-		currentTime.UnixNano(),
-	)
+	if compiled {
+		cond, err = cacheimpls.GetCompiledResourceExpression(
+			policy.Expression,
+			policy.ExpressionSignature,
+			currentTime.UnixNano(),
+		)
+	} else {
+		cond, err = cacheimpls.GetUnmarshalledResourceExpression(
+			policy.Expression,
+			policy.ExpressionSignature,
+			currentTime.UnixNano(),
+		)
+	}
 	if err != nil {
 		log.Debugf("pdp evalPolicy policy id: %d expression: %s format error: %v",
 			policy.ID, policy.Expression, err)
 
-		return false, err
+		return nil, err
 	}
 
 	err = ctx.InitEnvironments(cond, currentTime)
 	if err != nil {
 		log.Errorf("pdp evalPolicy polidy id:%d expression: %s, currentTime: %s, error:%v",
 			policy.ID, policy.Expression, currentTime, err)
-		return false, err
+		return nil, err
 	}
 
-	isPass := cond.Eval(ctx)
-	return isPass, err
+	return cond, nil
+}
+
+// ExplainPolicies 逐条计算policy是否满足, 并记录不满足的条件节点, 用于解释鉴权结果
+// NOTE: 与EvalPolicies不同, 不会在命中后提前返回
+func ExplainPolicies(ctx *evalctx.EvalContext, policies []types.AuthPolicy) []types.PolicyExplanation {
+	currentTime := time.Now()
+
+	explanations := make([]types.PolicyExplanation, 0, len(policies))
+	for _, policy := range policies {
+		explanation := types.PolicyExplanation{
+			ID:        policy.ID,
+			Effect:    svctypes.ConvertToPolicyEffectStr(policy.Effect),
+			ExpiredAt: policy.ExpiredAt,
+		}
+
+		isMatch, failedConditions, err := explainPolicy(ctx, policy, currentTime)
+		if err != nil {
+			explanation.Error = err.Error()
+		}
+		explanation.Matched = isMatch
+		explanation.FailedConditions = failedConditions
+
+		explanations = append(explanations, explanation)
+	}
+	return explanations
+}
+
+// explainPolicy 计算单个policy是否满足, 不满足时返回不满足的条件节点
+func explainPolicy(
+	ctx *evalctx.EvalContext,
+	policy types.AuthPolicy,
+	currentTime time.Time,
+) (bool, []types.ConditionExplanation, error) {
+	// action 不关联资源类型时, 直接返回true
+	if ctx.Action.WithoutResourceType() {
+		return true, nil, nil
+	}
+
+	cond, err := initPolicyCondition(ctx, policy, currentTime, false)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if cond.Eval(ctx) {
+		return true, nil, nil
+	}
+	return false, condition.ExplainFailedConditions(cond, ctx), nil
 }
 
 // PartialEvalPolicies 筛选check pass的policies
+// NOTE: deny优先, 命中的deny策略会以NOT的形式与allow策略的残留条件组合: (allow1 OR allow2) AND NOT (deny1 OR deny2)
+// 单条allow策略计算出错时, 按errorStrategy处理; deny策略计算出错时直接返回错误, 出错的策略ID记录在ctx中
 func PartialEvalPolicies(
 	ctx *evalctx.EvalContext,
 	policies []types.AuthPolicy,
+) ([]condition.Condition, []int64, error) {
+	return PartialEvalPoliciesWithAllowConditions(ctx, policies, nil)
+}
+
+// PartialEvalPoliciesWithAllowConditions 与PartialEvalPolicies一致, allowConditions为额外的allow残留条件(例如委托的权限),
+// 与allow策略的残留条件一起受deny策略的限制
+func PartialEvalPoliciesWithAllowConditions(
+	ctx *evalctx.EvalContext,
+	policies []types.AuthPolicy,
+	allowConditions []condition.Condition,
 ) ([]condition.Condition, []int64, error) {
 	currentTime := time.Now()
 
-	remainedConditions := make([]condition.Condition, 0, len(policies))
+	allowPolicies, denyPolicies := splitPoliciesByEffect(policies)
 
-	passedPolicyIDs := make([]int64, 0, len(policies))
-	for _, policy := range policies {
+	denyConditions := make([]condition.Condition, 0, len(denyPolicies))
+	for _, policy := range denyPolicies {
+		isMatch, cond, err := partialEvalPolicy(ctx, policy, currentTime)
+		if err != nil {
+			// 无法确定deny策略的范围, 返回错误
+			return nil, nil, handlePolicyError(ctx, policy, err)
+		}
+
+		if !isMatch || cond == nil {
+			continue
+		}
+
+		// deny策略完全满足, 没有任何权限
+		if cond.GetName() == operator.ANY {
+			return []condition.Condition{}, []int64{}, nil
+		}
+		denyConditions = append(denyConditions, cond)
+	}
+
+	remainedConditions := make([]condition.Condition, 0, len(allowPolicies)+len(allowConditions))
+	remainedConditions = append(remainedConditions, allowConditions...)
+
+	passedPolicyIDs := make([]int64, 0, len(allowPolicies))
+	var evalErr error
+	for _, policy := range allowPolicies {
 		isPass, condition, err := partialEvalPolicy(ctx, policy, currentTime)
 		if err != nil {
-			// TODO: 一条报错怎么处理?????
-			log.Debugf("pdp PartialEvalPoliciesy policy: %+v ctx: %+v error: %s", policy, ctx, err)
+			evalErr = handlePolicyError(ctx, policy, err)
+			if errorStrategy == ErrorStrategyStrict {
+				return nil, nil, evalErr
+			}
+			continue
 		}
 
 		if isPass {
@@ -109,7 +281,55 @@ func PartialEvalPolicies(
 		}
 	}
 
-	return remainedConditions, passedPolicyIDs, nil
+	// 没有策略通过, 并且有策略出错
+	if len(passedPolicyIDs) == 0 && len(allowConditions) == 0 && evalErr != nil &&
+		errorStrategy == ErrorStrategyFailClosed {
+		return nil, nil, evalErr
+	}
+
+	if len(remainedConditions) == 0 || len(denyConditions) == 0 {
+		return remainedConditions, passedPolicyIDs, nil
+	}
+
+	return []condition.Condition{applyDenyConditions(remainedConditions, denyConditions)}, passedPolicyIDs, nil
+}
+
+// MatchPolicy 使用请求中的资源实例部分求值, 只有残留条件为any(完全满足)的策略才算命中
+// NOTE: 用于反查有权限的subject, 残留条件依赖请求中未提供的资源时视为不满足
+func MatchPolicy(ctx *evalctx.EvalContext, policy types.AuthPolicy, currentTime time.Time) (bool, error) {
+	isPass, cond, err := partialEvalPolicy(ctx, policy, currentTime)
+	if err != nil {
+		return false, err
+	}
+
+	return isPass && cond != nil && cond.GetName() == operator.ANY, nil
+}
+
+// applyDenyConditions 将allow的残留条件与deny的残留条件组合
+func applyDenyConditions(allowConditions, denyConditions []condition.Condition) condition.Condition {
+	var denyCondition condition.Condition
+	if len(denyConditions) == 1 {
+		denyCondition = denyConditions[0]
+	} else {
+		denyCondition = condition.NewOrCondition(denyConditions)
+	}
+	notDenyCondition := condition.NewNotCondition(denyCondition)
+
+	// 任意allow为any, 则只需要排除deny
+	for _, c := range allowConditions {
+		if c.GetName() == operator.ANY {
+			return notDenyCondition
+		}
+	}
+
+	var allowCondition condition.Condition
+	if len(allowConditions) == 1 {
+		allowCondition = allowConditions[0]
+	} else {
+		allowCondition = condition.NewOrCondition(allowConditions)
+	}
+
+	return condition.NewAndCondition([]condition.Condition{allowCondition, notDenyCondition})
 }
 
 func partialEvalPolicy(
@@ -143,7 +363,7 @@ func partialEvalPolicy(
 	}
 
 	// if no resource passed
-	if !(ctx.HasResources() || ctx.HasEnv()) {
+	if !(ctx.HasResources() || ctx.HasEnv() || ctx.HasSubjectAttributes()) {
 		return true, cond, nil
 	}
 
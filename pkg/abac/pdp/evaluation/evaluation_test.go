@@ -22,6 +22,7 @@ import (
 	"iam/pkg/abac/types"
 	"iam/pkg/abac/types/request"
 	"iam/pkg/cacheimpls"
+	svctypes "iam/pkg/service/types"
 )
 
 var _ = Describe("Evaluation", func() {
@@ -94,6 +95,14 @@ var _ = Describe("Evaluation", func() {
 		ExpiredAt:           0,
 	}
 
+	willDenyPolicy := willPassPolicy
+	willDenyPolicy.ID = 2
+	willDenyPolicy.Effect = svctypes.PolicyEffectDeny
+
+	willNotDenyPolicy := willNotPassPolicy
+	willNotDenyPolicy.ID = 3
+	willNotDenyPolicy.Effect = svctypes.PolicyEffectDeny
+
 	BeforeEach(func() {
 		request := &request.Request{
 			System: "iam",
@@ -129,6 +138,7 @@ var _ = Describe("Evaluation", func() {
 		}
 
 		cacheimpls.LocalUnmarshaledExpressionCache = gocache.New(1*time.Minute, 5*time.Minute)
+		cacheimpls.LocalCompiledExpressionCache = gocache.New(1*time.Minute, 5*time.Minute)
 	})
 
 	Describe("EvalPolicies", func() {
@@ -190,9 +200,95 @@ var _ = Describe("Evaluation", func() {
 				willErrorPolicy,
 			}
 			allowed, _, err := EvalPolicies(c, policies)
-			// will skip the policy, only log.debug
+			// will skip the policy by default strategy
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), allowed)
+		})
+
+		It("ok, one pass, one deny", func() {
+			policies := []types.AuthPolicy{
+				willPassPolicy,
+				willDenyPolicy,
+			}
+
+			allowed, policyID, err := EvalPolicies(c, policies)
 			assert.NoError(GinkgoT(), err)
 			assert.False(GinkgoT(), allowed)
+			assert.Equal(GinkgoT(), int64(2), policyID)
+		})
+
+		It("ok, one pass, one deny not match", func() {
+			policies := []types.AuthPolicy{
+				willNotDenyPolicy,
+				willPassPolicy,
+			}
+
+			allowed, policyID, err := EvalPolicies(c, policies)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), allowed)
+			assert.Equal(GinkgoT(), int64(1), policyID)
+		})
+	})
+
+	Describe("EvalDenyPolicies", func() {
+		It("ok, deny", func() {
+			isDeny, policyID, err := EvalDenyPolicies(c, []types.AuthPolicy{willPassPolicy, willDenyPolicy})
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), isDeny)
+			assert.Equal(GinkgoT(), int64(2), policyID)
+		})
+
+		It("ok, not deny", func() {
+			isDeny, policyID, err := EvalDenyPolicies(c, []types.AuthPolicy{willPassPolicy, willNotDenyPolicy})
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), isDeny)
+			assert.Equal(GinkgoT(), int64(-1), policyID)
+		})
+	})
+
+	Describe("ExplainPolicies", func() {
+		It("no policies", func() {
+			explanations := ExplainPolicies(c, []types.AuthPolicy{})
+			assert.Empty(GinkgoT(), explanations)
+		})
+
+		It("ok, pass, not pass, deny", func() {
+			explanations := ExplainPolicies(c, []types.AuthPolicy{
+				willPassPolicy,
+				willNotPassPolicy,
+				willDenyPolicy,
+			})
+			assert.Len(GinkgoT(), explanations, 3)
+
+			assert.Equal(GinkgoT(), int64(1), explanations[0].ID)
+			assert.Equal(GinkgoT(), "allow", explanations[0].Effect)
+			assert.True(GinkgoT(), explanations[0].Matched)
+			assert.Empty(GinkgoT(), explanations[0].FailedConditions)
+
+			assert.False(GinkgoT(), explanations[1].Matched)
+			assert.Equal(GinkgoT(), []types.ConditionExplanation{
+				{
+					Operator: "StringEquals",
+					Key:      "iam.job.system",
+					Expected: []interface{}{"windows"},
+					Actual:   "linux",
+				},
+			}, explanations[1].FailedConditions)
+
+			assert.Equal(GinkgoT(), "deny", explanations[2].Effect)
+			assert.True(GinkgoT(), explanations[2].Matched)
+		})
+
+		It("error", func() {
+			explanations := ExplainPolicies(c, []types.AuthPolicy{willErrorPolicy})
+			assert.False(GinkgoT(), explanations[0].Matched)
+			assert.NotEmpty(GinkgoT(), explanations[0].Error)
+		})
+
+		It("ctx.Action.WithoutResourceType", func() {
+			c.Action.FillAttributes(1, 1, []types.ActionResourceType{})
+			explanations := ExplainPolicies(c, []types.AuthPolicy{willNotPassPolicy})
+			assert.True(GinkgoT(), explanations[0].Matched)
 		})
 	})
 
@@ -342,6 +438,134 @@ var _ = Describe("Evaluation", func() {
 			assert.Empty(GinkgoT(), ps)
 			assert.Empty(GinkgoT(), policyIDs)
 		})
+
+		It("ok, one pass, one deny", func() {
+			policies := []types.AuthPolicy{
+				willPassPolicy,
+				willDenyPolicy,
+			}
+
+			ps, policyIDs, err := PartialEvalPolicies(c, policies)
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), ps)
+			assert.Empty(GinkgoT(), policyIDs)
+		})
+
+		It("ok, one pass, one deny not match", func() {
+			policies := []types.AuthPolicy{
+				willPassPolicy,
+				willNotDenyPolicy,
+			}
+
+			ps, policyIDs, err := PartialEvalPolicies(c, policies)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), ps, 1)
+			assert.Equal(GinkgoT(), []int64{1}, policyIDs)
+		})
+
+		It("ok, subject attributes resolved", func() {
+			r := request.NewRequest()
+			r.System = "iam"
+			r.Action.Attribute.SetResourceTypes([]types.ActionResourceType{{System: "iam", Type: "job"}})
+			r.Subject.Attribute.SetAttributes(map[string]interface{}{"employment_type": "contractor"})
+			ctx := evalctx.NewEvalContext(r)
+
+			contractorPolicy := types.AuthPolicy{
+				ID:         4,
+				Expression: `{"StringEquals": {"iam._bk_iam_subject_.employment_type": ["contractor"]}}`,
+			}
+			ps, policyIDs, err := PartialEvalPolicies(ctx, []types.AuthPolicy{contractorPolicy})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []condition.Condition{condition.NewAnyCondition()}, ps)
+			assert.Equal(GinkgoT(), []int64{4}, policyIDs)
+
+			employeePolicy := types.AuthPolicy{
+				ID:         5,
+				Expression: `{"StringEquals": {"iam._bk_iam_subject_.employment_type": ["employee"]}}`,
+			}
+			ps, policyIDs, err = PartialEvalPolicies(ctx, []types.AuthPolicy{employeePolicy})
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), ps)
+			assert.Empty(GinkgoT(), policyIDs)
+		})
+	})
+
+	Describe("PartialEvalPoliciesWithAllowConditions", func() {
+		It("ok, allow conditions without policies", func() {
+			ps, policyIDs, err := PartialEvalPoliciesWithAllowConditions(
+				c, []types.AuthPolicy{willNotPassPolicy}, []condition.Condition{condition.NewAnyCondition()},
+			)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []condition.Condition{condition.NewAnyCondition()}, ps)
+			assert.Empty(GinkgoT(), policyIDs)
+		})
+
+		It("ok, allow conditions denied", func() {
+			ps, policyIDs, err := PartialEvalPoliciesWithAllowConditions(
+				c, []types.AuthPolicy{willDenyPolicy}, []condition.Condition{condition.NewAnyCondition()},
+			)
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), ps)
+			assert.Empty(GinkgoT(), policyIDs)
+		})
+	})
+
+	Describe("MatchPolicy", func() {
+		It("match", func() {
+			isMatch, err := MatchPolicy(c, willPassPolicy, time.Now())
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), isMatch)
+		})
+
+		It("not match", func() {
+			isMatch, err := MatchPolicy(c, willNotPassPolicy, time.Now())
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), isMatch)
+		})
+
+		It("error", func() {
+			_, err := MatchPolicy(c, willErrorPolicy, time.Now())
+			assert.Error(GinkgoT(), err)
+		})
+
+		It("remained condition", func() {
+			r := request.NewRequest()
+			r.System = "iam"
+			r.Action.Attribute.SetResourceTypes([]types.ActionResourceType{{System: "iam", Type: "job"}})
+			r.Resources = []types.Resource{{System: "iam", Type: "host", ID: "1"}}
+			isMatch, err := MatchPolicy(evalctx.NewEvalContext(r), willPassPolicy, time.Now())
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), isMatch)
+		})
+	})
+
+	Describe("applyDenyConditions", func() {
+		var allowCond, denyCond condition.Condition
+		BeforeEach(func() {
+			allowCond, _ = condition.NewConditionFromPolicyCondition(map[string]map[string][]interface{}{
+				"StringEquals": {"iam.job.id": {"job1"}},
+			})
+			denyCond, _ = condition.NewConditionFromPolicyCondition(map[string]map[string][]interface{}{
+				"StringEquals": {"iam.job.id": {"job2"}},
+			})
+		})
+
+		It("allow any", func() {
+			c := applyDenyConditions(
+				[]condition.Condition{allowCond, condition.NewAnyCondition()},
+				[]condition.Condition{denyCond},
+			)
+			assert.Equal(GinkgoT(), condition.NewNotCondition(denyCond), c)
+		})
+
+		It("allow and not deny", func() {
+			c := applyDenyConditions([]condition.Condition{allowCond}, []condition.Condition{denyCond, denyCond})
+			want := condition.NewAndCondition([]condition.Condition{
+				allowCond,
+				condition.NewNotCondition(condition.NewOrCondition([]condition.Condition{denyCond, denyCond})),
+			})
+			assert.Equal(GinkgoT(), want, c)
+		})
 	})
 
 	// TODO: partialEvalPolicy
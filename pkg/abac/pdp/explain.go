@@ -0,0 +1,207 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pdp
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/abac/pdp/evalctx"
+	"iam/pkg/abac/pdp/evaluation"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service"
+	svctypes "iam/pkg/service/types"
+)
+
+// Explain 解释鉴权结果, 返回每条ABAC策略/RBAC用户组的求值详情
+// NOTE: 与Eval的流程保持一致, 但不会在命中后提前返回, 只用于排查问题, 不要用于鉴权
+func Explain(r *request.Request, withoutCache bool) (explanation types.Explanation, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDP, "Explain")
+
+	explanation = types.Explanation{
+		Subject: types.SubjectExplanation{
+			Type: r.Subject.Type,
+			ID:   r.Subject.ID,
+		},
+		Policies:   []types.PolicyExplanation{},
+		RBACGroups: []types.GroupExplanation{},
+	}
+
+	// 1. 检查subject是否被冻结
+	if cacheimpls.IsSubjectInBlackList(r.Subject.Type, r.Subject.ID) {
+		explanation.Subject.InBlackList = true
+		explanation.Reason = types.ExplainReasonSubjectInBlackList
+		return explanation, nil
+	}
+
+	// 2. PIP查询action, 并检查请求资源与action关联的类型是否匹配
+	err = fillActionDetail(r)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return explanation, ErrInvalidAction
+		}
+		return explanation, errorWrapf(err, "Fetch action detail action=`%+v` fail", r.Action)
+	}
+
+	if !r.ValidateActionResource() {
+		return explanation, errorWrapf(ErrInvalidActionResource,
+			"ValidateActionResource systemID=`%s`, actionID=`%s`, resources=`%+v`, environment=`%+v` fail",
+			r.System, r.Action.ID, r.Resources, r.Environment)
+	}
+
+	// 3. PIP查询subject相关的属性
+	err = fillSubjectDepartments(r)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			explanation.Reason = types.ExplainReasonSubjectNotExists
+			return explanation, nil
+		}
+		return explanation, errorWrapf(err, "request fillSubjectDetail subject=`%+v`", r.Subject)
+	}
+	err = fillSubjectAttributes(r)
+	if err != nil {
+		return explanation, errorWrapf(err, "request fillSubjectAttributes subject=`%+v`", r.Subject)
+	}
+	explanation.Subject.Exists = true
+
+	// 4. 查询关联的group pks
+	abacGroupPKs, rbacGroupPKs, err := getEffectAuthTypeGroupPKs(r.System, r.Subject, r.Action)
+	if err != nil {
+		return explanation, errorWrapf(err,
+			"GetEffectAuthTypeGroupPKs systemID=`%s`, subject=`%+v`, action=`%+v` fail",
+			r.System, r.Subject, r.Action)
+	}
+	explanation.Subject.EffectGroupCount = len(abacGroupPKs) + len(rbacGroupPKs)
+
+	// 5. RBAC用户组是否有资源实例的授权
+	if len(rbacGroupPKs) > 0 {
+		explanation.RBACGroups, err = rbacExplain(r.System, r.Action, r.Resources, rbacGroupPKs, withoutCache)
+		if err != nil {
+			return explanation, errorWrapf(err, "rbacExplain systemID=`%s`, actionID=`%s`, groupPKs=`%v` fail",
+				r.System, r.Action.ID, rbacGroupPKs)
+		}
+	}
+
+	// 6. ABAC策略逐条求值
+	policies, err := queryPolicies(r.System, r.Subject, r.Action, abacGroupPKs, false, withoutCache, nil)
+	if err != nil && !errors.Is(err, ErrNoPolicies) {
+		return explanation, errorWrapf(err, "queryPolicies system=`%s`, subject=`%+v`, action=`%+v` fail",
+			r.System, r.Subject, r.Action)
+	}
+	if len(policies) > 0 {
+		explanation.Policies = evaluation.ExplainPolicies(evalctx.NewEvalContext(r), policies)
+	}
+
+	// 7. 已过期的策略, 不参与求值, 只用于展示
+	expiredPolicies, err := listExpiredPolicies(r.Subject, r.Action, abacGroupPKs)
+	if err != nil {
+		return explanation, errorWrapf(err, "listExpiredPolicies subject=`%+v`, action=`%+v` fail",
+			r.Subject, r.Action)
+	}
+	explanation.Policies = append(explanation.Policies, expiredPolicies...)
+
+	fillExplanationResult(&explanation)
+
+	// 8. 自身没有权限且没有被deny时, 委托的权限
+	if !explanation.Allowed && explanation.Reason != types.ExplainReasonDeniedByPolicy {
+		isPass, _, err := evalDelegations(r, withoutCache)
+		if err != nil {
+			return explanation, errorWrapf(err, "evalDelegations subject=`%+v` fail", r.Subject)
+		}
+		if isPass {
+			explanation.Allowed = true
+			explanation.Reason = types.ExplainReasonAllowedByDelegation
+		}
+	}
+	return explanation, nil
+}
+
+// listExpiredPolicies 查询subject及其用户组已过期的策略
+func listExpiredPolicies(
+	subject types.Subject,
+	action types.Action,
+	effectGroupPKs []int64,
+) ([]types.PolicyExplanation, error) {
+	subjectPK, err := subject.Attribute.GetPK()
+	if err != nil {
+		return nil, err
+	}
+
+	actionPK, err := action.Attribute.GetPK()
+	if err != nil {
+		return nil, err
+	}
+
+	subjectPKs := make([]int64, 0, len(effectGroupPKs)+1)
+	subjectPKs = append(subjectPKs, subjectPK)
+	subjectPKs = append(subjectPKs, effectGroupPKs...)
+
+	svc := service.NewPolicyService()
+	policies, err := svc.ListExpiredAuthBySubjectAction(subjectPKs, actionPK)
+	if err != nil {
+		return nil, err
+	}
+
+	explanations := make([]types.PolicyExplanation, 0, len(policies))
+	for _, p := range policies {
+		explanations = append(explanations, types.PolicyExplanation{
+			ID:        p.PK,
+			Effect:    svctypes.ConvertToPolicyEffectStr(p.Effect),
+			ExpiredAt: p.ExpiredAt,
+			Expired:   true,
+		})
+	}
+	return explanations, nil
+}
+
+// fillExplanationResult 根据策略与用户组的求值详情, 计算最终的鉴权结果与原因, 与Eval的deny优先规则保持一致
+func fillExplanationResult(explanation *types.Explanation) {
+	var allowedByPolicy, hasEffectPolicy bool
+	for _, p := range explanation.Policies {
+		if p.Expired {
+			continue
+		}
+		hasEffectPolicy = true
+
+		if !p.Matched {
+			continue
+		}
+
+		if p.Effect == svctypes.PolicyEffectDenyStr {
+			explanation.Allowed = false
+			explanation.Reason = types.ExplainReasonDeniedByPolicy
+			return
+		}
+		allowedByPolicy = true
+	}
+
+	for _, g := range explanation.RBACGroups {
+		if g.Authorized {
+			explanation.Allowed = true
+			explanation.Reason = types.ExplainReasonAllowedByRBACGroup
+			return
+		}
+	}
+
+	switch {
+	case allowedByPolicy:
+		explanation.Allowed = true
+		explanation.Reason = types.ExplainReasonAllowedByPolicy
+	case explanation.Subject.EffectGroupCount == 0 && !hasEffectPolicy:
+		explanation.Reason = types.ExplainReasonNoGroupsNorPolicies
+	default:
+		explanation.Reason = types.ExplainReasonNoPolicyMatched
+	}
+}
@@ -0,0 +1,280 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pdp
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/agiledragon/gomonkey/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/pdp/evalctx"
+	"iam/pkg/abac/pdp/evaluation"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/logging/debug"
+)
+
+var _ = Describe("Explain", func() {
+	Describe("Explain", func() {
+		var req *request.Request
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			req = &request.Request{
+				System: "test",
+				Subject: types.Subject{
+					Type: "user",
+					ID:   "admin",
+				},
+				Resources: []types.Resource{{
+					System: "test",
+				}},
+			}
+
+			patches = gomonkey.NewPatches()
+			patches.ApplyFunc(listDelegatorSubjects, func(
+				systemID string, subject types.Subject, action types.Action,
+			) ([]types.Subject, error) {
+				return nil, nil
+			})
+			patches.ApplyFunc(cacheimpls.IsSubjectInBlackList, func(_type, id string) bool {
+				return false
+			})
+		})
+		AfterEach(func() {
+			patches.Reset()
+		})
+
+		patchUntilGroups := func(abacGroupPKs, rbacGroupPKs []int64) {
+			patches.ApplyFunc(fillActionDetail, func(req *request.Request) error {
+				req.Action = types.NewAction()
+				req.Action.FillAttributes(123, 1, nil)
+				return nil
+			})
+			patches.ApplyMethod(reflect.TypeOf(req), "ValidateActionResource",
+				func(_ *request.Request) bool {
+					return true
+				})
+			patches.ApplyFunc(fillSubjectDepartments, func(req *request.Request) error {
+				return nil
+			})
+			patches.ApplyFunc(getEffectAuthTypeGroupPKs, func(
+				system string,
+				subject types.Subject,
+				action types.Action,
+			) ([]int64, []int64, error) {
+				return abacGroupPKs, rbacGroupPKs, nil
+			})
+		}
+
+		patchPolicies := func(policies []types.AuthPolicy, expired []types.PolicyExplanation) {
+			patches.ApplyFunc(queryPolicies, func(system string,
+				subject types.Subject,
+				action types.Action,
+				effectGroupPKs []int64,
+				withRbacPolicies bool,
+				withoutCache bool,
+				entry *debug.Entry,
+			) ([]types.AuthPolicy, error) {
+				if len(policies) == 0 {
+					return nil, ErrNoPolicies
+				}
+				return policies, nil
+			})
+			patches.ApplyFunc(listExpiredPolicies, func(
+				subject types.Subject,
+				action types.Action,
+				effectGroupPKs []int64,
+			) ([]types.PolicyExplanation, error) {
+				return expired, nil
+			})
+		}
+
+		It("subject in blacklist", func() {
+			patches.ApplyFunc(cacheimpls.IsSubjectInBlackList, func(_type, id string) bool {
+				return true
+			})
+
+			explanation, err := Explain(req, false)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), explanation.Allowed)
+			assert.True(GinkgoT(), explanation.Subject.InBlackList)
+			assert.Equal(GinkgoT(), types.ExplainReasonSubjectInBlackList, explanation.Reason)
+		})
+
+		It("invalid action", func() {
+			patches.ApplyFunc(fillActionDetail, func(req *request.Request) error {
+				return sql.ErrNoRows
+			})
+
+			_, err := Explain(req, false)
+			assert.ErrorIs(GinkgoT(), err, ErrInvalidAction)
+		})
+
+		It("ValidateActionResource fail", func() {
+			patches.ApplyFunc(fillActionDetail, func(req *request.Request) error {
+				return nil
+			})
+			patches.ApplyMethod(reflect.TypeOf(req), "ValidateActionResource",
+				func(_ *request.Request) bool {
+					return false
+				})
+
+			_, err := Explain(req, false)
+			assert.ErrorIs(GinkgoT(), err, ErrInvalidActionResource)
+		})
+
+		It("subject not exists", func() {
+			patchUntilGroups(nil, nil)
+			patches.ApplyFunc(fillSubjectDepartments, func(req *request.Request) error {
+				return sql.ErrNoRows
+			})
+
+			explanation, err := Explain(req, false)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), explanation.Subject.Exists)
+			assert.Equal(GinkgoT(), types.ExplainReasonSubjectNotExists, explanation.Reason)
+		})
+
+		It("no groups nor policies", func() {
+			patchUntilGroups(nil, nil)
+			patchPolicies(nil, []types.PolicyExplanation{{ID: 9, Expired: true}})
+
+			explanation, err := Explain(req, false)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), explanation.Allowed)
+			assert.Equal(GinkgoT(), 0, explanation.Subject.EffectGroupCount)
+			assert.Len(GinkgoT(), explanation.Policies, 1)
+			assert.Equal(GinkgoT(), types.ExplainReasonNoGroupsNorPolicies, explanation.Reason)
+		})
+
+		It("allowed by delegation", func() {
+			patchUntilGroups(nil, nil)
+			patchPolicies(nil, nil)
+			patches.ApplyFunc(evalDelegations, func(r *request.Request, withoutCache bool) (bool, int64, error) {
+				return true, 2, nil
+			})
+
+			explanation, err := Explain(req, false)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), explanation.Allowed)
+			assert.Equal(GinkgoT(), types.ExplainReasonAllowedByDelegation, explanation.Reason)
+		})
+
+		It("no policy matched", func() {
+			patchUntilGroups([]int64{1}, nil)
+			patchPolicies([]types.AuthPolicy{{ID: 1}}, nil)
+			patches.ApplyFunc(evaluation.ExplainPolicies, func(
+				ctx *evalctx.EvalContext, policies []types.AuthPolicy,
+			) []types.PolicyExplanation {
+				return []types.PolicyExplanation{{
+					ID:     1,
+					Effect: "allow",
+					FailedConditions: []types.ConditionExplanation{{
+						Operator: "StringEquals",
+						Key:      "test.host.id",
+						Expected: []interface{}{"1"},
+						Actual:   "2",
+					}},
+				}}
+			})
+
+			explanation, err := Explain(req, false)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), explanation.Allowed)
+			assert.Equal(GinkgoT(), 1, explanation.Subject.EffectGroupCount)
+			assert.Equal(GinkgoT(), types.ExplainReasonNoPolicyMatched, explanation.Reason)
+			assert.Len(GinkgoT(), explanation.Policies[0].FailedConditions, 1)
+		})
+
+		It("denied by policy, even rbac group authorized", func() {
+			patchUntilGroups([]int64{1}, []int64{2})
+			patchPolicies([]types.AuthPolicy{{ID: 1}, {ID: 2}}, nil)
+			patches.ApplyFunc(rbacExplain, func(
+				system string,
+				action types.Action,
+				resources []types.Resource,
+				effectGroupPKs []int64,
+				withoutCache bool,
+			) ([]types.GroupExplanation, error) {
+				return []types.GroupExplanation{{PK: 2, Authorized: true}}, nil
+			})
+			patches.ApplyFunc(evaluation.ExplainPolicies, func(
+				ctx *evalctx.EvalContext, policies []types.AuthPolicy,
+			) []types.PolicyExplanation {
+				return []types.PolicyExplanation{
+					{ID: 1, Effect: "allow", Matched: true},
+					{ID: 2, Effect: "deny", Matched: true},
+				}
+			})
+
+			explanation, err := Explain(req, false)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), explanation.Allowed)
+			assert.Equal(GinkgoT(), types.ExplainReasonDeniedByPolicy, explanation.Reason)
+		})
+
+		It("allowed by rbac group", func() {
+			patchUntilGroups(nil, []int64{2})
+			patchPolicies(nil, nil)
+			patches.ApplyFunc(rbacExplain, func(
+				system string,
+				action types.Action,
+				resources []types.Resource,
+				effectGroupPKs []int64,
+				withoutCache bool,
+			) ([]types.GroupExplanation, error) {
+				return []types.GroupExplanation{{PK: 2, Authorized: true}}, nil
+			})
+
+			explanation, err := Explain(req, false)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), explanation.Allowed)
+			assert.Equal(GinkgoT(), types.ExplainReasonAllowedByRBACGroup, explanation.Reason)
+		})
+
+		It("rbacExplain error", func() {
+			patchUntilGroups(nil, []int64{2})
+			patches.ApplyFunc(rbacExplain, func(
+				system string,
+				action types.Action,
+				resources []types.Resource,
+				effectGroupPKs []int64,
+				withoutCache bool,
+			) ([]types.GroupExplanation, error) {
+				return nil, errors.New("rbac fail")
+			})
+
+			_, err := Explain(req, false)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "rbac fail")
+		})
+
+		It("allowed by policy", func() {
+			patchUntilGroups([]int64{1}, nil)
+			patchPolicies([]types.AuthPolicy{{ID: 1}}, nil)
+			patches.ApplyFunc(evaluation.ExplainPolicies, func(
+				ctx *evalctx.EvalContext, policies []types.AuthPolicy,
+			) []types.PolicyExplanation {
+				return []types.PolicyExplanation{{ID: 1, Effect: "allow", Matched: true}}
+			})
+
+			explanation, err := Explain(req, false)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), explanation.Allowed)
+			assert.Equal(GinkgoT(), types.ExplainReasonAllowedByPolicy, explanation.Reason)
+		})
+	})
+})
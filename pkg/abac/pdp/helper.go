@@ -136,9 +136,35 @@ func queryAndPartialEvalConditions(
 		err = errorWrapf(err, "request fillSubjectDetail subject=`%+v`", r.Subject)
 		return nil, err
 	}
+	err = fillSubjectAttributes(r)
+	if err != nil {
+		err = errorWrapf(err, "request fillSubjectAttributes subject=`%+v`", r.Subject)
+		return nil, err
+	}
 	debug.WithValue(entry, "subject", r.Subject)
 
-	// 3. 查询关联的group pks
+	// 4. 委托的权限, 作为subject额外的allow条件
+	debug.AddStep(entry, "Query Delegated Conditions")
+	delegatedConditions, err := queryDelegatedConditions(r, withoutCache)
+	if err != nil {
+		err = errorWrapf(err, "queryDelegatedConditions subject=`%+v`, action=`%+v` fail", r.Subject, r.Action)
+		return nil, err
+	}
+
+	return partialEvalSubjectConditions(r, entry, delegatedConditions, withoutCache)
+}
+
+// partialEvalSubjectConditions 查询subject自身相关的Policy并部分求值
+// allowConditions: 额外的allow残留条件, 同样受subject的deny策略限制
+func partialEvalSubjectConditions(
+	r *request.Request,
+	entry *debug.Entry,
+	allowConditions []condition.Condition,
+	withoutCache bool,
+) ([]condition.Condition, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDP, "partialEvalSubjectConditions")
+
+	// 1. 查询关联的group pks
 	debug.AddStep(entry, "Get Effect AuthType Group PKs")
 	abacGroupPKs, rbacGroupPKs, err := getEffectAuthTypeGroupPKs(r.System, r.Subject, r.Action)
 	if err != nil {
@@ -154,12 +180,12 @@ func queryAndPartialEvalConditions(
 	debug.WithValue(entry, "abacGroupPks", abacGroupPKs)
 	debug.WithValue(entry, "rbacGroupPks", rbacGroupPKs)
 
-	// 4. PRP查询subject-action相关的policies
+	// 2. PRP查询subject-action相关的policies
 	debug.AddStep(entry, "Query Policies")
 	policies, err := queryPolicies(r.System, r.Subject, r.Action, abacGroupPKs, true, withoutCache, entry)
 	if err != nil {
 		if errors.Is(err, ErrNoPolicies) {
-			return nil, nil
+			return allowConditions, nil
 		}
 
 		err = errorWrapf(err, "queryPolicies system=`%s`, subject=`%+v`, action=`%+v`, withoutCache=`%t` fail",
@@ -170,7 +196,7 @@ func queryAndPartialEvalConditions(
 	debug.WithValue(entry, "policies", policies)
 	debug.WithUnknownEvalPolicies(entry, policies)
 
-	// 5. eval policies
+	// 3. eval policies
 	// 这里需要返回剩下的policies
 	debug.AddStep(entry, "Filter policies by eval resources")
 
@@ -188,10 +214,12 @@ func queryAndPartialEvalConditions(
 		envs, _ := evalctx.GenTimeEnvsFromCache(DefaultTz, time.Now())
 		debug.WithValue(entry, "env", envs)
 	}
-	conditions, passedPoliciesIDs, err := evaluation.PartialEvalPolicies(evalctx.NewEvalContext(r), policies)
+	ctx := evalctx.NewEvalContext(r)
+	conditions, passedPoliciesIDs, err := evaluation.PartialEvalPoliciesWithAllowConditions(ctx, policies, allowConditions)
 	if len(conditions) == 0 {
 		debug.WithNoPassEvalPolicies(entry, policies)
 	}
+	debug.WithErrorEvalPolicyIDs(entry, ctx.FailedPolicyIDs())
 	debug.WithPassEvalPolicyIDs(entry, passedPoliciesIDs)
 
 	return conditions, err
@@ -210,13 +238,49 @@ func fillSubjectDepartments(r *request.Request) error {
 		return err
 	}
 
-	departments, err := pip.GetSubjectDepartmentPKs(pk)
+	departments, ancestorDepartments, err := pip.GetSubjectDepartmentPKs(pk)
 	if err != nil {
 		err = errorWrapf(err, "GetSubjectDetail pk=`%d` fail", pk)
 		return err
 	}
 
-	r.Subject.FillAttributes(pk, departments)
+	r.Subject.FillAttributes(pk, departments, ancestorDepartments)
+	return nil
+}
+
+// fillSubjectAttributes 填充操作声明可用的subject自定义属性, 需要在fillSubjectDepartments之后调用
+func fillSubjectAttributes(r *request.Request) error {
+	// NOTE: 只有操作声明了可用的subject属性, 才需要查询
+	if !r.Action.Attribute.Has(types.SubjectAttrTypeName) {
+		return nil
+	}
+
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Request", "fillSubjectAttributes")
+
+	attrTypes, err := r.Action.Attribute.GetSubjectAttributeTypes()
+	if err != nil {
+		return errorWrapf(err, "GetSubjectAttributeTypes action=`%+v` fail", r.Action)
+	}
+
+	pk, err := r.Subject.Attribute.GetPK()
+	if err != nil {
+		return errorWrapf(err, "GetPK subject=`%+v` fail", r.Subject)
+	}
+
+	subjectAttrs, err := pip.GetSubjectAttributes(pk)
+	if err != nil {
+		return errorWrapf(err, "GetSubjectAttributes pk=`%d` fail", pk)
+	}
+
+	// 只保留操作声明的属性, 未声明的属性不能在条件中使用
+	attrs := make(map[string]interface{}, len(attrTypes))
+	for _, name := range attrTypes {
+		if value, ok := subjectAttrs[name]; ok {
+			attrs[name] = value
+		}
+	}
+
+	r.Subject.Attribute.SetAttributes(attrs)
 	return nil
 }
 
@@ -234,6 +298,25 @@ func fillActionDetail(r *request.Request) error {
 	}
 
 	r.Action.FillAttributes(pk, authType, actionResourceTypes)
+
+	// NOTE: 只有请求中带了环境属性, 才需要查询action关联的环境属性类型用于校验
+	if r.HasEnvironment() {
+		envTypes, err := pip.GetActionEnvironmentTypes(system, id)
+		if err != nil {
+			err = errorWrapf(err, "GetActionEnvironmentTypes system=`%s`, id=`%s` fail", system, id)
+			return err
+		}
+		r.Action.Attribute.SetEnvironmentTypes(envTypes)
+	}
+
+	subjectAttrTypes, err := pip.GetActionSubjectAttributes(system, id)
+	if err != nil {
+		err = errorWrapf(err, "GetActionSubjectAttributes system=`%s`, id=`%s` fail", system, id)
+		return err
+	}
+	if len(subjectAttrTypes) > 0 {
+		r.Action.Attribute.SetSubjectAttributeTypes(subjectAttrTypes)
+	}
 	return nil
 }
 
@@ -259,9 +342,9 @@ func getEffectAuthTypeGroupPKs(
 		if err != nil {
 			return nil, nil, err
 		}
-
-		return abacGroupPKs, rbacGroupPKs, nil
+	} else {
+		abacGroupPKs = groupPKs
 	}
 
-	return groupPKs, nil, nil
+	return abacGroupPKs, rbacGroupPKs, nil
 }
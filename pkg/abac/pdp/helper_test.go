@@ -116,6 +116,11 @@ var _ = Describe("Helper", func() {
 			}
 
 			patches = gomonkey.NewPatches()
+			patches.ApplyFunc(listDelegatorSubjects, func(
+				systemID string, subject types.Subject, action types.Action,
+			) ([]types.Subject, error) {
+				return nil, nil
+			})
 		})
 		AfterEach(func() {
 			ctl.Finish()
@@ -235,8 +240,8 @@ var _ = Describe("Helper", func() {
 			) (policies []types.AuthPolicy, err error) {
 				return []types.AuthPolicy{{}}, nil
 			})
-			patches.ApplyFunc(evaluation.PartialEvalPolicies, func(ctx *evalctx.EvalContext,
-				policie []types.AuthPolicy,
+			patches.ApplyFunc(evaluation.PartialEvalPoliciesWithAllowConditions, func(ctx *evalctx.EvalContext,
+				policies []types.AuthPolicy, allowConditions []condition.Condition,
 			) ([]condition.Condition, []int64, error) {
 				return nil, nil, errors.New("filter error")
 			})
@@ -277,8 +282,8 @@ var _ = Describe("Helper", func() {
 			) (policies []types.AuthPolicy, err error) {
 				return []types.AuthPolicy{{}}, nil
 			})
-			patches.ApplyFunc(evaluation.PartialEvalPolicies, func(ctx *evalctx.EvalContext,
-				policies []types.AuthPolicy,
+			patches.ApplyFunc(evaluation.PartialEvalPoliciesWithAllowConditions, func(ctx *evalctx.EvalContext,
+				policies []types.AuthPolicy, allowConditions []condition.Condition,
 			) ([]condition.Condition, []int64, error) {
 				return []condition.Condition{}, []int64{}, nil
 			})
@@ -318,8 +323,8 @@ var _ = Describe("Helper", func() {
 			) (policies []types.AuthPolicy, err error) {
 				return []types.AuthPolicy{{}}, nil
 			})
-			patches.ApplyFunc(evaluation.PartialEvalPolicies, func(ctx *evalctx.EvalContext,
-				policies []types.AuthPolicy,
+			patches.ApplyFunc(evaluation.PartialEvalPoliciesWithAllowConditions, func(ctx *evalctx.EvalContext,
+				policies []types.AuthPolicy, allowConditions []condition.Condition,
 			) ([]condition.Condition, []int64, error) {
 				return []condition.Condition{
 					condition.NewAnyCondition(),
@@ -358,8 +363,8 @@ var _ = Describe("Helper", func() {
 			patches = gomonkey.ApplyFunc(pip.GetSubjectPK, func(_type, id string) (pk int64, err error) {
 				return 123, nil
 			})
-			patches.ApplyFunc(pip.GetSubjectDepartmentPKs, func(pk int64) ([]int64, error) {
-				return nil, errors.New("get GetSubjectDepartmentPKs fail")
+			patches.ApplyFunc(pip.GetSubjectDepartmentPKs, func(pk int64) ([]int64, []int64, error) {
+				return nil, nil, errors.New("get GetSubjectDepartmentPKs fail")
 			})
 
 			err := fillSubjectDepartments(r)
@@ -372,8 +377,8 @@ var _ = Describe("Helper", func() {
 				return 123, nil
 			})
 
-			patches.ApplyFunc(pip.GetSubjectDepartmentPKs, func(pk int64) ([]int64, error) {
-				return []int64{1, 2, 3}, nil
+			patches.ApplyFunc(pip.GetSubjectDepartmentPKs, func(pk int64) ([]int64, []int64, error) {
+				return []int64{1, 2, 3}, []int64{4}, nil
 			})
 
 			err := fillSubjectDepartments(r)
@@ -414,9 +419,91 @@ var _ = Describe("Helper", func() {
 				) {
 					return 123, 1, []types.ActionResourceType{}, nil
 				})
+			patches.ApplyFunc(pip.GetActionSubjectAttributes, func(system, id string) ([]string, error) {
+				return nil, nil
+			})
+
+			err := fillActionDetail(r)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), r.Action.Attribute.Has(types.SubjectAttrTypeName))
+		})
+
+		It("GetActionSubjectAttributes fail", func() {
+			patches = gomonkey.ApplyFunc(pip.GetActionDetail,
+				func(system, id string) (pk int64, authType int64,
+					arts []types.ActionResourceType, err error,
+				) {
+					return 123, 1, []types.ActionResourceType{}, nil
+				})
+			patches.ApplyFunc(pip.GetActionSubjectAttributes, func(system, id string) ([]string, error) {
+				return nil, errors.New("get GetActionSubjectAttributes fail")
+			})
+
+			err := fillActionDetail(r)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "get GetActionSubjectAttributes fail")
+		})
+
+		It("ok, with subject attributes", func() {
+			patches = gomonkey.ApplyFunc(pip.GetActionDetail,
+				func(system, id string) (pk int64, authType int64,
+					arts []types.ActionResourceType, err error,
+				) {
+					return 123, 1, []types.ActionResourceType{}, nil
+				})
+			patches.ApplyFunc(pip.GetActionSubjectAttributes, func(system, id string) ([]string, error) {
+				return []string{"level"}, nil
+			})
 
 			err := fillActionDetail(r)
 			assert.NoError(GinkgoT(), err)
+			attrTypes, err := r.Action.Attribute.GetSubjectAttributeTypes()
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []string{"level"}, attrTypes)
+		})
+	})
+
+	Describe("fillSubjectAttributes", func() {
+		var r *request.Request
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			r = request.NewRequest()
+			r.Subject.Attribute.SetPK(123)
+		})
+		AfterEach(func() {
+			if patches != nil {
+				patches.Reset()
+			}
+		})
+
+		It("action not declared", func() {
+			err := fillSubjectAttributes(r)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), r.Subject.Attribute.Has(types.SubjectAttrsAttrName))
+		})
+
+		It("pip.GetSubjectAttributes fail", func() {
+			r.Action.Attribute.SetSubjectAttributeTypes([]string{"level"})
+			patches = gomonkey.ApplyFunc(pip.GetSubjectAttributes, func(pk int64) (map[string]interface{}, error) {
+				return nil, errors.New("get subject attributes fail")
+			})
+
+			err := fillSubjectAttributes(r)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "get subject attributes fail")
+		})
+
+		It("ok", func() {
+			r.Action.Attribute.SetSubjectAttributeTypes([]string{"level", "location"})
+			patches = gomonkey.ApplyFunc(pip.GetSubjectAttributes, func(pk int64) (map[string]interface{}, error) {
+				return map[string]interface{}{"level": float64(3), "employment_type": "contractor"}, nil
+			})
+
+			err := fillSubjectAttributes(r)
+			assert.NoError(GinkgoT(), err)
+			attrs, ok := r.Subject.Attribute.GetAttributes()
+			assert.True(GinkgoT(), ok)
+			assert.Equal(GinkgoT(), map[string]interface{}{"level": float64(3)}, attrs)
 		})
 	})
 })
@@ -11,7 +11,6 @@
 package pdp
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 
@@ -23,6 +22,7 @@ import (
 	"iam/pkg/cacheimpls"
 	"iam/pkg/logging/debug"
 	"iam/pkg/service"
+	svctypes "iam/pkg/service/types"
 )
 
 func rbacEval(
@@ -49,8 +49,8 @@ func rbacEval(
 	}
 	debug.WithValue(entry, "actionResourceTypes", actionResourceTypes)
 
-	// 检验资源类型是否匹配
-	err = validResourceType(resources, actionResourceTypes)
+	// 检验资源类型是否匹配, 并按操作关联资源类型的顺序排列资源实例
+	resources, err = validResourceType(resources, actionResourceTypes)
 	if err != nil {
 		err = errorWrapf(
 			err,
@@ -63,18 +63,18 @@ func rbacEval(
 
 	// 2. 解析资源实例的以及属性, 返回出可能被授权的资源实例节点
 	debug.AddStep(entry, "Parse Resource Nodes")
-	// 从resources中解析出用于rbac鉴权的资源实例节点 NOTE: 支持rbac鉴权的资源类型只能有一个
-	resourceNodes, err := abac.ParseResourceNode(resources[0])
+	// 从resources中解析出用于rbac鉴权的资源实例节点, 每个资源类型一组
+	resourceNodesList, err := abac.ParseResourceNodes(resources)
 	if err != nil {
 		err = errorWrapf(
 			err,
-			"parseResourceNode fail, resource=`%+v` actionResourceType=`%+v`",
-			resources[0],
-			actionResourceTypes[0],
+			"parseResourceNodes fail, resources=`%+v` actionResourceTypes=`%+v`",
+			resources,
+			actionResourceTypes,
 		)
 		return
 	}
-	debug.WithValue(entry, "resourceNodes", resourceNodes)
+	debug.WithValue(entry, "resourceNodes", resourceNodesList)
 
 	// 3. 拿action的PK
 	debug.AddStep(entry, "Get Action PK")
@@ -86,72 +86,37 @@ func rbacEval(
 	debug.WithValue(entry, "actionPK", actionPK)
 
 	debug.AddStep(entry, "Get Action Resource Type PK")
-	actionResourceTypePK, err := cacheimpls.GetLocalResourceTypePK(
-		actionResourceTypes[0].System,
-		actionResourceTypes[0].Type,
-	)
+	actionResourceTypePKs, err := getActionResourceTypePKs(actionResourceTypes)
 	if err != nil {
-		err = errorWrapf(
-			err,
-			"cacheimpls.GetLocalResourceTypePK fail, actionResourceType=`%+v`",
-			actionResourceTypes[0],
-		)
+		err = errorWrapf(err, "getActionResourceTypePKs fail, actionResourceTypes=`%+v`", actionResourceTypes)
 		return
 	}
-	debug.WithValue(entry, "actionResourceTypePK", actionResourceTypePK)
+	debug.WithValue(entry, "actionResourceTypePKs", actionResourceTypePKs)
 
-	// 4. 遍历查询资源实例节点授权的groupPKs
+	// 4. 遍历查询第一个资源实例节点授权的用户组, 其它资源实例在授权的资源实例组合中匹配
 	debug.AddStep(entry, "Get Resource ActionAuthorized Group PKs")
 	effectGroupPKSet := set.NewInt64SetWithValues(effectGroupPKs)
-	for i, resourceNode := range resourceNodes {
-		var groupPKs []int64
-		if withoutCache {
-			svc := service.NewGroupResourcePolicyService()
-			var actionGroupPKs map[int64][]int64
-			actionGroupPKs, err = svc.GetAuthorizedActionGroupMap(
-				system, actionResourceTypePK, resourceNode.TypePK, resourceNode.ID,
-			)
-			if err != nil {
-				err = errorWrapf(
-					err,
-					"svc.GetAuthorizedActionGroupMap fail, system=`%s` action=`%+v` resource=`%+v`",
-					system,
-					action,
-					resourceNode,
-				)
-				return
-			}
-
-			groupPKs = actionGroupPKs[actionPK]
-		} else {
-			groupPKs, err = cacheimpls.GetResourceActionAuthorizedGroupPKs(
-				system,
-				actionPK,
-				actionResourceTypePK,
-				resourceNode.TypePK,
-				resourceNode.ID,
-			)
-			if err != nil {
-				err = errorWrapf(
-					err,
-					"GetResourceActionAuthorizedGroupPKs fail, system=`%s` action=`%+v` resource=`%+v`",
-					system,
-					action,
-					resourceNode,
-				)
-				return
-			}
+	relatedNodeSets := newResourceNodeSets(resourceNodesList[1:])
+	for i, resourceNode := range resourceNodesList[0] {
+		var groups []svctypes.AuthorizedGroup
+		groups, err = getResourceNodeAuthorizedGroups(
+			system, action, actionPK, actionResourceTypePKs[0], resourceNode, withoutCache,
+		)
+		if err != nil {
+			err = errorWrapf(err, "getResourceNodeAuthorizedGroups fail")
+			return
 		}
 
 		debug.WithValue(entry, "loop"+strconv.Itoa(i), map[string]interface{}{
 			"resourceNode": resourceNode,
-			"groupPKs":     groupPKs,
+			"groups":       groups,
 		})
 
 		// 5. 判断资源实例授权的用户组是否在effectGroupPKs中
-		for _, groupPK := range groupPKs {
-			if effectGroupPKSet.Has(groupPK) {
-				debug.WithValue(entry, "passGroupPK", groupPK)
+		for _, group := range groups {
+			if effectGroupPKSet.Has(group.GroupPK) &&
+				matchRelatedResources(group.RelatedResources, actionResourceTypePKs[1:], relatedNodeSets) {
+				debug.WithValue(entry, "passGroupPK", group.GroupPK)
 				return true, nil
 			}
 		}
@@ -159,21 +124,228 @@ func rbacEval(
 	return false, nil
 }
 
-func validResourceType(resources []types.Resource, actionResourceTypes []types.ActionResourceType) error {
-	if len(resources) != 1 || len(actionResourceTypes) != 1 {
-		// NOTE: 能做RBAC鉴权的操作的资源类型只能有一个
-		return errors.New("rbacEval only support action with one resource type")
+// rbacExplain 返回每个rbac用户组是否有资源实例的授权, 用于解释鉴权结果
+func rbacExplain(
+	system string,
+	action types.Action,
+	resources []types.Resource,
+	effectGroupPKs []int64,
+	withoutCache bool,
+) ([]types.GroupExplanation, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDP, "rbacExplain")
+
+	authorizedGroupPKSet, err := listRbacAuthorizedGroupPKs(system, action, resources, withoutCache)
+	if err != nil {
+		return nil, errorWrapf(err, "listRbacAuthorizedGroupPKs fail, resources=`%+v`", resources)
+	}
+
+	explanations := make([]types.GroupExplanation, 0, len(effectGroupPKs))
+	for _, groupPK := range effectGroupPKs {
+		explanation := types.GroupExplanation{
+			PK:         groupPK,
+			Authorized: authorizedGroupPKSet.Has(groupPK),
+		}
+
+		// NOTE: only for display, ignore the error
+		group, err := cacheimpls.GetSubjectByPK(groupPK)
+		if err == nil {
+			explanation.ID = group.ID
+		}
+
+		explanations = append(explanations, explanation)
+	}
+	return explanations, nil
+}
+
+// listRbacAuthorizedGroupPKs 查询对资源实例有授权的所有rbac用户组
+func listRbacAuthorizedGroupPKs(
+	system string,
+	action types.Action,
+	resources []types.Resource,
+	withoutCache bool,
+) (*set.Int64Set, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDP, "listRbacAuthorizedGroupPKs")
+
+	actionResourceTypes, err := action.Attribute.GetResourceTypes()
+	if err != nil {
+		return nil, errorWrapf(err, "action.Attribute.GetResourceTypes fail, action=`%+v`", action)
+	}
+
+	resources, err = validResourceType(resources, actionResourceTypes)
+	if err != nil {
+		return nil, errorWrapf(err, "validResourceType fail, resources=`%+v`, actionResourceTypes=`%+v`",
+			resources, actionResourceTypes)
+	}
+
+	resourceNodesList, err := abac.ParseResourceNodes(resources)
+	if err != nil {
+		return nil, errorWrapf(err, "parseResourceNodes fail, resources=`%+v`", resources)
+	}
+
+	actionPK, err := action.Attribute.GetPK()
+	if err != nil {
+		return nil, errorWrapf(err, "action.Attribute.GetPK fail, action=`%+v`", action)
+	}
+
+	actionResourceTypePKs, err := getActionResourceTypePKs(actionResourceTypes)
+	if err != nil {
+		return nil, errorWrapf(err, "getActionResourceTypePKs fail, actionResourceTypes=`%+v`",
+			actionResourceTypes)
+	}
+
+	authorizedGroupPKSet := set.NewInt64Set()
+	relatedNodeSets := newResourceNodeSets(resourceNodesList[1:])
+	for _, resourceNode := range resourceNodesList[0] {
+		groups, err := getResourceNodeAuthorizedGroups(
+			system, action, actionPK, actionResourceTypePKs[0], resourceNode, withoutCache,
+		)
+		if err != nil {
+			return nil, errorWrapf(err, "getResourceNodeAuthorizedGroups fail")
+		}
+
+		for _, group := range groups {
+			if matchRelatedResources(group.RelatedResources, actionResourceTypePKs[1:], relatedNodeSets) {
+				authorizedGroupPKSet.Add(group.GroupPK)
+			}
+		}
+	}
+	return authorizedGroupPKSet, nil
+}
+
+// getResourceNodeAuthorizedGroups 查询资源实例节点授权的用户组
+func getResourceNodeAuthorizedGroups(
+	system string,
+	action types.Action,
+	actionPK, actionResourceTypePK int64,
+	resourceNode types.ResourceNode,
+	withoutCache bool,
+) (groups []svctypes.AuthorizedGroup, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDP, "getResourceNodeAuthorizedGroups")
+
+	if withoutCache {
+		svc := service.NewGroupResourcePolicyService()
+		var actionGroups map[int64][]svctypes.AuthorizedGroup
+		actionGroups, err = svc.GetAuthorizedActionGroupMap(
+			system, actionResourceTypePK, resourceNode.TypePK, resourceNode.ID,
+		)
+		if err != nil {
+			err = errorWrapf(
+				err,
+				"svc.GetAuthorizedActionGroupMap fail, system=`%s` action=`%+v` resource=`%+v`",
+				system,
+				action,
+				resourceNode,
+			)
+			return
+		}
+
+		return actionGroups[actionPK], nil
 	}
 
-	resource := resources[0]
-	actionResourceType := actionResourceTypes[0]
-	if actionResourceType.System != resource.System || actionResourceType.Type != resource.Type {
-		return fmt.Errorf(
-			"resource type not match, actionResourceType=`%+v`, resource=`%+v`",
-			actionResourceType,
-			resource,
+	groups, err = cacheimpls.GetResourceActionAuthorizedGroups(
+		system,
+		actionPK,
+		actionResourceTypePK,
+		resourceNode.TypePK,
+		resourceNode.ID,
+	)
+	if err != nil {
+		err = errorWrapf(
+			err,
+			"GetResourceActionAuthorizedGroups fail, system=`%s` action=`%+v` resource=`%+v`",
+			system,
+			action,
+			resourceNode,
 		)
+		return
+	}
+	return groups, nil
+}
+
+// getActionResourceTypePKs 按顺序查询操作关联的资源类型PK
+func getActionResourceTypePKs(actionResourceTypes []types.ActionResourceType) ([]int64, error) {
+	pks := make([]int64, 0, len(actionResourceTypes))
+	for _, rt := range actionResourceTypes {
+		pk, err := cacheimpls.GetLocalResourceTypePK(rt.System, rt.Type)
+		if err != nil {
+			return nil, err
+		}
+		pks = append(pks, pk)
+	}
+	return pks, nil
+}
+
+func resourceNodeKey(resourceTypePK int64, resourceID string) string {
+	return strconv.FormatInt(resourceTypePK, 10) + ":" + resourceID
+}
+
+// newResourceNodeSets 每个资源实例的资源节点集合, 用于匹配授权的资源实例组合
+func newResourceNodeSets(resourceNodesList [][]types.ResourceNode) []*set.StringSet {
+	nodeSets := make([]*set.StringSet, 0, len(resourceNodesList))
+	for _, resourceNodes := range resourceNodesList {
+		nodeSet := set.NewStringSet()
+		for _, node := range resourceNodes {
+			nodeSet.Add(resourceNodeKey(node.TypePK, node.ID))
+		}
+		nodeSets = append(nodeSets, nodeSet)
+	}
+	return nodeSets
+}
+
+// matchRelatedResources 授权的其它资源实例, 需要与请求的资源实例按顺序一一匹配
+// NOTE: 只关联一个资源类型时, relatedResources与nodeSets都为空
+func matchRelatedResources(
+	relatedResources []svctypes.RelatedResource,
+	actionResourceTypePKs []int64,
+	nodeSets []*set.StringSet,
+) bool {
+	if len(relatedResources) != len(nodeSets) {
+		return false
+	}
+
+	for i, rr := range relatedResources {
+		if rr.ActionRelatedResourceTypePK != actionResourceTypePKs[i] {
+			return false
+		}
+		if !nodeSets[i].Has(resourceNodeKey(rr.ResourceTypePK, rr.ResourceID)) {
+			return false
+		}
+	}
+	return true
+}
+
+// validResourceType 校验资源实例与操作关联的资源类型一一对应, 返回按操作关联资源类型顺序排列的资源实例
+func validResourceType(
+	resources []types.Resource,
+	actionResourceTypes []types.ActionResourceType,
+) ([]types.Resource, error) {
+	if len(actionResourceTypes) == 0 || len(resources) != len(actionResourceTypes) {
+		return nil, fmt.Errorf(
+			"rbacEval resources count not match action resource types, resources=`%d`, actionResourceTypes=`%d`",
+			len(resources),
+			len(actionResourceTypes),
+		)
+	}
+
+	sortedResources := make([]types.Resource, 0, len(resources))
+	for _, actionResourceType := range actionResourceTypes {
+		found := false
+		for _, resource := range resources {
+			if actionResourceType.System == resource.System && actionResourceType.Type == resource.Type {
+				sortedResources = append(sortedResources, resource)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf(
+				"resource type not match, actionResourceType=`%+v`, resources=`%+v`",
+				actionResourceType,
+				resources,
+			)
+		}
 	}
 
-	return nil
+	return sortedResources, nil
 }
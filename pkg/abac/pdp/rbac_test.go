@@ -13,6 +13,7 @@ package pdp
 import (
 	"errors"
 
+	"github.com/TencentBlueKing/gopkg/collection/set"
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -22,6 +23,7 @@ import (
 	"iam/pkg/cacheimpls"
 	"iam/pkg/service"
 	"iam/pkg/service/mock"
+	svctypes "iam/pkg/service/types"
 )
 
 var _ = Describe("rbac", func() {
@@ -35,7 +37,7 @@ var _ = Describe("rbac", func() {
 		})
 
 		It("resource type not found error", func() {
-			err := validResourceType([]types.Resource{{
+			_, err := validResourceType([]types.Resource{{
 				System:    "test",
 				Type:      "t2",
 				ID:        "id1",
@@ -46,7 +48,7 @@ var _ = Describe("rbac", func() {
 		})
 
 		It("resource type system not match error", func() {
-			err := validResourceType([]types.Resource{{
+			_, err := validResourceType([]types.Resource{{
 				System:    "test1",
 				Type:      "t1",
 				ID:        "id1",
@@ -55,6 +57,63 @@ var _ = Describe("rbac", func() {
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "resource type not match")
 		})
+
+		It("resources count not match error", func() {
+			_, err := validResourceType([]types.Resource{
+				{System: "test", Type: "t1", ID: "id1"},
+				{System: "test", Type: "t2", ID: "id2"},
+			}, actionResourceTypes)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "resources count not match")
+		})
+
+		It("ok, sorted by action resource types", func() {
+			resources, err := validResourceType([]types.Resource{
+				{System: "test", Type: "t2", ID: "id2"},
+				{System: "test", Type: "t1", ID: "id1"},
+			}, []types.ActionResourceType{{System: "test", Type: "t1"}, {System: "test", Type: "t2"}})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.Resource{
+				{System: "test", Type: "t1", ID: "id1"},
+				{System: "test", Type: "t2", ID: "id2"},
+			}, resources)
+		})
+	})
+
+	Describe("matchRelatedResources", func() {
+		var nodeSets []*set.StringSet
+		BeforeEach(func() {
+			nodeSets = newResourceNodeSets([][]types.ResourceNode{{
+				{System: "test", Type: "biz", ID: "1", TypePK: 2},
+				{System: "test", Type: "t2", ID: "id2", TypePK: 6},
+			}})
+		})
+
+		It("single resource type", func() {
+			assert.True(GinkgoT(), matchRelatedResources(nil, nil, nil))
+		})
+
+		It("missing related resources", func() {
+			assert.False(GinkgoT(), matchRelatedResources(nil, []int64{6}, nodeSets))
+		})
+
+		It("match", func() {
+			assert.True(GinkgoT(), matchRelatedResources([]svctypes.RelatedResource{
+				{ActionRelatedResourceTypePK: 6, ResourceTypePK: 2, ResourceID: "1"},
+			}, []int64{6}, nodeSets))
+		})
+
+		It("action related resource type not match", func() {
+			assert.False(GinkgoT(), matchRelatedResources([]svctypes.RelatedResource{
+				{ActionRelatedResourceTypePK: 7, ResourceTypePK: 6, ResourceID: "id2"},
+			}, []int64{6}, nodeSets))
+		})
+
+		It("resource not match", func() {
+			assert.False(GinkgoT(), matchRelatedResources([]svctypes.RelatedResource{
+				{ActionRelatedResourceTypePK: 6, ResourceTypePK: 6, ResourceID: "id3"},
+			}, []int64{6}, nodeSets))
+		})
 	})
 
 	Describe("rbacEval", func() {
@@ -117,7 +176,7 @@ var _ = Describe("rbac", func() {
 			assert.Contains(GinkgoT(), err.Error(), "action.Attribute.GetResourceTypes")
 		})
 
-		It("resources with two types fail", func() {
+		It("resources count not match fail", func() {
 			resources = []types.Resource{{
 				System: "test",
 				Type:   "t1",
@@ -135,10 +194,10 @@ var _ = Describe("rbac", func() {
 			}}
 			_, err := rbacEval("test", action, resources, effectGroupPKs, false, nil)
 			assert.Error(GinkgoT(), err)
-			assert.Contains(GinkgoT(), err.Error(), "rbacEval only support action with one resource type")
+			assert.Contains(GinkgoT(), err.Error(), "resources count not match")
 		})
 
-		It("action with two types fail", func() {
+		It("action with two types, resources count not match fail", func() {
 			action = types.NewAction()
 			action.FillAttributes(1, 1, []types.ActionResourceType{{
 				System: "test",
@@ -149,21 +208,21 @@ var _ = Describe("rbac", func() {
 			}})
 			_, err := rbacEval("test", action, resources, effectGroupPKs, false, nil)
 			assert.Error(GinkgoT(), err)
-			assert.Contains(GinkgoT(), err.Error(), "rbacEval only support action with one resource type")
+			assert.Contains(GinkgoT(), err.Error(), "resources count not match")
 		})
 
-		It("cacheimpls.GetResourceActionAuthorizedGroupPKs fail", func() {
-			patches = gomonkey.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroupPKs, func(
+		It("cacheimpls.GetResourceActionAuthorizedGroups fail", func() {
+			patches = gomonkey.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroups, func(
 				systemID string,
 				actionPK, actionResourceTypePK, resourceTypePK int64,
 				resourceID string,
-			) ([]int64, error) {
+			) ([]svctypes.AuthorizedGroup, error) {
 				return nil, errors.New("test")
 			})
 
 			_, err := rbacEval("test", action, resources, effectGroupPKs, false, nil)
 			assert.Error(GinkgoT(), err)
-			assert.Contains(GinkgoT(), err.Error(), "GetResourceActionAuthorizedGroupPKs")
+			assert.Contains(GinkgoT(), err.Error(), "GetResourceActionAuthorizedGroups")
 		})
 
 		It("svc.GetAuthorizedActionGroupMap fail", func() {
@@ -183,12 +242,12 @@ var _ = Describe("rbac", func() {
 		})
 
 		It("not pass withCache", func() {
-			patches = gomonkey.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroupPKs, func(
+			patches = gomonkey.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroups, func(
 				systemID string,
 				actionPK, actionResourceTypePK, resourceTypePK int64,
 				resourceID string,
-			) ([]int64, error) {
-				return []int64{3}, nil
+			) ([]svctypes.AuthorizedGroup, error) {
+				return []svctypes.AuthorizedGroup{{GroupPK: 3}}, nil
 			})
 
 			isPass, err := rbacEval("test", action, resources, effectGroupPKs, false, nil)
@@ -200,7 +259,7 @@ var _ = Describe("rbac", func() {
 			mockSvc := mock.NewMockGroupResourcePolicyService(ctl)
 			mockSvc.EXPECT().
 				GetAuthorizedActionGroupMap("test", int64(1), gomock.Any(), gomock.Any()).
-				Return(map[int64][]int64{}, nil).
+				Return(map[int64][]svctypes.AuthorizedGroup{}, nil).
 				AnyTimes()
 			patches = gomonkey.ApplyFunc(
 				service.NewGroupResourcePolicyService,
@@ -213,12 +272,12 @@ var _ = Describe("rbac", func() {
 		})
 
 		It("pass withCache", func() {
-			patches = gomonkey.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroupPKs, func(
+			patches = gomonkey.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroups, func(
 				systemID string,
 				actionPK, actionResourceTypePK, resourceTypePK int64,
 				resourceID string,
-			) ([]int64, error) {
-				return []int64{3, 1, 4, 5}, nil
+			) ([]svctypes.AuthorizedGroup, error) {
+				return []svctypes.AuthorizedGroup{{GroupPK: 3}, {GroupPK: 1}, {GroupPK: 4}, {GroupPK: 5}}, nil
 			})
 
 			isPass, err := rbacEval("test", action, resources, effectGroupPKs, false, nil)
@@ -230,7 +289,7 @@ var _ = Describe("rbac", func() {
 			mockSvc := mock.NewMockGroupResourcePolicyService(ctl)
 			mockSvc.EXPECT().
 				GetAuthorizedActionGroupMap("test", int64(1), gomock.Any(), gomock.Any()).
-				Return(map[int64][]int64{1: {1, 4, 5}}, nil).
+				Return(map[int64][]svctypes.AuthorizedGroup{1: {{GroupPK: 1}, {GroupPK: 4}}}, nil).
 				AnyTimes()
 			patches = gomonkey.ApplyFunc(
 				service.NewGroupResourcePolicyService,
@@ -241,5 +300,101 @@ var _ = Describe("rbac", func() {
 			assert.NoError(GinkgoT(), err)
 			assert.True(GinkgoT(), isPass)
 		})
+
+		Describe("action with two types", func() {
+			BeforeEach(func() {
+				action = types.NewAction()
+				action.FillAttributes(1, 1, []types.ActionResourceType{{
+					System: "test",
+					Type:   "t1",
+				}, {
+					System: "test",
+					Type:   "t2",
+				}})
+
+				resources = append(resources, types.Resource{
+					System: "test",
+					Type:   "t2",
+					ID:     "id2",
+					Attribute: map[string]interface{}{
+						"_bk_iam_path_": "/cmdb,biz,1/",
+					},
+				})
+
+				patches.ApplyFunc(
+					cacheimpls.GetLocalResourceTypePK,
+					func(_ string, id string) (int64, error) {
+						switch id {
+						case "t1":
+							return 1, nil
+						case "biz":
+							return 2, nil
+						case "set":
+							return 3, nil
+						case "module":
+							return 4, nil
+						case "func":
+							return 5, nil
+						case "t2":
+							return 6, nil
+						default:
+							return 0, errors.New("not found")
+						}
+					},
+				)
+			})
+
+			It("pass, related resource match the iam path", func() {
+				patches.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroups, func(
+					systemID string,
+					actionPK, actionResourceTypePK, resourceTypePK int64,
+					resourceID string,
+				) ([]svctypes.AuthorizedGroup, error) {
+					return []svctypes.AuthorizedGroup{{
+						GroupPK: 1,
+						RelatedResources: []svctypes.RelatedResource{
+							{ActionRelatedResourceTypePK: 6, ResourceTypePK: 2, ResourceID: "1"},
+						},
+					}}, nil
+				})
+
+				isPass, err := rbacEval("test", action, resources, effectGroupPKs, false, nil)
+				assert.NoError(GinkgoT(), err)
+				assert.True(GinkgoT(), isPass)
+			})
+
+			It("not pass, related resource not match", func() {
+				patches.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroups, func(
+					systemID string,
+					actionPK, actionResourceTypePK, resourceTypePK int64,
+					resourceID string,
+				) ([]svctypes.AuthorizedGroup, error) {
+					return []svctypes.AuthorizedGroup{{
+						GroupPK: 1,
+						RelatedResources: []svctypes.RelatedResource{
+							{ActionRelatedResourceTypePK: 6, ResourceTypePK: 6, ResourceID: "id3"},
+						},
+					}}, nil
+				})
+
+				isPass, err := rbacEval("test", action, resources, effectGroupPKs, false, nil)
+				assert.NoError(GinkgoT(), err)
+				assert.False(GinkgoT(), isPass)
+			})
+
+			It("not pass, grant without related resources", func() {
+				patches.ApplyFunc(cacheimpls.GetResourceActionAuthorizedGroups, func(
+					systemID string,
+					actionPK, actionResourceTypePK, resourceTypePK int64,
+					resourceID string,
+				) ([]svctypes.AuthorizedGroup, error) {
+					return []svctypes.AuthorizedGroup{{GroupPK: 1}}, nil
+				})
+
+				isPass, err := rbacEval("test", action, resources, effectGroupPKs, false, nil)
+				assert.NoError(GinkgoT(), err)
+				assert.False(GinkgoT(), isPass)
+			})
+		})
 	})
 })
@@ -0,0 +1,99 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pdp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+
+	"iam/pkg/abac/pdp/evalctx"
+	"iam/pkg/abac/pdp/evaluation"
+	"iam/pkg/abac/pdp/translate"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+)
+
+// ErrInvalidPolicyExpression 候选策略的表达式无法解析
+var ErrInvalidPolicyExpression = errors.New("invalid policy expression")
+
+// Simulate 使用候选策略对样例请求逐个求值, 返回每个请求的鉴权结果与查询表达式
+// NOTE: 策略, 操作关联的资源类型与资源属性都由调用方提供, 不会查询DB, 只用于保存策略前测试表达式
+func Simulate(policies []types.AuthPolicy, requests []*request.Request) ([]types.SimulationResult, error) {
+	// 1. 提前校验所有候选策略的表达式, 避免每个请求都返回相同的错误
+	for _, policy := range policies {
+		_, err := translate.PolicyExpressionToCondition(policy.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("%w: policy id=`%d`, %s", ErrInvalidPolicyExpression, policy.ID, err.Error())
+		}
+	}
+
+	// 2. 逐个请求求值, 单个请求出错不影响其它请求
+	results := make([]types.SimulationResult, 0, len(requests))
+	for _, r := range requests {
+		results = append(results, simulate(policies, r))
+	}
+	return results, nil
+}
+
+func simulate(policies []types.AuthPolicy, r *request.Request) (result types.SimulationResult) {
+	result = types.SimulationResult{
+		PolicyID:   -1,
+		Expression: EmptyPolicies,
+	}
+
+	// eval与query中求值出错的策略
+	failedPolicyIDSet := set.NewInt64Set()
+	defer func() {
+		result.FailedPolicyIDs = failedPolicyIDSet.ToSlice()
+	}()
+
+	// 1. eval: 只有传了资源, 或者操作不关联资源类型时才能计算是否有权限
+	if r.HasResources() || r.Action.WithoutResourceType() {
+		if !r.ValidateActionResource() {
+			result.Error = ErrInvalidActionResource.Error()
+			return result
+		}
+
+		ctx := evalctx.NewEvalContext(r)
+		isPass, policyID, err := evaluation.EvalPolicies(ctx, policies)
+		failedPolicyIDSet.Append(ctx.FailedPolicyIDs()...)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+
+		result.Allowed = isPass
+		result.PolicyID = policyID
+	}
+
+	// 2. query: 部分求值后转换为查询表达式
+	ctx := evalctx.NewEvalContext(r)
+	conditions, _, err := evaluation.PartialEvalPolicies(ctx, policies)
+	failedPolicyIDSet.Append(ctx.FailedPolicyIDs()...)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if len(conditions) == 0 {
+		return result
+	}
+
+	expr, err := translate.ConditionsTranslate(conditions, r.WithSystem)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Expression = expr
+
+	return result
+}
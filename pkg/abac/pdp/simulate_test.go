@@ -0,0 +1,108 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pdp
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	gocache "github.com/wklken/go-cache"
+
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/cacheimpls"
+	svctypes "iam/pkg/service/types"
+)
+
+var _ = Describe("Simulate", func() {
+	allowLinuxPolicy := types.AuthPolicy{
+		ID:         1,
+		Expression: `{"StringEquals": {"iam.job.system": ["linux"]}}`,
+	}
+	denyJob2Policy := types.AuthPolicy{
+		ID:         2,
+		Expression: `{"StringEquals": {"iam.job.id": ["job2"]}}`,
+		Effect:     svctypes.PolicyEffectDeny,
+	}
+
+	newRequest := func(resources ...types.Resource) *request.Request {
+		r := request.NewRequest()
+		r.System = "iam"
+		r.Action.ID = "execute_job"
+		r.Action.Attribute.SetResourceTypes([]types.ActionResourceType{{System: "iam", Type: "job"}})
+		r.Resources = resources
+		return r
+	}
+
+	BeforeEach(func() {
+		cacheimpls.LocalUnmarshaledExpressionCache = gocache.New(1*time.Minute, 5*time.Minute)
+		cacheimpls.LocalCompiledExpressionCache = gocache.New(1*time.Minute, 5*time.Minute)
+	})
+
+	It("invalid expression", func() {
+		_, err := Simulate([]types.AuthPolicy{{ID: 3, Expression: "{"}}, []*request.Request{newRequest()})
+		assert.Error(GinkgoT(), err)
+		assert.True(GinkgoT(), errors.Is(err, ErrInvalidPolicyExpression))
+	})
+
+	It("ok", func() {
+		results, err := Simulate(
+			[]types.AuthPolicy{allowLinuxPolicy, denyJob2Policy},
+			[]*request.Request{
+				newRequest(types.Resource{
+					System: "iam", Type: "job", ID: "job1",
+					Attribute: map[string]interface{}{"system": "linux"},
+				}),
+				newRequest(types.Resource{
+					System: "iam", Type: "job", ID: "job2",
+					Attribute: map[string]interface{}{"system": "linux"},
+				}),
+				newRequest(types.Resource{
+					System: "iam", Type: "job", ID: "job3",
+					Attribute: map[string]interface{}{"system": "windows"},
+				}),
+				newRequest(),
+			},
+		)
+		assert.NoError(GinkgoT(), err)
+		assert.Len(GinkgoT(), results, 4)
+
+		// allowed by policy 1
+		assert.True(GinkgoT(), results[0].Allowed)
+		assert.Equal(GinkgoT(), int64(1), results[0].PolicyID)
+		assert.Equal(GinkgoT(), "any", results[0].Expression["op"])
+
+		// denied by policy 2
+		assert.False(GinkgoT(), results[1].Allowed)
+		assert.Equal(GinkgoT(), int64(2), results[1].PolicyID)
+		assert.Empty(GinkgoT(), results[1].Expression)
+
+		// no policy matched
+		assert.False(GinkgoT(), results[2].Allowed)
+		assert.Equal(GinkgoT(), int64(-1), results[2].PolicyID)
+		assert.Empty(GinkgoT(), results[2].Expression)
+
+		// query only, allow AND NOT deny
+		assert.False(GinkgoT(), results[3].Allowed)
+		assert.Equal(GinkgoT(), "AND", results[3].Expression["op"])
+		assert.Empty(GinkgoT(), results[3].Error)
+	})
+
+	It("resources not match action", func() {
+		results, err := Simulate([]types.AuthPolicy{allowLinuxPolicy}, []*request.Request{
+			newRequest(types.Resource{System: "iam", Type: "host", ID: "host1"}),
+		})
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), ErrInvalidActionResource.Error(), results[0].Error)
+	})
+})
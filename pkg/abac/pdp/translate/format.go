@@ -0,0 +1,135 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	abacTypes "iam/pkg/abac/types"
+)
+
+// 表达式的输出格式
+const (
+	// FormatExpression 默认, 原始的 op/field/value 表达式
+	FormatExpression = "expression"
+	// FormatSQL 参数化的SQL WHERE条件
+	FormatSQL = "sql"
+	// FormatES Elasticsearch bool query
+	FormatES = "es"
+	// FormatMongo MongoDB filter
+	FormatMongo = "mongo"
+)
+
+// ErrUnsupportedOperator 输出格式不支持表达式中的操作符
+var ErrUnsupportedOperator = errors.New("operator not supported by the format")
+
+var validFieldRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// Formatter 将表达式转换为接入系统存储可直接使用的查询条件
+type Formatter interface {
+	Format(expr ExprCell) (interface{}, error)
+}
+
+type formatterFactory func(fieldMapping map[string]string) Formatter
+
+var formatterFactories = map[string]formatterFactory{
+	FormatSQL:   newSQLFormatter,
+	FormatES:    newESFormatter,
+	FormatMongo: newMongoFormatter,
+}
+
+// IsSupportedFormat 是否支持的输出格式
+func IsSupportedFormat(format string) bool {
+	if format == "" || format == FormatExpression {
+		return true
+	}
+	_, ok := formatterFactories[format]
+	return ok
+}
+
+// NewFormatter 创建输出格式转换器, fieldMapping 为表达式field到存储字段的映射, 未映射的field原样输出
+func NewFormatter(format string, fieldMapping map[string]string) (Formatter, error) {
+	factory, ok := formatterFactories[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported format %s", format)
+	}
+	return factory(fieldMapping), nil
+}
+
+// FormatExpr 将表达式转换为指定的输出格式, format为空或expression时原样返回
+func FormatExpr(format string, fieldMapping map[string]string, expr map[string]interface{}) (interface{}, error) {
+	if format == "" || format == FormatExpression {
+		return expr, nil
+	}
+
+	formatter, err := NewFormatter(format, fieldMapping)
+	if err != nil {
+		return nil, err
+	}
+	return formatter.Format(expr)
+}
+
+// fieldMapper 表达式field到存储字段的映射
+type fieldMapper map[string]string
+
+func (m fieldMapper) field(expr ExprCell) (string, error) {
+	field, ok := expr["field"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid field %v", expr["field"])
+	}
+
+	if mapped, ok := m[field]; ok {
+		return mapped, nil
+	}
+	return field, nil
+}
+
+// exprValues 获取 in/not_in 的值列表
+func exprValues(expr ExprCell) ([]interface{}, error) {
+	switch values := expr["value"].(type) {
+	case []interface{}:
+		return values, nil
+	default:
+		return nil, fmt.Errorf("op %s value should be array, got %v", exprOp(expr), expr["value"])
+	}
+}
+
+// exprStringValue 获取字符串匹配类操作符的值
+func exprStringValue(expr ExprCell) (string, error) {
+	value, ok := expr["value"].(string)
+	if !ok {
+		return "", fmt.Errorf("op %s value should be string, got %v", exprOp(expr), expr["value"])
+	}
+	return value, nil
+}
+
+// prefixValue 获取starts_with的前缀
+// NOTE: _bk_iam_path_ 支持最后一个节点为任意, /biz,1/set,*/ 应该按前缀 /biz,1/set, 处理
+func prefixValue(expr ExprCell) (string, error) {
+	value, err := exprStringValue(expr)
+	if err != nil {
+		return "", err
+	}
+
+	field, _ := expr["field"].(string)
+	if (field == abacTypes.IamPath || strings.HasSuffix(field, abacTypes.IamPathSuffix)) &&
+		strings.HasSuffix(value, ",*/") {
+		value = value[0 : len(value)-2]
+	}
+	return value, nil
+}
+
+func unsupportedOperatorError(format, op string) error {
+	return fmt.Errorf("%w: format=%s, op=%s", ErrUnsupportedOperator, format, op)
+}
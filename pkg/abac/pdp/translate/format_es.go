@@ -0,0 +1,127 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	"fmt"
+	"strings"
+)
+
+type esFormatter struct {
+	fields fieldMapper
+}
+
+func newESFormatter(fieldMapping map[string]string) Formatter {
+	return &esFormatter{fields: fieldMapping}
+}
+
+// Format 转换为Elasticsearch bool query
+func (f *esFormatter) Format(expr ExprCell) (interface{}, error) {
+	// 空表达式表示没有权限
+	if len(expr) == 0 {
+		return map[string]interface{}{"match_none": map[string]interface{}{}}, nil
+	}
+	return f.format(expr)
+}
+
+func (f *esFormatter) format(expr ExprCell) (map[string]interface{}, error) {
+	op := exprOp(expr)
+	switch op {
+	case "any":
+		return map[string]interface{}{"match_all": map[string]interface{}{}}, nil
+	case "AND", "OR":
+		content := exprContent(expr)
+		queries := make([]interface{}, 0, len(content))
+		for _, c := range content {
+			q, err := f.format(c)
+			if err != nil {
+				return nil, err
+			}
+			queries = append(queries, q)
+		}
+		if len(queries) == 0 {
+			return nil, fmt.Errorf("op %s content should not be empty", op)
+		}
+
+		if op == "AND" {
+			return map[string]interface{}{"bool": map[string]interface{}{"filter": queries}}, nil
+		}
+		return map[string]interface{}{
+			"bool": map[string]interface{}{"should": queries, "minimum_should_match": 1},
+		}, nil
+	}
+
+	field, err := f.fields.field(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	// not_xxx => bool must_not xxx
+	if strings.HasPrefix(op, "not_") {
+		positive := ExprCell{"op": strings.TrimPrefix(op, "not_"), "field": expr["field"], "value": expr["value"]}
+		q, err := f.format(positive)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"bool": map[string]interface{}{"must_not": []interface{}{q}}}, nil
+	}
+
+	switch op {
+	// NOTE: ES中term作用于数组字段时, 任意一个元素相等即命中, 所以contains也使用term
+	// ip类型的字段, term支持CIDR
+	case "eq", "contains", "in_cidr":
+		return map[string]interface{}{"term": map[string]interface{}{field: expr["value"]}}, nil
+	case "in":
+		values, err := exprValues(expr)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"terms": map[string]interface{}{field: values}}, nil
+	case "gt", "gte", "lt", "lte":
+		return map[string]interface{}{
+			"range": map[string]interface{}{field: map[string]interface{}{op: expr["value"]}},
+		}, nil
+	case "starts_with":
+		value, err := prefixValue(expr)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"prefix": map[string]interface{}{field: value}}, nil
+	case "ends_with", "string_contains":
+		value, err := exprStringValue(expr)
+		if err != nil {
+			return nil, err
+		}
+		pattern := "*" + escapeWildcard(value)
+		if op == "string_contains" {
+			pattern += "*"
+		}
+		return map[string]interface{}{"wildcard": map[string]interface{}{field: pattern}}, nil
+	case "match":
+		// 通配符语义与ES wildcard一致: `*` 任意长度, `?` 单个字符
+		value, err := exprStringValue(expr)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"wildcard": map[string]interface{}{field: strings.ReplaceAll(value, `\`, `\\`)},
+		}, nil
+	default:
+		return nil, unsupportedOperatorError(FormatES, op)
+	}
+}
+
+var wildcardReplacer = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
+
+// escapeWildcard 转义ES wildcard中的通配符
+func escapeWildcard(s string) string {
+	return wildcardReplacer.Replace(s)
+}
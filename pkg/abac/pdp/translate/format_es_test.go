@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("ESFormatter", func() {
+	var f Formatter
+	BeforeEach(func() {
+		f = newESFormatter(map[string]string{"host.id": "host_id"})
+	})
+
+	DescribeTable("Format", func(expr ExprCell, want map[string]interface{}) {
+		q, err := f.Format(expr)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), want, q)
+	},
+		Entry("any", ExprCell{"op": "any", "field": "", "value": []interface{}{}},
+			map[string]interface{}{"match_all": map[string]interface{}{}}),
+		Entry("empty expression", ExprCell{}, map[string]interface{}{"match_none": map[string]interface{}{}}),
+		Entry("eq with mapping", ExprCell{"op": "eq", "field": "host.id", "value": "1"},
+			map[string]interface{}{"term": map[string]interface{}{"host_id": "1"}}),
+		Entry("in", ExprCell{"op": "in", "field": "host.os", "value": []interface{}{"a", "b"}},
+			map[string]interface{}{"terms": map[string]interface{}{"host.os": []interface{}{"a", "b"}}}),
+		Entry("not_eq", ExprCell{"op": "not_eq", "field": "host.os", "value": "linux"},
+			map[string]interface{}{"bool": map[string]interface{}{"must_not": []interface{}{
+				map[string]interface{}{"term": map[string]interface{}{"host.os": "linux"}},
+			}}}),
+		Entry("lte", ExprCell{"op": "lte", "field": "host.cpu", "value": 4},
+			map[string]interface{}{"range": map[string]interface{}{"host.cpu": map[string]interface{}{"lte": 4}}}),
+		Entry("starts_with iam path any", ExprCell{
+			"op": "starts_with", "field": "host._bk_iam_path_", "value": "/biz,1/set,*/",
+		}, map[string]interface{}{"prefix": map[string]interface{}{"host._bk_iam_path_": "/biz,1/set,"}}),
+		Entry("ends_with escape", ExprCell{"op": "ends_with", "field": "host.name", "value": "a*"},
+			map[string]interface{}{"wildcard": map[string]interface{}{"host.name": `*a\*`}}),
+		Entry("string_contains", ExprCell{"op": "string_contains", "field": "host.name", "value": "db"},
+			map[string]interface{}{"wildcard": map[string]interface{}{"host.name": "*db*"}}),
+		Entry("match", ExprCell{"op": "match", "field": "host.name", "value": "db-?-*"},
+			map[string]interface{}{"wildcard": map[string]interface{}{"host.name": "db-?-*"}}),
+		Entry("in_cidr", ExprCell{"op": "in_cidr", "field": "host.ip", "value": "10.0.0.0/8"},
+			map[string]interface{}{"term": map[string]interface{}{"host.ip": "10.0.0.0/8"}}),
+		Entry("and/or", ExprCell{
+			"op": "OR",
+			"content": []ExprCell{
+				{"op": "eq", "field": "host.id", "value": "1"},
+				{"op": "AND", "content": []ExprCell{
+					{"op": "eq", "field": "host.os", "value": "linux"},
+				}},
+			},
+		}, map[string]interface{}{"bool": map[string]interface{}{
+			"should": []interface{}{
+				map[string]interface{}{"term": map[string]interface{}{"host_id": "1"}},
+				map[string]interface{}{"bool": map[string]interface{}{"filter": []interface{}{
+					map[string]interface{}{"term": map[string]interface{}{"host.os": "linux"}},
+				}}},
+			},
+			"minimum_should_match": 1,
+		}}),
+	)
+
+	It("fail, unsupported operator", func() {
+		_, err := f.Format(ExprCell{"op": "unknown", "field": "host.ip", "value": "1"})
+		assert.True(GinkgoT(), errors.Is(err, ErrUnsupportedOperator))
+
+		_, err = f.Format(ExprCell{"op": "not_unknown", "field": "host.ip", "value": "1"})
+		assert.True(GinkgoT(), errors.Is(err, ErrUnsupportedOperator))
+	})
+
+	It("fail, empty content", func() {
+		_, err := f.Format(ExprCell{"op": "OR", "content": []ExprCell{}})
+		assert.Error(GinkgoT(), err)
+	})
+})
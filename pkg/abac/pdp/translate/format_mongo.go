@@ -0,0 +1,125 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"iam/pkg/abac/pdp/condition"
+)
+
+var mongoCompareOperators = map[string]string{
+	"eq":     "$eq",
+	"not_eq": "$ne",
+	"gt":     "$gt",
+	"gte":    "$gte",
+	"lt":     "$lt",
+	"lte":    "$lte",
+	"in":     "$in",
+	"not_in": "$nin",
+	// 数组字段, $eq/$ne 会对数组中的每个元素比较
+	"contains":     "$eq",
+	"not_contains": "$ne",
+}
+
+type mongoFormatter struct {
+	fields fieldMapper
+}
+
+func newMongoFormatter(fieldMapping map[string]string) Formatter {
+	return &mongoFormatter{fields: fieldMapping}
+}
+
+// Format 转换为MongoDB filter
+func (f *mongoFormatter) Format(expr ExprCell) (interface{}, error) {
+	// 空表达式表示没有权限, 所有文档都有_id, 空的$in不会匹配任何文档
+	if len(expr) == 0 {
+		return map[string]interface{}{"_id": map[string]interface{}{"$in": []interface{}{}}}, nil
+	}
+	return f.format(expr)
+}
+
+func (f *mongoFormatter) format(expr ExprCell) (map[string]interface{}, error) {
+	op := exprOp(expr)
+	switch op {
+	case "any":
+		return map[string]interface{}{}, nil
+	case "AND", "OR":
+		content := exprContent(expr)
+		filters := make([]interface{}, 0, len(content))
+		for _, c := range content {
+			filter, err := f.format(c)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, filter)
+		}
+		if len(filters) == 0 {
+			return nil, fmt.Errorf("op %s content should not be empty", op)
+		}
+		return map[string]interface{}{"$" + strings.ToLower(op): filters}, nil
+	}
+
+	field, err := f.fields.field(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if mongoOp, ok := mongoCompareOperators[op]; ok {
+		value := expr["value"]
+		if op == "in" || op == "not_in" {
+			value, err = exprValues(expr)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return map[string]interface{}{field: map[string]interface{}{mongoOp: value}}, nil
+	}
+
+	var pattern string
+	switch strings.TrimPrefix(op, "not_") {
+	case "starts_with":
+		value, err := prefixValue(expr)
+		if err != nil {
+			return nil, err
+		}
+		pattern = "^" + regexp.QuoteMeta(value)
+	case "ends_with":
+		value, err := exprStringValue(expr)
+		if err != nil {
+			return nil, err
+		}
+		pattern = regexp.QuoteMeta(value) + "$"
+	case "string_contains":
+		value, err := exprStringValue(expr)
+		if err != nil {
+			return nil, err
+		}
+		pattern = regexp.QuoteMeta(value)
+	case "match":
+		value, err := exprStringValue(expr)
+		if err != nil {
+			return nil, err
+		}
+		pattern = condition.GlobToRegexp(value)
+	default:
+		return nil, unsupportedOperatorError(FormatMongo, op)
+	}
+
+	if strings.HasPrefix(op, "not_") {
+		return map[string]interface{}{
+			field: map[string]interface{}{"$not": map[string]interface{}{"$regex": pattern}},
+		}, nil
+	}
+	return map[string]interface{}{field: map[string]interface{}{"$regex": pattern}}, nil
+}
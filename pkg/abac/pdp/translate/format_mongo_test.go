@@ -0,0 +1,78 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("MongoFormatter", func() {
+	var f Formatter
+	BeforeEach(func() {
+		f = newMongoFormatter(map[string]string{"host.id": "_id"})
+	})
+
+	DescribeTable("Format", func(expr ExprCell, want map[string]interface{}) {
+		q, err := f.Format(expr)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), want, q)
+	},
+		Entry("any", ExprCell{"op": "any", "field": "", "value": []interface{}{}}, map[string]interface{}{}),
+		Entry("empty expression", ExprCell{},
+			map[string]interface{}{"_id": map[string]interface{}{"$in": []interface{}{}}}),
+		Entry("eq with mapping", ExprCell{"op": "eq", "field": "host.id", "value": "1"},
+			map[string]interface{}{"_id": map[string]interface{}{"$eq": "1"}}),
+		Entry("not_in", ExprCell{"op": "not_in", "field": "host.os", "value": []interface{}{"a"}},
+			map[string]interface{}{"host.os": map[string]interface{}{"$nin": []interface{}{"a"}}}),
+		Entry("gt", ExprCell{"op": "gt", "field": "host.cpu", "value": 4},
+			map[string]interface{}{"host.cpu": map[string]interface{}{"$gt": 4}}),
+		Entry("contains", ExprCell{"op": "contains", "field": "host.tags", "value": "db"},
+			map[string]interface{}{"host.tags": map[string]interface{}{"$eq": "db"}}),
+		Entry("starts_with iam path any", ExprCell{
+			"op": "starts_with", "field": "host._bk_iam_path_", "value": "/biz,1/set,*/",
+		}, map[string]interface{}{"host._bk_iam_path_": map[string]interface{}{"$regex": "^/biz,1/set,"}}),
+		Entry("not_ends_with", ExprCell{"op": "not_ends_with", "field": "host.name", "value": ".com"},
+			map[string]interface{}{"host.name": map[string]interface{}{
+				"$not": map[string]interface{}{"$regex": `\.com$`},
+			}}),
+		Entry("string_contains", ExprCell{"op": "string_contains", "field": "host.name", "value": "a+b"},
+			map[string]interface{}{"host.name": map[string]interface{}{"$regex": `a\+b`}}),
+		Entry("match", ExprCell{"op": "match", "field": "host.name", "value": "db-?-*"},
+			map[string]interface{}{"host.name": map[string]interface{}{"$regex": "^db-.-.*$"}}),
+		Entry("and/or", ExprCell{
+			"op": "AND",
+			"content": []ExprCell{
+				{"op": "eq", "field": "host.id", "value": "1"},
+				{"op": "OR", "content": []ExprCell{
+					{"op": "lt", "field": "host.cpu", "value": 8},
+				}},
+			},
+		}, map[string]interface{}{"$and": []interface{}{
+			map[string]interface{}{"_id": map[string]interface{}{"$eq": "1"}},
+			map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"host.cpu": map[string]interface{}{"$lt": 8}},
+			}},
+		}}),
+	)
+
+	It("fail, unsupported operator", func() {
+		_, err := f.Format(ExprCell{"op": "in_cidr", "field": "host.ip", "value": "10.0.0.0/8"})
+		assert.True(GinkgoT(), errors.Is(err, ErrUnsupportedOperator))
+	})
+
+	It("fail, empty content", func() {
+		_, err := f.Format(ExprCell{"op": "AND", "content": []ExprCell{}})
+		assert.Error(GinkgoT(), err)
+	})
+})
@@ -0,0 +1,178 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SQLCondition 参数化的SQL WHERE条件, 占位符为`?`
+type SQLCondition struct {
+	Where string        `json:"where"`
+	Args  []interface{} `json:"args"`
+}
+
+var sqlCompareOperators = map[string]string{
+	"eq":     "=",
+	"not_eq": "!=",
+	"gt":     ">",
+	"gte":    ">=",
+	"lt":     "<",
+	"lte":    "<=",
+}
+
+type sqlFormatter struct {
+	fields fieldMapper
+}
+
+func newSQLFormatter(fieldMapping map[string]string) Formatter {
+	return &sqlFormatter{fields: fieldMapping}
+}
+
+// Format 转换为SQL WHERE条件
+func (f *sqlFormatter) Format(expr ExprCell) (interface{}, error) {
+	cond := &SQLCondition{Args: []interface{}{}}
+	// 空表达式表示没有权限
+	if len(expr) == 0 {
+		cond.Where = "1 = 0"
+		return cond, nil
+	}
+
+	where, err := f.format(expr, cond)
+	if err != nil {
+		return nil, err
+	}
+	cond.Where = where
+	return cond, nil
+}
+
+func (f *sqlFormatter) format(expr ExprCell, cond *SQLCondition) (string, error) {
+	op := exprOp(expr)
+	switch op {
+	case "any":
+		return "1 = 1", nil
+	case "AND", "OR":
+		content := exprContent(expr)
+		parts := make([]string, 0, len(content))
+		for _, c := range content {
+			part, err := f.format(c, cond)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		if len(parts) == 0 {
+			return "", fmt.Errorf("op %s content should not be empty", op)
+		}
+		return "(" + strings.Join(parts, " "+op+" ") + ")", nil
+	}
+
+	column, err := f.column(expr)
+	if err != nil {
+		return "", err
+	}
+
+	switch op {
+	case "eq", "not_eq", "gt", "gte", "lt", "lte":
+		cond.Args = append(cond.Args, expr["value"])
+		return column + " " + sqlCompareOperators[op] + " ?", nil
+	case "in", "not_in":
+		values, err := exprValues(expr)
+		if err != nil {
+			return "", err
+		}
+		// 空列表: in永远为false, not_in永远为true
+		if len(values) == 0 {
+			if op == "in" {
+				return "1 = 0", nil
+			}
+			return "1 = 1", nil
+		}
+
+		cond.Args = append(cond.Args, values...)
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		if op == "in" {
+			return column + " IN (" + placeholders + ")", nil
+		}
+		return column + " NOT IN (" + placeholders + ")", nil
+	case "starts_with", "not_starts_with":
+		value, err := prefixValue(expr)
+		if err != nil {
+			return "", err
+		}
+		return f.like(column, op, escapeLike(value)+"%", cond), nil
+	case "ends_with", "not_ends_with", "string_contains", "not_string_contains":
+		value, err := exprStringValue(expr)
+		if err != nil {
+			return "", err
+		}
+		pattern := "%" + escapeLike(value)
+		if op == "string_contains" || op == "not_string_contains" {
+			pattern += "%"
+		}
+		return f.like(column, op, pattern, cond), nil
+	case "match", "not_match":
+		value, err := exprStringValue(expr)
+		if err != nil {
+			return "", err
+		}
+		return f.like(column, op, globToLike(value), cond), nil
+	default:
+		return "", unsupportedOperatorError(FormatSQL, op)
+	}
+}
+
+// column 获取字段名, 未配置映射的字段需要是合法的标识符, 防止SQL注入
+func (f *sqlFormatter) column(expr ExprCell) (string, error) {
+	field, _ := expr["field"].(string)
+	column, err := f.fields.field(expr)
+	if err != nil {
+		return "", err
+	}
+
+	if _, mapped := f.fields[field]; !mapped && !validFieldRegex.MatchString(column) {
+		return "", fmt.Errorf("invalid sql column %s, should set the field mapping", column)
+	}
+	return column, nil
+}
+
+func (f *sqlFormatter) like(column, op, pattern string, cond *SQLCondition) string {
+	cond.Args = append(cond.Args, pattern)
+	if strings.HasPrefix(op, "not_") {
+		return column + " NOT LIKE ?"
+	}
+	return column + " LIKE ?"
+}
+
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike 转义LIKE中的通配符, 使用默认的转义字符`\`
+func escapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+// globToLike 通配符表达式转换为LIKE表达式, `*` => `%`, `?` => `_`
+func globToLike(pattern string) string {
+	var b strings.Builder
+	b.Grow(len(pattern) + 8)
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		default:
+			b.WriteString(escapeLike(string(r)))
+		}
+	}
+	return b.String()
+}
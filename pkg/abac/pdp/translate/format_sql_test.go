@@ -0,0 +1,87 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("SQLFormatter", func() {
+	var f Formatter
+	BeforeEach(func() {
+		f = newSQLFormatter(map[string]string{"host.id": "t_host.host_id"})
+	})
+
+	DescribeTable("Format", func(expr ExprCell, where string, args []interface{}) {
+		c, err := f.Format(expr)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), &SQLCondition{Where: where, Args: args}, c)
+	},
+		Entry("any", ExprCell{"op": "any", "field": "", "value": []interface{}{}}, "1 = 1", []interface{}{}),
+		Entry("empty expression", ExprCell{}, "1 = 0", []interface{}{}),
+		Entry("eq with mapping", ExprCell{"op": "eq", "field": "host.id", "value": "1"},
+			"t_host.host_id = ?", []interface{}{"1"}),
+		Entry("not_eq", ExprCell{"op": "not_eq", "field": "host.os", "value": "linux"},
+			"host.os != ?", []interface{}{"linux"}),
+		Entry("gte", ExprCell{"op": "gte", "field": "host.cpu", "value": 4}, "host.cpu >= ?", []interface{}{4}),
+		Entry("in", ExprCell{"op": "in", "field": "host.os", "value": []interface{}{"a", "b"}},
+			"host.os IN (?, ?)", []interface{}{"a", "b"}),
+		Entry("not_in", ExprCell{"op": "not_in", "field": "host.os", "value": []interface{}{"a"}},
+			"host.os NOT IN (?)", []interface{}{"a"}),
+		Entry("empty in", ExprCell{"op": "in", "field": "host.os", "value": []interface{}{}}, "1 = 0", []interface{}{}),
+		Entry("empty not_in", ExprCell{"op": "not_in", "field": "host.os", "value": []interface{}{}},
+			"1 = 1", []interface{}{}),
+		Entry("starts_with iam path any", ExprCell{
+			"op": "starts_with", "field": "host._bk_iam_path_", "value": "/biz,1/set,*/",
+		}, "host._bk_iam_path_ LIKE ?", []interface{}{"/biz,1/set,%"}),
+		Entry("not_starts_with escape", ExprCell{"op": "not_starts_with", "field": "host.name", "value": "a_1%"},
+			"host.name NOT LIKE ?", []interface{}{`a\_1\%%`}),
+		Entry("ends_with", ExprCell{"op": "ends_with", "field": "host.name", "value": ".com"},
+			"host.name LIKE ?", []interface{}{"%.com"}),
+		Entry("string_contains", ExprCell{"op": "string_contains", "field": "host.name", "value": "db"},
+			"host.name LIKE ?", []interface{}{"%db%"}),
+		Entry("match", ExprCell{"op": "match", "field": "host.name", "value": "db-?-*"},
+			"host.name LIKE ?", []interface{}{"db-_-%"}),
+		Entry("and/or", ExprCell{
+			"op": "OR",
+			"content": []ExprCell{
+				{"op": "eq", "field": "host.id", "value": "1"},
+				{"op": "AND", "content": []map[string]interface{}{
+					{"op": "eq", "field": "host.os", "value": "linux"},
+					{"op": "lt", "field": "host.cpu", "value": 8},
+				}},
+			},
+		}, "(t_host.host_id = ? OR (host.os = ? AND host.cpu < ?))", []interface{}{"1", "linux", 8}),
+	)
+
+	It("fail, unsupported operator", func() {
+		_, err := f.Format(ExprCell{"op": "in_cidr", "field": "host.ip", "value": "10.0.0.0/8"})
+		assert.True(GinkgoT(), errors.Is(err, ErrUnsupportedOperator))
+	})
+
+	It("fail, invalid column", func() {
+		_, err := f.Format(ExprCell{"op": "eq", "field": "host.id; drop table", "value": "1"})
+		assert.Error(GinkgoT(), err)
+	})
+
+	It("fail, empty content", func() {
+		_, err := f.Format(ExprCell{"op": "AND", "content": []ExprCell{}})
+		assert.Error(GinkgoT(), err)
+	})
+
+	It("fail, in value not array", func() {
+		_, err := f.Format(ExprCell{"op": "in", "field": "host.id", "value": "1"})
+		assert.Error(GinkgoT(), err)
+	})
+})
@@ -0,0 +1,94 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("Format", func() {
+	expr := map[string]interface{}{"op": "eq", "field": "host.id", "value": "1"}
+
+	It("IsSupportedFormat", func() {
+		assert.True(GinkgoT(), IsSupportedFormat(""))
+		assert.True(GinkgoT(), IsSupportedFormat(FormatExpression))
+		assert.True(GinkgoT(), IsSupportedFormat(FormatSQL))
+		assert.True(GinkgoT(), IsSupportedFormat(FormatES))
+		assert.True(GinkgoT(), IsSupportedFormat(FormatMongo))
+		assert.False(GinkgoT(), IsSupportedFormat("xml"))
+	})
+
+	It("NewFormatter fail", func() {
+		_, err := NewFormatter("xml", nil)
+		assert.Error(GinkgoT(), err)
+	})
+
+	Describe("FormatExpr", func() {
+		It("expression", func() {
+			formatted, err := FormatExpr("", nil, expr)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expr, formatted)
+
+			formatted, err = FormatExpr(FormatExpression, nil, expr)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expr, formatted)
+		})
+
+		It("with field mapping", func() {
+			formatted, err := FormatExpr(FormatMongo, map[string]string{"host.id": "_id"}, expr)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[string]interface{}{"_id": map[string]interface{}{"$eq": "1"}}, formatted)
+		})
+
+		DescribeTable("empty expression", func(format string, want interface{}) {
+			formatted, err := FormatExpr(format, nil, map[string]interface{}{})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), want, formatted)
+		},
+			Entry("expression", FormatExpression, map[string]interface{}{}),
+			Entry("sql", FormatSQL, &SQLCondition{Where: "1 = 0", Args: []interface{}{}}),
+			Entry("es", FormatES, map[string]interface{}{"match_none": map[string]interface{}{}}),
+			Entry("mongo", FormatMongo, map[string]interface{}{"_id": map[string]interface{}{"$in": []interface{}{}}}),
+		)
+
+		It("fail", func() {
+			_, err := FormatExpr("xml", nil, expr)
+			assert.Error(GinkgoT(), err)
+		})
+	})
+
+	Describe("prefixValue", func() {
+		It("iam path any", func() {
+			v, err := prefixValue(ExprCell{"op": "starts_with", "field": "host._bk_iam_path_", "value": "/biz,1/set,*/"})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "/biz,1/set,", v)
+		})
+
+		It("not iam path", func() {
+			v, err := prefixValue(ExprCell{"op": "starts_with", "field": "host.path", "value": "/biz,1/set,*/"})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "/biz,1/set,*/", v)
+		})
+
+		It("fail, not string", func() {
+			_, err := prefixValue(ExprCell{"op": "starts_with", "field": "host.path", "value": 1})
+			assert.Error(GinkgoT(), err)
+		})
+	})
+
+	It("unsupportedOperatorError", func() {
+		err := unsupportedOperatorError(FormatSQL, "in_cidr")
+		assert.True(GinkgoT(), errors.Is(err, ErrUnsupportedOperator))
+	})
+})
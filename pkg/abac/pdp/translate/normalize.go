@@ -0,0 +1,231 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+表达式化简, 用于减小返回给接入系统的表达式:
+
+1. 展开嵌套的AND/OR, 只有一个子节点的AND/OR直接替换为该子节点
+2. OR中存在any时, 整个表达式为any; AND中的any直接移除
+3. OR中相同field的eq/in合并为in, 并去除重复的值
+4. OR中相同field的starts_with, 移除被更短前缀覆盖的条件
+5. 移除重复的子节点
+*/
+
+// Normalize 化简转换后的表达式
+func Normalize(expr ExprCell) ExprCell {
+	switch exprOp(expr) {
+	case "AND":
+		return normalizeAnd(expr, flattenContent(expr, "AND"))
+	case "OR":
+		return normalizeOr(expr, flattenContent(expr, "OR"))
+	default:
+		return expr
+	}
+}
+
+func exprOp(expr ExprCell) string {
+	op, _ := expr["op"].(string)
+	return op
+}
+
+// exprContent 获取AND/OR的子节点, 兼容condition.Translate生成的[]map[string]interface{}
+func exprContent(expr ExprCell) []ExprCell {
+	switch content := expr["content"].(type) {
+	case []ExprCell:
+		return content
+	case []map[string]interface{}:
+		cells := make([]ExprCell, 0, len(content))
+		for _, c := range content {
+			cells = append(cells, c)
+		}
+		return cells
+	case []interface{}:
+		cells := make([]ExprCell, 0, len(content))
+		for _, c := range content {
+			switch cell := c.(type) {
+			case ExprCell:
+				cells = append(cells, cell)
+			case map[string]interface{}:
+				cells = append(cells, cell)
+			}
+		}
+		return cells
+	default:
+		return nil
+	}
+}
+
+// flattenContent 化简所有子节点, 并展开与父节点op相同的子节点
+func flattenContent(expr ExprCell, op string) []ExprCell {
+	content := exprContent(expr)
+
+	flattened := make([]ExprCell, 0, len(content))
+	for _, c := range content {
+		nc := Normalize(c)
+		if exprOp(nc) == op {
+			flattened = append(flattened, exprContent(nc)...)
+		} else {
+			flattened = append(flattened, nc)
+		}
+	}
+	return flattened
+}
+
+func normalizeAnd(expr ExprCell, content []ExprCell) ExprCell {
+	if len(content) == 0 {
+		return expr
+	}
+
+	// a AND any => a
+	var anyExpr ExprCell
+	newContent := make([]ExprCell, 0, len(content))
+	for _, c := range content {
+		if exprOp(c) == "any" {
+			anyExpr = c
+			continue
+		}
+		newContent = append(newContent, c)
+	}
+	if len(newContent) == 0 {
+		return anyExpr
+	}
+
+	return newLogicalExprCell("AND", uniqueExprCells(newContent))
+}
+
+func normalizeOr(expr ExprCell, content []ExprCell) ExprCell {
+	if len(content) == 0 {
+		return expr
+	}
+
+	// a OR any => any
+	for _, c := range content {
+		if exprOp(c) == "any" {
+			return c
+		}
+	}
+
+	content = mergeContentField(content)
+	for i, c := range content {
+		if exprOp(c) == "in" {
+			content[i] = uniqueInValues(c)
+		}
+	}
+	content = removeCoveredPrefix(content)
+
+	return newLogicalExprCell("OR", uniqueExprCells(content))
+}
+
+func newLogicalExprCell(op string, content []ExprCell) ExprCell {
+	if len(content) == 1 {
+		return content[0]
+	}
+	return ExprCell{
+		"op":      op,
+		"content": content,
+	}
+}
+
+// exprKey 用于判断表达式/值是否重复, %#v 会对map的key排序并区分值的类型
+func exprKey(v interface{}) string {
+	return fmt.Sprintf("%#v", v)
+}
+
+func uniqueExprCells(content []ExprCell) []ExprCell {
+	seen := make(map[string]struct{}, len(content))
+	newContent := make([]ExprCell, 0, len(content))
+	for _, c := range content {
+		key := exprKey(c)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		newContent = append(newContent, c)
+	}
+	return newContent
+}
+
+func uniqueInValues(expr ExprCell) ExprCell {
+	values, ok := expr["value"].([]interface{})
+	if !ok {
+		return expr
+	}
+
+	seen := make(map[string]struct{}, len(values))
+	newValues := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		key := exprKey(v)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		newValues = append(newValues, v)
+	}
+
+	if len(newValues) == len(values) {
+		return expr
+	}
+	return ExprCell{
+		"op":    "in",
+		"field": expr["field"],
+		"value": newValues,
+	}
+}
+
+// removeCoveredPrefix 移除被同field更短前缀覆盖的starts_with, 例如 /biz,1/set,2/ 被 /biz,1/ 覆盖
+func removeCoveredPrefix(content []ExprCell) []ExprCell {
+	prefixes := map[string][]string{}
+	for _, c := range content {
+		if field, value, ok := startsWithFieldValue(c); ok {
+			prefixes[field] = append(prefixes[field], value)
+		}
+	}
+	if len(prefixes) == 0 {
+		return content
+	}
+
+	newContent := make([]ExprCell, 0, len(content))
+	for _, c := range content {
+		if field, value, ok := startsWithFieldValue(c); ok && isCoveredByShorterPrefix(value, prefixes[field]) {
+			continue
+		}
+		newContent = append(newContent, c)
+	}
+	return newContent
+}
+
+func startsWithFieldValue(expr ExprCell) (field, value string, ok bool) {
+	if exprOp(expr) != "starts_with" {
+		return "", "", false
+	}
+
+	field, ok = expr["field"].(string)
+	if !ok {
+		return "", "", false
+	}
+	value, ok = expr["value"].(string)
+	return field, value, ok
+}
+
+func isCoveredByShorterPrefix(value string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if len(p) < len(value) && strings.HasPrefix(value, p) {
+			return true
+		}
+	}
+	return false
+}
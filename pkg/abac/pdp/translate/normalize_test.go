@@ -0,0 +1,182 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/pdp/condition"
+)
+
+var _ = Describe("Normalize", func() {
+	anyExpr := ExprCell{"op": "any", "field": "", "value": []interface{}{}}
+
+	It("leaf", func() {
+		expr := ExprCell{"op": "eq", "field": "host.id", "value": "1"}
+		assert.Equal(GinkgoT(), expr, Normalize(expr))
+	})
+
+	It("empty content", func() {
+		expr := ExprCell{"op": "OR", "content": []ExprCell{}}
+		assert.Equal(GinkgoT(), expr, Normalize(expr))
+	})
+
+	It("flatten nested and single element", func() {
+		expr := ExprCell{
+			"op": "OR",
+			"content": []interface{}{
+				map[string]interface{}{
+					"op": "AND",
+					"content": []map[string]interface{}{
+						{"op": "eq", "field": "host.os", "value": "linux"},
+					},
+				},
+				ExprCell{
+					"op": "OR",
+					"content": []ExprCell{
+						{"op": "gt", "field": "host.cpu", "value": 4},
+						{"op": "OR", "content": []ExprCell{
+							{"op": "lt", "field": "host.mem", "value": 8},
+						}},
+					},
+				},
+			},
+		}
+
+		want := ExprCell{
+			"op": "OR",
+			"content": []ExprCell{
+				{"op": "gt", "field": "host.cpu", "value": 4},
+				{"op": "lt", "field": "host.mem", "value": 8},
+				// eq/in 合并后追加在最后
+				{"op": "eq", "field": "host.os", "value": "linux"},
+			},
+		}
+		assert.Equal(GinkgoT(), want, Normalize(expr))
+	})
+
+	It("or with any", func() {
+		expr := ExprCell{
+			"op": "OR",
+			"content": []ExprCell{
+				{"op": "eq", "field": "host.os", "value": "linux"},
+				{"op": "AND", "content": []ExprCell{anyExpr}},
+			},
+		}
+		assert.Equal(GinkgoT(), anyExpr, Normalize(expr))
+	})
+
+	It("and with any", func() {
+		expr := ExprCell{
+			"op": "AND",
+			"content": []ExprCell{
+				anyExpr,
+				{"op": "eq", "field": "host.os", "value": "linux"},
+			},
+		}
+		assert.Equal(GinkgoT(), ExprCell{"op": "eq", "field": "host.os", "value": "linux"}, Normalize(expr))
+
+		expr = ExprCell{"op": "AND", "content": []ExprCell{anyExpr, anyExpr}}
+		assert.Equal(GinkgoT(), anyExpr, Normalize(expr))
+	})
+
+	It("merge eq into in, unique values", func() {
+		expr := ExprCell{
+			"op": "OR",
+			"content": []ExprCell{
+				{"op": "eq", "field": "host.id", "value": "1"},
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/"},
+				{"op": "in", "field": "host.id", "value": []interface{}{"2", "1"}},
+				{"op": "eq", "field": "host.os", "value": "linux"},
+			},
+		}
+
+		want := ExprCell{
+			"op": "OR",
+			"content": []ExprCell{
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/"},
+				{"op": "in", "field": "host.id", "value": []interface{}{"1", "2"}},
+				{"op": "eq", "field": "host.os", "value": "linux"},
+			},
+		}
+		assert.Equal(GinkgoT(), want, Normalize(expr))
+	})
+
+	It("drop covered prefix and duplicated", func() {
+		expr := ExprCell{
+			"op": "OR",
+			"content": []ExprCell{
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/set,2/"},
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/"},
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/"},
+				{"op": "starts_with", "field": "host.path", "value": "/biz,10/"},
+				{"op": "starts_with", "field": "module.path", "value": "/biz,1/set,2/"},
+			},
+		}
+
+		want := ExprCell{
+			"op": "OR",
+			"content": []ExprCell{
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/"},
+				{"op": "starts_with", "field": "host.path", "value": "/biz,10/"},
+				{"op": "starts_with", "field": "module.path", "value": "/biz,1/set,2/"},
+			},
+		}
+		assert.Equal(GinkgoT(), want, Normalize(expr))
+	})
+
+	It("not drop prefix in and", func() {
+		expr := ExprCell{
+			"op": "AND",
+			"content": []ExprCell{
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/set,2/"},
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/"},
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/"},
+			},
+		}
+
+		want := ExprCell{
+			"op": "AND",
+			"content": []ExprCell{
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/set,2/"},
+				{"op": "starts_with", "field": "host.path", "value": "/biz,1/"},
+			},
+		}
+		assert.Equal(GinkgoT(), want, Normalize(expr))
+	})
+
+	It("ConditionsTranslate", func() {
+		prefix := func(v string) condition.Condition {
+			c, err := condition.NewConditionFromPolicyCondition(map[string]map[string][]interface{}{
+				"StringPrefix": {"bk_cmdb.host._bk_iam_path_": {v}},
+			})
+			assert.NoError(GinkgoT(), err)
+			return c
+		}
+
+		expr, err := ConditionsTranslate([]condition.Condition{
+			condition.NewAndCondition([]condition.Condition{prefix("/biz,1/set,2/")}),
+			prefix("/biz,1/"),
+			condition.NewOrCondition([]condition.Condition{prefix("/biz,1/"), prefix("/biz,2/")}),
+		}, false)
+		assert.NoError(GinkgoT(), err)
+
+		want := map[string]interface{}{
+			"op": "OR",
+			"content": []ExprCell{
+				{"op": "starts_with", "field": "host._bk_iam_path_", "value": "/biz,1/"},
+				{"op": "starts_with", "field": "host._bk_iam_path_", "value": "/biz,2/"},
+			},
+		}
+		assert.EqualValues(GinkgoT(), want, expr)
+	})
+})
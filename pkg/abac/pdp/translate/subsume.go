@@ -0,0 +1,239 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	"strings"
+
+	abacTypes "iam/pkg/abac/types"
+)
+
+/*
+表达式包含关系判断, 用于检测被其他策略覆盖的冗余策略:
+
+a 覆盖 b 表示满足b的资源一定满足a; 无法确定时保守地返回false, 保证不会误判为冗余
+
+1. a为any时覆盖任意表达式
+2. b为OR时, b的每个子节点都需要被a覆盖; a为AND时, a的每个子节点都需要覆盖b
+3. a为OR时, a的某个子节点覆盖b即可; b为AND时, b的某个子节点被a覆盖即可
+4. 相同field的叶子节点:
+   - eq/in: b的值是a的值的子集
+   - starts_with: b的前缀或b的eq/in值都以a的前缀开头
+   - 其他操作符: 只有完全相同时才覆盖
+*/
+
+// Covers 判断表达式a是否覆盖表达式b
+func Covers(a, b ExprCell) bool {
+	return covers(Normalize(a), Normalize(b))
+}
+
+// Overlaps 判断表达式a与表达式b是否存在交集, 无法确定时返回false
+func Overlaps(a, b ExprCell) bool {
+	return overlaps(Normalize(a), Normalize(b))
+}
+
+func covers(a, b ExprCell) bool {
+	if exprOp(a) == "any" || exprKey(a) == exprKey(b) {
+		return true
+	}
+
+	switch {
+	case exprOp(b) == "any":
+		return false
+	case exprOp(b) == "OR":
+		content := exprContent(b)
+		for _, c := range content {
+			if !covers(a, c) {
+				return false
+			}
+		}
+		return len(content) > 0
+	case exprOp(a) == "AND":
+		content := exprContent(a)
+		for _, c := range content {
+			if !covers(c, b) {
+				return false
+			}
+		}
+		return len(content) > 0
+	}
+
+	if exprOp(b) == "AND" {
+		for _, c := range exprContent(b) {
+			if covers(a, c) {
+				return true
+			}
+		}
+	}
+	if exprOp(a) == "OR" {
+		for _, c := range exprContent(a) {
+			if covers(c, b) {
+				return true
+			}
+		}
+	}
+
+	return leafCovers(a, b)
+}
+
+func leafCovers(a, b ExprCell) bool {
+	field, ok := leafField(a, b)
+	if !ok {
+		return false
+	}
+
+	bValues, ok := leafValues(b)
+	switch exprOp(a) {
+	case "eq", "in":
+		if !ok {
+			return false
+		}
+		aValues, _ := leafValues(a)
+		seen := make(map[string]struct{}, len(aValues))
+		for _, v := range aValues {
+			seen[exprKey(v)] = struct{}{}
+		}
+		for _, v := range bValues {
+			if _, ok := seen[exprKey(v)]; !ok {
+				return false
+			}
+		}
+		return len(bValues) > 0
+	case "starts_with":
+		prefix, _ := a["value"].(string)
+		prefix = trimPathWildcard(field, prefix)
+
+		if bField, bPrefix, isPrefix := startsWithFieldValue(b); isPrefix {
+			return strings.HasPrefix(trimPathWildcard(bField, bPrefix), prefix)
+		}
+		if !ok {
+			return false
+		}
+		for _, v := range bValues {
+			s, isString := v.(string)
+			if !isString || !strings.HasPrefix(s, prefix) {
+				return false
+			}
+		}
+		return len(bValues) > 0
+	default:
+		return false
+	}
+}
+
+func overlaps(a, b ExprCell) bool {
+	if exprOp(a) == "any" || exprOp(b) == "any" || exprKey(a) == exprKey(b) {
+		return true
+	}
+
+	if exprOp(a) == "OR" {
+		for _, c := range exprContent(a) {
+			if overlaps(c, b) {
+				return true
+			}
+		}
+		return false
+	}
+	if exprOp(b) == "OR" {
+		for _, c := range exprContent(b) {
+			if overlaps(a, c) {
+				return true
+			}
+		}
+		return false
+	}
+	// 存在包含关系时必然有交集, 否则AND的交集需要同时满足多个条件, 无法简单确定
+	if exprOp(a) == "AND" || exprOp(b) == "AND" {
+		return covers(a, b) || covers(b, a)
+	}
+
+	return leafOverlaps(a, b)
+}
+
+func leafOverlaps(a, b ExprCell) bool {
+	if _, ok := leafField(a, b); !ok {
+		return false
+	}
+
+	aField, aPrefix, aIsPrefix := startsWithFieldValue(a)
+	bField, bPrefix, bIsPrefix := startsWithFieldValue(b)
+	switch {
+	case aIsPrefix && bIsPrefix:
+		aPrefix, bPrefix = trimPathWildcard(aField, aPrefix), trimPathWildcard(bField, bPrefix)
+		return strings.HasPrefix(aPrefix, bPrefix) || strings.HasPrefix(bPrefix, aPrefix)
+	case aIsPrefix || bIsPrefix:
+		// 前缀与eq/in存在交集, 等价于前缀覆盖了其中某个值
+		prefixExpr, valuesExpr := a, b
+		if bIsPrefix {
+			prefixExpr, valuesExpr = b, a
+		}
+		values, ok := leafValues(valuesExpr)
+		if !ok {
+			return false
+		}
+		for _, v := range values {
+			if leafCovers(prefixExpr, ExprCell{"op": "eq", "field": valuesExpr["field"], "value": v}) {
+				return true
+			}
+		}
+		return false
+	}
+
+	aValues, aOK := leafValues(a)
+	bValues, bOK := leafValues(b)
+	if !aOK || !bOK {
+		return false
+	}
+	seen := make(map[string]struct{}, len(aValues))
+	for _, v := range aValues {
+		seen[exprKey(v)] = struct{}{}
+	}
+	for _, v := range bValues {
+		if _, ok := seen[exprKey(v)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// leafField 返回两个叶子节点相同的field
+func leafField(a, b ExprCell) (string, bool) {
+	aField, ok := a["field"].(string)
+	if !ok {
+		return "", false
+	}
+	bField, ok := b["field"].(string)
+	if !ok || aField != bField {
+		return "", false
+	}
+	return aField, true
+}
+
+// leafValues 获取eq/in节点的值列表
+func leafValues(expr ExprCell) ([]interface{}, bool) {
+	switch exprOp(expr) {
+	case "eq":
+		return []interface{}{expr["value"]}, true
+	case "in":
+		values, ok := expr["value"].([]interface{})
+		return values, ok
+	default:
+		return nil, false
+	}
+}
+
+// trimPathWildcard 与condition中的逻辑一致, _bk_iam_path_ 的 /biz,1/set,*/ 等价于前缀 /biz,1/set,
+func trimPathWildcard(field, prefix string) string {
+	if strings.HasSuffix(field, abacTypes.IamPathSuffix) && strings.HasSuffix(prefix, ",*/") {
+		return prefix[0 : len(prefix)-2]
+	}
+	return prefix
+}
@@ -0,0 +1,95 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package translate
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("Subsume", func() {
+	anyExpr := ExprCell{"op": "any", "field": "host.id", "value": []interface{}{}}
+	eq1 := ExprCell{"op": "eq", "field": "host.id", "value": "1"}
+	in12 := ExprCell{"op": "in", "field": "host.id", "value": []interface{}{"1", "2"}}
+	in23 := ExprCell{"op": "in", "field": "host.id", "value": []interface{}{"2", "3"}}
+	biz1 := ExprCell{"op": "starts_with", "field": "host._bk_iam_path_", "value": "/biz,1/"}
+	set2 := ExprCell{"op": "starts_with", "field": "host._bk_iam_path_", "value": "/biz,1/set,2/"}
+	biz2 := ExprCell{"op": "starts_with", "field": "host._bk_iam_path_", "value": "/biz,2/"}
+	os := ExprCell{"op": "eq", "field": "host.os", "value": "linux"}
+
+	Describe("Covers", func() {
+		It("any", func() {
+			assert.True(GinkgoT(), Covers(anyExpr, biz1))
+			assert.True(GinkgoT(), Covers(anyExpr, anyExpr))
+			assert.False(GinkgoT(), Covers(biz1, anyExpr))
+		})
+
+		It("in subset", func() {
+			assert.True(GinkgoT(), Covers(in12, eq1))
+			assert.True(GinkgoT(), Covers(in12, in12))
+			assert.False(GinkgoT(), Covers(eq1, in12))
+			assert.False(GinkgoT(), Covers(in12, in23))
+		})
+
+		It("prefix containment", func() {
+			assert.True(GinkgoT(), Covers(biz1, set2))
+			assert.False(GinkgoT(), Covers(set2, biz1))
+			assert.False(GinkgoT(), Covers(biz1, biz2))
+
+			wildcard := ExprCell{"op": "starts_with", "field": "host._bk_iam_path_", "value": "/biz,1/set,*/"}
+			assert.True(GinkgoT(), Covers(wildcard, set2))
+
+			path := ExprCell{"op": "in", "field": "host._bk_iam_path_", "value": []interface{}{"/biz,1/set,3/"}}
+			assert.True(GinkgoT(), Covers(biz1, path))
+			assert.False(GinkgoT(), Covers(biz2, path))
+		})
+
+		It("different field", func() {
+			assert.False(GinkgoT(), Covers(in12, os))
+		})
+
+		It("logical", func() {
+			assert.True(GinkgoT(), Covers(ExprCell{"op": "OR", "content": []ExprCell{biz1, in12}},
+				ExprCell{"op": "OR", "content": []ExprCell{set2, eq1}}))
+			assert.False(GinkgoT(), Covers(biz1, ExprCell{"op": "OR", "content": []ExprCell{set2, biz2}}))
+
+			assert.True(GinkgoT(), Covers(biz1, ExprCell{"op": "AND", "content": []ExprCell{set2, os}}))
+			assert.False(GinkgoT(), Covers(ExprCell{"op": "AND", "content": []ExprCell{biz1, os}}, set2))
+			assert.True(GinkgoT(), Covers(
+				ExprCell{"op": "AND", "content": []ExprCell{biz1, os}},
+				ExprCell{"op": "AND", "content": []ExprCell{set2, os}},
+			))
+		})
+	})
+
+	Describe("Overlaps", func() {
+		It("leaf", func() {
+			assert.True(GinkgoT(), Overlaps(in12, in23))
+			assert.False(GinkgoT(), Overlaps(eq1, in23))
+			assert.True(GinkgoT(), Overlaps(set2, biz1))
+			assert.False(GinkgoT(), Overlaps(biz1, biz2))
+			assert.False(GinkgoT(), Overlaps(in12, os))
+		})
+
+		It("prefix and values", func() {
+			path := ExprCell{"op": "in", "field": "host._bk_iam_path_", "value": []interface{}{"/biz,3/", "/biz,1/set,3/"}}
+			assert.True(GinkgoT(), Overlaps(path, biz1))
+			assert.False(GinkgoT(), Overlaps(biz2, path))
+		})
+
+		It("logical", func() {
+			assert.True(GinkgoT(), Overlaps(ExprCell{"op": "OR", "content": []ExprCell{biz2, eq1}}, in12))
+			assert.True(GinkgoT(), Overlaps(ExprCell{"op": "AND", "content": []ExprCell{set2, os}}, biz1))
+			assert.False(GinkgoT(), Overlaps(ExprCell{"op": "AND", "content": []ExprCell{biz1, os}}, in12))
+			assert.True(GinkgoT(), Overlaps(anyExpr, os))
+		})
+	})
+})
@@ -37,18 +37,13 @@ ObjectSet:
 
 转换给到接入系统的表达式(translate)
 
-1. 当前policy版本v1:  `{type}.{attr} eq abc`
-2. 未来policy版本v2:  `{system}.{type}.{attr} eq abc`
+1. policy版本v1(默认):  `{type}.{attr} eq abc`, withSystem=false
+2. policy版本v2:        `{system}.{type}.{attr} eq abc`, withSystem=true, 用于跨系统的操作区分资源所属的系统
 */
 
-// NOTE: currently, policy v1 should remove system from field, withSystem=False
-// TODO: when we upgrade policy to v2, we should support withSystem=True
-
 // Translate ...
 const Translate = "Translate"
 
-const defaultWithSystem = false
-
 // ExprCell 表达式基本单元
 type ExprCell map[string]interface{}
 
@@ -57,15 +52,16 @@ func (c ExprCell) Op() string {
 	return c["op"].(string)
 }
 
-// ConditionsTranslate 策略列表转换为QL表达式
+// ConditionsTranslate 策略列表转换为QL表达式, withSystem为true时表达式的field带上system(policy v2)
 func ConditionsTranslate(
 	conditions []condition.Condition,
+	withSystem bool,
 ) (map[string]interface{}, error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(Translate, "ConditionsTranslate")
 
 	content := make([]ExprCell, 0, len(conditions))
 	for _, c := range conditions {
-		condition, err := c.Translate(defaultWithSystem)
+		condition, err := c.Translate(withSystem)
 		if err != nil {
 			err = errorWrapf(err, "conditionTranslate condition=`%+v` fail", c)
 			return nil, err
@@ -84,21 +80,15 @@ func ConditionsTranslate(
 		content = append(content, condition)
 	}
 
-	// merge same field `eq` and `in`; to `in`
-	if len(content) > 1 {
-		// 合并条件中field相同, op为eq, in的条件
-		content = mergeContentField(content)
+	if len(content) == 1 {
+		return Normalize(content[0]), nil
 	}
 
-	switch len(content) {
-	case 1:
-		return content[0], nil
-	default:
-		return ExprCell{
-			"op":      "OR",
-			"content": content,
-		}, nil
-	}
+	// 化简: 展开嵌套的AND/OR, 合并相同field的eq/in, 移除被覆盖的前缀等
+	return Normalize(ExprCell{
+		"op":      "OR",
+		"content": content,
+	}), nil
 }
 
 func oldExprToCondition(expr string) (condition.Condition, error) {
@@ -171,20 +161,23 @@ func expressionToCondition(expr string) (condition.Condition, error) {
 	return oldExprToCondition(expr)
 }
 
-func PolicyExpressionTranslate(expr string) (ExprCell, error) {
+// PolicyExpressionTranslate 策略表达式转换为QL表达式, withSystem为true时表达式的field带上system(policy v2)
+func PolicyExpressionTranslate(expr string, withSystem bool) (ExprCell, error) {
 	condition, err := expressionToCondition(expr)
 	if err != nil {
 		return nil, err
 	}
-	return condition.Translate(defaultWithSystem)
+	return condition.Translate(withSystem)
 }
 
 func PolicyExpressionToCondition(expr string) (condition.Condition, error) {
 	return expressionToCondition(expr)
 }
 
+// mergeContentField 合并条件中field相同, op为eq, in的条件; 合并后的条件按field首次出现的顺序追加
 func mergeContentField(content []ExprCell) []ExprCell {
 	mergeableExprs := map[string][]ExprCell{}
+	fields := make([]string, 0, len(content))
 	newContent := make([]ExprCell, 0, len(content))
 
 	for _, expr := range content {
@@ -197,13 +190,15 @@ func mergeContentField(content []ExprCell) []ExprCell {
 				mergeableExprs[field] = exprs
 			} else {
 				mergeableExprs[field] = []ExprCell{expr}
+				fields = append(fields, field)
 			}
 		default:
 			newContent = append(newContent, expr)
 		}
 	}
 
-	for field, exprs := range mergeableExprs {
+	for _, field := range fields {
+		exprs := mergeableExprs[field]
 		if len(exprs) == 1 {
 			newContent = append(newContent, exprs[0])
 		} else {
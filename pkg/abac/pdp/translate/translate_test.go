@@ -31,7 +31,7 @@ var _ = Describe("Expression", func() {
 			It("any", func() {
 				expr, err := ConditionsTranslate([]condition.Condition{
 					condition.NewAnyCondition(),
-				})
+				}, false)
 				assert.NoError(GinkgoT(), err)
 				assert.Equal(GinkgoT(), anyExpr, expr)
 			})
@@ -39,7 +39,7 @@ var _ = Describe("Expression", func() {
 			It("not any", func() {
 				expr, err := ConditionsTranslate([]condition.Condition{
 					condition.NewBoolCondition("test", false),
-				})
+				}, false)
 				want := map[string]interface{}{"field": "test", "op": "eq", "value": false}
 				assert.NoError(GinkgoT(), err)
 				assert.Equal(GinkgoT(), want, expr)
@@ -51,7 +51,7 @@ var _ = Describe("Expression", func() {
 				expr, err := ConditionsTranslate([]condition.Condition{
 					condition.NewBoolCondition("test", false),
 					condition.NewAnyCondition(),
-				})
+				}, false)
 				assert.NoError(GinkgoT(), err)
 				assert.Equal(GinkgoT(), anyExpr, expr)
 			})
@@ -60,7 +60,7 @@ var _ = Describe("Expression", func() {
 				expr, err := ConditionsTranslate([]condition.Condition{
 					condition.NewBoolCondition("test", false),
 					condition.NewBoolCondition("test2", true),
-				})
+				}, false)
 				want := map[string]interface{}{
 					"op": "OR",
 					"content": []ExprCell{
@@ -75,6 +75,29 @@ var _ = Describe("Expression", func() {
 				// assert.True(GinkgoT(), assert.ObjectsAreEqualValues(want, expr))
 			})
 		})
+
+		Describe("withSystem", func() {
+			var conditions []condition.Condition
+			BeforeEach(func() {
+				c, err := condition.NewConditionFromPolicyCondition(map[string]map[string][]interface{}{
+					"StringEquals": {"bk_cmdb.host.id": {"1"}},
+				})
+				assert.NoError(GinkgoT(), err)
+				conditions = []condition.Condition{c}
+			})
+
+			It("v1, without system", func() {
+				expr, err := ConditionsTranslate(conditions, false)
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), "host.id", expr["field"])
+			})
+
+			It("v2, with system", func() {
+				expr, err := ConditionsTranslate(conditions, true)
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), "bk_cmdb.host.id", expr["field"])
+			})
+		})
 	})
 
 	Describe("oldExprToCondition", func() {
@@ -279,19 +302,19 @@ var _ = Describe("Expression", func() {
 		}
 
 		It("ok, any, expression=``", func() {
-			expr, err := PolicyExpressionTranslate("")
+			expr, err := PolicyExpressionTranslate("", false)
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), anyExpr, expr)
 		})
 
 		It("ok, any, expression=`[]`", func() {
-			expr, err := PolicyExpressionTranslate("[]")
+			expr, err := PolicyExpressionTranslate("[]", false)
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), anyExpr, expr)
 		})
 
 		It("fail, wrong expression", func() {
-			_, err := PolicyExpressionTranslate("123")
+			_, err := PolicyExpressionTranslate("123", false)
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "unmarshalFromString old expr fail")
 		})
@@ -304,7 +327,20 @@ var _ = Describe("Expression", func() {
 				"field": "biz.id",
 				"value": "2",
 			}
-			got, err := PolicyExpressionTranslate(resourceExpression)
+			got, err := PolicyExpressionTranslate(resourceExpression, false)
+			assert.NoError(GinkgoT(), err)
+			assert.EqualValues(GinkgoT(), want, got)
+		})
+
+		It("ok, single, with system", func() {
+			resourceExpression := `[{"system":"bk_cmdb","type":"biz","expression":{"StringEquals":{"id":["2"]}}}]`
+
+			want := ExprCell{
+				"op":    "eq",
+				"field": "bk_cmdb.biz.id",
+				"value": "2",
+			}
+			got, err := PolicyExpressionTranslate(resourceExpression, true)
 			assert.NoError(GinkgoT(), err)
 			assert.EqualValues(GinkgoT(), want, got)
 		})
@@ -312,7 +348,7 @@ var _ = Describe("Expression", func() {
 		It("fail, singleTranslate fail", func() {
 			resourceExpression := `[{"system":"bk_cmdb","type":"biz","expression":{"NotExists":{"id":["2"]}}}]`
 
-			_, err := PolicyExpressionTranslate(resourceExpression)
+			_, err := PolicyExpressionTranslate(resourceExpression, false)
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "can not support operator NotExist")
 		})
@@ -333,7 +369,7 @@ var _ = Describe("Expression", func() {
 				},
 			}
 
-			expr, err := PolicyExpressionTranslate(resourceExpression)
+			expr, err := PolicyExpressionTranslate(resourceExpression, false)
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), want, expr)
 		})
@@ -55,6 +55,15 @@ func (c *EvalContext) GetAttr(name string) (interface{}, error) {
 	return c.objSet.GetAttribute(name), nil
 }
 
+// GetObjectAttr 获取资源的属性值, _type should be {system}.{resource_type}
+func (c *EvalContext) GetObjectAttr(_type, name string) interface{} {
+	obj, exists := c.objSet.Get(_type)
+	if !exists {
+		return nil
+	}
+	return obj[name]
+}
+
 func (c *EvalContext) HasResource(_type string) bool {
 	// has {system}.{resource_type}
 	return c.objSet.Has(_type)
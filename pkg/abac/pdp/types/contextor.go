@@ -17,3 +17,9 @@ type EvalContextor interface {
 
 	HasResource(_type string) bool
 }
+
+// ObjectAttrGetter 可选接口, 使用预先拆分好的 {system}.{resource_type} 与 attr_key 获取属性值
+// NOTE: 用于编译后的条件求值, 避免每次求值都需要拆分key
+type ObjectAttrGetter interface {
+	GetObjectAttr(_type, name string) interface{}
+}
@@ -0,0 +1,489 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pdp
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+	log "github.com/sirupsen/logrus"
+
+	"iam/pkg/abac/pdp/evalctx"
+	"iam/pkg/abac/pdp/evaluation"
+	"iam/pkg/abac/prp"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service"
+	svctypes "iam/pkg/service/types"
+)
+
+// WhoCan 反查对资源实例有操作权限的用户, 并返回每个用户获得权限的路径
+// NOTE: 与Eval的求值逻辑保持一致, 只用于审计, 不要用于鉴权; 不包含超级管理员/系统管理员
+func WhoCan(r *request.Request, withoutCache bool) ([]types.WhoCanUser, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PDP, "WhoCan")
+
+	// 1. PIP查询action, 并检查请求资源与action关联的类型是否匹配
+	err := fillActionDetail(r)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidAction
+		}
+		return nil, errorWrapf(err, "Fetch action detail action=`%+v` fail", r.Action)
+	}
+
+	if !r.ValidateActionResource() {
+		return nil, errorWrapf(ErrInvalidActionResource,
+			"ValidateActionResource systemID=`%s`, actionID=`%s`, resources=`%+v` fail",
+			r.System, r.Action.ID, r.Resources)
+	}
+
+	actionPK, err := r.Action.Attribute.GetPK()
+	if err != nil {
+		return nil, errorWrapf(err, "action.Attribute.GetPK fail, action=`%+v`", r.Action)
+	}
+	actionAuthType, err := r.Action.Attribute.GetAuthType()
+	if err != nil {
+		return nil, errorWrapf(err, "action.Attribute.GetAuthType fail, action=`%+v`", r.Action)
+	}
+
+	collector := newWhoCanCollector()
+
+	// 2. ABAC策略逐条求值, 收集命中的策略
+	policies, err := prp.ListActionPolicies(actionPK, collector.now, r.Action.WithoutResourceType(), withoutCache)
+	if err != nil {
+		return nil, errorWrapf(err, "prp.ListActionPolicies actionPK=`%d` fail", actionPK)
+	}
+	matchedPolicies, err := matchActionPolicies(r, policies)
+	if err != nil {
+		return nil, errorWrapf(err, "matchActionPolicies fail, request=`%+v`", r)
+	}
+
+	// 3. 操作为rbac时, 用户组的abac策略只有用户组的授权类型包含abac时才生效
+	if actionAuthType == svctypes.AuthTypeRBAC {
+		matchedPolicies, err = filterAbacGroupPolicies(r.System, matchedPolicies)
+		if err != nil {
+			return nil, errorWrapf(err, "filterAbacGroupPolicies system=`%s` fail", r.System)
+		}
+	}
+
+	for _, policy := range matchedPolicies {
+		err = collector.addPolicy(policy)
+		if err != nil {
+			return nil, errorWrapf(err, "collector.addPolicy policy=`%+v` fail", policy)
+		}
+	}
+
+	// 4. RBAC用户组对资源实例的授权
+	if actionAuthType == svctypes.AuthTypeRBAC {
+		authorizedGroupPKSet, err := listRbacAuthorizedGroupPKs(r.System, r.Action, r.Resources, withoutCache)
+		if err != nil {
+			return nil, errorWrapf(err, "listRbacAuthorizedGroupPKs resources=`%+v` fail", r.Resources)
+		}
+
+		_, rbacGroupPKs, err := prp.SplitGroupPKsByAuthType(r.System, authorizedGroupPKSet.ToSlice())
+		if err != nil {
+			return nil, errorWrapf(err, "prp.SplitGroupPKsByAuthType system=`%s` fail", r.System)
+		}
+
+		for _, groupPK := range rbacGroupPKs {
+			err = collector.addRbacGroup(groupPK)
+			if err != nil {
+				return nil, errorWrapf(err, "collector.addRbacGroup groupPK=`%d` fail", groupPK)
+			}
+		}
+	}
+
+	users, err := collector.users()
+	if err != nil {
+		return nil, errorWrapf(err, "collector.users fail")
+	}
+	return users, nil
+}
+
+// matchActionPolicies 使用请求中的资源实例求值, 返回命中的策略
+func matchActionPolicies(r *request.Request, policies []prp.ActionPolicy) ([]prp.ActionPolicy, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	if r.HasRemoteResources() {
+		authPolicies := make([]types.AuthPolicy, 0, len(policies))
+		for _, p := range policies {
+			authPolicies = append(authPolicies, p.AuthPolicy)
+		}
+
+		err := fillRemoteResourceAttrs(r, authPolicies)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	currentTime := time.Now()
+	ctx := evalctx.NewEvalContext(r)
+
+	matchedPolicies := make([]prp.ActionPolicy, 0, len(policies))
+	for _, p := range policies {
+		isMatch, err := evaluation.MatchPolicy(ctx, p.AuthPolicy, currentTime)
+		if err != nil {
+			log.Errorf("pdp WhoCan evaluation.MatchPolicy policy id: %d fail, error: %v", p.ID, err)
+			// NOTE: 求值出错的allow策略视为不满足, deny策略视为满足, 避免deny失效
+			if p.Effect == svctypes.PolicyEffectDeny {
+				matchedPolicies = append(matchedPolicies, p)
+			}
+			continue
+		}
+
+		if isMatch {
+			matchedPolicies = append(matchedPolicies, p)
+		}
+	}
+	return matchedPolicies, nil
+}
+
+// filterAbacGroupPolicies 过滤掉授权类型不包含abac的用户组的策略
+func filterAbacGroupPolicies(system string, policies []prp.ActionPolicy) ([]prp.ActionPolicy, error) {
+	subjectPKs := make([]int64, 0, len(policies))
+	for _, p := range policies {
+		subjectPKs = append(subjectPKs, p.SubjectPK)
+	}
+
+	subjectMap, err := batchGetSubjectMap(subjectPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	groupPKs := make([]int64, 0, len(policies))
+	for _, subject := range subjectMap {
+		if subject.Type == svctypes.GroupType {
+			groupPKs = append(groupPKs, subject.PK)
+		}
+	}
+
+	abacGroupPKs, _, err := prp.SplitGroupPKsByAuthType(system, groupPKs)
+	if err != nil {
+		return nil, err
+	}
+	abacGroupPKSet := set.NewInt64SetWithValues(abacGroupPKs)
+
+	filteredPolicies := make([]prp.ActionPolicy, 0, len(policies))
+	for _, p := range policies {
+		subject, ok := subjectMap[p.SubjectPK]
+		if ok && subject.Type == svctypes.GroupType && !abacGroupPKSet.Has(p.SubjectPK) {
+			continue
+		}
+		filteredPolicies = append(filteredPolicies, p)
+	}
+	return filteredPolicies, nil
+}
+
+// groupMemberUser 用户组展开后的用户
+type groupMemberUser struct {
+	UserPK int64
+	// 通过部门加入用户组时的部门ID, 直接加入时为空
+	DepartmentID string
+	// 通过嵌套用户组加入时的下级用户组ID, 直接加入时为空
+	MemberGroupID string
+	ExpiredAt     int64
+}
+
+// whoCanCollector 收集用户获得权限的路径
+type whoCanCollector struct {
+	groupService      service.GroupService
+	departmentService service.DepartmentService
+
+	now int64
+
+	userPKs       []int64
+	userGrants    map[int64][]types.Grant
+	deniedUserPKs *set.Int64Set
+
+	groupMemberUsers map[int64][]groupMemberUser
+}
+
+func newWhoCanCollector() *whoCanCollector {
+	return &whoCanCollector{
+		groupService:      service.NewGroupService(),
+		departmentService: service.NewDepartmentService(),
+
+		now: time.Now().Unix(),
+
+		userGrants:    map[int64][]types.Grant{},
+		deniedUserPKs: set.NewInt64Set(),
+
+		groupMemberUsers: map[int64][]groupMemberUser{},
+	}
+}
+
+// addPolicy 将命中的策略展开到用户, deny策略命中的用户没有权限
+func (c *whoCanCollector) addPolicy(policy prp.ActionPolicy) error {
+	subject, err := cacheimpls.GetSubjectByPK(policy.SubjectPK)
+	if err != nil {
+		// NOTE: subject已被删除, 策略不生效
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	isDeny := policy.Effect == svctypes.PolicyEffectDeny
+
+	switch subject.Type {
+	case svctypes.UserType:
+		if isDeny {
+			c.deniedUserPKs.Add(subject.PK)
+			return nil
+		}
+
+		path := types.GrantPathDirect
+		if policy.TemplateID != 0 {
+			path = types.GrantPathTemplate
+		}
+		c.addGrant(subject.PK, types.Grant{
+			Path:       path,
+			AuthType:   svctypes.AuthTypeABACStr,
+			PolicyID:   policy.ID,
+			TemplateID: policy.TemplateID,
+			ExpiredAt:  policy.ExpiredAt,
+		})
+	case svctypes.GroupType:
+		members, err := c.listGroupMemberUsers(subject.PK)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range members {
+			if isDeny {
+				c.deniedUserPKs.Add(m.UserPK)
+				continue
+			}
+
+			grant := newGroupGrant(subject.ID, m, svctypes.AuthTypeABACStr)
+			grant.PolicyID = policy.ID
+			grant.TemplateID = policy.TemplateID
+			if policy.TemplateID != 0 {
+				grant.Path = types.GrantPathTemplate
+			}
+			if policy.ExpiredAt < grant.ExpiredAt {
+				grant.ExpiredAt = policy.ExpiredAt
+			}
+			c.addGrant(m.UserPK, grant)
+		}
+	}
+	return nil
+}
+
+// addRbacGroup 将有资源实例授权的rbac用户组展开到用户
+func (c *whoCanCollector) addRbacGroup(groupPK int64) error {
+	group, err := cacheimpls.GetSubjectByPK(groupPK)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	members, err := c.listGroupMemberUsers(groupPK)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range members {
+		c.addGrant(m.UserPK, newGroupGrant(group.ID, m, svctypes.AuthTypeRBACStr))
+	}
+	return nil
+}
+
+func newGroupGrant(groupID string, member groupMemberUser, authType string) types.Grant {
+	path := types.GrantPathGroup
+	switch {
+	case member.MemberGroupID != "":
+		path = types.GrantPathNestedGroup
+	case member.DepartmentID != "":
+		path = types.GrantPathDepartmentGroup
+	}
+
+	return types.Grant{
+		Path:          path,
+		AuthType:      authType,
+		GroupID:       groupID,
+		DepartmentID:  member.DepartmentID,
+		MemberGroupID: member.MemberGroupID,
+		ExpiredAt:     member.ExpiredAt,
+	}
+}
+
+func (c *whoCanCollector) addGrant(userPK int64, grant types.Grant) {
+	if _, ok := c.userGrants[userPK]; !ok {
+		c.userPKs = append(c.userPKs, userPK)
+	}
+	c.userGrants[userPK] = append(c.userGrants[userPK], grant)
+}
+
+// listGroupMemberUsers 查询用户组已生效且未过期的成员, 部门成员展开为部门(及包含的子部门)下的用户, 嵌套用户组展开为其成员
+func (c *whoCanCollector) listGroupMemberUsers(groupPK int64) ([]groupMemberUser, error) {
+	if users, ok := c.groupMemberUsers[groupPK]; ok {
+		return users, nil
+	}
+
+	users, err := c.listNestedGroupMemberUsers(groupPK, []int64{groupPK})
+	if err != nil {
+		return nil, err
+	}
+
+	c.groupMemberUsers[groupPK] = users
+	return users, nil
+}
+
+// listNestedGroupMemberUsers path为从有权限的用户组到groupPK的嵌套路径
+// NOTE: 与service中嵌套用户组的遍历一致, 最多展开maxGroupNestingDepth层, 数据中存在环时跳过已在路径中的用户组
+func (c *whoCanCollector) listNestedGroupMemberUsers(groupPK int64, path []int64) ([]groupMemberUser, error) {
+	members, err := c.groupService.ListGroupMember(groupPK)
+	if err != nil {
+		return nil, err
+	}
+
+	memberPKs := make([]int64, 0, len(members))
+	for _, m := range members {
+		if c.isEffectiveMember(m) {
+			memberPKs = append(memberPKs, m.SubjectPK)
+		}
+	}
+
+	subjectMap, err := batchGetSubjectMap(memberPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]groupMemberUser, 0, len(memberPKs))
+	for _, m := range members {
+		subject, ok := subjectMap[m.SubjectPK]
+		if !ok || !c.isEffectiveMember(m) {
+			continue
+		}
+
+		switch subject.Type {
+		case svctypes.UserType:
+			users = append(users, groupMemberUser{UserPK: subject.PK, ExpiredAt: m.ExpiredAt})
+		case svctypes.DepartmentType:
+			// 部门成员包含子部门时, 所有下级部门的用户也通过该部门获得权限
+			subDepartmentPKs, err := c.groupService.ListSubDepartmentMemberPKs(groupPK, []int64{subject.PK})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, departmentPK := range append([]int64{subject.PK}, subDepartmentPKs...) {
+				userPKs, err := c.departmentService.ListSubjectPKsByDepartmentPK(departmentPK)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, userPK := range userPKs {
+					users = append(users, groupMemberUser{
+						UserPK:       userPK,
+						DepartmentID: subject.ID,
+						ExpiredAt:    m.ExpiredAt,
+					})
+				}
+			}
+		case svctypes.GroupType:
+			if len(path) > service.MaxGroupNestingDepth() || containsInt64(path, subject.PK) {
+				continue
+			}
+
+			nestedPath := make([]int64, 0, len(path)+1)
+			nestedPath = append(append(nestedPath, path...), subject.PK)
+			nestedUsers, err := c.listNestedGroupMemberUsers(subject.PK, nestedPath)
+			if err != nil {
+				return nil, err
+			}
+
+			// 继承的成员关系过期时间取嵌套路径上最早的
+			for _, u := range nestedUsers {
+				if m.ExpiredAt < u.ExpiredAt {
+					u.ExpiredAt = m.ExpiredAt
+				}
+				u.MemberGroupID = subject.ID
+				users = append(users, u)
+			}
+		}
+	}
+	return users, nil
+}
+
+// isEffectiveMember 成员关系已到生效时间且未过期
+func (c *whoCanCollector) isEffectiveMember(m svctypes.GroupMember) bool {
+	return m.ExpiredAt > c.now && m.EffectiveAt <= c.now
+}
+
+// users 返回有权限的用户, 排除deny策略命中的用户与被冻结的用户
+func (c *whoCanCollector) users() ([]types.WhoCanUser, error) {
+	userPKs := make([]int64, 0, len(c.userPKs))
+	for _, pk := range c.userPKs {
+		if !c.deniedUserPKs.Has(pk) {
+			userPKs = append(userPKs, pk)
+		}
+	}
+
+	subjectMap, err := batchGetSubjectMap(userPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]types.WhoCanUser, 0, len(userPKs))
+	for _, pk := range userPKs {
+		subject, ok := subjectMap[pk]
+		if !ok || cacheimpls.IsSubjectInBlackList(subject.Type, subject.ID) {
+			continue
+		}
+
+		users = append(users, types.WhoCanUser{
+			ID:     subject.ID,
+			Name:   subject.Name,
+			Grants: c.userGrants[pk],
+		})
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+	return users, nil
+}
+
+func containsInt64(pks []int64, pk int64) bool {
+	for _, p := range pks {
+		if p == pk {
+			return true
+		}
+	}
+	return false
+}
+
+func batchGetSubjectMap(pks []int64) (map[int64]svctypes.Subject, error) {
+	subjectMap := make(map[int64]svctypes.Subject, len(pks))
+	if len(pks) == 0 {
+		return subjectMap, nil
+	}
+
+	subjects, err := cacheimpls.BatchGetSubjectByPKs(pks)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subject := range subjects {
+		subjectMap[subject.PK] = subject
+	}
+	return subjectMap, nil
+}
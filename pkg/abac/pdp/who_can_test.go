@@ -0,0 +1,298 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pdp
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/pdp/evalctx"
+	"iam/pkg/abac/pdp/evaluation"
+	"iam/pkg/abac/prp"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service"
+	"iam/pkg/service/mock"
+	svctypes "iam/pkg/service/types"
+)
+
+var _ = Describe("WhoCan", func() {
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+	var req *request.Request
+	var policies []prp.ActionPolicy
+	var subDepartmentPKs []int64
+
+	expiredAt := time.Now().Unix() + 3600
+	subjects := map[int64]svctypes.Subject{
+		1:  {PK: 1, Type: "user", ID: "alice", Name: "Alice"},
+		2:  {PK: 2, Type: "user", ID: "bob", Name: "Bob"},
+		3:  {PK: 3, Type: "user", ID: "carol", Name: "Carol"},
+		4:  {PK: 4, Type: "user", ID: "dave", Name: "Dave"},
+		5:  {PK: 5, Type: "user", ID: "eve", Name: "Eve"},
+		6:  {PK: 6, Type: "user", ID: "frank", Name: "Frank"},
+		10: {PK: 10, Type: "group", ID: "10", Name: "g1"},
+		11: {PK: 11, Type: "group", ID: "11", Name: "g2"},
+		12: {PK: 12, Type: "group", ID: "12", Name: "g3"},
+		20: {PK: 20, Type: "department", ID: "20", Name: "d1"},
+	}
+
+	patchAction := func(authType int64) {
+		patches.ApplyFunc(fillActionDetail, func(req *request.Request) error {
+			req.Action = types.NewAction()
+			req.Action.FillAttributes(123, authType, []types.ActionResourceType{{System: "test", Type: "host"}})
+			return nil
+		})
+	}
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		req = &request.Request{
+			System: "test",
+			Resources: []types.Resource{{
+				System: "test",
+				Type:   "host",
+				ID:     "1",
+			}},
+		}
+		policies = []prp.ActionPolicy{
+			{AuthPolicy: types.AuthPolicy{ID: 100, ExpiredAt: expiredAt}, SubjectPK: 1},
+			{AuthPolicy: types.AuthPolicy{ID: 101, ExpiredAt: expiredAt - 10}, SubjectPK: 10, TemplateID: 5},
+			// not match
+			{AuthPolicy: types.AuthPolicy{ID: 999, ExpiredAt: expiredAt}, SubjectPK: 4},
+		}
+
+		mockGroupService := mock.NewMockGroupService(ctl)
+		mockGroupService.EXPECT().ListGroupMember(int64(10)).Return([]svctypes.GroupMember{
+			{SubjectPK: 2, ExpiredAt: expiredAt},
+			{SubjectPK: 20, ExpiredAt: expiredAt},
+			{SubjectPK: 4, ExpiredAt: 1},
+			// not effective yet
+			{SubjectPK: 4, ExpiredAt: expiredAt, EffectiveAt: expiredAt - 100},
+			{SubjectPK: 12, ExpiredAt: expiredAt - 20},
+		}, nil).AnyTimes()
+		mockGroupService.EXPECT().ListGroupMember(int64(11)).Return([]svctypes.GroupMember{
+			{SubjectPK: 1, ExpiredAt: expiredAt},
+		}, nil).AnyTimes()
+		// nested group, and a cycle back to group 10
+		mockGroupService.EXPECT().ListGroupMember(int64(12)).Return([]svctypes.GroupMember{
+			{SubjectPK: 5, ExpiredAt: expiredAt},
+			{SubjectPK: 10, ExpiredAt: expiredAt},
+		}, nil).AnyTimes()
+		mockDepartmentService := mock.NewMockDepartmentService(ctl)
+		subDepartmentPKs = nil
+		mockGroupService.EXPECT().ListSubDepartmentMemberPKs(int64(10), []int64{20}).DoAndReturn(
+			func(groupPK int64, departmentPKs []int64) ([]int64, error) {
+				return subDepartmentPKs, nil
+			},
+		).AnyTimes()
+		mockDepartmentService.EXPECT().ListSubjectPKsByDepartmentPK(int64(20)).Return([]int64{3}, nil).AnyTimes()
+		mockDepartmentService.EXPECT().ListSubjectPKsByDepartmentPK(int64(21)).Return([]int64{6}, nil).AnyTimes()
+
+		patches = gomonkey.NewPatches()
+		patches.ApplyFunc(service.NewGroupService, func() service.GroupService {
+			return mockGroupService
+		})
+		patches.ApplyFunc(service.NewDepartmentService, func() service.DepartmentService {
+			return mockDepartmentService
+		})
+		patches.ApplyMethod(reflect.TypeOf(req), "ValidateActionResource", func(_ *request.Request) bool {
+			return true
+		})
+		patches.ApplyFunc(prp.ListActionPolicies, func(
+			actionPK, expiredAt int64, withoutResourceType, withoutCache bool,
+		) ([]prp.ActionPolicy, error) {
+			return policies, nil
+		})
+		patches.ApplyFunc(evaluation.MatchPolicy, func(
+			ctx *evalctx.EvalContext, policy types.AuthPolicy, currentTime time.Time,
+		) (bool, error) {
+			return policy.ID != 999, nil
+		})
+		patches.ApplyFunc(cacheimpls.GetSubjectByPK, func(pk int64) (svctypes.Subject, error) {
+			subject, ok := subjects[pk]
+			if !ok {
+				return subject, sql.ErrNoRows
+			}
+			return subject, nil
+		})
+		patches.ApplyFunc(cacheimpls.BatchGetSubjectByPKs, func(pks []int64) ([]svctypes.Subject, error) {
+			ss := make([]svctypes.Subject, 0, len(pks))
+			for _, pk := range pks {
+				if s, ok := subjects[pk]; ok {
+					ss = append(ss, s)
+				}
+			}
+			return ss, nil
+		})
+		patches.ApplyFunc(cacheimpls.IsSubjectInBlackList, func(_type, id string) bool {
+			return false
+		})
+	})
+	AfterEach(func() {
+		ctl.Finish()
+		patches.Reset()
+	})
+
+	It("invalid action", func() {
+		patches.ApplyFunc(fillActionDetail, func(req *request.Request) error {
+			return sql.ErrNoRows
+		})
+
+		_, err := WhoCan(req, false)
+		assert.ErrorIs(GinkgoT(), err, ErrInvalidAction)
+	})
+
+	It("abac", func() {
+		patchAction(svctypes.AuthTypeABAC)
+
+		users, err := WhoCan(req, false)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), []types.WhoCanUser{
+			{
+				ID:   "alice",
+				Name: "Alice",
+				Grants: []types.Grant{
+					{Path: types.GrantPathDirect, AuthType: "abac", PolicyID: 100, ExpiredAt: expiredAt},
+				},
+			},
+			{
+				ID:   "bob",
+				Name: "Bob",
+				Grants: []types.Grant{{
+					Path: types.GrantPathTemplate, AuthType: "abac", PolicyID: 101, TemplateID: 5,
+					GroupID: "10", ExpiredAt: expiredAt - 10,
+				}},
+			},
+			{
+				ID:   "carol",
+				Name: "Carol",
+				Grants: []types.Grant{{
+					Path: types.GrantPathTemplate, AuthType: "abac", PolicyID: 101, TemplateID: 5,
+					GroupID: "10", DepartmentID: "20", ExpiredAt: expiredAt - 10,
+				}},
+			},
+			{
+				ID:   "eve",
+				Name: "Eve",
+				Grants: []types.Grant{{
+					Path: types.GrantPathTemplate, AuthType: "abac", PolicyID: 101, TemplateID: 5,
+					GroupID: "10", MemberGroupID: "12", ExpiredAt: expiredAt - 20,
+				}},
+			},
+		}, users)
+	})
+
+	It("abac, department group", func() {
+		patchAction(svctypes.AuthTypeABAC)
+		policies[1].TemplateID = 0
+
+		users, err := WhoCan(req, false)
+		assert.NoError(GinkgoT(), err)
+		assert.Len(GinkgoT(), users, 4)
+		assert.Equal(GinkgoT(), types.GrantPathGroup, users[1].Grants[0].Path)
+		assert.Equal(GinkgoT(), types.GrantPathDepartmentGroup, users[2].Grants[0].Path)
+		assert.Equal(GinkgoT(), types.GrantPathNestedGroup, users[3].Grants[0].Path)
+	})
+
+	It("abac, include sub department", func() {
+		patchAction(svctypes.AuthTypeABAC)
+		subDepartmentPKs = []int64{21}
+
+		users, err := WhoCan(req, false)
+		assert.NoError(GinkgoT(), err)
+		assert.Len(GinkgoT(), users, 5)
+		assert.Equal(GinkgoT(), types.WhoCanUser{
+			ID:   "frank",
+			Name: "Frank",
+			Grants: []types.Grant{{
+				Path: types.GrantPathTemplate, AuthType: "abac", PolicyID: 101, TemplateID: 5,
+				GroupID: "10", DepartmentID: "20", ExpiredAt: expiredAt - 10,
+			}},
+		}, users[4])
+	})
+
+	It("deny and blacklist", func() {
+		patchAction(svctypes.AuthTypeABAC)
+		policies = append(policies, prp.ActionPolicy{
+			AuthPolicy: types.AuthPolicy{ID: 102, Effect: svctypes.PolicyEffectDeny},
+			SubjectPK:  3,
+		}, prp.ActionPolicy{
+			AuthPolicy: types.AuthPolicy{ID: 103, Effect: svctypes.PolicyEffectDeny},
+			SubjectPK:  12,
+		})
+		patches.ApplyFunc(cacheimpls.IsSubjectInBlackList, func(_type, id string) bool {
+			return id == "bob"
+		})
+
+		users, err := WhoCan(req, false)
+		assert.NoError(GinkgoT(), err)
+		assert.Len(GinkgoT(), users, 1)
+		assert.Equal(GinkgoT(), "alice", users[0].ID)
+	})
+
+	It("deny eval error", func() {
+		patchAction(svctypes.AuthTypeABAC)
+		policies = append(policies, prp.ActionPolicy{
+			AuthPolicy: types.AuthPolicy{ID: 104, Effect: svctypes.PolicyEffectDeny},
+			SubjectPK:  1,
+		})
+		patches.ApplyFunc(evaluation.MatchPolicy, func(
+			ctx *evalctx.EvalContext, policy types.AuthPolicy, currentTime time.Time,
+		) (bool, error) {
+			if policy.ID == 104 {
+				return false, errors.New("eval fail")
+			}
+			return policy.ID != 999, nil
+		})
+
+		users, err := WhoCan(req, false)
+		assert.NoError(GinkgoT(), err)
+		for _, user := range users {
+			assert.NotEqual(GinkgoT(), "alice", user.ID)
+		}
+	})
+
+	It("rbac", func() {
+		patchAction(svctypes.AuthTypeRBAC)
+		patches.ApplyFunc(prp.SplitGroupPKsByAuthType, func(
+			systemID string, groupPKs []int64,
+		) ([]int64, []int64, error) {
+			// group 10 is rbac only, group 11 is rbac only
+			return []int64{}, groupPKs, nil
+		})
+		patches.ApplyFunc(listRbacAuthorizedGroupPKs, func(
+			system string, action types.Action, resources []types.Resource, withoutCache bool,
+		) (*set.Int64Set, error) {
+			return set.NewInt64SetWithValues([]int64{11}), nil
+		})
+
+		users, err := WhoCan(req, false)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), []types.WhoCanUser{{
+			ID:   "alice",
+			Name: "Alice",
+			Grants: []types.Grant{
+				{Path: types.GrantPathDirect, AuthType: "abac", PolicyID: 100, ExpiredAt: expiredAt},
+				{Path: types.GrantPathGroup, AuthType: "rbac", GroupID: "11", ExpiredAt: expiredAt},
+			},
+		}}, users)
+	})
+})
@@ -41,3 +41,23 @@ func GetActionDetail(system, id string) (pk int64, authType int64, arts []types.
 	}
 	return detail.PK, detail.AuthType, arts, nil
 }
+
+// GetActionEnvironmentTypes 获取操作关联的环境属性类型
+func GetActionEnvironmentTypes(system, id string) ([]string, error) {
+	detail, err := cacheimpls.GetLocalActionDetail(system, id)
+	if err != nil {
+		return nil, errorx.Wrapf(err, ActionPIP, "GetActionEnvironmentTypes",
+			"cacheimpls.GetActionDetail system=`%s` actionID=`%s` fail", system, id)
+	}
+	return detail.EnvironmentTypes, nil
+}
+
+// GetActionSubjectAttributes 获取操作允许条件中使用的subject属性
+func GetActionSubjectAttributes(system, id string) ([]string, error) {
+	detail, err := cacheimpls.GetLocalActionDetail(system, id)
+	if err != nil {
+		return nil, errorx.Wrapf(err, ActionPIP, "GetActionSubjectAttributes",
+			"cacheimpls.GetActionDetail system=`%s` actionID=`%s` fail", system, id)
+	}
+	return detail.SubjectAttributes, nil
+}
@@ -68,4 +68,69 @@ var _ = Describe("Action", func() {
 			assert.Len(GinkgoT(), rts, 1)
 		})
 	})
+	Describe("GetActionEnvironmentTypes", func() {
+		var patches *gomonkey.Patches
+		AfterEach(func() {
+			patches.Reset()
+		})
+
+		It("GetLocalActionDetail fail", func() {
+			patches = gomonkey.ApplyFunc(
+				cacheimpls.GetLocalActionDetail,
+				func(system, id string) (types.ActionDetail, error) {
+					return types.ActionDetail{}, errors.New("get GetActionDetail fail")
+				},
+			)
+
+			_, err := pip.GetActionEnvironmentTypes("bk_test", "edit")
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "get GetActionDetail fail")
+		})
+
+		It("ok", func() {
+			patches = gomonkey.ApplyFunc(
+				cacheimpls.GetLocalActionDetail,
+				func(system, id string) (types.ActionDetail, error) {
+					return types.ActionDetail{PK: 123, EnvironmentTypes: []string{"client_ip"}}, nil
+				},
+			)
+
+			envTypes, err := pip.GetActionEnvironmentTypes("bk_test", "edit")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []string{"client_ip"}, envTypes)
+		})
+	})
+
+	Describe("GetActionSubjectAttributes", func() {
+		var patches *gomonkey.Patches
+		AfterEach(func() {
+			patches.Reset()
+		})
+
+		It("GetLocalActionDetail fail", func() {
+			patches = gomonkey.ApplyFunc(
+				cacheimpls.GetLocalActionDetail,
+				func(system, id string) (types.ActionDetail, error) {
+					return types.ActionDetail{}, errors.New("get GetActionDetail fail")
+				},
+			)
+
+			_, err := pip.GetActionSubjectAttributes("bk_test", "edit")
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "get GetActionDetail fail")
+		})
+
+		It("ok", func() {
+			patches = gomonkey.ApplyFunc(
+				cacheimpls.GetLocalActionDetail,
+				func(system, id string) (types.ActionDetail, error) {
+					return types.ActionDetail{PK: 123, SubjectAttributes: []string{"level"}}, nil
+				},
+			)
+
+			attrs, err := pip.GetActionSubjectAttributes("bk_test", "edit")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []string{"level"}, attrs)
+		})
+	})
 })
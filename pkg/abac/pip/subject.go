@@ -11,6 +11,7 @@
 package pip
 
 import (
+	"github.com/TencentBlueKing/gopkg/collection/set"
 	"github.com/TencentBlueKing/gopkg/errorx"
 
 	"iam/pkg/cacheimpls"
@@ -31,8 +32,9 @@ func GetSubjectPK(_type, id string) (int64, error) {
 	return pk, err
 }
 
-// GetSubjectDepartmentPKs ...
-func GetSubjectDepartmentPKs(pk int64) (departments []int64, err error) {
+// GetSubjectDepartmentPKs 获取subject的部门, 以及部门树中这些部门的所有上级部门
+// NOTE: ancestors不包括departments中已有的部门
+func GetSubjectDepartmentPKs(pk int64) (departments, ancestors []int64, err error) {
 	departments, err = cacheimpls.GetLocalSubjectDepartmentPKs(pk)
 	if err != nil {
 		err = errorx.Wrapf(err, SubjectPIP, "GetSubjectDepartmentPKs",
@@ -40,5 +42,34 @@ func GetSubjectDepartmentPKs(pk int64) (departments []int64, err error) {
 		return
 	}
 
-	return departments, err
+	pkSet := set.NewInt64SetWithValues(departments)
+	for _, departmentPK := range departments {
+		var departmentAncestors []int64
+		departmentAncestors, err = cacheimpls.GetLocalDepartmentAncestorPKs(departmentPK)
+		if err != nil {
+			err = errorx.Wrapf(err, SubjectPIP, "GetSubjectDepartmentPKs",
+				"cacheimpls.GetLocalDepartmentAncestorPKs departmentPK=`%d` fail", departmentPK)
+			return nil, nil, err
+		}
+
+		for _, ancestorPK := range departmentAncestors {
+			if !pkSet.Has(ancestorPK) {
+				pkSet.Add(ancestorPK)
+				ancestors = append(ancestors, ancestorPK)
+			}
+		}
+	}
+
+	return departments, ancestors, nil
+}
+
+// GetSubjectAttributes 获取subject的自定义属性, note this will cache in local for 1 minutes
+// NOTE: 返回的map为缓存中的共享对象, 不能修改
+func GetSubjectAttributes(pk int64) (map[string]interface{}, error) {
+	attrs, err := cacheimpls.GetLocalSubjectAttributes(pk)
+	if err != nil {
+		return nil, errorx.Wrapf(err, SubjectPIP, "GetSubjectAttributes",
+			"cacheimpls.GetLocalSubjectAttributes pk=`%d` fail", pk)
+	}
+	return attrs, nil
 }
@@ -71,19 +71,66 @@ var _ = Describe("Subject", func() {
 				return nil, errors.New("get GetLocalSubjectDepartmentPKs fail")
 			})
 
-			_, err := pip.GetSubjectDepartmentPKs(123)
+			_, _, err := pip.GetSubjectDepartmentPKs(123)
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "get GetLocalSubjectDepartmentPKs fail")
 		})
 
+		It("GetLocalDepartmentAncestorPKs fail", func() {
+			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectDepartmentPKs, func(pk int64) ([]int64, error) {
+				return []int64{1}, nil
+			})
+			patches.ApplyFunc(cacheimpls.GetLocalDepartmentAncestorPKs, func(pk int64) ([]int64, error) {
+				return nil, errors.New("get GetLocalDepartmentAncestorPKs fail")
+			})
+
+			_, _, err := pip.GetSubjectDepartmentPKs(123)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "get GetLocalDepartmentAncestorPKs fail")
+		})
+
 		It("ok", func() {
 			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectDepartmentPKs, func(pk int64) ([]int64, error) {
 				return []int64{1, 2, 3}, nil
 			})
+			// 1 -> 2 -> 4, 3 -> 5 -> 4
+			patches.ApplyFunc(cacheimpls.GetLocalDepartmentAncestorPKs, func(pk int64) ([]int64, error) {
+				return map[int64][]int64{1: {2, 4}, 3: {5, 4}}[pk], nil
+			})
 
-			depts, err := pip.GetSubjectDepartmentPKs(123)
+			depts, ancestors, err := pip.GetSubjectDepartmentPKs(123)
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), []int64{1, 2, 3}, depts)
+			assert.Equal(GinkgoT(), []int64{4, 5}, ancestors)
+		})
+	})
+
+	Describe("GetSubjectAttributes", func() {
+		var patches *gomonkey.Patches
+		AfterEach(func() {
+			patches.Reset()
+		})
+
+		It("GetLocalSubjectAttributes fail", func() {
+			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectAttributes,
+				func(pk int64) (map[string]interface{}, error) {
+					return nil, errors.New("get subject attributes fail")
+				})
+
+			_, err := pip.GetSubjectAttributes(123)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "get subject attributes fail")
+		})
+
+		It("ok", func() {
+			patches = gomonkey.ApplyFunc(cacheimpls.GetLocalSubjectAttributes,
+				func(pk int64) (map[string]interface{}, error) {
+					return map[string]interface{}{"level": float64(3)}, nil
+				})
+
+			attrs, err := pip.GetSubjectAttributes(123)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[string]interface{}{"level": float64(3)}, attrs)
 		})
 	})
 })
@@ -0,0 +1,128 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package prp
+
+import (
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/abac/prp/expression"
+	"iam/pkg/abac/types"
+	"iam/pkg/service"
+	svctypes "iam/pkg/service/types"
+)
+
+// ActionPolicyPRP ...
+const ActionPolicyPRP = "ActionPolicyPRP"
+
+const actionPolicyPageSize = 1000
+
+// ActionPolicy 操作的ABAC策略, 带上策略所属的subject, 用于反查有权限的subject
+type ActionPolicy struct {
+	types.AuthPolicy
+
+	SubjectPK  int64
+	TemplateID int64
+}
+
+// ListActionPolicies 查询操作所有未过期的ABAC策略
+// NOTE: 操作不关联资源类型时, 不查询表达式, expression为空
+func ListActionPolicies(
+	actionPK int64,
+	expiredAt int64,
+	withoutResourceType bool,
+	withoutCache bool,
+) ([]ActionPolicy, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(ActionPolicyPRP, "ListActionPolicies")
+
+	svc := service.NewOpenAbacPolicyService()
+	count, err := svc.GetCountByActionBeforeExpiredAt(actionPK, expiredAt)
+	if err != nil {
+		return nil, errorWrapf(err, "svc.GetCountByActionBeforeExpiredAt actionPK=`%d`, expiredAt=`%d` fail",
+			actionPK, expiredAt)
+	}
+	if count == 0 {
+		return []ActionPolicy{}, nil
+	}
+
+	policies := make([]svctypes.OpenAbacPolicy, 0, count)
+	for offset := int64(0); offset < count; offset += actionPolicyPageSize {
+		var ps []svctypes.OpenAbacPolicy
+		ps, err = svc.ListPagingByActionBeforeExpiredAt(actionPK, expiredAt, offset, actionPolicyPageSize)
+		if err != nil {
+			return nil, errorWrapf(err,
+				"svc.ListPagingByActionBeforeExpiredAt actionPK=`%d`, expiredAt=`%d`, offset=`%d` fail",
+				actionPK, expiredAt, offset)
+		}
+		policies = append(policies, ps...)
+	}
+
+	expressionMap := map[int64]svctypes.AuthExpression{}
+	if !withoutResourceType {
+		expressionMap, err = queryActionExpressions(actionPK, policies, withoutCache)
+		if err != nil {
+			return nil, errorWrapf(err, "queryActionExpressions actionPK=`%d` fail", actionPK)
+		}
+	}
+
+	actionPolicies := make([]ActionPolicy, 0, len(policies))
+	for _, p := range policies {
+		actionPolicies = append(actionPolicies, ActionPolicy{
+			AuthPolicy: types.AuthPolicy{
+				Version:             service.PolicyVersion,
+				ID:                  p.PK,
+				Expression:          expressionMap[p.ExpressionPK].Expression,
+				ExpressionSignature: expressionMap[p.ExpressionPK].Signature,
+				ExpiredAt:           p.ExpiredAt,
+				Effect:              p.Effect,
+			},
+			SubjectPK:  p.SubjectPK,
+			TemplateID: p.TemplateID,
+		})
+	}
+	return actionPolicies, nil
+}
+
+func queryActionExpressions(
+	actionPK int64,
+	policies []svctypes.OpenAbacPolicy,
+	withoutCache bool,
+) (map[int64]svctypes.AuthExpression, error) {
+	expressionPKs := make([]int64, 0, len(policies))
+	expressionPKSet := set.NewFixedLengthInt64Set(len(policies))
+	for _, p := range policies {
+		if p.ExpressionPK != AnyExpressionPK && !expressionPKSet.Has(p.ExpressionPK) {
+			expressionPKSet.Add(p.ExpressionPK)
+			expressionPKs = append(expressionPKs, p.ExpressionPK)
+		}
+	}
+
+	expressionMap := make(map[int64]svctypes.AuthExpression, len(expressionPKs))
+	if len(expressionPKs) == 0 {
+		return expressionMap, nil
+	}
+
+	var expressions []svctypes.AuthExpression
+	var err error
+	if withoutCache {
+		expressions, err = service.NewPolicyService().ListExpressionByPKs(expressionPKs)
+	} else {
+		expressions, err = expression.GetExpressionsFromCache(actionPK, expressionPKs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range expressions {
+		expressionMap[e.PK] = e
+	}
+	return expressionMap, nil
+}
@@ -43,7 +43,11 @@ type EnginePolicy struct {
 	Expression map[string]interface{}
 	TemplateID int64
 	ExpiredAt  int64
-	UpdatedAt  int64
+	// 策略生效时间, 0表示立即生效; rbac policy is always 0
+	EffectiveAt int64
+	UpdatedAt   int64
+	// rbac policy is always allow
+	Effect int64
 }
 
 type EnginePolicyManager interface {
@@ -161,7 +165,8 @@ func convertAbacPoliciesToEnginePolicies(
 			expressionPKs = append(expressionPKs, p.ExpressionPK)
 		}
 	}
-	pkExpressionMap, err := queryAndTranslateExpressions(expressionPKs)
+	// NOTE: 引擎使用policy v1
+	pkExpressionMap, err := queryAndTranslateExpressions(expressionPKs, false)
 	if err != nil {
 		err = fmt.Errorf("translateExpressions expressionPKs=`%+v` fail. err=%w", expressionPKs, err)
 		return
@@ -179,14 +184,16 @@ func convertAbacPoliciesToEnginePolicies(
 		}
 
 		enginePolicies = append(enginePolicies, EnginePolicy{
-			Version:    service.PolicyVersion,
-			ID:         p.PK,
-			ActionPKs:  []int64{p.ActionPK},
-			SubjectPK:  p.SubjectPK,
-			Expression: expression,
-			TemplateID: p.TemplateID,
-			ExpiredAt:  p.ExpiredAt,
-			UpdatedAt:  p.UpdatedAt.Unix(),
+			Version:     service.PolicyVersion,
+			ID:          p.PK,
+			ActionPKs:   []int64{p.ActionPK},
+			SubjectPK:   p.SubjectPK,
+			Expression:  expression,
+			TemplateID:  p.TemplateID,
+			ExpiredAt:   p.ExpiredAt,
+			EffectiveAt: p.EffectiveAt,
+			UpdatedAt:   p.UpdatedAt.Unix(),
+			Effect:      p.Effect,
 		})
 	}
 	return enginePolicies, nil
@@ -216,22 +223,50 @@ func convertRbacPoliciesToEnginePolicies(policies []types.EngineRbacPolicy) ([]E
 }
 
 func constructRbacPolicyExpr(p types.EngineRbacPolicy) (exprCell map[string]interface{}, err error) {
-	action_rt, err := cacheimpls.GetThinResourceType(p.ActionRelatedResourceTypePK)
+	exprCell, err = constructRbacResourceExpr(p.ActionRelatedResourceTypePK, p.ResourceTypePK, p.ResourceID)
+	if err != nil || len(p.RelatedResources) == 0 {
+		return
+	}
+
+	// 操作关联多个资源类型, 授权的资源实例组合需要同时满足
+	content := make([]translate.ExprCell, 0, 1+len(p.RelatedResources))
+	content = append(content, exprCell)
+	for _, rr := range p.RelatedResources {
+		var cell translate.ExprCell
+		cell, err = constructRbacResourceExpr(rr.ActionRelatedResourceTypePK, rr.ResourceTypePK, rr.ResourceID)
+		if err != nil {
+			return
+		}
+		content = append(content, cell)
+	}
+
+	exprCell = translate.ExprCell{
+		"op":      "AND",
+		"content": content,
+	}
+	return exprCell, nil
+}
+
+func constructRbacResourceExpr(
+	actionRelatedResourceTypePK, resourceTypePK int64,
+	resourceID string,
+) (exprCell translate.ExprCell, err error) {
+	action_rt, err := cacheimpls.GetThinResourceType(actionRelatedResourceTypePK)
 	if err != nil {
 		return
 	}
 
-	if p.ActionRelatedResourceTypePK == p.ResourceTypePK {
+	if actionRelatedResourceTypePK == resourceTypePK {
 		// pipeline.id eq 123
 		exprCell = translate.ExprCell{
 			"op":    "eq",
 			"field": action_rt.ID + ".id",
-			"value": p.ResourceID,
+			"value": resourceID,
 		}
 	} else {
 		// pipeline._bk_iam_path_ string_contains "/project,1/"
 		var rt types.ThinResourceType
-		rt, err = cacheimpls.GetThinResourceType(p.ResourceTypePK)
+		rt, err = cacheimpls.GetThinResourceType(resourceTypePK)
 		if err != nil {
 			return
 		}
@@ -239,7 +274,7 @@ func constructRbacPolicyExpr(p types.EngineRbacPolicy) (exprCell map[string]inte
 		exprCell = translate.ExprCell{
 			"op":    "string_contains",
 			"field": action_rt.ID + abactypes.IamPathSuffix,
-			"value": fmt.Sprintf("/%s,%s/", rt.ID, p.ResourceID),
+			"value": fmt.Sprintf("/%s,%s/", rt.ID, resourceID),
 		}
 	}
 
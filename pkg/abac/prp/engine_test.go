@@ -0,0 +1,88 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package prp
+
+import (
+	"errors"
+
+	"github.com/agiledragon/gomonkey/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/pdp/translate"
+	"iam/pkg/cacheimpls"
+	svctypes "iam/pkg/service/types"
+)
+
+var _ = Describe("Engine", func() {
+	Describe("constructRbacPolicyExpr", func() {
+		var patches *gomonkey.Patches
+		BeforeEach(func() {
+			patches = gomonkey.ApplyFunc(cacheimpls.GetThinResourceType,
+				func(pk int64) (svctypes.ThinResourceType, error) {
+					switch pk {
+					case 1:
+						return svctypes.ThinResourceType{PK: 1, System: "test", ID: "app"}, nil
+					case 2:
+						return svctypes.ThinResourceType{PK: 2, System: "test", ID: "cluster"}, nil
+					case 3:
+						return svctypes.ThinResourceType{PK: 3, System: "test", ID: "project"}, nil
+					default:
+						return svctypes.ThinResourceType{}, errors.New("not found")
+					}
+				})
+		})
+		AfterEach(func() {
+			patches.Reset()
+		})
+
+		It("single resource", func() {
+			expr, err := constructRbacPolicyExpr(svctypes.EngineRbacPolicy{
+				ActionRelatedResourceTypePK: 1,
+				ResourceTypePK:              1,
+				ResourceID:                  "a1",
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[string]interface{}{"op": "eq", "field": "app.id", "value": "a1"}, expr)
+		})
+
+		It("related resources", func() {
+			expr, err := constructRbacPolicyExpr(svctypes.EngineRbacPolicy{
+				ActionRelatedResourceTypePK: 1,
+				ResourceTypePK:              1,
+				ResourceID:                  "a1",
+				RelatedResources: []svctypes.RelatedResource{
+					{ActionRelatedResourceTypePK: 2, ResourceTypePK: 3, ResourceID: "p1"},
+				},
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[string]interface{}{
+				"op": "AND",
+				"content": []translate.ExprCell{
+					{"op": "eq", "field": "app.id", "value": "a1"},
+					{"op": "string_contains", "field": "cluster._bk_iam_path_", "value": "/project,p1/"},
+				},
+			}, expr)
+		})
+
+		It("related resource type not found", func() {
+			_, err := constructRbacPolicyExpr(svctypes.EngineRbacPolicy{
+				ActionRelatedResourceTypePK: 1,
+				ResourceTypePK:              1,
+				ResourceID:                  "a1",
+				RelatedResources: []svctypes.RelatedResource{
+					{ActionRelatedResourceTypePK: 4, ResourceTypePK: 4, ResourceID: "x"},
+				},
+			})
+			assert.Error(GinkgoT(), err)
+		})
+	})
+})
@@ -65,9 +65,10 @@ func GetEffectGroupPKs(systemID string, subject types.Subject) ([]int64, error)
 	return groupPKSet.ToSlice(), nil
 }
 
-// queryAndTranslateExpressions translate expression to json format
+// queryAndTranslateExpressions translate expression to json format, withSystem为true时field带上system(policy v2)
 func queryAndTranslateExpressions(
 	expressionPKs []int64,
+	withSystem bool,
 ) (pkExpressionMap map[int64]map[string]interface{}, err error) {
 	// when the pk is -1, will translate to any
 	pkExpressionStrMap := map[int64]string{
@@ -95,7 +96,7 @@ func queryAndTranslateExpressions(
 		// TODO: 如何优化这里的性能?
 		// TODO: 理论上, signature一样的只需要转一次
 		// e.Signature
-		translatedExpr, err1 := translate.PolicyExpressionTranslate(expr)
+		translatedExpr, err1 := translate.PolicyExpressionTranslate(expr, withSystem)
 		if err1 != nil {
 			err = fmt.Errorf("translate.PolicyExpressionTranslate expr=`%s` fail. err=%w", expr, err1)
 			return
@@ -48,7 +48,7 @@ var _ = Describe("Helper", func() {
 		})
 
 		It("subject GetDepartmentPKs fail", func() {
-			s.FillAttributes(123, []int64{1, 2, 3})
+			s.FillAttributes(123, []int64{1, 2, 3}, nil)
 			s.Attribute.Delete(types.DeptAttrName)
 
 			_, err := GetEffectGroupPKs("test", s)
@@ -61,7 +61,7 @@ var _ = Describe("Helper", func() {
 				func(systemID string, pks []int64) ([]svctypes.ThinSubjectGroup, error) {
 					return nil, errors.New("list subject_group fail")
 				})
-			s.FillAttributes(123, []int64{1, 2, 3})
+			s.FillAttributes(123, []int64{1, 2, 3}, nil)
 			_, err := GetEffectGroupPKs("test", s)
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "ListSubjectEffectGroups")
@@ -90,7 +90,7 @@ var _ = Describe("Helper", func() {
 					}, nil
 				})
 
-			s.FillAttributes(123, []int64{1, 2, 3})
+			s.FillAttributes(123, []int64{1, 2, 3}, nil)
 			pks, err := GetEffectGroupPKs("test", s)
 			assert.NoError(GinkgoT(), err)
 
@@ -98,4 +98,18 @@ var _ = Describe("Helper", func() {
 			assert.ElementsMatch(GinkgoT(), []int64{5, 6, 7}, pks)
 		})
 	})
+
+	Describe("filterPendingPolicies", func() {
+		It("ok", func() {
+			now := time.Now().Unix()
+			policies := filterPendingPolicies([]svctypes.AuthPolicy{
+				{PK: 1},
+				{PK: 2, EffectiveAt: now - 10},
+				{PK: 3, EffectiveAt: now + 10},
+			}, now)
+			assert.Len(GinkgoT(), policies, 2)
+			assert.Equal(GinkgoT(), int64(1), policies[0].PK)
+			assert.Equal(GinkgoT(), int64(2), policies[1].PK)
+		})
+	})
 })
@@ -35,24 +35,24 @@ func (m *MockOpenPolicyManager) EXPECT() *MockOpenPolicyManagerMockRecorder {
 }
 
 // Get mocks base method.
-func (m *MockOpenPolicyManager) Get(_type string, pk int64) (prp.OpenPolicy, error) {
+func (m *MockOpenPolicyManager) Get(_type string, pk int64, policyVersion string) (prp.OpenPolicy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", _type, pk)
+	ret := m.ctrl.Call(m, "Get", _type, pk, policyVersion)
 	ret0, _ := ret[0].(prp.OpenPolicy)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockOpenPolicyManagerMockRecorder) Get(_type, pk interface{}) *gomock.Call {
+func (mr *MockOpenPolicyManagerMockRecorder) Get(_type, pk, policyVersion interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockOpenPolicyManager)(nil).Get), _type, pk)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockOpenPolicyManager)(nil).Get), _type, pk, policyVersion)
 }
 
 // List mocks base method.
-func (m *MockOpenPolicyManager) List(_type string, actionPK, expiredAt, offset, limit int64) (int64, []prp.OpenPolicy, error) {
+func (m *MockOpenPolicyManager) List(_type string, actionPK, expiredAt, offset, limit int64, policyVersion string) (int64, []prp.OpenPolicy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", _type, actionPK, expiredAt, offset, limit)
+	ret := m.ctrl.Call(m, "List", _type, actionPK, expiredAt, offset, limit, policyVersion)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].([]prp.OpenPolicy)
 	ret2, _ := ret[2].(error)
@@ -60,9 +60,9 @@ func (m *MockOpenPolicyManager) List(_type string, actionPK, expiredAt, offset,
 }
 
 // List indicates an expected call of List.
-func (mr *MockOpenPolicyManagerMockRecorder) List(_type, actionPK, expiredAt, offset, limit interface{}) *gomock.Call {
+func (mr *MockOpenPolicyManagerMockRecorder) List(_type, actionPK, expiredAt, offset, limit, policyVersion interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOpenPolicyManager)(nil).List), _type, actionPK, expiredAt, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOpenPolicyManager)(nil).List), _type, actionPK, expiredAt, offset, limit, policyVersion)
 }
 
 // ListSubjects mocks base method.
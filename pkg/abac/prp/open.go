@@ -35,9 +35,12 @@ type OpenPolicy struct {
 }
 
 // OpenPolicyManager for /api/v1/open/systems/:system_id/policies api
+// policyVersion为返回的策略表达式版本, 见service.PolicyVersion
 type OpenPolicyManager interface {
-	Get(_type string, pk int64) (OpenPolicy, error)
-	List(_type string, actionPK int64, expiredAt int64, offset, limit int64) (int64, []OpenPolicy, error)
+	Get(_type string, pk int64, policyVersion string) (OpenPolicy, error)
+	List(
+		_type string, actionPK int64, expiredAt int64, offset, limit int64, policyVersion string,
+	) (int64, []OpenPolicy, error)
 	ListSubjects(_type string, systemID string, pks []int64) (map[int64]int64, error)
 }
 
@@ -55,7 +58,8 @@ func NewOpenPolicyManager() OpenPolicyManager {
 
 var ErrPolicyNotFound = errors.New("policy not found")
 
-func (m *openPolicyManager) Get(_type string, pk int64) (openPolicy OpenPolicy, err error) {
+func (m *openPolicyManager) Get(_type string, pk int64, policyVersion string) (openPolicy OpenPolicy, err error) {
+	withSystem := service.IsPolicyVersionWithSystem(policyVersion)
 	switch _type {
 	case PolicyTypeAbac:
 		// 1. query policy
@@ -69,7 +73,7 @@ func (m *openPolicyManager) Get(_type string, pk int64) (openPolicy OpenPolicy,
 		}
 
 		// 2. get expression
-		pkExpressionMap, err := queryAndTranslateExpressions([]int64{policy.ExpressionPK})
+		pkExpressionMap, err := queryAndTranslateExpressions([]int64{policy.ExpressionPK}, withSystem)
 		if err != nil {
 			return openPolicy, err
 		}
@@ -79,7 +83,7 @@ func (m *openPolicyManager) Get(_type string, pk int64) (openPolicy OpenPolicy,
 		}
 
 		openPolicy = OpenPolicy{
-			Version:    service.PolicyVersion,
+			Version:    policyVersion,
 			ID:         policy.PK,
 			ActionPK:   policy.ActionPK,
 			SubjectPK:  policy.SubjectPK,
@@ -99,14 +103,14 @@ func (m *openPolicyManager) Get(_type string, pk int64) (openPolicy OpenPolicy,
 			return openPolicy, err
 		}
 
-		translatedExpr, err1 := translate.PolicyExpressionTranslate(policy.Expression)
+		translatedExpr, err1 := translate.PolicyExpressionTranslate(policy.Expression, withSystem)
 		if err1 != nil {
 			err = fmt.Errorf("translate.PolicyExpressionTranslate expr=`%s` fail. err=%w", policy.Expression, err1)
 			return openPolicy, err
 		}
 
 		return OpenPolicy{
-			Version:    service.PolicyVersion,
+			Version:    policyVersion,
 			ID:         realPKToOutputRbacPolicyPK(policy.PK),
 			ActionPK:   policy.ActionPK,
 			SubjectPK:  policy.SubjectPK,
@@ -124,6 +128,7 @@ func (m *openPolicyManager) List(
 	actionPK int64,
 	expiredAt int64,
 	offset, limit int64,
+	policyVersion string,
 ) (count int64, policies []OpenPolicy, err error) {
 	switch _type {
 	case PolicyTypeAbac:
@@ -156,7 +161,7 @@ func (m *openPolicyManager) List(
 			return 0, nil, err
 		}
 
-		policies, err = convertAbacPoliciesToOpenPolicies(abacPolicies)
+		policies, err = convertAbacPoliciesToOpenPolicies(abacPolicies, policyVersion)
 		if err != nil {
 			err = fmt.Errorf(
 				"convertAbacPoliciesToOpenPolicies abacPolicies=`%+v` fail. err=%w",
@@ -195,7 +200,7 @@ func (m *openPolicyManager) List(
 			return 0, nil, err
 		}
 
-		policies, err = convertRbacPoliciesToOpenPolicies(rbacPolicies)
+		policies, err = convertRbacPoliciesToOpenPolicies(rbacPolicies, policyVersion)
 		if err != nil {
 			err = fmt.Errorf(
 				"convertRbacPoliciesToOpenPolicies rbacPolicies=`%+v` fail. err=%w",
@@ -282,6 +287,7 @@ func (m *openPolicyManager) ListSubjects(
 
 func convertAbacPoliciesToOpenPolicies(
 	policies []svctypes.OpenAbacPolicy,
+	policyVersion string,
 ) (openPolicies []OpenPolicy, err error) {
 	if len(policies) == 0 {
 		return
@@ -296,7 +302,7 @@ func convertAbacPoliciesToOpenPolicies(
 	}
 
 	// 2. query expression from cache
-	pkExpressionMap, err := queryAndTranslateExpressions(expressionPKs)
+	pkExpressionMap, err := queryAndTranslateExpressions(expressionPKs, service.IsPolicyVersionWithSystem(policyVersion))
 	if err != nil {
 		err = fmt.Errorf("translateExpressions expressionPKs=`%+v` fail. err=%w", expressionPKs, err)
 		return
@@ -315,7 +321,7 @@ func convertAbacPoliciesToOpenPolicies(
 		}
 
 		openPolicies = append(openPolicies, OpenPolicy{
-			Version:    service.PolicyVersion,
+			Version:    policyVersion,
 			ID:         p.PK,
 			ActionPK:   p.ActionPK,
 			SubjectPK:  p.SubjectPK,
@@ -328,21 +334,24 @@ func convertAbacPoliciesToOpenPolicies(
 
 func convertRbacPoliciesToOpenPolicies(
 	policies []svctypes.OpenRbacPolicy,
+	policyVersion string,
 ) (openPolicies []OpenPolicy, err error) {
 	if len(policies) == 0 {
 		return
 	}
 
+	withSystem := service.IsPolicyVersionWithSystem(policyVersion)
+
 	// loop policies to build openPolicies
 	for _, p := range policies {
-		translatedExpr, err1 := translate.PolicyExpressionTranslate(p.Expression)
+		translatedExpr, err1 := translate.PolicyExpressionTranslate(p.Expression, withSystem)
 		if err1 != nil {
 			log.WithError(err1).Errorf("translate.PolicyExpressionTranslate expr=`%s` fail", p.Expression)
 			continue
 		}
 
 		openPolicies = append(openPolicies, OpenPolicy{
-			Version:    service.PolicyVersion,
+			Version:    policyVersion,
 			ID:         realPKToOutputRbacPolicyPK(p.PK),
 			ActionPK:   p.ActionPK,
 			SubjectPK:  p.SubjectPK,
@@ -0,0 +1,47 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package prp
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/service"
+	svctypes "iam/pkg/service/types"
+)
+
+var _ = Describe("Open", func() {
+	Describe("convertRbacPoliciesToOpenPolicies", func() {
+		policies := []svctypes.OpenRbacPolicy{{
+			PK:         1,
+			SubjectPK:  2,
+			ActionPK:   3,
+			Expression: `[{"system":"bk_cmdb","type":"host","expression":{"StringEquals":{"id":["1"]}}}]`,
+			ExpiredAt:  4102444800,
+		}}
+
+		It("v1", func() {
+			openPolicies, err := convertRbacPoliciesToOpenPolicies(policies, service.PolicyVersion)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), openPolicies, 1)
+			assert.Equal(GinkgoT(), "1", openPolicies[0].Version)
+			assert.Equal(GinkgoT(), "host.id", openPolicies[0].Expression["field"])
+		})
+
+		It("v2", func() {
+			openPolicies, err := convertRbacPoliciesToOpenPolicies(policies, service.PolicyVersionV2)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), openPolicies, 1)
+			assert.Equal(GinkgoT(), "2", openPolicies[0].Version)
+			assert.Equal(GinkgoT(), "bk_cmdb.host.id", openPolicies[0].Expression["field"])
+		})
+	})
+})
@@ -50,9 +50,18 @@ func convertToAuthPolicy(svcPolicy svctypes.AuthPolicy, svcExpression svctypes.A
 		Expression:          svcExpression.Expression,
 		ExpressionSignature: svcExpression.Signature,
 		ExpiredAt:           svcPolicy.ExpiredAt,
+		Effect:              svcPolicy.Effect,
 	}
 }
 
+// policyUniqueKey 用于策略去重, 相同表达式的allow与deny策略不能合并
+func policyUniqueKey(p types.AuthPolicy) string {
+	if p.Effect == svctypes.PolicyEffectDeny {
+		return svctypes.PolicyEffectDenyStr + ":" + p.ExpressionSignature
+	}
+	return p.ExpressionSignature
+}
+
 func reportTooLargeQueryArguments(queryType string, count int, system, actionID, subjectType, subjectID string) {
 	if count < tooLargeThreshold {
 		return
@@ -151,8 +160,9 @@ func (m *policyManager) ListBySubjectAction(
 
 	effectPolicies = make([]types.AuthPolicy, 0, len(policies)*2)
 	for _, p := range policies {
-		if !signatureSet.Has(p.ExpressionSignature) {
-			signatureSet.Add(p.ExpressionSignature)
+		key := policyUniqueKey(p)
+		if !signatureSet.Has(key) {
+			signatureSet.Add(key)
 			effectPolicies = append(effectPolicies, p)
 		}
 	}
@@ -168,8 +178,9 @@ func (m *policyManager) ListBySubjectAction(
 	debug.WithValue(entry, "temporaryPolicies", temporaryPolicies)
 
 	for _, p := range temporaryPolicies {
-		if !signatureSet.Has(p.ExpressionSignature) {
-			signatureSet.Add(p.ExpressionSignature)
+		key := policyUniqueKey(p)
+		if !signatureSet.Has(key) {
+			signatureSet.Add(key)
 			effectPolicies = append(effectPolicies, p)
 		}
 	}
@@ -198,8 +209,9 @@ func (m *policyManager) ListBySubjectAction(
 		debug.WithValue(entry, "rbacPolicies", rbacPolicies)
 
 		for _, p := range rbacPolicies {
-			if !signatureSet.Has(p.ExpressionSignature) {
-				signatureSet.Add(p.ExpressionSignature)
+			key := policyUniqueKey(p)
+			if !signatureSet.Has(key) {
+				signatureSet.Add(key)
 				effectPolicies = append(effectPolicies, p)
 			}
 		}
@@ -263,6 +275,8 @@ func (m *policyManager) listBySubjectAction(
 			return
 		}
 	}
+	// NOTE: 未到生效时间的策略也会被缓存, 这里需要过滤掉
+	effectPolicies = filterPendingPolicies(effectPolicies, time.Now().Unix())
 	debug.WithValue(entry, "effectPolicies", effectPolicies)
 
 	// if no effect policies, return
@@ -275,7 +289,14 @@ func (m *policyManager) listBySubjectAction(
 	if action.WithoutResourceType() {
 		debug.WithValue(entry, "without_resource_types", true)
 		// only return the first policy with empty expression, will auth=True or policy=Any
+		// NOTE: if got any deny policy, return it first, will auth=False (deny-overrides)
 		policy := effectPolicies[0]
+		for _, p := range effectPolicies {
+			if p.Effect == svctypes.PolicyEffectDeny {
+				policy = p
+				break
+			}
+		}
 
 		// NOTE: the expression will be ""
 		// TODO: ? should be "" or "[]"?
@@ -522,3 +543,14 @@ func (m *policyManager) GetExpressionsFromCache(
 ) ([]svctypes.AuthExpression, error) {
 	return expression.GetExpressionsFromCache(actionPK, expressionPKs)
 }
+
+// filterPendingPolicies 过滤掉未到生效时间的策略
+func filterPendingPolicies(policies []svctypes.AuthPolicy, nowUnix int64) []svctypes.AuthPolicy {
+	effectPolicies := make([]svctypes.AuthPolicy, 0, len(policies))
+	for _, p := range policies {
+		if p.EffectiveAt <= nowUnix {
+			effectPolicies = append(effectPolicies, p)
+		}
+	}
+	return effectPolicies
+}
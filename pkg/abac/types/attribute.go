@@ -149,6 +149,48 @@ func (a *ActionAttribute) SetResourceTypes(resourceTypes []ActionResourceType) {
 	a.Set(ResourceTypeAttrName, resourceTypes)
 }
 
+// GetEnvironmentTypes 获取操作关联的环境属性类型
+func (a *ActionAttribute) GetEnvironmentTypes() ([]string, error) {
+	key := EnvTypeAttrName
+
+	actionEnvTypes, ok := a.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("key %s not exists", key)
+	}
+
+	envTypes, ok := actionEnvTypes.([]string)
+	if !ok {
+		return nil, fmt.Errorf("value %+v of key %s can not convert to []string", actionEnvTypes, key)
+	}
+	return envTypes, nil
+}
+
+// SetEnvironmentTypes 设置操作关联的环境属性类型
+func (a *ActionAttribute) SetEnvironmentTypes(envTypes []string) {
+	a.Set(EnvTypeAttrName, envTypes)
+}
+
+// GetSubjectAttributeTypes 获取操作允许条件中使用的subject属性
+func (a *ActionAttribute) GetSubjectAttributeTypes() ([]string, error) {
+	key := SubjectAttrTypeName
+
+	actionSubjectAttrTypes, ok := a.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("key %s not exists", key)
+	}
+
+	subjectAttrTypes, ok := actionSubjectAttrTypes.([]string)
+	if !ok {
+		return nil, fmt.Errorf("value %+v of key %s can not convert to []string", actionSubjectAttrTypes, key)
+	}
+	return subjectAttrTypes, nil
+}
+
+// SetSubjectAttributeTypes 设置操作允许条件中使用的subject属性
+func (a *ActionAttribute) SetSubjectAttributeTypes(subjectAttrTypes []string) {
+	a.Set(SubjectAttrTypeName, subjectAttrTypes)
+}
+
 // SubjectAttribute subject 的属性
 type SubjectAttribute struct {
 	Attribute
@@ -180,3 +222,29 @@ func (a *SubjectAttribute) GetDepartments() ([]int64, error) {
 func (a *SubjectAttribute) SetDepartments(department []int64) {
 	a.Set(DeptAttrName, department)
 }
+
+// GetAncestorDepartments 获取subject所属部门的所有上级部门
+func (a *SubjectAttribute) GetAncestorDepartments() ([]int64, error) {
+	return a.GetInt64Slice(AncestorDeptAttrName)
+}
+
+// SetAncestorDepartments 设置上级部门
+func (a *SubjectAttribute) SetAncestorDepartments(departments []int64) {
+	a.Set(AncestorDeptAttrName, departments)
+}
+
+// GetAttributes 获取subject的自定义属性, 用于条件中的 _bk_iam_subject_
+func (a *SubjectAttribute) GetAttributes() (map[string]interface{}, bool) {
+	attrs, ok := a.Get(SubjectAttrsAttrName)
+	if !ok {
+		return nil, false
+	}
+
+	values, ok := attrs.(map[string]interface{})
+	return values, ok
+}
+
+// SetAttributes 设置subject的自定义属性
+func (a *SubjectAttribute) SetAttributes(attrs map[string]interface{}) {
+	a.Set(SubjectAttrsAttrName, attrs)
+}
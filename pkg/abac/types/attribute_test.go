@@ -191,6 +191,43 @@ var _ = Describe("attribute", func() {
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), expectedRt, v)
 		})
+
+		It("GetEnvironmentTypes", func() {
+			_, err := a.GetEnvironmentTypes()
+			assert.Error(GinkgoT(), err)
+
+			a.Attribute[types.EnvTypeAttrName] = int64(1)
+			_, err = a.GetEnvironmentTypes()
+			assert.Error(GinkgoT(), err)
+
+			a.Attribute[types.EnvTypeAttrName] = []string{"client_ip"}
+			envTypes, err := a.GetEnvironmentTypes()
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []string{"client_ip"}, envTypes)
+		})
+
+		It("SetEnvironmentTypes", func() {
+			a.SetEnvironmentTypes([]string{"client_ip", "device"})
+			assert.True(GinkgoT(), a.Has(types.EnvTypeAttrName))
+
+			v, err := a.GetEnvironmentTypes()
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []string{"client_ip", "device"}, v)
+		})
+
+		It("SubjectAttributeTypes", func() {
+			_, err := a.GetSubjectAttributeTypes()
+			assert.Error(GinkgoT(), err)
+
+			a.Attribute[types.SubjectAttrTypeName] = int64(1)
+			_, err = a.GetSubjectAttributeTypes()
+			assert.Error(GinkgoT(), err)
+
+			a.SetSubjectAttributeTypes([]string{"level"})
+			v, err := a.GetSubjectAttributeTypes()
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []string{"level"}, v)
+		})
 	})
 
 	Describe("SubjectAttribute", func() {
@@ -246,5 +283,28 @@ var _ = Describe("attribute", func() {
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), []int64{1, 2, 3}, v)
 		})
+
+		It("SetAncestorDepartments", func() {
+			a.SetAncestorDepartments([]int64{4, 5})
+
+			assert.True(GinkgoT(), a.Has(types.AncestorDeptAttrName))
+			v, err := a.GetAncestorDepartments()
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []int64{4, 5}, v)
+		})
+
+		It("Attributes", func() {
+			_, ok := a.GetAttributes()
+			assert.False(GinkgoT(), ok)
+
+			a.Attribute[types.SubjectAttrsAttrName] = int64(1)
+			_, ok = a.GetAttributes()
+			assert.False(GinkgoT(), ok)
+
+			a.SetAttributes(map[string]interface{}{"level": float64(3)})
+			v, ok := a.GetAttributes()
+			assert.True(GinkgoT(), ok)
+			assert.Equal(GinkgoT(), map[string]interface{}{"level": float64(3)}, v)
+		})
 	})
 })
@@ -14,9 +14,13 @@ package types
 const (
 	ResourceTypeAttrName = "resource_type"
 
-	PKAttrName       = "pk"
-	AuthTypeAttrName = "auth_type"
-	DeptAttrName     = "department"
+	PKAttrName           = "pk"
+	AuthTypeAttrName     = "auth_type"
+	DeptAttrName         = "department"
+	AncestorDeptAttrName = "ancestor_department"
+	EnvTypeAttrName      = "environment_type"
+	SubjectAttrTypeName  = "subject_attribute_type"
+	SubjectAttrsAttrName = "subject_attribute"
 
 	IamPath       = "_bk_iam_path_"
 	IamPathSuffix = "." + IamPath
@@ -24,4 +28,7 @@ const (
 	IamEnv         = "_bk_iam_env_"
 	IamEnvSuffix   = "." + IamEnv
 	IamEnvTzSuffix = IamEnvSuffix + ".tz"
+
+	IamSubject       = "_bk_iam_subject_"
+	IamSubjectSuffix = "." + IamSubject
 )
@@ -0,0 +1,74 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package types
+
+// 鉴权结果解释的原因
+const (
+	ExplainReasonSubjectInBlackList  = "subject_in_blacklist"
+	ExplainReasonSubjectNotExists    = "subject_not_exists"
+	ExplainReasonSuperPermission     = "super_permission"
+	ExplainReasonNoGroupsNorPolicies = "no_groups_nor_policies"
+	ExplainReasonAllowedByRBACGroup  = "allowed_by_rbac_group"
+	ExplainReasonAllowedByPolicy     = "allowed_by_policy"
+	ExplainReasonAllowedByDelegation = "allowed_by_delegation"
+	ExplainReasonDeniedByPolicy      = "denied_by_policy"
+	ExplainReasonNoPolicyMatched     = "no_policy_matched"
+)
+
+// Explanation 鉴权结果的结构化解释
+type Explanation struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+
+	Subject    SubjectExplanation  `json:"subject"`
+	Policies   []PolicyExplanation `json:"policies"`
+	RBACGroups []GroupExplanation  `json:"rbac_groups"`
+}
+
+// SubjectExplanation subject相关的解释
+type SubjectExplanation struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+
+	InBlackList     bool `json:"in_blacklist"`
+	Exists          bool `json:"exists"`
+	SuperPermission bool `json:"super_permission"`
+	// 生效的用户组数量(包括通过部门继承的), 为0表示subject不属于任何用户组
+	EffectGroupCount int `json:"effect_group_count"`
+}
+
+// PolicyExplanation 单条ABAC策略的求值解释
+type PolicyExplanation struct {
+	ID        int64  `json:"id"`
+	Effect    string `json:"effect"`
+	ExpiredAt int64  `json:"expired_at"`
+	Expired   bool   `json:"expired"`
+	// 策略表达式是否满足, deny策略满足表示拒绝
+	Matched bool   `json:"matched"`
+	Error   string `json:"error,omitempty"`
+
+	FailedConditions []ConditionExplanation `json:"failed_conditions"`
+}
+
+// ConditionExplanation 求值不满足的条件节点
+type ConditionExplanation struct {
+	Operator string        `json:"operator"`
+	Key      string        `json:"key"`
+	Expected []interface{} `json:"expected"`
+	Actual   interface{}   `json:"actual"`
+}
+
+// GroupExplanation RBAC用户组的授权解释
+type GroupExplanation struct {
+	PK         int64  `json:"pk"`
+	ID         string `json:"id"`
+	Authorized bool   `json:"authorized"`
+}
@@ -20,4 +20,7 @@ type ResourceChangedAction struct {
 	Resource         ThinResourceNode
 	CreatedActionIDs []string
 	DeletedActionIDs []string
+
+	// 操作关联多个资源类型时, 与Resource一起授权的其它资源实例, 按操作关联资源类型的顺序
+	RelatedResources []ThinResourceNode
 }
@@ -61,6 +61,10 @@ type Policy struct {
 	Expression string
 	ExpiredAt  int64
 	TemplateID int64
+	// 策略效果: allow/deny, 默认allow
+	Effect int64
+	// 策略生效时间, 0表示立即生效
+	EffectiveAt int64
 }
 
 // SaaSPolicy ...
@@ -71,6 +75,9 @@ type SaaSPolicy struct {
 	System    string `json:"system"`
 	ActionID  string `json:"action_id"`
 	ExpiredAt int64  `json:"expired_at"`
+	Effect    string `json:"effect"`
+	// 策略生效时间, 0表示立即生效
+	EffectiveAt int64 `json:"effective_at"`
 }
 
 // AuthPolicy ...
@@ -81,6 +88,7 @@ type AuthPolicy struct {
 	Expression          string
 	ExpressionSignature string
 	ExpiredAt           int64
+	Effect              int64
 }
 
 // PolicyPKExpiredAt ...
@@ -26,6 +26,10 @@ type Request struct {
 	Subject   types.Subject
 	Action    types.Action
 	Resources []types.Resource
+	// 请求方传入的环境属性, 例如 client_ip / network_zone / device
+	Environment map[string]interface{}
+	// 返回的策略表达式field是否带上system, 即policy v2: `{system}.{type}.{attr}`
+	WithSystem bool
 }
 
 // NewRequest new request
@@ -37,6 +41,11 @@ func NewRequest() *Request {
 	}
 }
 
+// HasEnvironment 请求是否传入了环境属性
+func (r *Request) HasEnvironment() bool {
+	return len(r.Environment) > 0
+}
+
 func (r *Request) HasResources() bool {
 	return len(r.Resources) > 0
 }
@@ -76,6 +85,26 @@ func (r *Request) ValidateActionResource() bool {
 			return false
 		}
 	}
+	return r.validateActionEnvironment()
+}
+
+// validateActionEnvironment 检查鉴权传的环境属性是否都是action关联的环境属性类型
+func (r *Request) validateActionEnvironment() bool {
+	if !r.HasEnvironment() {
+		return true
+	}
+
+	envTypes, err := r.Action.Attribute.GetEnvironmentTypes()
+	if err != nil {
+		return false
+	}
+
+	envTypeSet := set.NewStringSetWithValues(envTypes)
+	for key := range r.Environment {
+		if !envTypeSet.Has(key) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -193,6 +193,41 @@ var _ = Describe("request", func() {
 			}
 			assert.False(GinkgoT(), r.ValidateActionResource())
 		})
+
+		It("ok, environment match", func() {
+			r.Resources = []types.Resource{
+				{
+					System: "bk_iam",
+					Type:   "job",
+				},
+			}
+			r.Environment = map[string]interface{}{"client_ip": "10.0.0.1"}
+			r.Action.Attribute.SetEnvironmentTypes([]string{"client_ip", "network_zone"})
+			assert.True(GinkgoT(), r.ValidateActionResource())
+		})
+
+		It("false, environment not declared", func() {
+			r.Resources = []types.Resource{
+				{
+					System: "bk_iam",
+					Type:   "job",
+				},
+			}
+			r.Environment = map[string]interface{}{"device": "pc"}
+			r.Action.Attribute.SetEnvironmentTypes([]string{"client_ip"})
+			assert.False(GinkgoT(), r.ValidateActionResource())
+		})
+
+		It("false, environment types not filled", func() {
+			r.Resources = []types.Resource{
+				{
+					System: "bk_iam",
+					Type:   "job",
+				},
+			}
+			r.Environment = map[string]interface{}{"client_ip": "10.0.0.1"}
+			assert.False(GinkgoT(), r.ValidateActionResource())
+		})
 	})
 	Describe("ValidateActionRemoteResource", func() {
 		expectedSystem := "bk_test"
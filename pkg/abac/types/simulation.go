@@ -0,0 +1,23 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package types
+
+// SimulationResult 候选策略对单个样例请求的求值结果
+type SimulationResult struct {
+	Allowed bool `json:"allowed"`
+	// 通过时为命中的allow策略, 拒绝时为命中的deny策略, -1表示没有命中任何策略
+	PolicyID int64 `json:"policy_id"`
+	// 使用请求中的资源与环境属性部分求值后, 转换得到的查询表达式
+	Expression map[string]interface{} `json:"expression"`
+	// 求值出错的策略
+	FailedPolicyIDs []int64 `json:"failed_policy_ids"`
+	Error           string  `json:"error,omitempty"`
+}
@@ -25,12 +25,18 @@ func NewSubject() Subject {
 }
 
 // FillAttributes 填充subject的属性
-func (s *Subject) FillAttributes(pk int64, departments []int64) {
+func (s *Subject) FillAttributes(pk int64, departments, ancestorDepartments []int64) {
 	s.Attribute.SetPK(pk)
 	s.Attribute.SetDepartments(departments)
+	s.Attribute.SetAncestorDepartments(ancestorDepartments)
 }
 
 // GetDepartmentPKs 获取部门PK
 func (s *Subject) GetDepartmentPKs() ([]int64, error) {
 	return s.Attribute.GetDepartments()
 }
+
+// GetAncestorDepartmentPKs 获取上级部门PK
+func (s *Subject) GetAncestorDepartmentPKs() ([]int64, error) {
+	return s.Attribute.GetAncestorDepartments()
+}
@@ -34,7 +34,8 @@ var _ = Describe("subject", func() {
 			It("ok", func() {
 				expectedPK := int64(123)
 				expectedDepts := []int64{1, 2, 3}
-				s.FillAttributes(expectedPK, expectedDepts)
+				expectedAncestorDepts := []int64{4}
+				s.FillAttributes(expectedPK, expectedDepts, expectedAncestorDepts)
 
 				pk, err := s.Attribute.GetPK()
 				assert.NoError(GinkgoT(), err)
@@ -43,6 +44,10 @@ var _ = Describe("subject", func() {
 				depts, err := s.Attribute.GetDepartments()
 				assert.NoError(GinkgoT(), err)
 				assert.Equal(GinkgoT(), expectedDepts, depts)
+
+				ancestorDepts, err := s.GetAncestorDepartmentPKs()
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), expectedAncestorDepts, ancestorDepts)
 			})
 		})
 
@@ -59,7 +64,7 @@ var _ = Describe("subject", func() {
 
 			It("empty", func() {
 				expectedDepts := []int64{1, 2, 3}
-				s.FillAttributes(123, expectedDepts)
+				s.FillAttributes(123, expectedDepts, nil)
 
 				pks, err := s.GetDepartmentPKs()
 				assert.NoError(GinkgoT(), err)
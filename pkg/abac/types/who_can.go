@@ -0,0 +1,49 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package types
+
+// 用户获得权限的路径
+const (
+	// 直接授权给用户的策略
+	GrantPathDirect = "direct"
+	// 用户是用户组的成员
+	GrantPathGroup = "group"
+	// 用户所在部门是用户组的成员
+	GrantPathDepartmentGroup = "department_group"
+	// 用户或用户所在部门通过嵌套的下级用户组加入用户组
+	GrantPathNestedGroup = "nested_group"
+	// 策略来源于权限模板, 用户直接或通过部门加入用户组
+	GrantPathTemplate = "template"
+)
+
+// WhoCanUser 对资源实例有操作权限的用户
+type WhoCanUser struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Grants []Grant `json:"grants"`
+}
+
+// Grant 用户获得权限的一条路径
+type Grant struct {
+	Path string `json:"path"`
+	// abac/rbac
+	AuthType string `json:"auth_type"`
+	// 只有abac有策略ID
+	PolicyID   int64  `json:"policy_id,omitempty"`
+	TemplateID int64  `json:"template_id,omitempty"`
+	GroupID    string `json:"group_id,omitempty"`
+	// 通过部门加入用户组时的部门
+	DepartmentID string `json:"department_id,omitempty"`
+	// 通过嵌套用户组加入时, 用户组中直接包含的下级用户组
+	MemberGroupID string `json:"member_group_id,omitempty"`
+	// 策略与用户组成员关系的过期时间, 取最早的
+	ExpiredAt int64 `json:"expired_at"`
+}
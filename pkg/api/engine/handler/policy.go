@@ -20,6 +20,7 @@ import (
 	"iam/pkg/abac/prp"
 	"iam/pkg/cacheimpls"
 	"iam/pkg/service"
+	svctypes "iam/pkg/service/types"
 	"iam/pkg/util"
 )
 
@@ -205,10 +206,12 @@ func convertEnginePoliciesToResponse(
 				ID:   subj.ID,
 				Name: subj.Name,
 			},
-			Expression: p.Expression,
-			TemplateID: p.TemplateID,
-			ExpiredAt:  p.ExpiredAt,
-			UpdatedAt:  p.UpdatedAt,
+			Expression:  p.Expression,
+			TemplateID:  p.TemplateID,
+			Effect:      svctypes.ConvertToPolicyEffectStr(p.Effect),
+			ExpiredAt:   p.ExpiredAt,
+			EffectiveAt: p.EffectiveAt,
+			UpdatedAt:   p.UpdatedAt,
 		}
 		results = append(results, policy)
 	}
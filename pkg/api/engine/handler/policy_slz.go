@@ -106,8 +106,11 @@ type enginePolicyResponse struct {
 	Subject    policyResponseSubject  `json:"subject"`
 	Expression map[string]interface{} `json:"expression"`
 	TemplateID int64                  `json:"template_id"`
+	Effect     string                 `json:"effect"      example:"allow"`
 	ExpiredAt  int64                  `json:"expired_at"  example:"4102444800"`
-	UpdatedAt  int64                  `json:"updated_at"  example:"4102444800"`
+	// 策略生效时间, 0表示立即生效, 接入方需要二次校验
+	EffectiveAt int64 `json:"effective_at" example:"0"`
+	UpdatedAt   int64 `json:"updated_at"   example:"4102444800"`
 }
 
 type policyListResponse struct {
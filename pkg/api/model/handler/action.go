@@ -94,6 +94,7 @@ func BatchCreateActions(c *gin.Context) {
 		}
 		action.RelatedResourceTypes = convertToRelatedResourceTypes(ac.RelatedResourceTypes)
 		action.RelatedEnvironments = convertToRelatedEnvironments(ac.RelatedEnvironments)
+		action.RelatedSubjectAttributes = convertToRelatedSubjectAttributes(ac.RelatedSubjectAttributes)
 
 		actions = append(actions, action)
 	}
@@ -200,6 +201,9 @@ func UpdateAction(c *gin.Context) {
 	if _, ok := data["related_environments"]; ok {
 		allowEmptyFields.AddKey("RelatedEnvironments")
 	}
+	if _, ok := data["related_subject_attributes"]; ok {
+		allowEmptyFields.AddKey("RelatedSubjectAttributes")
+	}
 	if _, ok := data["description"]; ok {
 		allowEmptyFields.AddKey("Description")
 	}
@@ -224,6 +228,8 @@ func UpdateAction(c *gin.Context) {
 		RelatedActions:       body.RelatedActions,
 		RelatedEnvironments:  convertToRelatedEnvironments(body.RelatedEnvironments),
 
+		RelatedSubjectAttributes: convertToRelatedSubjectAttributes(body.RelatedSubjectAttributes),
+
 		AllowEmptyFields: allowEmptyFields,
 	}
 
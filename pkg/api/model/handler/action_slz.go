@@ -16,6 +16,7 @@ import (
 	"github.com/TencentBlueKing/gopkg/collection/set"
 	"github.com/gin-gonic/gin/binding"
 
+	"iam/pkg/abac/pdp/condition"
 	"iam/pkg/api/common"
 	"iam/pkg/service/types"
 	"iam/pkg/util"
@@ -34,14 +35,24 @@ type relatedResourceType struct {
 	RelatedInstanceSelections []referenceInstanceSelection `json:"related_instance_selections" binding:"omitempty"`
 }
 
-// relatedEnvironment, currently only support `current_timestamp`.
-// if we support more types, should add a `validate` method, each type has different operators.
+// relatedEnvironment, the time-related environment types, all based on `_bk_iam_env_.tz`
+// - period_daily: hms, e.g. 09:00:00-18:00:00
+// - period_weekly: weekday, Monday=1 ... Sunday=7
+// - period_monthly: monthday, 1-31
+// - period_yearly: month, 1-12
+// - date_range: date, e.g. 20261101-20261111
+// - timestamp_range: ts, unix timestamp
 type relatedEnvironment struct {
-	// NOTE: currently only support period_daily, will support current_timestamp later
-	//       and no operators now!
-	//       only one field, but should be a struct! keep extensible in the future
-	Type string `json:"type" binding:"oneof=period_daily" example:"period_daily"`
-	// Operators []string `json:"operators" binding:"omitempty,unique"`
+	Type string `json:"type" binding:"required" example:"period_daily"`
+	// Operators 环境属性允许使用的条件操作符, 为空时不限制
+	Operators []string `json:"operators" binding:"omitempty,unique" example:"StringEquals,StringMatch"`
+}
+
+// relatedSubjectAttribute, the subject attribute could be used in condition, `{system}._bk_iam_subject_.{id}`
+type relatedSubjectAttribute struct {
+	ID string `json:"id" binding:"required,max=64" example:"employment_type"`
+	// Operators 属性允许使用的条件操作符, 为空时不限制
+	Operators []string `json:"operators" binding:"omitempty,unique" example:"StringEquals"`
 }
 
 type actionSerializer struct {
@@ -61,6 +72,8 @@ type actionSerializer struct {
 	RelatedActions       []string              `json:"related_actions"`
 	RelatedEnvironments  []relatedEnvironment  `json:"related_environments"   binding:"omitempty"`
 
+	RelatedSubjectAttributes []relatedSubjectAttribute `json:"related_subject_attributes" binding:"omitempty"`
+
 	Version int64 `json:"version" binding:"omitempty,gte=1" example:"1"`
 }
 
@@ -79,6 +92,8 @@ type actionUpdateSerializer struct {
 	RelatedActions       []string              `json:"related_actions"`
 	RelatedEnvironments  []relatedEnvironment  `json:"related_environments"   binding:"omitempty"`
 
+	RelatedSubjectAttributes []relatedSubjectAttribute `json:"related_subject_attributes" binding:"omitempty"`
+
 	Version int64 `json:"version" binding:"omitempty,gte=1" example:"1"`
 }
 
@@ -123,6 +138,13 @@ func (a *actionUpdateSerializer) validate(keys map[string]interface{}) (bool, st
 		}
 	}
 
+	if len(a.RelatedSubjectAttributes) > 0 {
+		valid, message := validateRelatedSubjectAttributes(a.RelatedSubjectAttributes, "")
+		if !valid {
+			return false, message
+		}
+	}
+
 	return true, "valid"
 }
 
@@ -149,6 +171,12 @@ func validateRelatedEnvironments(data []relatedEnvironment, actionID string) (bo
 			return false, message
 		}
 
+		if !validRelatedEnvironmentTypes.Has(d.Type) {
+			message := fmt.Sprintf("data of action_id=%s related_environments[%d] type `%s` not supported",
+				actionID, index, d.Type)
+			return false, message
+		}
+
 		// 校验 data.ID 没有重复
 		if typeID.Has(d.Type) {
 			message := fmt.Sprintf("data of action_id=%s related_environments[%d] id should not repeat",
@@ -157,6 +185,53 @@ func validateRelatedEnvironments(data []relatedEnvironment, actionID string) (bo
 		}
 
 		typeID.Add(d.Type)
+
+		// 校验 operators 为支持的属性条件操作符
+		for _, op := range d.Operators {
+			if !condition.IsSupportedOperator(op) {
+				message := fmt.Sprintf("data of action_id=%s related_environments[%d] operator `%s` not supported",
+					actionID, index, op)
+				return false, message
+			}
+		}
+	}
+	return true, "valid"
+}
+
+func validateRelatedSubjectAttributes(data []relatedSubjectAttribute, actionID string) (bool, string) {
+	attrID := set.NewStringSet()
+	for index, d := range data {
+		if err := binding.Validator.ValidateStruct(d); err != nil {
+			message := fmt.Sprintf("data of action_id=%s related_subject_attributes[%d], %s",
+				actionID, index, util.ValidationErrorMessage(err))
+			return false, message
+		}
+
+		// 属性名同用户属性的命名规则
+		if !common.ValidIDRegex.MatchString(d.ID) {
+			message := fmt.Sprintf("data of action_id=%s related_subject_attributes[%d] id `%s` invalid, %s",
+				actionID, index, d.ID, common.ErrInvalidID.Error())
+			return false, message
+		}
+
+		// 校验 data.ID 没有重复
+		if attrID.Has(d.ID) {
+			message := fmt.Sprintf("data of action_id=%s related_subject_attributes[%d] id should not repeat",
+				actionID, index)
+			return false, message
+		}
+
+		attrID.Add(d.ID)
+
+		// 校验 operators 为支持的属性条件操作符
+		for _, op := range d.Operators {
+			if !condition.IsSupportedOperator(op) {
+				message := fmt.Sprintf(
+					"data of action_id=%s related_subject_attributes[%d] operator `%s` not supported",
+					actionID, index, op)
+				return false, message
+			}
+		}
 	}
 	return true, "valid"
 }
@@ -279,6 +354,13 @@ func validateAction(body []actionSerializer) (bool, string) {
 				return false, message
 			}
 		}
+
+		if len(data.RelatedSubjectAttributes) > 0 {
+			valid, message := validateRelatedSubjectAttributes(data.RelatedSubjectAttributes, data.ID)
+			if !valid {
+				return false, message
+			}
+		}
 	}
 	return true, "valid"
 }
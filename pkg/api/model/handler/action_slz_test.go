@@ -104,6 +104,83 @@ var _ = Describe("ActionSlz", func() {
 		})
 	})
 
+	Describe("ValidateRelatedEnvironments", func() {
+		It("invalid type", func() {
+			a := []relatedEnvironment{
+				{Type: "abc"},
+			}
+			valid, message := validateRelatedEnvironments(a, "")
+			assert.False(GinkgoT(), valid)
+			assert.Contains(GinkgoT(), message, "type `abc` not supported")
+		})
+		It("repeat type", func() {
+			a := []relatedEnvironment{
+				{Type: "period_daily"},
+				{Type: "period_daily"},
+			}
+			valid, message := validateRelatedEnvironments(a, "")
+			assert.False(GinkgoT(), valid)
+			assert.Contains(GinkgoT(), message, "should not repeat")
+		})
+		It("invalid operator", func() {
+			a := []relatedEnvironment{
+				{Type: "period_daily", Operators: []string{"StringMatch", "AND"}},
+			}
+			valid, message := validateRelatedEnvironments(a, "")
+			assert.False(GinkgoT(), valid)
+			assert.Contains(GinkgoT(), message, "operator `AND` not supported")
+		})
+		It("valid", func() {
+			a := []relatedEnvironment{
+				{Type: "period_daily", Operators: []string{"StringSuffix", "StringMatch"}},
+				{Type: "period_weekly"},
+				{Type: "date_range"},
+				{Type: "client_ip", Operators: []string{"IPInCIDR"}},
+				{Type: "network_zone"},
+			}
+			valid, message := validateRelatedEnvironments(a, "")
+			assert.True(GinkgoT(), valid)
+			assert.Equal(GinkgoT(), "valid", message)
+		})
+	})
+
+	Describe("ValidateRelatedSubjectAttributes", func() {
+		It("invalid id", func() {
+			a := []relatedSubjectAttribute{
+				{ID: "Level"},
+			}
+			valid, message := validateRelatedSubjectAttributes(a, "")
+			assert.False(GinkgoT(), valid)
+			assert.Contains(GinkgoT(), message, "id `Level` invalid")
+		})
+		It("repeat id", func() {
+			a := []relatedSubjectAttribute{
+				{ID: "level"},
+				{ID: "level"},
+			}
+			valid, message := validateRelatedSubjectAttributes(a, "")
+			assert.False(GinkgoT(), valid)
+			assert.Contains(GinkgoT(), message, "should not repeat")
+		})
+		It("invalid operator", func() {
+			a := []relatedSubjectAttribute{
+				{ID: "level", Operators: []string{"NumericGt", "AND"}},
+			}
+			valid, message := validateRelatedSubjectAttributes(a, "")
+			assert.False(GinkgoT(), valid)
+			assert.Contains(GinkgoT(), message, "operator `AND` not supported")
+		})
+		It("valid", func() {
+			a := []relatedSubjectAttribute{
+				{ID: "level", Operators: []string{"NumericGte", "NumericLt"}},
+				{ID: "employment_type"},
+			}
+			valid, message := validateRelatedSubjectAttributes(a, "")
+			assert.True(GinkgoT(), valid)
+			assert.Equal(GinkgoT(), "valid", message)
+		})
+	})
+
 	Describe("ValidateRelatedResourceTypes", func() {
 		It("empty, invalid", func() {
 			a := []relatedResourceType{
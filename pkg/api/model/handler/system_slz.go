@@ -16,6 +16,9 @@ type systemProviderConfig struct {
 	Auth string `json:"auth" structs:"auth" binding:"required,oneof=none basic" example:"basic"`
 
 	Healthz string `json:"healthz" structs:"healthz" binding:"omitempty" example:"/healthz"`
+
+	// 策略查询/拉取接口默认返回的策略表达式版本, 不配置为1, 见service.PolicyVersion
+	PolicyVersion string `json:"policy_version" structs:"policy_version,omitempty" binding:"omitempty,oneof=1 2"`
 }
 
 type systemSerializer struct {
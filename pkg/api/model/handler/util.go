@@ -11,9 +11,10 @@
 package handler
 
 import (
-	svctypes "iam/pkg/service/types"
-
+	"github.com/TencentBlueKing/gopkg/collection/set"
 	"github.com/fatih/structs"
+
+	svctypes "iam/pkg/service/types"
 )
 
 // SelectionModeAll ...
@@ -24,6 +25,35 @@ const (
 	SelectionModeInstancePaste = "instance:paste"
 )
 
+// 时间相关的环境属性类型
+const (
+	EnvTypePeriodDaily    = "period_daily"
+	EnvTypePeriodWeekly   = "period_weekly"
+	EnvTypePeriodMonthly  = "period_monthly"
+	EnvTypePeriodYearly   = "period_yearly"
+	EnvTypeDateRange      = "date_range"
+	EnvTypeTimestampRange = "timestamp_range"
+)
+
+// 鉴权请求方传入的环境属性类型
+const (
+	EnvTypeClientIP    = "client_ip"
+	EnvTypeNetworkZone = "network_zone"
+	EnvTypeDevice      = "device"
+)
+
+var validRelatedEnvironmentTypes = set.NewStringSetWithValues([]string{
+	EnvTypePeriodDaily,
+	EnvTypePeriodWeekly,
+	EnvTypePeriodMonthly,
+	EnvTypePeriodYearly,
+	EnvTypeDateRange,
+	EnvTypeTimestampRange,
+	EnvTypeClientIP,
+	EnvTypeNetworkZone,
+	EnvTypeDevice,
+})
+
 func convertToRelatedResourceTypes(rrts []relatedResourceType) []svctypes.ActionResourceType {
 	arts := make([]svctypes.ActionResourceType, 0, len(rrts))
 	for _, rrt := range rrts {
@@ -56,12 +86,24 @@ func convertToRelatedEnvironments(res []relatedEnvironment) []svctypes.ActionEnv
 	aes := make([]svctypes.ActionEnvironment, 0, len(res))
 	for _, re := range res {
 		aes = append(aes, svctypes.ActionEnvironment{
-			Type: re.Type,
+			Type:      re.Type,
+			Operators: re.Operators,
 		})
 	}
 	return aes
 }
 
+func convertToRelatedSubjectAttributes(rsas []relatedSubjectAttribute) []svctypes.ActionSubjectAttribute {
+	asas := make([]svctypes.ActionSubjectAttribute, 0, len(rsas))
+	for _, rsa := range rsas {
+		asas = append(asas, svctypes.ActionSubjectAttribute{
+			ID:        rsa.ID,
+			Operators: rsa.Operators,
+		})
+	}
+	return asas
+}
+
 func convertAuthType(authType string) string {
 	if authType == "" {
 		return svctypes.AuthTypeABACStr
@@ -49,8 +49,15 @@ func PolicyGet(c *gin.Context) {
 	}
 	query.initDefault()
 
+	systemID := c.Param("system_id")
+	policyVersion, err := cacheimpls.GetSystemPolicyVersion(systemID)
+	if err != nil {
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
 	manager := prp.NewOpenPolicyManager()
-	policy, err := manager.Get(query.Type, param.PolicyID)
+	policy, err := manager.Get(query.Type, param.PolicyID, policyVersion)
 	if err != nil {
 		if errors.Is(err, prp.ErrPolicyNotFound) {
 			util.NotFoundJSONResponse(c, err.Error())
@@ -67,7 +74,6 @@ func PolicyGet(c *gin.Context) {
 		return
 	}
 
-	systemID := c.Param("system_id")
 	if systemID != data.System {
 		util.ForbiddenJSONResponse(c, fmt.Sprintf("system(%s) can't access system(%s)'s policy", systemID, data.System))
 		return
@@ -73,8 +73,14 @@ func PolicyList(c *gin.Context) {
 	offset := (query.Page - 1) * query.PageSize
 	limit := query.PageSize
 
+	policyVersion, err := cacheimpls.GetSystemPolicyVersion(systemID)
+	if err != nil {
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
 	manager := prp.NewOpenPolicyManager()
-	count, policies, err := manager.List(query.Type, actionPK, query.Timestamp, offset, limit)
+	count, policies, err := manager.List(query.Type, actionPK, query.Timestamp, offset, limit, policyVersion)
 	if err != nil {
 		util.SystemErrorJSONResponse(c, err)
 		return
@@ -265,7 +265,7 @@ func BatchAuthByResources(c *gin.Context) {
 			return
 		}
 		// no permission =>
-		if errors.Is(err, pdp.ErrSubjectNotExists) || errors.Is(err, pdp.ErrNoPolicies) {
+		if errors.Is(err, pdp.ErrSubjectNotExists) {
 			// 没有权限
 			for _, r := range body.ResourcesList {
 				data[buildResourceID(r)] = false
@@ -275,7 +275,18 @@ func BatchAuthByResources(c *gin.Context) {
 			return
 		}
 
-		// else, system error
+		// NOTE: subject自身没有策略时, 仍然可能有委托的权限
+		if !errors.Is(err, pdp.ErrNoPolicies) {
+			// else, system error
+			err = errorWrapf(err, "systemID=`%s`, body=`%+v`", systemID, body)
+			util.SystemErrorJSONResponseWithDebug(c, err, entry)
+			return
+		}
+	}
+
+	// 委托的权限, 使用delegator自身的策略单独计算
+	delegatedPolicies, err := pdp.QueryDelegatedAuthPolicies(req, isForce)
+	if err != nil {
 		err = errorWrapf(err, "systemID=`%s`, body=`%+v`", systemID, body)
 		util.SystemErrorJSONResponseWithDebug(c, err, entry)
 		return
@@ -303,20 +314,30 @@ func BatchAuthByResources(c *gin.Context) {
 		}
 
 		// do eval
-		isAllowed, _, err := evaluation.EvalPolicies(evalctx.NewEvalContext(r), policies)
+		isAllowed, policyID, err := evaluation.EvalPolicies(evalctx.NewEvalContext(r), policies)
 		if err != nil {
 			err = errorWrapf(err, " pdp.EvalPolicies req=`%+v`, policies=`%+v` fail", r, policies)
 			util.SystemErrorJSONResponseWithDebug(c, err, entry)
 			return
 		}
 
+		// 自身没有权限且没有被deny策略拒绝时, 计算委托的权限
+		if !isAllowed && policyID == -1 && len(delegatedPolicies) > 0 {
+			isAllowed, err = pdp.EvalDelegatedAuthPolicies(r, delegatedPolicies)
+			if err != nil {
+				err = errorWrapf(err, "pdp.EvalDelegatedAuthPolicies req=`%+v` fail", r)
+				util.SystemErrorJSONResponseWithDebug(c, err, entry)
+				return
+			}
+		}
+
 		data[buildResourceID(resources)] = isAllowed
 	}
 
 	// NOTE: debug mode, do translate, for understanding easier
 	if entry != nil && len(body.ResourcesList) > 0 {
 		debug.WithValue(entry, "expression", "set fail")
-		expr, err1 := cacheimpls.PoliciesTranslate(policies)
+		expr, err1 := cacheimpls.PoliciesTranslate(policies, req.WithSystem)
 		if err1 == nil {
 			debug.WithValue(entry, "expression", expr)
 		}
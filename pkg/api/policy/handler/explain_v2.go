@@ -0,0 +1,83 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"errors"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/gin-gonic/gin"
+
+	"iam/pkg/abac/pdp"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/util"
+)
+
+// ExplainV2 godoc
+// @Summary policy explain/鉴权结果解释
+// @Description explain why the subject is allowed or denied, with the detail of every policy and rbac group
+// @ID api-v2-policy-explain
+// @Tags policy
+// @Accept json
+// @Produce json
+// @Param system_id path string true "System ID"
+// @Param body body authV2Request true "the explain request, same as auth"
+// @Success 200 {object} types.Explanation
+// @Header 200 {string} X-Request-Id "the request id"
+// @Security AppCode
+// @Security AppSecret
+// @Router /api/v2/policy/systems/{system_id}/explain/ [post]
+func ExplainV2(c *gin.Context) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "ExplainV2")
+	_, isForce := c.GetQuery("force")
+
+	systemID := c.Param("system_id")
+
+	var body authV2Request
+	if err := c.ShouldBindJSON(&body); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	// 隔离结构体
+	req := request.NewRequest()
+	copyRequestFromAuthV2Body(req, systemID, &body)
+
+	explanation, err := pdp.Explain(req, isForce)
+	if err != nil {
+		if errors.Is(err, pdp.ErrInvalidAction) || errors.Is(err, pdp.ErrInvalidActionResource) {
+			util.BadRequestErrorJSONResponse(c, err.Error())
+			return
+		}
+
+		err = errorWrapf(err, "systemID=`%s`, body=`%+v`", systemID, body)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	// NOTE: same as auth, the super permission check is after the blacklist check
+	if !explanation.Subject.InBlackList {
+		hasSuperPerm, err := hasSystemSuperPermission(systemID, body.Subject.Type, body.Subject.ID)
+		if err != nil {
+			util.SystemErrorJSONResponse(c, err)
+			return
+		}
+
+		if hasSuperPerm {
+			explanation.Subject.SuperPermission = true
+			explanation.Allowed = true
+			explanation.Reason = types.ExplainReasonSuperPermission
+		}
+	}
+
+	util.SuccessJSONResponse(c, "ok", explanation)
+}
@@ -78,6 +78,11 @@ func Query(c *gin.Context) {
 	// 隔离结构体
 	req := request.NewRequest()
 	copyRequestFromQueryBody(req, &body)
+	if err := fillRequestPolicyVersion(req, body.PolicyVersion); err != nil {
+		err = errorWrapf(err, "fillRequestPolicyVersion systemID=`%s` fail", systemID)
+		util.SystemErrorJSONResponseWithDebug(c, err, entry)
+		return
+	}
 
 	// 如果传的筛选的资源实例为空, 则不判断外部依赖资源是否满足
 	willCheckRemoteResource := true
@@ -164,6 +169,11 @@ func BatchQueryByActions(c *gin.Context) {
 		req := request.NewRequest()
 		copyRequestFromQueryByActionsBody(req, &body)
 		req.Action.ID = action.ID
+		if err := fillRequestPolicyVersion(req, body.PolicyVersion); err != nil {
+			err = errorWrapf(err, "fillRequestPolicyVersion systemID=`%s` fail", systemID)
+			util.SystemErrorJSONResponseWithDebug(c, err, entry)
+			return
+		}
 
 		var subEntry *debug.Entry
 		if isDebug {
@@ -267,6 +277,11 @@ func QueryByExtResources(c *gin.Context) {
 	// 隔离结构体
 	req := request.NewRequest()
 	copyRequestFromQueryBody(req, &body.queryRequest)
+	if err := fillRequestPolicyVersion(req, body.PolicyVersion); err != nil {
+		err = errorWrapf(err, "fillRequestPolicyVersion systemID=`%s` fail", systemID)
+		util.SystemErrorJSONResponseWithDebug(c, err, entry)
+		return
+	}
 
 	// 结构体隔离转换
 	extResources := make([]types.ExtResource, 0, len(body.ExtResources))
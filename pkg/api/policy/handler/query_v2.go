@@ -17,6 +17,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"iam/pkg/abac/pdp"
+	"iam/pkg/abac/pdp/translate"
 	"iam/pkg/abac/types/request"
 	"iam/pkg/logging/debug"
 	"iam/pkg/util"
@@ -63,7 +64,9 @@ func QueryV2(c *gin.Context) {
 		body.Subject.Type,
 		body.Subject.ID,
 		func() interface{} {
-			return AnyExpression
+			// NOTE: any 转换为任意格式都不会失败
+			formatted, _ := translate.FormatExpr(body.Format, body.FieldMapping, AnyExpression)
+			return formatted
 		},
 	) {
 		return
@@ -72,6 +75,11 @@ func QueryV2(c *gin.Context) {
 	// 隔离结构体
 	req := request.NewRequest()
 	copyRequestFromQueryV2Body(req, systemID, &body)
+	if err := fillRequestPolicyVersion(req, body.PolicyVersion); err != nil {
+		err = errorWrapf(err, "fillRequestPolicyVersion systemID=`%s` fail", systemID)
+		util.SystemErrorJSONResponseWithDebug(c, err, entry)
+		return
+	}
 
 	// 如果传的筛选的资源实例为空, 则不判断外部依赖资源是否满足
 	willCheckRemoteResource := true
@@ -92,7 +100,13 @@ func QueryV2(c *gin.Context) {
 		return
 	}
 
-	util.SuccessJSONResponseWithDebug(c, "ok", expr, entry)
+	formatted, err := translate.FormatExpr(body.Format, body.FieldMapping, expr)
+	if err != nil {
+		util.BadRequestErrorJSONResponse(c, err.Error())
+		return
+	}
+
+	util.SuccessJSONResponseWithDebug(c, "ok", formatted, entry)
 }
 
 // BatchQueryV2ByActions godoc
@@ -158,6 +172,11 @@ func BatchQueryV2ByActions(c *gin.Context) {
 		req := request.NewRequest()
 		copyRequestFromQueryV2ByActionsBody(req, systemID, &body)
 		req.Action.ID = action.ID
+		if err := fillRequestPolicyVersion(req, body.PolicyVersion); err != nil {
+			err = errorWrapf(err, "fillRequestPolicyVersion systemID=`%s` fail", systemID)
+			util.SystemErrorJSONResponseWithDebug(c, err, entry)
+			return
+		}
 
 		var subEntry *debug.Entry
 		if isDebug {
@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/pdp"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/cacheimpls"
+	"iam/pkg/logging/debug"
+	"iam/pkg/util"
+)
+
+func TestQueryV2NoPermission(t *testing.T) {
+	patches := gomonkey.ApplyFunc(cacheimpls.IsSubjectInBlackList, func(_, _ string) bool { return false })
+	patches.ApplyFunc(hasSystemSuperPermission, func(_, _, _ string) (bool, error) { return false, nil })
+	patches.ApplyFunc(cacheimpls.GetSystemPolicyVersion, func(_ string) (string, error) { return "1", nil })
+	patches.ApplyFunc(pdp.Query, func(_ *request.Request, _ *debug.Entry, _, _ bool) (map[string]interface{}, error) {
+		return pdp.EmptyPolicies, nil
+	})
+	defer patches.Reset()
+
+	r := util.SetupRouter()
+	r.POST("/api/v2/policy/systems/:system_id/query/", QueryV2)
+
+	tests := []struct {
+		format string
+		want   interface{}
+	}{
+		{format: "expression", want: map[string]interface{}{}},
+		{format: "sql", want: map[string]interface{}{"where": "1 = 0", "args": []interface{}{}}},
+		{format: "es", want: map[string]interface{}{"match_none": map[string]interface{}{}}},
+		{
+			format: "mongo",
+			want:   map[string]interface{}{"_id": map[string]interface{}{"$in": []interface{}{}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			body := `{"subject": {"type": "user", "id": "admin"}, "action": {"id": "view_host"}, "format": "` +
+				tt.format + `"}`
+			req, _ := http.NewRequest(http.MethodPost, "/api/v2/policy/systems/bk_cmdb/query/",
+				bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			resp := util.ReadResponse(w)
+			assert.Equal(t, util.NoError, resp.Code)
+			assert.Equal(t, tt.want, resp.Data)
+		})
+	}
+}
@@ -58,6 +58,8 @@ type authRequest struct {
 	// required
 	Resources []resource `json:"resources" binding:"required"`
 	Action    action     `json:"action"    binding:"required"`
+	// optional, e.g. {"client_ip": "10.0.0.1", "network_zone": "office"}
+	Environment map[string]interface{} `json:"environment" binding:"omitempty"`
 }
 
 type authResponse struct {
@@ -69,12 +71,21 @@ type authV2Request struct {
 	Subject   subject    `json:"subject"   binding:"required"`
 	Action    action     `json:"action"    binding:"required"`
 	Resources []resource `json:"resources" binding:"required"`
+	// optional, e.g. {"client_ip": "10.0.0.1", "network_zone": "office"}
+	Environment map[string]interface{} `json:"environment" binding:"omitempty"`
 }
 
 type authV2Response struct {
 	Allowed bool `json:"allowed" example:"false"`
 }
 
+// ====== who can v2
+
+type whoCanV2Request struct {
+	Action    action     `json:"action"    binding:"required"`
+	Resources []resource `json:"resources" binding:"required"`
+}
+
 // ======= auth by actions
 
 type authByActionsRequest struct {
@@ -111,6 +122,9 @@ type queryRequest struct {
 	// can be empty
 	Resources []resource `json:"resources" binding:"omitempty"`
 	Action    action     `json:"action"    binding:"required"`
+
+	// 返回的策略表达式版本: 1 field为{type}.{attr}; 2 field为{system}.{type}.{attr}; 未指定时使用系统配置的版本
+	PolicyVersion string `json:"policy_version" binding:"omitempty,oneof=1 2" example:"2"`
 }
 
 // ====== query v2
@@ -120,6 +134,14 @@ type queryV2Request struct {
 	Action  action  `json:"action"    binding:"required"`
 	// can be empty
 	Resources []resource `json:"resources" binding:"omitempty"`
+
+	// 输出格式: expression(默认)/sql/es/mongo
+	Format string `json:"format" binding:"omitempty,oneof=expression sql es mongo"`
+	// 表达式field到存储字段的映射, 例如 {"host.id": "t_host.id"}
+	FieldMapping map[string]string `json:"field_mapping" binding:"omitempty"`
+
+	// 返回的策略表达式版本: 1 field为{type}.{attr}; 2 field为{system}.{type}.{attr}; 未指定时使用系统配置的版本
+	PolicyVersion string `json:"policy_version" binding:"omitempty,oneof=1 2" example:"2"`
 }
 
 // ======= query by actions
@@ -129,6 +151,9 @@ type queryByActionsRequest struct {
 	// can be empty
 	Resources []resource `json:"resources" binding:"omitempty"`
 	Actions   []action   `json:"actions"   binding:"required"`
+
+	// 返回的策略表达式版本: 1 field为{type}.{attr}; 2 field为{system}.{type}.{attr}; 未指定时使用系统配置的版本
+	PolicyVersion string `json:"policy_version" binding:"omitempty,oneof=1 2" example:"2"`
 }
 
 // ======= query by actions
@@ -138,6 +163,9 @@ type queryV2ByActionsRequest struct {
 	// can be empty
 	Resources []resource `json:"resources" binding:"omitempty"`
 	Actions   []action   `json:"actions"   binding:"required"`
+
+	// 返回的策略表达式版本: 1 field为{type}.{attr}; 2 field为{system}.{type}.{attr}; 未指定时使用系统配置的版本
+	PolicyVersion string `json:"policy_version" binding:"omitempty,oneof=1 2" example:"2"`
 }
 
 type actionInResponse struct {
@@ -22,6 +22,7 @@ import (
 	"iam/pkg/cacheimpls"
 	"iam/pkg/config"
 	"iam/pkg/logging/debug"
+	"iam/pkg/service"
 	svctypes "iam/pkg/service/types"
 	"iam/pkg/util"
 )
@@ -35,6 +36,20 @@ var AnyExpression = map[string]interface{}{
 	"value": []interface{}{},
 }
 
+// fillRequestPolicyVersion 设置返回的策略表达式版本, 请求未指定时使用系统配置的版本
+func fillRequestPolicyVersion(req *request.Request, policyVersion string) error {
+	if policyVersion == "" {
+		var err error
+		policyVersion, err = cacheimpls.GetSystemPolicyVersion(req.System)
+		if err != nil {
+			return err
+		}
+	}
+
+	req.WithSystem = service.IsPolicyVersionWithSystem(policyVersion)
+	return nil
+}
+
 func copyRequestFromAuthBody(req *request.Request, body *authRequest) {
 	req.System = body.System
 
@@ -51,6 +66,8 @@ func copyRequestFromAuthBody(req *request.Request, body *authRequest) {
 			Attribute: resource.Attribute,
 		})
 	}
+
+	req.Environment = body.Environment
 }
 
 func copyRequestFromAuthV2Body(req *request.Request, systemID string, body *authV2Request) {
@@ -69,6 +86,8 @@ func copyRequestFromAuthV2Body(req *request.Request, systemID string, body *auth
 			Attribute: resource.Attribute,
 		})
 	}
+
+	req.Environment = body.Environment
 }
 
 func copyRequestFromQueryBody(req *request.Request, body *queryRequest) {
@@ -70,6 +70,9 @@ func Test_copyRequestFromAuthBody(t *testing.T) {
 					Action: action{
 						ID: "test",
 					},
+					Environment: map[string]interface{}{
+						"client_ip": "10.0.0.1",
+					},
 				},
 			},
 		},
@@ -96,11 +99,54 @@ func Test_copyRequestFromAuthBody(t *testing.T) {
 						"key": "value",
 					},
 				}},
+				Environment: map[string]interface{}{
+					"client_ip": "10.0.0.1",
+				},
 			}, tt.args.req)
 		})
 	}
 }
 
+func Test_fillRequestPolicyVersion(t *testing.T) {
+	patches := gomonkey.ApplyFunc(cacheimpls.GetSystemPolicyVersion, func(systemID string) (string, error) {
+		if systemID == "bk_job" {
+			return "2", nil
+		}
+		if systemID == "error" {
+			return "", errors.New("error")
+		}
+		return "1", nil
+	})
+	defer patches.Reset()
+
+	tests := []struct {
+		name          string
+		system        string
+		policyVersion string
+		want          bool
+		wantErr       bool
+	}{
+		{name: "request v2", system: "iam", policyVersion: "2", want: true},
+		{name: "request v1 over system v2", system: "bk_job", policyVersion: "1", want: false},
+		{name: "system v2", system: "bk_job", want: true},
+		{name: "system default", system: "iam", want: false},
+		{name: "system fail", system: "error", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := request.NewRequest()
+			req.System = tt.system
+			err := fillRequestPolicyVersion(req, tt.policyVersion)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, req.WithSystem)
+		})
+	}
+}
+
 func Test_copyRequestFromQueryBody(t *testing.T) {
 	t.Parallel()
 
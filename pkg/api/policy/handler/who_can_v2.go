@@ -0,0 +1,77 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"errors"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/gin-gonic/gin"
+
+	"iam/pkg/abac/pdp"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/util"
+)
+
+// WhoCanV2 godoc
+// @Summary who can/反查有权限的用户
+// @Description list the users allowed to perform the action on the resource, with the grant path of every user
+// @ID api-v2-policy-who-can
+// @Tags policy
+// @Accept json
+// @Produce json
+// @Param system_id path string true "System ID"
+// @Param body body whoCanV2Request true "the action and resources"
+// @Success 200 {array} types.WhoCanUser
+// @Header 200 {string} X-Request-Id "the request id"
+// @Security AppCode
+// @Security AppSecret
+// @Router /api/v2/policy/systems/{system_id}/who_can/ [post]
+func WhoCanV2(c *gin.Context) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "WhoCanV2")
+	_, isForce := c.GetQuery("force")
+
+	systemID := c.Param("system_id")
+
+	var body whoCanV2Request
+	if err := c.ShouldBindJSON(&body); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	// 隔离结构体
+	req := request.NewRequest()
+	req.System = systemID
+	req.Action.ID = body.Action.ID
+	for _, resource := range body.Resources {
+		req.Resources = append(req.Resources, types.Resource{
+			System:    resource.System,
+			Type:      resource.Type,
+			ID:        resource.ID,
+			Attribute: resource.Attribute,
+		})
+	}
+
+	users, err := pdp.WhoCan(req, isForce)
+	if err != nil {
+		if errors.Is(err, pdp.ErrInvalidAction) || errors.Is(err, pdp.ErrInvalidActionResource) {
+			util.BadRequestErrorJSONResponse(c, err.Error())
+			return
+		}
+
+		err = errorWrapf(err, "systemID=`%s`, body=`%+v`", systemID, body)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", users)
+}
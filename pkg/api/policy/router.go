@@ -58,5 +58,13 @@ func RegisterV2(r *gin.RouterGroup) {
 		// in query_v2.go
 		// 批量查询
 		s.POST("/query_by_actions/", handler.BatchQueryV2ByActions)
+
+		// in explain_v2.go
+		// 鉴权结果解释
+		s.POST("/explain/", handler.ExplainV2)
+
+		// in who_can_v2.go
+		// 反查对资源实例有权限的用户
+		s.POST("/who_can/", handler.WhoCanV2)
 	}
 }
@@ -16,7 +16,7 @@ import (
 
 const (
 	actionSupportFields = "id,name,name_en,related_resource_types,version,type,auth_type," +
-		"hidden,description,description_en,related_actions,related_environments,sensitivity"
+		"hidden,description,description_en,related_actions,related_environments,related_subject_attributes,sensitivity"
 	actionDefaultFields = "id,name,name_en"
 )
 
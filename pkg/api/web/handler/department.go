@@ -11,6 +11,8 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/TencentBlueKing/gopkg/errorx"
 	"github.com/gin-gonic/gin"
 
@@ -133,3 +135,74 @@ func ListSubjectDepartments(c *gin.Context) {
 		"results": subjectDepartments,
 	})
 }
+
+// BatchUpdateDepartmentParents 批量设置部门的上级部门
+func BatchUpdateDepartmentParents(c *gin.Context) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "BatchUpdateDepartmentParents")
+
+	var departmentParents []departmentParent
+	if err := c.ShouldBindJSON(&departmentParents); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	if len(departmentParents) == 0 {
+		util.BadRequestErrorJSONResponse(c, "department parents can not be empty")
+		return
+	}
+
+	papDepartmentParents := make([]pap.DepartmentParent, 0, len(departmentParents))
+	for _, dp := range departmentParents {
+		if dp.DepartmentID == dp.ParentID {
+			util.BadRequestErrorJSONResponse(c, "department parent can not be itself")
+			return
+		}
+
+		papDepartmentParents = append(papDepartmentParents, pap.DepartmentParent{
+			DepartmentID: dp.DepartmentID,
+			ParentID:     dp.ParentID,
+		})
+	}
+
+	ctl := pap.NewDepartmentController()
+	err := ctl.BulkUpdateParents(papDepartmentParents)
+	if err != nil {
+		// 部门树成环或超过最大层数
+		if errors.Is(err, service.ErrDepartmentTreeCycle) || errors.Is(err, service.ErrDepartmentTreeTooDeep) {
+			util.ConflictJSONResponse(c, err.Error())
+			return
+		}
+
+		err = errorWrapf(err, "ctl.BulkUpdateParents departmentParents=`%+v`", papDepartmentParents)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", nil)
+}
+
+// BatchDeleteDepartmentParents 批量删除部门的上级部门
+func BatchDeleteDepartmentParents(c *gin.Context) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "BatchDeleteDepartmentParents")
+
+	var departmentIDs []string
+	if err := c.ShouldBindJSON(&departmentIDs); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	if len(departmentIDs) == 0 {
+		util.BadRequestErrorJSONResponse(c, "department id can not be empty")
+		return
+	}
+
+	ctl := pap.NewDepartmentController()
+	err := ctl.BulkDeleteParents(departmentIDs)
+	if err != nil {
+		err = errorWrapf(err, "ctl.BulkDeleteParents departmentIDs=`%+v`", departmentIDs)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", nil)
+}
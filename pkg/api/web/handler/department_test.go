@@ -19,6 +19,7 @@ import (
 
 	"iam/pkg/abac/pap"
 	"iam/pkg/abac/pap/mock"
+	"iam/pkg/service"
 	"iam/pkg/util"
 )
 
@@ -225,3 +226,77 @@ func TestBatchUpdateSubjectDepartments(t *testing.T) {
 			}).OK()
 	})
 }
+
+func TestBatchUpdateDepartmentParents(t *testing.T) {
+	newRequestFunc := util.CreateNewAPIRequestFunc(
+		"put", "/api/v1/department-parents", BatchUpdateDepartmentParents,
+	)
+
+	t.Run("no json", func(t *testing.T) {
+		newRequestFunc(t).NoJSON()
+	})
+
+	t.Run("bad request self parent", func(t *testing.T) {
+		newRequestFunc(t).
+			JSON([]interface{}{
+				map[string]interface{}{
+					"id":        "1",
+					"parent_id": "1",
+				},
+			}).BadRequest("bad request:department parent can not be itself")
+	})
+
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+
+	restMock := func() {
+		ctl.Finish()
+		if patches != nil {
+			patches.Reset()
+		}
+	}
+
+	t.Run("cycle conflict", func(t *testing.T) {
+		ctl = gomock.NewController(t)
+		mockCtl := mock.NewMockDepartmentController(ctl)
+		mockCtl.EXPECT().BulkUpdateParents([]pap.DepartmentParent{
+			{DepartmentID: "1", ParentID: "2"},
+		}).Return(service.ErrDepartmentTreeCycle).AnyTimes()
+		patches = gomonkey.ApplyFunc(pap.NewDepartmentController, func() pap.DepartmentController {
+			return mockCtl
+		})
+		defer restMock()
+
+		newRequestFunc(t).
+			JSON([]interface{}{
+				map[string]interface{}{
+					"id":        "1",
+					"parent_id": "2",
+				},
+			}).BadRequestContainsMessage(service.ErrDepartmentTreeCycle.Error(), util.ConflictError)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		ctl = gomock.NewController(t)
+		mockCtl := mock.NewMockDepartmentController(ctl)
+		mockCtl.EXPECT().BulkUpdateParents([]pap.DepartmentParent{
+			{DepartmentID: "1", ParentID: "2"},
+			{DepartmentID: "3", ParentID: ""},
+		}).Return(nil).AnyTimes()
+		patches = gomonkey.ApplyFunc(pap.NewDepartmentController, func() pap.DepartmentController {
+			return mockCtl
+		})
+		defer restMock()
+
+		newRequestFunc(t).
+			JSON([]interface{}{
+				map[string]interface{}{
+					"id":        "1",
+					"parent_id": "2",
+				},
+				map[string]interface{}{
+					"id": "3",
+				},
+			}).OK()
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 
+	"iam/pkg/abac/pap"
 	"iam/pkg/api/common"
 	"iam/pkg/cacheimpls"
 	"iam/pkg/service"
@@ -85,7 +86,16 @@ func batchSubjectBlackListExecute(c *gin.Context, doFunc func(subjectPKs []int64
 
 func BatchFreezeSubjects(c *gin.Context) {
 	svc := service.NewSubjectBlackListService()
-	batchSubjectBlackListExecute(c, svc.BulkCreate)
+	ctl := pap.NewSubjectDelegationController()
+	batchSubjectBlackListExecute(c, func(subjectPKs []int64) error {
+		err := svc.BulkCreate(subjectPKs)
+		if err != nil {
+			return err
+		}
+
+		// 冻结的用户发起的委托自动撤销
+		return ctl.BulkRevokeByDelegators(subjectPKs)
+	})
 }
 
 func BatchUnfreezeSubjects(c *gin.Context) {
@@ -21,6 +21,7 @@ import (
 	"iam/pkg/abac/pap"
 	abacTypes "iam/pkg/abac/types"
 	"iam/pkg/api/common"
+	"iam/pkg/service"
 	"iam/pkg/service/types"
 	"iam/pkg/util"
 )
@@ -75,7 +76,13 @@ func ListSubjectGroups(c *gin.Context) {
 
 	ctl := pap.NewGroupController()
 
-	count, err := ctl.GetSubjectGroupCountBeforeExpiredAt(query.Type, query.ID, query.BeforeExpiredAt)
+	var count int64
+	var err error
+	if query.At > 0 {
+		count, err = ctl.GetSubjectGroupCountAt(query.Type, query.ID, query.BeforeExpiredAt, query.At)
+	} else {
+		count, err = ctl.GetSubjectGroupCountBeforeExpiredAt(query.Type, query.ID, query.BeforeExpiredAt)
+	}
 	if err != nil {
 		err = errorWrapf(err, "type=`%s`, id=`%s`", query.Type, query.ID)
 		util.SystemErrorJSONResponse(c, err)
@@ -86,6 +93,7 @@ func ListSubjectGroups(c *gin.Context) {
 		query.Type,
 		query.ID,
 		query.BeforeExpiredAt,
+		query.At,
 		query.Limit,
 		query.Offset,
 	)
@@ -94,10 +102,11 @@ func ListSubjectGroups(c *gin.Context) {
 			err,
 			"Handler",
 			"ctl.ListPagingSubjectGroups",
-			"type=`%s`, id=`%s`, expiredAt=`%d`, limit=`%d`, offset=`%d`",
+			"type=`%s`, id=`%s`, expiredAt=`%d`, at=`%d`, limit=`%d`, offset=`%d`",
 			query.Type,
 			query.ID,
 			query.BeforeExpiredAt,
+			query.At,
 			query.Limit,
 			query.Offset,
 		)
@@ -201,7 +210,7 @@ func BatchUpdateGroupMembersExpiredAt(c *gin.Context) {
 	}
 
 	ctl := pap.NewGroupController()
-	err := ctl.UpdateGroupMembersExpiredAt(body.Type, body.ID, papSubjects)
+	err := ctl.UpdateGroupMembersExpiredAt(body.Type, body.ID, papSubjects, util.GetOperator(c))
 	if err != nil {
 		err = errorWrapf(err, "ctl.UpdateGroupMembersExpiredAt",
 			"type=`%s`, id=`%s`, subjects=`%+v`", body.Type, body.ID, papSubjects)
@@ -212,8 +221,39 @@ func BatchUpdateGroupMembersExpiredAt(c *gin.Context) {
 	util.SuccessJSONResponse(c, "ok", gin.H{})
 }
 
+// BatchUpdateGroupMembersSubDepartment 设置用户组的部门成员是否包含子部门
+func BatchUpdateGroupMembersSubDepartment(c *gin.Context) {
+	var body groupMemberSubDepartmentSerializer
+	if err := c.ShouldBindJSON(&body); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "BatchUpdateGroupMembersSubDepartment")
+
+	ctl := pap.NewGroupController()
+	err := ctl.UpdateDepartmentMembersIncludeSubDepartment(
+		body.Type, body.ID, body.Departments, body.IncludeSubDepartment,
+	)
+	if err != nil {
+		err = errorWrapf(err, "ctl.UpdateDepartmentMembersIncludeSubDepartment",
+			"type=`%s`, id=`%s`, departments=`%+v`, include_sub_department=`%t`",
+			body.Type, body.ID, body.Departments, body.IncludeSubDepartment)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", gin.H{})
+}
+
 // BatchDeleteGroupMembers 批量删除subject成员
 func BatchDeleteGroupMembers(c *gin.Context) {
+	var query dryRunSerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
 	var body deleteGroupMemberSerializer
 	if err := c.ShouldBindJSON(&body); err != nil {
 		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
@@ -228,21 +268,35 @@ func BatchDeleteGroupMembers(c *gin.Context) {
 	copier.Copy(&papSubjects, &body.Members)
 
 	ctl := pap.NewGroupController()
-	typeCount, err := ctl.DeleteGroupMembers(body.Type, body.ID, papSubjects)
+	var (
+		data interface{}
+		err  error
+	)
+	if query.DryRun {
+		data, err = ctl.DryRunDeleteGroupMembers(body.Type, body.ID, papSubjects)
+	} else {
+		data, err = ctl.DeleteGroupMembers(body.Type, body.ID, papSubjects, util.GetOperator(c))
+	}
 	if err != nil {
 		err = errorx.Wrapf(err, "Handler", "ctl.DeleteGroupMembers",
-			"type=`%s`, id=`%s`, subjects=`%+v`", body.Type, body.ID, papSubjects)
+			"type=`%s`, id=`%s`, subjects=`%+v`, dryRun=`%t`", body.Type, body.ID, papSubjects, query.DryRun)
 		util.SystemErrorJSONResponse(c, err)
 		return
 	}
 
-	util.SuccessJSONResponse(c, "ok", typeCount)
+	util.SuccessJSONResponse(c, "ok", data)
 }
 
 // BatchAddGroupMembers 批量添加subject成员
 func BatchAddGroupMembers(c *gin.Context) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "BatchAddGroupMembers")
 
+	var query dryRunSerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
 	var body addGroupMembersSerializer
 	if err := c.ShouldBindJSON(&body); err != nil {
 		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
@@ -256,9 +310,10 @@ func BatchAddGroupMembers(c *gin.Context) {
 	papSubjects := make([]pap.GroupMember, 0, len(body.Members))
 	for _, m := range body.Members {
 		papSubjects = append(papSubjects, pap.GroupMember{
-			Type:      m.Type,
-			ID:        m.ID,
-			ExpiredAt: body.ExpiredAt,
+			Type:        m.Type,
+			ID:          m.ID,
+			ExpiredAt:   body.ExpiredAt,
+			EffectiveAt: body.EffectiveAt,
 		})
 	}
 
@@ -275,21 +330,39 @@ func BatchAddGroupMembers(c *gin.Context) {
 	}
 
 	ctl := pap.NewGroupController()
-	typeCount, err := ctl.CreateOrUpdateGroupMembers(body.Type, body.ID, papSubjects)
+	var data interface{}
+	if query.DryRun {
+		data, err = ctl.DryRunCreateOrUpdateGroupMembers(body.Type, body.ID, papSubjects)
+	} else {
+		data, err = ctl.CreateOrUpdateGroupMembers(body.Type, body.ID, papSubjects, util.GetOperator(c))
+	}
 	if err != nil {
+		// 嵌套用户组成环或超过最大层数
+		if errors.Is(err, service.ErrGroupNestingCycle) || errors.Is(err, service.ErrGroupNestingTooDeep) {
+			util.ConflictJSONResponse(c, err.Error())
+			return
+		}
+
+		// 违反职责分离约束
+		if errors.Is(err, service.ErrSoDConstraintViolation) {
+			util.ConflictJSONResponse(c, err.Error())
+			return
+		}
+
 		err = errorWrapf(
 			err,
 			"ctl.CreateOrUpdateGroupMembers",
-			"type=`%s`, id=`%s`, subjects=`%+v`",
+			"type=`%s`, id=`%s`, subjects=`%+v`, dryRun=`%t`",
 			body.Type,
 			body.ID,
 			papSubjects,
+			query.DryRun,
 		)
 		util.SystemErrorJSONResponse(c, err)
 		return
 	}
 
-	util.SuccessJSONResponse(c, "ok", typeCount)
+	util.SuccessJSONResponse(c, "ok", data)
 }
 
 // ListGroupMemberBeforeExpiredAt 获取小于指定过期时间的成员列表
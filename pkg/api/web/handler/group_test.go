@@ -67,7 +67,7 @@ func TestBatchAddGroupMembers(t *testing.T) {
 				ID:        "admin",
 				ExpiredAt: 10,
 			},
-		}).Return(nil, errors.New("error")).AnyTimes()
+		}, gomock.Any()).Return(nil, errors.New("error")).AnyTimes()
 		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
 			return mockCtl
 		})
@@ -97,7 +97,7 @@ func TestBatchAddGroupMembers(t *testing.T) {
 				ID:        "admin",
 				ExpiredAt: 10,
 			},
-		}).Return(map[string]int64{"user": 1, "department": 0}, nil).AnyTimes()
+		}, gomock.Any()).Return(map[string]int64{"user": 1, "department": 0}, nil).AnyTimes()
 		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
 			return mockCtl
 		})
@@ -117,6 +117,37 @@ func TestBatchAddGroupMembers(t *testing.T) {
 				},
 			}).OK()
 	})
+	t.Run("dry run ok", func(t *testing.T) {
+		ctl = gomock.NewController(t)
+		mockCtl := mock.NewMockGroupController(ctl)
+		mockCtl.EXPECT().DryRunCreateOrUpdateGroupMembers("group", "1", []pap.GroupMember{
+			{
+				Type:      "user",
+				ID:        "admin",
+				ExpiredAt: 10,
+			},
+		}).Return(pap.GroupMembersDryRunResult{AffectedSubjectCount: 1}, nil)
+		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
+			return mockCtl
+		})
+		patches.ApplyFunc(checkSubjectGroupsQuota, func(_, _ string, _ []pap.GroupMember) error { return nil })
+		defer restMock()
+
+		newRequestFunc(t).
+			Query("dry_run", "true").
+			JSON(map[string]interface{}{
+				"type":       "group",
+				"id":         "1",
+				"expired_at": 10,
+				"members": []map[string]interface{}{
+					{
+						"type": "user",
+						"id":   "admin",
+					},
+				},
+			}).OK()
+	})
+
 }
 
 func TestDeleteGroupMembers(t *testing.T) {
@@ -162,7 +193,7 @@ func TestDeleteGroupMembers(t *testing.T) {
 				Type: "user",
 				ID:   "admin",
 			},
-		}).Return(nil, errors.New("error")).AnyTimes()
+		}, gomock.Any()).Return(nil, errors.New("error")).AnyTimes()
 		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
 			return mockCtl
 		})
@@ -189,13 +220,41 @@ func TestDeleteGroupMembers(t *testing.T) {
 				Type: "user",
 				ID:   "admin",
 			},
-		}).Return(map[string]int64{"user": 1, "department": 0}, nil).AnyTimes()
+		}, gomock.Any()).Return(map[string]int64{"user": 1, "department": 0}, nil).AnyTimes()
+		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
+			return mockCtl
+		})
+		defer restMock()
+
+		newRequestFunc(t).
+			JSON(map[string]interface{}{
+				"type": "group",
+				"id":   "1",
+				"members": []map[string]interface{}{
+					{
+						"type": "user",
+						"id":   "admin",
+					},
+				},
+			}).OK()
+	})
+
+	t.Run("dry run ok", func(t *testing.T) {
+		ctl = gomock.NewController(t)
+		mockCtl := mock.NewMockGroupController(ctl)
+		mockCtl.EXPECT().DryRunDeleteGroupMembers("group", "1", []pap.Subject{
+			{
+				Type: "user",
+				ID:   "admin",
+			},
+		}).Return(pap.GroupMembersDryRunResult{AffectedSubjectCount: 1}, nil)
 		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
 			return mockCtl
 		})
 		defer restMock()
 
 		newRequestFunc(t).
+			Query("dry_run", "true").
 			JSON(map[string]interface{}{
 				"type": "group",
 				"id":   "1",
@@ -253,7 +312,7 @@ func TestUpdateGroupMembersExpiredAt(t *testing.T) {
 				ID:        "admin",
 				ExpiredAt: 10,
 			},
-		}).Return(errors.New("error")).AnyTimes()
+		}, gomock.Any()).Return(errors.New("error")).AnyTimes()
 		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
 			return mockCtl
 		})
@@ -282,7 +341,7 @@ func TestUpdateGroupMembersExpiredAt(t *testing.T) {
 				ID:        "admin",
 				ExpiredAt: 10,
 			},
-		}).Return(nil).AnyTimes()
+		}, gomock.Any()).Return(nil).AnyTimes()
 		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
 			return mockCtl
 		})
@@ -0,0 +1,140 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/gin-gonic/gin"
+	jsoniter "github.com/json-iterator/go"
+	log "github.com/sirupsen/logrus"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/util"
+)
+
+const (
+	permissionDataContentType = "application/x-ndjson"
+
+	// 单行记录的最大长度, 与策略表达式mediumtext的上限一致
+	maxPermissionRecordSize = 16 * 1024 * 1024
+)
+
+// ExportPermissionData godoc
+// @Summary Export permission data/导出系统权限数据
+// @Description export groups, group members, policies, rbac policies and temporary policies of the system as json lines
+// @ID api-web-export-permission-data
+// @Tags web
+// @Produce application/x-ndjson
+// @Param system_id path string true "System ID"
+// @Success 200 {object} pap.PermissionRecord "one record per line"
+// @Header 200 {string} X-Request-Id "the request id"
+// @Security AppCode
+// @Security AppSecret
+// @Router /api/v1/web/systems/{system_id}/permission-data [get]
+func ExportPermissionData(c *gin.Context) {
+	systemID := c.Param("system_id")
+
+	// 第一条记录输出前出错可以返回错误响应, 之后只能中断输出
+	started := false
+	start := func() {
+		if !started {
+			c.Header("Content-Type", permissionDataContentType)
+			c.Status(http.StatusOK)
+			started = true
+		}
+	}
+
+	encoder := jsoniter.NewEncoder(c.Writer)
+	ctl := pap.NewPermissionDataController()
+	err := ctl.Export(systemID, func(record pap.PermissionRecord) error {
+		start()
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "ExportPermissionData", "systemID=`%s`", systemID)
+		if started {
+			log.WithError(err).Error("export permission data interrupted")
+			c.Abort()
+			return
+		}
+
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	start()
+}
+
+// ImportPermissionData godoc
+// @Summary Import permission data/导入系统权限数据
+// @Description idempotent import of the json lines exported by ExportPermissionData, conflicts are reported
+// @ID api-web-import-permission-data
+// @Tags web
+// @Accept application/x-ndjson
+// @Produce json
+// @Param system_id path string true "System ID"
+// @Param body body pap.PermissionRecord true "one record per line"
+// @Success 200 {object} util.Response{data=pap.PermissionImportReport}
+// @Header 200 {string} X-Request-Id "the request id"
+// @Security AppCode
+// @Security AppSecret
+// @Router /api/v1/web/systems/{system_id}/permission-data [post]
+func ImportPermissionData(c *gin.Context) {
+	systemID := c.Param("system_id")
+
+	records, err := parsePermissionRecords(c.Request.Body)
+	if err != nil {
+		util.BadRequestErrorJSONResponse(c, err.Error())
+		return
+	}
+	if len(records) == 0 {
+		util.BadRequestErrorJSONResponse(c, "no permission records")
+		return
+	}
+
+	ctl := pap.NewPermissionDataController()
+	report, err := ctl.Import(systemID, records, getPAPOperator(c))
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "ImportPermissionData", "systemID=`%s`", systemID)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", report)
+}
+
+// parsePermissionRecords 解析JSON Lines格式的权限数据, 每行一条记录
+func parsePermissionRecords(r io.Reader) ([]pap.PermissionRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxPermissionRecordSize)
+
+	records := []pap.PermissionRecord{}
+	for line := 1; scanner.Scan(); line++ {
+		var record pap.PermissionRecord
+		if err := jsoniter.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d is not a valid permission record: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read permission records fail: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,146 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/abac/pap/mock"
+	"iam/pkg/util"
+)
+
+func TestExportPermissionData(t *testing.T) {
+	serve := func() *httptest.ResponseRecorder {
+		r := util.SetupRouter()
+		r.GET("/api/v1/systems/:system_id/permission-data", ExportPermissionData)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/systems/bk_test/permission-data", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("export error", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		defer ctl.Finish()
+		mockCtl := mock.NewMockPermissionDataController(ctl)
+		mockCtl.EXPECT().Export("bk_test", gomock.Any()).Return(errors.New("export fail"))
+		patches := gomonkey.ApplyFunc(pap.NewPermissionDataController, func() pap.PermissionDataController {
+			return mockCtl
+		})
+		defer patches.Reset()
+
+		resp := util.ReadResponse(serve())
+		assert.Equal(t, util.SystemError, resp.Code)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		defer ctl.Finish()
+		mockCtl := mock.NewMockPermissionDataController(ctl)
+		mockCtl.EXPECT().Export("bk_test", gomock.Any()).DoAndReturn(
+			func(_ string, write func(record pap.PermissionRecord) error) error {
+				for _, id := range []string{"1", "2"} {
+					err := write(pap.PermissionRecord{
+						Kind:    pap.PermissionRecordKindGroup,
+						Subject: pap.Subject{Type: "group", ID: id},
+					})
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		)
+		patches := gomonkey.ApplyFunc(pap.NewPermissionDataController, func() pap.PermissionDataController {
+			return mockCtl
+		})
+		defer patches.Reset()
+
+		w := serve()
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, permissionDataContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t,
+			`{"kind":"group","subject":{"type":"group","id":"1","name":""}}`+"\n"+
+				`{"kind":"group","subject":{"type":"group","id":"2","name":""}}`+"\n",
+			w.Body.String(),
+		)
+	})
+}
+
+func TestImportPermissionData(t *testing.T) {
+	newRequestFunc := util.CreateNewAPIRequestFunc(
+		"post", "/api/v1/systems/bk_test/permission-data", ImportPermissionData,
+		"/api/v1/systems/:system_id/permission-data",
+	)
+
+	body := `{"kind":"group","subject":{"type":"group","id":"1"}}` + "\n" +
+		`{"kind":"group_member","subject":{"type":"group","id":"1"},"member":{"type":"user","id":"admin"}}` + "\n"
+
+	t.Run("empty body", func(t *testing.T) {
+		newRequestFunc(t).Body("").BadRequest("no permission records")
+	})
+
+	t.Run("invalid json line", func(t *testing.T) {
+		newRequestFunc(t).Body(body + "{").BadRequest("line 3 is not a valid permission record")
+	})
+
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+
+	restMock := func() {
+		ctl.Finish()
+		if patches != nil {
+			patches.Reset()
+		}
+	}
+
+	t.Run("import error", func(t *testing.T) {
+		ctl = gomock.NewController(t)
+		mockCtl := mock.NewMockPermissionDataController(ctl)
+		mockCtl.EXPECT().Import("bk_test", gomock.Any(), gomock.Any()).Return(
+			pap.PermissionImportReport{}, errors.New("import fail"),
+		)
+		patches = gomonkey.ApplyFunc(pap.NewPermissionDataController, func() pap.PermissionDataController {
+			return mockCtl
+		})
+		defer restMock()
+
+		newRequestFunc(t).Body(body).SystemError()
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		ctl = gomock.NewController(t)
+		mockCtl := mock.NewMockPermissionDataController(ctl)
+		mockCtl.EXPECT().Import("bk_test", []pap.PermissionRecord{
+			{Kind: pap.PermissionRecordKindGroup, Subject: pap.Subject{Type: "group", ID: "1"}},
+			{
+				Kind:    pap.PermissionRecordKindGroupMember,
+				Subject: pap.Subject{Type: "group", ID: "1"},
+				Member:  &pap.Subject{Type: "user", ID: "admin"},
+			},
+		}, gomock.Any()).Return(pap.PermissionImportReport{Total: 2, Applied: 2}, nil)
+		patches = gomonkey.ApplyFunc(pap.NewPermissionDataController, func() pap.PermissionDataController {
+			return mockCtl
+		})
+		defer restMock()
+
+		newRequestFunc(t).Body(body).OK()
+	})
+}
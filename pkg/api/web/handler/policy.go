@@ -11,12 +11,15 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/TencentBlueKing/gopkg/errorx"
 	"github.com/gin-gonic/gin"
 
 	"iam/pkg/abac/pap"
 	"iam/pkg/abac/types"
 	"iam/pkg/service"
+	svctypes "iam/pkg/service/types"
 	"iam/pkg/util"
 )
 
@@ -71,9 +74,11 @@ func convertToInternalTypesPolicy(systemID string, subject types.Subject, id, te
 			ID:        policy.ActionID,
 			Attribute: types.NewActionAttribute(),
 		},
-		Expression: policy.ResourceExpression,
-		ExpiredAt:  policy.ExpiredAt,
-		TemplateID: templateID,
+		Expression:  policy.ResourceExpression,
+		ExpiredAt:   policy.ExpiredAt,
+		TemplateID:  templateID,
+		Effect:      svctypes.ConvertToPolicyEffectInt(policy.Effect),
+		EffectiveAt: policy.EffectiveAt,
 	}
 }
 
@@ -85,6 +90,7 @@ func convertToInternalTypesPolicy(systemID string, subject types.Subject, id, te
 // @Accept json
 // @Produce json
 // @Param system_id path string true "system id"
+// @Param dry_run query bool false "validate and return the would-be changes without committing"
 // @Param body body policiesAlterSerializer true "create and update policies"
 // @Success 200 {object} util.Response
 // @Header 200 {string} X-Request-Id "the request id"
@@ -92,6 +98,12 @@ func convertToInternalTypesPolicy(systemID string, subject types.Subject, id, te
 // @Security AppSecret
 // @Router /api/v1/web/systems/{system_id}/policies [post]
 func AlterPolicies(c *gin.Context) {
+	var query dryRunSerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
 	var body policiesAlterSerializer
 	if err := c.ShouldBindJSON(&body); err != nil {
 		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
@@ -126,17 +138,33 @@ func AlterPolicies(c *gin.Context) {
 	}
 
 	ctl := pap.NewPolicyController()
-	err := ctl.AlterCustomPolicies(systemID, body.Subject.Type, body.Subject.ID,
-		createPolicies, updatePolicies, body.DeletePolicyIDs)
+	var (
+		data interface{} = gin.H{}
+		err  error
+	)
+	if query.DryRun {
+		data, err = ctl.DryRunAlterCustomPolicies(systemID, body.Subject.Type, body.Subject.ID,
+			createPolicies, updatePolicies, body.DeletePolicyIDs, getPAPOperator(c))
+	} else {
+		err = ctl.AlterCustomPolicies(systemID, body.Subject.Type, body.Subject.ID,
+			createPolicies, updatePolicies, body.DeletePolicyIDs, getPAPOperator(c))
+	}
 	if err != nil {
+		// 违反职责分离约束
+		if errors.Is(err, service.ErrSoDConstraintViolation) {
+			util.ConflictJSONResponse(c, err.Error())
+			return
+		}
+
 		err = errorx.Wrapf(err, "Handler", "AlterPolicies",
-			"systemID=`%s`, subjectType=`%s`, subjectID=`%s`, createPolicies=`%+v`, updatePolicies=`%+v`",
-			systemID, body.Subject.Type, body.Subject.ID, createPolicies, updatePolicies)
+			"systemID=`%s`, subjectType=`%s`, subjectID=`%s`, createPolicies=`%+v`, updatePolicies=`%+v`, "+
+				"dryRun=`%t`",
+			systemID, body.Subject.Type, body.Subject.ID, createPolicies, updatePolicies, query.DryRun)
 		util.SystemErrorJSONResponse(c, err)
 		return
 	}
 
-	util.SuccessJSONResponse(c, "ok", gin.H{})
+	util.SuccessJSONResponse(c, "ok", data)
 }
 
 // BatchDeletePolicies godoc
@@ -146,6 +174,7 @@ func AlterPolicies(c *gin.Context) {
 // @Tags web
 // @Accept json
 // @Produce json
+// @Param dry_run query bool false "validate and return the would-be changes without committing"
 // @Param body body policiesDeleteSerializer true "delete policy info"
 // @Success 200 {object} util.Response
 // @Header 200 {string} X-Request-Id "the request id"
@@ -153,6 +182,12 @@ func AlterPolicies(c *gin.Context) {
 // @Security AppSecret
 // @Router /api/v1/web/policies [delete]
 func BatchDeletePolicies(c *gin.Context) {
+	var query dryRunSerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
 	var body policiesDeleteSerializer
 	if err := c.ShouldBindJSON(&body); err != nil {
 		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
@@ -160,15 +195,24 @@ func BatchDeletePolicies(c *gin.Context) {
 	}
 
 	ctl := pap.NewPolicyController()
-	err := ctl.DeleteByIDs(body.SystemID, body.SubjectType, body.SubjectID, body.IDs)
+	var (
+		data interface{} = gin.H{}
+		err  error
+	)
+	if query.DryRun {
+		data, err = ctl.DryRunDeleteByIDs(body.SystemID, body.SubjectType, body.SubjectID, body.IDs, getPAPOperator(c))
+	} else {
+		err = ctl.DeleteByIDs(body.SystemID, body.SubjectType, body.SubjectID, body.IDs, getPAPOperator(c))
+	}
 	if err != nil {
 		err = errorx.Wrapf(err, "Handler", "BatchDeletePolicies",
-			"subjectType=`%s`, subjectID=`%s`, IDs=`%+v`", body.SubjectType, body.SubjectID, body.IDs)
+			"subjectType=`%s`, subjectID=`%s`, IDs=`%+v`, dryRun=`%t`",
+			body.SubjectType, body.SubjectID, body.IDs, query.DryRun)
 		util.SystemErrorJSONResponse(c, err)
 		return
 	}
 
-	util.SuccessJSONResponse(c, "ok", gin.H{})
+	util.SuccessJSONResponse(c, "ok", data)
 }
 
 // ListPolicy godoc
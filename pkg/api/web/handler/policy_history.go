@@ -0,0 +1,161 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"errors"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/gin-gonic/gin"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/service"
+	"iam/pkg/util"
+)
+
+// ListPolicyHistory godoc
+// @Summary List policy history/查询subject在系统下的策略变更记录
+// @Description list policy history of the subject in the system, order by version desc
+// @ID api-web-list-policy-history
+// @Tags web
+// @Accept json
+// @Produce json
+// @Param system_id path string true "system id"
+// @Param params query listPolicyHistorySerializer true "the subject and page"
+// @Success 200 {object} util.Response{data=[]pap.PolicyHistory}
+// @Header 200 {string} X-Request-Id "the request id"
+// @Security AppCode
+// @Security AppSecret
+// @Router /api/v1/web/systems/{system_id}/policies/history [get]
+func ListPolicyHistory(c *gin.Context) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "ListPolicyHistory")
+
+	var query listPolicyHistorySerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	query.Default()
+
+	systemID := c.Param("system_id")
+
+	ctl := pap.NewPolicyController()
+	count, err := ctl.GetPolicyHistoryCount(systemID, query.SubjectType, query.SubjectID)
+	if err != nil {
+		err = errorWrapf(err, "ctl.GetPolicyHistoryCount systemID=`%s`, subjectType=`%s`, subjectID=`%s`",
+			systemID, query.SubjectType, query.SubjectID)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	histories, err := ctl.ListPagingPolicyHistory(
+		systemID, query.SubjectType, query.SubjectID, query.Limit, query.Offset,
+	)
+	if err != nil {
+		err = errorWrapf(
+			err, "ctl.ListPagingPolicyHistory systemID=`%s`, subjectType=`%s`, subjectID=`%s`, "+
+				"limit=`%d`, offset=`%d`",
+			systemID, query.SubjectType, query.SubjectID, query.Limit, query.Offset,
+		)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", gin.H{
+		"count":   count,
+		"results": histories,
+	})
+}
+
+// DiffPolicyHistory godoc
+// @Summary Diff policy history/比较subject在系统下两个版本之间的策略变化
+// @Description diff the policies between two versions, to_version=0 means the latest
+// @ID api-web-diff-policy-history
+// @Tags web
+// @Accept json
+// @Produce json
+// @Param system_id path string true "system id"
+// @Param params query diffPolicyHistorySerializer true "the subject and versions"
+// @Success 200 {object} util.Response{data=[]pap.PolicyChange}
+// @Header 200 {string} X-Request-Id "the request id"
+// @Security AppCode
+// @Security AppSecret
+// @Router /api/v1/web/systems/{system_id}/policies/history/diff [get]
+func DiffPolicyHistory(c *gin.Context) {
+	var query diffPolicyHistorySerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	systemID := c.Param("system_id")
+
+	ctl := pap.NewPolicyController()
+	changes, err := ctl.DiffPolicyHistory(
+		systemID, query.SubjectType, query.SubjectID, query.FromVersion, query.ToVersion,
+	)
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "DiffPolicyHistory",
+			"systemID=`%s`, subjectType=`%s`, subjectID=`%s`, fromVersion=`%d`, toVersion=`%d`",
+			systemID, query.SubjectType, query.SubjectID, query.FromVersion, query.ToVersion)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", changes)
+}
+
+// RollbackPolicies godoc
+// @Summary Rollback policies/将subject在系统下的策略恢复到指定版本
+// @Description rollback the policies to the version through the normal alter policies process
+// @ID api-web-rollback-policies
+// @Tags web
+// @Accept json
+// @Produce json
+// @Param system_id path string true "system id"
+// @Param body body policiesRollbackSerializer true "the subject and version"
+// @Success 200 {object} util.Response
+// @Header 200 {string} X-Request-Id "the request id"
+// @Security AppCode
+// @Security AppSecret
+// @Router /api/v1/web/systems/{system_id}/policies/rollback [post]
+func RollbackPolicies(c *gin.Context) {
+	var body policiesRollbackSerializer
+	if err := c.ShouldBindJSON(&body); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	systemID := c.Param("system_id")
+
+	ctl := pap.NewPolicyController()
+	err := ctl.RollbackPolicies(systemID, body.Subject.Type, body.Subject.ID, body.Version, getPAPOperator(c))
+	if err != nil {
+		if errors.Is(err, pap.ErrInvalidPolicyVersion) {
+			util.BadRequestErrorJSONResponse(c, err.Error())
+			return
+		}
+		// 违反职责分离约束
+		if errors.Is(err, service.ErrSoDConstraintViolation) {
+			util.ConflictJSONResponse(c, err.Error())
+			return
+		}
+
+		err = errorx.Wrapf(err, "Handler", "RollbackPolicies",
+			"systemID=`%s`, subjectType=`%s`, subjectID=`%s`, version=`%d`",
+			systemID, body.Subject.Type, body.Subject.ID, body.Version)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", gin.H{})
+}
@@ -0,0 +1,89 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/gin-gonic/gin"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/util"
+)
+
+// AnalyzePolicyRedundancy godoc
+// @Summary Analyze policy redundancy/分析subject在系统下被用户组权限覆盖或重叠的自定义权限
+// @Description report the custom policies which are covered by or overlap with the effective group policies
+// @ID api-web-analyze-policy-redundancy
+// @Tags web
+// @Accept json
+// @Produce json
+// @Param system_id path string true "system id"
+// @Param params query policyRedundancySerializer true "the subject"
+// @Success 200 {object} util.Response{data=pap.PolicyRedundancyReport}
+// @Header 200 {string} X-Request-Id "the request id"
+// @Security AppCode
+// @Security AppSecret
+// @Router /api/v1/web/systems/{system_id}/policies/redundancy [get]
+func AnalyzePolicyRedundancy(c *gin.Context) {
+	var query policyRedundancySerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	systemID := c.Param("system_id")
+
+	ctl := pap.NewPolicyController()
+	report, err := ctl.AnalyzePolicyRedundancy(systemID, query.SubjectType, query.SubjectID, false, getPAPOperator(c))
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "AnalyzePolicyRedundancy",
+			"systemID=`%s`, subjectType=`%s`, subjectID=`%s`", systemID, query.SubjectType, query.SubjectID)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", report)
+}
+
+// CleanupPolicyRedundancy godoc
+// @Summary Cleanup redundant policies/删除subject在系统下被用户组权限完全覆盖的自定义权限
+// @Description delete the redundant custom policies, the overlapping policies will only be reported
+// @ID api-web-cleanup-policy-redundancy
+// @Tags web
+// @Accept json
+// @Produce json
+// @Param system_id path string true "system id"
+// @Param body body policyRedundancyCleanupSerializer true "the subject"
+// @Success 200 {object} util.Response{data=pap.PolicyRedundancyReport}
+// @Header 200 {string} X-Request-Id "the request id"
+// @Security AppCode
+// @Security AppSecret
+// @Router /api/v1/web/systems/{system_id}/policies/redundancy/cleanup [post]
+func CleanupPolicyRedundancy(c *gin.Context) {
+	var body policyRedundancyCleanupSerializer
+	if err := c.ShouldBindJSON(&body); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	systemID := c.Param("system_id")
+
+	ctl := pap.NewPolicyController()
+	report, err := ctl.AnalyzePolicyRedundancy(systemID, body.Subject.Type, body.Subject.ID, true, getPAPOperator(c))
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "CleanupPolicyRedundancy",
+			"systemID=`%s`, subjectType=`%s`, subjectID=`%s`", systemID, body.Subject.Type, body.Subject.ID)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", report)
+}
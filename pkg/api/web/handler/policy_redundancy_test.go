@@ -0,0 +1,116 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/abac/pap/mock"
+	"iam/pkg/util"
+)
+
+func TestAnalyzePolicyRedundancy(t *testing.T) {
+	newRequestFunc := util.CreateNewAPIRequestFunc(
+		"get", "/api/v1/systems/bk_test/policies/redundancy", AnalyzePolicyRedundancy,
+		"/api/v1/systems/:system_id/policies/redundancy",
+	)
+
+	t.Run("bad request", func(t *testing.T) {
+		newRequestFunc(t).BadRequest("bad request:SubjectType is required")
+	})
+
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+
+	restMock := func() {
+		ctl.Finish()
+		if patches != nil {
+			patches.Reset()
+		}
+	}
+
+	t.Run("manager error", func(t *testing.T) {
+		ctl = gomock.NewController(t)
+		mockPolicyCtl := mock.NewMockPolicyController(ctl)
+		mockPolicyCtl.EXPECT().AnalyzePolicyRedundancy("bk_test", "user", "admin", false, gomock.Any()).Return(
+			pap.PolicyRedundancyReport{}, errors.New("error"),
+		)
+		patches = gomonkey.ApplyFunc(pap.NewPolicyController, func() pap.PolicyController {
+			return mockPolicyCtl
+		})
+		defer restMock()
+
+		newRequestFunc(t).
+			Query("subject_type", "user").
+			Query("subject_id", "admin").
+			SystemError()
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		ctl = gomock.NewController(t)
+		mockPolicyCtl := mock.NewMockPolicyController(ctl)
+		mockPolicyCtl.EXPECT().AnalyzePolicyRedundancy("bk_test", "user", "admin", false, gomock.Any()).Return(
+			pap.PolicyRedundancyReport{}, nil,
+		)
+		patches = gomonkey.ApplyFunc(pap.NewPolicyController, func() pap.PolicyController {
+			return mockPolicyCtl
+		})
+		defer restMock()
+
+		newRequestFunc(t).
+			Query("subject_type", "user").
+			Query("subject_id", "admin").
+			OK()
+	})
+}
+
+func TestCleanupPolicyRedundancy(t *testing.T) {
+	newRequestFunc := util.CreateNewAPIRequestFunc(
+		"post", "/api/v1/systems/bk_test/policies/redundancy/cleanup", CleanupPolicyRedundancy,
+		"/api/v1/systems/:system_id/policies/redundancy/cleanup",
+	)
+
+	t.Run("no json", func(t *testing.T) {
+		newRequestFunc(t).NoJSON()
+	})
+
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+
+	restMock := func() {
+		ctl.Finish()
+		if patches != nil {
+			patches.Reset()
+		}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		ctl = gomock.NewController(t)
+		mockPolicyCtl := mock.NewMockPolicyController(ctl)
+		mockPolicyCtl.EXPECT().AnalyzePolicyRedundancy("bk_test", "user", "admin", true, gomock.Any()).Return(
+			pap.PolicyRedundancyReport{DeletedPolicyIDs: []int64{1}}, nil,
+		)
+		patches = gomonkey.ApplyFunc(pap.NewPolicyController, func() pap.PolicyController {
+			return mockPolicyCtl
+		})
+		defer restMock()
+
+		newRequestFunc(t).
+			JSON(map[string]interface{}{
+				"subject": map[string]interface{}{"type": "user", "id": "admin"},
+			}).OK()
+	})
+}
@@ -0,0 +1,96 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"errors"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/gin-gonic/gin"
+
+	"iam/pkg/abac/pdp"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	svctypes "iam/pkg/service/types"
+	"iam/pkg/util"
+)
+
+// SimulatePolicies godoc
+// @Summary Simulate policies/使用候选策略对样例请求求值
+// @Description evaluate candidate policy expressions against sample requests without saving them
+// @ID api-web-simulate-policies
+// @Tags web
+// @Accept json
+// @Produce json
+// @Param body body simulatePoliciesSerializer true "candidate policies and sample requests"
+// @Success 200 {object} util.Response{data=[]types.SimulationResult}
+// @Header 200 {string} X-Request-Id "the request id"
+// @Security AppCode
+// @Security AppSecret
+// @Router /api/v1/web/policies/simulate [post]
+func SimulatePolicies(c *gin.Context) {
+	var body simulatePoliciesSerializer
+	if err := c.ShouldBindJSON(&body); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	policies := make([]types.AuthPolicy, 0, len(body.Policies))
+	for _, p := range body.Policies {
+		policies = append(policies, types.AuthPolicy{
+			ID:         p.ID,
+			Expression: p.Expression,
+			Effect:     svctypes.ConvertToPolicyEffectInt(p.Effect),
+		})
+	}
+
+	actionResourceTypes := make([]types.ActionResourceType, 0, len(body.Action.ResourceTypes))
+	for _, rt := range body.Action.ResourceTypes {
+		actionResourceTypes = append(actionResourceTypes, types.ActionResourceType{
+			System: rt.System,
+			Type:   rt.Type,
+		})
+	}
+
+	requests := make([]*request.Request, 0, len(body.Requests))
+	for _, sr := range body.Requests {
+		r := request.NewRequest()
+		r.System = body.System
+		r.Action.ID = body.Action.ID
+		r.Action.Attribute.SetResourceTypes(actionResourceTypes)
+		r.Environment = sr.Environment
+		r.WithSystem = body.WithSystem
+		for _, resource := range sr.Resources {
+			r.Resources = append(r.Resources, types.Resource{
+				System:    resource.System,
+				Type:      resource.Type,
+				ID:        resource.ID,
+				Attribute: resource.Attribute,
+			})
+		}
+		requests = append(requests, r)
+	}
+
+	results, err := pdp.Simulate(policies, requests)
+	if err != nil {
+		if errors.Is(err, pdp.ErrInvalidPolicyExpression) {
+			util.BadRequestErrorJSONResponse(c, err.Error())
+			return
+		}
+
+		err = errorx.Wrapf(err, "Handler", "SimulatePolicies",
+			"system=`%s`, action=`%s`", body.System, body.Action.ID)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", results)
+}
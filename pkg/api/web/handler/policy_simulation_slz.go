@@ -0,0 +1,49 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+type simulationResourceType struct {
+	System string `json:"system" binding:"required"`
+	Type   string `json:"type"   binding:"required"`
+}
+
+type simulationAction struct {
+	ID string `json:"id" binding:"required"`
+	// 操作关联的资源类型, 为空表示操作不关联资源类型
+	ResourceTypes []simulationResourceType `json:"resource_types" binding:"omitempty,dive"`
+}
+
+type simulationPolicy struct {
+	ID         int64  `json:"id"         binding:"required"`
+	Expression string `json:"expression"`
+	Effect     string `json:"effect"     binding:"omitempty,oneof=allow deny"`
+}
+
+type simulationResource struct {
+	System    string                 `json:"system"    binding:"required"`
+	Type      string                 `json:"type"      binding:"required"`
+	ID        string                 `json:"id"        binding:"required"`
+	Attribute map[string]interface{} `json:"attribute"`
+}
+
+type simulationRequest struct {
+	// 不传资源时只返回部分求值后的查询表达式
+	Resources   []simulationResource   `json:"resources"   binding:"omitempty,dive"`
+	Environment map[string]interface{} `json:"environment"`
+}
+
+type simulatePoliciesSerializer struct {
+	System     string              `json:"system"      binding:"required"`
+	Action     simulationAction    `json:"action"      binding:"required"`
+	Policies   []simulationPolicy  `json:"policies"    binding:"required,gt=0,dive"`
+	Requests   []simulationRequest `json:"requests"    binding:"required,gt=0,max=100,dive"`
+	WithSystem bool                `json:"with_system"`
+}
@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+
+	"iam/pkg/abac/pdp"
+	"iam/pkg/abac/types"
+	"iam/pkg/abac/types/request"
+	"iam/pkg/util"
+)
+
+func TestSimulatePolicies(t *testing.T) {
+	newRequestFunc := util.CreateNewAPIRequestFunc(
+		"post", "/api/v1/web/policies/simulate", SimulatePolicies,
+	)
+
+	body := map[string]interface{}{
+		"system": "bk_test",
+		"action": map[string]interface{}{
+			"id":             "edit",
+			"resource_types": []map[string]interface{}{{"system": "bk_test", "type": "app"}},
+		},
+		"policies": []map[string]interface{}{{
+			"id":         1,
+			"expression": `{"StringEquals": {"bk_test.app.id": ["a1"]}}`,
+		}},
+		"requests": []map[string]interface{}{{
+			"resources": []map[string]interface{}{{"system": "bk_test", "type": "app", "id": "a1"}},
+		}},
+	}
+
+	t.Run("no json", func(t *testing.T) {
+		newRequestFunc(t).NoJSON()
+	})
+
+	t.Run("bad request without policies", func(t *testing.T) {
+		newRequestFunc(t).
+			JSON(map[string]interface{}{
+				"system":   "bk_test",
+				"action":   map[string]interface{}{"id": "edit"},
+				"requests": []map[string]interface{}{{}},
+			}).BadRequest("bad request:Policies is required")
+	})
+
+	t.Run("invalid expression", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(pdp.Simulate,
+			func(_ []types.AuthPolicy, _ []*request.Request) ([]types.SimulationResult, error) {
+				return nil, pdp.ErrInvalidPolicyExpression
+			})
+		defer patches.Reset()
+
+		newRequestFunc(t).JSON(body).BadRequestContainsMessage("invalid policy expression")
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(pdp.Simulate,
+			func(policies []types.AuthPolicy, requests []*request.Request) ([]types.SimulationResult, error) {
+				if len(requests) != 1 || requests[0].Action.WithoutResourceType() || len(requests[0].Resources) != 1 {
+					t.Fatalf("unexpected requests: %+v", requests)
+				}
+				return []types.SimulationResult{{Allowed: true, PolicyID: policies[0].ID}}, nil
+			})
+		defer patches.Reset()
+
+		newRequestFunc(t).JSON(body).OK()
+	})
+}
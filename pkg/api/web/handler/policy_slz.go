@@ -38,6 +38,10 @@ type policy struct {
 	ActionID           string `json:"action_id"           binding:"required"`
 	ResourceExpression string `json:"resource_expression" binding:"required"`
 	ExpiredAt          int64  `json:"expired_at"          binding:"required,min=0,max=4102444800"`
+	// 策略效果, 默认为allow; deny策略优先于allow策略(deny-overrides)
+	Effect string `json:"effect" binding:"omitempty,oneof=allow deny"`
+	// 策略生效时间, 不传或0表示立即生效
+	EffectiveAt int64 `json:"effective_at" binding:"omitempty,min=0,max=4102444800"`
 
 	// NOTE: this field not used!
 	Environment string `json:"environment" binding:"omitempty"`
@@ -24,4 +24,7 @@ type resourceAction struct {
 	Resource         resourceSerializer `json:"resource"           binding:"required"`
 	CreatedActionIDs []string           `json:"created_action_ids" binding:"required"`
 	DeletedActionIDs []string           `json:"deleted_action_ids" binding:"required"`
+
+	// 操作关联多个资源类型时, 按操作关联资源类型的顺序传入第二个及之后的资源实例
+	RelatedResources []resourceSerializer `json:"related_resources" binding:"omitempty,dive"`
 }
@@ -76,7 +76,7 @@ func TestAlterPolicies(t *testing.T) {
 		ctl = gomock.NewController(t)
 		mockPolicyCtl := mock.NewMockPolicyController(ctl)
 		mockPolicyCtl.EXPECT().AlterCustomPolicies(
-			"bk_test", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			"bk_test", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 		).Return(
 			errors.New("alter policies fail"),
 		).AnyTimes()
@@ -98,7 +98,7 @@ func TestAlterPolicies(t *testing.T) {
 		ctl = gomock.NewController(t)
 		mockPolicyCtl := mock.NewMockPolicyController(ctl)
 		mockPolicyCtl.EXPECT().AlterCustomPolicies(
-			"bk_test", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			"bk_test", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 		).Return(
 			nil,
 		).AnyTimes()
@@ -156,7 +156,7 @@ func TestBatchDeletePolicies(t *testing.T) {
 		ctl = gomock.NewController(t)
 		mockPolicyCtl := mock.NewMockPolicyController(ctl)
 		mockPolicyCtl.EXPECT().DeleteByIDs(
-			"system", "user", "test", []int64{1, 2},
+			"system", "user", "test", []int64{1, 2}, gomock.Any(),
 		).Return(
 			errors.New("delete fail"),
 		).AnyTimes()
@@ -178,7 +178,7 @@ func TestBatchDeletePolicies(t *testing.T) {
 		ctl = gomock.NewController(t)
 		mockPolicyCtl := mock.NewMockPolicyController(ctl)
 		mockPolicyCtl.EXPECT().DeleteByIDs(
-			"system", "user", "test", []int64{1, 2},
+			"system", "user", "test", []int64{1, 2}, gomock.Any(),
 		).Return(
 			nil,
 		).AnyTimes()
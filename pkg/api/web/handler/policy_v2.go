@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/TencentBlueKing/gopkg/errorx"
 	"github.com/gin-gonic/gin"
 
 	"iam/pkg/abac/pap"
 	"iam/pkg/abac/types"
+	"iam/pkg/service"
 	svctypes "iam/pkg/service/types"
 	"iam/pkg/util"
 )
@@ -18,6 +21,7 @@ import (
 // @Accept json
 // @Produce json
 // @Param system_id path string true "system id"
+// @Param dry_run query bool false "validate and return the would-be changes without committing"
 // @Param body body policiesAlterSerializerV2 true "create and update policies"
 // @Success 200 {object} util.Response
 // @Header 200 {string} X-Request-Id "the request id"
@@ -25,6 +29,12 @@ import (
 // @Security AppSecret
 // @Router /api/v2/web/systems/{system_id}/policies [post]
 func AlterPoliciesV2(c *gin.Context) {
+	var query dryRunSerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
 	var body policiesAlterSerializerV2
 	if err := c.ShouldBindJSON(&body); err != nil {
 		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
@@ -59,6 +69,15 @@ func AlterPoliciesV2(c *gin.Context) {
 
 	resourceChangedActions := make([]types.ResourceChangedAction, 0, len(body.ResourceActions))
 	for _, ra := range body.ResourceActions {
+		relatedResources := make([]types.ThinResourceNode, 0, len(ra.RelatedResources))
+		for _, r := range ra.RelatedResources {
+			relatedResources = append(relatedResources, types.ThinResourceNode{
+				System: r.SystemID,
+				Type:   r.Type,
+				ID:     r.ID,
+			})
+		}
+
 		resourceChangedActions = append(resourceChangedActions, types.ResourceChangedAction{
 			Resource: types.ThinResourceNode{
 				System: ra.Resource.SystemID,
@@ -67,31 +86,53 @@ func AlterPoliciesV2(c *gin.Context) {
 			},
 			CreatedActionIDs: ra.CreatedActionIDs,
 			DeletedActionIDs: ra.DeletedActionIDs,
+			RelatedResources: relatedResources,
 		})
 	}
 
 	ctl := pap.NewPolicyController()
-	err := ctl.AlterGroupPolicies(
-		systemID, body.Subject.Type, body.Subject.ID, body.TemplateID,
-		createPolicies, updatePolicies, body.DeletePolicyIDs,
-		resourceChangedActions,
-		svctypes.ConvertToAuthTypeInt(body.GroupAuthType),
+	var (
+		data interface{} = gin.H{}
+		err  error
 	)
+	if query.DryRun {
+		data, err = ctl.DryRunAlterGroupPolicies(
+			systemID, body.Subject.Type, body.Subject.ID, body.TemplateID,
+			createPolicies, updatePolicies, body.DeletePolicyIDs,
+			resourceChangedActions,
+			svctypes.ConvertToAuthTypeInt(body.GroupAuthType),
+			getPAPOperator(c),
+		)
+	} else {
+		err = ctl.AlterGroupPolicies(
+			systemID, body.Subject.Type, body.Subject.ID, body.TemplateID,
+			createPolicies, updatePolicies, body.DeletePolicyIDs,
+			resourceChangedActions,
+			svctypes.ConvertToAuthTypeInt(body.GroupAuthType),
+			getPAPOperator(c),
+		)
+	}
 	if err != nil {
+		// 违反职责分离约束
+		if errors.Is(err, service.ErrSoDConstraintViolation) {
+			util.ConflictJSONResponse(c, err.Error())
+			return
+		}
+
 		err = errorx.Wrapf(
 			err,
 			"Handler",
 			"AlterPoliciesV2",
 			"systemID=`%s`, subjectType=`%s`, subjectID=`%s`, templateID=`%d`, "+
 				"createPolicies=`%+v`, updatePolicies=`%+v` deletePolicyIDs=`%+v`, "+
-				"resourceActions=`%+v groupAuthType=`%s``",
+				"resourceActions=`%+v groupAuthType=`%s`` dryRun=`%t`",
 			systemID, body.Subject.Type, body.Subject.ID, body.TemplateID,
 			createPolicies, updatePolicies, body.DeletePolicyIDs,
-			resourceChangedActions, body.GroupAuthType,
+			resourceChangedActions, body.GroupAuthType, query.DryRun,
 		)
 		util.SystemErrorJSONResponse(c, err)
 		return
 	}
 
-	util.SuccessJSONResponse(c, "ok", gin.H{})
+	util.SuccessJSONResponse(c, "ok", data)
 }
@@ -0,0 +1,97 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"github.com/TencentBlueKing/gopkg/conv"
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/gin-gonic/gin"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/util"
+)
+
+// ListSoDConstraints 查询所有职责分离约束
+func ListSoDConstraints(c *gin.Context) {
+	ctl := pap.NewSoDConstraintController()
+	constraints, err := ctl.List()
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "ListSoDConstraints", "ctl.List fail")
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", constraints)
+}
+
+// CreateSoDConstraint 创建职责分离约束
+func CreateSoDConstraint(c *gin.Context) {
+	var body sodConstraintSerializer
+	if err := c.ShouldBindJSON(&body); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	if valid, message := body.validate(); !valid {
+		util.BadRequestErrorJSONResponse(c, message)
+		return
+	}
+
+	constraint := pap.SoDConstraint{
+		Type:        body.Type,
+		System:      body.System,
+		Name:        body.Name,
+		Description: body.Description,
+		Items:       body.Items,
+	}
+
+	ctl := pap.NewSoDConstraintController()
+	err := ctl.Create(constraint)
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "CreateSoDConstraint", "ctl.Create constraint=`%+v` fail", constraint)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", nil)
+}
+
+// DeleteSoDConstraint 删除职责分离约束
+func DeleteSoDConstraint(c *gin.Context) {
+	id, err := conv.ToInt64(c.Param("id"))
+	if err != nil {
+		util.BadRequestErrorJSONResponse(c, err.Error())
+		return
+	}
+
+	ctl := pap.NewSoDConstraintController()
+	err = ctl.Delete(id)
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "DeleteSoDConstraint", "ctl.Delete id=`%d` fail", id)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", nil)
+}
+
+// ListSoDViolations 查询已存在的违反职责分离约束的授权
+func ListSoDViolations(c *gin.Context) {
+	ctl := pap.NewSoDConstraintController()
+	violations, err := ctl.ListViolations()
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "ListSoDViolations", "ctl.ListViolations fail")
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", violations)
+}
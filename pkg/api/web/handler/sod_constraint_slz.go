@@ -0,0 +1,41 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"iam/pkg/service/types"
+)
+
+type sodConstraintSerializer struct {
+	Type        string   `json:"type"        binding:"required,oneof=group action"`
+	System      string   `json:"system"`
+	Name        string   `json:"name"        binding:"required,max=255"`
+	Description string   `json:"description" binding:"max=1024"`
+	Items       []string `json:"items"       binding:"required,min=2,unique"`
+}
+
+func (s *sodConstraintSerializer) validate() (bool, string) {
+	// 互斥操作必须属于同一个系统
+	if s.Type == types.SoDConstraintTypeAction && s.System == "" {
+		return false, "system required when type is action"
+	}
+
+	if s.Type == types.SoDConstraintTypeGroup && s.System != "" {
+		return false, "system should be empty when type is group"
+	}
+
+	for _, item := range s.Items {
+		if item == "" {
+			return false, "item can not be empty"
+		}
+	}
+	return true, "valid"
+}
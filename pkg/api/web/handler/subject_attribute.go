@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/gin-gonic/gin"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/util"
+)
+
+// NOTE: 用户属性当前只能通过以下接口由调用方全量推送, 组织架构同步(org connector)不会写入用户属性, 不在本次范围内
+
+// BatchUpdateSubjectAttributes 批量覆盖用户的属性
+func BatchUpdateSubjectAttributes(c *gin.Context) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "BatchUpdateSubjectAttributes")
+
+	var subjectAttributes []subjectAttribute
+	if err := c.ShouldBindJSON(&subjectAttributes); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	if len(subjectAttributes) == 0 {
+		util.BadRequestErrorJSONResponse(c, "subject attributes can not be empty")
+		return
+	}
+
+	papSubjectAttributes := make([]pap.SubjectAttribute, 0, len(subjectAttributes))
+	for i := range subjectAttributes {
+		if valid, message := subjectAttributes[i].validate(); !valid {
+			util.BadRequestErrorJSONResponse(c, message)
+			return
+		}
+
+		papSubjectAttributes = append(papSubjectAttributes, pap.SubjectAttribute{
+			SubjectID:  subjectAttributes[i].SubjectID,
+			Attributes: subjectAttributes[i].Attributes,
+		})
+	}
+
+	ctl := pap.NewSubjectAttributeController()
+	err := ctl.BulkUpdate(papSubjectAttributes)
+	if err != nil {
+		err = errorWrapf(err, "ctl.BulkUpdate subjectAttributes=`%+v`", papSubjectAttributes)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", nil)
+}
+
+// BatchDeleteSubjectAttributes 批量删除用户的属性
+func BatchDeleteSubjectAttributes(c *gin.Context) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "BatchDeleteSubjectAttributes")
+
+	var subjectIDs []string
+	if err := c.ShouldBindJSON(&subjectIDs); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	if len(subjectIDs) == 0 {
+		util.BadRequestErrorJSONResponse(c, "subject id can not be empty")
+		return
+	}
+
+	ctl := pap.NewSubjectAttributeController()
+	err := ctl.BulkDelete(subjectIDs)
+	if err != nil {
+		err = errorWrapf(err, "ctl.BulkDelete subjectIDs=`%+v`", subjectIDs)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", nil)
+}
@@ -0,0 +1,99 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/abac/pap/mock"
+	"iam/pkg/util"
+)
+
+func TestBatchUpdateSubjectAttributes(t *testing.T) {
+	newRequestFunc := util.CreateNewAPIRequestFunc(
+		"put", "/api/v1/subject-attributes", BatchUpdateSubjectAttributes,
+	)
+
+	t.Run("no json", func(t *testing.T) {
+		newRequestFunc(t).NoJSON()
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		newRequestFunc(t).
+			JSON([]interface{}{}).
+			BadRequestContainsMessage("subject attributes can not be empty")
+	})
+
+	t.Run("invalid attribute name", func(t *testing.T) {
+		newRequestFunc(t).
+			JSON([]interface{}{
+				map[string]interface{}{
+					"id":         "admin",
+					"attributes": map[string]interface{}{"Level": 3},
+				},
+			}).BadRequestContainsMessage("invalid attribute name `Level`")
+	})
+
+	t.Run("invalid attribute value", func(t *testing.T) {
+		newRequestFunc(t).
+			JSON([]interface{}{
+				map[string]interface{}{
+					"id":         "admin",
+					"attributes": map[string]interface{}{"location": map[string]interface{}{"city": "sz"}},
+				},
+			}).BadRequestContainsMessage("invalid attribute `location` value")
+	})
+
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+
+	restMock := func() {
+		ctl.Finish()
+		if patches != nil {
+			patches.Reset()
+		}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		ctl = gomock.NewController(t)
+		mockCtl := mock.NewMockSubjectAttributeController(ctl)
+		mockCtl.EXPECT().BulkUpdate([]pap.SubjectAttribute{
+			{
+				SubjectID: "admin",
+				Attributes: map[string]interface{}{
+					"level":           float64(3),
+					"employment_type": "contractor",
+					"tags":            []interface{}{"a", "b"},
+				},
+			},
+		}).Return(nil)
+		patches = gomonkey.ApplyFunc(pap.NewSubjectAttributeController, func() pap.SubjectAttributeController {
+			return mockCtl
+		})
+		defer restMock()
+
+		newRequestFunc(t).
+			JSON([]interface{}{
+				map[string]interface{}{
+					"id": "admin",
+					"attributes": map[string]interface{}{
+						"level":           3,
+						"employment_type": "contractor",
+						"tags":            []string{"a", "b"},
+					},
+				},
+			}).OK()
+	})
+}
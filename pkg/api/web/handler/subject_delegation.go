@@ -0,0 +1,98 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"errors"
+
+	"github.com/TencentBlueKing/gopkg/conv"
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/gin-gonic/gin"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/util"
+)
+
+// ListSubjectDelegations 查询用户发起的权限委托
+func ListSubjectDelegations(c *gin.Context) {
+	var query listSubjectDelegationSerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	ctl := pap.NewSubjectDelegationController()
+	delegations, err := ctl.ListByDelegator(query.Type, query.ID)
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "ListSubjectDelegations",
+			"ctl.ListByDelegator type=`%s`, id=`%s` fail", query.Type, query.ID)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", delegations)
+}
+
+// CreateSubjectDelegation 创建权限委托
+func CreateSubjectDelegation(c *gin.Context) {
+	var body subjectDelegationSerializer
+	if err := c.ShouldBindJSON(&body); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	if valid, message := body.validate(); !valid {
+		util.BadRequestErrorJSONResponse(c, message)
+		return
+	}
+
+	delegation := pap.SubjectDelegation{
+		Delegator: pap.Subject{Type: body.Delegator.Type, ID: body.Delegator.ID},
+		Delegatee: pap.Subject{Type: body.Delegatee.Type, ID: body.Delegatee.ID},
+		System:    body.System,
+		Actions:   body.Actions,
+		ExpiredAt: body.ExpiredAt,
+	}
+
+	ctl := pap.NewSubjectDelegationController()
+	err := ctl.Create(delegation)
+	if err != nil {
+		if errors.Is(err, pap.ErrInvalidSubjectDelegation) {
+			util.BadRequestErrorJSONResponse(c, err.Error())
+			return
+		}
+
+		err = errorx.Wrapf(err, "Handler", "CreateSubjectDelegation", "ctl.Create delegation=`%+v` fail", delegation)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", nil)
+}
+
+// DeleteSubjectDelegation 撤销权限委托
+func DeleteSubjectDelegation(c *gin.Context) {
+	id, err := conv.ToInt64(c.Param("id"))
+	if err != nil {
+		util.BadRequestErrorJSONResponse(c, err.Error())
+		return
+	}
+
+	ctl := pap.NewSubjectDelegationController()
+	err = ctl.Delete(id)
+	if err != nil {
+		err = errorx.Wrapf(err, "Handler", "DeleteSubjectDelegation", "ctl.Delete id=`%d` fail", id)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", nil)
+}
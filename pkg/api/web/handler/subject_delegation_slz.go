@@ -0,0 +1,42 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+type delegationSubjectSerializer struct {
+	Type string `json:"type" binding:"required,oneof=user"`
+	ID   string `json:"id"   binding:"required"`
+}
+
+type listSubjectDelegationSerializer struct {
+	Type string `form:"type" binding:"required,oneof=user"`
+	ID   string `form:"id"   binding:"required"`
+}
+
+type subjectDelegationSerializer struct {
+	Delegator delegationSubjectSerializer `json:"delegator"  binding:"required"`
+	Delegatee delegationSubjectSerializer `json:"delegatee"  binding:"required"`
+	System    string                      `json:"system"     binding:"required"`
+	Actions   []string                    `json:"actions"    binding:"required,min=1,unique"`
+	ExpiredAt int64                       `json:"expired_at" binding:"required,min=1"`
+}
+
+func (s *subjectDelegationSerializer) validate() (bool, string) {
+	if s.Delegator == s.Delegatee {
+		return false, "delegator and delegatee can not be the same"
+	}
+
+	for _, action := range s.Actions {
+		if action == "" {
+			return false, "action can not be empty"
+		}
+	}
+	return true, "valid"
+}
@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package handler
+
+import (
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/gin-gonic/gin"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/util"
+)
+
+// ListGroupMemberHistory 查询用户组的成员变更记录
+func ListGroupMemberHistory(c *gin.Context) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "ListGroupMemberHistory")
+
+	var query listGroupMemberSerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	query.Default()
+
+	ctl := pap.NewSubjectGroupHistoryController()
+	count, err := ctl.GetGroupHistoryCount(query.Type, query.ID)
+	if err != nil {
+		err = errorWrapf(err, "ctl.GetGroupHistoryCount type=`%s`, id=`%s`", query.Type, query.ID)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	histories, err := ctl.ListPagingGroupHistory(query.Type, query.ID, query.Limit, query.Offset)
+	if err != nil {
+		err = errorWrapf(
+			err, "ctl.ListPagingGroupHistory type=`%s`, id=`%s`, limit=`%d`, offset=`%d`",
+			query.Type, query.ID, query.Limit, query.Offset,
+		)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", gin.H{
+		"count":   count,
+		"results": histories,
+	})
+}
+
+// ListSubjectGroupHistory 查询subject的用户组变更记录
+func ListSubjectGroupHistory(c *gin.Context) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf("Handler", "ListSubjectGroupHistory")
+
+	var query listSubjectGroupHistorySerializer
+	if err := c.ShouldBindQuery(&query); err != nil {
+		util.BadRequestErrorJSONResponse(c, util.ValidationErrorMessage(err))
+		return
+	}
+
+	query.Default()
+
+	ctl := pap.NewSubjectGroupHistoryController()
+	count, err := ctl.GetSubjectHistoryCount(query.Type, query.ID)
+	if err != nil {
+		err = errorWrapf(err, "ctl.GetSubjectHistoryCount type=`%s`, id=`%s`", query.Type, query.ID)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	histories, err := ctl.ListPagingSubjectHistory(query.Type, query.ID, query.Limit, query.Offset)
+	if err != nil {
+		err = errorWrapf(
+			err, "ctl.ListPagingSubjectHistory type=`%s`, id=`%s`, limit=`%d`, offset=`%d`",
+			query.Type, query.ID, query.Limit, query.Offset,
+		)
+		util.SystemErrorJSONResponse(c, err)
+		return
+	}
+
+	util.SuccessJSONResponse(c, "ok", gin.H{
+		"count":   count,
+		"results": histories,
+	})
+}
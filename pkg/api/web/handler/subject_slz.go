@@ -11,6 +11,8 @@
 package handler
 
 import (
+	"fmt"
+
 	"iam/pkg/api/common"
 	"iam/pkg/service/types"
 )
@@ -29,6 +31,11 @@ func (s *pageSerializer) Default() {
 	}
 }
 
+// dryRunSerializer dry_run=true时只预演变更, 不提交
+type dryRunSerializer struct {
+	DryRun bool `form:"dry_run"`
+}
+
 type listSubjectSerializer struct {
 	Type string `form:"type" binding:"required,oneof=user group department"`
 	pageSerializer
@@ -67,11 +74,13 @@ type listSubjectGroupSerializer struct {
 	Type            string `form:"type"              binding:"required,oneof=user department"`
 	ID              string `form:"id"                binding:"required"`
 	BeforeExpiredAt int64  `form:"before_expired_at" binding:"omitempty,min=0"`
+	// At 查询该时刻的成员关系, 0表示当前
+	At int64 `form:"at" binding:"omitempty,min=0"`
 	pageSerializer
 }
 
 type memberSerializer struct {
-	Type string `json:"type" binding:"required,oneof=user department"`
+	Type string `json:"type" binding:"required,oneof=user department group"`
 	ID   string `json:"id"   binding:"required"`
 }
 
@@ -86,6 +95,8 @@ type addGroupMembersSerializer struct {
 	Type      string `json:"type"       binding:"required,oneof=group"`
 	ID        string `json:"id"         binding:"required"`
 	ExpiredAt int64  `json:"expired_at" binding:"omitempty,min=1,max=4102444800"`
+	// 生效时间, 不传或0表示立即生效
+	EffectiveAt int64 `json:"effective_at" binding:"omitempty,min=0,max=4102444800"`
 	// 防御，避免出现一次性添加太多成员，影响性能
 	Members []memberSerializer `json:"members"    binding:"required,gt=0,lte=1000"`
 }
@@ -96,6 +107,10 @@ func (s *addGroupMembersSerializer) validate() (bool, string) {
 		return false, "policy expires time required when add group member"
 	}
 
+	if s.EffectiveAt > 0 && s.ExpiredAt > 0 && s.EffectiveAt >= s.ExpiredAt {
+		return false, "effective_at should be less than expired_at"
+	}
+
 	if len(s.Members) > 0 {
 		if valid, message := common.ValidateArray(s.Members); !valid {
 			return false, message
@@ -110,6 +125,56 @@ type subjectDepartment struct {
 	DepartmentIDs []string `json:"departments" binding:"required"`
 }
 
+type subjectAttribute struct {
+	SubjectID  string                 `json:"id"         binding:"required"`
+	Attributes map[string]interface{} `json:"attributes" binding:"required,lte=100"`
+}
+
+// validate 属性名同model中的id规则, 属性值只能是 string/number/bool 或者其数组
+func (s *subjectAttribute) validate() (bool, string) {
+	for name, value := range s.Attributes {
+		if len(name) > 64 || !common.ValidIDRegex.MatchString(name) {
+			return false, fmt.Sprintf("invalid attribute name `%s`: %s", name, common.ErrInvalidID.Error())
+		}
+
+		if !isValidSubjectAttributeValue(value, true) {
+			return false, fmt.Sprintf("invalid attribute `%s` value: should be string, number, bool or array", name)
+		}
+	}
+	return true, "valid"
+}
+
+func isValidSubjectAttributeValue(value interface{}, allowArray bool) bool {
+	switch v := value.(type) {
+	case string, float64, bool:
+		return true
+	case []interface{}:
+		if !allowArray {
+			return false
+		}
+		for _, item := range v {
+			if !isValidSubjectAttributeValue(item, false) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+type departmentParent struct {
+	DepartmentID string `json:"id"        binding:"required"`
+	ParentID     string `json:"parent_id"`
+}
+
+type groupMemberSubDepartmentSerializer struct {
+	Type                 string   `json:"type"                   binding:"required,oneof=group"`
+	ID                   string   `json:"id"                     binding:"required"`
+	Departments          []string `json:"departments"            binding:"required,gt=0,lte=1000"`
+	IncludeSubDepartment bool     `json:"include_sub_department"`
+}
+
 type updateSubjectSerializer struct {
 	Type string `json:"type" binding:"required,oneof=user group department"`
 	ID   string `json:"id"   binding:"required"`
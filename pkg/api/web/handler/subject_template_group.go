@@ -11,11 +11,14 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/TencentBlueKing/gopkg/errorx"
 	"github.com/gin-gonic/gin"
 
 	"iam/pkg/abac/pap"
 	"iam/pkg/api/common"
+	"iam/pkg/service"
 	"iam/pkg/util"
 )
 
@@ -36,8 +39,14 @@ func BatchCreateSubjectTemplateGroup(c *gin.Context) {
 	papSubjectTemplateGroups := convertToPapSubjectTemplateGroup(body)
 
 	ctl := pap.NewGroupController()
-	err := ctl.BulkCreateSubjectTemplateGroup(papSubjectTemplateGroups)
+	err := ctl.BulkCreateSubjectTemplateGroup(papSubjectTemplateGroups, util.GetOperator(c))
 	if err != nil {
+		// 违反职责分离约束
+		if errors.Is(err, service.ErrSoDConstraintViolation) {
+			util.ConflictJSONResponse(c, err.Error())
+			return
+		}
+
 		err = errorWrapf(
 			err,
 			"ctl.BulkCreateSubjectTemplateGroup",
@@ -67,7 +76,7 @@ func BatchDeleteSubjectTemplateGroup(c *gin.Context) {
 	papSubjectTemplateGroups := convertToPapSubjectTemplateGroup(body)
 
 	ctl := pap.NewGroupController()
-	err := ctl.BulkDeleteSubjectTemplateGroup(papSubjectTemplateGroups)
+	err := ctl.BulkDeleteSubjectTemplateGroup(papSubjectTemplateGroups, util.GetOperator(c))
 	if err != nil {
 		err = errorWrapf(
 			err,
@@ -99,7 +108,7 @@ func BatchUpdateSubjectTemplateGroupExpiredAt(c *gin.Context) {
 	papSubjectTemplateGroups := convertToPapSubjectTemplateGroup(body)
 
 	ctl := pap.NewGroupController()
-	err := ctl.UpdateSubjectTemplateGroupExpiredAt(papSubjectTemplateGroups)
+	err := ctl.UpdateSubjectTemplateGroupExpiredAt(papSubjectTemplateGroups, util.GetOperator(c))
 	if err != nil {
 		err = errorWrapf(
 			err,
@@ -118,11 +127,12 @@ func convertToPapSubjectTemplateGroup(body []subjectTemplateGroupSerializer) []p
 	papSubjectTemplateGroups := make([]pap.SubjectTemplateGroup, 0, len(body))
 	for _, m := range body {
 		papSubjectTemplateGroups = append(papSubjectTemplateGroups, pap.SubjectTemplateGroup{
-			Type:       m.Type,
-			ID:         m.ID,
-			TemplateID: m.TemplateID,
-			GroupID:    m.GroupID,
-			ExpiredAt:  m.ExpiredAt,
+			Type:        m.Type,
+			ID:          m.ID,
+			TemplateID:  m.TemplateID,
+			GroupID:     m.GroupID,
+			ExpiredAt:   m.ExpiredAt,
+			EffectiveAt: m.EffectiveAt,
 		})
 	}
 	return papSubjectTemplateGroups
@@ -16,4 +16,6 @@ type subjectTemplateGroupSerializer struct {
 	TemplateID int64  `json:"template_id" binding:"required"`
 	GroupID    int64  `json:"group_id"    binding:"required"`
 	ExpiredAt  int64  `json:"expired_at"  binding:"omitempty,min=1,max=4102444800"`
+	// 生效时间, 不传或0表示立即生效
+	EffectiveAt int64 `json:"effective_at" binding:"omitempty,min=0,max=4102444800"`
 }
@@ -59,7 +59,7 @@ func TestBatchCreateSubjectTemplateGroup(t *testing.T) {
 				GroupID:    1,
 				ExpiredAt:  10,
 			},
-		}).Return(errors.New("error")).AnyTimes()
+		}, gomock.Any()).Return(errors.New("error")).AnyTimes()
 		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
 			return mockCtl
 		})
@@ -86,7 +86,7 @@ func TestBatchCreateSubjectTemplateGroup(t *testing.T) {
 				GroupID:    1,
 				ExpiredAt:  10,
 			},
-		}).Return(nil).AnyTimes()
+		}, gomock.Any()).Return(nil).AnyTimes()
 		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
 			return mockCtl
 		})
@@ -140,7 +140,7 @@ func TestBatchDeleteSubjectTemplateGroup(t *testing.T) {
 				GroupID:    1,
 				ExpiredAt:  10,
 			},
-		}).Return(errors.New("error")).AnyTimes()
+		}, gomock.Any()).Return(errors.New("error")).AnyTimes()
 		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
 			return mockCtl
 		})
@@ -167,7 +167,7 @@ func TestBatchDeleteSubjectTemplateGroup(t *testing.T) {
 				GroupID:    1,
 				ExpiredAt:  10,
 			},
-		}).Return(nil).AnyTimes()
+		}, gomock.Any()).Return(nil).AnyTimes()
 		patches = gomonkey.ApplyFunc(pap.NewGroupController, func() pap.GroupController {
 			return mockCtl
 		})
@@ -10,7 +10,10 @@
 
 package handler
 
-import "iam/pkg/api/common"
+import (
+	"iam/pkg/api/common"
+	svctypes "iam/pkg/service/types"
+)
 
 // 临时权限 request body
 type temporaryPoliciesSerializer struct {
@@ -24,6 +27,13 @@ func (slz *temporaryPoliciesSerializer) validate() (bool, string) {
 			return false, message
 		}
 	}
+
+	// 临时权限不支持deny策略
+	for _, p := range slz.Policies {
+		if p.Effect == svctypes.PolicyEffectDenyStr {
+			return false, "temporary policy not support effect deny"
+		}
+	}
 	return true, ""
 }
 
@@ -12,7 +12,11 @@ package handler
 
 import (
 	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/gin-gonic/gin"
 	jsoniter "github.com/json-iterator/go"
+
+	"iam/pkg/abac/pap"
+	"iam/pkg/util"
 )
 
 func validateFields(expected, actual string) bool {
@@ -48,3 +52,12 @@ func filterFields(set *set.StringSet, obj interface{}) (data map[string]interfac
 	}
 	return
 }
+
+// getPAPOperator 变更的调用方信息, 用于记录变更历史
+func getPAPOperator(c *gin.Context) pap.Operator {
+	return pap.Operator{
+		ClientID:  util.GetClientID(c),
+		Actor:     util.GetOperator(c),
+		RequestID: util.GetRequestID(c),
+	}
+}
@@ -48,6 +48,14 @@ func Register(r *gin.RouterGroup) {
 		s.GET("/policies", handler.ListSystemPolicy)
 		// policies 变更
 		s.POST("/policies", handler.AlterPolicies)
+		// policies 变更记录
+		s.GET("/policies/history", handler.ListPolicyHistory)
+		s.GET("/policies/history/diff", handler.DiffPolicyHistory)
+		// policies 回滚到指定版本
+		s.POST("/policies/rollback", handler.RollbackPolicies)
+		// 被用户组权限覆盖或重叠的自定义权限
+		s.GET("/policies/redundancy", handler.AnalyzePolicyRedundancy)
+		s.POST("/policies/redundancy/cleanup", handler.CleanupPolicyRedundancy)
 		// 获取自定义申请的策略
 		// 根据Action删除策略
 		s.DELETE("/actions/:action_id/policies", handler.DeleteActionPolicies)
@@ -58,6 +66,10 @@ func Register(r *gin.RouterGroup) {
 
 		// 带分页 https://github.com/TencentBlueKing/bk-iam-saas/issues/1155
 		s.GET("/subject-groups", handler.ListSystemSubjectGroups)
+
+		// 系统权限数据导出/导入, JSON Lines格式
+		s.GET("/permission-data", handler.ExportPermissionData)
+		s.POST("/permission-data", handler.ImportPermissionData)
 	}
 
 	// policy
@@ -66,6 +78,8 @@ func Register(r *gin.RouterGroup) {
 		r.GET("/policies", handler.ListPolicy)
 		// 删除
 		r.DELETE("/policies", handler.BatchDeletePolicies)
+		// 使用候选策略对样例请求求值, 不会保存策略
+		r.POST("/policies/simulate", handler.SimulatePolicies)
 	}
 
 	// temporary-policy
@@ -101,8 +115,12 @@ func Register(r *gin.RouterGroup) {
 		r.DELETE("/group-members", handler.BatchDeleteGroupMembers)
 		// 批量subject成员过期时间
 		r.PUT("/group-members/expired_at", handler.BatchUpdateGroupMembersExpiredAt)
+		// 批量设置部门成员是否包含子部门
+		r.PUT("/group-members/sub_department", handler.BatchUpdateGroupMembersSubDepartment)
 		// 查询小于指定过期时间的成员列表, 批量用户组查询
 		r.GET("/group-members/query", handler.ListGroupMemberBeforeExpiredAt)
+		// 用户组成员变更记录
+		r.GET("/group-members/history", handler.ListGroupMemberHistory)
 	}
 
 	// subject-template-groups
@@ -129,10 +147,50 @@ func Register(r *gin.RouterGroup) {
 		r.DELETE("/subject-departments", handler.BatchDeleteSubjectDepartments)
 	}
 
+	// Resource: subject-attributes
+	{
+		// 批量覆盖用户的属性
+		r.PUT("/subject-attributes", handler.BatchUpdateSubjectAttributes)
+		// 批量删除用户的属性
+		r.DELETE("/subject-attributes", handler.BatchDeleteSubjectAttributes)
+	}
+
+	// Resource: department-parents
+	{
+		// 批量设置部门的上级部门
+		r.PUT("/department-parents", handler.BatchUpdateDepartmentParents)
+		// 批量删除部门的上级部门
+		r.DELETE("/department-parents", handler.BatchDeleteDepartmentParents)
+	}
+
+	// Resource: sod-constraints
+	{
+		// 查询职责分离约束
+		r.GET("/sod-constraints", handler.ListSoDConstraints)
+		// 创建职责分离约束
+		r.POST("/sod-constraints", handler.CreateSoDConstraint)
+		// 删除职责分离约束
+		r.DELETE("/sod-constraints/:id", handler.DeleteSoDConstraint)
+		// 查询已存在的违反约束的授权
+		r.GET("/sod-constraints/violations", handler.ListSoDViolations)
+	}
+
+	// Resource: subject-delegations
+	{
+		// 查询用户发起的权限委托
+		r.GET("/subject-delegations", handler.ListSubjectDelegations)
+		// 创建权限委托
+		r.POST("/subject-delegations", handler.CreateSubjectDelegation)
+		// 撤销权限委托
+		r.DELETE("/subject-delegations/:id", handler.DeleteSubjectDelegation)
+	}
+
 	// subject-groups
 	{
 		// 带分页 https://github.com/TencentBlueKing/bk-iam-saas/issues/1155
 		r.GET("/subject-groups", handler.ListSubjectGroups)
+		// subject的用户组变更记录
+		r.GET("/subject-groups/history", handler.ListSubjectGroupHistory)
 
 		// subject-groups/detail?type=user&id=tome&groups=1,2,3,4,5
 		r.GET("/subjects-groups/detail", handler.QuerySubjectGroupsDetail)
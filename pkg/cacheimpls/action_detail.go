@@ -38,12 +38,24 @@ func retrieveActionDetail(key cache.Key) (interface{}, error) {
 		return nil, err
 	}
 
+	environmentTypes, err := svc.ListRelatedEnvironmentTypes(k.SystemID, k.ActionID)
+	if err != nil {
+		return nil, err
+	}
+
+	subjectAttributes, err := svc.ListRelatedSubjectAttributes(k.SystemID, k.ActionID)
+	if err != nil {
+		return nil, err
+	}
+
 	// NOTE: you should not add new field in ActionDetail, unless you know how to upgrade
 	// 如果要加新成员, 必须变更cache名字, 防止从已有缓存数据拿不到对应的字段产生bug
 	detail := types.ActionDetail{
-		PK:            pk,
-		AuthType:      authType,
-		ResourceTypes: resourceTypes,
+		PK:                pk,
+		AuthType:          authType,
+		ResourceTypes:     resourceTypes,
+		EnvironmentTypes:  environmentTypes,
+		SubjectAttributes: subjectAttributes,
 	}
 	return detail, nil
 }
@@ -39,6 +39,8 @@ func TestGetActionDetail(t *testing.T) {
 	}, nil).AnyTimes()
 	mockService.EXPECT().GetActionPK("test", "create").Return(int64(64), nil).AnyTimes()
 	mockService.EXPECT().GetAuthType("test", "create").Return(int64(1), nil).AnyTimes()
+	mockService.EXPECT().ListRelatedEnvironmentTypes("test", "create").Return([]string{"client_ip"}, nil).AnyTimes()
+	mockService.EXPECT().ListRelatedSubjectAttributes("test", "create").Return([]string{"level"}, nil).AnyTimes()
 
 	patches := gomonkey.ApplyFunc(service.NewActionService,
 		func() service.ActionService {
@@ -55,4 +57,6 @@ func TestGetActionDetail(t *testing.T) {
 	assert.Equal(t, int64(64), detail.PK)
 	assert.Equal(t, int64(1), detail.AuthType)
 	assert.Len(t, detail.ResourceTypes, 1)
+	assert.Equal(t, []string{"client_ip"}, detail.EnvironmentTypes)
+	assert.Equal(t, []string{"level"}, detail.SubjectAttributes)
 }
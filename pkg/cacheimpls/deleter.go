@@ -76,6 +76,27 @@ func (d subjectDepartmentCacheDeleter) Execute(key cache.Key) (err error) {
 	return SubjectDepartmentCache.Delete(key)
 }
 
+type departmentAncestorCacheDeleter struct{}
+
+// Execute ...
+func (d departmentAncestorCacheDeleter) Execute(key cache.Key) (err error) {
+	return DepartmentAncestorCache.Delete(key)
+}
+
+type subjectAttributeCacheDeleter struct{}
+
+// Execute ...
+func (d subjectAttributeCacheDeleter) Execute(key cache.Key) (err error) {
+	return SubjectAttributeCache.Delete(key)
+}
+
+type subjectDelegationCacheDeleter struct{}
+
+// Execute ...
+func (d subjectDelegationCacheDeleter) Execute(key cache.Key) (err error) {
+	return SubjectDelegationCache.Delete(key)
+}
+
 type systemCacheDeleter struct{}
 
 // Execute ...
@@ -141,6 +162,48 @@ func BatchDeleteSubjectDepartmentCache(pks []int64) error {
 	return nil
 }
 
+// BatchDeleteDepartmentAncestorCache ...
+func BatchDeleteDepartmentAncestorCache(pks []int64) error {
+	keys := make([]cache.Key, 0, len(pks))
+	for _, pk := range pks {
+		key := SubjectPKCacheKey{
+			PK: pk,
+		}
+		keys = append(keys, key)
+	}
+
+	DepartmentAncestorCacheCleaner.BatchDelete(keys)
+	return nil
+}
+
+// BatchDeleteSubjectAttributeCache ...
+func BatchDeleteSubjectAttributeCache(pks []int64) error {
+	keys := make([]cache.Key, 0, len(pks))
+	for _, pk := range pks {
+		key := SubjectPKCacheKey{
+			PK: pk,
+		}
+		keys = append(keys, key)
+	}
+
+	SubjectAttributeCacheCleaner.BatchDelete(keys)
+	return nil
+}
+
+// BatchDeleteSubjectDelegationCache ...
+func BatchDeleteSubjectDelegationCache(delegateePKs []int64) error {
+	keys := make([]cache.Key, 0, len(delegateePKs))
+	for _, pk := range delegateePKs {
+		key := SubjectPKCacheKey{
+			PK: pk,
+		}
+		keys = append(keys, key)
+	}
+
+	SubjectDelegationCacheCleaner.BatchDelete(keys)
+	return nil
+}
+
 // DeleteSystemCache ...
 func DeleteSystemCache(systemID string) error {
 	key := cache.NewStringKey(systemID)
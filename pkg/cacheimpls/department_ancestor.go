@@ -0,0 +1,42 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cacheimpls
+
+import (
+	"github.com/TencentBlueKing/gopkg/cache"
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/service"
+)
+
+func retrieveDepartmentAncestor(key cache.Key) (interface{}, error) {
+	k := key.(SubjectPKCacheKey)
+
+	departmentSvc := service.NewDepartmentService()
+	ancestors, err := departmentSvc.ListAncestorDepartmentPKs([]int64{k.PK})
+	if err != nil {
+		return nil, err
+	}
+
+	return ancestors, nil
+}
+
+// GetDepartmentAncestorPKs 获取部门的所有上级部门
+func GetDepartmentAncestorPKs(pk int64) (ancestorPKs []int64, err error) {
+	key := SubjectPKCacheKey{
+		PK: pk,
+	}
+
+	err = DepartmentAncestorCache.GetInto(key, &ancestorPKs, retrieveDepartmentAncestor)
+	err = errorx.Wrapf(err, CacheLayer, "GetDepartmentAncestorPKs",
+		"DepartmentAncestorCache.Get key=`%s` fail", key.Key())
+	return
+}
@@ -22,6 +22,7 @@ import (
 
 	"iam/pkg/cache/redis"
 	"iam/pkg/service"
+	"iam/pkg/service/types"
 )
 
 const RandExpireSeconds = 60
@@ -38,16 +39,16 @@ func (k SystemResourceCacheKey) Key() string {
 	return fmt.Sprintf("%s:%d:%d:%s", k.SystemID, k.ActionResourceTypePK, k.ResourceTypePK, k.ResourceID)
 }
 
-// GetResourceActionAuthorizedGroupPKs 使用操作与资源信息拿rbac授权的用户组
-// Get Resource Action Authorized Group PKs from redis Hash
+// GetResourceActionAuthorizedGroups 使用操作与资源信息拿rbac授权的用户组
+// Get Resource Action Authorized Groups from redis Hash
 // Key: system_id:action_resource_type_pk:resource_type_pk:resource_id
 // Hash Field: action_pk
-// Hash Value: group_pks []int64
-func GetResourceActionAuthorizedGroupPKs(
+// Hash Value: groups []types.AuthorizedGroup
+func GetResourceActionAuthorizedGroups(
 	systemID string,
 	actionPK, actionResourceTypePK, resourceTypePK int64,
 	resourceID string,
-) ([]int64, error) {
+) ([]types.AuthorizedGroup, error) {
 	key := SystemResourceCacheKey{
 		SystemID:             systemID,
 		ActionResourceTypePK: actionResourceTypePK,
@@ -55,17 +56,20 @@ func GetResourceActionAuthorizedGroupPKs(
 		ResourceID:           resourceID,
 	}
 
-	groupPKs, err := getResourceActionAuthorizedGroupPKsFromCache(key, actionPK)
+	groups, err := getResourceActionAuthorizedGroupsFromCache(key, actionPK)
 	if err == nil {
-		return groupPKs, nil
+		return groups, nil
 	}
 
-	return retrieveResourceActionAuthorizedGroupPKs(key, actionPK)
+	return retrieveResourceActionAuthorizedGroups(key, actionPK)
 }
 
-func retrieveResourceActionAuthorizedGroupPKs(key SystemResourceCacheKey, actionPK int64) ([]int64, error) {
+func retrieveResourceActionAuthorizedGroups(
+	key SystemResourceCacheKey,
+	actionPK int64,
+) ([]types.AuthorizedGroup, error) {
 	svc := service.NewGroupResourcePolicyService()
-	actionGroupPKs, err := svc.GetAuthorizedActionGroupMap(
+	actionGroups, err := svc.GetAuthorizedActionGroupMap(
 		key.SystemID,
 		key.ActionResourceTypePK,
 		key.ResourceTypePK,
@@ -75,18 +79,18 @@ func retrieveResourceActionAuthorizedGroupPKs(key SystemResourceCacheKey, action
 		return nil, err
 	}
 
-	groupPKs := actionGroupPKs[actionPK]
-	setActionGroupPKs(key, actionPK, groupPKs)
+	groups := actionGroups[actionPK]
+	setActionGroups(key, actionPK, groups)
 
-	return groupPKs, nil
+	return groups, nil
 }
 
-func setActionGroupPKs(key cache.Key, actionPK int64, groupPKs []int64) error {
+func setActionGroups(key cache.Key, actionPK int64, groups []types.AuthorizedGroup) error {
 	hashKeyField := redis.HashKeyField{
 		Key:   key.Key(),
 		Field: strconv.FormatInt(actionPK, 10),
 	}
-	_bytes, err := GroupResourcePolicyCache.Marshal(groupPKs)
+	_bytes, err := GroupResourcePolicyCache.Marshal(groups)
 	if err != nil {
 		return err
 	}
@@ -108,8 +112,8 @@ func setActionGroupPKs(key cache.Key, actionPK int64, groupPKs []int64) error {
 	return err
 }
 
-// getResourceActionAuthorizedGroupPKsFromCache 从缓存拿操作与资源信息拿rbac授权的用户组
-func getResourceActionAuthorizedGroupPKsFromCache(key cache.Key, actionPK int64) ([]int64, error) {
+// getResourceActionAuthorizedGroupsFromCache 从缓存拿操作与资源信息拿rbac授权的用户组
+func getResourceActionAuthorizedGroupsFromCache(key cache.Key, actionPK int64) ([]types.AuthorizedGroup, error) {
 	hashKeyField := redis.HashKeyField{
 		Key:   key.Key(),
 		Field: strconv.FormatInt(actionPK, 10),
@@ -121,14 +125,14 @@ func getResourceActionAuthorizedGroupPKsFromCache(key cache.Key, actionPK int64)
 		return nil, err
 	}
 
-	var groupPKs []int64
-	err = GroupResourcePolicyCache.Unmarshal(conv.StringToBytes(value), &groupPKs)
+	var groups []types.AuthorizedGroup
+	err = GroupResourcePolicyCache.Unmarshal(conv.StringToBytes(value), &groups)
 	if err != nil {
 		log.WithError(err).Errorf("GroupResourcePolicyCache.Unmarshal value=`%s` fail", value)
 		return nil, err
 	}
 
-	return groupPKs, nil
+	return groups, nil
 }
 
 // DeleteResourceAuthorizedGroupPKsCache 删除资源授权的group pks缓存
@@ -23,9 +23,10 @@ import (
 	"iam/pkg/cache/redis"
 	"iam/pkg/service"
 	"iam/pkg/service/mock"
+	"iam/pkg/service/types"
 )
 
-func Test_batchSetActionGroupPKs(t *testing.T) {
+func Test_setActionGroups(t *testing.T) {
 	expiration := 5 * time.Minute
 	mockCache := redis.NewMockCache("mockCache", expiration)
 
@@ -37,9 +38,21 @@ func Test_batchSetActionGroupPKs(t *testing.T) {
 		ResourceTypePK:       int64(2),
 		ResourceID:           "resource_test",
 	}
-	err := setActionGroupPKs(key, 1, []int64{1, 2, 3})
+	err := setActionGroups(key, 1, []types.AuthorizedGroup{{GroupPK: 1}, {GroupPK: 2}, {GroupPK: 3}})
 	assert.NoError(t, err)
 
+	related := []types.AuthorizedGroup{{
+		GroupPK: 1,
+		RelatedResources: []types.RelatedResource{
+			{ActionRelatedResourceTypePK: 3, ResourceTypePK: 3, ResourceID: "c1"},
+		},
+	}}
+	err = setActionGroups(key, 2, related)
+	assert.NoError(t, err)
+	groups, err := getResourceActionAuthorizedGroupsFromCache(key, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, related, groups)
+
 	hashKeyField := redis.HashKeyField{
 		Key:   key.Key(),
 		Field: strconv.FormatInt(1, 10),
@@ -47,13 +60,13 @@ func Test_batchSetActionGroupPKs(t *testing.T) {
 	value, err := GroupResourcePolicyCache.HGet(hashKeyField)
 	assert.NoError(t, err)
 
-	var groupPKs []int64
-	err = GroupResourcePolicyCache.Unmarshal(conv.StringToBytes(value), &groupPKs)
+	groups = nil
+	err = GroupResourcePolicyCache.Unmarshal(conv.StringToBytes(value), &groups)
 	assert.NoError(t, err)
-	assert.Equal(t, []int64{1, 2, 3}, groupPKs)
+	assert.Equal(t, []types.AuthorizedGroup{{GroupPK: 1}, {GroupPK: 2}, {GroupPK: 3}}, groups)
 }
 
-func Test_getResourceActionAuthorizedGroupPKsFromCache(t *testing.T) {
+func Test_getResourceActionAuthorizedGroupsFromCache(t *testing.T) {
 	expiration := 5 * time.Minute
 	mockCache := redis.NewMockCache("mockCache", expiration)
 
@@ -65,14 +78,14 @@ func Test_getResourceActionAuthorizedGroupPKsFromCache(t *testing.T) {
 		ResourceTypePK:       int64(2),
 		ResourceID:           "resource_test",
 	}
-	err := setActionGroupPKs(key, 1, []int64{1, 2, 3})
+	err := setActionGroups(key, 1, []types.AuthorizedGroup{{GroupPK: 1}, {GroupPK: 2}, {GroupPK: 3}})
 	assert.NoError(t, err)
 
-	groupPKs, err := getResourceActionAuthorizedGroupPKsFromCache(key, 1)
+	groups, err := getResourceActionAuthorizedGroupsFromCache(key, 1)
 	assert.NoError(t, err)
-	assert.Equal(t, []int64{1, 2, 3}, groupPKs)
+	assert.Equal(t, []types.AuthorizedGroup{{GroupPK: 1}, {GroupPK: 2}, {GroupPK: 3}}, groups)
 
-	_, err = getResourceActionAuthorizedGroupPKsFromCache(key, 3)
+	_, err = getResourceActionAuthorizedGroupsFromCache(key, 3)
 	assert.Error(t, err)
 }
 
@@ -87,9 +100,9 @@ func Test_retrieveResourceAuthorizedActionGroup(t *testing.T) {
 
 	mockService := mock.NewMockGroupResourcePolicyService(ctl)
 	mockService.EXPECT().GetAuthorizedActionGroupMap("test", int64(1), int64(2), "resource_test").Return(
-		map[int64][]int64{
-			1: {1, 2, 3},
-			2: {4, 5, 6},
+		map[int64][]types.AuthorizedGroup{
+			1: {{GroupPK: 1}, {GroupPK: 2}, {GroupPK: 3}},
+			2: {{GroupPK: 4}, {GroupPK: 5}, {GroupPK: 6}},
 		}, nil).AnyTimes()
 
 	patches := gomonkey.ApplyFunc(service.NewGroupResourcePolicyService,
@@ -105,12 +118,12 @@ func Test_retrieveResourceAuthorizedActionGroup(t *testing.T) {
 		ResourceID:           "resource_test",
 	}
 
-	groupPKs, err := retrieveResourceActionAuthorizedGroupPKs(key, 1)
+	groups, err := retrieveResourceActionAuthorizedGroups(key, 1)
 	assert.NoError(t, err)
-	assert.Equal(t, []int64{1, 2, 3}, groupPKs)
+	assert.Equal(t, []types.AuthorizedGroup{{GroupPK: 1}, {GroupPK: 2}, {GroupPK: 3}}, groups)
 }
 
-func TestGetResourceActionAuthorizedGroupPKs(t *testing.T) {
+func TestGetResourceActionAuthorizedGroups(t *testing.T) {
 	key := SystemResourceCacheKey{
 		SystemID:             "test",
 		ActionResourceTypePK: int64(1),
@@ -118,23 +131,23 @@ func TestGetResourceActionAuthorizedGroupPKs(t *testing.T) {
 		ResourceID:           "resource_test",
 	}
 
-	err := setActionGroupPKs(key, 2, []int64{1, 2, 3})
+	err := setActionGroups(key, 2, []types.AuthorizedGroup{{GroupPK: 1}, {GroupPK: 2}, {GroupPK: 3}})
 	assert.NoError(t, err)
 
-	groupPKs, err := GetResourceActionAuthorizedGroupPKs("test", int64(2), int64(1), int64(2), "resource_test")
+	groups, err := GetResourceActionAuthorizedGroups("test", int64(2), int64(1), int64(2), "resource_test")
 	assert.NoError(t, err)
-	assert.Equal(t, []int64{1, 2, 3}, groupPKs)
+	assert.Equal(t, []types.AuthorizedGroup{{GroupPK: 1}, {GroupPK: 2}, {GroupPK: 3}}, groups)
 }
 
-func TestGetResourceActionAuthorizedGroupPKs_retrieve(t *testing.T) {
+func TestGetResourceActionAuthorizedGroups_retrieve(t *testing.T) {
 	ctl := gomock.NewController(t)
 	defer ctl.Finish()
 
 	mockService := mock.NewMockGroupResourcePolicyService(ctl)
 	mockService.EXPECT().GetAuthorizedActionGroupMap("test", int64(1), int64(2), "resource_test").Return(
-		map[int64][]int64{
-			1: {1, 2, 3},
-			2: {4, 5, 6},
+		map[int64][]types.AuthorizedGroup{
+			1: {{GroupPK: 1}, {GroupPK: 2}, {GroupPK: 3}},
+			2: {{GroupPK: 4}, {GroupPK: 5}, {GroupPK: 6}},
 		}, nil).Times(2)
 
 	patches := gomonkey.ApplyFunc(service.NewGroupResourcePolicyService,
@@ -148,11 +161,11 @@ func TestGetResourceActionAuthorizedGroupPKs_retrieve(t *testing.T) {
 
 	GroupResourcePolicyCache = mockCache
 
-	groupPKs, err := GetResourceActionAuthorizedGroupPKs("test", int64(2), int64(1), int64(2), "resource_test")
+	groups, err := GetResourceActionAuthorizedGroups("test", int64(2), int64(1), int64(2), "resource_test")
 	assert.NoError(t, err)
-	assert.Equal(t, []int64{4, 5, 6}, groupPKs)
+	assert.Equal(t, []types.AuthorizedGroup{{GroupPK: 4}, {GroupPK: 5}, {GroupPK: 6}}, groups)
 
-	groupPKs, err = GetResourceActionAuthorizedGroupPKs("test", int64(1), int64(1), int64(2), "resource_test")
+	groups, err = GetResourceActionAuthorizedGroups("test", int64(1), int64(1), int64(2), "resource_test")
 	assert.NoError(t, err)
-	assert.Equal(t, []int64{1, 2, 3}, groupPKs)
+	assert.Equal(t, []types.AuthorizedGroup{{GroupPK: 1}, {GroupPK: 2}, {GroupPK: 3}}, groups)
 }
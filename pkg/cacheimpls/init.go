@@ -37,9 +37,13 @@ var (
 	LocalRemoteResourceListCache    memory.Cache
 	LocalSubjectPKCache             memory.Cache
 	LocalSubjectDepartmentCache     memory.Cache
+	LocalDepartmentAncestorCache    memory.Cache
+	LocalSubjectAttributeCache      memory.Cache
+	LocalSubjectDelegationCache     memory.Cache
 	LocalAPIGatewayJWTClientIDCache memory.Cache
 	LocalActionCache                memory.Cache // for iam engine
 	LocalUnmarshaledExpressionCache *gocache.Cache
+	LocalCompiledExpressionCache    *gocache.Cache
 	LocalGroupSystemAuthTypeCache   *gocache.Cache
 	LocalActionDetailCache          memory.Cache
 	LocalSubjectBlackListCache      memory.Cache
@@ -49,6 +53,9 @@ var (
 	RemoteResourceCache     *redis.Cache
 	ResourceTypeCache       *redis.Cache
 	SubjectDepartmentCache  *redis.Cache
+	DepartmentAncestorCache *redis.Cache
+	SubjectAttributeCache   *redis.Cache
+	SubjectDelegationCache  *redis.Cache
 	SubjectPKCache          *redis.Cache
 	SubjectSystemGroupCache *redis.Cache
 
@@ -71,11 +78,14 @@ var (
 	LocalTemporaryPolicyCache *gocache.Cache
 	ChangeListCache           *redis.Cache
 
-	ActionCacheCleaner            *cleaner.CacheCleaner
-	ActionListCacheCleaner        *cleaner.CacheCleaner
-	ResourceTypeCacheCleaner      *cleaner.CacheCleaner
-	SubjectDepartmentCacheCleaner *cleaner.CacheCleaner
-	SystemCacheCleaner            *cleaner.CacheCleaner
+	ActionCacheCleaner             *cleaner.CacheCleaner
+	ActionListCacheCleaner         *cleaner.CacheCleaner
+	ResourceTypeCacheCleaner       *cleaner.CacheCleaner
+	SubjectDepartmentCacheCleaner  *cleaner.CacheCleaner
+	DepartmentAncestorCacheCleaner *cleaner.CacheCleaner
+	SubjectAttributeCacheCleaner   *cleaner.CacheCleaner
+	SubjectDelegationCacheCleaner  *cleaner.CacheCleaner
+	SystemCacheCleaner             *cleaner.CacheCleaner
 )
 
 // ErrNotExceptedTypeFromCache ...
@@ -135,6 +145,33 @@ func InitCaches(disabled bool) {
 		nil,
 	)
 
+	// 部门树变更后各实例的本地缓存无法主动清理, 过期时间不宜过长
+	LocalDepartmentAncestorCache = memory.NewCache(
+		"local_department_ancestor",
+		disabled,
+		retrieveDepartmentAncestorFromRedis,
+		1*time.Minute,
+		nil,
+	)
+
+	// 属性变更后各实例的本地缓存无法主动清理, 过期时间不宜过长
+	LocalSubjectAttributeCache = memory.NewCache(
+		"local_subject_attribute",
+		disabled,
+		retrieveSubjectAttributeFromRedis,
+		1*time.Minute,
+		nil,
+	)
+
+	// 委托变更后各实例的本地缓存无法主动清理, 过期时间不宜过长
+	LocalSubjectDelegationCache = memory.NewCache(
+		"local_subject_delegation",
+		disabled,
+		retrieveSubjectDelegationFromRedis,
+		1*time.Minute,
+		nil,
+	)
+
 	// 影响: 每次鉴权 => 理论上, 也可以改成两级cache
 
 	LocalSubjectRoleCache = memory.NewCache(
@@ -178,6 +215,7 @@ func InitCaches(disabled bool) {
 	// 无影响, 重算而已不查db
 
 	LocalUnmarshaledExpressionCache = gocache.New(30*time.Minute, 5*time.Minute)
+	LocalCompiledExpressionCache = gocache.New(30*time.Minute, 5*time.Minute)
 
 	// 影响: 每次鉴权
 
@@ -261,7 +299,7 @@ func InitCaches(disabled bool) {
 	)
 
 	ActionDetailCache = redis.NewCache(
-		"act_dtl:2",
+		"act_dtl:4",
 		30*time.Minute,
 	)
 
@@ -275,6 +313,21 @@ func InitCaches(disabled bool) {
 		30*time.Minute,
 	)
 
+	DepartmentAncestorCache = redis.NewCache(
+		"dep_anc",
+		30*time.Minute,
+	)
+
+	SubjectAttributeCache = redis.NewCache(
+		"sub_attr",
+		30*time.Minute,
+	)
+
+	SubjectDelegationCache = redis.NewCache(
+		"sub_dlg",
+		30*time.Minute,
+	)
+
 	ActionListCache = redis.NewCache(
 		"all_act:2",
 		30*time.Minute,
@@ -296,7 +349,7 @@ func InitCaches(disabled bool) {
 	)
 
 	GroupResourcePolicyCache = redis.NewCache(
-		"grp_res_pl",
+		"grp_res_pl:2",
 		30*time.Minute,
 	)
 
@@ -342,6 +395,24 @@ func InitCaches(disabled bool) {
 	)
 	go SubjectDepartmentCacheCleaner.Run()
 
+	DepartmentAncestorCacheCleaner = cleaner.NewCacheCleaner(
+		"DepartmentAncestorCacheCleaner",
+		departmentAncestorCacheDeleter{},
+	)
+	go DepartmentAncestorCacheCleaner.Run()
+
+	SubjectAttributeCacheCleaner = cleaner.NewCacheCleaner(
+		"SubjectAttributeCacheCleaner",
+		subjectAttributeCacheDeleter{},
+	)
+	go SubjectAttributeCacheCleaner.Run()
+
+	SubjectDelegationCacheCleaner = cleaner.NewCacheCleaner(
+		"SubjectDelegationCacheCleaner",
+		subjectDelegationCacheDeleter{},
+	)
+	go SubjectDelegationCacheCleaner.Run()
+
 	SystemCacheCleaner = cleaner.NewCacheCleaner("SystemCacheCleaner", systemCacheDeleter{})
 	go SystemCacheCleaner.Run()
 }
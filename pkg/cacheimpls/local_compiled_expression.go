@@ -0,0 +1,52 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cacheimpls
+
+import (
+	"errors"
+
+	"github.com/TencentBlueKing/gopkg/stringx"
+
+	"iam/pkg/abac/pdp/condition"
+)
+
+// GetCompiledResourceExpression 获取编译后的策略表达式, 以signature为key缓存
+func GetCompiledResourceExpression(
+	expression string,
+	signature string,
+	timestampNano int64,
+) (c *condition.CompiledCondition, err error) {
+	// 预防signature为空导致缓存数据冲突
+	if signature == "" {
+		signature = stringx.MD5Hash(expression)
+	}
+
+	value, exists := LocalCompiledExpressionCache.GetAfterExpirationAnchor(signature, timestampNano)
+	if !exists {
+		var cond condition.Condition
+		cond, err = GetUnmarshalledResourceExpression(expression, signature, timestampNano)
+		if err != nil {
+			return nil, err
+		}
+
+		value = condition.Compile(cond)
+		LocalCompiledExpressionCache.Set(signature, value, 0)
+	}
+
+	var ok bool
+	c, ok = value.(*condition.CompiledCondition)
+	if !ok {
+		err = errors.New("not *condition.CompiledCondition in cache")
+		return
+	}
+
+	return c, nil
+}
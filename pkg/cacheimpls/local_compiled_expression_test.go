@@ -0,0 +1,45 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cacheimpls
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	gocache "github.com/wklken/go-cache"
+)
+
+func TestGetCompiledResourceExpression(t *testing.T) {
+	LocalUnmarshaledExpressionCache = gocache.New(1*time.Minute, 5*time.Minute)
+	LocalCompiledExpressionCache = gocache.New(1*time.Minute, 5*time.Minute)
+
+	expression := `[{"system": "iam", "type": "job", "expression": {"StringEquals": {"id": ["1"]}}}]`
+
+	c, err := GetCompiledResourceExpression(expression, "", time.Now().UnixNano())
+	assert.NoError(t, err)
+	assert.NotNil(t, c)
+	assert.Equal(t, "StringEquals", c.GetName())
+
+	// hit the cache
+	c2, err := GetCompiledResourceExpression(expression, "", time.Now().UnixNano())
+	assert.NoError(t, err)
+	assert.Same(t, c, c2)
+
+	// invalid expression
+	_, err = GetCompiledResourceExpression("123", "", time.Now().UnixNano())
+	assert.Error(t, err)
+
+	// invalid value in cache
+	LocalCompiledExpressionCache.Set("abc", 1, 0)
+	_, err = GetCompiledResourceExpression(expression, "abc", time.Now().UnixNano())
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cacheimpls
+
+import (
+	"github.com/TencentBlueKing/gopkg/cache"
+	"github.com/TencentBlueKing/gopkg/errorx"
+)
+
+func retrieveDepartmentAncestorFromRedis(key cache.Key) (interface{}, error) {
+	k := key.(SubjectPKCacheKey)
+	return GetDepartmentAncestorPKs(k.PK)
+}
+
+// GetLocalDepartmentAncestorPKs ...
+func GetLocalDepartmentAncestorPKs(pk int64) (ancestorPKs []int64, err error) {
+	key := SubjectPKCacheKey{
+		PK: pk,
+	}
+
+	i, err := LocalDepartmentAncestorCache.Get(key)
+	if err != nil {
+		err = errorx.Wrapf(err, CacheLayer, "GetLocalDepartmentAncestorPKs",
+			"LocalDepartmentAncestorCache.Get pk=`%d` fail", pk)
+		return
+	}
+
+	ancestorPKs, ok := i.([]int64)
+	if !ok {
+		err = errorx.Wrapf(ErrNotExceptedTypeFromCache, CacheLayer, "GetLocalDepartmentAncestorPKs",
+			"convert ancestorPKs fail")
+		return
+	}
+	return ancestorPKs, nil
+}
@@ -78,7 +78,8 @@ func GetUnmarshalledResourceExpression(
 	return c, nil
 }
 
-func PoliciesTranslate(policies []types.AuthPolicy) (map[string]interface{}, error) {
+// PoliciesTranslate 策略列表转换为QL表达式, withSystem为true时表达式的field带上system(policy v2)
+func PoliciesTranslate(policies []types.AuthPolicy, withSystem bool) (map[string]interface{}, error) {
 	timestampNano := time.Now().UnixNano()
 
 	conditions := make([]condition.Condition, 0, len(policies))
@@ -92,5 +93,5 @@ func PoliciesTranslate(policies []types.AuthPolicy) (map[string]interface{}, err
 		conditions = append(conditions, cond)
 	}
 
-	return translate.ConditionsTranslate(conditions)
+	return translate.ConditionsTranslate(conditions, withSystem)
 }
@@ -0,0 +1,44 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cacheimpls
+
+import (
+	"github.com/TencentBlueKing/gopkg/cache"
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/service"
+	"iam/pkg/service/types"
+)
+
+func retrieveSubjectDelegation(key cache.Key) (interface{}, error) {
+	k := key.(SubjectPKCacheKey)
+
+	svc := service.NewSubjectDelegationService()
+	delegations, err := svc.ListEffectByDelegatee(k.PK)
+	if err != nil {
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+// ListSubjectDelegations 获取delegatee未过期的委托
+// NOTE: 缓存期间委托可能过期, 使用时需要再次判断过期时间
+func ListSubjectDelegations(delegateePK int64) (delegations []types.SubjectDelegation, err error) {
+	key := SubjectPKCacheKey{
+		PK: delegateePK,
+	}
+
+	err = SubjectDelegationCache.GetInto(key, &delegations, retrieveSubjectDelegation)
+	err = errorx.Wrapf(err, CacheLayer, "ListSubjectDelegations",
+		"SubjectDelegationCache.Get key=`%s` fail", key.Key())
+	return
+}
@@ -34,3 +34,17 @@ func GetSystem(systemID string) (system types.System, err error) {
 	err = errorx.Wrapf(err, CacheLayer, "GetSystem", "SystemCache.Get key=`%s` fail", key.Key())
 	return
 }
+
+// GetSystemPolicyVersion 系统provider_config中配置的策略表达式版本, 未配置时为v1
+func GetSystemPolicyVersion(systemID string) (string, error) {
+	system, err := GetSystem(systemID)
+	if err != nil {
+		return "", err
+	}
+
+	version, ok := system.ProviderConfig["policy_version"].(string)
+	if !ok || version == "" {
+		return service.PolicyVersion, nil
+	}
+	return version, nil
+}
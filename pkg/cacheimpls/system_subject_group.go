@@ -245,20 +245,15 @@ func BatchDeleteSubjectAuthSystemGroupCache(subjectPKs []int64, groupPK int64) {
 	}
 }
 
-// BatchDeleteGroupMemberSubjectSystemGroupCache 批量删除group的member的 group 缓存
+// BatchDeleteGroupMemberSubjectSystemGroupCache 批量删除group的member(包括嵌套用户组的成员)的 group 缓存
 func BatchDeleteGroupMemberSubjectSystemGroupCache(systemID string, groupPK int64) {
 	svc := service.NewGroupService()
-	members, err := svc.ListGroupMember(groupPK)
+	subjectPKs, err := svc.ListNestedGroupMemberSubjectPKs([]int64{groupPK})
 	if err != nil {
 		log.WithError(err).Errorf(
 			"BatchDeleteGroupMemberSubjectSystemGroupCache fail systemID=`%s`, groupPK=`%d`", systemID, groupPK,
 		)
 	} else {
-		subjectPKs := make([]int64, 0, len(members))
-		for _, m := range members {
-			subjectPKs = append(subjectPKs, m.SubjectPK)
-		}
-
 		err = batchDeleteSubjectSystemGroupCache([]string{systemID}, subjectPKs)
 		if err != nil {
 			log.Error(err.Error())
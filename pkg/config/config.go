@@ -39,6 +39,18 @@ type PolicyCache struct {
 	ExpirationDays int64
 }
 
+// PolicyEval 策略计算相关配置
+type PolicyEval struct {
+	// 单条策略计算出错(例如表达式解析失败)时的处理策略: skip(默认)/fail_closed/strict
+	ErrorStrategy string
+}
+
+// NestedGroup 嵌套用户组相关配置
+type NestedGroup struct {
+	// 用户组最大嵌套层数, 不配置时默认为3
+	MaxDepth int
+}
+
 // Logger ...
 type Logger struct {
 	System    LogConfig
@@ -174,6 +186,8 @@ type Config struct {
 
 	Cache       Cache
 	PolicyCache PolicyCache
+	PolicyEval  PolicyEval
+	NestedGroup NestedGroup
 	Logger      Logger
 
 	Cryptos map[string]*Crypto
@@ -0,0 +1,114 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"iam/pkg/database"
+)
+
+// DepartmentTree 部门-上级部门关系表
+type DepartmentTree struct {
+	PK           int64 `db:"pk"`
+	DepartmentPK int64 `db:"department_pk"`
+	ParentPK     int64 `db:"parent_pk"`
+}
+
+// DepartmentTreeManager ...
+type DepartmentTreeManager interface {
+	ListByDepartmentPKs(departmentPKs []int64) ([]DepartmentTree, error)
+	ListByParentPKs(parentPKs []int64) ([]DepartmentTree, error)
+
+	BulkCreateWithTx(tx *sqlx.Tx, departmentTrees []DepartmentTree) error
+	BulkDeleteByDepartmentPKsWithTx(tx *sqlx.Tx, departmentPKs []int64) error
+}
+
+type departmentTreeManager struct {
+	DB *sqlx.DB
+}
+
+// NewDepartmentTreeManager ...
+func NewDepartmentTreeManager() DepartmentTreeManager {
+	return &departmentTreeManager{
+		DB: database.GetDefaultDBClient().DB,
+	}
+}
+
+// ListByDepartmentPKs 查询部门的上级部门
+func (m *departmentTreeManager) ListByDepartmentPKs(departmentPKs []int64) (
+	departmentTrees []DepartmentTree, err error,
+) {
+	if len(departmentPKs) == 0 {
+		return
+	}
+
+	query := `SELECT
+		 pk,
+		 department_pk,
+		 parent_pk
+		 FROM department_tree
+		 WHERE department_pk IN (?)`
+	err = database.SqlxSelect(m.DB, &departmentTrees, query, departmentPKs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return departmentTrees, nil
+	}
+	return
+}
+
+// ListByParentPKs 查询部门的直接下级部门
+func (m *departmentTreeManager) ListByParentPKs(parentPKs []int64) (departmentTrees []DepartmentTree, err error) {
+	if len(parentPKs) == 0 {
+		return
+	}
+
+	query := `SELECT
+		 pk,
+		 department_pk,
+		 parent_pk
+		 FROM department_tree
+		 WHERE parent_pk IN (?)`
+	err = database.SqlxSelect(m.DB, &departmentTrees, query, parentPKs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return departmentTrees, nil
+	}
+	return
+}
+
+// BulkCreateWithTx ...
+func (m *departmentTreeManager) BulkCreateWithTx(tx *sqlx.Tx, departmentTrees []DepartmentTree) error {
+	if len(departmentTrees) == 0 {
+		return nil
+	}
+
+	sql := `INSERT INTO department_tree (
+		department_pk,
+		parent_pk
+	) VALUES (
+		:department_pk,
+		:parent_pk)`
+	return database.SqlxBulkInsertWithTx(tx, sql, departmentTrees)
+}
+
+// BulkDeleteByDepartmentPKsWithTx ...
+func (m *departmentTreeManager) BulkDeleteByDepartmentPKsWithTx(tx *sqlx.Tx, departmentPKs []int64) error {
+	if len(departmentPKs) == 0 {
+		return nil
+	}
+
+	sql := `DELETE FROM department_tree WHERE department_pk IN (?)`
+	return database.SqlxDeleteWithTx(tx, sql, departmentPKs)
+}
@@ -0,0 +1,89 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database"
+)
+
+func Test_departmentTreeManager_ListByDepartmentPKs(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, department_pk, parent_pk FROM department_tree WHERE department_pk IN`
+		mockRows := sqlmock.NewRows([]string{"pk", "department_pk", "parent_pk"}).AddRow(int64(1), int64(2), int64(3))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(2)).WillReturnRows(mockRows)
+
+		manager := &departmentTreeManager{DB: db}
+		departmentTrees, err := manager.ListByDepartmentPKs([]int64{2})
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []DepartmentTree{{PK: 1, DepartmentPK: 2, ParentPK: 3}}, departmentTrees)
+	})
+}
+
+func Test_departmentTreeManager_ListByParentPKs(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, department_pk, parent_pk FROM department_tree WHERE parent_pk IN`
+		mockRows := sqlmock.NewRows([]string{"pk", "department_pk", "parent_pk"}).AddRow(int64(1), int64(2), int64(3))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(3)).WillReturnRows(mockRows)
+
+		manager := &departmentTreeManager{DB: db}
+		departmentTrees, err := manager.ListByParentPKs([]int64{3})
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []DepartmentTree{{PK: 1, DepartmentPK: 2, ParentPK: 3}}, departmentTrees)
+	})
+}
+
+func Test_departmentTreeManager_BulkCreateWithTx(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^INSERT INTO department_tree`).WithArgs(
+			int64(2), int64(3),
+		).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+
+		manager := &departmentTreeManager{DB: db}
+		err = manager.BulkCreateWithTx(tx, []DepartmentTree{{DepartmentPK: 2, ParentPK: 3}})
+
+		tx.Commit()
+
+		assert.NoError(t, err, "query from db fail.")
+	})
+}
+
+func Test_departmentTreeManager_BulkDeleteByDepartmentPKsWithTx(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^DELETE FROM department_tree WHERE department_pk IN`).WithArgs(
+			int64(2), int64(3),
+		).WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectCommit()
+
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+
+		manager := &departmentTreeManager{DB: db}
+		err = manager.BulkDeleteByDepartmentPKsWithTx(tx, []int64{2, 3})
+
+		tx.Commit()
+
+		assert.NoError(t, err, "query from db fail.")
+	})
+}
@@ -110,7 +110,9 @@ func (m *engineAbacPolicyManager) selectBetweenPK(
 		action_pk,
 		expression_pk,
 		expired_at,
+		effective_at,
 		template_id,
+		effect,
 		updated_at
 		FROM policy
 		WHERE expired_at > ?
@@ -125,7 +127,9 @@ func (m *engineAbacPolicyManager) selectByPKs(policies *[]EngineAbacPolicy, pks
 		action_pk,
 		expression_pk,
 		expired_at,
+		effective_at,
 		template_id,
+		effect,
 		updated_at
 		FROM policy
 		WHERE pk IN (?)`
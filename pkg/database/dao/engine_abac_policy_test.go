@@ -61,8 +61,9 @@ func Test_engineAbacPolicyManager_ListBetweenPK(t *testing.T) {
 		now := time.Unix(1617457847, 0)
 
 		mockRows := sqlmock.NewRows([]string{
-			"pk", "subject_pk", "action_pk", "expression_pk", "expired_at", "template_id", "updated_at",
-		}).AddRow(int64(1), int64(1), int64(1), int64(1), int64(1), int64(1), now)
+			"pk", "subject_pk", "action_pk", "expression_pk", "expired_at", "effective_at", "template_id", "effect",
+			"updated_at",
+		}).AddRow(int64(1), int64(1), int64(1), int64(1), int64(1), int64(0), int64(1), int64(1), now)
 		mock.ExpectQuery(
 			`SELECT
 			pk,
@@ -70,7 +71,9 @@ func Test_engineAbacPolicyManager_ListBetweenPK(t *testing.T) {
 			action_pk,
 			expression_pk,
 			expired_at,
+			effective_at,
 			template_id,
+			effect,
 			updated_at
 			FROM policy
 			WHERE expired_at > .*
@@ -92,6 +95,7 @@ func Test_engineAbacPolicyManager_ListBetweenPK(t *testing.T) {
 
 				ExpiredAt:  int64(1),
 				TemplateID: int64(1),
+				Effect:     int64(1),
 			},
 			UpdatedAt: now,
 		}
@@ -105,8 +109,9 @@ func Test_engineAbacPolicyManager_ListByPKs(t *testing.T) {
 		now := time.Unix(1617457847, 0)
 
 		mockRows := sqlmock.NewRows([]string{
-			"pk", "subject_pk", "action_pk", "expression_pk", "expired_at", "template_id", "updated_at",
-		}).AddRow(int64(1), int64(1), int64(1), int64(1), int64(1), int64(1), now)
+			"pk", "subject_pk", "action_pk", "expression_pk", "expired_at", "effective_at", "template_id", "effect",
+			"updated_at",
+		}).AddRow(int64(1), int64(1), int64(1), int64(1), int64(1), int64(0), int64(1), int64(1), now)
 		mock.ExpectQuery(
 			`SELECT
 			pk,
@@ -114,7 +119,9 @@ func Test_engineAbacPolicyManager_ListByPKs(t *testing.T) {
 			action_pk,
 			expression_pk,
 			expired_at,
+			effective_at,
 			template_id,
+			effect,
 			updated_at
 			FROM policy
 			WHERE pk IN`,
@@ -133,6 +140,7 @@ func Test_engineAbacPolicyManager_ListByPKs(t *testing.T) {
 
 				ExpiredAt:  int64(1),
 				TemplateID: int64(1),
+				Effect:     int64(1),
 			},
 			UpdatedAt: now,
 		}
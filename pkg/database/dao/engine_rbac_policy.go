@@ -39,8 +39,9 @@ type EngineRbacPolicy struct {
 	ActionRelatedResourceTypePK int64  `db:"action_related_resource_type_pk"` // 操作关联的资源类型
 
 	// 授权的资源实例
-	ResourceTypePK int64  `db:"resource_type_pk"`
-	ResourceID     string `db:"resource_id"`
+	ResourceTypePK   int64  `db:"resource_type_pk"`
+	ResourceID       string `db:"resource_id"`
+	RelatedResources string `db:"related_resources"` // json存储了其它资源类型授权的资源实例
 
 	UpdatedAt time.Time `db:"updated_at"`
 }
@@ -78,6 +79,7 @@ func (m *engineRbacPolicyManager) ListBetweenPK(
 		action_related_resource_type_pk,
 		resource_type_pk,
 		resource_id,
+		related_resources,
 		updated_at
 		FROM rbac_group_resource_policy
 		WHERE pk BETWEEN ? AND ?`
@@ -99,6 +101,7 @@ func (m *engineRbacPolicyManager) ListByPKs(pks []int64) (policies []EngineRbacP
 		action_related_resource_type_pk,
 		resource_type_pk,
 		resource_id,
+		related_resources,
 		updated_at
 		FROM rbac_group_resource_policy
 		WHERE pk IN (?)`
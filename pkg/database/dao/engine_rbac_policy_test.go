@@ -61,8 +61,9 @@ func Test_engineRbacPolicyManager_ListBetweenPK(t *testing.T) {
 		now := time.Unix(1617457847, 0)
 
 		mockRows := sqlmock.NewRows([]string{
-			"pk", "group_pk", "system_id", "template_id", "action_pks", "action_related_resource_type_pk", "resource_type_pk", "resource_id", "updated_at",
-		}).AddRow(int64(1), int64(1), "", int64(1), "", int64(1), int64(1), "", now)
+			"pk", "group_pk", "system_id", "template_id", "action_pks", "action_related_resource_type_pk", "resource_type_pk", "resource_id",
+			"related_resources", "updated_at",
+		}).AddRow(int64(1), int64(1), "", int64(1), "", int64(1), int64(1), "", "", now)
 		mock.ExpectQuery(
 			`SELECT
 			pk,
@@ -73,6 +74,7 @@ func Test_engineRbacPolicyManager_ListBetweenPK(t *testing.T) {
 			action_related_resource_type_pk,
 			resource_type_pk,
 			resource_id,
+			related_resources,
 			updated_at
 			FROM rbac_group_resource_policy
 			WHERE pk BETWEEN .* AND .*`,
@@ -104,8 +106,9 @@ func Test_engineRbacPolicyManager_ListByPKs(t *testing.T) {
 		now := time.Unix(1617457847, 0)
 
 		mockRows := sqlmock.NewRows([]string{
-			"pk", "group_pk", "system_id", "template_id", "action_pks", "action_related_resource_type_pk", "resource_type_pk", "resource_id", "updated_at",
-		}).AddRow(int64(1), int64(1), "", int64(1), "", int64(1), int64(1), "", now)
+			"pk", "group_pk", "system_id", "template_id", "action_pks", "action_related_resource_type_pk", "resource_type_pk", "resource_id",
+			"related_resources", "updated_at",
+		}).AddRow(int64(1), int64(1), "", int64(1), "", int64(1), int64(1), "", "", now)
 		mock.ExpectQuery(
 			`SELECT
 			pk,
@@ -116,6 +119,7 @@ func Test_engineRbacPolicyManager_ListByPKs(t *testing.T) {
 			action_related_resource_type_pk,
 			resource_type_pk,
 			resource_id,
+			related_resources,
 			updated_at
 			FROM rbac_group_resource_policy
 			WHERE pk IN`,
@@ -39,11 +39,14 @@ type GroupResourcePolicy struct {
 	// 授权的资源实例
 	ResourceTypePK int64  `db:"resource_type_pk"`
 	ResourceID     string `db:"resource_id"`
+	// 操作关联多个资源类型时, 其它资源类型授权的资源实例, json存储, 只关联一个资源类型时为空
+	RelatedResources string `db:"related_resources"`
 }
 
 type ThinGroupResourcePolicy struct {
-	GroupPK   int64  `db:"group_pk"`
-	ActionPKs string `db:"action_pks"`
+	GroupPK          int64  `db:"group_pk"`
+	ActionPKs        string `db:"action_pks"`
+	RelatedResources string `db:"related_resources"`
 }
 
 type GroupResourcePolicyManager interface {
@@ -54,6 +57,8 @@ type GroupResourcePolicyManager interface {
 		actionRelatedResourceTypePK int64,
 	) (policies []GroupResourcePolicy, err error)
 	ListActionPKsByGroup(groupPK int64) ([]string, error)
+	ListThinBySystem(systemID string) (policies []ThinGroupResourcePolicy, err error)
+	ListPagingBySystem(systemID string, lastPK, limit int64) (policies []GroupResourcePolicy, err error)
 	BulkCreateWithTx(tx *sqlx.Tx, policies []GroupResourcePolicy) error
 	BulkUpdateActionPKsWithTx(tx *sqlx.Tx, policies []GroupResourcePolicy) error
 	BulkDeleteByPKsWithTx(tx *sqlx.Tx, pks []int64) error
@@ -93,7 +98,8 @@ func (m *groupResourcePolicyManager) ListBySignatures(signatures []string) (poli
 		action_pks,
 		action_related_resource_type_pk,
 		resource_type_pk,
-		resource_id
+		resource_id,
+		related_resources
 		FROM rbac_group_resource_policy
 		WHERE signature IN (?)`
 	err = database.SqlxSelect(m.DB, &policies, query, signatures)
@@ -117,7 +123,8 @@ func (m *groupResourcePolicyManager) ListByGroupSystemActionRelatedResourceType(
 		action_pks,
 		action_related_resource_type_pk,
 		resource_type_pk,
-		resource_id
+		resource_id,
+		related_resources
 		FROM rbac_group_resource_policy
 		WHERE group_pk = ?
 		AND action_related_resource_type_pk = ?
@@ -143,7 +150,8 @@ func (m *groupResourcePolicyManager) BulkCreateWithTx(tx *sqlx.Tx, policies []Gr
 		action_pks,
 		action_related_resource_type_pk,
 		resource_type_pk,
-		resource_id
+		resource_id,
+		related_resources
 	) VALUES (
 		:signature,
 		:group_pk,
@@ -152,7 +160,8 @@ func (m *groupResourcePolicyManager) BulkCreateWithTx(tx *sqlx.Tx, policies []Gr
 		:action_pks,
 		:action_related_resource_type_pk,
 		:resource_type_pk,
-		:resource_id
+		:resource_id,
+		:related_resources
 	)`
 
 	return database.SqlxBulkInsertWithTx(tx, sql, policies)
@@ -184,7 +193,8 @@ func (m *groupResourcePolicyManager) ListThinByResource(
 ) (policies []ThinGroupResourcePolicy, err error) {
 	query := `SELECT 
 		group_pk,
-		action_pks
+		action_pks,
+		related_resources
 		FROM rbac_group_resource_policy
 		WHERE system_id = ?
 		AND action_related_resource_type_pk = ?
@@ -211,6 +221,49 @@ func (m *groupResourcePolicyManager) ListActionPKsByGroup(groupPK int64) (action
 	return actionPKsList, err
 }
 
+// ListThinBySystem 查询系统下所有用户组的授权操作
+func (m *groupResourcePolicyManager) ListThinBySystem(systemID string) (policies []ThinGroupResourcePolicy, err error) {
+	query := `SELECT
+		group_pk,
+		action_pks,
+		related_resources
+		FROM rbac_group_resource_policy
+		WHERE system_id = ?`
+	err = database.SqlxSelect(m.DB, &policies, query, systemID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return policies, nil
+	}
+	return
+}
+
+// ListPagingBySystem 按pk顺序分页查询系统下的授权, 返回pk大于lastPK的记录
+func (m *groupResourcePolicyManager) ListPagingBySystem(
+	systemID string,
+	lastPK, limit int64,
+) (policies []GroupResourcePolicy, err error) {
+	query := `SELECT
+		pk,
+		signature,
+		group_pk,
+		template_id,
+		system_id,
+		action_pks,
+		action_related_resource_type_pk,
+		resource_type_pk,
+		resource_id,
+		related_resources
+		FROM rbac_group_resource_policy
+		WHERE system_id = ?
+		AND pk > ?
+		ORDER BY pk
+		LIMIT ?`
+	err = database.SqlxSelect(m.DB, &policies, query, systemID, lastPK, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return policies, nil
+	}
+	return
+}
+
 // BulkDeleteByGroupPKsWithTx ...
 func (m *groupResourcePolicyManager) BulkDeleteByGroupPKsWithTx(
 	tx *sqlx.Tx,
@@ -56,7 +56,7 @@ var _ = Describe("GroupResourcePolicyManager", func() {
 		mockRows := database.NewMockRows(mock, []interface{}{policy}...)
 		mock.ExpectQuery(
 			"^SELECT pk, signature, group_pk, template_id, system_id," +
-				" action_pks, action_related_resource_type_pk, resource_type_pk, resource_id" +
+				" action_pks, action_related_resource_type_pk, resource_type_pk, resource_id, related_resources" +
 				" FROM rbac_group_resource_policy WHERE signature IN (.*)$",
 		).WithArgs(policy.Signature).WillReturnRows(mockRows)
 
@@ -73,7 +73,7 @@ var _ = Describe("GroupResourcePolicyManager", func() {
 		mockRows := database.NewMockRows(mock, []interface{}{policy}...)
 		mock.ExpectQuery(
 			"^SELECT pk, signature, group_pk, template_id, system_id,"+
-				" action_pks, action_related_resource_type_pk, resource_type_pk, resource_id"+
+				" action_pks, action_related_resource_type_pk, resource_type_pk, resource_id, related_resources"+
 				" FROM rbac_group_resource_policy WHERE "+
 				"group_pk = (.*) AND action_related_resource_type_pk = (.*) AND system_id = (.*)$",
 		).WithArgs(int64(1), int64(3), "test").WillReturnRows(mockRows)
@@ -93,6 +93,7 @@ var _ = Describe("GroupResourcePolicyManager", func() {
 		).WithArgs(
 			policy.Signature, policy.GroupPK, policy.TemplateID, policy.SystemID,
 			policy.ActionPKs, policy.ActionRelatedResourceTypePK, policy.ResourceTypePK, policy.ResourceID,
+			policy.RelatedResources,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
@@ -148,7 +149,7 @@ var _ = Describe("GroupResourcePolicyManager", func() {
 		mockRows := database.NewMockRows(mock, []interface{}{thinPolicy}...)
 		mock.ExpectQuery(
 			`^SELECT 
-			group_pk, action_pks
+			group_pk, action_pks, related_resources
 			FROM rbac_group_resource_policy
 			WHERE system_id = (.*)
 			AND action_related_resource_type_pk = (.*)
@@ -175,6 +176,43 @@ var _ = Describe("GroupResourcePolicyManager", func() {
 		assert.NoError(GinkgoT(), err)
 		assert.Equal(GinkgoT(), []string{"[1,2,3]", "[4,5,6]"}, actionPKs)
 	})
+
+	It("ListThinBySystem", func() {
+		mockRows := sqlmock.NewRows([]string{"group_pk", "action_pks", "related_resources"}).
+			AddRow(int64(1), "[1,2,3]", "")
+		mock.ExpectQuery(
+			"^SELECT group_pk, action_pks, related_resources FROM rbac_group_resource_policy WHERE system_id = (.*)$",
+		).WithArgs("test").WillReturnRows(mockRows)
+
+		policies, err := manager.ListThinBySystem("test")
+
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), []ThinGroupResourcePolicy{{GroupPK: 1, ActionPKs: "[1,2,3]"}}, policies)
+	})
+
+	It("ListPagingBySystem", func() {
+		mockRows := sqlmock.NewRows([]string{
+			"pk", "signature", "group_pk", "template_id", "system_id", "action_pks",
+			"action_related_resource_type_pk", "resource_type_pk", "resource_id", "related_resources",
+		}).AddRow(int64(2), "sig", int64(1), int64(0), "test", "[1,2,3]", int64(1), int64(1), "1", "")
+		mock.ExpectQuery(
+			"^SELECT (.*) FROM rbac_group_resource_policy WHERE system_id = (.*) AND pk > (.*) ORDER BY pk LIMIT (.*)$",
+		).WithArgs("test", int64(1), int64(100)).WillReturnRows(mockRows)
+
+		policies, err := manager.ListPagingBySystem("test", 1, 100)
+
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), []GroupResourcePolicy{{
+			PK:                          2,
+			Signature:                   "sig",
+			GroupPK:                     1,
+			SystemID:                    "test",
+			ActionPKs:                   "[1,2,3]",
+			ActionRelatedResourceTypePK: 1,
+			ResourceTypePK:              1,
+			ResourceID:                  "1",
+		}}, policies)
+	})
 })
 
 func Test_groupResourcePolicyManager_BulkDeleteByGroupPKsWithTx(t *testing.T) {
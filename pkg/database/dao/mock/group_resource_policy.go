@@ -151,6 +151,21 @@ func (mr *MockGroupResourcePolicyManagerMockRecorder) ListBySignatures(signature
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBySignatures", reflect.TypeOf((*MockGroupResourcePolicyManager)(nil).ListBySignatures), signatures)
 }
 
+// ListPagingBySystem mocks base method.
+func (m *MockGroupResourcePolicyManager) ListPagingBySystem(systemID string, lastPK, limit int64) ([]dao.GroupResourcePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPagingBySystem", systemID, lastPK, limit)
+	ret0, _ := ret[0].([]dao.GroupResourcePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPagingBySystem indicates an expected call of ListPagingBySystem.
+func (mr *MockGroupResourcePolicyManagerMockRecorder) ListPagingBySystem(systemID, lastPK, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPagingBySystem", reflect.TypeOf((*MockGroupResourcePolicyManager)(nil).ListPagingBySystem), systemID, lastPK, limit)
+}
+
 // ListThinByResource mocks base method.
 func (m *MockGroupResourcePolicyManager) ListThinByResource(systemID string, actionResourceTypePK, resourceTypePK int64, resourceID string) ([]dao.ThinGroupResourcePolicy, error) {
 	m.ctrl.T.Helper()
@@ -165,3 +180,18 @@ func (mr *MockGroupResourcePolicyManagerMockRecorder) ListThinByResource(systemI
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThinByResource", reflect.TypeOf((*MockGroupResourcePolicyManager)(nil).ListThinByResource), systemID, actionResourceTypePK, resourceTypePK, resourceID)
 }
+
+// ListThinBySystem mocks base method.
+func (m *MockGroupResourcePolicyManager) ListThinBySystem(systemID string) ([]dao.ThinGroupResourcePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListThinBySystem", systemID)
+	ret0, _ := ret[0].([]dao.ThinGroupResourcePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListThinBySystem indicates an expected call of ListThinBySystem.
+func (mr *MockGroupResourcePolicyManagerMockRecorder) ListThinBySystem(systemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThinBySystem", reflect.TypeOf((*MockGroupResourcePolicyManager)(nil).ListThinBySystem), systemID)
+}
@@ -50,18 +50,18 @@ func (mr *MockOpenAbacPolicyManagerMockRecorder) Get(pk interface{}) *gomock.Cal
 }
 
 // GetCountByActionBeforeExpiredAt mocks base method.
-func (m *MockOpenAbacPolicyManager) GetCountByActionBeforeExpiredAt(actionPK, expiredAt int64) (int64, error) {
+func (m *MockOpenAbacPolicyManager) GetCountByActionBeforeExpiredAt(actionPK, expiredAt, effectiveAt int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCountByActionBeforeExpiredAt", actionPK, expiredAt)
+	ret := m.ctrl.Call(m, "GetCountByActionBeforeExpiredAt", actionPK, expiredAt, effectiveAt)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCountByActionBeforeExpiredAt indicates an expected call of GetCountByActionBeforeExpiredAt.
-func (mr *MockOpenAbacPolicyManagerMockRecorder) GetCountByActionBeforeExpiredAt(actionPK, expiredAt interface{}) *gomock.Call {
+func (mr *MockOpenAbacPolicyManagerMockRecorder) GetCountByActionBeforeExpiredAt(actionPK, expiredAt, effectiveAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountByActionBeforeExpiredAt", reflect.TypeOf((*MockOpenAbacPolicyManager)(nil).GetCountByActionBeforeExpiredAt), actionPK, expiredAt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountByActionBeforeExpiredAt", reflect.TypeOf((*MockOpenAbacPolicyManager)(nil).GetCountByActionBeforeExpiredAt), actionPK, expiredAt, effectiveAt)
 }
 
 // ListByPKs mocks base method.
@@ -80,16 +80,16 @@ func (mr *MockOpenAbacPolicyManagerMockRecorder) ListByPKs(pks interface{}) *gom
 }
 
 // ListPagingByActionPKBeforeExpiredAt mocks base method.
-func (m *MockOpenAbacPolicyManager) ListPagingByActionPKBeforeExpiredAt(actionPK, expiredAt, offset, limit int64) ([]dao.OpenAbacPolicy, error) {
+func (m *MockOpenAbacPolicyManager) ListPagingByActionPKBeforeExpiredAt(actionPK, expiredAt, effectiveAt, offset, limit int64) ([]dao.OpenAbacPolicy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPagingByActionPKBeforeExpiredAt", actionPK, expiredAt, offset, limit)
+	ret := m.ctrl.Call(m, "ListPagingByActionPKBeforeExpiredAt", actionPK, expiredAt, effectiveAt, offset, limit)
 	ret0, _ := ret[0].([]dao.OpenAbacPolicy)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListPagingByActionPKBeforeExpiredAt indicates an expected call of ListPagingByActionPKBeforeExpiredAt.
-func (mr *MockOpenAbacPolicyManagerMockRecorder) ListPagingByActionPKBeforeExpiredAt(actionPK, expiredAt, offset, limit interface{}) *gomock.Call {
+func (mr *MockOpenAbacPolicyManagerMockRecorder) ListPagingByActionPKBeforeExpiredAt(actionPK, expiredAt, effectiveAt, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPagingByActionPKBeforeExpiredAt", reflect.TypeOf((*MockOpenAbacPolicyManager)(nil).ListPagingByActionPKBeforeExpiredAt), actionPK, expiredAt, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPagingByActionPKBeforeExpiredAt", reflect.TypeOf((*MockOpenAbacPolicyManager)(nil).ListPagingByActionPKBeforeExpiredAt), actionPK, expiredAt, effectiveAt, offset, limit)
 }
@@ -78,6 +78,20 @@ func (mr *MockPolicyManagerMockRecorder) BulkDeleteByTemplatePKsWithTx(tx, subje
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteByTemplatePKsWithTx", reflect.TypeOf((*MockPolicyManager)(nil).BulkDeleteByTemplatePKsWithTx), tx, subjectPK, templateID, pks)
 }
 
+// BulkUpdateEffectWithTx mocks base method.
+func (m *MockPolicyManager) BulkUpdateEffectWithTx(tx *sqlx.Tx, policies []dao.Policy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateEffectWithTx", tx, policies)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkUpdateEffectWithTx indicates an expected call of BulkUpdateEffectWithTx.
+func (mr *MockPolicyManagerMockRecorder) BulkUpdateEffectWithTx(tx, policies interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateEffectWithTx", reflect.TypeOf((*MockPolicyManager)(nil).BulkUpdateEffectWithTx), tx, policies)
+}
+
 // BulkUpdateExpiredAtWithTx mocks base method.
 func (m *MockPolicyManager) BulkUpdateExpiredAtWithTx(tx *sqlx.Tx, policies []dao.Policy) error {
 	m.ctrl.T.Helper()
@@ -210,3 +224,18 @@ func (mr *MockPolicyManagerMockRecorder) ListExpressionBySubjectsTemplate(subjec
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpressionBySubjectsTemplate", reflect.TypeOf((*MockPolicyManager)(nil).ListExpressionBySubjectsTemplate), subjectPKs, templateID)
 }
+
+// ListThinByActions mocks base method.
+func (m *MockPolicyManager) ListThinByActions(actionPKs []int64, expiredAt int64) ([]dao.ThinPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListThinByActions", actionPKs, expiredAt)
+	ret0, _ := ret[0].([]dao.ThinPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListThinByActions indicates an expected call of ListThinByActions.
+func (mr *MockPolicyManagerMockRecorder) ListThinByActions(actionPKs, expiredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThinByActions", reflect.TypeOf((*MockPolicyManager)(nil).ListThinByActions), actionPKs, expiredAt)
+}
@@ -135,3 +135,18 @@ func (mr *MockSubjectDepartmentManagerMockRecorder) ListPaging(limit, offset int
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaging", reflect.TypeOf((*MockSubjectDepartmentManager)(nil).ListPaging), limit, offset)
 }
+
+// ListSubjectPKsByDepartmentPK mocks base method.
+func (m *MockSubjectDepartmentManager) ListSubjectPKsByDepartmentPK(departmentPK int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubjectPKsByDepartmentPK", departmentPK)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubjectPKsByDepartmentPK indicates an expected call of ListSubjectPKsByDepartmentPK.
+func (mr *MockSubjectDepartmentManagerMockRecorder) ListSubjectPKsByDepartmentPK(departmentPK interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubjectPKsByDepartmentPK", reflect.TypeOf((*MockSubjectDepartmentManager)(nil).ListSubjectPKsByDepartmentPK), departmentPK)
+}
@@ -92,6 +92,20 @@ func (mr *MockSubjectGroupManagerMockRecorder) BulkDeleteBySubjectPKsWithTx(tx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteBySubjectPKsWithTx", reflect.TypeOf((*MockSubjectGroupManager)(nil).BulkDeleteBySubjectPKsWithTx), tx, subjectPKs)
 }
 
+// BulkUpdateEffectiveAtWithTx mocks base method.
+func (m *MockSubjectGroupManager) BulkUpdateEffectiveAtWithTx(tx *sqlx.Tx, relations []dao.SubjectRelation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateEffectiveAtWithTx", tx, relations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkUpdateEffectiveAtWithTx indicates an expected call of BulkUpdateEffectiveAtWithTx.
+func (mr *MockSubjectGroupManagerMockRecorder) BulkUpdateEffectiveAtWithTx(tx, relations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateEffectiveAtWithTx", reflect.TypeOf((*MockSubjectGroupManager)(nil).BulkUpdateEffectiveAtWithTx), tx, relations)
+}
+
 // BulkUpdateExpiredAtWithTx mocks base method.
 func (m *MockSubjectGroupManager) BulkUpdateExpiredAtWithTx(tx *sqlx.Tx, relations []dao.SubjectRelation) error {
 	m.ctrl.T.Helper()
@@ -107,18 +121,18 @@ func (mr *MockSubjectGroupManagerMockRecorder) BulkUpdateExpiredAtWithTx(tx, rel
 }
 
 // GetExpiredAtBySubjectGroup mocks base method.
-func (m *MockSubjectGroupManager) GetExpiredAtBySubjectGroup(subjectPK, groupPK int64) (int64, error) {
+func (m *MockSubjectGroupManager) GetExpiredAtBySubjectGroup(subjectPK, groupPK, effectiveAt int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExpiredAtBySubjectGroup", subjectPK, groupPK)
+	ret := m.ctrl.Call(m, "GetExpiredAtBySubjectGroup", subjectPK, groupPK, effectiveAt)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetExpiredAtBySubjectGroup indicates an expected call of GetExpiredAtBySubjectGroup.
-func (mr *MockSubjectGroupManagerMockRecorder) GetExpiredAtBySubjectGroup(subjectPK, groupPK interface{}) *gomock.Call {
+func (mr *MockSubjectGroupManagerMockRecorder) GetExpiredAtBySubjectGroup(subjectPK, groupPK, effectiveAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredAtBySubjectGroup", reflect.TypeOf((*MockSubjectGroupManager)(nil).GetExpiredAtBySubjectGroup), subjectPK, groupPK)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredAtBySubjectGroup", reflect.TypeOf((*MockSubjectGroupManager)(nil).GetExpiredAtBySubjectGroup), subjectPK, groupPK, effectiveAt)
 }
 
 // GetGroupMemberCount mocks base method.
@@ -241,6 +255,21 @@ func (mr *MockSubjectGroupManagerMockRecorder) ListGroupMember(groupPK interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGroupMember", reflect.TypeOf((*MockSubjectGroupManager)(nil).ListGroupMember), groupPK)
 }
 
+// ListMemberGroupRelationsByGroupPKs mocks base method.
+func (m *MockSubjectGroupManager) ListMemberGroupRelationsByGroupPKs(groupPKs []int64) ([]dao.ThinSubjectRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMemberGroupRelationsByGroupPKs", groupPKs)
+	ret0, _ := ret[0].([]dao.ThinSubjectRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMemberGroupRelationsByGroupPKs indicates an expected call of ListMemberGroupRelationsByGroupPKs.
+func (mr *MockSubjectGroupManagerMockRecorder) ListMemberGroupRelationsByGroupPKs(groupPKs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMemberGroupRelationsByGroupPKs", reflect.TypeOf((*MockSubjectGroupManager)(nil).ListMemberGroupRelationsByGroupPKs), groupPKs)
+}
+
 // ListPagingGroupMember mocks base method.
 func (m *MockSubjectGroupManager) ListPagingGroupMember(groupPK, limit, offset int64) ([]dao.SubjectRelation, error) {
 	m.ctrl.T.Helper()
@@ -346,6 +375,21 @@ func (mr *MockSubjectGroupManagerMockRecorder) ListPagingSubjectSystemGroups(sub
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPagingSubjectSystemGroups", reflect.TypeOf((*MockSubjectGroupManager)(nil).ListPagingSubjectSystemGroups), subjectPK, systemID, limit, offset)
 }
 
+// ListPendingRelationBeforeEffectiveAt mocks base method.
+func (m *MockSubjectGroupManager) ListPendingRelationBeforeEffectiveAt(effectiveAt, limit int64) ([]dao.SubjectRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingRelationBeforeEffectiveAt", effectiveAt, limit)
+	ret0, _ := ret[0].([]dao.SubjectRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingRelationBeforeEffectiveAt indicates an expected call of ListPendingRelationBeforeEffectiveAt.
+func (mr *MockSubjectGroupManagerMockRecorder) ListPendingRelationBeforeEffectiveAt(effectiveAt, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingRelationBeforeEffectiveAt", reflect.TypeOf((*MockSubjectGroupManager)(nil).ListPendingRelationBeforeEffectiveAt), effectiveAt, limit)
+}
+
 // ListRelationBySubjectPKGroupPKs mocks base method.
 func (m *MockSubjectGroupManager) ListRelationBySubjectPKGroupPKs(subjectPK int64, groupPKs []int64) ([]dao.SubjectRelation, error) {
 	m.ctrl.T.Helper()
@@ -376,17 +420,61 @@ func (mr *MockSubjectGroupManagerMockRecorder) ListRelationBySubjectPKGroupPKsBe
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRelationBySubjectPKGroupPKsBeforeExpiredAt", reflect.TypeOf((*MockSubjectGroupManager)(nil).ListRelationBySubjectPKGroupPKsBeforeExpiredAt), groupPKs, expiredAt)
 }
 
+// ListSubDepartmentRelationAfterExpiredAtBySubjectPKs mocks base method.
+func (m *MockSubjectGroupManager) ListSubDepartmentRelationAfterExpiredAtBySubjectPKs(subjectPKs []int64, expiredAt, effectiveAt int64) ([]dao.ThinSubjectRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubDepartmentRelationAfterExpiredAtBySubjectPKs", subjectPKs, expiredAt, effectiveAt)
+	ret0, _ := ret[0].([]dao.ThinSubjectRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubDepartmentRelationAfterExpiredAtBySubjectPKs indicates an expected call of ListSubDepartmentRelationAfterExpiredAtBySubjectPKs.
+func (mr *MockSubjectGroupManagerMockRecorder) ListSubDepartmentRelationAfterExpiredAtBySubjectPKs(subjectPKs, expiredAt, effectiveAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubDepartmentRelationAfterExpiredAtBySubjectPKs", reflect.TypeOf((*MockSubjectGroupManager)(nil).ListSubDepartmentRelationAfterExpiredAtBySubjectPKs), subjectPKs, expiredAt, effectiveAt)
+}
+
+// ListSubDepartmentRelationsByGroupPKs mocks base method.
+func (m *MockSubjectGroupManager) ListSubDepartmentRelationsByGroupPKs(groupPKs []int64) ([]dao.ThinSubjectRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubDepartmentRelationsByGroupPKs", groupPKs)
+	ret0, _ := ret[0].([]dao.ThinSubjectRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubDepartmentRelationsByGroupPKs indicates an expected call of ListSubDepartmentRelationsByGroupPKs.
+func (mr *MockSubjectGroupManagerMockRecorder) ListSubDepartmentRelationsByGroupPKs(groupPKs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubDepartmentRelationsByGroupPKs", reflect.TypeOf((*MockSubjectGroupManager)(nil).ListSubDepartmentRelationsByGroupPKs), groupPKs)
+}
+
 // ListThinRelationAfterExpiredAtBySubjectPKs mocks base method.
-func (m *MockSubjectGroupManager) ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs []int64, expiredAt int64) ([]dao.ThinSubjectRelation, error) {
+func (m *MockSubjectGroupManager) ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs []int64, expiredAt, effectiveAt int64) ([]dao.ThinSubjectRelation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListThinRelationAfterExpiredAtBySubjectPKs", subjectPKs, expiredAt)
+	ret := m.ctrl.Call(m, "ListThinRelationAfterExpiredAtBySubjectPKs", subjectPKs, expiredAt, effectiveAt)
 	ret0, _ := ret[0].([]dao.ThinSubjectRelation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListThinRelationAfterExpiredAtBySubjectPKs indicates an expected call of ListThinRelationAfterExpiredAtBySubjectPKs.
-func (mr *MockSubjectGroupManagerMockRecorder) ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs, expiredAt interface{}) *gomock.Call {
+func (mr *MockSubjectGroupManagerMockRecorder) ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs, expiredAt, effectiveAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThinRelationAfterExpiredAtBySubjectPKs", reflect.TypeOf((*MockSubjectGroupManager)(nil).ListThinRelationAfterExpiredAtBySubjectPKs), subjectPKs, expiredAt, effectiveAt)
+}
+
+// UpdateIncludeSubDepartment mocks base method.
+func (m *MockSubjectGroupManager) UpdateIncludeSubDepartment(groupPK int64, subjectPKs []int64, includeSubDepartment bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIncludeSubDepartment", groupPK, subjectPKs, includeSubDepartment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIncludeSubDepartment indicates an expected call of UpdateIncludeSubDepartment.
+func (mr *MockSubjectGroupManagerMockRecorder) UpdateIncludeSubDepartment(groupPK, subjectPKs, includeSubDepartment interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThinRelationAfterExpiredAtBySubjectPKs", reflect.TypeOf((*MockSubjectGroupManager)(nil).ListThinRelationAfterExpiredAtBySubjectPKs), subjectPKs, expiredAt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIncludeSubDepartment", reflect.TypeOf((*MockSubjectGroupManager)(nil).UpdateIncludeSubDepartment), groupPK, subjectPKs, includeSubDepartment)
 }
@@ -77,6 +77,20 @@ func (mr *MockSubjectTemplateGroupManagerMockRecorder) BulkDeleteWithTx(tx, rela
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteWithTx", reflect.TypeOf((*MockSubjectTemplateGroupManager)(nil).BulkDeleteWithTx), tx, relations)
 }
 
+// BulkUpdateEffectiveAtWithTx mocks base method.
+func (m *MockSubjectTemplateGroupManager) BulkUpdateEffectiveAtWithTx(tx *sqlx.Tx, relations []dao.SubjectTemplateGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateEffectiveAtWithTx", tx, relations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkUpdateEffectiveAtWithTx indicates an expected call of BulkUpdateEffectiveAtWithTx.
+func (mr *MockSubjectTemplateGroupManagerMockRecorder) BulkUpdateEffectiveAtWithTx(tx, relations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateEffectiveAtWithTx", reflect.TypeOf((*MockSubjectTemplateGroupManager)(nil).BulkUpdateEffectiveAtWithTx), tx, relations)
+}
+
 // BulkUpdateExpiredAtByRelationWithTx mocks base method.
 func (m *MockSubjectTemplateGroupManager) BulkUpdateExpiredAtByRelationWithTx(tx *sqlx.Tx, relations []dao.SubjectRelation) error {
 	m.ctrl.T.Helper()
@@ -106,18 +120,18 @@ func (mr *MockSubjectTemplateGroupManagerMockRecorder) BulkUpdateExpiredAtWithTx
 }
 
 // GetMaxExpiredAtBySubjectGroup mocks base method.
-func (m *MockSubjectTemplateGroupManager) GetMaxExpiredAtBySubjectGroup(subjectPK, groupPK, excludeTemplateID int64) (int64, error) {
+func (m *MockSubjectTemplateGroupManager) GetMaxExpiredAtBySubjectGroup(subjectPK, groupPK, excludeTemplateID, effectiveAt int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMaxExpiredAtBySubjectGroup", subjectPK, groupPK, excludeTemplateID)
+	ret := m.ctrl.Call(m, "GetMaxExpiredAtBySubjectGroup", subjectPK, groupPK, excludeTemplateID, effectiveAt)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMaxExpiredAtBySubjectGroup indicates an expected call of GetMaxExpiredAtBySubjectGroup.
-func (mr *MockSubjectTemplateGroupManagerMockRecorder) GetMaxExpiredAtBySubjectGroup(subjectPK, groupPK, excludeTemplateID interface{}) *gomock.Call {
+func (mr *MockSubjectTemplateGroupManagerMockRecorder) GetMaxExpiredAtBySubjectGroup(subjectPK, groupPK, excludeTemplateID, effectiveAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxExpiredAtBySubjectGroup", reflect.TypeOf((*MockSubjectTemplateGroupManager)(nil).GetMaxExpiredAtBySubjectGroup), subjectPK, groupPK, excludeTemplateID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxExpiredAtBySubjectGroup", reflect.TypeOf((*MockSubjectTemplateGroupManager)(nil).GetMaxExpiredAtBySubjectGroup), subjectPK, groupPK, excludeTemplateID, effectiveAt)
 }
 
 // GetTemplateGroupMemberCount mocks base method.
@@ -165,6 +179,21 @@ func (mr *MockSubjectTemplateGroupManagerMockRecorder) ListPagingTemplateGroupMe
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPagingTemplateGroupMember", reflect.TypeOf((*MockSubjectTemplateGroupManager)(nil).ListPagingTemplateGroupMember), groupPK, templateID, limit, offset)
 }
 
+// ListPendingRelationBeforeEffectiveAt mocks base method.
+func (m *MockSubjectTemplateGroupManager) ListPendingRelationBeforeEffectiveAt(effectiveAt, limit int64) ([]dao.SubjectTemplateGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingRelationBeforeEffectiveAt", effectiveAt, limit)
+	ret0, _ := ret[0].([]dao.SubjectTemplateGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingRelationBeforeEffectiveAt indicates an expected call of ListPendingRelationBeforeEffectiveAt.
+func (mr *MockSubjectTemplateGroupManagerMockRecorder) ListPendingRelationBeforeEffectiveAt(effectiveAt, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingRelationBeforeEffectiveAt", reflect.TypeOf((*MockSubjectTemplateGroupManager)(nil).ListPendingRelationBeforeEffectiveAt), effectiveAt, limit)
+}
+
 // ListRelationBySubjectPKGroupPKs mocks base method.
 func (m *MockSubjectTemplateGroupManager) ListRelationBySubjectPKGroupPKs(subjectPK int64, groupPKs []int64) ([]dao.SubjectTemplateGroup, error) {
 	m.ctrl.T.Helper()
@@ -181,16 +210,16 @@ func (mr *MockSubjectTemplateGroupManagerMockRecorder) ListRelationBySubjectPKGr
 }
 
 // ListThinRelationWithMaxExpiredAtByGroupPK mocks base method.
-func (m *MockSubjectTemplateGroupManager) ListThinRelationWithMaxExpiredAtByGroupPK(groupPK int64) ([]dao.ThinSubjectRelation, error) {
+func (m *MockSubjectTemplateGroupManager) ListThinRelationWithMaxExpiredAtByGroupPK(groupPK, effectiveAt int64) ([]dao.ThinSubjectRelation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListThinRelationWithMaxExpiredAtByGroupPK", groupPK)
+	ret := m.ctrl.Call(m, "ListThinRelationWithMaxExpiredAtByGroupPK", groupPK, effectiveAt)
 	ret0, _ := ret[0].([]dao.ThinSubjectRelation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListThinRelationWithMaxExpiredAtByGroupPK indicates an expected call of ListThinRelationWithMaxExpiredAtByGroupPK.
-func (mr *MockSubjectTemplateGroupManagerMockRecorder) ListThinRelationWithMaxExpiredAtByGroupPK(groupPK interface{}) *gomock.Call {
+func (mr *MockSubjectTemplateGroupManagerMockRecorder) ListThinRelationWithMaxExpiredAtByGroupPK(groupPK, effectiveAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThinRelationWithMaxExpiredAtByGroupPK", reflect.TypeOf((*MockSubjectTemplateGroupManager)(nil).ListThinRelationWithMaxExpiredAtByGroupPK), groupPK)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThinRelationWithMaxExpiredAtByGroupPK", reflect.TypeOf((*MockSubjectTemplateGroupManager)(nil).ListThinRelationWithMaxExpiredAtByGroupPK), groupPK, effectiveAt)
 }
@@ -80,6 +80,21 @@ func (mr *MockTemporaryPolicyManagerMockRecorder) BulkDeleteByPKs(subjectPK, pks
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteByPKs", reflect.TypeOf((*MockTemporaryPolicyManager)(nil).BulkDeleteByPKs), subjectPK, pks)
 }
 
+// ListByActionPKsBeforeExpiredAt mocks base method.
+func (m *MockTemporaryPolicyManager) ListByActionPKsBeforeExpiredAt(actionPKs []int64, expiredAt int64) ([]dao.TemporaryPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByActionPKsBeforeExpiredAt", actionPKs, expiredAt)
+	ret0, _ := ret[0].([]dao.TemporaryPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByActionPKsBeforeExpiredAt indicates an expected call of ListByActionPKsBeforeExpiredAt.
+func (mr *MockTemporaryPolicyManagerMockRecorder) ListByActionPKsBeforeExpiredAt(actionPKs, expiredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByActionPKsBeforeExpiredAt", reflect.TypeOf((*MockTemporaryPolicyManager)(nil).ListByActionPKsBeforeExpiredAt), actionPKs, expiredAt)
+}
+
 // ListByPKs mocks base method.
 func (m *MockTemporaryPolicyManager) ListByPKs(pks []int64) ([]dao.TemporaryPolicy, error) {
 	m.ctrl.T.Helper()
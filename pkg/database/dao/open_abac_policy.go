@@ -26,10 +26,11 @@ type OpenAbacPolicy struct {
 }
 type OpenAbacPolicyManager interface {
 	Get(pk int64) (OpenAbacPolicy, error)
-	GetCountByActionBeforeExpiredAt(actionPK int64, expiredAt int64) (int64, error)
+	GetCountByActionBeforeExpiredAt(actionPK int64, expiredAt int64, effectiveAt int64) (int64, error)
 	ListPagingByActionPKBeforeExpiredAt(
 		actionPK int64,
 		expiredAt int64,
+		effectiveAt int64,
 		offset int64,
 		limit int64,
 	) ([]OpenAbacPolicy, error)
@@ -54,7 +55,9 @@ func (m *openAbacPolicyManager) Get(pk int64) (policy OpenAbacPolicy, err error)
 			action_pk,
 			expression_pk,
 			expired_at,
-			template_id
+			template_id,
+			effect,
+			effective_at
 			FROM policy
 			WHERE pk = ?
 			LIMIT 1`
@@ -62,24 +65,27 @@ func (m *openAbacPolicyManager) Get(pk int64) (policy OpenAbacPolicy, err error)
 	return
 }
 
-// GetCountByActionBeforeExpiredAt ...
+// GetCountByActionBeforeExpiredAt 只统计effectiveAt时已生效的策略
 func (m *openAbacPolicyManager) GetCountByActionBeforeExpiredAt(
 	actionPK int64,
 	expiredAt int64,
+	effectiveAt int64,
 ) (count int64, err error) {
 	query := `SELECT
 	count(*)
 	FROM policy
 	WHERE action_pk = ?
-	AND expired_at > ?`
-	err = database.SqlxGet(m.DB, &count, query, actionPK, expiredAt)
+	AND expired_at > ?
+	AND effective_at <= ?`
+	err = database.SqlxGet(m.DB, &count, query, actionPK, expiredAt, effectiveAt)
 	return
 }
 
-// ListPagingByActionPKBeforeExpiredAt ...
+// ListPagingByActionPKBeforeExpiredAt 只查询effectiveAt时已生效的策略
 func (m *openAbacPolicyManager) ListPagingByActionPKBeforeExpiredAt(
 	actionPK int64,
 	expiredAt int64,
+	effectiveAt int64,
 	offset int64,
 	limit int64,
 ) (policies []OpenAbacPolicy, err error) {
@@ -89,10 +95,13 @@ func (m *openAbacPolicyManager) ListPagingByActionPKBeforeExpiredAt(
 	action_pk,
 	expression_pk,
 	expired_at,
-	template_id
+	template_id,
+	effect,
+	effective_at
 	FROM policy
 	WHERE action_pk = ?
 	AND expired_at > ?
+	AND effective_at <= ?
 	ORDER BY pk asc
 	LIMIT ? OFFSET ?`
 
@@ -104,7 +113,9 @@ func (m *openAbacPolicyManager) ListPagingByActionPKBeforeExpiredAt(
 		t.action_pk,
 		t.expression_pk,
 		t.expired_at,
-		t.template_id
+		t.template_id,
+		t.effect,
+		t.effective_at
 		FROM policy t
 		INNER JOIN
 		(
@@ -112,12 +123,13 @@ func (m *openAbacPolicyManager) ListPagingByActionPKBeforeExpiredAt(
 			FROM policy
 			WHERE action_pk = ?
 			AND expired_at > ?
+			AND effective_at <= ?
 			ORDER BY pk asc
 			LIMIT ? OFFSET ?
 		) p ON t.pk = p.pk`
 	}
 
-	err = database.SqlxSelect(m.DB, &policies, query, actionPK, expiredAt, limit, offset)
+	err = database.SqlxSelect(m.DB, &policies, query, actionPK, expiredAt, effectiveAt, limit, offset)
 	if errors.Is(err, sql.ErrNoRows) {
 		return policies, nil
 	}
@@ -132,7 +144,9 @@ func (m *openAbacPolicyManager) ListByPKs(pks []int64) (policies []OpenAbacPolic
 		action_pk,
 		expression_pk,
 		expired_at,
-		template_id
+		template_id,
+		effect,
+		effective_at
 		FROM policy
 		WHERE pk in (?)`
 	err = database.SqlxSelect(m.DB, &policies, query, pks)
@@ -27,6 +27,8 @@ type AuthPolicy struct {
 	SubjectPK    int64 `db:"subject_pk"`
 	ExpressionPK int64 `db:"expression_pk"`
 	ExpiredAt    int64 `db:"expired_at"`
+	EffectiveAt  int64 `db:"effective_at"`
+	Effect       int64 `db:"effect"`
 }
 
 // Policy ...
@@ -38,8 +40,20 @@ type Policy struct {
 	ExpressionPK int64 `db:"expression_pk"`
 
 	// 策略有效期，unix time，单位秒(s)
-	ExpiredAt  int64 `db:"expired_at"`
-	TemplateID int64 `db:"template_id"`
+	ExpiredAt int64 `db:"expired_at"`
+	// 策略生效时间，unix time，单位秒(s), 0表示立即生效
+	EffectiveAt int64 `db:"effective_at"`
+	TemplateID  int64 `db:"template_id"`
+	// 策略效果, 0: allow, 1: deny
+	Effect int64 `db:"effect"`
+}
+
+// ThinPolicy ...
+type ThinPolicy struct {
+	PK        int64 `db:"pk"`
+	SubjectPK int64 `db:"subject_pk"`
+	ActionPK  int64 `db:"action_pk"`
+	Effect    int64 `db:"effect"`
 }
 
 // PolicyManager ...
@@ -59,10 +73,15 @@ type PolicyManager interface {
 	BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error
 	BulkUpdateExpressionPKWithTx(tx *sqlx.Tx, policies []Policy) error
 	BulkUpdateExpiredAtWithTx(tx *sqlx.Tx, policies []Policy) error
+	BulkUpdateEffectWithTx(tx *sqlx.Tx, policies []Policy) error
 	DeleteByActionPKWithTx(tx *sqlx.Tx, actionPK, limit int64) (int64, error)
 	// for model update
 
 	HasAnyByActionPK(actionPK int64) (bool, error)
+
+	// for sod constraint
+
+	ListThinByActions(actionPKs []int64, expiredAt int64) ([]ThinPolicy, error)
 }
 
 type policyManager struct {
@@ -140,6 +159,18 @@ func (m *policyManager) ListBySubjectActionTemplate(
 	return
 }
 
+// ListThinByActions 查询指定操作上所有未过期的策略
+func (m *policyManager) ListThinByActions(actionPKs []int64, expiredAt int64) (policies []ThinPolicy, err error) {
+	if len(actionPKs) == 0 {
+		return
+	}
+	err = m.selectThinByActions(&policies, actionPKs, expiredAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return policies, nil
+	}
+	return
+}
+
 // BulkCreateWithTx ...
 func (m *policyManager) BulkCreateWithTx(tx *sqlx.Tx, policies []Policy) error {
 	if len(policies) == 0 {
@@ -192,6 +223,14 @@ func (m *policyManager) BulkUpdateExpiredAtWithTx(tx *sqlx.Tx, policies []Policy
 	return m.updateExpiredAtWithTx(tx, policies)
 }
 
+// BulkUpdateEffectWithTx ...
+func (m *policyManager) BulkUpdateEffectWithTx(tx *sqlx.Tx, policies []Policy) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	return m.bulkUpdateEffectWithTx(tx, policies)
+}
+
 // DeleteByActionPKWithTx ...
 func (m *policyManager) DeleteByActionPKWithTx(tx *sqlx.Tx, actionPK, limit int64) (int64, error) {
 	return m.deleteByActionPKWithTx(tx, actionPK, limit)
@@ -206,7 +245,9 @@ func (m *policyManager) selectBySubjectPKAndPKs(
 		action_pk,
 		expression_pk,
 		expired_at,
-		template_id
+		effective_at,
+		template_id,
+		effect
 		FROM policy
 		WHERE subject_pk = ?
 		AND pk IN (?)`
@@ -220,7 +261,9 @@ func (m *policyManager) selectAuthBySubjectAction(
 		pk,
 		subject_pk,
 		expression_pk,
-		expired_at
+		expired_at,
+		effective_at,
+		effect
 		FROM policy
 		WHERE subject_pk in (?)
 		AND action_pk = ?
@@ -248,7 +291,9 @@ func (m *policyManager) selectBySubjectActionTemplate(
 		action_pk,
 		expression_pk,
 		expired_at,
-		template_id
+		effective_at,
+		template_id,
+		effect
 		FROM policy
 		WHERE subject_pk = ?
 		AND action_pk in (?)
@@ -256,6 +301,18 @@ func (m *policyManager) selectBySubjectActionTemplate(
 	return database.SqlxSelect(m.DB, policies, query, subjectPK, actionPKs, templateID)
 }
 
+func (m *policyManager) selectThinByActions(policies *[]ThinPolicy, actionPKs []int64, expiredAt int64) error {
+	query := `SELECT
+		pk,
+		subject_pk,
+		action_pk,
+		effect
+		FROM policy
+		WHERE action_pk in (?)
+		AND expired_at > ?`
+	return database.SqlxSelect(m.DB, policies, query, actionPKs, expiredAt)
+}
+
 func (m *policyManager) selectBySubjectTemplateBeforeExpiredAt(
 	policies *[]Policy, subjectPK int64, templateID int64, expiredAt int64,
 ) error {
@@ -265,7 +322,9 @@ func (m *policyManager) selectBySubjectTemplateBeforeExpiredAt(
 		action_pk,
 		expression_pk,
 		expired_at,
-		template_id
+		effective_at,
+		template_id,
+		effect
 		FROM policy
 		WHERE subject_pk = ?
 		AND template_id = ?
@@ -280,13 +339,17 @@ func (m *policyManager) bulkInsertWithTx(tx *sqlx.Tx, policies []Policy) error {
 		action_pk,
 		expression_pk,
 		expired_at,
-		template_id
+		effective_at,
+		template_id,
+		effect
 	) VALUES (
 		:subject_pk,
 		:action_pk,
 		:expression_pk,
 		:expired_at,
-		:template_id)`
+		:effective_at,
+		:template_id,
+		:effect)`
 	return database.SqlxBulkInsertWithTx(tx, sql, policies)
 }
 
@@ -322,6 +385,12 @@ func (m *policyManager) updateExpiredAtWithTx(tx *sqlx.Tx, policies []Policy) er
 	return database.SqlxBulkUpdateWithTx(tx, sql, policies)
 }
 
+func (m *policyManager) bulkUpdateEffectWithTx(tx *sqlx.Tx, policies []Policy) error {
+	sql := `UPDATE policy SET effect = :effect WHERE pk = :pk`
+
+	return database.SqlxBulkUpdateWithTx(tx, sql, policies)
+}
+
 func (m *policyManager) deleteByActionPKWithTx(tx *sqlx.Tx, actionPK, limit int64) (int64, error) {
 	sql := `DELETE FROM policy WHERE action_pk = ? LIMIT ?`
 	return database.SqlxDeleteReturnRowsWithTx(tx, sql, actionPK, limit)
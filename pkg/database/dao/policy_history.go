@@ -0,0 +1,185 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"iam/pkg/database"
+)
+
+// PolicyHistory 策略变更记录, pk即为变更的版本号
+type PolicyHistory struct {
+	PK         int64  `db:"pk"`
+	SubjectPK  int64  `db:"subject_pk"`
+	System     string `db:"system_id"`
+	ActionPK   int64  `db:"action_pk"`
+	TemplateID int64  `db:"template_id"`
+	PolicyPK   int64  `db:"policy_pk"`
+	Operation  string `db:"operation"`
+	// 变更前后的策略快照(json), 空字符串表示策略不存在
+	BeforePolicy string    `db:"before_policy"`
+	AfterPolicy  string    `db:"after_policy"`
+	ClientID     string    `db:"client_id"`
+	Actor        string    `db:"actor"`
+	RequestID    string    `db:"request_id"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// PolicyHistoryManager ...
+type PolicyHistoryManager interface {
+	Get(pk int64) (PolicyHistory, error)
+	GetCount(subjectPK int64, system string) (int64, error)
+	ListPaging(subjectPK int64, system string, limit, offset int64) ([]PolicyHistory, error)
+	ListAfterPK(subjectPK int64, system string, pk int64) ([]PolicyHistory, error)
+
+	BulkCreateWithTx(tx *sqlx.Tx, histories []PolicyHistory) error
+}
+
+type policyHistoryManager struct {
+	DB *sqlx.DB
+}
+
+// NewPolicyHistoryManager ...
+func NewPolicyHistoryManager() PolicyHistoryManager {
+	return &policyHistoryManager{
+		DB: database.GetDefaultDBClient().DB,
+	}
+}
+
+// Get ...
+func (m *policyHistoryManager) Get(pk int64) (history PolicyHistory, err error) {
+	query := `SELECT
+		 pk,
+		 subject_pk,
+		 system_id,
+		 action_pk,
+		 template_id,
+		 policy_pk,
+		 operation,
+		 before_policy,
+		 after_policy,
+		 client_id,
+		 actor,
+		 request_id,
+		 created_at
+		 FROM policy_history
+		 WHERE pk = ?
+		 LIMIT 1`
+	err = database.SqlxGet(m.DB, &history, query, pk)
+	return
+}
+
+// GetCount ...
+func (m *policyHistoryManager) GetCount(subjectPK int64, system string) (count int64, err error) {
+	query := `SELECT COUNT(*) FROM policy_history WHERE subject_pk = ? AND system_id = ?`
+	err = database.SqlxGet(m.DB, &count, query, subjectPK, system)
+	return
+}
+
+// ListPaging 按版本倒序查询subject在系统下的策略变更记录
+func (m *policyHistoryManager) ListPaging(
+	subjectPK int64, system string, limit, offset int64,
+) (histories []PolicyHistory, err error) {
+	query := `SELECT
+		 pk,
+		 subject_pk,
+		 system_id,
+		 action_pk,
+		 template_id,
+		 policy_pk,
+		 operation,
+		 before_policy,
+		 after_policy,
+		 client_id,
+		 actor,
+		 request_id,
+		 created_at
+		 FROM policy_history
+		 WHERE subject_pk = ?
+		 AND system_id = ?
+		 ORDER BY pk DESC
+		 LIMIT ? OFFSET ?`
+	err = database.SqlxSelect(m.DB, &histories, query, subjectPK, system, limit, offset)
+	if errors.Is(err, sql.ErrNoRows) {
+		return histories, nil
+	}
+	return
+}
+
+// ListAfterPK 按版本顺序查询subject在系统下指定版本之后的所有变更记录
+func (m *policyHistoryManager) ListAfterPK(
+	subjectPK int64, system string, pk int64,
+) (histories []PolicyHistory, err error) {
+	query := `SELECT
+		 pk,
+		 subject_pk,
+		 system_id,
+		 action_pk,
+		 template_id,
+		 policy_pk,
+		 operation,
+		 before_policy,
+		 after_policy,
+		 client_id,
+		 actor,
+		 request_id,
+		 created_at
+		 FROM policy_history
+		 WHERE subject_pk = ?
+		 AND system_id = ?
+		 AND pk > ?
+		 ORDER BY pk`
+	err = database.SqlxSelect(m.DB, &histories, query, subjectPK, system, pk)
+	if errors.Is(err, sql.ErrNoRows) {
+		return histories, nil
+	}
+	return
+}
+
+// BulkCreateWithTx ...
+func (m *policyHistoryManager) BulkCreateWithTx(tx *sqlx.Tx, histories []PolicyHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+
+	sql := `INSERT INTO policy_history (
+		subject_pk,
+		system_id,
+		action_pk,
+		template_id,
+		policy_pk,
+		operation,
+		before_policy,
+		after_policy,
+		client_id,
+		actor,
+		request_id
+	) VALUES (
+		:subject_pk,
+		:system_id,
+		:action_pk,
+		:template_id,
+		:policy_pk,
+		:operation,
+		:before_policy,
+		:after_policy,
+		:client_id,
+		:actor,
+		:request_id)`
+	return database.SqlxBulkInsertWithTx(tx, sql, histories)
+}
@@ -0,0 +1,102 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database"
+)
+
+func Test_policyHistoryManager_GetCount(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT COUNT(.*) FROM policy_history WHERE subject_pk = (.*) AND system_id = `
+		mockRows := sqlmock.NewRows([]string{"count(*)"}).AddRow(int64(3))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(1), "bk_cmdb").WillReturnRows(mockRows)
+
+		manager := &policyHistoryManager{DB: db}
+		cnt, err := manager.GetCount(int64(1), "bk_cmdb")
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, int64(3), cnt)
+	})
+}
+
+func Test_policyHistoryManager_ListAfterPK(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		now := time.Now()
+		mockQuery := `^SELECT pk, subject_pk, system_id, action_pk, template_id, policy_pk, operation, ` +
+			`before_policy, after_policy, client_id, actor, request_id, created_at FROM policy_history ` +
+			`WHERE subject_pk = (.*) AND system_id = (.*) AND pk > (.*) ORDER BY pk$`
+		mockRows := sqlmock.NewRows([]string{
+			"pk", "subject_pk", "system_id", "action_pk", "template_id", "policy_pk", "operation",
+			"before_policy", "after_policy", "client_id", "actor", "request_id", "created_at",
+		}).AddRow(
+			int64(2), int64(1), "bk_cmdb", int64(3), int64(0), int64(4), "delete",
+			`{"expression":"[]"}`, "", "bk_iam", "admin", "abc", now,
+		)
+		mock.ExpectQuery(mockQuery).WithArgs(int64(1), "bk_cmdb", int64(1)).WillReturnRows(mockRows)
+
+		manager := &policyHistoryManager{DB: db}
+		histories, err := manager.ListAfterPK(int64(1), "bk_cmdb", int64(1))
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []PolicyHistory{{
+			PK:           2,
+			SubjectPK:    1,
+			System:       "bk_cmdb",
+			ActionPK:     3,
+			PolicyPK:     4,
+			Operation:    "delete",
+			BeforePolicy: `{"expression":"[]"}`,
+			ClientID:     "bk_iam",
+			Actor:        "admin",
+			RequestID:    "abc",
+			CreatedAt:    now,
+		}}, histories)
+	})
+}
+
+func Test_policyHistoryManager_BulkCreateWithTx(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO policy_history`).WithArgs(
+			int64(1), "bk_cmdb", int64(3), int64(0), int64(4), "update",
+			`{"expression":"[]"}`, `{"expression":"[{}]"}`, "bk_iam", "admin", "abc",
+		).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+
+		manager := &policyHistoryManager{DB: db}
+		err = manager.BulkCreateWithTx(tx, []PolicyHistory{{
+			SubjectPK:    1,
+			System:       "bk_cmdb",
+			ActionPK:     3,
+			PolicyPK:     4,
+			Operation:    "update",
+			BeforePolicy: `{"expression":"[]"}`,
+			AfterPolicy:  `{"expression":"[{}]"}`,
+			ClientID:     "bk_iam",
+			Actor:        "admin",
+			RequestID:    "abc",
+		}})
+
+		tx.Commit()
+		assert.NoError(t, err)
+	})
+}
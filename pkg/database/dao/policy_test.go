@@ -36,7 +36,7 @@ func Test_policyManager_ListAuthBySubjectAction(t *testing.T) {
 				ExpiredAt:    2,
 			},
 		}
-		mockQuery := `^SELECT pk, subject_pk, expression_pk, expired_at FROM policy WHERE subject_pk`
+		mockQuery := `^SELECT pk, subject_pk, expression_pk, expired_at, effective_at, effect FROM policy WHERE subject_pk`
 		mockRows := database.NewMockRows(mock, mockData...)
 		mock.ExpectQuery(mockQuery).WithArgs(int64(1), int64(2), int64(1), 0).WillReturnRows(mockRows)
 
@@ -54,7 +54,7 @@ func Test_policyManager_BulkCreateWithTx(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectExec(`INSERT INTO policy`).WithArgs(
-			int64(1), int64(1), int64(1), int64(1), int64(1),
+			int64(1), int64(1), int64(1), int64(1), int64(0), int64(1), int64(0),
 		).WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
@@ -115,7 +115,7 @@ func Test_policyManager_ListByPKs(t *testing.T) {
 				ExpiredAt:    2,
 			},
 		}
-		mockQuery := `^SELECT pk, subject_pk, action_pk, expression_pk, expired_at, template_id FROM policy WHERE subject_pk`
+		mockQuery := `^SELECT pk, subject_pk, action_pk, expression_pk, expired_at, effective_at, template_id, effect FROM policy WHERE subject_pk`
 		mockRows := database.NewMockRows(mock, mockData...)
 		mock.ExpectQuery(mockQuery).WithArgs(int64(1), int64(1), int64(2)).WillReturnRows(mockRows)
 
@@ -180,7 +180,7 @@ func Test_policyManager_ListBySubjectTemplateBeforeExpiredAt(t *testing.T) {
 				ExpiredAt:    2,
 			},
 		}
-		mockQuery := `^SELECT pk, subject_pk, action_pk, expression_pk, expired_at, template_id FROM policy WHERE subject_pk`
+		mockQuery := `^SELECT pk, subject_pk, action_pk, expression_pk, expired_at, effective_at, template_id, effect FROM policy WHERE subject_pk`
 		mockRows := database.NewMockRows(mock, mockData...)
 		mock.ExpectQuery(mockQuery).WithArgs(int64(1), int64(0), int64(1000)).WillReturnRows(mockRows)
 
@@ -194,6 +194,25 @@ func Test_policyManager_ListBySubjectTemplateBeforeExpiredAt(t *testing.T) {
 	})
 }
 
+func Test_policyManager_ListThinByActions(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, subject_pk, action_pk, effect FROM policy WHERE action_pk in (.*) AND expired_at >`
+		mockRows := sqlmock.NewRows([]string{"pk", "subject_pk", "action_pk", "effect"}).
+			AddRow(int64(1), int64(2), int64(3), int64(0)).
+			AddRow(int64(2), int64(2), int64(4), int64(1))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(3), int64(4), int64(1000)).WillReturnRows(mockRows)
+
+		manager := &policyManager{DB: db}
+		policies, err := manager.ListThinByActions([]int64{3, 4}, int64(1000))
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []ThinPolicy{
+			{PK: 1, SubjectPK: 2, ActionPK: 3},
+			{PK: 2, SubjectPK: 2, ActionPK: 4, Effect: 1},
+		}, policies)
+	})
+}
+
 func Test_policyManager_UpdateExpiredAt(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
 		mock.ExpectBegin()
@@ -220,6 +239,32 @@ func Test_policyManager_UpdateExpiredAt(t *testing.T) {
 	})
 }
 
+func Test_policyManager_BulkUpdateEffectWithTx(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectPrepare(`UPDATE policy SET effect = (.*) WHERE pk = (.*)`)
+		mock.ExpectExec(`UPDATE policy SET effect =`).WithArgs(
+			int64(1), int64(1),
+		).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		policies := []Policy{{
+			PK:     1,
+			Effect: 1,
+		}}
+
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+
+		manager := &policyManager{DB: db}
+		err = manager.BulkUpdateEffectWithTx(tx, policies)
+
+		tx.Commit()
+
+		assert.NoError(t, err)
+	})
+}
+
 func Test_policyManager_BulkUpdateExpressionPKWithTx(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
 		mock.ExpectBegin()
@@ -0,0 +1,114 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"iam/pkg/database"
+)
+
+// SoDConstraint 职责分离(Separation of Duties)约束, 约束内的用户组/操作互斥
+type SoDConstraint struct {
+	PK   int64  `db:"pk"`
+	Type string `db:"type"` // group/action
+	// action类型的约束所属的系统, group类型为空
+	SystemID    string `db:"system_id"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
+	// json存储了互斥的用户组PK/操作PK列表
+	ItemPKs string `db:"item_pks"`
+}
+
+// SoDConstraintManager ...
+type SoDConstraintManager interface {
+	List() ([]SoDConstraint, error)
+	ListByType(_type string) ([]SoDConstraint, error)
+
+	Create(constraint SoDConstraint) error
+	Delete(pk int64) (int64, error)
+}
+
+type sodConstraintManager struct {
+	DB *sqlx.DB
+}
+
+// NewSoDConstraintManager ...
+func NewSoDConstraintManager() SoDConstraintManager {
+	return &sodConstraintManager{
+		DB: database.GetDefaultDBClient().DB,
+	}
+}
+
+// List ...
+func (m *sodConstraintManager) List() (constraints []SoDConstraint, err error) {
+	query := `SELECT
+		 pk,
+		 type,
+		 system_id,
+		 name,
+		 description,
+		 item_pks
+		 FROM sod_constraint
+		 ORDER BY pk`
+	err = database.SqlxSelect(m.DB, &constraints, query)
+	if errors.Is(err, sql.ErrNoRows) {
+		return constraints, nil
+	}
+	return
+}
+
+// ListByType ...
+func (m *sodConstraintManager) ListByType(_type string) (constraints []SoDConstraint, err error) {
+	query := `SELECT
+		 pk,
+		 type,
+		 system_id,
+		 name,
+		 description,
+		 item_pks
+		 FROM sod_constraint
+		 WHERE type = ?
+		 ORDER BY pk`
+	err = database.SqlxSelect(m.DB, &constraints, query, _type)
+	if errors.Is(err, sql.ErrNoRows) {
+		return constraints, nil
+	}
+	return
+}
+
+// Create ...
+func (m *sodConstraintManager) Create(constraint SoDConstraint) error {
+	sql := `INSERT INTO sod_constraint (
+		type,
+		system_id,
+		name,
+		description,
+		item_pks
+	) VALUES (
+		:type,
+		:system_id,
+		:name,
+		:description,
+		:item_pks)`
+	return database.SqlxBulkInsert(m.DB, sql, []SoDConstraint{constraint})
+}
+
+// Delete ...
+func (m *sodConstraintManager) Delete(pk int64) (int64, error) {
+	sql := `DELETE FROM sod_constraint WHERE pk = ?`
+	return database.SqlxDelete(m.DB, sql, pk)
+}
@@ -0,0 +1,82 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database"
+)
+
+func Test_sodConstraintManager_List(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, type, system_id, name, description, item_pks FROM sod_constraint ORDER BY pk`
+		mockRows := sqlmock.NewRows([]string{"pk", "type", "system_id", "name", "description", "item_pks"}).
+			AddRow(int64(1), "action", "bk_cmdb", "payment", "", "[1,2]")
+		mock.ExpectQuery(mockQuery).WillReturnRows(mockRows)
+
+		manager := &sodConstraintManager{DB: db}
+		constraints, err := manager.List()
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []SoDConstraint{{
+			PK: 1, Type: "action", SystemID: "bk_cmdb", Name: "payment", ItemPKs: "[1,2]",
+		}}, constraints)
+	})
+}
+
+func Test_sodConstraintManager_ListByType(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, type, system_id, name, description, item_pks FROM sod_constraint WHERE type = `
+		mockRows := sqlmock.NewRows([]string{"pk", "type", "system_id", "name", "description", "item_pks"}).
+			AddRow(int64(1), "group", "", "admin", "desc", "[3,4]")
+		mock.ExpectQuery(mockQuery).WithArgs("group").WillReturnRows(mockRows)
+
+		manager := &sodConstraintManager{DB: db}
+		constraints, err := manager.ListByType("group")
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []SoDConstraint{{
+			PK: 1, Type: "group", Name: "admin", Description: "desc", ItemPKs: "[3,4]",
+		}}, constraints)
+	})
+}
+
+func Test_sodConstraintManager_Create(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectExec(`^INSERT INTO sod_constraint`).WithArgs(
+			"action", "bk_cmdb", "payment", "", "[1,2]",
+		).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		manager := &sodConstraintManager{DB: db}
+		err := manager.Create(SoDConstraint{Type: "action", SystemID: "bk_cmdb", Name: "payment", ItemPKs: "[1,2]"})
+
+		assert.NoError(t, err, "query from db fail.")
+	})
+}
+
+func Test_sodConstraintManager_Delete(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectExec(`^DELETE FROM sod_constraint WHERE pk =`).WithArgs(
+			int64(1),
+		).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		manager := &sodConstraintManager{DB: db}
+		rows, err := manager.Delete(1)
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, int64(1), rows)
+	})
+}
@@ -0,0 +1,92 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"iam/pkg/database"
+)
+
+// SubjectAttribute subject的属性, value为json序列化后的值
+type SubjectAttribute struct {
+	PK        int64  `db:"pk"`
+	SubjectPK int64  `db:"subject_pk"`
+	Name      string `db:"name"`
+	Value     string `db:"value"`
+}
+
+// SubjectAttributeManager ...
+type SubjectAttributeManager interface {
+	ListBySubjectPK(subjectPK int64) ([]SubjectAttribute, error)
+
+	BulkCreateWithTx(tx *sqlx.Tx, subjectAttributes []SubjectAttribute) error
+	BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error
+}
+
+type subjectAttributeManager struct {
+	DB *sqlx.DB
+}
+
+// NewSubjectAttributeManager ...
+func NewSubjectAttributeManager() SubjectAttributeManager {
+	return &subjectAttributeManager{
+		DB: database.GetDefaultDBClient().DB,
+	}
+}
+
+// ListBySubjectPK 查询subject的所有属性
+func (m *subjectAttributeManager) ListBySubjectPK(subjectPK int64) (subjectAttributes []SubjectAttribute, err error) {
+	query := `SELECT
+		 pk,
+		 subject_pk,
+		 name,
+		 value
+		 FROM subject_attribute
+		 WHERE subject_pk = ?`
+	err = database.SqlxSelect(m.DB, &subjectAttributes, query, subjectPK)
+	if errors.Is(err, sql.ErrNoRows) {
+		return subjectAttributes, nil
+	}
+	return
+}
+
+// BulkCreateWithTx ...
+func (m *subjectAttributeManager) BulkCreateWithTx(tx *sqlx.Tx, subjectAttributes []SubjectAttribute) error {
+	if len(subjectAttributes) == 0 {
+		return nil
+	}
+
+	sql := `INSERT INTO subject_attribute (
+		subject_pk,
+		name,
+		value
+	) VALUES (
+		:subject_pk,
+		:name,
+		:value)`
+	return database.SqlxBulkInsertWithTx(tx, sql, subjectAttributes)
+}
+
+// BulkDeleteBySubjectPKsWithTx ...
+func (m *subjectAttributeManager) BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error {
+	if len(subjectPKs) == 0 {
+		return nil
+	}
+
+	sql := `DELETE FROM subject_attribute WHERE subject_pk IN (?)`
+	return database.SqlxDeleteWithTx(tx, sql, subjectPKs)
+}
@@ -0,0 +1,76 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database"
+)
+
+func Test_subjectAttributeManager_ListBySubjectPK(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, subject_pk, name, value FROM subject_attribute WHERE subject_pk =`
+		mockRows := sqlmock.NewRows([]string{"pk", "subject_pk", "name", "value"}).
+			AddRow(int64(1), int64(2), "level", "3")
+		mock.ExpectQuery(mockQuery).WithArgs(int64(2)).WillReturnRows(mockRows)
+
+		manager := &subjectAttributeManager{DB: db}
+		subjectAttributes, err := manager.ListBySubjectPK(int64(2))
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []SubjectAttribute{{PK: 1, SubjectPK: 2, Name: "level", Value: "3"}}, subjectAttributes)
+	})
+}
+
+func Test_subjectAttributeManager_BulkCreateWithTx(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^INSERT INTO subject_attribute`).WithArgs(
+			int64(2), "level", "3",
+		).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+
+		manager := &subjectAttributeManager{DB: db}
+		err = manager.BulkCreateWithTx(tx, []SubjectAttribute{{SubjectPK: 2, Name: "level", Value: "3"}})
+
+		tx.Commit()
+
+		assert.NoError(t, err, "query from db fail.")
+	})
+}
+
+func Test_subjectAttributeManager_BulkDeleteBySubjectPKsWithTx(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^DELETE FROM subject_attribute WHERE subject_pk IN`).WithArgs(
+			int64(2), int64(3),
+		).WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectCommit()
+
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+
+		manager := &subjectAttributeManager{DB: db}
+		err = manager.BulkDeleteBySubjectPKsWithTx(tx, []int64{2, 3})
+
+		tx.Commit()
+
+		assert.NoError(t, err, "query from db fail.")
+	})
+}
@@ -0,0 +1,162 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"iam/pkg/database"
+)
+
+// SubjectDelegation 权限委托, delegator将部分操作的权限委托给delegatee
+type SubjectDelegation struct {
+	PK          int64  `db:"pk"`
+	DelegatorPK int64  `db:"delegator_pk"`
+	DelegateePK int64  `db:"delegatee_pk"`
+	SystemID    string `db:"system_id"`
+	// json存储了委托的操作PK列表
+	ActionPKs string `db:"action_pks"`
+	ExpiredAt int64  `db:"expired_at"`
+}
+
+// SubjectDelegationManager ...
+type SubjectDelegationManager interface {
+	Get(pk int64) (SubjectDelegation, error)
+	ListByDelegatee(delegateePK int64, expiredAt int64) ([]SubjectDelegation, error)
+	ListByDelegatorPKs(delegatorPKs []int64) ([]SubjectDelegation, error)
+
+	Create(delegation SubjectDelegation) error
+	Delete(pk int64) (int64, error)
+	BulkDeleteByDelegatorPKs(delegatorPKs []int64) (int64, error)
+	BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error
+}
+
+type subjectDelegationManager struct {
+	DB *sqlx.DB
+}
+
+// NewSubjectDelegationManager ...
+func NewSubjectDelegationManager() SubjectDelegationManager {
+	return &subjectDelegationManager{
+		DB: database.GetDefaultDBClient().DB,
+	}
+}
+
+// Get ...
+func (m *subjectDelegationManager) Get(pk int64) (delegation SubjectDelegation, err error) {
+	query := `SELECT
+		 pk,
+		 delegator_pk,
+		 delegatee_pk,
+		 system_id,
+		 action_pks,
+		 expired_at
+		 FROM subject_delegation
+		 WHERE pk = ?
+		 LIMIT 1`
+	err = database.SqlxGet(m.DB, &delegation, query, pk)
+	return
+}
+
+// ListByDelegatee 查询delegatee在expiredAt之后仍有效的委托
+func (m *subjectDelegationManager) ListByDelegatee(
+	delegateePK int64, expiredAt int64,
+) (delegations []SubjectDelegation, err error) {
+	query := `SELECT
+		 pk,
+		 delegator_pk,
+		 delegatee_pk,
+		 system_id,
+		 action_pks,
+		 expired_at
+		 FROM subject_delegation
+		 WHERE delegatee_pk = ?
+		 AND expired_at > ?
+		 ORDER BY pk`
+	err = database.SqlxSelect(m.DB, &delegations, query, delegateePK, expiredAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return delegations, nil
+	}
+	return
+}
+
+// ListByDelegatorPKs ...
+func (m *subjectDelegationManager) ListByDelegatorPKs(
+	delegatorPKs []int64,
+) (delegations []SubjectDelegation, err error) {
+	if len(delegatorPKs) == 0 {
+		return
+	}
+
+	query := `SELECT
+		 pk,
+		 delegator_pk,
+		 delegatee_pk,
+		 system_id,
+		 action_pks,
+		 expired_at
+		 FROM subject_delegation
+		 WHERE delegator_pk IN (?)
+		 ORDER BY pk`
+	err = database.SqlxSelect(m.DB, &delegations, query, delegatorPKs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return delegations, nil
+	}
+	return
+}
+
+// Create ...
+func (m *subjectDelegationManager) Create(delegation SubjectDelegation) error {
+	sql := `INSERT INTO subject_delegation (
+		delegator_pk,
+		delegatee_pk,
+		system_id,
+		action_pks,
+		expired_at
+	) VALUES (
+		:delegator_pk,
+		:delegatee_pk,
+		:system_id,
+		:action_pks,
+		:expired_at)`
+	return database.SqlxBulkInsert(m.DB, sql, []SubjectDelegation{delegation})
+}
+
+// Delete ...
+func (m *subjectDelegationManager) Delete(pk int64) (int64, error) {
+	sql := `DELETE FROM subject_delegation WHERE pk = ?`
+	return database.SqlxDelete(m.DB, sql, pk)
+}
+
+// BulkDeleteByDelegatorPKs ...
+func (m *subjectDelegationManager) BulkDeleteByDelegatorPKs(delegatorPKs []int64) (int64, error) {
+	if len(delegatorPKs) == 0 {
+		return 0, nil
+	}
+
+	sql := `DELETE FROM subject_delegation WHERE delegator_pk IN (?)`
+	return database.SqlxDelete(m.DB, sql, delegatorPKs)
+}
+
+// BulkDeleteBySubjectPKsWithTx 删除subject作为delegator或delegatee的所有委托
+func (m *subjectDelegationManager) BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error {
+	if len(subjectPKs) == 0 {
+		return nil
+	}
+
+	sql := `DELETE FROM subject_delegation WHERE delegator_pk IN (?) OR delegatee_pk IN (?)`
+	return database.SqlxDeleteWithTx(tx, sql, subjectPKs, subjectPKs)
+}
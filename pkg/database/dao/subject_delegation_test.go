@@ -0,0 +1,125 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database"
+)
+
+func Test_subjectDelegationManager_Get(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, delegator_pk, delegatee_pk, system_id, action_pks, expired_at ` +
+			`FROM subject_delegation WHERE pk = (.*) LIMIT 1`
+		mockRows := sqlmock.NewRows([]string{
+			"pk", "delegator_pk", "delegatee_pk", "system_id", "action_pks", "expired_at",
+		}).AddRow(int64(1), int64(2), int64(3), "bk_cmdb", "[1]", int64(100))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(1)).WillReturnRows(mockRows)
+
+		manager := &subjectDelegationManager{DB: db}
+		delegation, err := manager.Get(1)
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, SubjectDelegation{
+			PK: 1, DelegatorPK: 2, DelegateePK: 3, SystemID: "bk_cmdb", ActionPKs: "[1]", ExpiredAt: 100,
+		}, delegation)
+	})
+}
+
+func Test_subjectDelegationManager_ListByDelegatee(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, delegator_pk, delegatee_pk, system_id, action_pks, expired_at ` +
+			`FROM subject_delegation WHERE delegatee_pk = (.*) AND expired_at > (.*) ORDER BY pk`
+		mockRows := sqlmock.NewRows([]string{
+			"pk", "delegator_pk", "delegatee_pk", "system_id", "action_pks", "expired_at",
+		}).AddRow(int64(1), int64(2), int64(3), "bk_cmdb", "[1]", int64(100))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(3), int64(10)).WillReturnRows(mockRows)
+
+		manager := &subjectDelegationManager{DB: db}
+		delegations, err := manager.ListByDelegatee(3, 10)
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []SubjectDelegation{{
+			PK: 1, DelegatorPK: 2, DelegateePK: 3, SystemID: "bk_cmdb", ActionPKs: "[1]", ExpiredAt: 100,
+		}}, delegations)
+	})
+}
+
+func Test_subjectDelegationManager_ListByDelegatorPKs(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, delegator_pk, delegatee_pk, system_id, action_pks, expired_at ` +
+			`FROM subject_delegation WHERE delegator_pk IN (.*) ORDER BY pk`
+		mockRows := sqlmock.NewRows([]string{
+			"pk", "delegator_pk", "delegatee_pk", "system_id", "action_pks", "expired_at",
+		}).AddRow(int64(1), int64(2), int64(3), "bk_cmdb", "[1]", int64(100))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(2)).WillReturnRows(mockRows)
+
+		manager := &subjectDelegationManager{DB: db}
+		delegations, err := manager.ListByDelegatorPKs([]int64{2})
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Len(t, delegations, 1)
+	})
+}
+
+func Test_subjectDelegationManager_Create(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectExec(`^INSERT INTO subject_delegation`).WithArgs(
+			int64(2), int64(3), "bk_cmdb", "[1]", int64(100),
+		).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		manager := &subjectDelegationManager{DB: db}
+		err := manager.Create(SubjectDelegation{
+			DelegatorPK: 2, DelegateePK: 3, SystemID: "bk_cmdb", ActionPKs: "[1]", ExpiredAt: 100,
+		})
+
+		assert.NoError(t, err, "query from db fail.")
+	})
+}
+
+func Test_subjectDelegationManager_BulkDeleteByDelegatorPKs(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectExec(`^DELETE FROM subject_delegation WHERE delegator_pk IN`).WithArgs(
+			int64(2), int64(3),
+		).WillReturnResult(sqlmock.NewResult(1, 2))
+
+		manager := &subjectDelegationManager{DB: db}
+		rows, err := manager.BulkDeleteByDelegatorPKs([]int64{2, 3})
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, int64(2), rows)
+	})
+}
+
+func Test_subjectDelegationManager_BulkDeleteBySubjectPKsWithTx(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^DELETE FROM subject_delegation WHERE delegator_pk IN (.*) OR delegatee_pk IN`).WithArgs(
+			int64(2), int64(2),
+		).WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectCommit()
+
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+
+		manager := &subjectDelegationManager{DB: db}
+		err = manager.BulkDeleteBySubjectPKsWithTx(tx, []int64{2})
+
+		tx.Commit()
+
+		assert.NoError(t, err, "query from db fail.")
+	})
+}
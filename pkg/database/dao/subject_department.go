@@ -33,6 +33,7 @@ type SubjectDepartmentManager interface {
 	Get(subjectPK int64) (string, error)
 	GetCount() (int64, error)
 	ListPaging(limit, offset int64) ([]SubjectDepartment, error)
+	ListSubjectPKsByDepartmentPK(departmentPK int64) ([]int64, error)
 
 	BulkCreate(subjectDepartments []SubjectDepartment) error
 	BulkUpdate(subjectDepartments []SubjectDepartment) error
@@ -108,6 +109,16 @@ func (m *subjectDepartmentManger) ListPaging(limit, offset int64) ([]SubjectDepa
 	return subjectDepartments, err
 }
 
+// ListSubjectPKsByDepartmentPK 查询部门下的所有subject
+func (m *subjectDepartmentManger) ListSubjectPKsByDepartmentPK(departmentPK int64) ([]int64, error) {
+	subjectPKs := []int64{}
+	err := m.selectSubjectPKsByDepartmentPK(&subjectPKs, departmentPK)
+	if errors.Is(err, sql.ErrNoRows) {
+		return subjectPKs, nil
+	}
+	return subjectPKs, err
+}
+
 func (m *subjectDepartmentManger) getDepartmentPKs(departmentPKs *string, subjectPK int64) error {
 	query := `SELECT
 		department_pks
@@ -116,6 +127,15 @@ func (m *subjectDepartmentManger) getDepartmentPKs(departmentPKs *string, subjec
 	return database.SqlxGet(m.DB, departmentPKs, query, subjectPK)
 }
 
+func (m *subjectDepartmentManger) selectSubjectPKsByDepartmentPK(subjectPKs *[]int64, departmentPK int64) error {
+	// NOTE: department_pks为逗号分隔的字符串, 无法走索引, 只用于审计等低频查询
+	query := `SELECT
+		subject_pk
+		FROM subject_department
+		WHERE FIND_IN_SET(?, department_pks)`
+	return database.SqlxSelect(m.DB, subjectPKs, query, departmentPK)
+}
+
 func (m *subjectDepartmentManger) getCount(count *int64) error {
 	query := `SELECT
 		COUNT(*)
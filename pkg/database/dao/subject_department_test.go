@@ -34,6 +34,20 @@ func Test_subjectDepartmentManger_Get(t *testing.T) {
 	})
 }
 
+func Test_subjectDepartmentManger_ListSubjectPKsByDepartmentPK(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT subject_pk FROM subject_department WHERE FIND_IN_SET`
+		mockRows := sqlmock.NewRows([]string{"subject_pk"}).AddRow(int64(1)).AddRow(int64(2))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(3)).WillReturnRows(mockRows)
+
+		manager := &subjectDepartmentManger{DB: db}
+		pks, err := manager.ListSubjectPKsByDepartmentPK(int64(3))
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []int64{1, 2}, pks)
+	})
+}
+
 func Test_subjectDepartmentManger_GetCount(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
 		mockQuery := `^SELECT (.*) FROM subject_department`
@@ -30,8 +30,10 @@ type SubjectRelation struct {
 	GroupPK int64 `db:"parent_pk"`
 	// 策略有效期，unix time，单位秒(s)
 	// NOTE: map policy_expired_at to ExpiredAt in dao
-	ExpiredAt int64     `db:"policy_expired_at"`
-	CreatedAt time.Time `db:"created_at"`
+	ExpiredAt int64 `db:"policy_expired_at"`
+	// 生效时间，unix time，单位秒(s), 0表示已生效
+	EffectiveAt int64     `db:"effective_at"`
+	CreatedAt   time.Time `db:"created_at"`
 }
 
 // ThinSubjectRelation with the minimum fields of the relationship: subject-group-policy_expired_at
@@ -41,11 +43,16 @@ type ThinSubjectRelation struct {
 	GroupPK int64 `db:"parent_pk"`
 	// NOTE: map policy_expired_at to ExpiredAt in dao
 	ExpiredAt int64 `db:"policy_expired_at"`
+	// 生效时间, 0表示已生效, 只有部分查询返回
+	EffectiveAt int64 `db:"effective_at"`
 }
 
 // SubjectGroupManager ...
 type SubjectGroupManager interface {
-	ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs []int64, expiredAt int64) ([]ThinSubjectRelation, error)
+	ListThinRelationAfterExpiredAtBySubjectPKs(
+		subjectPKs []int64,
+		expiredAt, effectiveAt int64,
+	) ([]ThinSubjectRelation, error)
 
 	GetSubjectGroupCount(subjectPK int64) (int64, error)
 	GetSubjectGroupCountBeforeExpiredAt(subjectPK int64, expiredAt int64) (int64, error)
@@ -74,15 +81,23 @@ type SubjectGroupManager interface {
 	BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error
 	BulkDeleteByGroupPKsWithTx(tx *sqlx.Tx, groupPKs []int64) error
 	BulkUpdateExpiredAtWithTx(tx *sqlx.Tx, relations []SubjectRelation) error
+	BulkUpdateEffectiveAtWithTx(tx *sqlx.Tx, relations []SubjectRelation) error
 
 	ListGroupMember(groupPK int64) ([]SubjectRelation, error)
+	ListMemberGroupRelationsByGroupPKs(groupPKs []int64) ([]ThinSubjectRelation, error)
+	ListSubDepartmentRelationsByGroupPKs(groupPKs []int64) ([]ThinSubjectRelation, error)
+	ListSubDepartmentRelationAfterExpiredAtBySubjectPKs(
+		subjectPKs []int64, expiredAt, effectiveAt int64,
+	) ([]ThinSubjectRelation, error)
+	UpdateIncludeSubDepartment(groupPK int64, subjectPKs []int64, includeSubDepartment bool) error
 	ListPagingGroupMember(groupPK int64, limit, offset int64) ([]SubjectRelation, error)
 	ListPagingGroupMemberBeforeExpiredAt(
 		groupPK int64, expiredAt int64, limit, offset int64,
 	) (members []SubjectRelation, err error)
 	GetGroupMemberCount(groupPK int64) (int64, error)
 	GetGroupMemberCountBeforeExpiredAt(groupPK int64, expiredAt int64) (int64, error)
-	GetExpiredAtBySubjectGroup(subjectPK, groupPK int64) (int64, error)
+	GetExpiredAtBySubjectGroup(subjectPK, groupPK, effectiveAt int64) (int64, error)
+	ListPendingRelationBeforeEffectiveAt(effectiveAt int64, limit int64) ([]SubjectRelation, error)
 
 	BulkDeleteByGroupMembersWithTx(tx *sqlx.Tx, groupPK int64, subjectPKs []int64) (int64, error)
 }
@@ -134,6 +149,7 @@ func (m *subjectGroupManager) ListPagingSubjectGroups(
 		 subject_pk,
 		 parent_pk,
 		 policy_expired_at,
+		 effective_at,
 		 created_at
 		 FROM subject_relation
 		 WHERE subject_pk = ?
@@ -156,6 +172,7 @@ func (m *subjectGroupManager) ListPagingSubjectSystemGroups(
 		 t.subject_pk,
 		 t.parent_pk,
 		 t.policy_expired_at,
+		 t.effective_at,
 		 t.created_at
 		 FROM subject_relation t
 		 LEFT JOIN group_system_auth_type s
@@ -226,6 +243,7 @@ func (m *subjectGroupManager) ListPagingSubjectGroupBeforeExpiredAt(
 		 subject_pk,
 		 parent_pk,
 		 policy_expired_at,
+		 effective_at,
 		 created_at
 		 FROM subject_relation
 		 WHERE subject_pk = ?
@@ -249,6 +267,7 @@ func (m *subjectGroupManager) ListPagingSubjectSystemGroupBeforeExpiredAt(
 	     t.subject_pk,
 	     t.parent_pk,
 	     t.policy_expired_at,
+	     t.effective_at,
 	     t.created_at
 	     FROM subject_relation t
 	     LEFT JOIN group_system_auth_type s
@@ -266,10 +285,11 @@ func (m *subjectGroupManager) ListPagingSubjectSystemGroupBeforeExpiredAt(
 	return
 }
 
-// ListThinRelationAfterExpiredAtBySubjectPKs ...
-func (m *subjectGroupManager) ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs []int64, expiredAt int64) (
-	relations []ThinSubjectRelation, err error,
-) {
+// ListThinRelationAfterExpiredAtBySubjectPKs 查询未过期且在 effectiveAt 时已生效的关系
+func (m *subjectGroupManager) ListThinRelationAfterExpiredAtBySubjectPKs(
+	subjectPKs []int64,
+	expiredAt, effectiveAt int64,
+) (relations []ThinSubjectRelation, err error) {
 	if len(subjectPKs) == 0 {
 		return
 	}
@@ -280,8 +300,9 @@ func (m *subjectGroupManager) ListThinRelationAfterExpiredAtBySubjectPKs(subject
 		 policy_expired_at
 		 FROM subject_relation
 		 WHERE subject_pk in (?)
-		 AND policy_expired_at > ?`
-	err = database.SqlxSelect(m.DB, &relations, query, subjectPKs, expiredAt)
+		 AND policy_expired_at > ?
+		 AND effective_at <= ?`
+	err = database.SqlxSelect(m.DB, &relations, query, subjectPKs, expiredAt, effectiveAt)
 	// 吞掉记录不存在的错误, subject本身是可以不加入任何用户组和组织的
 	if errors.Is(err, sql.ErrNoRows) {
 		return relations, nil
@@ -307,6 +328,7 @@ func (m *subjectGroupManager) ListGroupMember(groupPK int64) (members []SubjectR
 		 subject_pk,
 		 parent_pk,
 		 policy_expired_at,
+		 effective_at,
 		 created_at
 		 FROM subject_relation
 		 WHERE parent_pk = ?`
@@ -317,15 +339,106 @@ func (m *subjectGroupManager) ListGroupMember(groupPK int64) (members []SubjectR
 	return
 }
 
+// ListMemberGroupRelationsByGroupPKs 查询groups中成员类型为group的关系(嵌套用户组)
+func (m *subjectGroupManager) ListMemberGroupRelationsByGroupPKs(groupPKs []int64) (
+	relations []ThinSubjectRelation, err error,
+) {
+	if len(groupPKs) == 0 {
+		return
+	}
+
+	query := `SELECT
+		 r.subject_pk,
+		 r.parent_pk,
+		 r.policy_expired_at,
+		 r.effective_at
+		 FROM subject_relation r
+		 INNER JOIN subject s
+		 ON r.subject_pk = s.pk
+		 WHERE r.parent_pk IN (?)
+		 AND s.type = 'group'`
+	err = database.SqlxSelect(m.DB, &relations, query, groupPKs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return relations, nil
+	}
+	return
+}
+
+// ListSubDepartmentRelationsByGroupPKs 查询groups中包含子部门的部门成员关系
+func (m *subjectGroupManager) ListSubDepartmentRelationsByGroupPKs(groupPKs []int64) (
+	relations []ThinSubjectRelation, err error,
+) {
+	if len(groupPKs) == 0 {
+		return
+	}
+
+	query := `SELECT
+		 subject_pk,
+		 parent_pk,
+		 policy_expired_at,
+		 effective_at
+		 FROM subject_relation
+		 WHERE parent_pk IN (?)
+		 AND include_sub_department = 1`
+	err = database.SqlxSelect(m.DB, &relations, query, groupPKs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return relations, nil
+	}
+	return
+}
+
+// ListSubDepartmentRelationAfterExpiredAtBySubjectPKs 查询部门加入的包含子部门的用户组关系
+func (m *subjectGroupManager) ListSubDepartmentRelationAfterExpiredAtBySubjectPKs(
+	subjectPKs []int64,
+	expiredAt, effectiveAt int64,
+) (relations []ThinSubjectRelation, err error) {
+	if len(subjectPKs) == 0 {
+		return
+	}
+
+	query := `SELECT
+		 subject_pk,
+		 parent_pk,
+		 policy_expired_at
+		 FROM subject_relation
+		 WHERE subject_pk IN (?)
+		 AND policy_expired_at > ?
+		 AND effective_at <= ?
+		 AND include_sub_department = 1`
+	err = database.SqlxSelect(m.DB, &relations, query, subjectPKs, expiredAt, effectiveAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return relations, nil
+	}
+	return
+}
+
+// UpdateIncludeSubDepartment 设置部门成员是否包含子部门
+func (m *subjectGroupManager) UpdateIncludeSubDepartment(
+	groupPK int64,
+	subjectPKs []int64,
+	includeSubDepartment bool,
+) error {
+	if len(subjectPKs) == 0 {
+		return nil
+	}
+
+	sql := `UPDATE subject_relation
+		 SET include_sub_department = ?
+		 WHERE parent_pk = ?
+		 AND subject_pk IN (?)`
+	return database.SqlxExec(m.DB, sql, includeSubDepartment, groupPK, subjectPKs)
+}
+
 // GetExpiredAtBySubjectGroup ...
-func (m *subjectGroupManager) GetExpiredAtBySubjectGroup(subjectPK, groupPK int64) (int64, error) {
+func (m *subjectGroupManager) GetExpiredAtBySubjectGroup(subjectPK, groupPK, effectiveAt int64) (int64, error) {
 	var expiredAt int64
 	query := `SELECT
 		 policy_expired_at
 		 FROM subject_relation
 		 WHERE subject_pk = ?
-		 AND parent_pk = ?`
-	err := database.SqlxGet(m.DB, &expiredAt, query, subjectPK, groupPK)
+		 AND parent_pk = ?
+		 AND effective_at <= ?`
+	err := database.SqlxGet(m.DB, &expiredAt, query, subjectPK, groupPK, effectiveAt)
 
 	return expiredAt, err
 }
@@ -433,6 +546,7 @@ func (m *subjectGroupManager) ListRelationBySubjectPKGroupPKs(
 		 subject_pk,
 		 parent_pk,
 		 policy_expired_at,
+		 effective_at,
 		 created_at
 		 FROM subject_relation
 		 WHERE subject_pk = ?
@@ -457,6 +571,45 @@ func (m *subjectGroupManager) BulkUpdateExpiredAtWithTx(
 	return database.SqlxBulkUpdateWithTx(tx, sql, relations)
 }
 
+// BulkUpdateEffectiveAtWithTx ...
+func (m *subjectGroupManager) BulkUpdateEffectiveAtWithTx(
+	tx *sqlx.Tx,
+	relations []SubjectRelation,
+) error {
+	if len(relations) == 0 {
+		return nil
+	}
+
+	sql := `UPDATE subject_relation
+		 SET effective_at = :effective_at
+		 WHERE subject_pk = :subject_pk AND parent_pk = :parent_pk`
+	return database.SqlxBulkUpdateWithTx(tx, sql, relations)
+}
+
+// ListPendingRelationBeforeEffectiveAt 查询已到生效时间但尚未生效的关系
+func (m *subjectGroupManager) ListPendingRelationBeforeEffectiveAt(
+	effectiveAt int64,
+	limit int64,
+) (relations []SubjectRelation, err error) {
+	query := `SELECT
+		 pk,
+		 subject_pk,
+		 parent_pk,
+		 policy_expired_at,
+		 effective_at,
+		 created_at
+		 FROM subject_relation
+		 WHERE effective_at > 0
+		 AND effective_at <= ?
+		 ORDER BY effective_at
+		 LIMIT ?`
+	err = database.SqlxSelect(m.DB, &relations, query, effectiveAt, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return relations, nil
+	}
+	return
+}
+
 func (m *subjectGroupManager) selectPagingMembers(
 	members *[]SubjectRelation, groupPK int64, limit, offset int64,
 ) error {
@@ -465,6 +618,7 @@ func (m *subjectGroupManager) selectPagingMembers(
 		 subject_pk,
 		 parent_pk,
 		 policy_expired_at,
+		 effective_at,
 		 created_at
 		 FROM subject_relation
 		 WHERE parent_pk = ?
@@ -481,6 +635,7 @@ func (m *subjectGroupManager) selectPagingMembersBeforeExpiredAt(
 		 subject_pk,
 		 parent_pk,
 		 policy_expired_at,
+		 effective_at,
 		 created_at
 		 FROM subject_relation
 		 WHERE parent_pk = ?
@@ -534,10 +689,12 @@ func (m *subjectGroupManager) bulkInsertWithTx(tx *sqlx.Tx, relations []SubjectR
 	sql := `INSERT INTO subject_relation (
 		subject_pk,
 		parent_pk,
-		policy_expired_at
+		policy_expired_at,
+		effective_at
 	) VALUES (:subject_pk,
 		:parent_pk,
-		:policy_expired_at)`
+		:policy_expired_at,
+		:effective_at)`
 	return database.SqlxBulkInsertWithTx(tx, sql, relations)
 }
 
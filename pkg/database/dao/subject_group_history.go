@@ -0,0 +1,190 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"iam/pkg/database"
+)
+
+// SubjectGroupHistory 用户组成员变更记录
+type SubjectGroupHistory struct {
+	PK         int64  `db:"pk"`
+	SubjectPK  int64  `db:"subject_pk"`
+	GroupPK    int64  `db:"group_pk"`
+	TemplateID int64  `db:"template_id"`
+	Action     string `db:"action"`
+	Source     string `db:"source"`
+	ExpiredAt  int64  `db:"expired_at"`
+	// 生效时间, 0表示立即生效
+	EffectiveAt int64     `db:"effective_at"`
+	Actor       string    `db:"actor"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// SubjectGroupHistoryManager ...
+type SubjectGroupHistoryManager interface {
+	GetGroupHistoryCount(groupPK int64) (int64, error)
+	ListPagingGroupHistory(groupPK, limit, offset int64) ([]SubjectGroupHistory, error)
+	GetSubjectHistoryCount(subjectPK int64) (int64, error)
+	ListPagingSubjectHistory(subjectPK, limit, offset int64) ([]SubjectGroupHistory, error)
+	ListSubjectHistoryBeforeCreatedAt(subjectPK int64, createdAt time.Time) ([]SubjectGroupHistory, error)
+
+	BulkCreate(histories []SubjectGroupHistory) error
+	BulkCreateWithTx(tx *sqlx.Tx, histories []SubjectGroupHistory) error
+}
+
+type subjectGroupHistoryManager struct {
+	DB *sqlx.DB
+}
+
+// NewSubjectGroupHistoryManager ...
+func NewSubjectGroupHistoryManager() SubjectGroupHistoryManager {
+	return &subjectGroupHistoryManager{
+		DB: database.GetDefaultDBClient().DB,
+	}
+}
+
+// GetGroupHistoryCount ...
+func (m *subjectGroupHistoryManager) GetGroupHistoryCount(groupPK int64) (count int64, err error) {
+	query := `SELECT COUNT(*) FROM subject_group_history WHERE group_pk = ?`
+	err = database.SqlxGet(m.DB, &count, query, groupPK)
+	return
+}
+
+// ListPagingGroupHistory 按时间倒序查询用户组的成员变更记录
+func (m *subjectGroupHistoryManager) ListPagingGroupHistory(
+	groupPK, limit, offset int64,
+) (histories []SubjectGroupHistory, err error) {
+	query := `SELECT
+		 pk,
+		 subject_pk,
+		 group_pk,
+		 template_id,
+		 action,
+		 source,
+		 expired_at,
+		 effective_at,
+		 actor,
+		 created_at
+		 FROM subject_group_history
+		 WHERE group_pk = ?
+		 ORDER BY pk DESC
+		 LIMIT ? OFFSET ?`
+	err = database.SqlxSelect(m.DB, &histories, query, groupPK, limit, offset)
+	if errors.Is(err, sql.ErrNoRows) {
+		return histories, nil
+	}
+	return
+}
+
+// GetSubjectHistoryCount ...
+func (m *subjectGroupHistoryManager) GetSubjectHistoryCount(subjectPK int64) (count int64, err error) {
+	query := `SELECT COUNT(*) FROM subject_group_history WHERE subject_pk = ?`
+	err = database.SqlxGet(m.DB, &count, query, subjectPK)
+	return
+}
+
+// ListPagingSubjectHistory 按时间倒序查询subject的用户组变更记录
+func (m *subjectGroupHistoryManager) ListPagingSubjectHistory(
+	subjectPK, limit, offset int64,
+) (histories []SubjectGroupHistory, err error) {
+	query := `SELECT
+		 pk,
+		 subject_pk,
+		 group_pk,
+		 template_id,
+		 action,
+		 source,
+		 expired_at,
+		 effective_at,
+		 actor,
+		 created_at
+		 FROM subject_group_history
+		 WHERE subject_pk = ?
+		 ORDER BY pk DESC
+		 LIMIT ? OFFSET ?`
+	err = database.SqlxSelect(m.DB, &histories, query, subjectPK, limit, offset)
+	if errors.Is(err, sql.ErrNoRows) {
+		return histories, nil
+	}
+	return
+}
+
+// ListSubjectHistoryBeforeCreatedAt 按变更顺序查询subject在createdAt之前的所有变更记录, 用于回放某一时刻的成员关系
+func (m *subjectGroupHistoryManager) ListSubjectHistoryBeforeCreatedAt(
+	subjectPK int64, createdAt time.Time,
+) (histories []SubjectGroupHistory, err error) {
+	query := `SELECT
+		 pk,
+		 subject_pk,
+		 group_pk,
+		 template_id,
+		 action,
+		 source,
+		 expired_at,
+		 effective_at,
+		 actor,
+		 created_at
+		 FROM subject_group_history
+		 WHERE subject_pk = ?
+		 AND created_at <= ?
+		 ORDER BY pk`
+	err = database.SqlxSelect(m.DB, &histories, query, subjectPK, createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return histories, nil
+	}
+	return
+}
+
+// BulkCreate ...
+func (m *subjectGroupHistoryManager) BulkCreate(histories []SubjectGroupHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+
+	return database.SqlxBulkInsert(m.DB, insertSubjectGroupHistorySQL, histories)
+}
+
+// BulkCreateWithTx ...
+func (m *subjectGroupHistoryManager) BulkCreateWithTx(tx *sqlx.Tx, histories []SubjectGroupHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+
+	return database.SqlxBulkInsertWithTx(tx, insertSubjectGroupHistorySQL, histories)
+}
+
+const insertSubjectGroupHistorySQL = `INSERT INTO subject_group_history (
+	subject_pk,
+	group_pk,
+	template_id,
+	action,
+	source,
+	expired_at,
+	effective_at,
+	actor
+) VALUES (
+	:subject_pk,
+	:group_pk,
+	:template_id,
+	:action,
+	:source,
+	:expired_at,
+	:effective_at,
+	:actor)`
@@ -0,0 +1,109 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database"
+)
+
+func Test_subjectGroupHistoryManager_GetGroupHistoryCount(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT COUNT(.*) FROM subject_group_history WHERE group_pk = `
+		mockRows := sqlmock.NewRows([]string{"count(*)"}).AddRow(int64(2))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(1)).WillReturnRows(mockRows)
+
+		manager := &subjectGroupHistoryManager{DB: db}
+		cnt, err := manager.GetGroupHistoryCount(int64(1))
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, int64(2), cnt)
+	})
+}
+
+func Test_subjectGroupHistoryManager_ListPagingSubjectHistory(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		now := time.Now()
+		mockQuery := `^SELECT pk, subject_pk, group_pk, template_id, action, source, expired_at, effective_at, actor, ` +
+			`created_at FROM subject_group_history WHERE subject_pk = (.*) ORDER BY pk DESC LIMIT (.*) OFFSET (.*)$`
+		mockRows := sqlmock.NewRows([]string{
+			"pk", "subject_pk", "group_pk", "template_id", "action", "source",
+			"expired_at", "effective_at", "actor", "created_at",
+		}).AddRow(int64(1), int64(2), int64(3), int64(0), "add", "manual", int64(10), int64(0), "admin", now)
+		mock.ExpectQuery(mockQuery).WithArgs(int64(2), int64(10), int64(0)).WillReturnRows(mockRows)
+
+		manager := &subjectGroupHistoryManager{DB: db}
+		histories, err := manager.ListPagingSubjectHistory(int64(2), int64(10), int64(0))
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []SubjectGroupHistory{{
+			PK:        1,
+			SubjectPK: 2,
+			GroupPK:   3,
+			Action:    "add",
+			Source:    "manual",
+			ExpiredAt: 10,
+			Actor:     "admin",
+			CreatedAt: now,
+		}}, histories)
+	})
+}
+
+func Test_subjectGroupHistoryManager_ListSubjectHistoryBeforeCreatedAt(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		at := time.Unix(100, 0)
+		mockQuery := `^SELECT pk, subject_pk, group_pk, template_id, action, source, expired_at, effective_at, actor, ` +
+			`created_at FROM subject_group_history WHERE subject_pk = (.*) AND created_at <= (.*) ORDER BY pk$`
+		mockRows := sqlmock.NewRows([]string{
+			"pk", "subject_pk", "group_pk", "template_id", "action", "source",
+			"expired_at", "effective_at", "actor", "created_at",
+		}).AddRow(int64(1), int64(2), int64(3), int64(0), "add", "manual", int64(10), int64(0), "admin", at)
+		mock.ExpectQuery(mockQuery).WithArgs(int64(2), at).WillReturnRows(mockRows)
+
+		manager := &subjectGroupHistoryManager{DB: db}
+		histories, err := manager.ListSubjectHistoryBeforeCreatedAt(int64(2), at)
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Len(t, histories, 1)
+		assert.Equal(t, int64(3), histories[0].GroupPK)
+	})
+}
+
+func Test_subjectGroupHistoryManager_BulkCreateWithTx(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^INSERT INTO subject_group_history`).WithArgs(
+			int64(2), int64(3), int64(0), "remove", "sync", int64(0), int64(0), "",
+		).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+
+		manager := &subjectGroupHistoryManager{DB: db}
+		err = manager.BulkCreateWithTx(tx, []SubjectGroupHistory{{
+			SubjectPK: 2,
+			GroupPK:   3,
+			Action:    "remove",
+			Source:    "sync",
+		}})
+
+		tx.Commit()
+
+		assert.NoError(t, err, "query from db fail.")
+	})
+}
@@ -36,7 +36,7 @@ func Test_subjectRelationManager_GetCount(t *testing.T) {
 
 func Test_subjectRelationManager_List(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
-		mockQuery := `^SELECT pk, subject_pk, parent_pk, policy_expired_at, created_at FROM subject_relation
+		mockQuery := `^SELECT pk, subject_pk, parent_pk, policy_expired_at, effective_at, created_at FROM subject_relation
 		 WHERE parent_pk = (.*) ORDER BY pk DESC LIMIT (.*) OFFSET (.*)`
 		mockRows := sqlmock.NewRows(
 			[]string{"pk", "subject_pk", "parent_pk", "policy_expired_at"},
@@ -67,7 +67,7 @@ func Test_subjectRelationManager_GetSubjectGroupCount(t *testing.T) {
 
 func Test_subjectRelationManager_ListPagingRelation(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
-		mockQuery := `^SELECT pk, subject_pk, parent_pk, policy_expired_at, created_at FROM subject_relation
+		mockQuery := `^SELECT pk, subject_pk, parent_pk, policy_expired_at, effective_at, created_at FROM subject_relation
 		 WHERE subject_pk = (.*) ORDER BY pk DESC LIMIT (.*) OFFSET (.*)`
 		mockRows := sqlmock.NewRows(
 			[]string{
@@ -125,6 +125,7 @@ func Test_subjectRelationManager_ListPagingRelationBeforeExpiredAt(t *testing.T)
 		 subject_pk,
 		 parent_pk,
 		 policy_expired_at,
+		 effective_at,
 		 created_at
 		 FROM subject_relation
 		 WHERE subject_pk = (.*)
@@ -164,7 +165,7 @@ func Test_subjectRelationManager_BulkCreateWithTx(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectExec(`^INSERT INTO subject_relation`).WithArgs(
-			int64(2), int64(1), int64(3),
+			int64(2), int64(1), int64(3), int64(0),
 		).WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
@@ -193,6 +194,7 @@ func Test_subjectRelationManager_ListThinRelationBySubjectPKGroupPKs(t *testing.
 		subject_pk,
 		parent_pk,
 		policy_expired_at,
+		effective_at,
 		created_at
 		FROM subject_relation
 		WHERE subject_pk = (.*)
@@ -219,12 +221,13 @@ func Test_subjectRelationManager_ListThinRelationBySubjectPKGroupPKs(t *testing.
 
 func Test_subjectRelationManager_GetExpiredAtBySubjectGroup(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
-		mockQuery := `^SELECT policy_expired_at FROM subject_relation WHERE subject_pk = (.*) AND parent_pk = (.*)`
+		mockQuery := `^SELECT policy_expired_at FROM subject_relation WHERE subject_pk = (.*) AND parent_pk = (.*)
+		 AND effective_at <= (.*)`
 		mockRows := sqlmock.NewRows([]string{"policy_expired_at"}).AddRow(int64(1))
-		mock.ExpectQuery(mockQuery).WithArgs(int64(1), int64(10)).WillReturnRows(mockRows)
+		mock.ExpectQuery(mockQuery).WithArgs(int64(1), int64(10), int64(100)).WillReturnRows(mockRows)
 
 		manager := &subjectGroupManager{DB: db}
-		expiredAt, err := manager.GetExpiredAtBySubjectGroup(int64(1), int64(10))
+		expiredAt, err := manager.GetExpiredAtBySubjectGroup(int64(1), int64(10), int64(100))
 
 		assert.NoError(t, err, "query from db fail.")
 		assert.Equal(t, expiredAt, int64(1))
@@ -261,7 +264,7 @@ func Test_subjectRelationManager_GetSubjectSystemGroupCountBeforeExpiredAt(t *te
 
 func Test_subjectRelationManager_ListPagingSubjectSystemGroups(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
-		mockQuery := `^SELECT t.pk, t.subject_pk, t.parent_pk, t.policy_expired_at, t.created_at
+		mockQuery := `^SELECT t.pk, t.subject_pk, t.parent_pk, t.policy_expired_at, t.effective_at, t.created_at
 		 FROM subject_relation t LEFT JOIN group_system_auth_type s ON t.parent_pk = s.group_pk
 		 WHERE t.subject_pk = (.*) AND s.system_id = (.*) ORDER BY t.pk DESC LIMIT (.*) OFFSET (.*)`
 		mockRows := sqlmock.NewRows(
@@ -281,7 +284,7 @@ func Test_subjectRelationManager_ListPagingSubjectSystemGroups(t *testing.T) {
 
 func Test_subjectRelationManager_ListPagingSubjectSystemGroupBeforeExpiredAt(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
-		mockQuery := `^SELECT t.pk, t.subject_pk, t.parent_pk, t.policy_expired_at, t.created_at
+		mockQuery := `^SELECT t.pk, t.subject_pk, t.parent_pk, t.policy_expired_at, t.effective_at, t.created_at
 		 FROM subject_relation t LEFT JOIN group_system_auth_type s ON t.parent_pk = s.group_pk
 		 WHERE t.subject_pk = (.*) AND t.policy_expired_at < (.*) AND s.system_id = (.*) ORDER BY t.pk DESC LIMIT (.*) OFFSET (.*)`
 		mockRows := sqlmock.NewRows(
@@ -319,3 +322,106 @@ func Test_subjectRelationManager_BulkUpdateExpiredAtWithTx(t *testing.T) {
 		assert.NoError(t, err, "query from db fail.")
 	})
 }
+
+func Test_subjectRelationManager_ListMemberGroupRelationsByGroupPKs(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT r.subject_pk, r.parent_pk, r.policy_expired_at, r.effective_at FROM subject_relation r
+		 INNER JOIN subject s ON r.subject_pk = s.pk WHERE r.parent_pk IN (.*) AND s.type = 'group'`
+		mockRows := sqlmock.NewRows(
+			[]string{"subject_pk", "parent_pk", "policy_expired_at", "effective_at"},
+		).AddRow(int64(2), int64(1), int64(10), int64(5))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(1)).WillReturnRows(mockRows)
+
+		manager := &subjectGroupManager{DB: db}
+		relations, err := manager.ListMemberGroupRelationsByGroupPKs([]int64{1})
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []ThinSubjectRelation{{SubjectPK: 2, GroupPK: 1, ExpiredAt: 10, EffectiveAt: 5}}, relations)
+	})
+}
+
+func Test_subjectRelationManager_ListSubDepartmentRelationsByGroupPKs(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT subject_pk, parent_pk, policy_expired_at, effective_at FROM subject_relation
+		 WHERE parent_pk IN (.*) AND include_sub_department = 1`
+		mockRows := sqlmock.NewRows(
+			[]string{"subject_pk", "parent_pk", "policy_expired_at", "effective_at"},
+		).AddRow(int64(2), int64(1), int64(10), int64(0))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(1)).WillReturnRows(mockRows)
+
+		manager := &subjectGroupManager{DB: db}
+		relations, err := manager.ListSubDepartmentRelationsByGroupPKs([]int64{1})
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []ThinSubjectRelation{{SubjectPK: 2, GroupPK: 1, ExpiredAt: 10}}, relations)
+	})
+}
+
+func Test_subjectRelationManager_ListSubDepartmentRelationAfterExpiredAtBySubjectPKs(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT subject_pk, parent_pk, policy_expired_at FROM subject_relation
+		 WHERE subject_pk IN (.*) AND policy_expired_at > (.*) AND effective_at <= (.*) AND include_sub_department = 1`
+		mockRows := sqlmock.NewRows(
+			[]string{"subject_pk", "parent_pk", "policy_expired_at"},
+		).AddRow(int64(2), int64(1), int64(10))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(2), int64(5), int64(5)).WillReturnRows(mockRows)
+
+		manager := &subjectGroupManager{DB: db}
+		relations, err := manager.ListSubDepartmentRelationAfterExpiredAtBySubjectPKs([]int64{2}, 5, 5)
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []ThinSubjectRelation{{SubjectPK: 2, GroupPK: 1, ExpiredAt: 10}}, relations)
+	})
+}
+
+func Test_subjectRelationManager_UpdateIncludeSubDepartment(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^UPDATE subject_relation SET include_sub_department = (.*) WHERE parent_pk = (.*)
+		 AND subject_pk IN (.*)`
+		mock.ExpectExec(mockQuery).WithArgs(true, int64(1), int64(2)).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		manager := &subjectGroupManager{DB: db}
+		err := manager.UpdateIncludeSubDepartment(1, []int64{2}, true)
+
+		assert.NoError(t, err, "query from db fail.")
+	})
+}
+
+func Test_subjectRelationManager_ListPendingRelationBeforeEffectiveAt(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, subject_pk, parent_pk, policy_expired_at, effective_at, created_at FROM subject_relation
+		 WHERE effective_at > 0 AND effective_at <= (.*) ORDER BY effective_at LIMIT (.*)`
+		mockRows := sqlmock.NewRows(
+			[]string{"pk", "subject_pk", "parent_pk", "policy_expired_at", "effective_at"},
+		).AddRow(int64(1), int64(2), int64(3), int64(20), int64(5))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(10), int64(100)).WillReturnRows(mockRows)
+
+		manager := &subjectGroupManager{DB: db}
+		relations, err := manager.ListPendingRelationBeforeEffectiveAt(10, 100)
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []SubjectRelation{{PK: 1, SubjectPK: 2, GroupPK: 3, ExpiredAt: 20, EffectiveAt: 5}}, relations)
+	})
+}
+
+func Test_subjectRelationManager_BulkUpdateEffectiveAtWithTx(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^UPDATE subject_relation SET effective_at = (.*) WHERE subject_pk = (.*) AND parent_pk = (.*)`
+		mock.ExpectBegin()
+		mock.ExpectPrepare(mockQuery)
+		mock.ExpectExec(mockQuery).WithArgs(int64(0), int64(2), int64(1)).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+		manager := &subjectGroupManager{DB: db}
+		err = manager.BulkUpdateEffectiveAtWithTx(tx, []SubjectRelation{{
+			SubjectPK: int64(2),
+			GroupPK:   int64(1),
+		}})
+
+		tx.Commit()
+
+		assert.NoError(t, err)
+	})
+}
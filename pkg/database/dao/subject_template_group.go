@@ -24,28 +24,32 @@ import (
 
 // SubjectTemplateGroup  用户/部门-人员模版-用户组关系表
 type SubjectTemplateGroup struct {
-	PK         int64     `db:"pk"`
-	SubjectPK  int64     `db:"subject_pk"`
-	TemplateID int64     `db:"template_id"`
-	GroupPK    int64     `db:"group_pk"`
-	ExpiredAt  int64     `db:"expired_at"`
-	CreatedAt  time.Time `db:"created_at"`
+	PK         int64 `db:"pk"`
+	SubjectPK  int64 `db:"subject_pk"`
+	TemplateID int64 `db:"template_id"`
+	GroupPK    int64 `db:"group_pk"`
+	ExpiredAt  int64 `db:"expired_at"`
+	// 生效时间, 0表示已生效
+	EffectiveAt int64     `db:"effective_at"`
+	CreatedAt   time.Time `db:"created_at"`
 }
 
 type SubjectTemplateGroupManager interface {
 	GetTemplateGroupMemberCount(groupPK, templateID int64) (int64, error)
-	GetMaxExpiredAtBySubjectGroup(subjectPK, groupPK int64, excludeTemplateID int64) (int64, error)
+	GetMaxExpiredAtBySubjectGroup(subjectPK, groupPK int64, excludeTemplateID int64, effectiveAt int64) (int64, error)
 	ListPagingTemplateGroupMember(
 		groupPK, templateID int64,
 		limit, offset int64,
 	) (members []SubjectTemplateGroup, err error)
 	ListRelationBySubjectPKGroupPKs(subjectPK int64, groupPKs []int64) ([]SubjectTemplateGroup, error)
 	ListGroupDistinctSubjectPK(groupPK int64) (subjectPKs []int64, err error)
-	ListThinRelationWithMaxExpiredAtByGroupPK(groupPK int64) ([]ThinSubjectRelation, error)
+	ListThinRelationWithMaxExpiredAtByGroupPK(groupPK, effectiveAt int64) ([]ThinSubjectRelation, error)
+	ListPendingRelationBeforeEffectiveAt(effectiveAt int64, limit int64) ([]SubjectTemplateGroup, error)
 
 	BulkCreateWithTx(tx *sqlx.Tx, relations []SubjectTemplateGroup) error
 	BulkUpdateExpiredAtWithTx(tx *sqlx.Tx, relations []SubjectTemplateGroup) error
 	BulkUpdateExpiredAtByRelationWithTx(tx *sqlx.Tx, relations []SubjectRelation) error
+	BulkUpdateEffectiveAtWithTx(tx *sqlx.Tx, relations []SubjectTemplateGroup) error
 	BulkDeleteWithTx(tx *sqlx.Tx, relations []SubjectTemplateGroup) error
 	BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error
 }
@@ -71,11 +75,13 @@ func (m *subjectTemplateGroupManager) BulkCreateWithTx(tx *sqlx.Tx, relations []
 		subject_pk,
 		template_id,
 		group_pk,
-		expired_at
+		expired_at,
+		effective_at
 	) VALUES (:subject_pk,
 		:template_id,
 		:group_pk,
-		:expired_at)`
+		:expired_at,
+		:effective_at)`
 	return database.SqlxBulkInsertWithTx(tx, sql, relations)
 }
 
@@ -103,6 +109,23 @@ func (m *subjectTemplateGroupManager) BulkUpdateExpiredAtWithTx(
 	return database.SqlxBulkUpdateWithTx(tx, sql, relations)
 }
 
+// BulkUpdateEffectiveAtWithTx ...
+func (m *subjectTemplateGroupManager) BulkUpdateEffectiveAtWithTx(
+	tx *sqlx.Tx,
+	relations []SubjectTemplateGroup,
+) error {
+	if len(relations) == 0 {
+		return nil
+	}
+
+	sql := `UPDATE subject_template_group
+		 SET effective_at = :effective_at
+		 WHERE subject_pk = :subject_pk
+		 AND group_pk = :group_pk
+		 AND template_id = :template_id`
+	return database.SqlxBulkUpdateWithTx(tx, sql, relations)
+}
+
 // BulkDeleteWithTx ...
 func (m *subjectTemplateGroupManager) BulkDeleteWithTx(tx *sqlx.Tx, relations []SubjectTemplateGroup) error {
 	if len(relations) == 0 {
@@ -119,6 +142,7 @@ func (m *subjectTemplateGroupManager) BulkDeleteWithTx(tx *sqlx.Tx, relations []
 func (m *subjectTemplateGroupManager) GetMaxExpiredAtBySubjectGroup(
 	subjectPK, groupPK int64,
 	excludeTemplateID int64,
+	effectiveAt int64,
 ) (int64, error) {
 	var expiredAt sql.NullInt64
 	query := `SELECT
@@ -126,8 +150,9 @@ func (m *subjectTemplateGroupManager) GetMaxExpiredAtBySubjectGroup(
 		 FROM subject_template_group
 		 WHERE subject_pk = ?
 		 AND group_pk = ?
-		 AND template_id != ?`
-	err := database.SqlxGet(m.DB, &expiredAt, query, subjectPK, groupPK, excludeTemplateID)
+		 AND template_id != ?
+		 AND effective_at <= ?`
+	err := database.SqlxGet(m.DB, &expiredAt, query, subjectPK, groupPK, excludeTemplateID, effectiveAt)
 	if err != nil {
 		return 0, err
 	}
@@ -152,6 +177,7 @@ func (m *subjectTemplateGroupManager) ListPagingTemplateGroupMember(
 		 template_id,
 		 group_pk,
 		 expired_at,
+		 effective_at,
 		 created_at
 		 FROM subject_template_group
 		 WHERE group_pk = ?
@@ -189,6 +215,7 @@ func (m *subjectTemplateGroupManager) ListRelationBySubjectPKGroupPKs(
 		 template_id,
 		 group_pk,
 		 expired_at,
+		 effective_at,
 		 created_at
 		 FROM subject_template_group
 		 WHERE subject_pk = ?
@@ -216,7 +243,7 @@ func (m *subjectTemplateGroupManager) ListGroupDistinctSubjectPK(groupPK int64)
 }
 
 func (m *subjectTemplateGroupManager) ListThinRelationWithMaxExpiredAtByGroupPK(
-	groupPK int64,
+	groupPK, effectiveAt int64,
 ) ([]ThinSubjectRelation, error) {
 	relations := []ThinSubjectRelation{}
 
@@ -225,9 +252,10 @@ func (m *subjectTemplateGroupManager) ListThinRelationWithMaxExpiredAtByGroupPK(
 		 MAX(expired_at) AS policy_expired_at
 		 FROM subject_template_group
 		 WHERE group_pk = ?
+		 AND effective_at <= ?
 		 GROUP BY subject_pk`
 
-	err := database.SqlxSelect(m.DB, &relations, query, groupPK)
+	err := database.SqlxSelect(m.DB, &relations, query, groupPK, effectiveAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return relations, nil
 	}
@@ -248,3 +276,28 @@ func (m *subjectTemplateGroupManager) BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx,
 		 WHERE subject_pk in (?)`
 	return database.SqlxDeleteWithTx(tx, sql, subjectPKs)
 }
+
+// ListPendingRelationBeforeEffectiveAt 查询已到生效时间但尚未生效的关系
+func (m *subjectTemplateGroupManager) ListPendingRelationBeforeEffectiveAt(
+	effectiveAt int64,
+	limit int64,
+) (relations []SubjectTemplateGroup, err error) {
+	query := `SELECT
+		 pk,
+		 subject_pk,
+		 template_id,
+		 group_pk,
+		 expired_at,
+		 effective_at,
+		 created_at
+		 FROM subject_template_group
+		 WHERE effective_at > 0
+		 AND effective_at <= ?
+		 ORDER BY effective_at
+		 LIMIT ?`
+	err = database.SqlxSelect(m.DB, &relations, query, effectiveAt, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return relations, nil
+	}
+	return
+}
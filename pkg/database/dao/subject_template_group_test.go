@@ -24,7 +24,7 @@ func Test_subjectTemplateGroupManager_BulkCreateWithTx(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectExec(`^INSERT INTO subject_template_group`).WithArgs(
-			int64(2), int64(1), int64(1), int64(3),
+			int64(2), int64(1), int64(1), int64(3), int64(0),
 		).WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
@@ -94,10 +94,10 @@ func Test_subjectTemplateGroupManager_GetExpiredAtBySubjectGroup(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
 		mockQuery := `^SELECT (.*) FROM subject_template_group WHERE subject_pk =`
 		mockRows := sqlmock.NewRows([]string{"policy_expired_at"}).AddRow(int64(1))
-		mock.ExpectQuery(mockQuery).WithArgs(int64(1), int64(10), int64(0)).WillReturnRows(mockRows)
+		mock.ExpectQuery(mockQuery).WithArgs(int64(1), int64(10), int64(0), int64(100)).WillReturnRows(mockRows)
 
 		manager := &subjectTemplateGroupManager{DB: db}
-		expiredAt, err := manager.GetMaxExpiredAtBySubjectGroup(int64(1), int64(10), int64(0))
+		expiredAt, err := manager.GetMaxExpiredAtBySubjectGroup(int64(1), int64(10), int64(0), int64(100))
 
 		assert.NoError(t, err, "query from db fail.")
 		assert.Equal(t, expiredAt, int64(1))
@@ -106,7 +106,7 @@ func Test_subjectTemplateGroupManager_GetExpiredAtBySubjectGroup(t *testing.T) {
 
 func Test_subjectTemplateGroupManager_ListPagingTemplateGroupMember(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
-		mockQuery := `^SELECT pk, subject_pk, template_id, group_pk, expired_at, created_at FROM subject_template_group
+		mockQuery := `^SELECT pk, subject_pk, template_id, group_pk, expired_at, effective_at, created_at FROM subject_template_group
 		 WHERE group_pk = (.*) AND template_id = (.*) ORDER BY pk DESC LIMIT (.*) OFFSET (.*)`
 		mockRows := sqlmock.NewRows(
 			[]string{"pk", "subject_pk", "template_id", "group_pk", "expired_at"},
@@ -143,6 +143,7 @@ func Test_subjectTemplateGroupManager_ListRelationBySubjectPKGroupPKs(t *testing
 		template_id,
 		group_pk,
 		expired_at,
+		effective_at,
 		created_at
 		FROM subject_template_group
 		WHERE subject_pk = (.*)
@@ -200,10 +201,10 @@ func Test_subjectTemplateGroupManager_ListThinRelationWithMaxExpiredAtByGroupPK(
 			AddRow(int64(1), int64(1)).
 			AddRow(int64(2), int64(2))
 
-		mock.ExpectQuery(mockQuery).WithArgs(groupPK).WillReturnRows(rows)
+		mock.ExpectQuery(mockQuery).WithArgs(groupPK, int64(100)).WillReturnRows(rows)
 
 		manager := &subjectTemplateGroupManager{DB: db}
-		relations, err := manager.ListThinRelationWithMaxExpiredAtByGroupPK(groupPK)
+		relations, err := manager.ListThinRelationWithMaxExpiredAtByGroupPK(groupPK, int64(100))
 
 		assert.NoError(t, err, "query from db failed")
 		assert.Len(t, relations, 2, "did not get expected number of relations")
@@ -212,3 +213,48 @@ func Test_subjectTemplateGroupManager_ListThinRelationWithMaxExpiredAtByGroupPK(
 		}
 	})
 }
+
+func Test_subjectTemplateGroupManager_ListPendingRelationBeforeEffectiveAt(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, subject_pk, template_id, group_pk, expired_at, effective_at, created_at
+		 FROM subject_template_group WHERE effective_at > 0 AND effective_at <= (.*) ORDER BY effective_at LIMIT (.*)`
+		mockRows := sqlmock.NewRows(
+			[]string{"pk", "subject_pk", "template_id", "group_pk", "expired_at", "effective_at"},
+		).AddRow(int64(1), int64(2), int64(3), int64(4), int64(20), int64(5))
+		mock.ExpectQuery(mockQuery).WithArgs(int64(10), int64(100)).WillReturnRows(mockRows)
+
+		manager := &subjectTemplateGroupManager{DB: db}
+		relations, err := manager.ListPendingRelationBeforeEffectiveAt(10, 100)
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []SubjectTemplateGroup{{
+			PK: 1, SubjectPK: 2, TemplateID: 3, GroupPK: 4, ExpiredAt: 20, EffectiveAt: 5,
+		}}, relations)
+	})
+}
+
+func Test_subjectTemplateGroupManager_BulkUpdateEffectiveAtWithTx(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^UPDATE subject_template_group SET effective_at = (.*) WHERE subject_pk = (.*)
+		 AND group_pk = (.*) AND template_id = (.*)`
+		mock.ExpectBegin()
+		mock.ExpectPrepare(mockQuery)
+		mock.ExpectExec(mockQuery).
+			WithArgs(int64(0), int64(2), int64(1), int64(3)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+		manager := &subjectTemplateGroupManager{DB: db}
+		err = manager.BulkUpdateEffectiveAtWithTx(tx, []SubjectTemplateGroup{{
+			SubjectPK:  int64(2),
+			GroupPK:    int64(1),
+			TemplateID: int64(3),
+		}})
+
+		tx.Commit()
+
+		assert.NoError(t, err)
+	})
+}
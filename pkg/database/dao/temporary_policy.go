@@ -46,6 +46,7 @@ type TemporaryPolicyManager interface {
 	ListByPKs(pks []int64) ([]TemporaryPolicy, error)
 
 	// for saas
+	ListByActionPKsBeforeExpiredAt(actionPKs []int64, expiredAt int64) ([]TemporaryPolicy, error)
 	BulkCreateWithTx(tx *sqlx.Tx, policies []TemporaryPolicy) ([]int64, error)
 	BulkDeleteByPKs(subjectPK int64, pks []int64) (int64, error)
 	BulkDeleteBeforeExpiredAtWithTx(tx *sqlx.Tx, expiredAt, limit int64) (int64, error)
@@ -82,6 +83,31 @@ func (m *temporaryPolicyManager) ListByPKs(pks []int64) (policies []TemporaryPol
 	return
 }
 
+// ListByActionPKsBeforeExpiredAt 查询操作下未过期的临时权限
+func (m *temporaryPolicyManager) ListByActionPKsBeforeExpiredAt(
+	actionPKs []int64, expiredAt int64,
+) (policies []TemporaryPolicy, err error) {
+	if len(actionPKs) == 0 {
+		return
+	}
+
+	query := `SELECT
+		pk,
+		subject_pk,
+		action_pk,
+		expression,
+		expired_at
+		FROM temporary_policy
+		WHERE action_pk IN (?)
+		AND expired_at > ?
+		ORDER BY pk`
+	err = database.SqlxSelect(m.DB, &policies, query, actionPKs, expiredAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return policies, nil
+	}
+	return
+}
+
 // BulkCreateWithTx ...
 func (m *temporaryPolicyManager) BulkCreateWithTx(tx *sqlx.Tx, policies []TemporaryPolicy) ([]int64, error) {
 	if len(policies) == 0 {
@@ -58,6 +58,28 @@ func Test_temporaryPolicyManager_ListByPKs(t *testing.T) {
 	})
 }
 
+func Test_temporaryPolicyManager_ListByActionPKsBeforeExpiredAt(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT pk, subject_pk, action_pk, expression, expired_at FROM temporary_policy ` +
+			`WHERE action_pk IN (.*) AND expired_at > (.*) ORDER BY pk$`
+		mockRows := sqlmock.NewRows([]string{"pk", "subject_pk", "action_pk", "expression", "expired_at"}).AddRow(
+			1, 2, 3, "[]", 4)
+		mock.ExpectQuery(mockQuery).WithArgs(int64(3), int64(5), int64(0)).WillReturnRows(mockRows)
+
+		manager := &temporaryPolicyManager{DB: db}
+		ps, err := manager.ListByActionPKsBeforeExpiredAt([]int64{3, 5}, 0)
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, []TemporaryPolicy{{
+			PK:         1,
+			SubjectPK:  2,
+			ActionPK:   3,
+			Expression: "[]",
+			ExpiredAt:  4,
+		}}, ps)
+	})
+}
+
 func Test_temporaryPolicyManager_BulkCreateWithTx(t *testing.T) {
 	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
 		mock.ExpectBegin()
@@ -93,6 +93,36 @@ func (mr *MockSaaSActionManagerMockRecorder) GetAuthType(system, actionID interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthType", reflect.TypeOf((*MockSaaSActionManager)(nil).GetAuthType), system, actionID)
 }
 
+// GetRelatedEnvironments mocks base method.
+func (m *MockSaaSActionManager) GetRelatedEnvironments(system, actionID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelatedEnvironments", system, actionID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelatedEnvironments indicates an expected call of GetRelatedEnvironments.
+func (mr *MockSaaSActionManagerMockRecorder) GetRelatedEnvironments(system, actionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelatedEnvironments", reflect.TypeOf((*MockSaaSActionManager)(nil).GetRelatedEnvironments), system, actionID)
+}
+
+// GetRelatedSubjectAttributes mocks base method.
+func (m *MockSaaSActionManager) GetRelatedSubjectAttributes(system, actionID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelatedSubjectAttributes", system, actionID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelatedSubjectAttributes indicates an expected call of GetRelatedSubjectAttributes.
+func (mr *MockSaaSActionManagerMockRecorder) GetRelatedSubjectAttributes(system, actionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelatedSubjectAttributes", reflect.TypeOf((*MockSaaSActionManager)(nil).GetRelatedSubjectAttributes), system, actionID)
+}
+
 // ListBySystem mocks base method.
 func (m *MockSaaSActionManager) ListBySystem(system string) ([]sdao.SaaSAction, error) {
 	m.ctrl.T.Helper()
@@ -26,20 +26,21 @@ import (
 type SaaSAction struct {
 	database.AllowBlankFields
 
-	PK                  int64  `db:"pk"`
-	System              string `db:"system_id"`
-	ID                  string `db:"id"`
-	Name                string `db:"name"`
-	NameEn              string `db:"name_en"`
-	Description         string `db:"description"`
-	DescriptionEn       string `db:"description_en"`
-	Sensitivity         int64  `db:"sensitivity"`
-	RelatedActions      string `db:"related_actions"`
-	RelatedEnvironments string `db:"related_environments"`
-	AuthType            string `db:"auth_type"`
-	Type                string `db:"type"`
-	Hidden              bool   `db:"hidden"`
-	Version             int64  `db:"version"`
+	PK                       int64  `db:"pk"`
+	System                   string `db:"system_id"`
+	ID                       string `db:"id"`
+	Name                     string `db:"name"`
+	NameEn                   string `db:"name_en"`
+	Description              string `db:"description"`
+	DescriptionEn            string `db:"description_en"`
+	Sensitivity              int64  `db:"sensitivity"`
+	RelatedActions           string `db:"related_actions"`
+	RelatedEnvironments      string `db:"related_environments"`
+	RelatedSubjectAttributes string `db:"related_subject_attributes"`
+	AuthType                 string `db:"auth_type"`
+	Type                     string `db:"type"`
+	Hidden                   bool   `db:"hidden"`
+	Version                  int64  `db:"version"`
 }
 
 // SaaSActionManager ...
@@ -53,6 +54,8 @@ type SaaSActionManager interface {
 
 	// for auth
 	GetAuthType(system, actionID string) (autType string, err error)
+	GetRelatedEnvironments(system, actionID string) (relatedEnvironments string, err error)
+	GetRelatedSubjectAttributes(system, actionID string) (relatedSubjectAttributes string, err error)
 }
 
 type saasActionManager struct {
@@ -78,6 +81,20 @@ func (m *saasActionManager) GetAuthType(system, actionID string) (autType string
 	return
 }
 
+// GetRelatedEnvironments ...
+func (m *saasActionManager) GetRelatedEnvironments(system, actionID string) (relatedEnvironments string, err error) {
+	err = m.getRelatedEnvironmentsByActionID(&relatedEnvironments, system, actionID)
+	return
+}
+
+// GetRelatedSubjectAttributes ...
+func (m *saasActionManager) GetRelatedSubjectAttributes(
+	system, actionID string,
+) (relatedSubjectAttributes string, err error) {
+	err = m.getRelatedSubjectAttributesByActionID(&relatedSubjectAttributes, system, actionID)
+	return
+}
+
 // ListBySystem ...
 func (m *saasActionManager) ListBySystem(system string) (saasAction []SaaSAction, err error) {
 	err = m.selectBySystem(&saasAction, system)
@@ -136,12 +153,14 @@ func (m *saasActionManager) bulkInsertWithTx(tx *sqlx.Tx, saasActions []SaaSActi
 		sensitivity,
 		related_actions,
 		related_environments,
+		related_subject_attributes,
 		auth_type,
 		type,
 		hidden,
 		version
 	) VALUES (:system_id, :id, :name, :name_en, :description, :description_en, :sensitivity,
-			:related_actions, :related_environments, :auth_type, :type, :hidden, :version)`
+			:related_actions, :related_environments, :related_subject_attributes,
+			:auth_type, :type, :hidden, :version)`
 	return database.SqlxBulkInsertWithTx(tx, query, saasActions)
 }
 
@@ -170,6 +189,7 @@ func (m *saasActionManager) selectBySystem(saasAction *[]SaaSAction, system stri
 		sensitivity,
 		related_actions,
 		related_environments,
+		related_subject_attributes,
 		auth_type,
 		type,
 		hidden,
@@ -207,3 +227,29 @@ func (m *saasActionManager) getAuthTypeByActionID(authType *string, system, acti
 		LIMIT 1`
 	return database.SqlxGet(m.DB, authType, query, system, actionID)
 }
+
+func (m *saasActionManager) getRelatedEnvironmentsByActionID(
+	relatedEnvironments *string,
+	system, actionID string,
+) error {
+	query := `SELECT
+		related_environments
+		FROM saas_action
+		WHERE system_id = ?
+		AND id = ?
+		LIMIT 1`
+	return database.SqlxGet(m.DB, relatedEnvironments, query, system, actionID)
+}
+
+func (m *saasActionManager) getRelatedSubjectAttributesByActionID(
+	relatedSubjectAttributes *string,
+	system, actionID string,
+) error {
+	query := `SELECT
+		related_subject_attributes
+		FROM saas_action
+		WHERE system_id = ?
+		AND id = ?
+		LIMIT 1`
+	return database.SqlxGet(m.DB, relatedSubjectAttributes, query, system, actionID)
+}
@@ -34,3 +34,33 @@ func Test_actionManager_GetPK(t *testing.T) {
 		assert.Equal(t, authType, "abac")
 	})
 }
+
+func Test_actionManager_GetRelatedEnvironments(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT related_environments FROM saas_action WHERE system_id = (.*) AND id = (.*) LIMIT 1`
+		mockRows := sqlmock.NewRows([]string{"related_environments"}).
+			AddRow(`[{"type":"client_ip"}]`)
+		mock.ExpectQuery(mockQuery).WithArgs("iam", "edit").WillReturnRows(mockRows)
+
+		manager := &saasActionManager{DB: db}
+		relatedEnvironments, err := manager.GetRelatedEnvironments("iam", "edit")
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, `[{"type":"client_ip"}]`, relatedEnvironments)
+	})
+}
+
+func Test_actionManager_GetRelatedSubjectAttributes(t *testing.T) {
+	database.RunWithMock(t, func(db *sqlx.DB, mock sqlmock.Sqlmock, t *testing.T) {
+		mockQuery := `^SELECT related_subject_attributes FROM saas_action WHERE system_id = (.*) AND id = (.*) LIMIT 1`
+		mockRows := sqlmock.NewRows([]string{"related_subject_attributes"}).
+			AddRow(`[{"id":"level"}]`)
+		mock.ExpectQuery(mockQuery).WithArgs("iam", "edit").WillReturnRows(mockRows)
+
+		manager := &saasActionManager{DB: db}
+		relatedSubjectAttributes, err := manager.GetRelatedSubjectAttributes("iam", "edit")
+
+		assert.NoError(t, err, "query from db fail.")
+		assert.Equal(t, `[{"id":"level"}]`, relatedSubjectAttributes)
+	})
+}
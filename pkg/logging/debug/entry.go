@@ -50,6 +50,9 @@ const (
 	Pass    = "pass"
 	NoPass  = "no pass"
 	Unknown = "unknown"
+	Deny    = "deny"
+	// EvalError 策略计算出错, 例如表达式解析失败
+	EvalError = "error"
 )
 
 // WithUnknownEval ...
@@ -67,6 +70,16 @@ func (e *Entry) WithNoPassEval(policyID int64) {
 	e.Evals[policyID] = NoPass
 }
 
+// WithDenyEval ...
+func (e *Entry) WithDenyEval(policyID int64) {
+	e.Evals[policyID] = Deny
+}
+
+// WithErrorEval ...
+func (e *Entry) WithErrorEval(policyID int64) {
+	e.Evals[policyID] = EvalError
+}
+
 // WithError ...
 func (e *Entry) WithError(err error) {
 	if err != nil {
@@ -71,6 +71,17 @@ func TestEntry_WithUnknownEval(t *testing.T) {
 	assert.Equal(t, Unknown, entry.Evals[int64(1)])
 }
 
+func TestEntry_WithDenyEval(t *testing.T) {
+	pool := newEntryPool()
+	entry := pool.Get()
+
+	entry.WithDenyEval(1)
+
+	assert.Len(t, entry.Evals, 1)
+	assert.Contains(t, entry.Evals, int64(1))
+	assert.Equal(t, Deny, entry.Evals[int64(1)])
+}
+
 func TestEntry_WithValue(t *testing.T) {
 	pool := newEntryPool()
 
@@ -95,6 +95,26 @@ func WithNoPassEvalPolicy(e *Entry, policyID int64) {
 	e.WithNoPassEval(policyID)
 }
 
+// WithErrorEvalPolicyIDs 记录计算出错的策略
+func WithErrorEvalPolicyIDs(e *Entry, policyIDs []int64) {
+	if e == nil {
+		return
+	}
+
+	for _, pid := range policyIDs {
+		e.WithErrorEval(pid)
+	}
+}
+
+// WithDenyEvalPolicy ...
+func WithDenyEvalPolicy(e *Entry, policyID int64) {
+	if e == nil {
+		return
+	}
+
+	e.WithDenyEval(policyID)
+}
+
 // WithError ...
 func WithError(e *Entry, err error) {
 	if e == nil {
@@ -33,6 +33,8 @@ func TestNil(t *testing.T) {
 
 	WithPassEvalPolicy(e, 1)
 	WithNoPassEvalPolicy(e, 1)
+	WithDenyEvalPolicy(e, 1)
+	WithErrorEvalPolicyIDs(e, []int64{1})
 	WithError(e, nil)
 	AddStep(e, "hello")
 	AddSubDebug(e, nil)
@@ -64,13 +66,17 @@ func TestNotNil(t *testing.T) {
 	})
 	WithPassEvalPolicy(e, 4)
 	WithNoPassEvalPolicy(e, 5)
+	WithDenyEvalPolicy(e, 6)
+	WithErrorEvalPolicyIDs(e, []int64{7})
 
-	assert.Len(t, e.Evals, 5)
+	assert.Len(t, e.Evals, 7)
 	assert.Equal(t, e.Evals[int64(1)], Unknown)
 	assert.Equal(t, e.Evals[int64(2)], Pass)
 	assert.Equal(t, e.Evals[int64(3)], NoPass)
 	assert.Equal(t, e.Evals[int64(4)], Pass)
 	assert.Equal(t, e.Evals[int64(5)], NoPass)
+	assert.Equal(t, e.Evals[int64(6)], Deny)
+	assert.Equal(t, e.Evals[int64(7)], EvalError)
 
 	msg := "this is a error"
 	WithError(e, errors.New(msg))
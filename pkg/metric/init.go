@@ -79,6 +79,16 @@ var (
 		},
 		[]string{"process"},
 	)
+
+	// PolicyEvalErrorCount 策略计算出错(表达式解析失败/环境属性初始化失败等)的计数
+	PolicyEvalErrorCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "bkiam_policy_eval_error_total",
+			Help:        "How many policies failed to eval, partitioned by system and action.",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		},
+		[]string{"system", "action"},
+	)
 )
 
 // InitMetrics ...
@@ -90,4 +100,5 @@ func InitMetrics() {
 	prometheus.MustRegister(TaskStatsTotalCount)
 	prometheus.MustRegister(TaskStatsSuccessCount)
 	prometheus.MustRegister(TaskStatsFailCount)
+	prometheus.MustRegister(PolicyEvalErrorCount)
 }
@@ -22,6 +22,7 @@ types定义的数据结构的加载层
 import (
 	"database/sql"
 	"errors"
+	"strings"
 
 	"github.com/TencentBlueKing/gopkg/errorx"
 	jsoniter "github.com/json-iterator/go"
@@ -45,6 +46,11 @@ type ActionService interface {
 
 	GetAuthType(system, id string) (int64, error)
 
+	// ListRelatedEnvironmentTypes 获取action关联的环境属性类型
+	ListRelatedEnvironmentTypes(system, id string) ([]string, error)
+	// ListRelatedSubjectAttributes 获取action允许使用的subject属性
+	ListRelatedSubjectAttributes(system, id string) ([]string, error)
+
 	// ListBySystem 注意: 查 db 由于有填充resourceTypes/InstanceSelections, db 查询量非常大, 例如cmdb可能走近100次查询
 	// 建议应用层使用 cacheimpls.ListActionBySystem(systemID)
 	ListBySystem(system string) ([]types.Action, error)
@@ -237,6 +243,15 @@ func (l *actionService) ListBySystem(system string) ([]types.Action, error) {
 				return nil, errorWrapf(err, "unmarshal action.RelatedEnvironments=`%+v` fail", ac.RelatedEnvironments)
 			}
 		}
+		// NOTE: 0036 新增的列对存量数据为空字符串
+		if strings.TrimSpace(ac.RelatedSubjectAttributes) != "" {
+			err = jsoniter.UnmarshalFromString(ac.RelatedSubjectAttributes, &action.RelatedSubjectAttributes)
+			if err != nil {
+				return nil, errorWrapf(
+					err, "unmarshal action.RelatedSubjectAttributes=`%+v` fail", ac.RelatedSubjectAttributes,
+				)
+			}
+		}
 
 		relatedResourceTypes := []types.ActionResourceType{}
 		_, ok := actionResourceTypeMap[ac.ID]
@@ -357,6 +372,12 @@ func (l *actionService) BulkCreate(system string, actions []types.Action) error
 		if err2 != nil {
 			return errorWrapf(err1, "marshal action.RelatedEnvironments=`%+v` fail", ac.RelatedEnvironments)
 		}
+		relatedSubjectAttributes, err3 := jsoniter.MarshalToString(ac.RelatedSubjectAttributes)
+		if err3 != nil {
+			return errorWrapf(
+				err3, "marshal action.RelatedSubjectAttributes=`%+v` fail", ac.RelatedSubjectAttributes,
+			)
+		}
 
 		dbSaaSActions = append(dbSaaSActions, sdao.SaaSAction{
 			System:              system,
@@ -372,6 +393,8 @@ func (l *actionService) BulkCreate(system string, actions []types.Action) error
 			Type:                ac.Type,
 			Hidden:              ac.Hidden,
 			Version:             ac.Version,
+
+			RelatedSubjectAttributes: relatedSubjectAttributes,
 		})
 
 		singleDBActionResourceTypes, singleDBSaaSActionResourceTypes, err1 := l.convertToDBRelatedResourceTypes(
@@ -506,6 +529,18 @@ func (l *actionService) Update(system, actionID string, action types.Action) err
 			return errorWrapf(err, "unmarshal action.RelatedEnvironments=`%+v` fail", action.RelatedEnvironments)
 		}
 	}
+	var relatedSubjectAttributes string
+	if action.AllowEmptyFields.HasKey("RelatedSubjectAttributes") {
+		allowBlank.AddKey("RelatedSubjectAttributes")
+
+		var err1 error
+		relatedSubjectAttributes, err1 = jsoniter.MarshalToString(action.RelatedSubjectAttributes)
+		if err1 != nil {
+			return errorWrapf(
+				err1, "marshal action.RelatedSubjectAttributes=`%+v` fail", action.RelatedSubjectAttributes,
+			)
+		}
+	}
 
 	// 4. update saas action
 	data := sdao.SaaSAction{
@@ -521,6 +556,8 @@ func (l *actionService) Update(system, actionID string, action types.Action) err
 		RelatedActions:      relatedActions,
 		RelatedEnvironments: relatedEnvironments,
 
+		RelatedSubjectAttributes: relatedSubjectAttributes,
+
 		AllowBlankFields: allowBlank,
 	}
 
@@ -663,3 +700,55 @@ func (l *actionService) GetAuthType(system, id string) (int64, error) {
 
 	return 0, errors.New("unknown auth type")
 }
+
+// ListRelatedEnvironmentTypes 获取action关联的环境属性类型
+func (l *actionService) ListRelatedEnvironmentTypes(system, id string) ([]string, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(ActionSVC, "ListRelatedEnvironmentTypes")
+
+	relatedEnvironmentsStr, err := l.saasManager.GetRelatedEnvironments(system, id)
+	if err != nil {
+		return nil, errorWrapf(err, "saasManager.GetRelatedEnvironments system=`%s`, id=`%s` fail", system, id)
+	}
+
+	if relatedEnvironmentsStr == "" {
+		return []string{}, nil
+	}
+
+	var relatedEnvironments []types.ActionEnvironment
+	err = jsoniter.UnmarshalFromString(relatedEnvironmentsStr, &relatedEnvironments)
+	if err != nil {
+		return nil, errorWrapf(err, "unmarshal relatedEnvironments=`%s` fail", relatedEnvironmentsStr)
+	}
+
+	envTypes := make([]string, 0, len(relatedEnvironments))
+	for _, env := range relatedEnvironments {
+		envTypes = append(envTypes, env.Type)
+	}
+	return envTypes, nil
+}
+
+// ListRelatedSubjectAttributes 获取action允许使用的subject属性
+func (l *actionService) ListRelatedSubjectAttributes(system, id string) ([]string, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(ActionSVC, "ListRelatedSubjectAttributes")
+
+	relatedSubjectAttributesStr, err := l.saasManager.GetRelatedSubjectAttributes(system, id)
+	if err != nil {
+		return nil, errorWrapf(err, "saasManager.GetRelatedSubjectAttributes system=`%s`, id=`%s` fail", system, id)
+	}
+
+	if strings.TrimSpace(relatedSubjectAttributesStr) == "" {
+		return []string{}, nil
+	}
+
+	var relatedSubjectAttributes []types.ActionSubjectAttribute
+	err = jsoniter.UnmarshalFromString(relatedSubjectAttributesStr, &relatedSubjectAttributes)
+	if err != nil {
+		return nil, errorWrapf(err, "unmarshal relatedSubjectAttributes=`%s` fail", relatedSubjectAttributesStr)
+	}
+
+	attrs := make([]string, 0, len(relatedSubjectAttributes))
+	for _, attr := range relatedSubjectAttributes {
+		attrs = append(attrs, attr.ID)
+	}
+	return attrs, nil
+}
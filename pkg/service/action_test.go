@@ -178,4 +178,142 @@ var _ = Describe("ActionService", func() {
 			assert.Contains(GinkgoT(), err.Error(), "unknown")
 		})
 	})
+
+	Describe("ListRelatedEnvironmentTypes", func() {
+		var ctl *gomock.Controller
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("saasManager.GetRelatedEnvironments fail", func() {
+			mockSaaSActionManager := smock.NewMockSaaSActionManager(ctl)
+			mockSaaSActionManager.EXPECT().GetRelatedEnvironments("test", "action").Return(
+				"", errors.New("GetRelatedEnvironments"),
+			).AnyTimes()
+
+			manager := &actionService{
+				saasManager: mockSaaSActionManager,
+			}
+
+			_, err := manager.ListRelatedEnvironmentTypes("test", "action")
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "GetRelatedEnvironments")
+		})
+
+		It("empty ok", func() {
+			mockSaaSActionManager := smock.NewMockSaaSActionManager(ctl)
+			mockSaaSActionManager.EXPECT().GetRelatedEnvironments("test", "action").Return(
+				"", nil,
+			).AnyTimes()
+
+			manager := &actionService{
+				saasManager: mockSaaSActionManager,
+			}
+
+			envTypes, err := manager.ListRelatedEnvironmentTypes("test", "action")
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), envTypes)
+		})
+
+		It("ok", func() {
+			mockSaaSActionManager := smock.NewMockSaaSActionManager(ctl)
+			mockSaaSActionManager.EXPECT().GetRelatedEnvironments("test", "action").Return(
+				`[{"type":"period_daily"},{"type":"client_ip"}]`, nil,
+			).AnyTimes()
+
+			manager := &actionService{
+				saasManager: mockSaaSActionManager,
+			}
+
+			envTypes, err := manager.ListRelatedEnvironmentTypes("test", "action")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []string{"period_daily", "client_ip"}, envTypes)
+		})
+
+		It("unmarshal fail", func() {
+			mockSaaSActionManager := smock.NewMockSaaSActionManager(ctl)
+			mockSaaSActionManager.EXPECT().GetRelatedEnvironments("test", "action").Return(
+				`[{"type":`, nil,
+			).AnyTimes()
+
+			manager := &actionService{
+				saasManager: mockSaaSActionManager,
+			}
+
+			_, err := manager.ListRelatedEnvironmentTypes("test", "action")
+			assert.Error(GinkgoT(), err)
+		})
+	})
+	Describe("ListRelatedSubjectAttributes", func() {
+		var ctl *gomock.Controller
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("saasManager.GetRelatedSubjectAttributes fail", func() {
+			mockSaaSActionManager := smock.NewMockSaaSActionManager(ctl)
+			mockSaaSActionManager.EXPECT().GetRelatedSubjectAttributes("test", "action").Return(
+				"", errors.New("GetRelatedSubjectAttributes"),
+			).AnyTimes()
+
+			manager := &actionService{
+				saasManager: mockSaaSActionManager,
+			}
+
+			_, err := manager.ListRelatedSubjectAttributes("test", "action")
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "GetRelatedSubjectAttributes")
+		})
+
+		It("empty ok", func() {
+			mockSaaSActionManager := smock.NewMockSaaSActionManager(ctl)
+			mockSaaSActionManager.EXPECT().GetRelatedSubjectAttributes("test", "action").Return(
+				"", nil,
+			).AnyTimes()
+
+			manager := &actionService{
+				saasManager: mockSaaSActionManager,
+			}
+
+			attrs, err := manager.ListRelatedSubjectAttributes("test", "action")
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), attrs)
+		})
+
+		It("null ok", func() {
+			mockSaaSActionManager := smock.NewMockSaaSActionManager(ctl)
+			mockSaaSActionManager.EXPECT().GetRelatedSubjectAttributes("test", "action").Return(
+				"null", nil,
+			).AnyTimes()
+
+			manager := &actionService{
+				saasManager: mockSaaSActionManager,
+			}
+
+			attrs, err := manager.ListRelatedSubjectAttributes("test", "action")
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), attrs)
+		})
+
+		It("ok", func() {
+			mockSaaSActionManager := smock.NewMockSaaSActionManager(ctl)
+			mockSaaSActionManager.EXPECT().GetRelatedSubjectAttributes("test", "action").Return(
+				`[{"id":"level"},{"id":"employment_type","operators":["StringEquals"]}]`, nil,
+			).AnyTimes()
+
+			manager := &actionService{
+				saasManager: mockSaaSActionManager,
+			}
+
+			attrs, err := manager.ListRelatedSubjectAttributes("test", "action")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []string{"level", "employment_type"}, attrs)
+		})
+	})
 })
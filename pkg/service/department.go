@@ -28,6 +28,7 @@ const DepartmentSVC = "DepartmentSVC"
 type DepartmentService interface {
 	// 鉴权
 	GetSubjectDepartmentPKs(subjectPK int64) ([]int64, error) // cache subject detail
+	ListSubjectPKsByDepartmentPK(departmentPK int64) ([]int64, error)
 
 	// web api
 	GetCount() (int64, error)
@@ -38,16 +39,24 @@ type DepartmentService interface {
 
 	// for pap
 	BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, pks []int64) error
+
+	// department tree
+	ListAncestorDepartmentPKs(departmentPKs []int64) ([]int64, error)
+	ListDescendantDepartmentPKs(departmentPKs []int64) ([]int64, error)
+	BulkUpdateParents(departmentParents []types.DepartmentParent) error
+	BulkDeleteParents(departmentPKs []int64) error
 }
 
 type departmentService struct {
-	manager dao.SubjectDepartmentManager
+	manager     dao.SubjectDepartmentManager
+	treeManager dao.DepartmentTreeManager
 }
 
 // NewDepartmentService ...
 func NewDepartmentService() DepartmentService {
 	return &departmentService{
-		manager: dao.NewSubjectDepartmentManager(),
+		manager:     dao.NewSubjectDepartmentManager(),
+		treeManager: dao.NewDepartmentTreeManager(),
 	}
 }
 
@@ -66,6 +75,16 @@ func (l *departmentService) GetSubjectDepartmentPKs(subjectPK int64) ([]int64, e
 	return departmentPKs, nil
 }
 
+// ListSubjectPKsByDepartmentPK 查询部门下的subject
+func (l *departmentService) ListSubjectPKsByDepartmentPK(departmentPK int64) ([]int64, error) {
+	subjectPKs, err := l.manager.ListSubjectPKsByDepartmentPK(departmentPK)
+	if err != nil {
+		return nil, errorx.Wrapf(err, DepartmentSVC, "ListSubjectPKsByDepartmentPK",
+			"manager.ListSubjectPKsByDepartmentPK departmentPK=`%d` fail", departmentPK)
+	}
+	return subjectPKs, nil
+}
+
 // BulkCreate 批量创建用户部门关系
 func (l *departmentService) BulkCreate(subjectDepartments []types.SubjectDepartment) error {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(DepartmentSVC, "BulkCreate")
@@ -62,6 +62,46 @@ var _ = Describe("DepartmentService", func() {
 		})
 	})
 
+	Describe("ListSubjectPKsByDepartmentPK", func() {
+		var ctl *gomock.Controller
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("manager.ListSubjectPKsByDepartmentPK fail", func() {
+			mockManager := mock.NewMockSubjectDepartmentManager(ctl)
+			mockManager.EXPECT().ListSubjectPKsByDepartmentPK(int64(1)).Return(
+				nil, errors.New("error"),
+			).AnyTimes()
+
+			manager := &departmentService{
+				manager: mockManager,
+			}
+
+			_, err := manager.ListSubjectPKsByDepartmentPK(1)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListSubjectPKsByDepartmentPK")
+		})
+
+		It("success", func() {
+			mockManager := mock.NewMockSubjectDepartmentManager(ctl)
+			mockManager.EXPECT().ListSubjectPKsByDepartmentPK(int64(1)).Return(
+				[]int64{2, 3}, nil,
+			).AnyTimes()
+
+			manager := &departmentService{
+				manager: mockManager,
+			}
+
+			pks, err := manager.ListSubjectPKsByDepartmentPK(1)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []int64{2, 3}, pks)
+		})
+	})
+
 	Describe("BulkCreateSubjectDepartments", func() {
 		var ctl *gomock.Controller
 		BeforeEach(func() {
@@ -0,0 +1,246 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/database"
+	"iam/pkg/database/dao"
+	"iam/pkg/service/types"
+)
+
+// maxDepartmentTreeDepth 部门树最大遍历层数, 避免数据中存在环时无限遍历
+const maxDepartmentTreeDepth = 32
+
+var (
+	// ErrDepartmentTreeCycle 部门树成环
+	ErrDepartmentTreeCycle = errors.New("department tree cycle")
+	// ErrDepartmentTreeTooDeep 部门树超过最大层数
+	ErrDepartmentTreeTooDeep = errors.New("department tree too deep")
+)
+
+// listDepartmentParents 逐层查询departments及其所有上级部门的直接上级部门
+func listDepartmentParents(
+	treeManager dao.DepartmentTreeManager,
+	departmentPKs []int64,
+) (parents map[int64]int64, err error) {
+	parents = make(map[int64]int64)
+
+	queried := set.NewInt64Set()
+	current := departmentPKs
+	for depth := 0; depth <= maxDepartmentTreeDepth && len(current) > 0; depth++ {
+		queried.Append(current...)
+
+		departmentTrees, err := treeManager.ListByDepartmentPKs(current)
+		if err != nil {
+			return nil, err
+		}
+
+		next := set.NewInt64Set()
+		for _, t := range departmentTrees {
+			parents[t.DepartmentPK] = t.ParentPK
+			if !queried.Has(t.ParentPK) {
+				next.Add(t.ParentPK)
+			}
+		}
+		current = next.ToSlice()
+	}
+	return parents, nil
+}
+
+// walkDepartmentAncestors 沿着parents向上遍历, 返回departmentPK的所有上级部门(从近到远)
+func walkDepartmentAncestors(parents map[int64]int64, departmentPK int64) (ancestors []int64, err error) {
+	visited := set.NewInt64SetWithValues([]int64{departmentPK})
+	for parentPK, ok := parents[departmentPK]; ok; parentPK, ok = parents[parentPK] {
+		if visited.Has(parentPK) {
+			return ancestors, ErrDepartmentTreeCycle
+		}
+		if len(ancestors) >= maxDepartmentTreeDepth {
+			return ancestors, ErrDepartmentTreeTooDeep
+		}
+
+		visited.Add(parentPK)
+		ancestors = append(ancestors, parentPK)
+	}
+	return ancestors, nil
+}
+
+// listDepartmentAncestors 查询departments的所有上级部门(从近到远)
+// NOTE: 鉴权链路上不因为脏数据失败, 成环或超过最大层数时只返回已遍历到的上级部门
+func listDepartmentAncestors(
+	treeManager dao.DepartmentTreeManager,
+	departmentPKs []int64,
+) (map[int64][]int64, error) {
+	parents, err := listDepartmentParents(treeManager, departmentPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	departmentAncestors := make(map[int64][]int64, len(departmentPKs))
+	for _, pk := range departmentPKs {
+		ancestors, _ := walkDepartmentAncestors(parents, pk)
+		if len(ancestors) != 0 {
+			departmentAncestors[pk] = ancestors
+		}
+	}
+	return departmentAncestors, nil
+}
+
+// listDescendantDepartmentPKs 逐层查询departments的所有下级部门, 不包括departments本身
+func listDescendantDepartmentPKs(treeManager dao.DepartmentTreeManager, departmentPKs []int64) ([]int64, error) {
+	visited := set.NewInt64SetWithValues(departmentPKs)
+	descendants := set.NewInt64Set()
+
+	current := departmentPKs
+	for depth := 0; depth < maxDepartmentTreeDepth && len(current) > 0; depth++ {
+		departmentTrees, err := treeManager.ListByParentPKs(current)
+		if err != nil {
+			return nil, err
+		}
+
+		next := make([]int64, 0, len(departmentTrees))
+		for _, t := range departmentTrees {
+			if visited.Has(t.DepartmentPK) {
+				continue
+			}
+
+			visited.Add(t.DepartmentPK)
+			descendants.Add(t.DepartmentPK)
+			next = append(next, t.DepartmentPK)
+		}
+		current = next
+	}
+	return descendants.ToSlice(), nil
+}
+
+// ListAncestorDepartmentPKs 查询departments的所有上级部门, 不包括departments本身
+func (l *departmentService) ListAncestorDepartmentPKs(departmentPKs []int64) ([]int64, error) {
+	departmentAncestors, err := listDepartmentAncestors(l.treeManager, departmentPKs)
+	if err != nil {
+		return nil, errorx.Wrapf(err, DepartmentSVC, "ListAncestorDepartmentPKs", "departmentPKs=`%+v`", departmentPKs)
+	}
+
+	// 保持从近到远的顺序
+	pkSet := set.NewInt64SetWithValues(departmentPKs)
+	ancestorPKs := make([]int64, 0, len(departmentAncestors))
+	for _, pk := range departmentPKs {
+		for _, ancestorPK := range departmentAncestors[pk] {
+			if !pkSet.Has(ancestorPK) {
+				pkSet.Add(ancestorPK)
+				ancestorPKs = append(ancestorPKs, ancestorPK)
+			}
+		}
+	}
+	return ancestorPKs, nil
+}
+
+// ListDescendantDepartmentPKs 查询departments的所有下级部门, 不包括departments本身
+func (l *departmentService) ListDescendantDepartmentPKs(departmentPKs []int64) ([]int64, error) {
+	descendants, err := listDescendantDepartmentPKs(l.treeManager, departmentPKs)
+	if err != nil {
+		return nil, errorx.Wrapf(err, DepartmentSVC, "ListDescendantDepartmentPKs", "departmentPKs=`%+v`", departmentPKs)
+	}
+	return descendants, nil
+}
+
+// BulkUpdateParents 批量设置部门的上级部门, 上级部门不能是部门本身或其下级部门
+func (l *departmentService) BulkUpdateParents(departmentParents []types.DepartmentParent) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(DepartmentSVC, "BulkUpdateParents")
+	if len(departmentParents) == 0 {
+		return nil
+	}
+
+	departmentPKs := make([]int64, 0, len(departmentParents))
+	parentPKs := make([]int64, 0, len(departmentParents))
+	departmentTrees := make([]dao.DepartmentTree, 0, len(departmentParents))
+	for _, dp := range departmentParents {
+		departmentPKs = append(departmentPKs, dp.DepartmentPK)
+		// ParentPK为0表示根部门, 只删除已有的上级部门
+		if dp.ParentPK == 0 {
+			continue
+		}
+
+		parentPKs = append(parentPKs, dp.ParentPK)
+		departmentTrees = append(departmentTrees, dao.DepartmentTree{
+			DepartmentPK: dp.DepartmentPK,
+			ParentPK:     dp.ParentPK,
+		})
+	}
+
+	// 使用变更后的部门树检查是否成环
+	parents, err := listDepartmentParents(l.treeManager, parentPKs)
+	if err != nil {
+		return errorWrapf(err, "listDepartmentParents parentPKs=`%+v` fail", parentPKs)
+	}
+	for _, dp := range departmentParents {
+		delete(parents, dp.DepartmentPK)
+	}
+	for _, t := range departmentTrees {
+		parents[t.DepartmentPK] = t.ParentPK
+	}
+	for _, t := range departmentTrees {
+		_, err = walkDepartmentAncestors(parents, t.DepartmentPK)
+		if err != nil {
+			return errorWrapf(err, "departmentPK=`%d`, parentPK=`%d`", t.DepartmentPK, t.ParentPK)
+		}
+	}
+
+	tx, err := database.GenerateDefaultDBTx()
+	defer database.RollBackWithLog(tx)
+	if err != nil {
+		return errorWrapf(err, "define tx error")
+	}
+
+	err = l.treeManager.BulkDeleteByDepartmentPKsWithTx(tx, departmentPKs)
+	if err != nil {
+		return errorWrapf(err, "treeManager.BulkDeleteByDepartmentPKsWithTx departmentPKs=`%+v` fail", departmentPKs)
+	}
+
+	err = l.treeManager.BulkCreateWithTx(tx, departmentTrees)
+	if err != nil {
+		return errorWrapf(err, "treeManager.BulkCreateWithTx departmentTrees=`%+v` fail", departmentTrees)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errorWrapf(err, "tx commit error")
+	}
+	return nil
+}
+
+// BulkDeleteParents 批量删除部门的上级部门, 部门变为根部门
+func (l *departmentService) BulkDeleteParents(departmentPKs []int64) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(DepartmentSVC, "BulkDeleteParents")
+	if len(departmentPKs) == 0 {
+		return nil
+	}
+
+	tx, err := database.GenerateDefaultDBTx()
+	defer database.RollBackWithLog(tx)
+	if err != nil {
+		return errorWrapf(err, "define tx error")
+	}
+
+	err = l.treeManager.BulkDeleteByDepartmentPKsWithTx(tx, departmentPKs)
+	if err != nil {
+		return errorWrapf(err, "treeManager.BulkDeleteByDepartmentPKsWithTx departmentPKs=`%+v` fail", departmentPKs)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errorWrapf(err, "tx commit error")
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database"
+	"iam/pkg/database/dao"
+	"iam/pkg/database/dao/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("DepartmentTree", func() {
+	var ctl *gomock.Controller
+	var mockTreeManager *mock.MockDepartmentTreeManager
+	var svc *departmentService
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockTreeManager = mock.NewMockDepartmentTreeManager(ctl)
+		svc = &departmentService{treeManager: mockTreeManager}
+	})
+	AfterEach(func() {
+		ctl.Finish()
+	})
+
+	Describe("ListAncestorDepartmentPKs", func() {
+		It("ok", func() {
+			// 1 -> 2 -> 3
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{1}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 1, ParentPK: 2}}, nil,
+			)
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{2}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 2, ParentPK: 3}}, nil,
+			)
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{3}).Return(nil, nil)
+
+			pks, err := svc.ListAncestorDepartmentPKs([]int64{1})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []int64{2, 3}, pks)
+		})
+
+		It("cycle", func() {
+			// 1 -> 2 -> 1
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{1}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 1, ParentPK: 2}}, nil,
+			)
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{2}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 2, ParentPK: 1}}, nil,
+			)
+
+			pks, err := svc.ListAncestorDepartmentPKs([]int64{1})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []int64{2}, pks)
+		})
+
+		It("fail", func() {
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{1}).Return(nil, errors.New("error"))
+
+			_, err := svc.ListAncestorDepartmentPKs([]int64{1})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListAncestorDepartmentPKs")
+		})
+	})
+
+	Describe("ListDescendantDepartmentPKs", func() {
+		It("ok", func() {
+			// 1 -> 2, 1 -> 3, 2 -> 4
+			mockTreeManager.EXPECT().ListByParentPKs([]int64{1}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 2, ParentPK: 1}, {DepartmentPK: 3, ParentPK: 1}}, nil,
+			)
+			mockTreeManager.EXPECT().ListByParentPKs([]int64{2, 3}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 4, ParentPK: 2}}, nil,
+			)
+			mockTreeManager.EXPECT().ListByParentPKs([]int64{4}).Return(nil, nil)
+
+			pks, err := svc.ListDescendantDepartmentPKs([]int64{1})
+			assert.NoError(GinkgoT(), err)
+			assert.ElementsMatch(GinkgoT(), []int64{2, 3, 4}, pks)
+		})
+	})
+
+	Describe("BulkUpdateParents", func() {
+		var patches *gomonkey.Patches
+
+		BeforeEach(func() {
+			tx := &sql.Tx{}
+			patches = gomonkey.ApplyMethod(reflect.TypeOf(tx), "Commit", func(tx *sql.Tx) error {
+				return nil
+			})
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return &sqlx.Tx{Tx: tx}, nil
+			})
+			patches.ApplyFunc(database.RollBackWithLog, func(tx *sqlx.Tx) {})
+		})
+		AfterEach(func() {
+			patches.Reset()
+		})
+
+		It("self cycle", func() {
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{1}).Return(nil, nil)
+
+			err := svc.BulkUpdateParents([]types.DepartmentParent{{DepartmentPK: 1, ParentPK: 1}})
+			assert.ErrorIs(GinkgoT(), err, ErrDepartmentTreeCycle)
+		})
+
+		It("descendant cycle", func() {
+			// 3 -> 2 -> 1, 1 的上级部门设置为 3 后成环
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{3}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 3, ParentPK: 2}}, nil,
+			)
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{2}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 2, ParentPK: 1}}, nil,
+			)
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{1}).Return(nil, nil)
+
+			err := svc.BulkUpdateParents([]types.DepartmentParent{{DepartmentPK: 1, ParentPK: 3}})
+			assert.ErrorIs(GinkgoT(), err, ErrDepartmentTreeCycle)
+		})
+
+		It("ok", func() {
+			mockTreeManager.EXPECT().ListByDepartmentPKs([]int64{3}).Return(nil, nil)
+			mockTreeManager.EXPECT().BulkDeleteByDepartmentPKsWithTx(gomock.Any(), []int64{1, 2}).Return(nil)
+			mockTreeManager.EXPECT().BulkCreateWithTx(
+				gomock.Any(), []dao.DepartmentTree{{DepartmentPK: 1, ParentPK: 3}},
+			).Return(nil)
+
+			err := svc.BulkUpdateParents([]types.DepartmentParent{
+				{DepartmentPK: 1, ParentPK: 3},
+				{DepartmentPK: 2, ParentPK: 0},
+			})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+})
@@ -90,7 +90,9 @@ func convertToEngineAbacPolicies(policies []dao.EngineAbacPolicy) (abacPolicies
 			ActionPK:     p.ActionPK,
 			ExpressionPK: p.ExpressionPK,
 			ExpiredAt:    p.ExpiredAt,
+			EffectiveAt:  p.EffectiveAt,
 			TemplateID:   p.TemplateID,
+			Effect:       p.Effect,
 			UpdatedAt:    p.UpdatedAt,
 		})
 	}
@@ -94,6 +94,11 @@ func convertToEngineRbacPolicies(policies []dao.EngineRbacPolicy) (rbacPolicies
 			)
 		}
 
+		relatedResources, err := unmarshalRelatedResources(p.RelatedResources)
+		if err != nil {
+			return nil, err
+		}
+
 		rbacPolicies = append(rbacPolicies, types.EngineRbacPolicy{
 			PK:                          p.PK,
 			GroupPK:                     p.GroupPK,
@@ -103,6 +108,7 @@ func convertToEngineRbacPolicies(policies []dao.EngineRbacPolicy) (rbacPolicies
 			ActionRelatedResourceTypePK: p.ActionRelatedResourceTypePK,
 			ResourceTypePK:              p.ResourceTypePK,
 			ResourceID:                  p.ResourceID,
+			RelatedResources:            relatedResources,
 			UpdatedAt:                   p.UpdatedAt,
 		})
 	}
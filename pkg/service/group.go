@@ -24,7 +24,6 @@ import (
 	"github.com/TencentBlueKing/gopkg/errorx"
 	"github.com/jmoiron/sqlx"
 
-	"iam/pkg/database"
 	"iam/pkg/database/dao"
 	"iam/pkg/service/types"
 )
@@ -76,7 +75,9 @@ type GroupService interface {
 
 	UpdateGroupMembersExpiredAtWithTx(tx *sqlx.Tx, groupPK int64, members []types.SubjectTemplateGroup) error
 	UpdateSubjectTemplateGroupExpiredAtWithTx(tx *sqlx.Tx, relations []types.SubjectTemplateGroup) error
-	BulkDeleteGroupMembers(groupPK int64, userPKs, departmentPKs []int64) (map[string]int64, error)
+	BulkDeleteGroupMembersWithTx(
+		tx *sqlx.Tx, groupPK int64, userPKs, departmentPKs, memberGroupPKs []int64,
+	) (map[string]int64, error)
 	BulkCreateGroupMembersWithTx(tx *sqlx.Tx, groupPK int64, relations []types.SubjectTemplateGroup) error
 	BulkCreateSubjectTemplateGroupWithTx(tx *sqlx.Tx, relations []types.SubjectTemplateGroup) error
 	UpdateSubjectGroupExpiredAtWithTx(
@@ -96,11 +97,27 @@ type GroupService interface {
 	ListGroupAuthBySystemGroupPKs(systemID string, groupPKs []int64) ([]types.GroupAuthType, error)
 	AlterGroupAuthType(tx *sqlx.Tx, systemID string, groupPK int64, authType int64) (changed bool, err error)
 
+	// nested group
+	CheckNestedGroupMembers(groupPK int64, memberGroupPKs []int64) error
+	ListAncestorGroupPKs(groupPK int64) ([]int64, error)
+	ListNestedGroupMemberSubjectPKs(groupPKs []int64) ([]int64, error)
+	GetNestedExpiredAtBySubjectGroup(subjectPK, groupPK int64) (int64, error)
+	ListEffectGroupPKs(subjectPKs []int64) ([]int64, error)
+
+	// sub department
+	ListSubDepartmentMemberPKs(groupPK int64, departmentPKs []int64) ([]int64, error)
+	ListSubDepartmentGroupPKs(departmentPKs []int64) ([]int64, error)
+	UpdateMembersIncludeSubDepartment(groupPK int64, departmentPKs []int64, includeSubDepartment bool) error
+
 	// open api
 	ListEffectThinSubjectGroupsBySubjectPKs(subjectPKs []int64) ([]types.ThinSubjectGroup, error)
 
 	// task
 	GetMaxExpiredAtBySubjectGroup(subjectPK, groupPK int64, excludeTemplateID int64) (int64, error)
+	ListPendingSubjectGroupsBeforeEffectiveAt(effectiveAt, limit int64) ([]types.SubjectTemplateGroup, error)
+	ActivateSubjectGroups(relations []types.SubjectTemplateGroup) error
+
+	GetSubjectTemplateGroupEffectiveAt(subjectPK, groupPK, templateID int64) (int64, error)
 }
 
 type groupService struct {
@@ -108,6 +125,7 @@ type groupService struct {
 	authTypeManger              dao.GroupSystemAuthTypeManager
 	subjectSystemGroupManager   dao.SubjectSystemGroupManager
 	subjectTemplateGroupManager dao.SubjectTemplateGroupManager
+	departmentTreeManager       dao.DepartmentTreeManager
 }
 
 // NewGroupService GroupService工厂
@@ -117,6 +135,7 @@ func NewGroupService() GroupService {
 		authTypeManger:              dao.NewGroupSystemAuthTypeManager(),
 		subjectSystemGroupManager:   dao.NewSubjectSystemGroupManager(),
 		subjectTemplateGroupManager: dao.NewSubjectTemplateGroupManager(),
+		departmentTreeManager:       dao.NewDepartmentTreeManager(),
 	}
 }
 
@@ -147,7 +166,7 @@ func (l *groupService) ListEffectThinSubjectGroupsBySubjectPKs(
 	// 过期时间必须大于当前时间
 	now := time.Now().Unix()
 
-	relations, err := l.manager.ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs, now)
+	relations, err := l.manager.ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs, now, now)
 	if err != nil {
 		return subjectGroups, errorWrapf(
 			err,
@@ -346,9 +365,15 @@ func (l *groupService) ListEffectSubjectGroupsBySubjectPKGroupPKs(
 	}
 
 	groupPKset := set.NewInt64Set()
+	now := time.Now().Unix()
 
 	subjectGroups = make([]types.SubjectGroupWithSource, 0, len(relations)+len(templateRelations))
 	for _, r := range relations {
+		// 未到生效时间的关系不生效
+		if r.EffectiveAt > now {
+			continue
+		}
+
 		subjectGroups = append(subjectGroups, types.SubjectGroupWithSource{
 			PK:            r.PK,
 			GroupPK:       r.GroupPK,
@@ -361,7 +386,7 @@ func (l *groupService) ListEffectSubjectGroupsBySubjectPKGroupPKs(
 	}
 
 	for _, r := range templateRelations {
-		if groupPKset.Has(r.GroupPK) {
+		if r.EffectiveAt > now || groupPKset.Has(r.GroupPK) {
 			continue
 		}
 
@@ -383,10 +408,11 @@ func convertToGroupMembers(daoRelations []dao.SubjectRelation) []types.GroupMemb
 	relations := make([]types.GroupMember, 0, len(daoRelations))
 	for _, r := range daoRelations {
 		relations = append(relations, types.GroupMember{
-			PK:        r.PK,
-			SubjectPK: r.SubjectPK,
-			ExpiredAt: r.ExpiredAt,
-			CreatedAt: r.CreatedAt,
+			PK:          r.PK,
+			SubjectPK:   r.SubjectPK,
+			ExpiredAt:   r.ExpiredAt,
+			EffectiveAt: r.EffectiveAt,
+			CreatedAt:   r.CreatedAt,
 		})
 	}
 	return relations
@@ -439,10 +465,11 @@ func (l *groupService) ListPagingTemplateGroupMember(
 	members := make([]types.GroupMember, 0, len(templateMembers))
 	for _, m := range templateMembers {
 		members = append(members, types.GroupMember{
-			PK:        m.PK,
-			SubjectPK: m.SubjectPK,
-			ExpiredAt: m.ExpiredAt,
-			CreatedAt: m.CreatedAt,
+			PK:          m.PK,
+			SubjectPK:   m.SubjectPK,
+			ExpiredAt:   m.ExpiredAt,
+			EffectiveAt: m.EffectiveAt,
+			CreatedAt:   m.CreatedAt,
 		})
 	}
 	return members, nil
@@ -511,27 +538,22 @@ func (l *groupService) UpdateGroupMembersExpiredAtWithTx(
 	return nil
 }
 
-// BulkDeleteGroupMembers ...
-func (l *groupService) BulkDeleteGroupMembers(
+// BulkDeleteGroupMembersWithTx ...
+func (l *groupService) BulkDeleteGroupMembersWithTx(
+	tx *sqlx.Tx,
 	groupPK int64,
-	userPKs, departmentPKs []int64,
+	userPKs, departmentPKs, memberGroupPKs []int64,
 ) (map[string]int64, error) {
-	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupSVC, "BulkDeleteGroupMember")
-
-	// 使用事务
-	tx, err := database.GenerateDefaultDBTx()
-	defer database.RollBackWithLog(tx)
-
-	if err != nil {
-		return nil, errorWrapf(err, "define tx error")
-	}
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupSVC, "BulkDeleteGroupMembersWithTx")
 
 	typeCount := map[string]int64{
 		types.UserType:       0,
 		types.DepartmentType: 0,
+		types.GroupType:      0,
 	}
 
 	var count int64
+	var err error
 	if len(userPKs) != 0 {
 		count, err = l.manager.BulkDeleteByGroupMembersWithTx(tx, groupPK, userPKs)
 		if err != nil {
@@ -552,21 +574,34 @@ func (l *groupService) BulkDeleteGroupMembers(
 		typeCount[types.DepartmentType] = count
 	}
 
+	if len(memberGroupPKs) != 0 {
+		count, err = l.manager.BulkDeleteByGroupMembersWithTx(tx, groupPK, memberGroupPKs)
+		if err != nil {
+			return nil, errorWrapf(
+				err, "manager.BulkDeleteByGroupMembersWithTx groupPK=`%d`, memberGroupPKs=`%+v` fail",
+				groupPK, memberGroupPKs)
+		}
+		typeCount[types.GroupType] = count
+	}
+
 	// 更新subject system group
 	systemIDs, err := l.ListGroupAuthSystemIDs(groupPK)
 	if err != nil {
 		return nil, errorWrapf(err, "listGroupAuthSystem groupPK=`%d` fail", groupPK)
 	}
 
-	subjectPKs := make([]int64, 0, len(userPKs)+len(departmentPKs))
+	subjectPKs := make([]int64, 0, len(userPKs)+len(departmentPKs)+len(memberGroupPKs))
 	subjectPKs = append(subjectPKs, userPKs...)
 	subjectPKs = append(subjectPKs, departmentPKs...)
+	subjectPKs = append(subjectPKs, memberGroupPKs...)
 
 	now := time.Now().Unix()
 	// 需要判断还有没有其他的数据再来删除
 	for _, subjectPK := range subjectPKs {
 		// 如果还有其它的未过期的, 不需要删除
-		expiredAt, err := l.subjectTemplateGroupManager.GetMaxExpiredAtBySubjectGroup(subjectPK, groupPK, 0)
+		expiredAt, err := l.subjectTemplateGroupManager.GetMaxExpiredAtBySubjectGroup(
+			subjectPK, groupPK, 0, now,
+		)
 		if err != nil && err != ErrGroupMemberNotFound {
 			return nil, errorWrapf(
 				err,
@@ -594,11 +629,7 @@ func (l *groupService) BulkDeleteGroupMembers(
 		}
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		return nil, errorWrapf(err, "tx commit error")
-	}
-	return typeCount, err
+	return typeCount, nil
 }
 
 // BulkCreateGroupMembersWithTx ...
@@ -612,9 +643,10 @@ func (l *groupService) BulkCreateGroupMembersWithTx(
 	daoRelations := make([]dao.SubjectRelation, 0, len(relations))
 	for _, r := range relations {
 		daoRelations = append(daoRelations, dao.SubjectRelation{
-			SubjectPK: r.SubjectPK,
-			GroupPK:   r.GroupPK,
-			ExpiredAt: r.ExpiredAt,
+			SubjectPK:   r.SubjectPK,
+			GroupPK:     r.GroupPK,
+			ExpiredAt:   r.ExpiredAt,
+			EffectiveAt: r.EffectiveAt,
 		})
 	}
 
@@ -857,10 +889,11 @@ func convertToDaoSubjectTemplateGroup(relations []types.SubjectTemplateGroup) []
 	daoRelations := make([]dao.SubjectTemplateGroup, 0, len(relations))
 	for _, r := range relations {
 		daoRelations = append(daoRelations, dao.SubjectTemplateGroup{
-			SubjectPK:  r.SubjectPK,
-			TemplateID: r.TemplateID,
-			GroupPK:    r.GroupPK,
-			ExpiredAt:  r.ExpiredAt,
+			SubjectPK:   r.SubjectPK,
+			TemplateID:  r.TemplateID,
+			GroupPK:     r.GroupPK,
+			ExpiredAt:   r.ExpiredAt,
+			EffectiveAt: r.EffectiveAt,
 		})
 	}
 	return daoRelations
@@ -962,7 +995,8 @@ func (l *groupService) BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []in
 // GetMaxExpiredAtBySubjectGroup ...
 func (l *groupService) GetMaxExpiredAtBySubjectGroup(subjectPK, groupPK int64, excludeTemplateID int64) (int64, error) {
 	// 同时查询 subject relation 与 subject template group, 取最大的过期时间
-	subjectRelationExpiredAt, err1 := l.manager.GetExpiredAtBySubjectGroup(subjectPK, groupPK)
+	now := time.Now().Unix()
+	subjectRelationExpiredAt, err1 := l.manager.GetExpiredAtBySubjectGroup(subjectPK, groupPK, now)
 	if err1 != nil && !errors.Is(err1, sql.ErrNoRows) {
 		err1 = errorx.Wrapf(
 			err1, GroupSVC, "manager.GetExpiredAtBySubjectGroup", "subjectPK=`%d`, groupPK=`%d`", subjectPK, groupPK,
@@ -974,6 +1008,7 @@ func (l *groupService) GetMaxExpiredAtBySubjectGroup(subjectPK, groupPK int64, e
 		subjectPK,
 		groupPK,
 		excludeTemplateID,
+		now,
 	)
 	if err2 != nil && !errors.Is(err2, sql.ErrNoRows) {
 		err2 = errorx.Wrapf(
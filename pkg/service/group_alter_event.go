@@ -13,6 +13,8 @@ package service
 //go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
 
 import (
+	"time"
+
 	"github.com/TencentBlueKing/gopkg/collection/set"
 	"github.com/TencentBlueKing/gopkg/errorx"
 	"github.com/jmoiron/sqlx"
@@ -30,6 +32,9 @@ const GroupAlterEventSVC = "GroupAlterEventSVC"
 type GroupAlterEventService interface {
 	ListBeforeCreateAt(createdAt int64, limit int64) ([]types.GroupAlterEvent, error)
 
+	ListAffectedSubjectPKs(groupPK int64) ([]int64, error)
+	ListGroupActionPKs(groupPK int64) ([]int64, error)
+
 	CreateByGroupAction(groupPK int64, actionPKs []int64) error
 	CreateByGroupSubject(groupPK int64, subjectPKs []int64) error
 	CreateBySubjectActionGroup(subjectPK, actionPK, groupPK int64) error
@@ -42,6 +47,7 @@ type groupAlterEventService struct {
 	subjectGroupManager         dao.SubjectGroupManager
 	subjectTemplateGroupManager dao.SubjectTemplateGroupManager
 	groupResourcePolicyManager  dao.GroupResourcePolicyManager
+	departmentTreeManager       dao.DepartmentTreeManager
 }
 
 // NewGroupAlterEventService ...
@@ -51,6 +57,7 @@ func NewGroupAlterEventService() GroupAlterEventService {
 		subjectGroupManager:         dao.NewSubjectGroupManager(),
 		subjectTemplateGroupManager: dao.NewSubjectTemplateGroupManager(),
 		groupResourcePolicyManager:  dao.NewGroupResourcePolicyManager(),
+		departmentTreeManager:       dao.NewDepartmentTreeManager(),
 	}
 }
 
@@ -108,6 +115,29 @@ func (s *groupAlterEventService) CreateByGroupAction(
 		return nil
 	}
 
+	subjectPKs, err := s.ListAffectedSubjectPKs(groupPK)
+	if err != nil {
+		err = errorWrapf(err, "ListAffectedSubjectPKs groupPK=`%d` fail", groupPK)
+		return
+	}
+
+	if len(subjectPKs) == 0 {
+		return nil
+	}
+
+	err = s.create(groupPK, actionPKs, subjectPKs)
+	if err != nil {
+		err = errorWrapf(err, "create fail groupPK=`%d` actionPKs=`%+v` subjectPKs=`%+v`", actionPKs, subjectPKs)
+		return
+	}
+
+	return nil
+}
+
+// ListAffectedSubjectPKs 查询用户组权限变更时受影响的subject, 包括模板成员, 嵌套用户组成员与子部门
+func (s *groupAlterEventService) ListAffectedSubjectPKs(groupPK int64) (subjectPKs []int64, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupAlterEventSVC, "ListAffectedSubjectPKs")
+
 	subjectRelations, err := s.subjectGroupManager.ListGroupMember(groupPK)
 	if err != nil {
 		err = errorWrapf(err, "subjectGroupManager.ListGroupMember groupPK=`%d` fail", groupPK)
@@ -115,29 +145,53 @@ func (s *groupAlterEventService) CreateByGroupAction(
 	}
 
 	if len(subjectRelations) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// 查询 subject template group
-	subjectPKs, err := s.subjectTemplateGroupManager.ListGroupDistinctSubjectPK(groupPK)
+	templateSubjectPKs, err := s.subjectTemplateGroupManager.ListGroupDistinctSubjectPK(groupPK)
 	if err != nil {
 		err = errorWrapf(err, "subjectTemplateGroupManager.ListGroupDistinctSubjectPK groupPK=`%d` fail", groupPK)
 		return
 	}
 
-	subjectPKset := set.NewInt64SetWithValues(subjectPKs)
+	subjectPKset := set.NewInt64SetWithValues(templateSubjectPKs)
 	for _, r := range subjectRelations {
 		subjectPKset.Add(r.SubjectPK)
 	}
 
-	subjectPKs = subjectPKset.ToSlice()
-	err = s.create(groupPK, actionPKs, subjectPKs)
+	// 嵌套的下级用户组的成员同样继承用户组的权限, 只展开已生效且未过期的下级用户组
+	memberGroupPKs, err := listEffectiveMemberGroupPKs(s.subjectGroupManager, []int64{groupPK}, time.Now().Unix())
 	if err != nil {
-		err = errorWrapf(err, "create fail groupPK=`%d` actionPKs=`%+v` subjectPKs=`%+v`", actionPKs, subjectPKs)
+		err = errorWrapf(err, "listEffectiveMemberGroupPKs groupPK=`%d` fail", groupPK)
 		return
 	}
 
-	return nil
+	if len(memberGroupPKs) != 0 {
+		nestedSubjectPKs, err := listNestedGroupMemberSubjectPKs(
+			s.subjectGroupManager, s.departmentTreeManager, memberGroupPKs,
+		)
+		if err != nil {
+			return nil, errorWrapf(err, "listNestedGroupMemberSubjectPKs groupPKs=`%+v` fail", memberGroupPKs)
+		}
+
+		subjectPKset.Append(nestedSubjectPKs...)
+		for _, pk := range memberGroupPKs {
+			delete(subjectPKset.Data, pk)
+		}
+	}
+
+	// 包含子部门的部门成员, 其下级部门同样继承用户组的权限
+	subDepartmentPKs, err := listSubDepartmentMemberPKs(
+		s.subjectGroupManager, s.departmentTreeManager, []int64{groupPK}, nil,
+	)
+	if err != nil {
+		err = errorWrapf(err, "listSubDepartmentMemberPKs groupPK=`%d` fail", groupPK)
+		return
+	}
+	subjectPKset.Append(subDepartmentPKs...)
+
+	return subjectPKset.ToSlice(), nil
 }
 
 // CreateByGroupSubject ...
@@ -151,33 +205,22 @@ func (s *groupAlterEventService) CreateByGroupSubject(
 		return nil
 	}
 
-	actionPKsList, err := s.groupResourcePolicyManager.ListActionPKsByGroup(groupPK)
+	actionPKs, err := s.ListGroupActionPKs(groupPK)
 	if err != nil {
-		err = errorWrapf(err, "groupResourcePolicyManager.ListActionPKsByGroup groupPK=`%d` fail", groupPK)
+		err = errorWrapf(err, "ListGroupActionPKs groupPK=`%d` fail", groupPK)
 		return
 	}
 
-	actionPKSet := set.NewInt64Set()
-	for _, actionPKsStr := range actionPKsList {
-		var actionPKs []int64
-		if err = jsoniter.UnmarshalFromString(actionPKsStr, &actionPKs); err != nil {
-			err = errorWrapf(err, "json.Unmarshal actionPKsStr=`%s` fail", actionPKsStr)
-			return
-		}
-
-		actionPKSet.Append(actionPKs...)
-	}
-
-	if actionPKSet.Size() == 0 {
+	if len(actionPKs) == 0 {
 		return nil
 	}
 
-	err = s.create(groupPK, actionPKSet.ToSlice(), subjectPKs)
+	err = s.create(groupPK, actionPKs, subjectPKs)
 	if err != nil {
 		err = errorWrapf(
 			err,
 			"create fail groupPK=`%d` actionPKs=`%+v` subjectPKs=`%+v`",
-			actionPKSet.ToSlice(),
+			actionPKs,
 			subjectPKs,
 		)
 		return err
@@ -186,6 +229,28 @@ func (s *groupAlterEventService) CreateByGroupSubject(
 	return nil
 }
 
+// ListGroupActionPKs 查询用户组RBAC策略授权的所有操作
+func (s *groupAlterEventService) ListGroupActionPKs(groupPK int64) ([]int64, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupAlterEventSVC, "ListGroupActionPKs")
+
+	actionPKsList, err := s.groupResourcePolicyManager.ListActionPKsByGroup(groupPK)
+	if err != nil {
+		return nil, errorWrapf(err, "groupResourcePolicyManager.ListActionPKsByGroup groupPK=`%d` fail", groupPK)
+	}
+
+	actionPKSet := set.NewInt64Set()
+	for _, actionPKsStr := range actionPKsList {
+		var actionPKs []int64
+		if err = jsoniter.UnmarshalFromString(actionPKsStr, &actionPKs); err != nil {
+			return nil, errorWrapf(err, "json.Unmarshal actionPKsStr=`%s` fail", actionPKsStr)
+		}
+
+		actionPKSet.Append(actionPKs...)
+	}
+
+	return actionPKSet.ToSlice(), nil
+}
+
 func (s *groupAlterEventService) create(groupPK int64, actionPKs, subjectPKs []int64) error {
 	actionPKStr, err := jsoniter.MarshalToString(actionPKs)
 	if err != nil {
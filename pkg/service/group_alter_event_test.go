@@ -18,6 +18,7 @@ import (
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/jmoiron/sqlx"
+	jsoniter "github.com/json-iterator/go"
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/stretchr/testify/assert"
 
@@ -59,6 +60,8 @@ var _ = Describe("GroupAlterEventService", func() {
 				{SubjectPK: 11},
 				{SubjectPK: 12},
 			}, nil)
+			mockSubjectGroupManager.EXPECT().ListMemberGroupRelationsByGroupPKs([]int64{1}).Return(nil, nil)
+			mockSubjectGroupManager.EXPECT().ListSubDepartmentRelationsByGroupPKs([]int64{1}).Return(nil, nil)
 
 			mockSubjectTemplateGroupManager := mock.NewMockSubjectTemplateGroupManager(ctl)
 			mockSubjectTemplateGroupManager.EXPECT().ListGroupDistinctSubjectPK(int64(1)).Return([]int64{
@@ -78,6 +81,53 @@ var _ = Describe("GroupAlterEventService", func() {
 			assert.NoError(GinkgoT(), err)
 		})
 
+		It("nested group and sub department members", func() {
+			mockSubjectGroupManager := mock.NewMockSubjectGroupManager(ctl)
+			mockSubjectGroupManager.EXPECT().ListGroupMember(int64(1)).Return([]dao.SubjectRelation{
+				{SubjectPK: 11},
+				{SubjectPK: 2},
+			}, nil)
+			mockSubjectGroupManager.EXPECT().ListMemberGroupRelationsByGroupPKs([]int64{1}).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 2, GroupPK: 1, ExpiredAt: 4102444800}}, nil,
+			)
+			mockSubjectGroupManager.EXPECT().ListMemberGroupRelationsByGroupPKs([]int64{2}).Return(nil, nil)
+			mockSubjectGroupManager.EXPECT().ListGroupMember(int64(2)).Return([]dao.SubjectRelation{
+				{SubjectPK: 21, ExpiredAt: 4102444800},
+			}, nil)
+			mockSubjectGroupManager.EXPECT().ListSubDepartmentRelationsByGroupPKs([]int64{2}).Return(nil, nil)
+			mockSubjectGroupManager.EXPECT().ListSubDepartmentRelationsByGroupPKs([]int64{1}).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 12, GroupPK: 1, ExpiredAt: 4102444800}}, nil,
+			)
+
+			mockDepartmentTreeManager := mock.NewMockDepartmentTreeManager(ctl)
+			mockDepartmentTreeManager.EXPECT().ListByParentPKs([]int64{12}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 13, ParentPK: 12}}, nil,
+			)
+			mockDepartmentTreeManager.EXPECT().ListByParentPKs([]int64{13}).Return(nil, nil)
+
+			mockSubjectTemplateGroupManager := mock.NewMockSubjectTemplateGroupManager(ctl)
+			mockSubjectTemplateGroupManager.EXPECT().ListGroupDistinctSubjectPK(int64(1)).Return([]int64{}, nil)
+
+			mockManager := mock.NewMockGroupAlterEventManager(ctl)
+			mockManager.EXPECT().Create(gomock.Any()).DoAndReturn(func(event dao.GroupAlterEvent) error {
+				var subjectPKs []int64
+				err := jsoniter.UnmarshalFromString(event.SubjectPKs, &subjectPKs)
+				assert.NoError(GinkgoT(), err)
+				assert.ElementsMatch(GinkgoT(), []int64{11, 21, 13}, subjectPKs)
+				return nil
+			})
+
+			svc = &groupAlterEventService{
+				manager:                     mockManager,
+				subjectTemplateGroupManager: mockSubjectTemplateGroupManager,
+				subjectGroupManager:         mockSubjectGroupManager,
+				departmentTreeManager:       mockDepartmentTreeManager,
+			}
+
+			err := svc.CreateByGroupAction(1, []int64{1, 2})
+			assert.NoError(GinkgoT(), err)
+		})
+
 		It("empty action pks", func() {
 			svc = &groupAlterEventService{}
 			err := svc.CreateByGroupAction(1, []int64{})
@@ -115,6 +165,8 @@ var _ = Describe("GroupAlterEventService", func() {
 				{SubjectPK: 11},
 				{SubjectPK: 12},
 			}, nil)
+			mockSubjectGroupManager.EXPECT().ListMemberGroupRelationsByGroupPKs([]int64{1}).Return(nil, nil)
+			mockSubjectGroupManager.EXPECT().ListSubDepartmentRelationsByGroupPKs([]int64{1}).Return(nil, nil)
 
 			mockSubjectTemplateGroupManager := mock.NewMockSubjectTemplateGroupManager(ctl)
 			mockSubjectTemplateGroupManager.EXPECT().ListGroupDistinctSubjectPK(int64(1)).Return([]int64{
@@ -230,6 +282,47 @@ var _ = Describe("GroupAlterEventService", func() {
 		})
 	})
 
+	Describe("ListGroupActionPKs cases", func() {
+		var ctl *gomock.Controller
+
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("ok", func() {
+			mockGroupResourcePolicyManager := mock.NewMockGroupResourcePolicyManager(ctl)
+			mockGroupResourcePolicyManager.EXPECT().
+				ListActionPKsByGroup(int64(1)).
+				Return([]string{"[1,2]", "[2,3]"}, nil)
+
+			svc := &groupAlterEventService{
+				groupResourcePolicyManager: mockGroupResourcePolicyManager,
+			}
+
+			actionPKs, err := svc.ListGroupActionPKs(1)
+			assert.NoError(GinkgoT(), err)
+			assert.ElementsMatch(GinkgoT(), []int64{1, 2, 3}, actionPKs)
+		})
+
+		It("unmarshal fail", func() {
+			mockGroupResourcePolicyManager := mock.NewMockGroupResourcePolicyManager(ctl)
+			mockGroupResourcePolicyManager.EXPECT().
+				ListActionPKsByGroup(int64(1)).
+				Return([]string{"[1,"}, nil)
+
+			svc := &groupAlterEventService{
+				groupResourcePolicyManager: mockGroupResourcePolicyManager,
+			}
+
+			_, err := svc.ListGroupActionPKs(1)
+			assert.Error(GinkgoT(), err)
+		})
+	})
+
 	Describe("ListBeforeCreateAt cases", func() {
 		var ctl *gomock.Controller
 		var svc GroupAlterEventService
@@ -0,0 +1,182 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/database"
+	"iam/pkg/database/dao"
+	"iam/pkg/service/types"
+)
+
+// ListPendingSubjectGroupsBeforeEffectiveAt 查询已到生效时间但尚未激活的成员关系, 包括用户组成员与人员模版成员
+// NOTE: 用户组成员关系的 TemplateID 为 0
+func (l *groupService) ListPendingSubjectGroupsBeforeEffectiveAt(
+	effectiveAt, limit int64,
+) ([]types.SubjectTemplateGroup, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupSVC, "ListPendingSubjectGroupsBeforeEffectiveAt")
+
+	relations, err := l.manager.ListPendingRelationBeforeEffectiveAt(effectiveAt, limit)
+	if err != nil {
+		return nil, errorWrapf(
+			err, "manager.ListPendingRelationBeforeEffectiveAt effectiveAt=`%d`, limit=`%d` fail", effectiveAt, limit,
+		)
+	}
+
+	templateRelations, err := l.subjectTemplateGroupManager.ListPendingRelationBeforeEffectiveAt(effectiveAt, limit)
+	if err != nil {
+		return nil, errorWrapf(
+			err,
+			"subjectTemplateGroupManager.ListPendingRelationBeforeEffectiveAt effectiveAt=`%d`, limit=`%d` fail",
+			effectiveAt,
+			limit,
+		)
+	}
+
+	subjectGroups := make([]types.SubjectTemplateGroup, 0, len(relations)+len(templateRelations))
+	for _, r := range relations {
+		subjectGroups = append(subjectGroups, types.SubjectTemplateGroup{
+			SubjectPK:   r.SubjectPK,
+			GroupPK:     r.GroupPK,
+			ExpiredAt:   r.ExpiredAt,
+			EffectiveAt: r.EffectiveAt,
+		})
+	}
+
+	for _, r := range templateRelations {
+		subjectGroups = append(subjectGroups, types.SubjectTemplateGroup{
+			SubjectPK:   r.SubjectPK,
+			TemplateID:  r.TemplateID,
+			GroupPK:     r.GroupPK,
+			ExpiredAt:   r.ExpiredAt,
+			EffectiveAt: r.EffectiveAt,
+		})
+	}
+
+	return subjectGroups, nil
+}
+
+// GetSubjectTemplateGroupEffectiveAt 查询人员模版成员关系的生效时间, 关系不存在时返回0
+func (l *groupService) GetSubjectTemplateGroupEffectiveAt(subjectPK, groupPK, templateID int64) (int64, error) {
+	relations, err := l.subjectTemplateGroupManager.ListRelationBySubjectPKGroupPKs(subjectPK, []int64{groupPK})
+	if err != nil {
+		return 0, errorx.Wrapf(
+			err, GroupSVC, "GetSubjectTemplateGroupEffectiveAt",
+			"subjectTemplateGroupManager.ListRelationBySubjectPKGroupPKs subjectPK=`%d`, groupPK=`%d` fail",
+			subjectPK, groupPK,
+		)
+	}
+
+	for _, r := range relations {
+		if r.TemplateID == templateID {
+			return r.EffectiveAt, nil
+		}
+	}
+	return 0, nil
+}
+
+// ActivateSubjectGroups 激活已到生效时间的成员关系, 重置生效时间并更新subject system group
+// NOTE: 读路径已按 effective_at <= now 判断生效, 重置生效时间仅用于标记关系已处理, 避免重复激活
+func (l *groupService) ActivateSubjectGroups(relations []types.SubjectTemplateGroup) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupSVC, "ActivateSubjectGroups")
+
+	if len(relations) == 0 {
+		return nil
+	}
+
+	// 同一subject-group可能同时有多条待激活的关系, 取最大的过期时间
+	type subjectGroupKey struct {
+		subjectPK int64
+		groupPK   int64
+	}
+	expiredAtMap := make(map[subjectGroupKey]int64, len(relations))
+
+	daoRelations := make([]dao.SubjectRelation, 0, len(relations))
+	daoTemplateRelations := make([]dao.SubjectTemplateGroup, 0, len(relations))
+	for _, r := range relations {
+		if r.TemplateID == 0 {
+			daoRelations = append(daoRelations, dao.SubjectRelation{
+				SubjectPK: r.SubjectPK,
+				GroupPK:   r.GroupPK,
+			})
+		} else {
+			daoTemplateRelations = append(daoTemplateRelations, dao.SubjectTemplateGroup{
+				SubjectPK:  r.SubjectPK,
+				TemplateID: r.TemplateID,
+				GroupPK:    r.GroupPK,
+			})
+		}
+
+		key := subjectGroupKey{subjectPK: r.SubjectPK, groupPK: r.GroupPK}
+		if r.ExpiredAt > expiredAtMap[key] {
+			expiredAtMap[key] = r.ExpiredAt
+		}
+	}
+
+	// 与subject已生效的关系比较, 取最大的过期时间
+	now := time.Now().Unix()
+	needUpdateRelations := make([]types.SubjectTemplateGroup, 0, len(expiredAtMap))
+	for key, expiredAt := range expiredAtMap {
+		effectExpiredAt, err := l.GetMaxExpiredAtBySubjectGroup(key.subjectPK, key.groupPK, 0)
+		if err != nil && !errors.Is(err, ErrGroupMemberNotFound) {
+			return errorWrapf(
+				err, "GetMaxExpiredAtBySubjectGroup subjectPK=`%d`, groupPK=`%d` fail", key.subjectPK, key.groupPK,
+			)
+		}
+
+		if effectExpiredAt > expiredAt {
+			expiredAt = effectExpiredAt
+		}
+
+		// 已过期的关系无需添加
+		if expiredAt <= now {
+			continue
+		}
+
+		needUpdateRelations = append(needUpdateRelations, types.SubjectTemplateGroup{
+			SubjectPK:  key.subjectPK,
+			GroupPK:    key.groupPK,
+			ExpiredAt:  expiredAt,
+			NeedUpdate: true,
+		})
+	}
+
+	tx, err := database.GenerateDefaultDBTx()
+	defer database.RollBackWithLog(tx)
+	if err != nil {
+		return errorWrapf(err, "define tx error")
+	}
+
+	err = l.manager.BulkUpdateEffectiveAtWithTx(tx, daoRelations)
+	if err != nil {
+		return errorWrapf(err, "manager.BulkUpdateEffectiveAtWithTx relations=`%+v` fail", daoRelations)
+	}
+
+	err = l.subjectTemplateGroupManager.BulkUpdateEffectiveAtWithTx(tx, daoTemplateRelations)
+	if err != nil {
+		return errorWrapf(
+			err, "subjectTemplateGroupManager.BulkUpdateEffectiveAtWithTx relations=`%+v` fail", daoTemplateRelations,
+		)
+	}
+
+	err = l.BulkUpdateSubjectSystemGroupBySubjectTemplateGroupWithTx(tx, needUpdateRelations)
+	if err != nil {
+		return errorWrapf(
+			err, "BulkUpdateSubjectSystemGroupBySubjectTemplateGroupWithTx relations=`%+v` fail", needUpdateRelations,
+		)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,188 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database"
+	"iam/pkg/database/dao"
+	"iam/pkg/database/dao/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("GroupEffective", func() {
+	var ctl *gomock.Controller
+	var mockManager *mock.MockSubjectGroupManager
+	var mockSubjectTemplateGroupManager *mock.MockSubjectTemplateGroupManager
+	var manager *groupService
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockManager = mock.NewMockSubjectGroupManager(ctl)
+		mockSubjectTemplateGroupManager = mock.NewMockSubjectTemplateGroupManager(ctl)
+		manager = &groupService{
+			manager:                     mockManager,
+			subjectTemplateGroupManager: mockSubjectTemplateGroupManager,
+		}
+	})
+	AfterEach(func() {
+		ctl.Finish()
+	})
+
+	Describe("ListPendingSubjectGroupsBeforeEffectiveAt", func() {
+		It("manager fail", func() {
+			mockManager.EXPECT().ListPendingRelationBeforeEffectiveAt(int64(10), int64(100)).Return(
+				nil, errors.New("error"),
+			)
+
+			_, err := manager.ListPendingSubjectGroupsBeforeEffectiveAt(10, 100)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "manager.ListPendingRelationBeforeEffectiveAt")
+		})
+
+		It("ok", func() {
+			mockManager.EXPECT().ListPendingRelationBeforeEffectiveAt(int64(10), int64(100)).Return(
+				[]dao.SubjectRelation{{SubjectPK: 1, GroupPK: 2, ExpiredAt: 20, EffectiveAt: 5}}, nil,
+			)
+			mockSubjectTemplateGroupManager.EXPECT().ListPendingRelationBeforeEffectiveAt(int64(10), int64(100)).Return(
+				[]dao.SubjectTemplateGroup{{SubjectPK: 1, TemplateID: 3, GroupPK: 2, ExpiredAt: 30, EffectiveAt: 6}}, nil,
+			)
+
+			relations, err := manager.ListPendingSubjectGroupsBeforeEffectiveAt(10, 100)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.SubjectTemplateGroup{
+				{SubjectPK: 1, GroupPK: 2, ExpiredAt: 20, EffectiveAt: 5},
+				{SubjectPK: 1, TemplateID: 3, GroupPK: 2, ExpiredAt: 30, EffectiveAt: 6},
+			}, relations)
+		})
+	})
+
+	Describe("GetSubjectTemplateGroupEffectiveAt", func() {
+		It("fail", func() {
+			mockSubjectTemplateGroupManager.EXPECT().ListRelationBySubjectPKGroupPKs(int64(1), []int64{2}).Return(
+				nil, errors.New("error"),
+			)
+
+			_, err := manager.GetSubjectTemplateGroupEffectiveAt(1, 2, 3)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListRelationBySubjectPKGroupPKs")
+		})
+
+		It("ok", func() {
+			mockSubjectTemplateGroupManager.EXPECT().ListRelationBySubjectPKGroupPKs(int64(1), []int64{2}).Return(
+				[]dao.SubjectTemplateGroup{
+					{SubjectPK: 1, GroupPK: 2, TemplateID: 4, EffectiveAt: 0},
+					{SubjectPK: 1, GroupPK: 2, TemplateID: 3, EffectiveAt: 100},
+				}, nil,
+			).Times(2)
+
+			effectiveAt, err := manager.GetSubjectTemplateGroupEffectiveAt(1, 2, 3)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), int64(100), effectiveAt)
+
+			effectiveAt, err = manager.GetSubjectTemplateGroupEffectiveAt(1, 2, 5)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), int64(0), effectiveAt)
+		})
+	})
+
+	Describe("ActivateSubjectGroups", func() {
+		var patches *gomonkey.Patches
+		var updatedRelations []types.SubjectTemplateGroup
+
+		BeforeEach(func() {
+			tx := &sql.Tx{}
+			patches = gomonkey.ApplyMethod(reflect.TypeOf(tx), "Commit", func(tx *sql.Tx) error {
+				return nil
+			})
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return &sqlx.Tx{Tx: tx}, nil
+			})
+			patches.ApplyFunc(database.RollBackWithLog, func(tx *sqlx.Tx) {})
+			patches.ApplyMethod(
+				reflect.TypeOf(manager),
+				"BulkUpdateSubjectSystemGroupBySubjectTemplateGroupWithTx",
+				func(_ *groupService, _ *sqlx.Tx, relations []types.SubjectTemplateGroup) error {
+					updatedRelations = relations
+					return nil
+				},
+			)
+		})
+		AfterEach(func() {
+			patches.Reset()
+			updatedRelations = nil
+		})
+
+		It("empty", func() {
+			err := manager.ActivateSubjectGroups(nil)
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("GetMaxExpiredAtBySubjectGroup fail", func() {
+			mockManager.EXPECT().GetExpiredAtBySubjectGroup(int64(1), int64(2), gomock.Any()).Return(int64(0), errors.New("error"))
+
+			err := manager.ActivateSubjectGroups([]types.SubjectTemplateGroup{
+				{SubjectPK: 1, GroupPK: 2, ExpiredAt: 20, EffectiveAt: 5},
+			})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "GetMaxExpiredAtBySubjectGroup")
+		})
+
+		It("ok", func() {
+			expiredAt := time.Now().Unix() + 100
+
+			mockManager.EXPECT().GetExpiredAtBySubjectGroup(int64(1), int64(2), gomock.Any()).Return(int64(0), sql.ErrNoRows)
+			mockSubjectTemplateGroupManager.EXPECT().
+				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0), gomock.Any()).
+				Return(int64(0), nil)
+			mockManager.EXPECT().BulkUpdateEffectiveAtWithTx(gomock.Any(), []dao.SubjectRelation{
+				{SubjectPK: 1, GroupPK: 2},
+			}).Return(nil)
+			mockSubjectTemplateGroupManager.EXPECT().BulkUpdateEffectiveAtWithTx(gomock.Any(), []dao.SubjectTemplateGroup{
+				{SubjectPK: 1, TemplateID: 3, GroupPK: 2},
+			}).Return(nil)
+
+			err := manager.ActivateSubjectGroups([]types.SubjectTemplateGroup{
+				{SubjectPK: 1, GroupPK: 2, ExpiredAt: expiredAt, EffectiveAt: 5},
+				{SubjectPK: 1, TemplateID: 3, GroupPK: 2, ExpiredAt: expiredAt + 10, EffectiveAt: 5},
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.SubjectTemplateGroup{
+				{SubjectPK: 1, GroupPK: 2, ExpiredAt: expiredAt + 10, NeedUpdate: true},
+			}, updatedRelations)
+		})
+
+		It("ok, expired", func() {
+			mockManager.EXPECT().GetExpiredAtBySubjectGroup(int64(1), int64(2), gomock.Any()).Return(int64(0), sql.ErrNoRows)
+			mockSubjectTemplateGroupManager.EXPECT().
+				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0), gomock.Any()).
+				Return(int64(0), nil)
+			mockManager.EXPECT().BulkUpdateEffectiveAtWithTx(gomock.Any(), gomock.Any()).Return(nil)
+			mockSubjectTemplateGroupManager.EXPECT().BulkUpdateEffectiveAtWithTx(gomock.Any(), gomock.Any()).Return(nil)
+
+			err := manager.ActivateSubjectGroups([]types.SubjectTemplateGroup{
+				{SubjectPK: 1, GroupPK: 2, ExpiredAt: 1, EffectiveAt: 5},
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), updatedRelations)
+		})
+	})
+})
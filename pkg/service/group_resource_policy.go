@@ -15,6 +15,7 @@ package service
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/TencentBlueKing/gopkg/collection/set"
 	"github.com/TencentBlueKing/gopkg/errorx"
@@ -29,24 +30,26 @@ import (
 const GroupResourcePolicySVC = "GroupResourcePolicySVC"
 
 type GroupResourcePolicyService interface {
+	// Alter 返回删除的策略PK与实际发生变化的内容
 	Alter(
 		tx *sqlx.Tx,
 		groupPK, templateID int64,
 		systemID string,
 		systemActionPKSet *set.Int64Set,
 		resourceChangedContents []types.ResourceChangedContent,
-	) ([]int64, error)
+	) ([]int64, []types.ResourceChangedContent, error)
 
 	GetAuthorizedActionGroupMap(
 		systemID string,
 		actionResourceTypePK, resourceTypePK int64,
 		resourceTypeID string,
-	) (map[int64][]int64, error)
+	) (map[int64][]types.AuthorizedGroup, error)
 	ListResourceByGroupAction(
 		groupPK int64,
 		systemID string,
 		actionPK, actionResourceTypePK int64,
 	) ([]types.Resource, error)
+	ListPagingBySystem(systemID string, lastPK, limit int64) ([]types.GroupResourcePolicy, error)
 	BulkDeleteByGroupPKsWithTx(tx *sqlx.Tx, groupPKs []int64) error
 
 	DeleteByActionPKWithTx(tx *sqlx.Tx, actionPK int64) error
@@ -75,9 +78,39 @@ func (s *groupResourcePolicyService) calculateSignature(
 		"%d:%d:%s:%d:%d:%s",
 		groupPK, templateID, systemID, rcc.ActionRelatedResourceTypePK, rcc.ResourceTypePK, rcc.ResourceID,
 	)
+	// Note: 关联多个资源类型时, 按顺序追加其它资源实例, 只关联一个资源类型时与原有的signature保持一致
+	for _, rr := range rcc.RelatedResources {
+		signature += fmt.Sprintf("|%d:%d:%s", rr.ActionRelatedResourceTypePK, rr.ResourceTypePK, rr.ResourceID)
+	}
 	return stringx.MD5Hash(signature)
 }
 
+// marshalRelatedResources : 将其它资源类型授权的资源实例转换为json字符串, 为空时返回空字符串
+func marshalRelatedResources(relatedResources []types.RelatedResource) (string, error) {
+	if len(relatedResources) == 0 {
+		return "", nil
+	}
+
+	return jsoniter.MarshalToString(relatedResources)
+}
+
+// unmarshalRelatedResources : 解析json字符串存储的其它资源类型授权的资源实例
+// NOTE: related_resources字段没有默认值, 加字段前已存在的策略为空字符串, 表示没有关联其它资源类型
+func unmarshalRelatedResources(relatedResources string) ([]types.RelatedResource, error) {
+	if strings.TrimSpace(relatedResources) == "" {
+		return nil, nil
+	}
+
+	var rrs []types.RelatedResource
+	err := jsoniter.UnmarshalFromString(relatedResources, &rrs)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"jsoniter.UnmarshalFromString relatedResources=`%s` fail, err: %w", relatedResources, err,
+		)
+	}
+	return rrs, nil
+}
+
 // calculateChangedActionPKs : 使用旧的ActionPKs和要变更的内容，计算出最终变更的ActionPKs
 func (s *groupResourcePolicyService) calculateChangedActionPKs(
 	oldActionPKs string, systemActionPKSet *set.Int64Set, rcc types.ResourceChangedContent,
@@ -136,7 +169,7 @@ func (s *groupResourcePolicyService) Alter(
 	systemID string,
 	systemActionPKSet *set.Int64Set,
 	resourceChangedContents []types.ResourceChangedContent,
-) ([]int64, error) {
+) ([]int64, []types.ResourceChangedContent, error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupResourcePolicySVC, "Alter")
 
 	// Note: 底层这里没有保证并发同时修改的问题，而是顶层调用者SaaS通过分布式锁保证的
@@ -150,7 +183,7 @@ func (s *groupResourcePolicyService) Alter(
 	// 2. 查询每条策略
 	policies, err := s.manager.ListBySignatures(signatures)
 	if err != nil {
-		return nil, errorWrapf(
+		return nil, nil, errorWrapf(
 			err,
 			"manager.ListBySignatures fail, groupPK=`%d`, templateID=`%d`, systemID=`%s`, resourceChangedContents=`%v`",
 			groupPK, templateID, systemID, resourceChangedContents,
@@ -165,6 +198,7 @@ func (s *groupResourcePolicyService) Alter(
 	createdPolicies := make([]dao.GroupResourcePolicy, 0, len(resourceChangedContents))
 	updatedPolicies := make([]dao.GroupResourcePolicy, 0, len(resourceChangedContents))
 	deletedPolicyPKs := make([]int64, 0, len(resourceChangedContents))
+	changedContents := make([]types.ResourceChangedContent, 0, len(resourceChangedContents))
 	for _, rcc := range resourceChangedContents {
 		signature := s.calculateSignature(groupPK, templateID, systemID, rcc)
 		policy, found := signatureToPolicyMap[signature]
@@ -172,7 +206,7 @@ func (s *groupResourcePolicyService) Alter(
 		// 根据变更内容，计算出变更后的ActionPKs Json字符串
 		actionPKs, err := s.calculateChangedActionPKs(policy.ActionPKs, systemActionPKSet, rcc)
 		if err != nil {
-			return nil, errorWrapf(
+			return nil, nil, errorWrapf(
 				err,
 				"calculateChangedActionPKs fail, signature=`%s`, groupPK=`%d`, templateID=`%d`,"+
 					" systemID=`%s`, resourceChangedContent=`%v`",
@@ -184,8 +218,27 @@ func (s *groupResourcePolicyService) Alter(
 			)
 		}
 
+		// 记录实际发生变化的操作
+		createdActionPKs, deletedActionPKs, err := diffActionPKs(policy.ActionPKs, actionPKs, systemActionPKSet)
+		if err != nil {
+			return nil, nil, errorWrapf(err, "diffActionPKs fail, old=`%s`, new=`%s`", policy.ActionPKs, actionPKs)
+		}
+		if len(createdActionPKs) > 0 || len(deletedActionPKs) > 0 {
+			changedContent := rcc
+			changedContent.CreatedActionPKs = createdActionPKs
+			changedContent.DeletedActionPKs = deletedActionPKs
+			changedContents = append(changedContents, changedContent)
+		}
+
 		// 3.1 找不到，则需要新增记录
 		if !found && actionPKs != "" {
+			relatedResources, err := marshalRelatedResources(rcc.RelatedResources)
+			if err != nil {
+				return nil, nil, errorWrapf(
+					err, "marshalRelatedResources fail, relatedResources=`%v`", rcc.RelatedResources,
+				)
+			}
+
 			createdPolicies = append(createdPolicies, dao.GroupResourcePolicy{
 				Signature:                   signature,
 				GroupPK:                     groupPK,
@@ -195,6 +248,7 @@ func (s *groupResourcePolicyService) Alter(
 				ActionRelatedResourceTypePK: rcc.ActionRelatedResourceTypePK,
 				ResourceTypePK:              rcc.ResourceTypePK,
 				ResourceID:                  rcc.ResourceID,
+				RelatedResources:            relatedResources,
 			})
 			continue
 		}
@@ -223,33 +277,69 @@ func (s *groupResourcePolicyService) Alter(
 	if len(createdPolicies) > 0 {
 		err := s.manager.BulkCreateWithTx(tx, createdPolicies)
 		if err != nil {
-			return nil, errorWrapf(err, "manager.BulkCreateWithTx fail policies=`%v`", createdPolicies)
+			return nil, nil, errorWrapf(err, "manager.BulkCreateWithTx fail policies=`%v`", createdPolicies)
 		}
 	}
 	// 删
 	if len(deletedPolicyPKs) > 0 {
 		err := s.manager.BulkDeleteByPKsWithTx(tx, deletedPolicyPKs)
 		if err != nil {
-			return nil, errorWrapf(err, "manager.BulkDeleteByPKsWithTx fail pks=`%v`", deletedPolicyPKs)
+			return nil, nil, errorWrapf(err, "manager.BulkDeleteByPKsWithTx fail pks=`%v`", deletedPolicyPKs)
 		}
 	}
 	// 改
 	if len(updatedPolicies) > 0 {
 		err := s.manager.BulkUpdateActionPKsWithTx(tx, updatedPolicies)
 		if err != nil {
-			return nil, errorWrapf(err, "manager.BulkUpdateActionPKsWithTx fail policies=`%v`", updatedPolicies)
+			return nil, nil, errorWrapf(err, "manager.BulkUpdateActionPKsWithTx fail policies=`%v`", updatedPolicies)
 		}
 	}
 
-	return deletedPolicyPKs, nil
+	return deletedPolicyPKs, changedContents, nil
+}
+
+// diffActionPKs 比较变更前后的ActionPKs, 系统中已不存在的操作不计入删除
+func diffActionPKs(
+	oldActionPKs, newActionPKs string, systemActionPKSet *set.Int64Set,
+) (createdActionPKs, deletedActionPKs []int64, err error) {
+	unmarshal := func(actionPKs string) (actionPKList []int64, err error) {
+		if len(actionPKs) > 0 {
+			err = jsoniter.UnmarshalFromString(actionPKs, &actionPKList)
+		}
+		return
+	}
+
+	oldActionPKList, err := unmarshal(oldActionPKs)
+	if err != nil {
+		return
+	}
+	newActionPKList, err := unmarshal(newActionPKs)
+	if err != nil {
+		return
+	}
+
+	oldActionPKSet := set.NewInt64SetWithValues(oldActionPKList)
+	newActionPKSet := set.NewInt64SetWithValues(newActionPKList)
+	for _, actionPK := range newActionPKList {
+		if !oldActionPKSet.Has(actionPK) {
+			createdActionPKs = append(createdActionPKs, actionPK)
+		}
+	}
+	for _, actionPK := range oldActionPKList {
+		if systemActionPKSet.Has(actionPK) && !newActionPKSet.Has(actionPK) {
+			deletedActionPKs = append(deletedActionPKs, actionPK)
+		}
+	}
+	return createdActionPKs, deletedActionPKs, nil
 }
 
 // GetAuthorizedActionGroupMap 查询有权限的用户组的操作
+// NOTE: 操作关联多个资源类型时, 返回的用户组带有授权的其它资源实例, 鉴权时需要同时匹配
 func (s *groupResourcePolicyService) GetAuthorizedActionGroupMap(
 	systemID string,
 	actionResourceTypePK, resourceTypePK int64,
 	resourceID string,
-) (map[int64][]int64, error) {
+) (map[int64][]types.AuthorizedGroup, error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupResourcePolicySVC, "GetAuthorizedActionGroupMap")
 
 	daoGroupResourcePolicies, err := s.manager.ListThinByResource(
@@ -269,7 +359,9 @@ func (s *groupResourcePolicyService) GetAuthorizedActionGroupMap(
 		)
 	}
 
+	// 只关联一个资源类型的授权, 同一个用户组只需要返回一次
 	actionGroupPKsSets := make(map[int64]*set.Int64Set, 5)
+	actionGroups := make(map[int64][]types.AuthorizedGroup, 5)
 	for _, daoGroupResourcePolicy := range daoGroupResourcePolicies {
 		var actionPKs []int64
 		if err := jsoniter.UnmarshalFromString(daoGroupResourcePolicy.ActionPKs, &actionPKs); err != nil {
@@ -280,21 +372,30 @@ func (s *groupResourcePolicyService) GetAuthorizedActionGroupMap(
 			)
 		}
 
+		relatedResources, err := unmarshalRelatedResources(daoGroupResourcePolicy.RelatedResources)
+		if err != nil {
+			return nil, errorWrapf(err, "unmarshalRelatedResources fail")
+		}
+
 		for _, actionPK := range actionPKs {
-			if _, found := actionGroupPKsSets[actionPK]; !found {
-				actionGroupPKsSets[actionPK] = set.NewInt64Set()
+			if len(relatedResources) == 0 {
+				if _, found := actionGroupPKsSets[actionPK]; !found {
+					actionGroupPKsSets[actionPK] = set.NewInt64Set()
+				}
+				if actionGroupPKsSets[actionPK].Has(daoGroupResourcePolicy.GroupPK) {
+					continue
+				}
+				actionGroupPKsSets[actionPK].Add(daoGroupResourcePolicy.GroupPK)
 			}
 
-			actionGroupPKsSets[actionPK].Add(daoGroupResourcePolicy.GroupPK)
+			actionGroups[actionPK] = append(actionGroups[actionPK], types.AuthorizedGroup{
+				GroupPK:          daoGroupResourcePolicy.GroupPK,
+				RelatedResources: relatedResources,
+			})
 		}
 	}
 
-	actionGroupPKs := make(map[int64][]int64, len(actionGroupPKsSets))
-	for actionPK, actionGroupPKsSet := range actionGroupPKsSets {
-		actionGroupPKs[actionPK] = actionGroupPKsSet.ToSlice()
-	}
-
-	return actionGroupPKs, nil
+	return actionGroups, nil
 }
 
 func (s *groupResourcePolicyService) ListResourceByGroupAction(
@@ -334,6 +435,11 @@ func (s *groupResourcePolicyService) ListResourceByGroupAction(
 			continue
 		}
 
+		// NOTE: 关联多个资源类型的授权无法只用单个资源实例表达, 这里忽略
+		if policy.RelatedResources != "" {
+			continue
+		}
+
 		resources = append(resources, types.Resource{
 			ResourceTypePK: policy.ResourceTypePK,
 			ResourceID:     policy.ResourceID,
@@ -343,6 +449,49 @@ func (s *groupResourcePolicyService) ListResourceByGroupAction(
 	return resources, nil
 }
 
+// ListPagingBySystem 按pk顺序分页查询系统下的授权, 用于导出
+func (s *groupResourcePolicyService) ListPagingBySystem(
+	systemID string,
+	lastPK, limit int64,
+) ([]types.GroupResourcePolicy, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupResourcePolicySVC, "ListPagingBySystem")
+
+	daoPolicies, err := s.manager.ListPagingBySystem(systemID, lastPK, limit)
+	if err != nil {
+		return nil, errorWrapf(
+			err, "manager.ListPagingBySystem fail, systemID=`%s`, lastPK=`%d`, limit=`%d`", systemID, lastPK, limit,
+		)
+	}
+
+	policies := make([]types.GroupResourcePolicy, 0, len(daoPolicies))
+	for _, p := range daoPolicies {
+		var actionPKs []int64
+		if err := jsoniter.UnmarshalFromString(p.ActionPKs, &actionPKs); err != nil {
+			return nil, errorWrapf(
+				err, "jsoniter.UnmarshalFromString fail, pk=`%d` actionPKs=`%s`", p.PK, p.ActionPKs,
+			)
+		}
+
+		relatedResources, err := unmarshalRelatedResources(p.RelatedResources)
+		if err != nil {
+			return nil, errorWrapf(err, "unmarshalRelatedResources fail, pk=`%d`", p.PK)
+		}
+
+		policies = append(policies, types.GroupResourcePolicy{
+			PK:                          p.PK,
+			GroupPK:                     p.GroupPK,
+			TemplateID:                  p.TemplateID,
+			ActionPKs:                   actionPKs,
+			ActionRelatedResourceTypePK: p.ActionRelatedResourceTypePK,
+			ResourceTypePK:              p.ResourceTypePK,
+			ResourceID:                  p.ResourceID,
+			RelatedResources:            relatedResources,
+		})
+	}
+
+	return policies, nil
+}
+
 // BulkDeleteByGroupPKsWithTx ...
 func (s *groupResourcePolicyService) BulkDeleteByGroupPKsWithTx(
 	tx *sqlx.Tx,
@@ -84,6 +84,28 @@ var _ = Describe("GroupResourcePolicyService", func() {
 				assert.NotEqual(GinkgoT(), s1, s3)
 				assert.NotEqual(GinkgoT(), s2, s3)
 			})
+
+			It("related resources", func() {
+				rcc := types.ResourceChangedContent{
+					ActionRelatedResourceTypePK: int64(3),
+					ResourceTypePK:              int64(4),
+					ResourceID:                  "resource_id",
+				}
+				s1 := interSvc.calculateSignature(int64(1), int64(2), "test", rcc)
+
+				rcc.RelatedResources = []types.RelatedResource{{
+					ActionRelatedResourceTypePK: int64(5),
+					ResourceTypePK:              int64(5),
+					ResourceID:                  "cluster1",
+				}}
+				s2 := interSvc.calculateSignature(int64(1), int64(2), "test", rcc)
+
+				rcc.RelatedResources[0].ResourceID = "cluster2"
+				s3 := interSvc.calculateSignature(int64(1), int64(2), "test", rcc)
+
+				assert.NotEqual(GinkgoT(), s1, s2)
+				assert.NotEqual(GinkgoT(), s2, s3)
+			})
 		})
 
 		Context("calculateChangedActionPKs", func() {
@@ -128,6 +150,25 @@ var _ = Describe("GroupResourcePolicyService", func() {
 				assertJsonStringOfInt64Slice(GinkgoT(), `[5, 4, 2]`, aks)
 			})
 		})
+
+		Context("unmarshalRelatedResources", func() {
+			It("empty", func() {
+				for _, s := range []string{"", " ", "null", "[]"} {
+					rrs, err := unmarshalRelatedResources(s)
+					assert.NoError(GinkgoT(), err)
+					assert.Empty(GinkgoT(), rrs)
+				}
+			})
+			It("error", func() {
+				_, err := unmarshalRelatedResources("xxx")
+				assert.Regexp(GinkgoT(), "jsoniter.UnmarshalFromString (.*) fail", err.Error())
+			})
+			It("ok", func() {
+				rrs, err := unmarshalRelatedResources(`[{"resource_type_pk":2,"resource_id":"cluster1"}]`)
+				assert.NoError(GinkgoT(), err)
+				assert.Len(GinkgoT(), rrs, 1)
+			})
+		})
 	})
 
 	Context("Alter Policy", func() {
@@ -176,7 +217,7 @@ var _ = Describe("GroupResourcePolicyService", func() {
 		It("ListBySignatures error", func() {
 			mockManager.EXPECT().ListBySignatures([]string{signature}).Return(nil, errors.New("error"))
 
-			_, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
+			_, _, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
 			assert.Error(GinkgoT(), err)
 			assert.Regexp(GinkgoT(), "manager.ListBySignatures fail", err.Error())
 		})
@@ -185,7 +226,7 @@ var _ = Describe("GroupResourcePolicyService", func() {
 			daoPolicy.ActionPKs = "json error"
 			mockManager.EXPECT().ListBySignatures([]string{signature}).Return([]dao.GroupResourcePolicy{daoPolicy}, nil)
 
-			_, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
+			_, _, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
 			assert.Error(GinkgoT(), err)
 			assert.Regexp(GinkgoT(), "calculateChangedActionPKs fail", err.Error())
 		})
@@ -195,7 +236,7 @@ var _ = Describe("GroupResourcePolicyService", func() {
 			mockManager.EXPECT().BulkCreateWithTx(gomock.Any(), gomock.Any()).Return(errors.New("error"))
 
 			rcc.CreatedActionPKs = []int64{1}
-			_, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
+			_, _, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
 			assert.Error(GinkgoT(), err)
 			assert.Regexp(GinkgoT(), "manager.BulkCreateWithTx fail", err.Error())
 		})
@@ -205,7 +246,7 @@ var _ = Describe("GroupResourcePolicyService", func() {
 			mockManager.EXPECT().BulkCreateWithTx(gomock.Any(), gomock.Any()).Return(nil)
 
 			rcc.CreatedActionPKs = []int64{1}
-			_, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
+			_, _, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
 			assert.NoError(GinkgoT(), err)
 		})
 
@@ -215,7 +256,7 @@ var _ = Describe("GroupResourcePolicyService", func() {
 			mockManager.EXPECT().BulkDeleteByPKsWithTx(gomock.Any(), gomock.Any()).Return(errors.New("error"))
 
 			rcc.DeletedActionPKs = []int64{1}
-			_, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
+			_, _, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
 			assert.Error(GinkgoT(), err)
 			assert.Regexp(GinkgoT(), "manager.BulkDeleteByPKsWithTx fail", err.Error())
 		})
@@ -226,7 +267,7 @@ var _ = Describe("GroupResourcePolicyService", func() {
 			mockManager.EXPECT().BulkDeleteByPKsWithTx(gomock.Any(), gomock.Any()).Return(nil)
 
 			rcc.DeletedActionPKs = []int64{1}
-			_, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
+			_, _, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
 			assert.NoError(GinkgoT(), err)
 		})
 
@@ -237,7 +278,7 @@ var _ = Describe("GroupResourcePolicyService", func() {
 
 			rcc.CreatedActionPKs = []int64{3, 4}
 			rcc.DeletedActionPKs = []int64{1}
-			_, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
+			_, _, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
 			assert.Error(GinkgoT(), err)
 			assert.Regexp(GinkgoT(), "manager.BulkUpdateActionPKsWithTx fail", err.Error())
 		})
@@ -249,8 +290,26 @@ var _ = Describe("GroupResourcePolicyService", func() {
 
 			rcc.CreatedActionPKs = []int64{3, 4}
 			rcc.DeletedActionPKs = []int64{1}
-			_, err := svc.Alter(nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc})
+			_, changedContents, err := svc.Alter(
+				nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc},
+			)
 			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), changedContents, 1)
+			assert.Equal(GinkgoT(), []int64{3, 4}, changedContents[0].CreatedActionPKs)
+			assert.Equal(GinkgoT(), []int64{1}, changedContents[0].DeletedActionPKs)
+		})
+
+		It("no change", func() {
+			daoPolicy.ActionPKs = "[1,2]"
+			mockManager.EXPECT().ListBySignatures([]string{signature}).Return([]dao.GroupResourcePolicy{daoPolicy}, nil)
+
+			rcc.CreatedActionPKs = []int64{1}
+			rcc.DeletedActionPKs = []int64{3}
+			_, changedContents, err := svc.Alter(
+				nil, int64(1), int64(2), "test", systemActionPKSet, []types.ResourceChangedContent{rcc},
+			)
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), changedContents)
 		})
 	})
 
@@ -308,22 +367,69 @@ var _ = Describe("GroupResourcePolicyService", func() {
 					ActionPKs: "[3,4]",
 				}}, nil)
 
-			actionGroupPKs, err := svc.GetAuthorizedActionGroupMap("test", int64(1), int64(2), "resource_test")
+			actionGroups, err := svc.GetAuthorizedActionGroupMap("test", int64(1), int64(2), "resource_test")
 			assert.NoError(GinkgoT(), err)
-			assert.Len(GinkgoT(), actionGroupPKs, 4)
-			for actionPK, groupPKs := range actionGroupPKs {
+			assert.Len(GinkgoT(), actionGroups, 4)
+			for actionPK, groups := range actionGroups {
 				switch actionPK {
 				case int64(1):
-					assert.Equal(GinkgoT(), []int64{1}, groupPKs)
+					assert.Equal(GinkgoT(), []types.AuthorizedGroup{{GroupPK: 1}}, groups)
 				case int64(2):
-					assert.Equal(GinkgoT(), []int64{1}, groupPKs)
+					assert.Equal(GinkgoT(), []types.AuthorizedGroup{{GroupPK: 1}}, groups)
 				case int64(3):
-					assert.Len(GinkgoT(), groupPKs, 2)
+					assert.Len(GinkgoT(), groups, 2)
 				case int64(4):
-					assert.Equal(GinkgoT(), []int64{1}, groupPKs)
+					assert.Equal(GinkgoT(), []types.AuthorizedGroup{{GroupPK: 1}}, groups)
 				}
 			}
 		})
+
+		It("ok, related resources", func() {
+			mockManager.EXPECT().
+				ListThinByResource("test", int64(1), int64(2), "resource_test").
+				Return([]dao.ThinGroupResourcePolicy{{
+					GroupPK:          int64(1),
+					ActionPKs:        "[1]",
+					RelatedResources: `[{"action_related_resource_type_pk":3,"resource_type_pk":3,"resource_id":"c1"}]`,
+				}, {
+					GroupPK:          int64(1),
+					ActionPKs:        "[1]",
+					RelatedResources: `[{"action_related_resource_type_pk":3,"resource_type_pk":3,"resource_id":"c2"}]`,
+				}}, nil)
+
+			actionGroups, err := svc.GetAuthorizedActionGroupMap("test", int64(1), int64(2), "resource_test")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[int64][]types.AuthorizedGroup{
+				1: {
+					{
+						GroupPK: 1,
+						RelatedResources: []types.RelatedResource{
+							{ActionRelatedResourceTypePK: 3, ResourceTypePK: 3, ResourceID: "c1"},
+						},
+					},
+					{
+						GroupPK: 1,
+						RelatedResources: []types.RelatedResource{
+							{ActionRelatedResourceTypePK: 3, ResourceTypePK: 3, ResourceID: "c2"},
+						},
+					},
+				},
+			}, actionGroups)
+		})
+
+		It("unmarshalRelatedResources error", func() {
+			mockManager.EXPECT().
+				ListThinByResource("test", int64(1), int64(2), "resource_test").
+				Return([]dao.ThinGroupResourcePolicy{{
+					GroupPK:          int64(1),
+					ActionPKs:        "[1]",
+					RelatedResources: "xxx",
+				}}, nil)
+
+			_, err := svc.GetAuthorizedActionGroupMap("test", int64(1), int64(2), "resource_test")
+			assert.Error(GinkgoT(), err)
+			assert.Regexp(GinkgoT(), "unmarshalRelatedResources fail", err.Error())
+		})
 	})
 
 	Context("ListResourceByGroupAction", func() {
@@ -404,4 +510,71 @@ var _ = Describe("GroupResourcePolicyService", func() {
 			}, resources)
 		})
 	})
+
+	Context("ListPagingBySystem", func() {
+		var (
+			ctl         *gomock.Controller
+			mockManager *mock.MockGroupResourcePolicyManager
+			svc         GroupResourcePolicyService
+		)
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+			mockManager = mock.NewMockGroupResourcePolicyManager(ctl)
+			svc = &groupResourcePolicyService{
+				manager: mockManager,
+			}
+		})
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("manager.ListPagingBySystem error", func() {
+			mockManager.EXPECT().ListPagingBySystem("test", int64(0), int64(10)).Return(nil, errors.New("error"))
+
+			_, err := svc.ListPagingBySystem("test", 0, 10)
+			assert.Error(GinkgoT(), err)
+			assert.Regexp(GinkgoT(), "manager.ListPagingBySystem fail", err.Error())
+		})
+
+		It("jsoniter.UnmarshalFromString error", func() {
+			mockManager.EXPECT().ListPagingBySystem("test", int64(0), int64(10)).Return([]dao.GroupResourcePolicy{{
+				PK:        1,
+				ActionPKs: "xxx",
+			}}, nil)
+
+			_, err := svc.ListPagingBySystem("test", 0, 10)
+			assert.Error(GinkgoT(), err)
+			assert.Regexp(GinkgoT(), "jsoniter.UnmarshalFromString fail", err.Error())
+		})
+
+		It("ok", func() {
+			mockManager.EXPECT().ListPagingBySystem("test", int64(0), int64(10)).Return([]dao.GroupResourcePolicy{{
+				PK:                          1,
+				GroupPK:                     2,
+				TemplateID:                  3,
+				ActionPKs:                   "[1,2]",
+				ActionRelatedResourceTypePK: 1,
+				ResourceTypePK:              1,
+				ResourceID:                  "test1",
+				RelatedResources:            `[{"action_related_resource_type_pk":2,"resource_type_pk":2,"resource_id":"a"}]`,
+			}}, nil)
+
+			policies, err := svc.ListPagingBySystem("test", 0, 10)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.GroupResourcePolicy{{
+				PK:                          1,
+				GroupPK:                     2,
+				TemplateID:                  3,
+				ActionPKs:                   []int64{1, 2},
+				ActionRelatedResourceTypePK: 1,
+				ResourceTypePK:              1,
+				ResourceID:                  "test1",
+				RelatedResources: []types.RelatedResource{{
+					ActionRelatedResourceTypePK: 2,
+					ResourceTypePK:              2,
+					ResourceID:                  "a",
+				}},
+			}}, policies)
+		})
+	})
 })
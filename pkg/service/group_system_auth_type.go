@@ -60,7 +60,9 @@ func (s *groupService) AlterGroupAuthType(
 			}
 
 			// 查询用户组模版成员
-			relations, err := s.subjectTemplateGroupManager.ListThinRelationWithMaxExpiredAtByGroupPK(groupPK)
+			relations, err := s.subjectTemplateGroupManager.ListThinRelationWithMaxExpiredAtByGroupPK(
+				groupPK, time.Now().Unix(),
+			)
 			if err != nil {
 				return false, errorWrapf(
 					err,
@@ -101,7 +103,8 @@ func (s *groupService) AlterGroupAuthType(
 			}
 
 			// 查询用户组模版成员
-			relations, err := s.subjectTemplateGroupManager.ListThinRelationWithMaxExpiredAtByGroupPK(groupPK)
+			nowTS := time.Now().Unix()
+			relations, err := s.subjectTemplateGroupManager.ListThinRelationWithMaxExpiredAtByGroupPK(groupPK, nowTS)
 			if err != nil {
 				return false, errorWrapf(
 					err,
@@ -110,10 +113,10 @@ func (s *groupService) AlterGroupAuthType(
 				)
 			}
 
-			nowTS := time.Now().Unix()
 			subjectExpiredAtMap := make(map[int64]int64, len(relations)+len(members))
 			for _, relation := range members {
-				if relation.ExpiredAt < nowTS {
+				// 未生效的成员由checker在生效时添加
+				if relation.ExpiredAt < nowTS || relation.EffectiveAt > nowTS {
 					continue
 				}
 
@@ -399,7 +399,7 @@ var _ = Describe("GroupService", func() {
 			).AnyTimes()
 
 			mockSubjectTemplateGroupManager := mock.NewMockSubjectTemplateGroupManager(ctl)
-			mockSubjectTemplateGroupManager.EXPECT().ListThinRelationWithMaxExpiredAtByGroupPK(int64(1)).Return(
+			mockSubjectTemplateGroupManager.EXPECT().ListThinRelationWithMaxExpiredAtByGroupPK(int64(1), gomock.Any()).Return(
 				[]dao.ThinSubjectRelation{}, nil,
 			)
 
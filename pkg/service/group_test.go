@@ -18,11 +18,9 @@ import (
 
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/golang/mock/gomock"
-	"github.com/jmoiron/sqlx"
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/stretchr/testify/assert"
 
-	"iam/pkg/database"
 	"iam/pkg/database/dao"
 	"iam/pkg/database/dao/mock"
 	"iam/pkg/service/types"
@@ -310,7 +308,7 @@ var _ = Describe("GroupService", func() {
 		})
 	})
 
-	Describe("BulkDeleteGroupMembers", func() {
+	Describe("BulkDeleteGroupMembersWithTx", func() {
 		var ctl *gomock.Controller
 		BeforeEach(func() {
 			ctl = gomock.NewController(GinkgoT())
@@ -325,21 +323,11 @@ var _ = Describe("GroupService", func() {
 				int64(0), errors.New("error"),
 			)
 
-			db, mock := database.NewMockSqlxDB()
-			mock.ExpectBegin()
-			mock.ExpectCommit()
-			tx, _ := db.Beginx()
-
-			patches := gomonkey.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
-				return tx, nil
-			})
-			defer patches.Reset()
-
 			manager := &groupService{
 				manager: mockSubjectService,
 			}
 
-			_, err := manager.BulkDeleteGroupMembers(int64(1), []int64{2}, []int64{3})
+			_, err := manager.BulkDeleteGroupMembersWithTx(nil, int64(1), []int64{2}, []int64{3}, nil)
 			assert.Error(GinkgoT(), err)
 			assert.Contains(GinkgoT(), err.Error(), "BulkDeleteByGroupMembersWithTx")
 		})
@@ -354,36 +342,36 @@ var _ = Describe("GroupService", func() {
 				int64(1), nil,
 			)
 
+			mockSubjectService.EXPECT().BulkDeleteByGroupMembersWithTx(gomock.Any(), int64(1), []int64{4}).Return(
+				int64(1), nil,
+			)
+
 			mockSubjectTemplateGroupManager := mock.NewMockSubjectTemplateGroupManager(ctl)
 			mockSubjectTemplateGroupManager.EXPECT().
-				GetMaxExpiredAtBySubjectGroup(gomock.Any(), gomock.Any(), int64(0)).
+				GetMaxExpiredAtBySubjectGroup(gomock.Any(), gomock.Any(), int64(0), gomock.Any()).
 				Return(
 					time.Now().Unix()+10, nil,
 				).
 				AnyTimes()
 
-			db, mock := database.NewMockSqlxDB()
-			mock.ExpectBegin()
-			mock.ExpectCommit()
-			tx, _ := db.Beginx()
-
-			patches := gomonkey.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
-				return tx, nil
-			})
-
 			manager := &groupService{
 				manager:                     mockSubjectService,
 				subjectTemplateGroupManager: mockSubjectTemplateGroupManager,
 			}
 
-			patches.ApplyMethod(reflect.TypeOf(manager), "ListGroupAuthSystemIDs",
+			patches := gomonkey.ApplyMethod(reflect.TypeOf(manager), "ListGroupAuthSystemIDs",
 				func(s *groupService, groupPK int64) ([]string, error) {
 					return []string{}, nil
 				})
 			defer patches.Reset()
 
-			_, err := manager.BulkDeleteGroupMembers(int64(1), []int64{2}, []int64{3})
+			typeCount, err := manager.BulkDeleteGroupMembersWithTx(nil, int64(1), []int64{2}, []int64{3}, []int64{4})
 			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[string]int64{
+				types.UserType:       1,
+				types.DepartmentType: 1,
+				types.GroupType:      1,
+			}, typeCount)
 		})
 	})
 
@@ -398,7 +386,7 @@ var _ = Describe("GroupService", func() {
 
 		It("manager.ListThinRelationAfterExpiredAtBySubjectPKs fail", func() {
 			mockSubjectService := mock.NewMockSubjectGroupManager(ctl)
-			mockSubjectService.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1, 2}, gomock.Any()).Return(
+			mockSubjectService.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1, 2}, gomock.Any(), gomock.Any()).Return(
 				nil, errors.New("error"),
 			)
 
@@ -413,7 +401,7 @@ var _ = Describe("GroupService", func() {
 
 		It("ok", func() {
 			mockSubjectService := mock.NewMockSubjectGroupManager(ctl)
-			mockSubjectService.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1, 2}, gomock.Any()).Return(
+			mockSubjectService.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1, 2}, gomock.Any(), gomock.Any()).Return(
 				[]dao.ThinSubjectRelation{
 					{
 						GroupPK:   1,
@@ -483,6 +471,11 @@ var _ = Describe("GroupService", func() {
 						SubjectPK: 123,
 						GroupPK:   2,
 						ExpiredAt: 1,
+					}, {
+						SubjectPK:   123,
+						GroupPK:     3,
+						ExpiredAt:   1,
+						EffectiveAt: time.Now().Unix() + 3600,
 					}}, nil,
 				).
 				AnyTimes()
@@ -517,7 +510,7 @@ var _ = Describe("GroupService", func() {
 		It("manager.GetExpiredAtBySubjectGroup fail", func() {
 			mockSubjectService := mock.NewMockSubjectGroupManager(ctl)
 			mockSubjectService.EXPECT().
-				GetExpiredAtBySubjectGroup(int64(1), int64(2)).
+				GetExpiredAtBySubjectGroup(int64(1), int64(2), gomock.Any()).
 				Return(
 					int64(0), errors.New("error"),
 				)
@@ -535,14 +528,14 @@ var _ = Describe("GroupService", func() {
 		It("not found", func() {
 			mockSubjectService := mock.NewMockSubjectGroupManager(ctl)
 			mockSubjectService.EXPECT().
-				GetExpiredAtBySubjectGroup(int64(1), int64(2)).
+				GetExpiredAtBySubjectGroup(int64(1), int64(2), gomock.Any()).
 				Return(
 					int64(0), sql.ErrNoRows,
 				)
 
 			mockSubjectTemplateGroupManager := mock.NewMockSubjectTemplateGroupManager(ctl)
 			mockSubjectTemplateGroupManager.EXPECT().
-				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0)).
+				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0), gomock.Any()).
 				Return(
 					int64(0), sql.ErrNoRows,
 				)
@@ -561,14 +554,14 @@ var _ = Describe("GroupService", func() {
 		It("ok", func() {
 			mockSubjectService := mock.NewMockSubjectGroupManager(ctl)
 			mockSubjectService.EXPECT().
-				GetExpiredAtBySubjectGroup(int64(1), int64(2)).
+				GetExpiredAtBySubjectGroup(int64(1), int64(2), gomock.Any()).
 				Return(
 					int64(10), nil,
 				)
 
 			mockSubjectTemplateGroupManager := mock.NewMockSubjectTemplateGroupManager(ctl)
 			mockSubjectTemplateGroupManager.EXPECT().
-				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0)).
+				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0), gomock.Any()).
 				Return(
 					int64(0), sql.ErrNoRows,
 				)
@@ -586,14 +579,14 @@ var _ = Describe("GroupService", func() {
 		It("ok", func() {
 			mockSubjectService := mock.NewMockSubjectGroupManager(ctl)
 			mockSubjectService.EXPECT().
-				GetExpiredAtBySubjectGroup(int64(1), int64(2)).
+				GetExpiredAtBySubjectGroup(int64(1), int64(2), gomock.Any()).
 				Return(
 					int64(1), nil,
 				)
 
 			mockSubjectTemplateGroupManager := mock.NewMockSubjectTemplateGroupManager(ctl)
 			mockSubjectTemplateGroupManager.EXPECT().
-				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0)).
+				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0), gomock.Any()).
 				Return(
 					int64(10), nil,
 				)
@@ -197,6 +197,36 @@ func (mr *MockActionServiceMockRecorder) ListBySystem(system interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBySystem", reflect.TypeOf((*MockActionService)(nil).ListBySystem), system)
 }
 
+// ListRelatedEnvironmentTypes mocks base method.
+func (m *MockActionService) ListRelatedEnvironmentTypes(system, id string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRelatedEnvironmentTypes", system, id)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRelatedEnvironmentTypes indicates an expected call of ListRelatedEnvironmentTypes.
+func (mr *MockActionServiceMockRecorder) ListRelatedEnvironmentTypes(system, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRelatedEnvironmentTypes", reflect.TypeOf((*MockActionService)(nil).ListRelatedEnvironmentTypes), system, id)
+}
+
+// ListRelatedSubjectAttributes mocks base method.
+func (m *MockActionService) ListRelatedSubjectAttributes(system, id string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRelatedSubjectAttributes", system, id)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRelatedSubjectAttributes indicates an expected call of ListRelatedSubjectAttributes.
+func (mr *MockActionServiceMockRecorder) ListRelatedSubjectAttributes(system, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRelatedSubjectAttributes", reflect.TypeOf((*MockActionService)(nil).ListRelatedSubjectAttributes), system, id)
+}
+
 // ListThinActionByPKs mocks base method.
 func (m *MockActionService) ListThinActionByPKs(pks []int64) ([]types.ThinAction, error) {
 	m.ctrl.T.Helper()
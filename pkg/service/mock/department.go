@@ -77,6 +77,20 @@ func (mr *MockDepartmentServiceMockRecorder) BulkDeleteBySubjectPKsWithTx(tx, pk
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteBySubjectPKsWithTx", reflect.TypeOf((*MockDepartmentService)(nil).BulkDeleteBySubjectPKsWithTx), tx, pks)
 }
 
+// BulkDeleteParents mocks base method.
+func (m *MockDepartmentService) BulkDeleteParents(departmentPKs []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkDeleteParents", departmentPKs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkDeleteParents indicates an expected call of BulkDeleteParents.
+func (mr *MockDepartmentServiceMockRecorder) BulkDeleteParents(departmentPKs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteParents", reflect.TypeOf((*MockDepartmentService)(nil).BulkDeleteParents), departmentPKs)
+}
+
 // BulkUpdate mocks base method.
 func (m *MockDepartmentService) BulkUpdate(subjectDepartments []types.SubjectDepartment) error {
 	m.ctrl.T.Helper()
@@ -91,6 +105,20 @@ func (mr *MockDepartmentServiceMockRecorder) BulkUpdate(subjectDepartments inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdate", reflect.TypeOf((*MockDepartmentService)(nil).BulkUpdate), subjectDepartments)
 }
 
+// BulkUpdateParents mocks base method.
+func (m *MockDepartmentService) BulkUpdateParents(departmentParents []types.DepartmentParent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateParents", departmentParents)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkUpdateParents indicates an expected call of BulkUpdateParents.
+func (mr *MockDepartmentServiceMockRecorder) BulkUpdateParents(departmentParents interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateParents", reflect.TypeOf((*MockDepartmentService)(nil).BulkUpdateParents), departmentParents)
+}
+
 // GetCount mocks base method.
 func (m *MockDepartmentService) GetCount() (int64, error) {
 	m.ctrl.T.Helper()
@@ -121,6 +149,36 @@ func (mr *MockDepartmentServiceMockRecorder) GetSubjectDepartmentPKs(subjectPK i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectDepartmentPKs", reflect.TypeOf((*MockDepartmentService)(nil).GetSubjectDepartmentPKs), subjectPK)
 }
 
+// ListAncestorDepartmentPKs mocks base method.
+func (m *MockDepartmentService) ListAncestorDepartmentPKs(departmentPKs []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAncestorDepartmentPKs", departmentPKs)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAncestorDepartmentPKs indicates an expected call of ListAncestorDepartmentPKs.
+func (mr *MockDepartmentServiceMockRecorder) ListAncestorDepartmentPKs(departmentPKs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAncestorDepartmentPKs", reflect.TypeOf((*MockDepartmentService)(nil).ListAncestorDepartmentPKs), departmentPKs)
+}
+
+// ListDescendantDepartmentPKs mocks base method.
+func (m *MockDepartmentService) ListDescendantDepartmentPKs(departmentPKs []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDescendantDepartmentPKs", departmentPKs)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDescendantDepartmentPKs indicates an expected call of ListDescendantDepartmentPKs.
+func (mr *MockDepartmentServiceMockRecorder) ListDescendantDepartmentPKs(departmentPKs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDescendantDepartmentPKs", reflect.TypeOf((*MockDepartmentService)(nil).ListDescendantDepartmentPKs), departmentPKs)
+}
+
 // ListPaging mocks base method.
 func (m *MockDepartmentService) ListPaging(limit, offset int64) ([]types.SubjectDepartment, error) {
 	m.ctrl.T.Helper()
@@ -135,3 +193,18 @@ func (mr *MockDepartmentServiceMockRecorder) ListPaging(limit, offset interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaging", reflect.TypeOf((*MockDepartmentService)(nil).ListPaging), limit, offset)
 }
+
+// ListSubjectPKsByDepartmentPK mocks base method.
+func (m *MockDepartmentService) ListSubjectPKsByDepartmentPK(departmentPK int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubjectPKsByDepartmentPK", departmentPK)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubjectPKsByDepartmentPK indicates an expected call of ListSubjectPKsByDepartmentPK.
+func (mr *MockDepartmentServiceMockRecorder) ListSubjectPKsByDepartmentPK(departmentPK interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubjectPKsByDepartmentPK", reflect.TypeOf((*MockDepartmentService)(nil).ListSubjectPKsByDepartmentPK), departmentPK)
+}
@@ -35,6 +35,20 @@ func (m *MockGroupService) EXPECT() *MockGroupServiceMockRecorder {
 	return m.recorder
 }
 
+// ActivateSubjectGroups mocks base method.
+func (m *MockGroupService) ActivateSubjectGroups(relations []types.SubjectTemplateGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateSubjectGroups", relations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ActivateSubjectGroups indicates an expected call of ActivateSubjectGroups.
+func (mr *MockGroupServiceMockRecorder) ActivateSubjectGroups(relations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateSubjectGroups", reflect.TypeOf((*MockGroupService)(nil).ActivateSubjectGroups), relations)
+}
+
 // AlterGroupAuthType mocks base method.
 func (m *MockGroupService) AlterGroupAuthType(tx *sqlx.Tx, systemID string, groupPK, authType int64) (bool, error) {
 	m.ctrl.T.Helper()
@@ -106,19 +120,19 @@ func (mr *MockGroupServiceMockRecorder) BulkDeleteBySubjectPKsWithTx(tx, subject
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteBySubjectPKsWithTx", reflect.TypeOf((*MockGroupService)(nil).BulkDeleteBySubjectPKsWithTx), tx, subjectPKs)
 }
 
-// BulkDeleteGroupMembers mocks base method.
-func (m *MockGroupService) BulkDeleteGroupMembers(groupPK int64, userPKs, departmentPKs []int64) (map[string]int64, error) {
+// BulkDeleteGroupMembersWithTx mocks base method.
+func (m *MockGroupService) BulkDeleteGroupMembersWithTx(tx *sqlx.Tx, groupPK int64, userPKs, departmentPKs, memberGroupPKs []int64) (map[string]int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BulkDeleteGroupMembers", groupPK, userPKs, departmentPKs)
+	ret := m.ctrl.Call(m, "BulkDeleteGroupMembersWithTx", tx, groupPK, userPKs, departmentPKs, memberGroupPKs)
 	ret0, _ := ret[0].(map[string]int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// BulkDeleteGroupMembers indicates an expected call of BulkDeleteGroupMembers.
-func (mr *MockGroupServiceMockRecorder) BulkDeleteGroupMembers(groupPK, userPKs, departmentPKs interface{}) *gomock.Call {
+// BulkDeleteGroupMembersWithTx indicates an expected call of BulkDeleteGroupMembersWithTx.
+func (mr *MockGroupServiceMockRecorder) BulkDeleteGroupMembersWithTx(tx, groupPK, userPKs, departmentPKs, memberGroupPKs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteGroupMembers", reflect.TypeOf((*MockGroupService)(nil).BulkDeleteGroupMembers), groupPK, userPKs, departmentPKs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteGroupMembersWithTx", reflect.TypeOf((*MockGroupService)(nil).BulkDeleteGroupMembersWithTx), tx, groupPK, userPKs, departmentPKs, memberGroupPKs)
 }
 
 // BulkDeleteSubjectTemplateGroupWithTx mocks base method.
@@ -149,6 +163,20 @@ func (mr *MockGroupServiceMockRecorder) BulkUpdateSubjectSystemGroupBySubjectTem
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateSubjectSystemGroupBySubjectTemplateGroupWithTx", reflect.TypeOf((*MockGroupService)(nil).BulkUpdateSubjectSystemGroupBySubjectTemplateGroupWithTx), tx, relations)
 }
 
+// CheckNestedGroupMembers mocks base method.
+func (m *MockGroupService) CheckNestedGroupMembers(groupPK int64, memberGroupPKs []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckNestedGroupMembers", groupPK, memberGroupPKs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckNestedGroupMembers indicates an expected call of CheckNestedGroupMembers.
+func (mr *MockGroupServiceMockRecorder) CheckNestedGroupMembers(groupPK, memberGroupPKs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckNestedGroupMembers", reflect.TypeOf((*MockGroupService)(nil).CheckNestedGroupMembers), groupPK, memberGroupPKs)
+}
+
 // GetGroupMemberCount mocks base method.
 func (m *MockGroupService) GetGroupMemberCount(groupPK int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -224,6 +252,21 @@ func (mr *MockGroupServiceMockRecorder) GetMaxExpiredAtBySubjectGroup(subjectPK,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxExpiredAtBySubjectGroup", reflect.TypeOf((*MockGroupService)(nil).GetMaxExpiredAtBySubjectGroup), subjectPK, groupPK, excludeTemplateID)
 }
 
+// GetNestedExpiredAtBySubjectGroup mocks base method.
+func (m *MockGroupService) GetNestedExpiredAtBySubjectGroup(subjectPK, groupPK int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNestedExpiredAtBySubjectGroup", subjectPK, groupPK)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNestedExpiredAtBySubjectGroup indicates an expected call of GetNestedExpiredAtBySubjectGroup.
+func (mr *MockGroupServiceMockRecorder) GetNestedExpiredAtBySubjectGroup(subjectPK, groupPK interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNestedExpiredAtBySubjectGroup", reflect.TypeOf((*MockGroupService)(nil).GetNestedExpiredAtBySubjectGroup), subjectPK, groupPK)
+}
+
 // GetSubjectGroupCountBeforeExpiredAt mocks base method.
 func (m *MockGroupService) GetSubjectGroupCountBeforeExpiredAt(subjectPK, expiredAt int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -254,6 +297,21 @@ func (mr *MockGroupServiceMockRecorder) GetSubjectSystemGroupCountBeforeExpiredA
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectSystemGroupCountBeforeExpiredAt", reflect.TypeOf((*MockGroupService)(nil).GetSubjectSystemGroupCountBeforeExpiredAt), subjectPK, systemID, expiredAt)
 }
 
+// GetSubjectTemplateGroupEffectiveAt mocks base method.
+func (m *MockGroupService) GetSubjectTemplateGroupEffectiveAt(subjectPK, groupPK, templateID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectTemplateGroupEffectiveAt", subjectPK, groupPK, templateID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectTemplateGroupEffectiveAt indicates an expected call of GetSubjectTemplateGroupEffectiveAt.
+func (mr *MockGroupServiceMockRecorder) GetSubjectTemplateGroupEffectiveAt(subjectPK, groupPK, templateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectTemplateGroupEffectiveAt", reflect.TypeOf((*MockGroupService)(nil).GetSubjectTemplateGroupEffectiveAt), subjectPK, groupPK, templateID)
+}
+
 // GetTemplateGroupMemberCount mocks base method.
 func (m *MockGroupService) GetTemplateGroupMemberCount(groupPK, templateID int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -269,6 +327,36 @@ func (mr *MockGroupServiceMockRecorder) GetTemplateGroupMemberCount(groupPK, tem
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateGroupMemberCount", reflect.TypeOf((*MockGroupService)(nil).GetTemplateGroupMemberCount), groupPK, templateID)
 }
 
+// ListAncestorGroupPKs mocks base method.
+func (m *MockGroupService) ListAncestorGroupPKs(groupPK int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAncestorGroupPKs", groupPK)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAncestorGroupPKs indicates an expected call of ListAncestorGroupPKs.
+func (mr *MockGroupServiceMockRecorder) ListAncestorGroupPKs(groupPK interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAncestorGroupPKs", reflect.TypeOf((*MockGroupService)(nil).ListAncestorGroupPKs), groupPK)
+}
+
+// ListEffectGroupPKs mocks base method.
+func (m *MockGroupService) ListEffectGroupPKs(subjectPKs []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEffectGroupPKs", subjectPKs)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEffectGroupPKs indicates an expected call of ListEffectGroupPKs.
+func (mr *MockGroupServiceMockRecorder) ListEffectGroupPKs(subjectPKs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEffectGroupPKs", reflect.TypeOf((*MockGroupService)(nil).ListEffectGroupPKs), subjectPKs)
+}
+
 // ListEffectSubjectGroupsBySubjectPKGroupPKs mocks base method.
 func (m *MockGroupService) ListEffectSubjectGroupsBySubjectPKGroupPKs(subjectPK int64, groupPKs []int64) ([]types.SubjectGroupWithSource, error) {
 	m.ctrl.T.Helper()
@@ -374,6 +462,21 @@ func (mr *MockGroupServiceMockRecorder) ListGroupSubjectBeforeExpiredAtByGroupPK
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGroupSubjectBeforeExpiredAtByGroupPKs", reflect.TypeOf((*MockGroupService)(nil).ListGroupSubjectBeforeExpiredAtByGroupPKs), groupPKs, expiredAt)
 }
 
+// ListNestedGroupMemberSubjectPKs mocks base method.
+func (m *MockGroupService) ListNestedGroupMemberSubjectPKs(groupPKs []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNestedGroupMemberSubjectPKs", groupPKs)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNestedGroupMemberSubjectPKs indicates an expected call of ListNestedGroupMemberSubjectPKs.
+func (mr *MockGroupServiceMockRecorder) ListNestedGroupMemberSubjectPKs(groupPKs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNestedGroupMemberSubjectPKs", reflect.TypeOf((*MockGroupService)(nil).ListNestedGroupMemberSubjectPKs), groupPKs)
+}
+
 // ListPagingGroupMember mocks base method.
 func (m *MockGroupService) ListPagingGroupMember(groupPK, limit, offset int64) ([]types.GroupMember, error) {
 	m.ctrl.T.Helper()
@@ -464,6 +567,51 @@ func (mr *MockGroupServiceMockRecorder) ListPagingTemplateGroupMember(groupPK, t
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPagingTemplateGroupMember", reflect.TypeOf((*MockGroupService)(nil).ListPagingTemplateGroupMember), groupPK, templateID, limit, offset)
 }
 
+// ListPendingSubjectGroupsBeforeEffectiveAt mocks base method.
+func (m *MockGroupService) ListPendingSubjectGroupsBeforeEffectiveAt(effectiveAt, limit int64) ([]types.SubjectTemplateGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingSubjectGroupsBeforeEffectiveAt", effectiveAt, limit)
+	ret0, _ := ret[0].([]types.SubjectTemplateGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingSubjectGroupsBeforeEffectiveAt indicates an expected call of ListPendingSubjectGroupsBeforeEffectiveAt.
+func (mr *MockGroupServiceMockRecorder) ListPendingSubjectGroupsBeforeEffectiveAt(effectiveAt, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingSubjectGroupsBeforeEffectiveAt", reflect.TypeOf((*MockGroupService)(nil).ListPendingSubjectGroupsBeforeEffectiveAt), effectiveAt, limit)
+}
+
+// ListSubDepartmentGroupPKs mocks base method.
+func (m *MockGroupService) ListSubDepartmentGroupPKs(departmentPKs []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubDepartmentGroupPKs", departmentPKs)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubDepartmentGroupPKs indicates an expected call of ListSubDepartmentGroupPKs.
+func (mr *MockGroupServiceMockRecorder) ListSubDepartmentGroupPKs(departmentPKs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubDepartmentGroupPKs", reflect.TypeOf((*MockGroupService)(nil).ListSubDepartmentGroupPKs), departmentPKs)
+}
+
+// ListSubDepartmentMemberPKs mocks base method.
+func (m *MockGroupService) ListSubDepartmentMemberPKs(groupPK int64, departmentPKs []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubDepartmentMemberPKs", groupPK, departmentPKs)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubDepartmentMemberPKs indicates an expected call of ListSubDepartmentMemberPKs.
+func (mr *MockGroupServiceMockRecorder) ListSubDepartmentMemberPKs(groupPK, departmentPKs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubDepartmentMemberPKs", reflect.TypeOf((*MockGroupService)(nil).ListSubDepartmentMemberPKs), groupPK, departmentPKs)
+}
+
 // ListSubjectGroupsBySubjectPKGroupPKs mocks base method.
 func (m *MockGroupService) ListSubjectGroupsBySubjectPKGroupPKs(subjectPK int64, groupPKs []int64) ([]types.SubjectGroup, error) {
 	m.ctrl.T.Helper()
@@ -493,6 +641,20 @@ func (mr *MockGroupServiceMockRecorder) UpdateGroupMembersExpiredAtWithTx(tx, gr
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroupMembersExpiredAtWithTx", reflect.TypeOf((*MockGroupService)(nil).UpdateGroupMembersExpiredAtWithTx), tx, groupPK, members)
 }
 
+// UpdateMembersIncludeSubDepartment mocks base method.
+func (m *MockGroupService) UpdateMembersIncludeSubDepartment(groupPK int64, departmentPKs []int64, includeSubDepartment bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMembersIncludeSubDepartment", groupPK, departmentPKs, includeSubDepartment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMembersIncludeSubDepartment indicates an expected call of UpdateMembersIncludeSubDepartment.
+func (mr *MockGroupServiceMockRecorder) UpdateMembersIncludeSubDepartment(groupPK, departmentPKs, includeSubDepartment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMembersIncludeSubDepartment", reflect.TypeOf((*MockGroupService)(nil).UpdateMembersIncludeSubDepartment), groupPK, departmentPKs, includeSubDepartment)
+}
+
 // UpdateSubjectGroupExpiredAtWithTx mocks base method.
 func (m *MockGroupService) UpdateSubjectGroupExpiredAtWithTx(tx *sqlx.Tx, relations []types.SubjectTemplateGroup, updateSubjectRelation bool) error {
 	m.ctrl.T.Helper()
@@ -91,6 +91,21 @@ func (mr *MockGroupAlterEventServiceMockRecorder) CreateBySubjectActionGroup(sub
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBySubjectActionGroup", reflect.TypeOf((*MockGroupAlterEventService)(nil).CreateBySubjectActionGroup), subjectPK, actionPK, groupPK)
 }
 
+// ListAffectedSubjectPKs mocks base method.
+func (m *MockGroupAlterEventService) ListAffectedSubjectPKs(groupPK int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAffectedSubjectPKs", groupPK)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAffectedSubjectPKs indicates an expected call of ListAffectedSubjectPKs.
+func (mr *MockGroupAlterEventServiceMockRecorder) ListAffectedSubjectPKs(groupPK interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAffectedSubjectPKs", reflect.TypeOf((*MockGroupAlterEventService)(nil).ListAffectedSubjectPKs), groupPK)
+}
+
 // ListBeforeCreateAt mocks base method.
 func (m *MockGroupAlterEventService) ListBeforeCreateAt(createdAt, limit int64) ([]types.GroupAlterEvent, error) {
 	m.ctrl.T.Helper()
@@ -105,3 +120,18 @@ func (mr *MockGroupAlterEventServiceMockRecorder) ListBeforeCreateAt(createdAt,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBeforeCreateAt", reflect.TypeOf((*MockGroupAlterEventService)(nil).ListBeforeCreateAt), createdAt, limit)
 }
+
+// ListGroupActionPKs mocks base method.
+func (m *MockGroupAlterEventService) ListGroupActionPKs(groupPK int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGroupActionPKs", groupPK)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGroupActionPKs indicates an expected call of ListGroupActionPKs.
+func (mr *MockGroupAlterEventServiceMockRecorder) ListGroupActionPKs(groupPK interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGroupActionPKs", reflect.TypeOf((*MockGroupAlterEventService)(nil).ListGroupActionPKs), groupPK)
+}
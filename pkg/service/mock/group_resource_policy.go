@@ -37,12 +37,13 @@ func (m *MockGroupResourcePolicyService) EXPECT() *MockGroupResourcePolicyServic
 }
 
 // Alter mocks base method.
-func (m *MockGroupResourcePolicyService) Alter(tx *sqlx.Tx, groupPK, templateID int64, systemID string, systemActionPKSet *set.Int64Set, resourceChangedContents []types.ResourceChangedContent) ([]int64, error) {
+func (m *MockGroupResourcePolicyService) Alter(tx *sqlx.Tx, groupPK, templateID int64, systemID string, systemActionPKSet *set.Int64Set, resourceChangedContents []types.ResourceChangedContent) ([]int64, []types.ResourceChangedContent, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Alter", tx, groupPK, templateID, systemID, systemActionPKSet, resourceChangedContents)
 	ret0, _ := ret[0].([]int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].([]types.ResourceChangedContent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Alter indicates an expected call of Alter.
@@ -80,10 +81,10 @@ func (mr *MockGroupResourcePolicyServiceMockRecorder) DeleteByActionPKWithTx(tx,
 }
 
 // GetAuthorizedActionGroupMap mocks base method.
-func (m *MockGroupResourcePolicyService) GetAuthorizedActionGroupMap(systemID string, actionResourceTypePK, resourceTypePK int64, resourceTypeID string) (map[int64][]int64, error) {
+func (m *MockGroupResourcePolicyService) GetAuthorizedActionGroupMap(systemID string, actionResourceTypePK, resourceTypePK int64, resourceTypeID string) (map[int64][]types.AuthorizedGroup, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAuthorizedActionGroupMap", systemID, actionResourceTypePK, resourceTypePK, resourceTypeID)
-	ret0, _ := ret[0].(map[int64][]int64)
+	ret0, _ := ret[0].(map[int64][]types.AuthorizedGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -94,6 +95,21 @@ func (mr *MockGroupResourcePolicyServiceMockRecorder) GetAuthorizedActionGroupMa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizedActionGroupMap", reflect.TypeOf((*MockGroupResourcePolicyService)(nil).GetAuthorizedActionGroupMap), systemID, actionResourceTypePK, resourceTypePK, resourceTypeID)
 }
 
+// ListPagingBySystem mocks base method.
+func (m *MockGroupResourcePolicyService) ListPagingBySystem(systemID string, lastPK, limit int64) ([]types.GroupResourcePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPagingBySystem", systemID, lastPK, limit)
+	ret0, _ := ret[0].([]types.GroupResourcePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPagingBySystem indicates an expected call of ListPagingBySystem.
+func (mr *MockGroupResourcePolicyServiceMockRecorder) ListPagingBySystem(systemID, lastPK, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPagingBySystem", reflect.TypeOf((*MockGroupResourcePolicyService)(nil).ListPagingBySystem), systemID, lastPK, limit)
+}
+
 // ListResourceByGroupAction mocks base method.
 func (m *MockGroupResourcePolicyService) ListResourceByGroupAction(groupPK int64, systemID string, actionPK, actionResourceTypePK int64) ([]types.Resource, error) {
 	m.ctrl.T.Helper()
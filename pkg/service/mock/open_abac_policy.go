@@ -64,6 +64,21 @@ func (mr *MockOpenAbacPolicyServiceMockRecorder) GetCountByActionBeforeExpiredAt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountByActionBeforeExpiredAt", reflect.TypeOf((*MockOpenAbacPolicyService)(nil).GetCountByActionBeforeExpiredAt), actionPK, expiredAt)
 }
 
+// ListAllPagingByActionBeforeExpiredAt mocks base method.
+func (m *MockOpenAbacPolicyService) ListAllPagingByActionBeforeExpiredAt(actionPK, expiredAt, offset, limit int64) ([]types.OpenAbacPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllPagingByActionBeforeExpiredAt", actionPK, expiredAt, offset, limit)
+	ret0, _ := ret[0].([]types.OpenAbacPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllPagingByActionBeforeExpiredAt indicates an expected call of ListAllPagingByActionBeforeExpiredAt.
+func (mr *MockOpenAbacPolicyServiceMockRecorder) ListAllPagingByActionBeforeExpiredAt(actionPK, expiredAt, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllPagingByActionBeforeExpiredAt", reflect.TypeOf((*MockOpenAbacPolicyService)(nil).ListAllPagingByActionBeforeExpiredAt), actionPK, expiredAt, offset, limit)
+}
+
 // ListByPKs mocks base method.
 func (m *MockOpenAbacPolicyService) ListByPKs(pks []int64) ([]types.OpenAbacPolicy, error) {
 	m.ctrl.T.Helper()
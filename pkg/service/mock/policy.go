@@ -122,6 +122,20 @@ func (mr *MockPolicyServiceMockRecorder) DeleteByPKs(subjectPK, pks interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPKs", reflect.TypeOf((*MockPolicyService)(nil).DeleteByPKs), subjectPK, pks)
 }
 
+// DeleteByPKsWithTx mocks base method.
+func (m *MockPolicyService) DeleteByPKsWithTx(tx *sqlx.Tx, subjectPK int64, pks []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByPKsWithTx", tx, subjectPK, pks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByPKsWithTx indicates an expected call of DeleteByPKsWithTx.
+func (mr *MockPolicyServiceMockRecorder) DeleteByPKsWithTx(tx, subjectPK, pks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPKsWithTx", reflect.TypeOf((*MockPolicyService)(nil).DeleteByPKsWithTx), tx, subjectPK, pks)
+}
+
 // DeleteUnreferencedExpressions mocks base method.
 func (m *MockPolicyService) DeleteUnreferencedExpressions() error {
 	m.ctrl.T.Helper()
@@ -166,6 +180,36 @@ func (mr *MockPolicyServiceMockRecorder) ListAuthBySubjectAction(subjectPKs, act
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthBySubjectAction", reflect.TypeOf((*MockPolicyService)(nil).ListAuthBySubjectAction), subjectPKs, actionPK)
 }
 
+// ListBySubjectPKAndPKs mocks base method.
+func (m *MockPolicyService) ListBySubjectPKAndPKs(subjectPK int64, pks []int64) ([]types.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBySubjectPKAndPKs", subjectPK, pks)
+	ret0, _ := ret[0].([]types.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBySubjectPKAndPKs indicates an expected call of ListBySubjectPKAndPKs.
+func (mr *MockPolicyServiceMockRecorder) ListBySubjectPKAndPKs(subjectPK, pks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBySubjectPKAndPKs", reflect.TypeOf((*MockPolicyService)(nil).ListBySubjectPKAndPKs), subjectPK, pks)
+}
+
+// ListExpiredAuthBySubjectAction mocks base method.
+func (m *MockPolicyService) ListExpiredAuthBySubjectAction(subjectPKs []int64, actionPK int64) ([]types.AuthPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredAuthBySubjectAction", subjectPKs, actionPK)
+	ret0, _ := ret[0].([]types.AuthPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredAuthBySubjectAction indicates an expected call of ListExpiredAuthBySubjectAction.
+func (mr *MockPolicyServiceMockRecorder) ListExpiredAuthBySubjectAction(subjectPKs, actionPK interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredAuthBySubjectAction", reflect.TypeOf((*MockPolicyService)(nil).ListExpiredAuthBySubjectAction), subjectPKs, actionPK)
+}
+
 // ListExpressionByPKs mocks base method.
 func (m *MockPolicyService) ListExpressionByPKs(pks []int64) ([]types.AuthExpression, error) {
 	m.ctrl.T.Helper()
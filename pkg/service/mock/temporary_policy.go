@@ -77,6 +77,21 @@ func (mr *MockTemporaryPolicyServiceMockRecorder) DeleteByPKs(subjectPK, pks int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPKs", reflect.TypeOf((*MockTemporaryPolicyService)(nil).DeleteByPKs), subjectPK, pks)
 }
 
+// ListByActionPKsBeforeExpiredAt mocks base method.
+func (m *MockTemporaryPolicyService) ListByActionPKsBeforeExpiredAt(actionPKs []int64, expiredAt int64) ([]types.TemporaryPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByActionPKsBeforeExpiredAt", actionPKs, expiredAt)
+	ret0, _ := ret[0].([]types.TemporaryPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByActionPKsBeforeExpiredAt indicates an expected call of ListByActionPKsBeforeExpiredAt.
+func (mr *MockTemporaryPolicyServiceMockRecorder) ListByActionPKsBeforeExpiredAt(actionPKs, expiredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByActionPKsBeforeExpiredAt", reflect.TypeOf((*MockTemporaryPolicyService)(nil).ListByActionPKsBeforeExpiredAt), actionPKs, expiredAt)
+}
+
 // ListByPKs mocks base method.
 func (m *MockTemporaryPolicyService) ListByPKs(pks []int64) ([]types.TemporaryPolicy, error) {
 	m.ctrl.T.Helper()
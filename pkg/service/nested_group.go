@@ -0,0 +1,416 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/database/dao"
+	"iam/pkg/service/types"
+)
+
+// DefaultMaxGroupNestingDepth 用户组默认最大嵌套层数
+const DefaultMaxGroupNestingDepth = 3
+
+var (
+	// ErrGroupNestingCycle 嵌套用户组成环
+	ErrGroupNestingCycle = errors.New("group nesting cycle")
+	// ErrGroupNestingTooDeep 嵌套用户组超过最大层数
+	ErrGroupNestingTooDeep = errors.New("group nesting too deep")
+)
+
+var maxGroupNestingDepth = DefaultMaxGroupNestingDepth
+
+// InitMaxGroupNestingDepth 初始化用户组最大嵌套层数, 小于等于0时使用默认值
+func InitMaxGroupNestingDepth(depth int) {
+	if depth <= 0 {
+		depth = DefaultMaxGroupNestingDepth
+	}
+	maxGroupNestingDepth = depth
+}
+
+// MaxGroupNestingDepth 用户组最大嵌套层数
+func MaxGroupNestingDepth() int {
+	return maxGroupNestingDepth
+}
+
+// walkNestedGroups 从groupPKs开始逐层遍历嵌套用户组, 返回遍历到的用户组(不包括groupPKs本身)及最大层数
+// NOTE: 最多遍历 maxGroupNestingDepth+1 层, 既能判断是否超过最大层数, 也避免数据中存在环时无限遍历
+func walkNestedGroups(
+	groupPKs []int64,
+	listNext func(groupPKs []int64) ([]int64, error),
+) (groups *set.Int64Set, depth int, err error) {
+	groups = set.NewInt64Set()
+
+	current := groupPKs
+	for depth <= maxGroupNestingDepth && len(current) > 0 {
+		next, err := listNext(current)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(next) == 0 {
+			break
+		}
+
+		depth++
+		groups.Append(next...)
+		current = next
+	}
+	return groups, depth, nil
+}
+
+// listParentGroupPKs 查询groups直接加入的上级用户组
+func listParentGroupPKs(manager dao.SubjectGroupManager, groupPKs []int64) ([]int64, error) {
+	relations, err := manager.ListThinRelationAfterExpiredAtBySubjectPKs(groupPKs, 0, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	parentPKs := set.NewInt64Set()
+	for _, r := range relations {
+		parentPKs.Add(r.GroupPK)
+	}
+	return parentPKs.ToSlice(), nil
+}
+
+// isEffectiveRelation 成员关系已到生效时间且未过期
+func isEffectiveRelation(expiredAt, effectiveAt, now int64) bool {
+	return expiredAt > now && effectiveAt <= now
+}
+
+// listMemberGroupPKs 查询groups中直接包含的下级用户组
+// NOTE: 用于检查嵌套结构(成环/层数), 包含已过期与未到生效时间的关系
+func listMemberGroupPKs(manager dao.SubjectGroupManager, groupPKs []int64) ([]int64, error) {
+	relations, err := manager.ListMemberGroupRelationsByGroupPKs(groupPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	memberPKs := set.NewInt64Set()
+	for _, r := range relations {
+		memberPKs.Add(r.SubjectPK)
+	}
+	return memberPKs.ToSlice(), nil
+}
+
+// listEffectiveMemberGroupPKs 查询groups中直接包含的, 已生效且未过期的下级用户组
+func listEffectiveMemberGroupPKs(manager dao.SubjectGroupManager, groupPKs []int64, now int64) ([]int64, error) {
+	relations, err := manager.ListMemberGroupRelationsByGroupPKs(groupPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	memberPKs := set.NewInt64Set()
+	for _, r := range relations {
+		if isEffectiveRelation(r.ExpiredAt, r.EffectiveAt, now) {
+			memberPKs.Add(r.SubjectPK)
+		}
+	}
+	return memberPKs.ToSlice(), nil
+}
+
+// listNestedGroupMemberSubjectPKs 查询groups及其嵌套的下级用户组的所有已生效且未过期的成员, 不包括用户组类型的成员
+// NOTE: 包含子部门的部门成员, 其所有下级部门也会返回
+// NOTE: 未到生效时间的成员关系在生效时由effective checker生成事件并清理缓存, 这里不需要包含
+func listNestedGroupMemberSubjectPKs(
+	manager dao.SubjectGroupManager,
+	treeManager dao.DepartmentTreeManager,
+	groupPKs []int64,
+) ([]int64, error) {
+	now := time.Now().Unix()
+	descendants, _, err := walkNestedGroups(groupPKs, func(pks []int64) ([]int64, error) {
+		return listEffectiveMemberGroupPKs(manager, pks, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groupPKSet := set.NewInt64SetWithValues(groupPKs)
+	groupPKSet.Append(descendants.ToSlice()...)
+
+	subjectPKSet := set.NewInt64Set()
+	for _, groupPK := range groupPKSet.ToSlice() {
+		members, err := manager.ListGroupMember(groupPK)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range members {
+			if !groupPKSet.Has(m.SubjectPK) && isEffectiveRelation(m.ExpiredAt, m.EffectiveAt, now) {
+				subjectPKSet.Add(m.SubjectPK)
+			}
+		}
+	}
+
+	subDepartmentPKs, err := listSubDepartmentMemberPKs(manager, treeManager, groupPKSet.ToSlice(), nil)
+	if err != nil {
+		return nil, err
+	}
+	subjectPKSet.Append(subDepartmentPKs...)
+
+	return subjectPKSet.ToSlice(), nil
+}
+
+// CheckNestedGroupMembers 检查用户组加入groupPK后是否成环或超过最大嵌套层数
+func (l *groupService) CheckNestedGroupMembers(groupPK int64, memberGroupPKs []int64) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupSVC, "CheckNestedGroupMembers")
+
+	ancestors, up, err := walkNestedGroups([]int64{groupPK}, l.listParentGroupPKs)
+	if err != nil {
+		return errorWrapf(err, "walkNestedGroups ancestors groupPK=`%d` fail", groupPK)
+	}
+
+	for _, memberGroupPK := range memberGroupPKs {
+		if memberGroupPK == groupPK || ancestors.Has(memberGroupPK) {
+			return errorWrapf(ErrGroupNestingCycle, "groupPK=`%d`, memberGroupPK=`%d`", groupPK, memberGroupPK)
+		}
+
+		_, down, err := walkNestedGroups([]int64{memberGroupPK}, l.listMemberGroupPKs)
+		if err != nil {
+			return errorWrapf(err, "walkNestedGroups descendants groupPK=`%d` fail", memberGroupPK)
+		}
+
+		if up+1+down > maxGroupNestingDepth {
+			return errorWrapf(
+				ErrGroupNestingTooDeep,
+				"groupPK=`%d`, memberGroupPK=`%d`, depth=`%d`, max=`%d`",
+				groupPK, memberGroupPK, up+1+down, maxGroupNestingDepth,
+			)
+		}
+	}
+
+	return nil
+}
+
+// ListAncestorGroupPKs 查询group通过嵌套加入的所有上级用户组
+func (l *groupService) ListAncestorGroupPKs(groupPK int64) ([]int64, error) {
+	ancestors, _, err := walkNestedGroups([]int64{groupPK}, l.listParentGroupPKs)
+	if err != nil {
+		return nil, errorx.Wrapf(err, GroupSVC, "ListAncestorGroupPKs", "groupPK=`%d`", groupPK)
+	}
+
+	// 数据中存在环时, 遍历结果会包含group本身
+	delete(ancestors.Data, groupPK)
+	return ancestors.ToSlice(), nil
+}
+
+// ListNestedGroupMemberSubjectPKs 查询groups及其嵌套的下级用户组的所有user/department成员
+func (l *groupService) ListNestedGroupMemberSubjectPKs(groupPKs []int64) ([]int64, error) {
+	subjectPKs, err := listNestedGroupMemberSubjectPKs(l.manager, l.departmentTreeManager, groupPKs)
+	if err != nil {
+		return nil, errorx.Wrapf(err, GroupSVC, "ListNestedGroupMemberSubjectPKs", "groupPKs=`%+v`", groupPKs)
+	}
+	return subjectPKs, nil
+}
+
+// ListEffectGroupPKs 查询subjects直接加入, 以及通过嵌套用户组或上级部门继承的所有未过期的用户组
+func (l *groupService) ListEffectGroupPKs(subjectPKs []int64) ([]int64, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(GroupSVC, "ListEffectGroupPKs")
+	now := time.Now().Unix()
+
+	relations, err := l.manager.ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs, now, now)
+	if err != nil {
+		return nil, errorWrapf(
+			err, "manager.ListThinRelationAfterExpiredAtBySubjectPKs subjectPKs=`%+v` fail", subjectPKs,
+		)
+	}
+
+	groupPKs := set.NewInt64Set()
+	for _, r := range relations {
+		groupPKs.Add(r.GroupPK)
+	}
+
+	inherited, err := l.listInheritedSubjectGroups(subjectPKs, now)
+	if err != nil {
+		return nil, errorWrapf(err, "listInheritedSubjectGroups subjectPKs=`%+v` fail", subjectPKs)
+	}
+	for _, groups := range inherited {
+		for groupPK := range groups {
+			groupPKs.Add(groupPK)
+		}
+	}
+	return groupPKs.ToSlice(), nil
+}
+
+// GetNestedExpiredAtBySubjectGroup 查询subject通过嵌套用户组或上级部门继承group的过期时间, 没有继承关系时返回0
+func (l *groupService) GetNestedExpiredAtBySubjectGroup(subjectPK, groupPK int64) (int64, error) {
+	inherited, err := l.listInheritedSubjectGroups([]int64{subjectPK}, time.Now().Unix())
+	if err != nil {
+		return 0, errorx.Wrapf(
+			err, GroupSVC, "GetNestedExpiredAtBySubjectGroup", "subjectPK=`%d`, groupPK=`%d`", subjectPK, groupPK,
+		)
+	}
+
+	return inherited[subjectPK][groupPK], nil
+}
+
+func (l *groupService) listParentGroupPKs(groupPKs []int64) ([]int64, error) {
+	return listParentGroupPKs(l.manager, groupPKs)
+}
+
+func (l *groupService) listMemberGroupPKs(groupPKs []int64) ([]int64, error) {
+	return listMemberGroupPKs(l.manager, groupPKs)
+}
+
+// listInheritedSubjectGroups 查询subjects通过嵌套用户组或上级部门(包含子部门)间接加入的用户组
+// 过期时间取链路上最小的, 多条链路时取最大的
+func (l *groupService) listInheritedSubjectGroups(
+	subjectPKs []int64,
+	now int64,
+) (inherited map[int64]map[int64]int64, err error) {
+	inherited = make(map[int64]map[int64]int64)
+
+	relations, err := l.manager.ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// subjectPK -> groupPK -> expiredAt, 当前层的用户组
+	current := make(map[int64]map[int64]int64)
+	for _, r := range relations {
+		setMaxExpiredAt(current, r.SubjectPK, r.GroupPK, r.ExpiredAt)
+	}
+
+	// 部门通过上级部门包含子部门的成员关系继承的用户组, 同样继承其嵌套的上级用户组
+	subDepartmentRelations, err := l.listSubDepartmentInheritedRelations(subjectPKs, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range subDepartmentRelations {
+		setMaxExpiredAt(inherited, r.SubjectPK, r.GroupPK, r.ExpiredAt)
+		setMaxExpiredAt(current, r.SubjectPK, r.GroupPK, r.ExpiredAt)
+	}
+
+	for depth := 0; depth < maxGroupNestingDepth && len(current) > 0; depth++ {
+		groupPKSet := set.NewInt64Set()
+		for _, groups := range current {
+			for groupPK := range groups {
+				groupPKSet.Add(groupPK)
+			}
+		}
+
+		parentRelations, err := l.manager.ListThinRelationAfterExpiredAtBySubjectPKs(
+			groupPKSet.ToSlice(), now, now,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(parentRelations) == 0 {
+			break
+		}
+
+		groupParents := make(map[int64][]dao.ThinSubjectRelation, len(parentRelations))
+		for _, r := range parentRelations {
+			groupParents[r.SubjectPK] = append(groupParents[r.SubjectPK], r)
+		}
+
+		next := make(map[int64]map[int64]int64)
+		for subjectPK, groups := range current {
+			for groupPK, expiredAt := range groups {
+				for _, parent := range groupParents[groupPK] {
+					parentExpiredAt := expiredAt
+					if parent.ExpiredAt < parentExpiredAt {
+						parentExpiredAt = parent.ExpiredAt
+					}
+
+					// 已经有过期时间更长的链路, 不需要继续向上查找
+					if old, ok := inherited[subjectPK][parent.GroupPK]; ok && old >= parentExpiredAt {
+						continue
+					}
+
+					setMaxExpiredAt(inherited, subjectPK, parent.GroupPK, parentExpiredAt)
+					setMaxExpiredAt(next, subjectPK, parent.GroupPK, parentExpiredAt)
+				}
+			}
+		}
+		current = next
+	}
+
+	return inherited, nil
+}
+
+// mergeInheritedSubjectGroups 将subjects通过嵌套用户组或上级部门继承的, 在系统中有授权的用户组合并到subjectGroups中
+func (l *groupService) mergeInheritedSubjectGroups(
+	systemID string,
+	subjectPKs []int64,
+	now int64,
+	subjectGroups map[int64][]types.ThinSubjectGroup,
+) error {
+	inherited, err := l.listInheritedSubjectGroups(subjectPKs, now)
+	if err != nil {
+		return err
+	}
+	if len(inherited) == 0 {
+		return nil
+	}
+
+	groupPKSet := set.NewInt64Set()
+	for _, groups := range inherited {
+		for groupPK := range groups {
+			groupPKSet.Add(groupPK)
+		}
+	}
+
+	groupAuthTypes, err := l.ListGroupAuthBySystemGroupPKs(systemID, groupPKSet.ToSlice())
+	if err != nil {
+		return err
+	}
+
+	authorizedGroupPKs := set.NewInt64Set()
+	for _, g := range groupAuthTypes {
+		if g.AuthType != types.AuthTypeNone {
+			authorizedGroupPKs.Add(g.GroupPK)
+		}
+	}
+
+	for subjectPK, groups := range inherited {
+		groupIndex := make(map[int64]int, len(subjectGroups[subjectPK]))
+		for i, g := range subjectGroups[subjectPK] {
+			groupIndex[g.GroupPK] = i
+		}
+
+		for groupPK, expiredAt := range groups {
+			if !authorizedGroupPKs.Has(groupPK) {
+				continue
+			}
+
+			if i, ok := groupIndex[groupPK]; ok {
+				if subjectGroups[subjectPK][i].ExpiredAt < expiredAt {
+					subjectGroups[subjectPK][i].ExpiredAt = expiredAt
+				}
+				continue
+			}
+
+			subjectGroups[subjectPK] = append(subjectGroups[subjectPK], types.ThinSubjectGroup{
+				GroupPK:   groupPK,
+				ExpiredAt: expiredAt,
+			})
+		}
+	}
+
+	return nil
+}
+
+func setMaxExpiredAt(m map[int64]map[int64]int64, subjectPK, groupPK, expiredAt int64) {
+	groups, ok := m[subjectPK]
+	if !ok {
+		groups = make(map[int64]int64)
+		m[subjectPK] = groups
+	}
+
+	if old, ok := groups[groupPK]; !ok || old < expiredAt {
+		groups[groupPK] = expiredAt
+	}
+}
@@ -0,0 +1,182 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database/dao"
+	"iam/pkg/database/dao/mock"
+)
+
+var _ = Describe("NestedGroup", func() {
+	var ctl *gomock.Controller
+	var mockManager *mock.MockSubjectGroupManager
+	var mockDepartmentTreeManager *mock.MockDepartmentTreeManager
+	var manager *groupService
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockManager = mock.NewMockSubjectGroupManager(ctl)
+		mockDepartmentTreeManager = mock.NewMockDepartmentTreeManager(ctl)
+		manager = &groupService{manager: mockManager, departmentTreeManager: mockDepartmentTreeManager}
+	})
+	AfterEach(func() {
+		ctl.Finish()
+		InitMaxGroupNestingDepth(0)
+	})
+
+	Describe("InitMaxGroupNestingDepth", func() {
+		It("default", func() {
+			InitMaxGroupNestingDepth(-1)
+			assert.Equal(GinkgoT(), DefaultMaxGroupNestingDepth, maxGroupNestingDepth)
+		})
+
+		It("ok", func() {
+			InitMaxGroupNestingDepth(5)
+			assert.Equal(GinkgoT(), 5, maxGroupNestingDepth)
+		})
+	})
+
+	Describe("CheckNestedGroupMembers", func() {
+		It("self cycle", func() {
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, int64(0), gomock.Any()).Return(nil, nil)
+
+			err := manager.CheckNestedGroupMembers(1, []int64{1})
+			assert.ErrorIs(GinkgoT(), err, ErrGroupNestingCycle)
+		})
+
+		It("ancestor cycle", func() {
+			// 1 -> 2, 2 加入 1 后成环
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, int64(0), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 1, GroupPK: 2}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{2}, int64(0), gomock.Any()).Return(nil, nil)
+
+			err := manager.CheckNestedGroupMembers(1, []int64{2})
+			assert.ErrorIs(GinkgoT(), err, ErrGroupNestingCycle)
+		})
+
+		It("too deep", func() {
+			InitMaxGroupNestingDepth(2)
+
+			// 1 -> 2, 3 加入 1, 4 是 3 的成员, 层数为 3
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, int64(0), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 1, GroupPK: 2}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{2}, int64(0), gomock.Any()).Return(nil, nil)
+			mockManager.EXPECT().ListMemberGroupRelationsByGroupPKs([]int64{3}).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 4, GroupPK: 3}}, nil,
+			)
+			mockManager.EXPECT().ListMemberGroupRelationsByGroupPKs([]int64{4}).Return(nil, nil)
+
+			err := manager.CheckNestedGroupMembers(1, []int64{3})
+			assert.ErrorIs(GinkgoT(), err, ErrGroupNestingTooDeep)
+		})
+
+		It("fail", func() {
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, int64(0), gomock.Any()).Return(
+				nil, errors.New("error"),
+			)
+
+			err := manager.CheckNestedGroupMembers(1, []int64{3})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "walkNestedGroups")
+		})
+
+		It("ok", func() {
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, int64(0), gomock.Any()).Return(nil, nil)
+			mockManager.EXPECT().ListMemberGroupRelationsByGroupPKs([]int64{3}).Return(nil, nil)
+
+			err := manager.CheckNestedGroupMembers(1, []int64{3})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("ListAncestorGroupPKs", func() {
+		It("ok", func() {
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, int64(0), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 1, GroupPK: 2}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{2}, int64(0), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 2, GroupPK: 3}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{3}, int64(0), gomock.Any()).Return(nil, nil)
+
+			groupPKs, err := manager.ListAncestorGroupPKs(1)
+			assert.NoError(GinkgoT(), err)
+			assert.ElementsMatch(GinkgoT(), []int64{2, 3}, groupPKs)
+		})
+	})
+
+	Describe("ListNestedGroupMemberSubjectPKs", func() {
+		It("ok", func() {
+			expiredAt := time.Now().Unix() + 100
+			effectiveAt := time.Now().Unix() + 10
+
+			// 未到生效时间的下级用户组3不展开
+			mockManager.EXPECT().ListMemberGroupRelationsByGroupPKs([]int64{1}).Return(
+				[]dao.ThinSubjectRelation{
+					{SubjectPK: 2, GroupPK: 1, ExpiredAt: expiredAt},
+					{SubjectPK: 3, GroupPK: 1, ExpiredAt: expiredAt, EffectiveAt: effectiveAt},
+				}, nil,
+			)
+			mockManager.EXPECT().ListMemberGroupRelationsByGroupPKs([]int64{2}).Return(nil, nil)
+			mockManager.EXPECT().ListGroupMember(int64(1)).Return(
+				[]dao.SubjectRelation{
+					{SubjectPK: 11, ExpiredAt: expiredAt},
+					{SubjectPK: 2, ExpiredAt: expiredAt},
+					// 已过期
+					{SubjectPK: 12, ExpiredAt: 1},
+					// 未到生效时间
+					{SubjectPK: 13, ExpiredAt: expiredAt, EffectiveAt: effectiveAt},
+				}, nil,
+			)
+			mockManager.EXPECT().ListGroupMember(int64(2)).Return(
+				[]dao.SubjectRelation{{SubjectPK: 21, ExpiredAt: expiredAt}}, nil,
+			)
+			mockManager.EXPECT().ListSubDepartmentRelationsByGroupPKs(gomock.Any()).Return(nil, nil)
+
+			subjectPKs, err := manager.ListNestedGroupMemberSubjectPKs([]int64{1})
+			assert.NoError(GinkgoT(), err)
+			assert.ElementsMatch(GinkgoT(), []int64{11, 21}, subjectPKs)
+		})
+	})
+
+	Describe("GetNestedExpiredAtBySubjectGroup", func() {
+		It("ok", func() {
+			ts := time.Now().Unix() + 10
+
+			mockDepartmentTreeManager.EXPECT().ListByDepartmentPKs([]int64{1}).Return(nil, nil).Times(2)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, gomock.Any(), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 1, GroupPK: 2, ExpiredAt: ts}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{2}, gomock.Any(), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 2, GroupPK: 3, ExpiredAt: ts + 10}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{3}, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+			expiredAt, err := manager.GetNestedExpiredAtBySubjectGroup(1, 3)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), ts, expiredAt)
+
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, gomock.Any(), gomock.Any()).Return(nil, nil)
+			expiredAt, err = manager.GetNestedExpiredAtBySubjectGroup(1, 3)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), int64(0), expiredAt)
+		})
+	})
+})
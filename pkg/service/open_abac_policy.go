@@ -11,6 +11,9 @@
 package service
 
 import (
+	"math"
+	"time"
+
 	"github.com/TencentBlueKing/gopkg/errorx"
 
 	"iam/pkg/database/dao"
@@ -24,6 +27,8 @@ type OpenAbacPolicyService interface {
 	Get(pk int64) (types.OpenAbacPolicy, error)
 	ListPagingByActionBeforeExpiredAt(
 		actionPK int64, expiredAt int64, offset int64, limit int64) ([]types.OpenAbacPolicy, error)
+	ListAllPagingByActionBeforeExpiredAt(
+		actionPK int64, expiredAt int64, offset int64, limit int64) ([]types.OpenAbacPolicy, error)
 	GetCountByActionBeforeExpiredAt(actionPK int64, expiredAt int64) (int64, error)
 
 	ListByPKs(pks []int64) ([]types.OpenAbacPolicy, error)
@@ -55,16 +60,19 @@ func (s *openAbacPolicyService) Get(pk int64) (daoPolicy types.OpenAbacPolicy, e
 		ActionPK:     policy.ActionPK,
 		ExpressionPK: policy.ExpressionPK,
 		ExpiredAt:    policy.ExpiredAt,
+		TemplateID:   policy.TemplateID,
+		Effect:       policy.Effect,
+		EffectiveAt:  policy.EffectiveAt,
 	}
 	return
 }
 
-// GetCountByActionBeforeExpiredAt ...
+// GetCountByActionBeforeExpiredAt 不包含未到生效时间的策略
 func (s *openAbacPolicyService) GetCountByActionBeforeExpiredAt(actionPK int64, expiredAt int64) (int64, error) {
-	return s.manager.GetCountByActionBeforeExpiredAt(actionPK, expiredAt)
+	return s.manager.GetCountByActionBeforeExpiredAt(actionPK, expiredAt, time.Now().Unix())
 }
 
-// ListPagingByActionBeforeExpiredAt ...
+// ListPagingByActionBeforeExpiredAt 不包含未到生效时间的策略
 func (s *openAbacPolicyService) ListPagingByActionBeforeExpiredAt(
 	actionPK int64,
 	expiredAt int64,
@@ -73,7 +81,7 @@ func (s *openAbacPolicyService) ListPagingByActionBeforeExpiredAt(
 ) (queryPolicies []types.OpenAbacPolicy, err error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicySVC, "ListPagingByActionBeforeExpiredAt")
 
-	policies, err := s.manager.ListPagingByActionPKBeforeExpiredAt(actionPK, expiredAt, offset, limit)
+	policies, err := s.manager.ListPagingByActionPKBeforeExpiredAt(actionPK, expiredAt, time.Now().Unix(), offset, limit)
 	if err != nil {
 		err = errorWrapf(err,
 			"manager.ListByActionPK actionPK=`%d`, expiredAt=`%d`, offset=`%d`, limit=`%d` fail",
@@ -85,6 +93,27 @@ func (s *openAbacPolicyService) ListPagingByActionBeforeExpiredAt(
 	return
 }
 
+// ListAllPagingByActionBeforeExpiredAt 包含未到生效时间的策略, 用于权限数据导出
+func (s *openAbacPolicyService) ListAllPagingByActionBeforeExpiredAt(
+	actionPK int64,
+	expiredAt int64,
+	offset int64,
+	limit int64,
+) (queryPolicies []types.OpenAbacPolicy, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicySVC, "ListAllPagingByActionBeforeExpiredAt")
+
+	policies, err := s.manager.ListPagingByActionPKBeforeExpiredAt(actionPK, expiredAt, math.MaxInt64, offset, limit)
+	if err != nil {
+		err = errorWrapf(err,
+			"manager.ListPagingByActionPKBeforeExpiredAt actionPK=`%d`, expiredAt=`%d`, offset=`%d`, limit=`%d` fail",
+			actionPK, expiredAt, offset, limit)
+		return nil, err
+	}
+
+	queryPolicies = convertPoliciesToOpenAbacPolicies(policies)
+	return
+}
+
 // ListByPKs ...
 func (s *openAbacPolicyService) ListByPKs(pks []int64) (queryPolicies []types.OpenAbacPolicy, err error) {
 	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicySVC, "ListByPKs")
@@ -109,6 +138,9 @@ func convertPoliciesToOpenAbacPolicies(policies []dao.OpenAbacPolicy) []types.Op
 			ActionPK:     p.ActionPK,
 			ExpressionPK: p.ExpressionPK,
 			ExpiredAt:    p.ExpiredAt,
+			TemplateID:   p.TemplateID,
+			Effect:       p.Effect,
+			EffectiveAt:  p.EffectiveAt,
 		})
 	}
 	return queryPolicies
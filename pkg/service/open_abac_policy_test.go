@@ -12,6 +12,7 @@ package service
 
 import (
 	"errors"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -75,4 +76,54 @@ var _ = Describe("OpenAbacPolicyService", func() {
 			assert.Contains(GinkgoT(), err.Error(), "manager.ListByPKs")
 		})
 	})
+
+	Describe("ListPagingByActionBeforeExpiredAt cases", func() {
+		var ctl *gomock.Controller
+
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("exclude pending policies", func() {
+			now := time.Now().Unix()
+			mockPolicyManager := mock.NewMockOpenAbacPolicyManager(ctl)
+			mockPolicyManager.EXPECT().ListPagingByActionPKBeforeExpiredAt(
+				int64(1), int64(100), gomock.Any(), int64(0), int64(10),
+			).DoAndReturn(func(actionPK, expiredAt, effectiveAt, offset, limit int64) ([]dao.OpenAbacPolicy, error) {
+				assert.GreaterOrEqual(GinkgoT(), effectiveAt, now)
+				return []dao.OpenAbacPolicy{{Policy: dao.Policy{PK: 1, ExpressionPK: 1}}}, nil
+			})
+
+			svc := openAbacPolicyService{
+				manager: mockPolicyManager,
+			}
+
+			policies, err := svc.ListPagingByActionBeforeExpiredAt(1, 100, 0, 10)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), policies, 1)
+		})
+
+		It("count exclude pending policies", func() {
+			now := time.Now().Unix()
+			mockPolicyManager := mock.NewMockOpenAbacPolicyManager(ctl)
+			mockPolicyManager.EXPECT().GetCountByActionBeforeExpiredAt(
+				int64(1), int64(100), gomock.Any(),
+			).DoAndReturn(func(actionPK, expiredAt, effectiveAt int64) (int64, error) {
+				assert.GreaterOrEqual(GinkgoT(), effectiveAt, now)
+				return 1, nil
+			})
+
+			svc := openAbacPolicyService{
+				manager: mockPolicyManager,
+			}
+
+			count, err := svc.GetCountByActionBeforeExpiredAt(1, 100)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), int64(1), count)
+		})
+	})
 })
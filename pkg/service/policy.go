@@ -26,8 +26,19 @@ import (
 
 //go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
 
-// PolicyVersion ...
-const PolicyVersion = "1"
+// 返回给接入系统的策略表达式版本
+// v1(默认): 表达式的field为 `{resource_type}.{attr}`
+// v2: 表达式的field为 `{system}.{resource_type}.{attr}`, 用于跨系统的操作(例如job操作cmdb的主机)区分资源所属的系统
+// 系统默认的版本可以通过provider_config.policy_version配置, 请求中指定的版本优先
+const (
+	PolicyVersion   = "1"
+	PolicyVersionV2 = "2"
+)
+
+// IsPolicyVersionWithSystem 策略表达式版本的field是否带上system
+func IsPolicyVersionWithSystem(version string) bool {
+	return version == PolicyVersionV2
+}
 
 // PolicySVC ...
 const PolicySVC = "PolicySVC"
@@ -51,12 +62,14 @@ type PolicyService interface {
 	// for auth
 
 	ListAuthBySubjectAction(subjectPKs []int64, actionPK int64) ([]types.AuthPolicy, error)
+	ListExpiredAuthBySubjectAction(subjectPKs []int64, actionPK int64) ([]types.AuthPolicy, error)
 	ListExpressionByPKs(pks []int64) ([]types.AuthExpression, error)
 
 	// for saas
 
 	ListThinBySubjectActionTemplate(subjectPK int64, actionPKs []int64, templateID int64) ([]types.ThinPolicy, error)
 	ListThinBySubjectTemplateBeforeExpiredAt(subjectPK int64, templateID, expiredAt int64) ([]types.ThinPolicy, error)
+	ListBySubjectPKAndPKs(subjectPK int64, pks []int64) ([]types.Policy, error)
 
 	AlterCustomPolicies(subjectPK int64, createPolicies, updatePolicies []types.Policy, deletePolicyIDs []int64,
 		actionPKWithResourceTypeSet *set.Int64Set) (map[int64][]int64, error)
@@ -69,6 +82,7 @@ type PolicyService interface {
 	) (map[int64][]int64, error)
 
 	DeleteByPKs(subjectPK int64, pks []int64) error
+	DeleteByPKsWithTx(tx *sqlx.Tx, subjectPK int64, pks []int64) error
 
 	DeleteByActionPKWithTx(tx *sqlx.Tx, actionPK int64) error
 
@@ -129,6 +143,40 @@ func (s *policyService) ListAuthBySubjectAction(subjectPKs []int64, actionPK int
 			SubjectPK:    p.SubjectPK,
 			ExpressionPK: p.ExpressionPK,
 			ExpiredAt:    p.ExpiredAt,
+			Effect:       p.Effect,
+			EffectiveAt:  p.EffectiveAt,
+		})
+	}
+	return policies, nil
+}
+
+// ListExpiredAuthBySubjectAction 查询已过期的策略, 用于解释鉴权结果
+func (s *policyService) ListExpiredAuthBySubjectAction(
+	subjectPKs []int64, actionPK int64,
+) ([]types.AuthPolicy, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicySVC, "ListExpiredAuthBySubjectAction")
+	// NOTE: expiredAt=0 will query all the policies, then filter the expired ones
+	daoPolicies, err := s.manager.ListAuthBySubjectAction(subjectPKs, actionPK, 0)
+	if err != nil {
+		return nil, errorWrapf(
+			err, "manager.ListAuthBySubjectAction subjectPKs=`%+v`, actionPK=`%d`, expiredAt=`0`",
+			subjectPKs, actionPK,
+		)
+	}
+
+	nowUnix := time.Now().Unix()
+	policies := make([]types.AuthPolicy, 0, len(daoPolicies))
+	for _, p := range daoPolicies {
+		if p.ExpiredAt >= nowUnix {
+			continue
+		}
+
+		policies = append(policies, types.AuthPolicy{
+			PK:           p.PK,
+			SubjectPK:    p.SubjectPK,
+			ExpressionPK: p.ExpressionPK,
+			ExpiredAt:    p.ExpiredAt,
+			Effect:       p.Effect,
 		})
 	}
 	return policies, nil
@@ -157,10 +205,12 @@ func (s *policyService) convertToThinPolicies(daoPolicies []dao.Policy) []types.
 	thinPolicies := make([]types.ThinPolicy, 0, len(daoPolicies))
 	for _, p := range daoPolicies {
 		thinPolicies = append(thinPolicies, types.ThinPolicy{
-			Version:   PolicyVersion,
-			ID:        p.PK,
-			ActionPK:  p.ActionPK,
-			ExpiredAt: p.ExpiredAt,
+			Version:     PolicyVersion,
+			ID:          p.PK,
+			ActionPK:    p.ActionPK,
+			ExpiredAt:   p.ExpiredAt,
+			EffectiveAt: p.EffectiveAt,
+			Effect:      p.Effect,
 		})
 	}
 	return thinPolicies
@@ -200,6 +250,51 @@ func (s *policyService) ListThinBySubjectTemplateBeforeExpiredAt(
 	return s.convertToThinPolicies(daoPolicies), nil
 }
 
+// ListBySubjectPKAndPKs 查询subject的策略及其表达式, 操作未关联资源类型的策略表达式为空
+func (s *policyService) ListBySubjectPKAndPKs(subjectPK int64, pks []int64) ([]types.Policy, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicySVC, "ListBySubjectPKAndPKs")
+	daoPolicies, err := s.manager.ListBySubjectPKAndPKs(subjectPK, pks)
+	if err != nil {
+		return nil, errorWrapf(err, "manager.ListBySubjectPKAndPKs subjectPK=`%d`, pks=`%+v`", subjectPK, pks)
+	}
+
+	expressionPKs := make([]int64, 0, len(daoPolicies))
+	for _, p := range daoPolicies {
+		if p.ExpressionPK != expressionPKActionWithoutResource {
+			expressionPKs = append(expressionPKs, p.ExpressionPK)
+		}
+	}
+
+	expressionMap := make(map[int64]dao.AuthExpression, len(expressionPKs))
+	if len(expressionPKs) > 0 {
+		daoExpressions, err := s.expressionManger.ListAuthByPKs(expressionPKs)
+		if err != nil {
+			return nil, errorWrapf(err, "expressionManger.ListAuthByPKs pks=`%+v`", expressionPKs)
+		}
+		for _, e := range daoExpressions {
+			expressionMap[e.PK] = e
+		}
+	}
+
+	policies := make([]types.Policy, 0, len(daoPolicies))
+	for _, p := range daoPolicies {
+		expression := expressionMap[p.ExpressionPK]
+		policies = append(policies, types.Policy{
+			Version:     PolicyVersion,
+			ID:          p.PK,
+			SubjectPK:   p.SubjectPK,
+			ActionPK:    p.ActionPK,
+			Expression:  expression.Expression,
+			Signature:   expression.Signature,
+			ExpiredAt:   p.ExpiredAt,
+			EffectiveAt: p.EffectiveAt,
+			TemplateID:  p.TemplateID,
+			Effect:      p.Effect,
+		})
+	}
+	return policies, nil
+}
+
 // AlterCustomPolicies subject custom alter policies
 func (s *policyService) AlterCustomPolicies(
 	subjectPK int64,
@@ -262,9 +357,11 @@ func (s *policyService) AlterCustomPoliciesWithTx(
 			})
 
 			daoCreatePolicies = append(daoCreatePolicies, dao.Policy{
-				SubjectPK: p.SubjectPK,
-				ActionPK:  p.ActionPK,
-				ExpiredAt: p.ExpiredAt,
+				SubjectPK:   p.SubjectPK,
+				ActionPK:    p.ActionPK,
+				ExpiredAt:   p.ExpiredAt,
+				EffectiveAt: p.EffectiveAt,
+				Effect:      p.Effect,
 			})
 
 			policyExpressionIndexes = append(policyExpressionIndexes, policyExpressionIndex{
@@ -278,6 +375,8 @@ func (s *policyService) AlterCustomPoliciesWithTx(
 				ActionPK:     p.ActionPK,
 				ExpressionPK: expressionPKActionWithoutResource,
 				ExpiredAt:    p.ExpiredAt,
+				EffectiveAt:  p.EffectiveAt,
+				Effect:       p.Effect,
 			})
 		}
 	}
@@ -299,6 +398,7 @@ func (s *policyService) AlterCustomPoliciesWithTx(
 
 	daoUpdateExpressions := make([]dao.Expression, 0, len(daoForUpdatePolicies))
 	daoUpdatePolicies := make([]dao.Policy, 0, len(daoForUpdatePolicies))
+	daoUpdateEffectPolicies := make([]dao.Policy, 0, len(daoForUpdatePolicies))
 	for _, p := range daoForUpdatePolicies {
 		up := updatePolicyMap[p.PK]
 		if up.ActionPK == p.ActionPK && p.TemplateID == 0 {
@@ -315,6 +415,12 @@ func (s *policyService) AlterCustomPoliciesWithTx(
 				daoUpdatePolicies = append(daoUpdatePolicies, p)
 			}
 
+			// 更新策略效果 allow/deny
+			if up.Effect != p.Effect {
+				p.Effect = up.Effect
+				daoUpdateEffectPolicies = append(daoUpdateEffectPolicies, p)
+			}
+
 			// collect the update actionPK/expressionPK, we need to know: {actionPK:[expr1PK, expr2PK] }
 			updatedActionPKExpressionPKs[p.ActionPK] = append(updatedActionPKExpressionPKs[p.ActionPK], p.ExpressionPK)
 		}
@@ -344,6 +450,14 @@ func (s *policyService) AlterCustomPoliciesWithTx(
 		}
 	}
 
+	if len(daoUpdateEffectPolicies) != 0 {
+		err = s.manager.BulkUpdateEffectWithTx(tx, daoUpdateEffectPolicies)
+		if err != nil {
+			err = errorWrapf(err, "manager.BulkUpdateEffectWithTx policies=`%+v`", daoUpdateEffectPolicies)
+			return
+		}
+	}
+
 	err = s.expressionManger.BulkUpdateWithTx(tx, daoUpdateExpressions)
 	if err != nil {
 		err = errorWrapf(err, "expressionManger.BulkUpdateWithTx expressions=`%+v`", daoUpdateExpressions)
@@ -409,6 +523,11 @@ func (s *policyService) DeleteByPKs(subjectPK int64, pks []int64) error {
 	return err
 }
 
+// DeleteByPKsWithTx ...
+func (s *policyService) DeleteByPKsWithTx(tx *sqlx.Tx, subjectPK int64, pks []int64) error {
+	return s.deleteByPKsWithTx(tx, subjectPK, pks)
+}
+
 // HasAnyByActionPK ...
 func (s *policyService) HasAnyByActionPK(actionPK int64) (bool, error) {
 	return s.manager.HasAnyByActionPK(actionPK)
@@ -512,6 +631,7 @@ func (s *policyService) CreateAndDeleteTemplatePoliciesWithTx(
 				ExpiredAt:    p.ExpiredAt,
 				ExpressionPK: expressionPK,
 				TemplateID:   p.TemplateID,
+				Effect:       p.Effect,
 			})
 		} else {
 			// 无关联资源的自定义权限, expression 为 -1, 不创建expression对象
@@ -521,6 +641,7 @@ func (s *policyService) CreateAndDeleteTemplatePoliciesWithTx(
 				ExpressionPK: expressionPKActionWithoutResource,
 				ExpiredAt:    p.ExpiredAt,
 				TemplateID:   p.TemplateID,
+				Effect:       p.Effect,
 			})
 		}
 	}
@@ -574,6 +695,7 @@ func (s *policyService) UpdateTemplatePoliciesWithTx(
 
 	// 3. 生成需要更新的policies
 	daoUpdatePolicies := make([]dao.Policy, 0, len(policies))
+	daoUpdateEffectPolicies := make([]dao.Policy, 0, len(policies))
 	for _, p := range policies {
 		daoPolicy, ok := daoPolicyMap[p.ID]
 		// policy不存在
@@ -589,6 +711,12 @@ func (s *policyService) UpdateTemplatePoliciesWithTx(
 			return
 		}
 
+		// 策略效果 allow/deny 变更
+		if p.Effect != daoPolicy.Effect {
+			daoPolicy.Effect = p.Effect
+			daoUpdateEffectPolicies = append(daoUpdateEffectPolicies, daoPolicy)
+		}
+
 		// 操作未关联资源类型, 不更新
 		if daoPolicy.ExpressionPK == expressionPKActionWithoutResource {
 			continue
@@ -611,6 +739,15 @@ func (s *policyService) UpdateTemplatePoliciesWithTx(
 		return
 	}
 
+	// 5. 更新policy的effect
+	if len(daoUpdateEffectPolicies) != 0 {
+		err = s.manager.BulkUpdateEffectWithTx(tx, daoUpdateEffectPolicies)
+		if err != nil {
+			err = errorWrapf(err, "manager.BulkUpdateEffectWithTx policies=`%+v`", daoUpdateEffectPolicies)
+			return
+		}
+	}
+
 	return err
 }
 
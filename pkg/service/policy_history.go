@@ -0,0 +1,275 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/jmoiron/sqlx"
+	jsoniter "github.com/json-iterator/go"
+
+	"iam/pkg/database/dao"
+	"iam/pkg/service/types"
+)
+
+// PolicyHistorySVC ...
+const PolicyHistorySVC = "PolicyHistorySVC"
+
+// PolicyHistoryService ...
+type PolicyHistoryService interface {
+	// web api
+	Get(version int64) (types.PolicyHistory, error)
+	GetCount(subjectPK int64, system string) (int64, error)
+	ListPaging(subjectPK int64, system string, limit, offset int64) ([]types.PolicyHistory, error)
+	ListChanges(subjectPK int64, system string, fromVersion, toVersion int64) ([]types.PolicyChange, error)
+
+	// for pap
+	BulkCreateWithTx(tx *sqlx.Tx, histories []types.PolicyHistory) error
+}
+
+type policyHistoryService struct {
+	manager dao.PolicyHistoryManager
+}
+
+// NewPolicyHistoryService ...
+func NewPolicyHistoryService() PolicyHistoryService {
+	return &policyHistoryService{
+		manager: dao.NewPolicyHistoryManager(),
+	}
+}
+
+func marshalPolicySnapshot(snapshot *types.PolicySnapshot) (string, error) {
+	if snapshot == nil {
+		return "", nil
+	}
+	return jsoniter.MarshalToString(snapshot)
+}
+
+func unmarshalPolicySnapshot(s string) (*types.PolicySnapshot, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var snapshot types.PolicySnapshot
+	err := jsoniter.UnmarshalFromString(s, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func convertToPolicyHistory(h dao.PolicyHistory) (history types.PolicyHistory, err error) {
+	before, err := unmarshalPolicySnapshot(h.BeforePolicy)
+	if err != nil {
+		return
+	}
+	after, err := unmarshalPolicySnapshot(h.AfterPolicy)
+	if err != nil {
+		return
+	}
+
+	return types.PolicyHistory{
+		Version:    h.PK,
+		SubjectPK:  h.SubjectPK,
+		System:     h.System,
+		ActionPK:   h.ActionPK,
+		TemplateID: h.TemplateID,
+		PolicyPK:   h.PolicyPK,
+		Operation:  h.Operation,
+		Before:     before,
+		After:      after,
+		ClientID:   h.ClientID,
+		Actor:      h.Actor,
+		RequestID:  h.RequestID,
+		CreatedAt:  h.CreatedAt,
+	}, nil
+}
+
+func convertToPolicyHistories(daoHistories []dao.PolicyHistory) ([]types.PolicyHistory, error) {
+	histories := make([]types.PolicyHistory, 0, len(daoHistories))
+	for _, h := range daoHistories {
+		history, err := convertToPolicyHistory(h)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(histories, history)
+	}
+	return histories, nil
+}
+
+// Get 查询指定版本的变更记录
+func (l *policyHistoryService) Get(version int64) (history types.PolicyHistory, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyHistorySVC, "Get")
+
+	daoHistory, err := l.manager.Get(version)
+	if err != nil {
+		err = errorWrapf(err, "manager.Get version=`%d` fail", version)
+		return
+	}
+
+	history, err = convertToPolicyHistory(daoHistory)
+	if err != nil {
+		err = errorWrapf(err, "convertToPolicyHistory history=`%+v` fail", daoHistory)
+	}
+	return history, err
+}
+
+// GetCount ...
+func (l *policyHistoryService) GetCount(subjectPK int64, system string) (int64, error) {
+	count, err := l.manager.GetCount(subjectPK, system)
+	if err != nil {
+		return 0, errorx.Wrapf(err, PolicyHistorySVC, "GetCount",
+			"manager.GetCount subjectPK=`%d`, system=`%s` fail", subjectPK, system)
+	}
+	return count, nil
+}
+
+// ListPaging ...
+func (l *policyHistoryService) ListPaging(
+	subjectPK int64, system string, limit, offset int64,
+) ([]types.PolicyHistory, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyHistorySVC, "ListPaging")
+
+	daoHistories, err := l.manager.ListPaging(subjectPK, system, limit, offset)
+	if err != nil {
+		return nil, errorWrapf(err, "manager.ListPaging subjectPK=`%d`, system=`%s`, limit=`%d`, offset=`%d` fail",
+			subjectPK, system, limit, offset)
+	}
+
+	histories, err := convertToPolicyHistories(daoHistories)
+	if err != nil {
+		return nil, errorWrapf(err, "convertToPolicyHistories subjectPK=`%d`, system=`%s` fail", subjectPK, system)
+	}
+	return histories, nil
+}
+
+// policyChangeKey 同一操作(模板)下允许同时存在allow与deny策略, 需要按effect区分
+type policyChangeKey struct {
+	actionPK   int64
+	templateID int64
+	effect     int64
+	// RBAC授权的资源实例
+	resources string
+}
+
+func newPolicyChangeKey(h types.PolicyHistory) policyChangeKey {
+	key := policyChangeKey{actionPK: h.ActionPK, templateID: h.TemplateID}
+
+	snapshot := h.Before
+	if snapshot == nil {
+		snapshot = h.After
+	}
+	if snapshot != nil {
+		key.effect = snapshot.Effect
+		if len(snapshot.Resources) > 0 {
+			key.resources = fmt.Sprint(snapshot.Resources)
+		}
+	}
+	return key
+}
+
+// ListChanges 比较fromVersion与toVersion两个版本之间的策略变化, toVersion为0表示当前最新版本
+func (l *policyHistoryService) ListChanges(
+	subjectPK int64, system string, fromVersion, toVersion int64,
+) ([]types.PolicyChange, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyHistorySVC, "ListChanges")
+
+	daoHistories, err := l.manager.ListAfterPK(subjectPK, system, fromVersion)
+	if err != nil {
+		return nil, errorWrapf(err, "manager.ListAfterPK subjectPK=`%d`, system=`%s`, pk=`%d` fail",
+			subjectPK, system, fromVersion)
+	}
+
+	histories, err := convertToPolicyHistories(daoHistories)
+	if err != nil {
+		return nil, errorWrapf(err, "convertToPolicyHistories subjectPK=`%d`, system=`%s` fail", subjectPK, system)
+	}
+
+	// 同一操作(模板, effect, RBAC授权的资源实例)下, 取区间内第一条记录的变更前与最后一条记录的变更后
+	keys := make([]policyChangeKey, 0, len(histories))
+	changeMap := make(map[policyChangeKey]*types.PolicyChange, len(histories))
+	for _, h := range histories {
+		if toVersion > 0 && h.Version > toVersion {
+			break
+		}
+
+		key := newPolicyChangeKey(h)
+		change, ok := changeMap[key]
+		if !ok {
+			change = &types.PolicyChange{
+				ActionPK:   h.ActionPK,
+				TemplateID: h.TemplateID,
+				Before:     h.Before,
+			}
+			changeMap[key] = change
+			keys = append(keys, key)
+		}
+		change.After = h.After
+	}
+
+	changes := make([]types.PolicyChange, 0, len(keys))
+	for _, key := range keys {
+		change := changeMap[key]
+		if equalPolicySnapshot(change.Before, change.After) {
+			continue
+		}
+		changes = append(changes, *change)
+	}
+	return changes, nil
+}
+
+func equalPolicySnapshot(a, b *types.PolicySnapshot) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return reflect.DeepEqual(*a, *b)
+}
+
+// BulkCreateWithTx ...
+func (l *policyHistoryService) BulkCreateWithTx(tx *sqlx.Tx, histories []types.PolicyHistory) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(PolicyHistorySVC, "BulkCreateWithTx")
+
+	daoHistories := make([]dao.PolicyHistory, 0, len(histories))
+	for _, h := range histories {
+		before, err := marshalPolicySnapshot(h.Before)
+		if err != nil {
+			return errorWrapf(err, "marshalPolicySnapshot before=`%+v` fail", h.Before)
+		}
+		after, err := marshalPolicySnapshot(h.After)
+		if err != nil {
+			return errorWrapf(err, "marshalPolicySnapshot after=`%+v` fail", h.After)
+		}
+
+		daoHistories = append(daoHistories, dao.PolicyHistory{
+			SubjectPK:    h.SubjectPK,
+			System:       h.System,
+			ActionPK:     h.ActionPK,
+			TemplateID:   h.TemplateID,
+			PolicyPK:     h.PolicyPK,
+			Operation:    h.Operation,
+			BeforePolicy: before,
+			AfterPolicy:  after,
+			ClientID:     h.ClientID,
+			Actor:        h.Actor,
+			RequestID:    h.RequestID,
+		})
+	}
+
+	err := l.manager.BulkCreateWithTx(tx, daoHistories)
+	if err != nil {
+		return errorWrapf(err, "manager.BulkCreateWithTx histories=`%+v` fail", daoHistories)
+	}
+	return nil
+}
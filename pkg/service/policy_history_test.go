@@ -0,0 +1,157 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database"
+	"iam/pkg/database/dao"
+	"iam/pkg/database/dao/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("PolicyHistoryService", func() {
+	var ctl *gomock.Controller
+	var mockManager *mock.MockPolicyHistoryManager
+	var svc *policyHistoryService
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockManager = mock.NewMockPolicyHistoryManager(ctl)
+		svc = &policyHistoryService{manager: mockManager}
+	})
+	AfterEach(func() {
+		ctl.Finish()
+	})
+
+	Describe("ListChanges", func() {
+		It("manager.ListAfterPK fail", func() {
+			mockManager.EXPECT().ListAfterPK(int64(1), "test", int64(2)).Return(nil, errors.New("error"))
+
+			_, err := svc.ListChanges(1, "test", 2, 0)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListAfterPK")
+		})
+
+		It("ok", func() {
+			mockManager.EXPECT().ListAfterPK(int64(1), "test", int64(2)).Return(
+				[]dao.PolicyHistory{
+					// 新增后修改
+					{PK: 3, ActionPK: 1, Operation: "create", AfterPolicy: `{"expression":"a"}`},
+					{
+						PK: 4, ActionPK: 1, Operation: "update",
+						BeforePolicy: `{"expression":"a"}`, AfterPolicy: `{"expression":"b"}`,
+					},
+					// 删除
+					{PK: 5, ActionPK: 2, Operation: "delete", BeforePolicy: `{"expression":"c","effect":1}`},
+					// 修改后恢复, 无变化
+					{
+						PK: 6, ActionPK: 3, TemplateID: 1, Operation: "update",
+						BeforePolicy: `{"expression":"d"}`, AfterPolicy: `{"expression":"e"}`,
+					},
+					{
+						PK: 7, ActionPK: 3, TemplateID: 1, Operation: "update",
+						BeforePolicy: `{"expression":"e"}`, AfterPolicy: `{"expression":"d"}`,
+					},
+					// 超出版本范围
+					{PK: 8, ActionPK: 4, Operation: "create", AfterPolicy: `{"expression":"f"}`},
+				}, nil,
+			)
+
+			changes, err := svc.ListChanges(1, "test", 2, 7)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.PolicyChange{
+				{ActionPK: 1, After: &types.PolicySnapshot{Expression: "b"}},
+				{ActionPK: 2, Before: &types.PolicySnapshot{Expression: "c", Effect: 1}},
+			}, changes)
+		})
+
+		It("rbac resources", func() {
+			host1 := `"resources":[{"system":"test","type":"host","id":"1"}]`
+			host2 := `"resources":[{"system":"test","type":"host","id":"2"}]`
+			mockManager.EXPECT().ListAfterPK(int64(1), "test", int64(2)).Return(
+				[]dao.PolicyHistory{
+					// 同一操作不同资源实例的授权分别计算
+					{PK: 3, ActionPK: 1, Operation: "create", AfterPolicy: `{` + host1 + `}`},
+					{PK: 4, ActionPK: 1, Operation: "create", AfterPolicy: `{` + host2 + `}`},
+					{PK: 5, ActionPK: 1, Operation: "delete", BeforePolicy: `{` + host2 + `}`},
+				}, nil,
+			)
+
+			changes, err := svc.ListChanges(1, "test", 2, 0)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.PolicyChange{
+				{
+					ActionPK: 1,
+					After: &types.PolicySnapshot{
+						Resources: []types.PolicyResource{{System: "test", Type: "host", ID: "1"}},
+					},
+				},
+			}, changes)
+		})
+
+		It("effect", func() {
+			mockManager.EXPECT().ListAfterPK(int64(1), "test", int64(2)).Return(
+				[]dao.PolicyHistory{
+					// 同一操作的allow与deny策略分别计算
+					{PK: 3, ActionPK: 1, Operation: "create", AfterPolicy: `{"expression":"a","effect":1}`},
+					{
+						PK: 4, ActionPK: 1, Operation: "update",
+						BeforePolicy: `{"expression":"b"}`, AfterPolicy: `{"expression":"c"}`,
+					},
+				}, nil,
+			)
+
+			changes, err := svc.ListChanges(1, "test", 2, 0)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.PolicyChange{
+				{ActionPK: 1, After: &types.PolicySnapshot{Expression: "a", Effect: 1}},
+				{
+					ActionPK: 1,
+					Before:   &types.PolicySnapshot{Expression: "b"},
+					After:    &types.PolicySnapshot{Expression: "c"},
+				},
+			}, changes)
+		})
+	})
+
+	Describe("BulkCreateWithTx", func() {
+		It("ok", func() {
+			db, dbMock := database.NewMockSqlxDB()
+			dbMock.ExpectBegin()
+			tx, _ := db.Beginx()
+
+			mockManager.EXPECT().BulkCreateWithTx(tx, []dao.PolicyHistory{{
+				SubjectPK:    1,
+				System:       "test",
+				ActionPK:     2,
+				Operation:    "delete",
+				BeforePolicy: `{"expression":"a","expired_at":10,"effect":0,"effective_at":0}`,
+				Actor:        "admin",
+			}}).Return(nil)
+
+			err := svc.BulkCreateWithTx(tx, []types.PolicyHistory{{
+				SubjectPK: 1,
+				System:    "test",
+				ActionPK:  2,
+				Operation: "delete",
+				Before:    &types.PolicySnapshot{Expression: "a", ExpiredAt: 10},
+				Actor:     "admin",
+			}})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+})
@@ -82,6 +82,62 @@ var _ = Describe("PolicyService", func() {
 		})
 	})
 
+	Describe("ListExpiredAuthBySubjectAction cases", func() {
+		var ctl *gomock.Controller
+
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("ok", func() {
+			returned := []dao.AuthPolicy{
+				{
+					PK:        1,
+					ExpiredAt: 1,
+				},
+				{
+					PK:        2,
+					ExpiredAt: 4102444800,
+				},
+			}
+			expected := []types.AuthPolicy{
+				{
+					PK:        1,
+					ExpiredAt: 1,
+				},
+			}
+
+			mockPolicyManager := mock.NewMockPolicyManager(ctl)
+			mockPolicyManager.EXPECT().ListAuthBySubjectAction([]int64{1, 2}, int64(1), int64(0)).Return(
+				returned, nil,
+			)
+			svc := policyService{
+				manager: mockPolicyManager,
+			}
+
+			policies, err := svc.ListExpiredAuthBySubjectAction([]int64{1, 2}, int64(1))
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), expected, policies)
+		})
+
+		It("error", func() {
+			mockPolicyManager := mock.NewMockPolicyManager(ctl)
+			mockPolicyManager.EXPECT().ListAuthBySubjectAction([]int64{1, 2}, int64(1), int64(0)).Return(
+				nil, errors.New("error"),
+			)
+			svc := policyService{
+				manager: mockPolicyManager,
+			}
+
+			_, err := svc.ListExpiredAuthBySubjectAction([]int64{1, 2}, int64(1))
+			assert.Error(GinkgoT(), err)
+		})
+	})
+
 	Describe("ListExpressionByPKs cases", func() {
 		var ctl *gomock.Controller
 
@@ -251,6 +307,60 @@ var _ = Describe("PolicyService", func() {
 		})
 	})
 
+	Describe("ListBySubjectPKAndPKs cases", func() {
+		var ctl *gomock.Controller
+
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("ok", func() {
+			mockPolicyManager := mock.NewMockPolicyManager(ctl)
+			mockPolicyManager.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{1, 2}).Return(
+				[]dao.Policy{
+					{PK: 1, SubjectPK: 1, ActionPK: 1, ExpressionPK: 3, ExpiredAt: 10},
+					{PK: 2, SubjectPK: 1, ActionPK: 2, ExpressionPK: -1, ExpiredAt: 10, Effect: 1},
+				}, nil,
+			)
+			mockExpressionManager := mock.NewMockExpressionManager(ctl)
+			mockExpressionManager.EXPECT().ListAuthByPKs([]int64{3}).Return(
+				[]dao.AuthExpression{{PK: 3, Expression: "[]", Signature: "sig"}}, nil,
+			)
+			svc := policyService{
+				manager:          mockPolicyManager,
+				expressionManger: mockExpressionManager,
+			}
+
+			policies, err := svc.ListBySubjectPKAndPKs(int64(1), []int64{1, 2})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.Policy{
+				{
+					Version: PolicyVersion, ID: 1, SubjectPK: 1, ActionPK: 1,
+					Expression: "[]", Signature: "sig", ExpiredAt: 10,
+				},
+				{Version: PolicyVersion, ID: 2, SubjectPK: 1, ActionPK: 2, ExpiredAt: 10, Effect: 1},
+			}, policies)
+		})
+
+		It("error", func() {
+			mockPolicyManager := mock.NewMockPolicyManager(ctl)
+			mockPolicyManager.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{1}).Return(
+				nil, errors.New("error"),
+			)
+			svc := policyService{
+				manager: mockPolicyManager,
+			}
+
+			_, err := svc.ListBySubjectPKAndPKs(int64(1), []int64{1})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListBySubjectPKAndPKs")
+		})
+	})
+
 	Describe("DeleteByPKs cases", func() {
 		var ctl *gomock.Controller
 
@@ -440,6 +550,76 @@ var _ = Describe("PolicyService", func() {
 			err = dbMock.ExpectationsWereMet()
 			assert.NoError(GinkgoT(), err)
 		})
+		It("ok, update effect", func() {
+			mockPolicyManager := mock.NewMockPolicyManager(ctl)
+			mockPolicyManager.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{1}).Return(
+				[]dao.Policy{
+					{
+						PK:           1,
+						SubjectPK:    1,
+						ActionPK:     3,
+						ExpressionPK: 1,
+						ExpiredAt:    1,
+					},
+				}, nil,
+			)
+			mockPolicyManager.EXPECT().ListBySubjectPKAndPKs(int64(1), []int64{}).Return([]dao.Policy{}, nil)
+			mockPolicyManager.EXPECT().BulkDeleteByTemplatePKsWithTx(
+				gomock.Any(), int64(1), int64(0), []int64{}).Return(int64(0), nil)
+			mockPolicyManager.EXPECT().BulkCreateWithTx(gomock.Any(), []dao.Policy{}).Return(nil)
+			mockPolicyManager.EXPECT().BulkUpdateEffectWithTx(gomock.Any(), []dao.Policy{
+				{
+					PK:           1,
+					SubjectPK:    1,
+					ActionPK:     3,
+					ExpressionPK: 1,
+					ExpiredAt:    1,
+					Effect:       types.PolicyEffectDeny,
+				},
+			}).Return(nil)
+
+			mockExpressionManager := mock.NewMockExpressionManager(ctl)
+			mockExpressionManager.EXPECT().BulkCreateWithTx(gomock.Any(), []dao.Expression{}).Return([]int64{}, nil)
+			mockExpressionManager.EXPECT().BulkUpdateWithTx(gomock.Any(), []dao.Expression{
+				{
+					PK:         1,
+					Type:       0,
+					Expression: "test",
+					Signature:  "098f6bcd4621d373cade4e832627b4f6",
+				},
+			}).Return(nil)
+			mockExpressionManager.EXPECT().BulkDeleteByPKsWithTx(gomock.Any(), []int64{}).Return(int64(0), nil)
+
+			svc := policyService{
+				manager:          mockPolicyManager,
+				expressionManger: mockExpressionManager,
+			}
+
+			db, dbMock := database.NewMockSqlxDB()
+			dbMock.ExpectBegin()
+			dbMock.ExpectCommit()
+
+			patches := gomonkey.ApplyFunc(database.GenerateDefaultDBTx, db.Beginx)
+			defer patches.Reset()
+
+			updatePolicies := []types.Policy{
+				{
+					Version:    "1",
+					ID:         1,
+					SubjectPK:  1,
+					ActionPK:   3,
+					Expression: "test",
+					ExpiredAt:  1,
+					Effect:     types.PolicyEffectDeny,
+				},
+			}
+
+			_, err := svc.AlterCustomPolicies(1, []types.Policy{}, updatePolicies, []int64{}, set.NewInt64Set())
+			assert.NoError(GinkgoT(), err)
+
+			err = dbMock.ExpectationsWereMet()
+			assert.NoError(GinkgoT(), err)
+		})
 	})
 
 	Describe("BulkDeleteBySubjectPKsWithTx cases", func() {
@@ -0,0 +1,675 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+	jsoniter "github.com/json-iterator/go"
+
+	"iam/pkg/database/dao"
+	"iam/pkg/service/types"
+)
+
+// SoDConstraintSVC ...
+const SoDConstraintSVC = "SoDConstraintSVC"
+
+// ErrSoDConstraintViolation 违反职责分离约束
+var ErrSoDConstraintViolation = errors.New("sod constraint violation")
+
+// SoDConstraintService ...
+type SoDConstraintService interface {
+	// web api
+	List() ([]types.SoDConstraint, error)
+	Create(constraint types.SoDConstraint) error
+	Delete(pk int64) error
+	ListViolations() ([]types.SoDViolation, error)
+
+	// for pap
+	CheckGroupMembers(groupPK int64, subjectPKs []int64) error
+	CheckGroupMemberActions(groupPK int64, subjectPKs []int64) error
+	CheckSubjectActions(subjectPK int64, createdActionPKs []int64, deletedPolicyIDs []int64) error
+}
+
+type sodConstraintService struct {
+	manager                     dao.SoDConstraintManager
+	policyManager               dao.PolicyManager
+	groupResourcePolicyManager  dao.GroupResourcePolicyManager
+	subjectGroupManager         dao.SubjectGroupManager
+	subjectTemplateGroupManager dao.SubjectTemplateGroupManager
+	subjectManager              dao.SubjectManager
+
+	// 查询subject通过部门, 嵌套用户组等继承的用户组
+	groupService      GroupService
+	departmentService DepartmentService
+}
+
+// NewSoDConstraintService ...
+func NewSoDConstraintService() SoDConstraintService {
+	return &sodConstraintService{
+		manager:                     dao.NewSoDConstraintManager(),
+		policyManager:               dao.NewPolicyManager(),
+		groupResourcePolicyManager:  dao.NewGroupResourcePolicyManager(),
+		subjectGroupManager:         dao.NewSubjectGroupManager(),
+		subjectTemplateGroupManager: dao.NewSubjectTemplateGroupManager(),
+		subjectManager:              dao.NewSubjectManager(),
+		groupService:                NewGroupService(),
+		departmentService:           NewDepartmentService(),
+	}
+}
+
+func convertToSoDConstraints(daoConstraints []dao.SoDConstraint) ([]types.SoDConstraint, error) {
+	constraints := make([]types.SoDConstraint, 0, len(daoConstraints))
+	for _, c := range daoConstraints {
+		var itemPKs []int64
+		err := jsoniter.UnmarshalFromString(c.ItemPKs, &itemPKs)
+		if err != nil {
+			return nil, errorx.Wrapf(err, SoDConstraintSVC, "convertToSoDConstraints",
+				"unmarshal constraint pk=`%d` item_pks=`%s` fail", c.PK, c.ItemPKs)
+		}
+
+		constraints = append(constraints, types.SoDConstraint{
+			PK:          c.PK,
+			Type:        c.Type,
+			SystemID:    c.SystemID,
+			Name:        c.Name,
+			Description: c.Description,
+			ItemPKs:     itemPKs,
+		})
+	}
+	return constraints, nil
+}
+
+// List ...
+func (l *sodConstraintService) List() ([]types.SoDConstraint, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SoDConstraintSVC, "List")
+
+	daoConstraints, err := l.manager.List()
+	if err != nil {
+		return nil, errorWrapf(err, "manager.List fail")
+	}
+
+	constraints, err := convertToSoDConstraints(daoConstraints)
+	if err != nil {
+		return nil, errorWrapf(err, "convertToSoDConstraints fail")
+	}
+	return constraints, nil
+}
+
+func (l *sodConstraintService) listByType(_type string) ([]types.SoDConstraint, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SoDConstraintSVC, "listByType")
+
+	daoConstraints, err := l.manager.ListByType(_type)
+	if err != nil {
+		return nil, errorWrapf(err, "manager.ListByType type=`%s` fail", _type)
+	}
+
+	constraints, err := convertToSoDConstraints(daoConstraints)
+	if err != nil {
+		return nil, errorWrapf(err, "convertToSoDConstraints fail")
+	}
+	return constraints, nil
+}
+
+// Create ...
+func (l *sodConstraintService) Create(constraint types.SoDConstraint) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SoDConstraintSVC, "Create")
+
+	itemPKs, err := jsoniter.MarshalToString(set.NewInt64SetWithValues(constraint.ItemPKs).ToSlice())
+	if err != nil {
+		return errorWrapf(err, "marshal item_pks=`%+v` fail", constraint.ItemPKs)
+	}
+
+	err = l.manager.Create(dao.SoDConstraint{
+		Type:        constraint.Type,
+		SystemID:    constraint.SystemID,
+		Name:        constraint.Name,
+		Description: constraint.Description,
+		ItemPKs:     itemPKs,
+	})
+	if err != nil {
+		return errorWrapf(err, "manager.Create constraint=`%+v` fail", constraint)
+	}
+	return nil
+}
+
+// Delete ...
+func (l *sodConstraintService) Delete(pk int64) error {
+	_, err := l.manager.Delete(pk)
+	if err != nil {
+		return errorx.Wrapf(err, SoDConstraintSVC, "Delete", "manager.Delete pk=`%d` fail", pk)
+	}
+	return nil
+}
+
+// CheckGroupMembers 检查subjects加入用户组后是否会同时属于互斥的用户组
+// NOTE: subjects会同时加入用户组嵌套的上级用户组, 部门/用户组成员包含的用户也需要检查;
+// 未生效(effective_at > 0)的直接成员关系也视为已拥有
+func (l *sodConstraintService) CheckGroupMembers(groupPK int64, subjectPKs []int64) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SoDConstraintSVC, "CheckGroupMembers")
+	if len(subjectPKs) == 0 {
+		return nil
+	}
+
+	constraints, err := l.listByType(types.SoDConstraintTypeGroup)
+	if err != nil {
+		return errorWrapf(err, "listByType fail")
+	}
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	ancestorPKs, err := l.groupService.ListAncestorGroupPKs(groupPK)
+	if err != nil {
+		return errorWrapf(err, "groupService.ListAncestorGroupPKs groupPK=`%d` fail", groupPK)
+	}
+	joinedGroupPKSet := set.NewInt64SetWithValues(append([]int64{groupPK}, ancestorPKs...))
+
+	// 只需要检查包含加入的用户组的约束
+	relatedConstraints := make([]types.SoDConstraint, 0, len(constraints))
+	relatedGroupPKSet := set.NewInt64Set()
+	for _, c := range constraints {
+		for _, pk := range c.ItemPKs {
+			if joinedGroupPKSet.Has(pk) {
+				relatedConstraints = append(relatedConstraints, c)
+				relatedGroupPKSet.Append(c.ItemPKs...)
+				break
+			}
+		}
+	}
+	if len(relatedConstraints) == 0 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	for _, subjectPK := range subjectPKs {
+		memberPKs, _, err := l.listMemberSubjectPKs([]int64{subjectPK})
+		if err != nil {
+			return errorWrapf(err, "listMemberSubjectPKs subjectPK=`%d` fail", subjectPK)
+		}
+
+		heldGroupPKSet, err := l.listHeldGroupPKSet(memberPKs)
+		if err != nil {
+			return errorWrapf(err, "listHeldGroupPKSet subjectPK=`%d` fail", subjectPK)
+		}
+
+		pendingGroupPKs, err := l.listSubjectGroupPKs(subjectPK, relatedGroupPKSet.ToSlice(), now)
+		if err != nil {
+			return errorWrapf(err, "listSubjectGroupPKs subjectPK=`%d` fail", subjectPK)
+		}
+		heldGroupPKSet.Append(pendingGroupPKs...)
+
+		for _, c := range relatedConstraints {
+			joinedGroupPKs := make([]int64, 0, len(c.ItemPKs))
+			heldGroupPKs := make([]int64, 0, len(c.ItemPKs))
+			for _, pk := range c.ItemPKs {
+				if joinedGroupPKSet.Has(pk) {
+					joinedGroupPKs = append(joinedGroupPKs, pk)
+				} else if heldGroupPKSet.Has(pk) {
+					heldGroupPKs = append(heldGroupPKs, pk)
+				}
+			}
+
+			if len(joinedGroupPKs) > 1 || len(heldGroupPKs) > 0 {
+				return errorWrapf(ErrSoDConstraintViolation,
+					"constraint `%s`: subjectPK=`%d` already in groupPKs=`%+v`, can not join groupPKs=`%+v`",
+					c.Name, subjectPK, heldGroupPKs, joinedGroupPKs)
+			}
+		}
+	}
+	return nil
+}
+
+// listSubjectGroupPKs 查询subject在groupPKs中未过期的用户组, 包括直接加入与通过人员模板加入的
+func (l *sodConstraintService) listSubjectGroupPKs(subjectPK int64, groupPKs []int64, now int64) ([]int64, error) {
+	relations, err := l.subjectGroupManager.ListRelationBySubjectPKGroupPKs(subjectPK, groupPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	templateRelations, err := l.subjectTemplateGroupManager.ListRelationBySubjectPKGroupPKs(subjectPK, groupPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	heldGroupPKs := set.NewInt64Set()
+	for _, r := range relations {
+		if r.ExpiredAt > now {
+			heldGroupPKs.Add(r.GroupPK)
+		}
+	}
+	for _, r := range templateRelations {
+		if r.ExpiredAt > now {
+			heldGroupPKs.Add(r.GroupPK)
+		}
+	}
+	return heldGroupPKs.ToSlice(), nil
+}
+
+// CheckGroupMemberActions 检查subjects加入用户组后, 获得用户组(及上级用户组)的操作是否会同时拥有互斥的操作
+func (l *sodConstraintService) CheckGroupMemberActions(groupPK int64, subjectPKs []int64) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SoDConstraintSVC, "CheckGroupMemberActions")
+	if len(subjectPKs) == 0 {
+		return nil
+	}
+
+	constraints, err := l.listByType(types.SoDConstraintTypeAction)
+	if err != nil {
+		return errorWrapf(err, "listByType fail")
+	}
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	ancestorPKs, err := l.groupService.ListAncestorGroupPKs(groupPK)
+	if err != nil {
+		return errorWrapf(err, "groupService.ListAncestorGroupPKs groupPK=`%d` fail", groupPK)
+	}
+	groupPKs := append([]int64{groupPK}, ancestorPKs...)
+
+	actionPKSet := set.NewInt64Set()
+	for _, c := range constraints {
+		actionPKSet.Append(c.ItemPKs...)
+	}
+
+	joinedActionPKSet, err := l.listPolicyHeldActionPKSet(groupPKs, groupPKs, actionPKSet.ToSlice(), nil)
+	if err != nil {
+		return errorWrapf(err, "listPolicyHeldActionPKSet groupPKs=`%+v` fail", groupPKs)
+	}
+	if joinedActionPKSet.Size() == 0 {
+		return nil
+	}
+
+	for _, subjectPK := range subjectPKs {
+		err = l.checkActions(constraints, subjectPK, joinedActionPKSet.ToSlice(), nil)
+		if err != nil {
+			return errorWrapf(err, "checkActions subjectPK=`%d` fail", subjectPK)
+		}
+	}
+	return nil
+}
+
+// CheckSubjectActions 检查subject新增操作的授权后是否会同时拥有互斥的操作
+// createdActionPKs: 新增授权的操作, deletedPolicyIDs: 同时删除的自定义策略
+// NOTE: subject为用户组时, 用户组的成员也会获得新增的操作, 需要一起检查;
+// RBAC策略只要还有任一资源关联该操作即视为拥有, 不考虑本次删除
+func (l *sodConstraintService) CheckSubjectActions(
+	subjectPK int64, createdActionPKs []int64, deletedPolicyIDs []int64,
+) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SoDConstraintSVC, "CheckSubjectActions")
+	if len(createdActionPKs) == 0 {
+		return nil
+	}
+
+	constraints, err := l.listByType(types.SoDConstraintTypeAction)
+	if err != nil {
+		return errorWrapf(err, "listByType fail")
+	}
+
+	err = l.checkActions(constraints, subjectPK, createdActionPKs, deletedPolicyIDs)
+	if err != nil {
+		return errorWrapf(err, "checkActions subjectPK=`%d` fail", subjectPK)
+	}
+	return nil
+}
+
+// checkActions 检查subject及其包含的成员获得addedActionPKs后是否会同时拥有互斥的操作
+func (l *sodConstraintService) checkActions(
+	constraints []types.SoDConstraint, subjectPK int64, addedActionPKs []int64, deletedPolicyIDs []int64,
+) error {
+	addedActionPKSet := set.NewInt64SetWithValues(addedActionPKs)
+
+	// 只需要检查包含新增操作的约束
+	relatedConstraints := make([]types.SoDConstraint, 0, len(constraints))
+	systemActionPKSets := make(map[string]*set.Int64Set)
+	for _, c := range constraints {
+		for _, pk := range c.ItemPKs {
+			if addedActionPKSet.Has(pk) {
+				relatedConstraints = append(relatedConstraints, c)
+				if _, ok := systemActionPKSets[c.SystemID]; !ok {
+					systemActionPKSets[c.SystemID] = set.NewInt64Set()
+				}
+				systemActionPKSets[c.SystemID].Append(c.ItemPKs...)
+				break
+			}
+		}
+	}
+	if len(relatedConstraints) == 0 {
+		return nil
+	}
+
+	memberPKs, groupPKs, err := l.listMemberSubjectPKs([]int64{subjectPK})
+	if err != nil {
+		return err
+	}
+
+	heldActionPKSet := set.NewInt64SetWithValues(addedActionPKs)
+	for systemID, actionPKSet := range systemActionPKSets {
+		actionPKs, err := l.listHeldActionPKSet(systemID, memberPKs, groupPKs, actionPKSet.ToSlice(), deletedPolicyIDs)
+		if err != nil {
+			return err
+		}
+		heldActionPKSet.Append(actionPKs.ToSlice()...)
+	}
+
+	for _, c := range relatedConstraints {
+		heldActionPKs := make([]int64, 0, len(c.ItemPKs))
+		for _, pk := range c.ItemPKs {
+			if heldActionPKSet.Has(pk) {
+				heldActionPKs = append(heldActionPKs, pk)
+			}
+		}
+
+		if len(heldActionPKs) > 1 {
+			return errorx.Wrapf(ErrSoDConstraintViolation, SoDConstraintSVC, "checkActions",
+				"constraint `%s`: subjectPK=`%d` can not hold actionPKs=`%+v` at the same time",
+				c.Name, subjectPK, heldActionPKs)
+		}
+	}
+	return nil
+}
+
+// listMemberSubjectPKs 查询subjects及其包含的所有subject: 部门下的用户, 用户组嵌套包含的user/department及部门下的用户
+// 同时返回subjects中的用户组; 这些subject都会获得subjects的授权, 需要一起检查
+func (l *sodConstraintService) listMemberSubjectPKs(subjectPKs []int64) (memberPKs, groupPKs []int64, err error) {
+	subjects, err := l.subjectManager.ListByPKs(subjectPKs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	memberPKSet := set.NewInt64SetWithValues(subjectPKs)
+	departmentPKs := make([]int64, 0, len(subjects))
+	for _, s := range subjects {
+		switch s.Type {
+		case types.GroupType:
+			groupPKs = append(groupPKs, s.PK)
+		case types.DepartmentType:
+			departmentPKs = append(departmentPKs, s.PK)
+		}
+	}
+
+	if len(groupPKs) > 0 {
+		nestedPKs, err := l.groupService.ListNestedGroupMemberSubjectPKs(groupPKs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		nestedSubjects, err := l.subjectManager.ListByPKs(nestedPKs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		memberPKSet.Append(nestedPKs...)
+		for _, s := range nestedSubjects {
+			if s.Type == types.DepartmentType {
+				departmentPKs = append(departmentPKs, s.PK)
+			}
+		}
+	}
+
+	for _, departmentPK := range departmentPKs {
+		userPKs, err := l.departmentService.ListSubjectPKsByDepartmentPK(departmentPK)
+		if err != nil {
+			return nil, nil, err
+		}
+		memberPKSet.Append(userPKs...)
+	}
+	return memberPKSet.ToSlice(), groupPKs, nil
+}
+
+// listAuthSubjectPKs 查询subjects鉴权时使用的subject, 即subjects本身及用户所在的部门
+func (l *sodConstraintService) listAuthSubjectPKs(subjectPKs []int64) ([]int64, error) {
+	pkSet := set.NewInt64SetWithValues(subjectPKs)
+	for _, subjectPK := range subjectPKs {
+		departmentPKs, err := l.departmentService.GetSubjectDepartmentPKs(subjectPK)
+		if err != nil {
+			return nil, err
+		}
+		pkSet.Append(departmentPKs...)
+	}
+	return pkSet.ToSlice(), nil
+}
+
+// listHeldGroupPKSet 查询subjects直接加入, 或通过部门, 上级部门, 嵌套用户组继承的所有未过期的用户组
+func (l *sodConstraintService) listHeldGroupPKSet(subjectPKs []int64) (*set.Int64Set, error) {
+	authSubjectPKs, err := l.listAuthSubjectPKs(subjectPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	groupPKs, err := l.groupService.ListEffectGroupPKs(authSubjectPKs)
+	if err != nil {
+		return nil, err
+	}
+	return set.NewInt64SetWithValues(groupPKs), nil
+}
+
+// listHeldActionPKSet 查询subjects在actionPKs中拥有的操作, 与鉴权一致包括subjects, 用户所在部门与生效的用户组的授权
+// groupPKs: subjects中的用户组, 其RBAC策略同样需要查询
+func (l *sodConstraintService) listHeldActionPKSet(
+	systemID string, subjectPKs, groupPKs, actionPKs, deletedPolicyIDs []int64,
+) (*set.Int64Set, error) {
+	authSubjectPKs, err := l.listAuthSubjectPKs(subjectPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	subjectGroups, err := l.groupService.ListEffectThinSubjectGroups(systemID, authSubjectPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	groupPKSet := set.NewInt64SetWithValues(groupPKs)
+	for _, groups := range subjectGroups {
+		for _, g := range groups {
+			groupPKSet.Add(g.GroupPK)
+		}
+	}
+
+	policySubjectPKSet := set.NewInt64SetWithValues(authSubjectPKs)
+	policySubjectPKSet.Append(groupPKSet.ToSlice()...)
+	return l.listPolicyHeldActionPKSet(policySubjectPKSet.ToSlice(), groupPKSet.ToSlice(), actionPKs, deletedPolicyIDs)
+}
+
+// listPolicyHeldActionPKSet 查询subjects的自定义策略与groups的RBAC策略在actionPKs中拥有的操作
+func (l *sodConstraintService) listPolicyHeldActionPKSet(
+	subjectPKs, groupPKs, actionPKs, deletedPolicyIDs []int64,
+) (*set.Int64Set, error) {
+	now := time.Now().Unix()
+	deletedPolicyIDSet := set.NewInt64SetWithValues(deletedPolicyIDs)
+	heldActionPKSet := set.NewInt64Set()
+	for _, actionPK := range actionPKs {
+		policies, err := l.policyManager.ListAuthBySubjectAction(subjectPKs, actionPK, now)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range policies {
+			if p.Effect != types.PolicyEffectDeny && !deletedPolicyIDSet.Has(p.PK) {
+				heldActionPKSet.Add(actionPK)
+				break
+			}
+		}
+	}
+
+	actionPKSet := set.NewInt64SetWithValues(actionPKs)
+	for _, groupPK := range groupPKs {
+		actionPKsList, err := l.groupResourcePolicyManager.ListActionPKsByGroup(groupPK)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, actionPKsStr := range actionPKsList {
+			var pks []int64
+			err = jsoniter.UnmarshalFromString(actionPKsStr, &pks)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, pk := range pks {
+				if actionPKSet.Has(pk) {
+					heldActionPKSet.Add(pk)
+				}
+			}
+		}
+	}
+	return heldActionPKSet, nil
+}
+
+// ListViolations 查询所有已存在的违反约束的授权
+// NOTE: 与鉴权一致, 包括通过部门, 上级部门, 嵌套用户组继承的用户组与操作
+func (l *sodConstraintService) ListViolations() ([]types.SoDViolation, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SoDConstraintSVC, "ListViolations")
+
+	constraints, err := l.List()
+	if err != nil {
+		return nil, errorWrapf(err, "List fail")
+	}
+
+	violations := make([]types.SoDViolation, 0)
+	for _, c := range constraints {
+		var subjectItems map[int64][]int64
+		switch c.Type {
+		case types.SoDConstraintTypeGroup:
+			subjectItems, err = l.listGroupViolationItems(c.ItemPKs)
+		case types.SoDConstraintTypeAction:
+			subjectItems, err = l.listActionViolationItems(c.SystemID, c.ItemPKs)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, errorWrapf(err, "list violation items of constraint pk=`%d` fail", c.PK)
+		}
+
+		subjectPKs := make([]int64, 0, len(subjectItems))
+		for subjectPK := range subjectItems {
+			subjectPKs = append(subjectPKs, subjectPK)
+		}
+		sort.Slice(subjectPKs, func(i, j int) bool { return subjectPKs[i] < subjectPKs[j] })
+
+		for _, subjectPK := range subjectPKs {
+			violations = append(violations, types.SoDViolation{
+				Constraint: c,
+				SubjectPK:  subjectPK,
+				ItemPKs:    subjectItems[subjectPK],
+			})
+		}
+	}
+	return violations, nil
+}
+
+// filterHeldItems 返回itemPKs中已拥有的, 按pk排序
+func filterHeldItems(itemPKs []int64, held *set.Int64Set) []int64 {
+	heldItemPKs := make([]int64, 0, len(itemPKs))
+	for _, pk := range itemPKs {
+		if held.Has(pk) {
+			heldItemPKs = append(heldItemPKs, pk)
+		}
+	}
+	sort.Slice(heldItemPKs, func(i, j int) bool { return heldItemPKs[i] < heldItemPKs[j] })
+	return heldItemPKs
+}
+
+// listGroupViolationItems 查询同时拥有groupPKs中多个用户组的subject及其拥有的用户组
+func (l *sodConstraintService) listGroupViolationItems(groupPKs []int64) (map[int64][]int64, error) {
+	// 直接或间接加入了groupPKs的subject
+	candidatePKs, _, err := l.listMemberSubjectPKs(groupPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	subjectItems := make(map[int64][]int64)
+	for _, subjectPK := range candidatePKs {
+		heldGroupPKSet, err := l.listHeldGroupPKSet([]int64{subjectPK})
+		if err != nil {
+			return nil, err
+		}
+
+		itemPKs := filterHeldItems(groupPKs, heldGroupPKSet)
+		if len(itemPKs) > 1 {
+			subjectItems[subjectPK] = itemPKs
+		}
+	}
+	return subjectItems, nil
+}
+
+// listActionViolationItems 查询同时拥有actionPKs中多个操作的subject及其拥有的操作
+func (l *sodConstraintService) listActionViolationItems(
+	systemID string, actionPKs []int64,
+) (map[int64][]int64, error) {
+	// 有授权的subject, 及其包含的成员
+	policies, err := l.policyManager.ListThinByActions(actionPKs, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	grantedPKSet := set.NewInt64Set()
+	for _, p := range policies {
+		if p.Effect != types.PolicyEffectDeny {
+			grantedPKSet.Add(p.SubjectPK)
+		}
+	}
+
+	rbacPolicies, err := l.groupResourcePolicyManager.ListThinBySystem(systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	actionPKSet := set.NewInt64SetWithValues(actionPKs)
+	for _, p := range rbacPolicies {
+		var pks []int64
+		err = jsoniter.UnmarshalFromString(p.ActionPKs, &pks)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pk := range pks {
+			if actionPKSet.Has(pk) {
+				grantedPKSet.Add(p.GroupPK)
+				break
+			}
+		}
+	}
+	if grantedPKSet.Size() == 0 {
+		return nil, nil
+	}
+
+	candidatePKs, groupPKs, err := l.listMemberSubjectPKs(grantedPKSet.ToSlice())
+	if err != nil {
+		return nil, err
+	}
+	groupPKSet := set.NewInt64SetWithValues(groupPKs)
+
+	subjectItems := make(map[int64][]int64)
+	for _, subjectPK := range candidatePKs {
+		var subjectGroupPKs []int64
+		if groupPKSet.Has(subjectPK) {
+			subjectGroupPKs = []int64{subjectPK}
+		}
+
+		heldActionPKSet, err := l.listHeldActionPKSet(systemID, []int64{subjectPK}, subjectGroupPKs, actionPKs, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		itemPKs := filterHeldItems(actionPKs, heldActionPKSet)
+		if len(itemPKs) > 1 {
+			subjectItems[subjectPK] = itemPKs
+		}
+	}
+	return subjectItems, nil
+}
@@ -0,0 +1,430 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database/dao"
+	"iam/pkg/database/dao/mock"
+	svcmock "iam/pkg/service/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("SoDConstraintService", func() {
+	var ctl *gomock.Controller
+	var mockManager *mock.MockSoDConstraintManager
+	var mockSubjectGroupManager *mock.MockSubjectGroupManager
+	var mockSubjectTemplateGroupManager *mock.MockSubjectTemplateGroupManager
+	var svc *sodConstraintService
+	var expiredAt int64
+
+	// subject的类型, 用户所在的部门, 直接或继承的用户组, 用户组的上级用户组与嵌套成员, 部门下的用户, 以及授权
+	var subjectTypes map[int64]string
+	var subjectDepartments map[int64][]int64
+	var subjectGroups map[int64][]int64
+	var groupAncestors map[int64][]int64
+	var groupMembers map[int64][]int64
+	var departmentUsers map[int64][]int64
+	var policies []dao.ThinPolicy
+	var groupRBACActions map[int64][]string
+
+	union := func(m map[int64][]int64, pks []int64) []int64 {
+		result := []int64{}
+		for _, pk := range pks {
+			result = append(result, m[pk]...)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockManager = mock.NewMockSoDConstraintManager(ctl)
+		mockSubjectGroupManager = mock.NewMockSubjectGroupManager(ctl)
+		mockSubjectTemplateGroupManager = mock.NewMockSubjectTemplateGroupManager(ctl)
+		expiredAt = time.Now().Unix() + 100
+
+		subjectTypes = map[int64]string{
+			1: types.GroupType, 2: types.GroupType, 3: types.GroupType,
+			10: types.UserType, 11: types.UserType,
+			20: types.DepartmentType,
+		}
+		subjectDepartments = map[int64][]int64{}
+		subjectGroups = map[int64][]int64{}
+		groupAncestors = map[int64][]int64{}
+		groupMembers = map[int64][]int64{}
+		departmentUsers = map[int64][]int64{}
+		policies = nil
+		groupRBACActions = map[int64][]string{}
+
+		mockSubjectManager := mock.NewMockSubjectManager(ctl)
+		mockSubjectManager.EXPECT().ListByPKs(gomock.Any()).DoAndReturn(func(pks []int64) ([]dao.Subject, error) {
+			subjects := []dao.Subject{}
+			for _, pk := range pks {
+				subjects = append(subjects, dao.Subject{PK: pk, Type: subjectTypes[pk]})
+			}
+			return subjects, nil
+		}).AnyTimes()
+
+		mockDepartmentService := svcmock.NewMockDepartmentService(ctl)
+		mockDepartmentService.EXPECT().GetSubjectDepartmentPKs(gomock.Any()).DoAndReturn(
+			func(pk int64) ([]int64, error) { return subjectDepartments[pk], nil },
+		).AnyTimes()
+		mockDepartmentService.EXPECT().ListSubjectPKsByDepartmentPK(gomock.Any()).DoAndReturn(
+			func(pk int64) ([]int64, error) { return departmentUsers[pk], nil },
+		).AnyTimes()
+
+		mockGroupService := svcmock.NewMockGroupService(ctl)
+		mockGroupService.EXPECT().ListAncestorGroupPKs(gomock.Any()).DoAndReturn(
+			func(pk int64) ([]int64, error) { return groupAncestors[pk], nil },
+		).AnyTimes()
+		mockGroupService.EXPECT().ListNestedGroupMemberSubjectPKs(gomock.Any()).DoAndReturn(
+			func(pks []int64) ([]int64, error) { return union(groupMembers, pks), nil },
+		).AnyTimes()
+		mockGroupService.EXPECT().ListEffectGroupPKs(gomock.Any()).DoAndReturn(
+			func(pks []int64) ([]int64, error) { return union(subjectGroups, pks), nil },
+		).AnyTimes()
+		mockGroupService.EXPECT().ListEffectThinSubjectGroups("test", gomock.Any()).DoAndReturn(
+			func(systemID string, pks []int64) (map[int64][]types.ThinSubjectGroup, error) {
+				result := map[int64][]types.ThinSubjectGroup{}
+				for _, pk := range pks {
+					for _, groupPK := range subjectGroups[pk] {
+						result[pk] = append(result[pk], types.ThinSubjectGroup{GroupPK: groupPK, ExpiredAt: expiredAt})
+					}
+				}
+				return result, nil
+			},
+		).AnyTimes()
+
+		mockPolicyManager := mock.NewMockPolicyManager(ctl)
+		mockPolicyManager.EXPECT().ListAuthBySubjectAction(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(subjectPKs []int64, actionPK int64, expiredAt int64) ([]dao.AuthPolicy, error) {
+				subjectPKSet := map[int64]bool{}
+				for _, pk := range subjectPKs {
+					subjectPKSet[pk] = true
+				}
+
+				authPolicies := []dao.AuthPolicy{}
+				for _, p := range policies {
+					if p.ActionPK == actionPK && subjectPKSet[p.SubjectPK] {
+						authPolicies = append(authPolicies, dao.AuthPolicy{PK: p.PK, SubjectPK: p.SubjectPK, Effect: p.Effect})
+					}
+				}
+				return authPolicies, nil
+			},
+		).AnyTimes()
+		mockPolicyManager.EXPECT().ListThinByActions(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(actionPKs []int64, expiredAt int64) ([]dao.ThinPolicy, error) { return policies, nil },
+		).AnyTimes()
+
+		mockGroupResourcePolicyManager := mock.NewMockGroupResourcePolicyManager(ctl)
+		mockGroupResourcePolicyManager.EXPECT().ListActionPKsByGroup(gomock.Any()).DoAndReturn(
+			func(pk int64) ([]string, error) { return groupRBACActions[pk], nil },
+		).AnyTimes()
+		mockGroupResourcePolicyManager.EXPECT().ListThinBySystem("test").DoAndReturn(
+			func(systemID string) ([]dao.ThinGroupResourcePolicy, error) {
+				rbacPolicies := []dao.ThinGroupResourcePolicy{}
+				for groupPK, actionPKsList := range groupRBACActions {
+					for _, actionPKs := range actionPKsList {
+						rbacPolicies = append(rbacPolicies, dao.ThinGroupResourcePolicy{GroupPK: groupPK, ActionPKs: actionPKs})
+					}
+				}
+				return rbacPolicies, nil
+			},
+		).AnyTimes()
+
+		svc = &sodConstraintService{
+			manager:                     mockManager,
+			policyManager:               mockPolicyManager,
+			groupResourcePolicyManager:  mockGroupResourcePolicyManager,
+			subjectGroupManager:         mockSubjectGroupManager,
+			subjectTemplateGroupManager: mockSubjectTemplateGroupManager,
+			subjectManager:              mockSubjectManager,
+			groupService:                mockGroupService,
+			departmentService:           mockDepartmentService,
+		}
+	})
+	AfterEach(func() {
+		ctl.Finish()
+	})
+
+	expectNoDirectRelations := func(subjectPK int64) {
+		mockSubjectGroupManager.EXPECT().ListRelationBySubjectPKGroupPKs(subjectPK, gomock.Any()).Return(nil, nil)
+		mockSubjectTemplateGroupManager.EXPECT().ListRelationBySubjectPKGroupPKs(subjectPK, gomock.Any()).Return(
+			nil, nil,
+		)
+	}
+
+	Describe("List", func() {
+		It("manager.List fail", func() {
+			mockManager.EXPECT().List().Return(nil, errors.New("error"))
+
+			_, err := svc.List()
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "manager.List")
+		})
+
+		It("unmarshal fail", func() {
+			mockManager.EXPECT().List().Return([]dao.SoDConstraint{{PK: 1, ItemPKs: "["}}, nil)
+
+			_, err := svc.List()
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "convertToSoDConstraints")
+		})
+
+		It("ok", func() {
+			mockManager.EXPECT().List().Return([]dao.SoDConstraint{
+				{PK: 1, Type: "group", Name: "test", ItemPKs: "[1,2]"},
+			}, nil)
+
+			constraints, err := svc.List()
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.SoDConstraint{
+				{PK: 1, Type: "group", Name: "test", ItemPKs: []int64{1, 2}},
+			}, constraints)
+		})
+	})
+
+	Describe("Create", func() {
+		It("ok", func() {
+			mockManager.EXPECT().Create(dao.SoDConstraint{Type: "group", Name: "test", ItemPKs: "[1]"}).Return(nil)
+
+			err := svc.Create(types.SoDConstraint{Type: "group", Name: "test", ItemPKs: []int64{1, 1}})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("Delete", func() {
+		It("fail", func() {
+			mockManager.EXPECT().Delete(int64(1)).Return(int64(0), errors.New("error"))
+
+			err := svc.Delete(1)
+			assert.Error(GinkgoT(), err)
+		})
+	})
+
+	Describe("CheckGroupMembers", func() {
+		BeforeEach(func() {
+			mockManager.EXPECT().ListByType("group").Return([]dao.SoDConstraint{
+				{PK: 1, Type: "group", Name: "test", ItemPKs: "[1,2]"},
+			}, nil)
+		})
+
+		It("no related constraint", func() {
+			err := svc.CheckGroupMembers(3, []int64{10})
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("violation", func() {
+			mockSubjectGroupManager.EXPECT().ListRelationBySubjectPKGroupPKs(int64(10), gomock.Any()).Return(nil, nil)
+			mockSubjectTemplateGroupManager.EXPECT().ListRelationBySubjectPKGroupPKs(int64(10), gomock.Any()).Return(
+				[]dao.SubjectTemplateGroup{{SubjectPK: 10, GroupPK: 2, ExpiredAt: expiredAt}}, nil,
+			)
+
+			err := svc.CheckGroupMembers(1, []int64{10})
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+			assert.Contains(GinkgoT(), err.Error(), "test")
+		})
+
+		It("expired ok", func() {
+			mockSubjectGroupManager.EXPECT().ListRelationBySubjectPKGroupPKs(int64(10), gomock.Any()).Return(
+				[]dao.SubjectRelation{{SubjectPK: 10, GroupPK: 2, ExpiredAt: 1}}, nil,
+			)
+			mockSubjectTemplateGroupManager.EXPECT().ListRelationBySubjectPKGroupPKs(int64(10), gomock.Any()).Return(
+				nil, nil,
+			)
+
+			err := svc.CheckGroupMembers(1, []int64{10})
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("violation, user in group through department", func() {
+			subjectDepartments[10] = []int64{20}
+			subjectGroups[20] = []int64{2}
+			expectNoDirectRelations(10)
+
+			err := svc.CheckGroupMembers(1, []int64{10})
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+
+		It("violation, user of department member in group", func() {
+			departmentUsers[20] = []int64{11}
+			subjectGroups[11] = []int64{2}
+			expectNoDirectRelations(20)
+
+			err := svc.CheckGroupMembers(1, []int64{20})
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+
+		It("violation, join ancestor group", func() {
+			groupAncestors[3] = []int64{1}
+			subjectGroups[10] = []int64{2}
+			expectNoDirectRelations(10)
+
+			err := svc.CheckGroupMembers(3, []int64{10})
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+
+		It("violation, member of nested group in group", func() {
+			groupMembers[3] = []int64{11}
+			subjectGroups[11] = []int64{2}
+			expectNoDirectRelations(3)
+
+			err := svc.CheckGroupMembers(1, []int64{3})
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+	})
+
+	Describe("CheckSubjectActions", func() {
+		BeforeEach(func() {
+			mockManager.EXPECT().ListByType("action").Return([]dao.SoDConstraint{
+				{PK: 1, Type: "action", SystemID: "test", Name: "payment", ItemPKs: "[1,2]"},
+			}, nil).AnyTimes()
+		})
+
+		It("empty", func() {
+			err := svc.CheckSubjectActions(1, nil, nil)
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("violation", func() {
+			policies = []dao.ThinPolicy{{PK: 100, SubjectPK: 10, ActionPK: 2}}
+
+			err := svc.CheckSubjectActions(10, []int64{1}, nil)
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+
+		It("violation, group policy and custom policy", func() {
+			subjectGroups[10] = []int64{3}
+			policies = []dao.ThinPolicy{{PK: 100, SubjectPK: 3, ActionPK: 2}}
+
+			err := svc.CheckSubjectActions(10, []int64{1}, nil)
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+
+		It("violation, group through department", func() {
+			subjectDepartments[10] = []int64{20}
+			subjectGroups[20] = []int64{3}
+			groupRBACActions[3] = []string{"[2,3]"}
+
+			err := svc.CheckSubjectActions(10, []int64{1}, nil)
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+
+		It("violation, group rbac", func() {
+			groupRBACActions[3] = []string{"[2,3]"}
+
+			err := svc.CheckSubjectActions(3, []int64{1}, nil)
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+
+		It("violation, group member", func() {
+			groupMembers[3] = []int64{10}
+			policies = []dao.ThinPolicy{{PK: 100, SubjectPK: 10, ActionPK: 2}}
+
+			err := svc.CheckSubjectActions(3, []int64{1}, nil)
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+
+		It("deny ok", func() {
+			subjectGroups[10] = []int64{3}
+			policies = []dao.ThinPolicy{{PK: 100, SubjectPK: 3, ActionPK: 2, Effect: types.PolicyEffectDeny}}
+
+			err := svc.CheckSubjectActions(10, []int64{1}, nil)
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("deleted ok", func() {
+			policies = []dao.ThinPolicy{{PK: 100, SubjectPK: 10, ActionPK: 2}}
+
+			err := svc.CheckSubjectActions(10, []int64{1}, []int64{100})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("CheckSubjectActions fail", func() {
+		It("fail", func() {
+			mockManager.EXPECT().ListByType("action").Return(nil, errors.New("error"))
+
+			err := svc.CheckSubjectActions(10, []int64{1}, nil)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "listByType")
+		})
+	})
+
+	Describe("CheckGroupMemberActions", func() {
+		BeforeEach(func() {
+			mockManager.EXPECT().ListByType("action").Return([]dao.SoDConstraint{
+				{PK: 1, Type: "action", SystemID: "test", Name: "payment", ItemPKs: "[1,2]"},
+			}, nil)
+			policies = []dao.ThinPolicy{{PK: 100, SubjectPK: 3, ActionPK: 1}}
+		})
+
+		It("ok", func() {
+			err := svc.CheckGroupMemberActions(3, []int64{10})
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("violation", func() {
+			policies = append(policies, dao.ThinPolicy{PK: 101, SubjectPK: 10, ActionPK: 2})
+
+			err := svc.CheckGroupMemberActions(3, []int64{10})
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+
+		It("violation, ancestor group", func() {
+			groupAncestors[1] = []int64{3}
+			subjectGroups[20] = []int64{2}
+			groupRBACActions[2] = []string{"[2]"}
+			departmentUsers[20] = []int64{11}
+			subjectDepartments[11] = []int64{20}
+
+			err := svc.CheckGroupMemberActions(1, []int64{20})
+			assert.ErrorIs(GinkgoT(), err, ErrSoDConstraintViolation)
+		})
+	})
+
+	Describe("ListViolations", func() {
+		It("ok", func() {
+			mockManager.EXPECT().List().Return([]dao.SoDConstraint{
+				{PK: 1, Type: "group", Name: "group", ItemPKs: "[1,2]"},
+				{PK: 2, Type: "action", SystemID: "test", Name: "action", ItemPKs: "[3,4]"},
+			}, nil)
+
+			// user 11 in group 1 directly, and in group 2 through department 20
+			groupMembers[1] = []int64{11}
+			groupMembers[2] = []int64{20}
+			departmentUsers[20] = []int64{11}
+			subjectDepartments[11] = []int64{20}
+			subjectGroups[11] = []int64{1}
+			subjectGroups[20] = []int64{2}
+
+			// user 10 has custom policy of action 3, and action 4 through group 3
+			policies = []dao.ThinPolicy{{PK: 1, SubjectPK: 10, ActionPK: 3}}
+			groupRBACActions[3] = []string{"[4,5]"}
+			groupMembers[3] = []int64{10}
+			subjectGroups[10] = []int64{3}
+
+			violations, err := svc.ListViolations()
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), violations, 2)
+			assert.Equal(GinkgoT(), int64(11), violations[0].SubjectPK)
+			assert.Equal(GinkgoT(), []int64{1, 2}, violations[0].ItemPKs)
+			assert.Equal(GinkgoT(), int64(10), violations[1].SubjectPK)
+			assert.Equal(GinkgoT(), []int64{3, 4}, violations[1].ItemPKs)
+		})
+	})
+})
@@ -0,0 +1,146 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/database/dao"
+)
+
+// listSubDepartmentMemberPKs 查询groups中已生效且未过期的包含子部门的部门成员的所有下级部门
+func listSubDepartmentMemberPKs(
+	manager dao.SubjectGroupManager,
+	treeManager dao.DepartmentTreeManager,
+	groupPKs []int64,
+	departmentPKs []int64,
+) ([]int64, error) {
+	relations, err := manager.ListSubDepartmentRelationsByGroupPKs(groupPKs)
+	if err != nil {
+		return nil, err
+	}
+
+	// departmentPKs不为空时, 只查询其中的部门成员
+	now := time.Now().Unix()
+	filter := set.NewInt64SetWithValues(departmentPKs)
+	memberPKs := set.NewInt64Set()
+	for _, r := range relations {
+		if !isEffectiveRelation(r.ExpiredAt, r.EffectiveAt, now) {
+			continue
+		}
+		if len(departmentPKs) == 0 || filter.Has(r.SubjectPK) {
+			memberPKs.Add(r.SubjectPK)
+		}
+	}
+	if memberPKs.Size() == 0 {
+		return nil, nil
+	}
+
+	return listDescendantDepartmentPKs(treeManager, memberPKs.ToSlice())
+}
+
+// ListSubDepartmentMemberPKs 查询group中包含子部门的部门成员(departmentPKs中)的所有下级部门
+func (l *groupService) ListSubDepartmentMemberPKs(groupPK int64, departmentPKs []int64) ([]int64, error) {
+	subDepartmentPKs, err := listSubDepartmentMemberPKs(
+		l.manager, l.departmentTreeManager, []int64{groupPK}, departmentPKs,
+	)
+	if err != nil {
+		return nil, errorx.Wrapf(
+			err, GroupSVC, "ListSubDepartmentMemberPKs", "groupPK=`%d`, departmentPKs=`%+v`", groupPK, departmentPKs,
+		)
+	}
+	return subDepartmentPKs, nil
+}
+
+// ListSubDepartmentGroupPKs 查询departments以包含子部门的方式加入的用户组
+func (l *groupService) ListSubDepartmentGroupPKs(departmentPKs []int64) ([]int64, error) {
+	relations, err := l.manager.ListSubDepartmentRelationAfterExpiredAtBySubjectPKs(
+		departmentPKs, 0, time.Now().Unix(),
+	)
+	if err != nil {
+		return nil, errorx.Wrapf(err, GroupSVC, "ListSubDepartmentGroupPKs", "departmentPKs=`%+v`", departmentPKs)
+	}
+
+	groupPKs := set.NewInt64Set()
+	for _, r := range relations {
+		groupPKs.Add(r.GroupPK)
+	}
+	return groupPKs.ToSlice(), nil
+}
+
+// UpdateMembersIncludeSubDepartment 设置用户组的部门成员是否包含子部门
+func (l *groupService) UpdateMembersIncludeSubDepartment(
+	groupPK int64,
+	departmentPKs []int64,
+	includeSubDepartment bool,
+) error {
+	err := l.manager.UpdateIncludeSubDepartment(groupPK, departmentPKs, includeSubDepartment)
+	if err != nil {
+		return errorx.Wrapf(
+			err, GroupSVC, "UpdateMembersIncludeSubDepartment",
+			"manager.UpdateIncludeSubDepartment groupPK=`%d`, departmentPKs=`%+v`, includeSubDepartment=`%t` fail",
+			groupPK, departmentPKs, includeSubDepartment,
+		)
+	}
+	return nil
+}
+
+// listSubDepartmentInheritedRelations 查询subjects通过上级部门包含子部门的成员关系继承的用户组
+// NOTE: 返回的关系中SubjectPK为下级部门
+func (l *groupService) listSubDepartmentInheritedRelations(
+	subjectPKs []int64,
+	now int64,
+) ([]dao.ThinSubjectRelation, error) {
+	departmentAncestors, err := listDepartmentAncestors(l.departmentTreeManager, subjectPKs)
+	if err != nil {
+		return nil, err
+	}
+	if len(departmentAncestors) == 0 {
+		return nil, nil
+	}
+
+	ancestorPKs := set.NewInt64Set()
+	for _, ancestors := range departmentAncestors {
+		ancestorPKs.Append(ancestors...)
+	}
+
+	relations, err := l.manager.ListSubDepartmentRelationAfterExpiredAtBySubjectPKs(
+		ancestorPKs.ToSlice(), now, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(relations) == 0 {
+		return nil, nil
+	}
+
+	ancestorRelations := make(map[int64][]dao.ThinSubjectRelation, len(relations))
+	for _, r := range relations {
+		ancestorRelations[r.SubjectPK] = append(ancestorRelations[r.SubjectPK], r)
+	}
+
+	inherited := make([]dao.ThinSubjectRelation, 0, len(relations))
+	for subjectPK, ancestors := range departmentAncestors {
+		for _, ancestorPK := range ancestors {
+			for _, r := range ancestorRelations[ancestorPK] {
+				inherited = append(inherited, dao.ThinSubjectRelation{
+					SubjectPK: subjectPK,
+					GroupPK:   r.GroupPK,
+					ExpiredAt: r.ExpiredAt,
+				})
+			}
+		}
+	}
+	return inherited, nil
+}
@@ -0,0 +1,105 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database/dao"
+	"iam/pkg/database/dao/mock"
+)
+
+var _ = Describe("SubDepartmentGroup", func() {
+	var ctl *gomock.Controller
+	var mockManager *mock.MockSubjectGroupManager
+	var mockTreeManager *mock.MockDepartmentTreeManager
+	var svc *groupService
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockManager = mock.NewMockSubjectGroupManager(ctl)
+		mockTreeManager = mock.NewMockDepartmentTreeManager(ctl)
+		svc = &groupService{manager: mockManager, departmentTreeManager: mockTreeManager}
+	})
+	AfterEach(func() {
+		ctl.Finish()
+	})
+
+	Describe("ListSubDepartmentMemberPKs", func() {
+		It("ok", func() {
+			mockManager.EXPECT().ListSubDepartmentRelationsByGroupPKs([]int64{1}).Return(
+				[]dao.ThinSubjectRelation{
+					{SubjectPK: 2, GroupPK: 1, ExpiredAt: 4102444800},
+					{SubjectPK: 3, GroupPK: 1, ExpiredAt: 4102444800},
+				}, nil,
+			)
+			mockTreeManager.EXPECT().ListByParentPKs([]int64{2}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 4, ParentPK: 2}}, nil,
+			)
+			mockTreeManager.EXPECT().ListByParentPKs([]int64{4}).Return(nil, nil)
+
+			pks, err := svc.ListSubDepartmentMemberPKs(1, []int64{2})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []int64{4}, pks)
+		})
+
+		It("no sub department member", func() {
+			mockManager.EXPECT().ListSubDepartmentRelationsByGroupPKs([]int64{1}).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 3, GroupPK: 1}}, nil,
+			)
+
+			pks, err := svc.ListSubDepartmentMemberPKs(1, []int64{2})
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), pks)
+		})
+
+		It("fail", func() {
+			mockManager.EXPECT().ListSubDepartmentRelationsByGroupPKs([]int64{1}).Return(nil, errors.New("error"))
+
+			_, err := svc.ListSubDepartmentMemberPKs(1, nil)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListSubDepartmentMemberPKs")
+		})
+	})
+
+	Describe("ListSubDepartmentGroupPKs", func() {
+		It("ok", func() {
+			mockManager.EXPECT().ListSubDepartmentRelationAfterExpiredAtBySubjectPKs([]int64{2, 3}, int64(0), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 2, GroupPK: 1}, {SubjectPK: 3, GroupPK: 1}}, nil,
+			)
+
+			pks, err := svc.ListSubDepartmentGroupPKs([]int64{2, 3})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []int64{1}, pks)
+		})
+	})
+
+	Describe("UpdateMembersIncludeSubDepartment", func() {
+		It("fail", func() {
+			mockManager.EXPECT().UpdateIncludeSubDepartment(int64(1), []int64{2}, true).Return(errors.New("error"))
+
+			err := svc.UpdateMembersIncludeSubDepartment(1, []int64{2}, true)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "UpdateIncludeSubDepartment")
+		})
+
+		It("ok", func() {
+			mockManager.EXPECT().UpdateIncludeSubDepartment(int64(1), []int64{2}, false).Return(nil)
+
+			err := svc.UpdateMembersIncludeSubDepartment(1, []int64{2}, false)
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+})
@@ -0,0 +1,147 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/jmoiron/sqlx"
+	jsoniter "github.com/json-iterator/go"
+
+	"iam/pkg/database"
+	"iam/pkg/database/dao"
+	"iam/pkg/service/types"
+)
+
+// SubjectAttributeSVC ...
+const SubjectAttributeSVC = "SubjectAttributeSVC"
+
+// SubjectAttributeService ...
+type SubjectAttributeService interface {
+	// 鉴权
+	GetBySubjectPK(subjectPK int64) (map[string]interface{}, error)
+
+	// web api
+	BulkUpdate(subjectAttributes []types.SubjectAttribute) error
+	BulkDelete(subjectPKs []int64) error
+
+	// for pap
+	BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error
+}
+
+type subjectAttributeService struct {
+	manager dao.SubjectAttributeManager
+}
+
+// NewSubjectAttributeService ...
+func NewSubjectAttributeService() SubjectAttributeService {
+	return &subjectAttributeService{
+		manager: dao.NewSubjectAttributeManager(),
+	}
+}
+
+// GetBySubjectPK 查询subject的所有属性
+func (l *subjectAttributeService) GetBySubjectPK(subjectPK int64) (map[string]interface{}, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectAttributeSVC, "GetBySubjectPK")
+
+	daoSubjectAttributes, err := l.manager.ListBySubjectPK(subjectPK)
+	if err != nil {
+		return nil, errorWrapf(err, "manager.ListBySubjectPK subjectPK=`%d` fail", subjectPK)
+	}
+
+	attributes := make(map[string]interface{}, len(daoSubjectAttributes))
+	for _, sa := range daoSubjectAttributes {
+		var value interface{}
+		err = jsoniter.UnmarshalFromString(sa.Value, &value)
+		if err != nil {
+			return nil, errorWrapf(err, "unmarshal subject attribute name=`%s`, value=`%s` fail", sa.Name, sa.Value)
+		}
+		attributes[sa.Name] = value
+	}
+	return attributes, nil
+}
+
+// BulkUpdate 全量覆盖subject的属性
+func (l *subjectAttributeService) BulkUpdate(subjectAttributes []types.SubjectAttribute) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectAttributeSVC, "BulkUpdate")
+	if len(subjectAttributes) == 0 {
+		return nil
+	}
+
+	subjectPKs := make([]int64, 0, len(subjectAttributes))
+	daoSubjectAttributes := make([]dao.SubjectAttribute, 0, len(subjectAttributes))
+	for _, sa := range subjectAttributes {
+		subjectPKs = append(subjectPKs, sa.SubjectPK)
+
+		for name, value := range sa.Attributes {
+			valueStr, err := jsoniter.MarshalToString(value)
+			if err != nil {
+				return errorWrapf(err, "marshal subject attribute name=`%s`, value=`%+v` fail", name, value)
+			}
+
+			daoSubjectAttributes = append(daoSubjectAttributes, dao.SubjectAttribute{
+				SubjectPK: sa.SubjectPK,
+				Name:      name,
+				Value:     valueStr,
+			})
+		}
+	}
+
+	tx, err := database.GenerateDefaultDBTx()
+	defer database.RollBackWithLog(tx)
+	if err != nil {
+		return errorWrapf(err, "define tx error")
+	}
+
+	err = l.manager.BulkDeleteBySubjectPKsWithTx(tx, subjectPKs)
+	if err != nil {
+		return errorWrapf(err, "manager.BulkDeleteBySubjectPKsWithTx subjectPKs=`%+v` fail", subjectPKs)
+	}
+
+	err = l.manager.BulkCreateWithTx(tx, daoSubjectAttributes)
+	if err != nil {
+		return errorWrapf(err, "manager.BulkCreateWithTx subjectAttributes=`%+v` fail", daoSubjectAttributes)
+	}
+
+	return tx.Commit()
+}
+
+// BulkDelete 删除subject的所有属性
+func (l *subjectAttributeService) BulkDelete(subjectPKs []int64) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectAttributeSVC, "BulkDelete")
+	if len(subjectPKs) == 0 {
+		return nil
+	}
+
+	tx, err := database.GenerateDefaultDBTx()
+	defer database.RollBackWithLog(tx)
+	if err != nil {
+		return errorWrapf(err, "define tx error")
+	}
+
+	err = l.manager.BulkDeleteBySubjectPKsWithTx(tx, subjectPKs)
+	if err != nil {
+		return errorWrapf(err, "manager.BulkDeleteBySubjectPKsWithTx subjectPKs=`%+v` fail", subjectPKs)
+	}
+
+	return tx.Commit()
+}
+
+// BulkDeleteBySubjectPKsWithTx ...
+func (l *subjectAttributeService) BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error {
+	err := l.manager.BulkDeleteBySubjectPKsWithTx(tx, subjectPKs)
+	if err != nil {
+		return errorx.Wrapf(err, SubjectAttributeSVC, "BulkDeleteBySubjectPKsWithTx",
+			"manager.BulkDeleteBySubjectPKsWithTx subjectPKs=`%+v` fail", subjectPKs)
+	}
+	return nil
+}
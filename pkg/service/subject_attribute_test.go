@@ -0,0 +1,127 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database"
+	"iam/pkg/database/dao"
+	"iam/pkg/database/dao/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("SubjectAttributeService", func() {
+	var ctl *gomock.Controller
+	var mockManager *mock.MockSubjectAttributeManager
+	var svc *subjectAttributeService
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockManager = mock.NewMockSubjectAttributeManager(ctl)
+		svc = &subjectAttributeService{manager: mockManager}
+	})
+	AfterEach(func() {
+		ctl.Finish()
+	})
+
+	Describe("GetBySubjectPK", func() {
+		It("manager.ListBySubjectPK fail", func() {
+			mockManager.EXPECT().ListBySubjectPK(int64(1)).Return(nil, errors.New("error"))
+
+			_, err := svc.GetBySubjectPK(1)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListBySubjectPK")
+		})
+
+		It("unmarshal fail", func() {
+			mockManager.EXPECT().ListBySubjectPK(int64(1)).Return(
+				[]dao.SubjectAttribute{{SubjectPK: 1, Name: "level", Value: "{"}}, nil,
+			)
+
+			_, err := svc.GetBySubjectPK(1)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "unmarshal")
+		})
+
+		It("ok", func() {
+			mockManager.EXPECT().ListBySubjectPK(int64(1)).Return(
+				[]dao.SubjectAttribute{
+					{SubjectPK: 1, Name: "level", Value: "3"},
+					{SubjectPK: 1, Name: "employment_type", Value: `"contractor"`},
+					{SubjectPK: 1, Name: "location", Value: `["sz","gz"]`},
+				}, nil,
+			)
+
+			attributes, err := svc.GetBySubjectPK(1)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[string]interface{}{
+				"level":           float64(3),
+				"employment_type": "contractor",
+				"location":        []interface{}{"sz", "gz"},
+			}, attributes)
+		})
+	})
+
+	Describe("BulkUpdate", func() {
+		var patches *gomonkey.Patches
+
+		BeforeEach(func() {
+			tx := &sql.Tx{}
+			patches = gomonkey.ApplyMethod(reflect.TypeOf(tx), "Commit", func(tx *sql.Tx) error {
+				return nil
+			})
+			patches.ApplyFunc(database.GenerateDefaultDBTx, func() (*sqlx.Tx, error) {
+				return &sqlx.Tx{Tx: tx}, nil
+			})
+			patches.ApplyFunc(database.RollBackWithLog, func(tx *sqlx.Tx) {})
+		})
+		AfterEach(func() {
+			patches.Reset()
+		})
+
+		It("empty", func() {
+			err := svc.BulkUpdate(nil)
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("manager.BulkDeleteBySubjectPKsWithTx fail", func() {
+			mockManager.EXPECT().BulkDeleteBySubjectPKsWithTx(gomock.Any(), []int64{1}).Return(errors.New("error"))
+
+			err := svc.BulkUpdate([]types.SubjectAttribute{
+				{SubjectPK: 1, Attributes: map[string]interface{}{"level": 3}},
+			})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "BulkDeleteBySubjectPKsWithTx")
+		})
+
+		It("ok", func() {
+			mockManager.EXPECT().BulkDeleteBySubjectPKsWithTx(gomock.Any(), []int64{1, 2}).Return(nil)
+			mockManager.EXPECT().BulkCreateWithTx(gomock.Any(), []dao.SubjectAttribute{
+				{SubjectPK: 1, Name: "level", Value: "3"},
+			}).Return(nil)
+
+			err := svc.BulkUpdate([]types.SubjectAttribute{
+				{SubjectPK: 1, Attributes: map[string]interface{}{"level": 3}},
+				{SubjectPK: 2, Attributes: map[string]interface{}{}},
+			})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+})
@@ -0,0 +1,199 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/collection/set"
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/jmoiron/sqlx"
+	jsoniter "github.com/json-iterator/go"
+
+	"iam/pkg/database/dao"
+	"iam/pkg/service/types"
+)
+
+// SubjectDelegationSVC ...
+const SubjectDelegationSVC = "SubjectDelegationSVC"
+
+// SubjectDelegationService ...
+type SubjectDelegationService interface {
+	// 鉴权
+	ListEffectByDelegatee(delegateePK int64) ([]types.SubjectDelegation, error)
+
+	// web api
+	Get(pk int64) (types.SubjectDelegation, error)
+	ListByDelegator(delegatorPK int64) ([]types.SubjectDelegation, error)
+	Create(delegation types.SubjectDelegation) error
+	Delete(pk int64) error
+	BulkDeleteByDelegatorPKs(delegatorPKs []int64) (delegateePKs []int64, err error)
+
+	// for pap
+	BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error
+}
+
+type subjectDelegationService struct {
+	manager dao.SubjectDelegationManager
+}
+
+// NewSubjectDelegationService ...
+func NewSubjectDelegationService() SubjectDelegationService {
+	return &subjectDelegationService{
+		manager: dao.NewSubjectDelegationManager(),
+	}
+}
+
+func convertToSubjectDelegation(delegation dao.SubjectDelegation) (types.SubjectDelegation, error) {
+	var actionPKs []int64
+	err := jsoniter.UnmarshalFromString(delegation.ActionPKs, &actionPKs)
+	if err != nil {
+		return types.SubjectDelegation{}, err
+	}
+
+	return types.SubjectDelegation{
+		PK:          delegation.PK,
+		DelegatorPK: delegation.DelegatorPK,
+		DelegateePK: delegation.DelegateePK,
+		SystemID:    delegation.SystemID,
+		ActionPKs:   actionPKs,
+		ExpiredAt:   delegation.ExpiredAt,
+	}, nil
+}
+
+func convertToSubjectDelegations(daoDelegations []dao.SubjectDelegation) ([]types.SubjectDelegation, error) {
+	delegations := make([]types.SubjectDelegation, 0, len(daoDelegations))
+	for _, d := range daoDelegations {
+		delegation, err := convertToSubjectDelegation(d)
+		if err != nil {
+			return nil, errorx.Wrapf(err, SubjectDelegationSVC, "convertToSubjectDelegations",
+				"unmarshal delegation pk=`%d` action_pks=`%s` fail", d.PK, d.ActionPKs)
+		}
+		delegations = append(delegations, delegation)
+	}
+	return delegations, nil
+}
+
+// ListEffectByDelegatee 查询delegatee未过期的委托
+func (l *subjectDelegationService) ListEffectByDelegatee(delegateePK int64) ([]types.SubjectDelegation, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectDelegationSVC, "ListEffectByDelegatee")
+
+	daoDelegations, err := l.manager.ListByDelegatee(delegateePK, time.Now().Unix())
+	if err != nil {
+		return nil, errorWrapf(err, "manager.ListByDelegatee delegateePK=`%d` fail", delegateePK)
+	}
+
+	delegations, err := convertToSubjectDelegations(daoDelegations)
+	if err != nil {
+		return nil, errorWrapf(err, "convertToSubjectDelegations fail")
+	}
+	return delegations, nil
+}
+
+// Get ...
+func (l *subjectDelegationService) Get(pk int64) (types.SubjectDelegation, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectDelegationSVC, "Get")
+
+	daoDelegation, err := l.manager.Get(pk)
+	if err != nil {
+		return types.SubjectDelegation{}, errorWrapf(err, "manager.Get pk=`%d` fail", pk)
+	}
+
+	delegation, err := convertToSubjectDelegation(daoDelegation)
+	if err != nil {
+		return types.SubjectDelegation{}, errorWrapf(err, "unmarshal action_pks=`%s` fail", daoDelegation.ActionPKs)
+	}
+	return delegation, nil
+}
+
+// ListByDelegator ...
+func (l *subjectDelegationService) ListByDelegator(delegatorPK int64) ([]types.SubjectDelegation, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectDelegationSVC, "ListByDelegator")
+
+	daoDelegations, err := l.manager.ListByDelegatorPKs([]int64{delegatorPK})
+	if err != nil {
+		return nil, errorWrapf(err, "manager.ListByDelegatorPKs delegatorPK=`%d` fail", delegatorPK)
+	}
+
+	delegations, err := convertToSubjectDelegations(daoDelegations)
+	if err != nil {
+		return nil, errorWrapf(err, "convertToSubjectDelegations fail")
+	}
+	return delegations, nil
+}
+
+// Create ...
+func (l *subjectDelegationService) Create(delegation types.SubjectDelegation) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectDelegationSVC, "Create")
+
+	actionPKs, err := jsoniter.MarshalToString(set.NewInt64SetWithValues(delegation.ActionPKs).ToSlice())
+	if err != nil {
+		return errorWrapf(err, "marshal action_pks=`%+v` fail", delegation.ActionPKs)
+	}
+
+	daoDelegation := dao.SubjectDelegation{
+		DelegatorPK: delegation.DelegatorPK,
+		DelegateePK: delegation.DelegateePK,
+		SystemID:    delegation.SystemID,
+		ActionPKs:   actionPKs,
+		ExpiredAt:   delegation.ExpiredAt,
+	}
+	err = l.manager.Create(daoDelegation)
+	if err != nil {
+		return errorWrapf(err, "manager.Create delegation=`%+v` fail", daoDelegation)
+	}
+	return nil
+}
+
+// Delete ...
+func (l *subjectDelegationService) Delete(pk int64) error {
+	_, err := l.manager.Delete(pk)
+	if err != nil {
+		return errorx.Wrapf(err, SubjectDelegationSVC, "Delete", "manager.Delete pk=`%d` fail", pk)
+	}
+	return nil
+}
+
+// BulkDeleteByDelegatorPKs 撤销delegators的所有委托, 返回受影响的delegatee用于清理缓存
+func (l *subjectDelegationService) BulkDeleteByDelegatorPKs(delegatorPKs []int64) (delegateePKs []int64, err error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectDelegationSVC, "BulkDeleteByDelegatorPKs")
+
+	delegations, err := l.manager.ListByDelegatorPKs(delegatorPKs)
+	if err != nil {
+		return nil, errorWrapf(err, "manager.ListByDelegatorPKs delegatorPKs=`%+v` fail", delegatorPKs)
+	}
+	if len(delegations) == 0 {
+		return nil, nil
+	}
+
+	_, err = l.manager.BulkDeleteByDelegatorPKs(delegatorPKs)
+	if err != nil {
+		return nil, errorWrapf(err, "manager.BulkDeleteByDelegatorPKs delegatorPKs=`%+v` fail", delegatorPKs)
+	}
+
+	delegateePKSet := set.NewInt64Set()
+	for _, d := range delegations {
+		delegateePKSet.Add(d.DelegateePK)
+	}
+	return delegateePKSet.ToSlice(), nil
+}
+
+// BulkDeleteBySubjectPKsWithTx ...
+func (l *subjectDelegationService) BulkDeleteBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error {
+	err := l.manager.BulkDeleteBySubjectPKsWithTx(tx, subjectPKs)
+	if err != nil {
+		return errorx.Wrapf(err, SubjectDelegationSVC, "BulkDeleteBySubjectPKsWithTx",
+			"manager.BulkDeleteBySubjectPKsWithTx subjectPKs=`%+v` fail", subjectPKs)
+	}
+	return nil
+}
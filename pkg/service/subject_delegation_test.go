@@ -0,0 +1,107 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database/dao"
+	"iam/pkg/database/dao/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("SubjectDelegationService", func() {
+	var ctl *gomock.Controller
+	var mockManager *mock.MockSubjectDelegationManager
+	var svc *subjectDelegationService
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockManager = mock.NewMockSubjectDelegationManager(ctl)
+		svc = &subjectDelegationService{manager: mockManager}
+	})
+	AfterEach(func() {
+		ctl.Finish()
+	})
+
+	Describe("ListEffectByDelegatee", func() {
+		It("manager.ListByDelegatee fail", func() {
+			mockManager.EXPECT().ListByDelegatee(int64(1), gomock.Any()).Return(nil, errors.New("error"))
+
+			_, err := svc.ListEffectByDelegatee(1)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListByDelegatee")
+		})
+
+		It("unmarshal fail", func() {
+			mockManager.EXPECT().ListByDelegatee(int64(1), gomock.Any()).Return(
+				[]dao.SubjectDelegation{{PK: 1, ActionPKs: "["}}, nil,
+			)
+
+			_, err := svc.ListEffectByDelegatee(1)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "convertToSubjectDelegations")
+		})
+
+		It("ok", func() {
+			mockManager.EXPECT().ListByDelegatee(int64(1), gomock.Any()).Return(
+				[]dao.SubjectDelegation{{
+					PK: 1, DelegatorPK: 2, DelegateePK: 1, SystemID: "test", ActionPKs: "[1,2]", ExpiredAt: 10,
+				}}, nil,
+			)
+
+			delegations, err := svc.ListEffectByDelegatee(1)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.SubjectDelegation{{
+				PK: 1, DelegatorPK: 2, DelegateePK: 1, SystemID: "test", ActionPKs: []int64{1, 2}, ExpiredAt: 10,
+			}}, delegations)
+		})
+	})
+
+	Describe("Create", func() {
+		It("ok", func() {
+			mockManager.EXPECT().Create(dao.SubjectDelegation{
+				DelegatorPK: 2, DelegateePK: 1, SystemID: "test", ActionPKs: "[1]", ExpiredAt: 10,
+			}).Return(nil)
+
+			err := svc.Create(types.SubjectDelegation{
+				DelegatorPK: 2, DelegateePK: 1, SystemID: "test", ActionPKs: []int64{1, 1}, ExpiredAt: 10,
+			})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("BulkDeleteByDelegatorPKs", func() {
+		It("empty", func() {
+			mockManager.EXPECT().ListByDelegatorPKs([]int64{2}).Return(nil, nil)
+
+			delegateePKs, err := svc.BulkDeleteByDelegatorPKs([]int64{2})
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), delegateePKs)
+		})
+
+		It("ok", func() {
+			mockManager.EXPECT().ListByDelegatorPKs([]int64{2}).Return(
+				[]dao.SubjectDelegation{{PK: 1, DelegatorPK: 2, DelegateePK: 1}, {PK: 2, DelegatorPK: 2, DelegateePK: 1}},
+				nil,
+			)
+			mockManager.EXPECT().BulkDeleteByDelegatorPKs([]int64{2}).Return(int64(2), nil)
+
+			delegateePKs, err := svc.BulkDeleteByDelegatorPKs([]int64{2})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []int64{1}, delegateePKs)
+		})
+	})
+})
@@ -0,0 +1,241 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+//go:generate mockgen -source=$GOFILE -destination=./mock/$GOFILE -package=mock
+
+import (
+	"sort"
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+	"github.com/jmoiron/sqlx"
+
+	"iam/pkg/database/dao"
+	"iam/pkg/service/types"
+)
+
+// SubjectGroupHistorySVC ...
+const SubjectGroupHistorySVC = "SubjectGroupHistorySVC"
+
+// SubjectGroupHistoryService ...
+type SubjectGroupHistoryService interface {
+	// web api
+	GetGroupHistoryCount(groupPK int64) (int64, error)
+	ListPagingGroupHistory(groupPK, limit, offset int64) ([]types.SubjectGroupHistory, error)
+	GetSubjectHistoryCount(subjectPK int64) (int64, error)
+	ListPagingSubjectHistory(subjectPK, limit, offset int64) ([]types.SubjectGroupHistory, error)
+	ListSubjectGroupsAt(subjectPK, at int64) ([]types.SubjectGroup, error)
+
+	// for pap
+	BulkCreateWithTx(tx *sqlx.Tx, histories []types.SubjectGroupHistory) error
+	BulkCreateSyncRemoveBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error
+}
+
+type subjectGroupHistoryService struct {
+	manager             dao.SubjectGroupHistoryManager
+	subjectGroupManager dao.SubjectGroupManager
+}
+
+// NewSubjectGroupHistoryService ...
+func NewSubjectGroupHistoryService() SubjectGroupHistoryService {
+	return &subjectGroupHistoryService{
+		manager:             dao.NewSubjectGroupHistoryManager(),
+		subjectGroupManager: dao.NewSubjectGroupManager(),
+	}
+}
+
+func convertToSubjectGroupHistories(daoHistories []dao.SubjectGroupHistory) []types.SubjectGroupHistory {
+	histories := make([]types.SubjectGroupHistory, 0, len(daoHistories))
+	for _, h := range daoHistories {
+		histories = append(histories, types.SubjectGroupHistory{
+			PK:          h.PK,
+			SubjectPK:   h.SubjectPK,
+			GroupPK:     h.GroupPK,
+			TemplateID:  h.TemplateID,
+			Action:      h.Action,
+			Source:      h.Source,
+			ExpiredAt:   h.ExpiredAt,
+			EffectiveAt: h.EffectiveAt,
+			Actor:       h.Actor,
+			CreatedAt:   h.CreatedAt,
+		})
+	}
+	return histories
+}
+
+// GetGroupHistoryCount ...
+func (l *subjectGroupHistoryService) GetGroupHistoryCount(groupPK int64) (int64, error) {
+	count, err := l.manager.GetGroupHistoryCount(groupPK)
+	if err != nil {
+		return 0, errorx.Wrapf(err, SubjectGroupHistorySVC, "GetGroupHistoryCount",
+			"manager.GetGroupHistoryCount groupPK=`%d` fail", groupPK)
+	}
+	return count, nil
+}
+
+// ListPagingGroupHistory ...
+func (l *subjectGroupHistoryService) ListPagingGroupHistory(
+	groupPK, limit, offset int64,
+) ([]types.SubjectGroupHistory, error) {
+	daoHistories, err := l.manager.ListPagingGroupHistory(groupPK, limit, offset)
+	if err != nil {
+		return nil, errorx.Wrapf(err, SubjectGroupHistorySVC, "ListPagingGroupHistory",
+			"manager.ListPagingGroupHistory groupPK=`%d`, limit=`%d`, offset=`%d` fail", groupPK, limit, offset)
+	}
+	return convertToSubjectGroupHistories(daoHistories), nil
+}
+
+// GetSubjectHistoryCount ...
+func (l *subjectGroupHistoryService) GetSubjectHistoryCount(subjectPK int64) (int64, error) {
+	count, err := l.manager.GetSubjectHistoryCount(subjectPK)
+	if err != nil {
+		return 0, errorx.Wrapf(err, SubjectGroupHistorySVC, "GetSubjectHistoryCount",
+			"manager.GetSubjectHistoryCount subjectPK=`%d` fail", subjectPK)
+	}
+	return count, nil
+}
+
+// ListPagingSubjectHistory ...
+func (l *subjectGroupHistoryService) ListPagingSubjectHistory(
+	subjectPK, limit, offset int64,
+) ([]types.SubjectGroupHistory, error) {
+	daoHistories, err := l.manager.ListPagingSubjectHistory(subjectPK, limit, offset)
+	if err != nil {
+		return nil, errorx.Wrapf(err, SubjectGroupHistorySVC, "ListPagingSubjectHistory",
+			"manager.ListPagingSubjectHistory subjectPK=`%d`, limit=`%d`, offset=`%d` fail", subjectPK, limit, offset)
+	}
+	return convertToSubjectGroupHistories(daoHistories), nil
+}
+
+type historyMembership struct {
+	expiredAt   int64
+	effectiveAt int64
+	createdAt   time.Time
+}
+
+// ListSubjectGroupsAt 回放变更记录, 查询subject在at时刻直接加入的用户组
+func (l *subjectGroupHistoryService) ListSubjectGroupsAt(subjectPK, at int64) ([]types.SubjectGroup, error) {
+	daoHistories, err := l.manager.ListSubjectHistoryBeforeCreatedAt(subjectPK, time.Unix(at, 0))
+	if err != nil {
+		return nil, errorx.Wrapf(err, SubjectGroupHistorySVC, "ListSubjectGroupsAt",
+			"manager.ListSubjectHistoryBeforeCreatedAt subjectPK=`%d`, at=`%d` fail", subjectPK, at)
+	}
+
+	// groupPK -> templateID -> membership, 手动加入的templateID为0
+	memberships := make(map[int64]map[int64]historyMembership)
+	for _, h := range daoHistories {
+		switch h.Action {
+		case types.SubjectGroupHistoryActionAdd, types.SubjectGroupHistoryActionRenew:
+			if _, ok := memberships[h.GroupPK]; !ok {
+				memberships[h.GroupPK] = make(map[int64]historyMembership)
+			}
+
+			m, ok := memberships[h.GroupPK][h.TemplateID]
+			if !ok || h.Action == types.SubjectGroupHistoryActionAdd {
+				m = historyMembership{effectiveAt: h.EffectiveAt, createdAt: h.CreatedAt}
+			}
+			m.expiredAt = h.ExpiredAt
+			memberships[h.GroupPK][h.TemplateID] = m
+		case types.SubjectGroupHistoryActionRemove:
+			// 同步删除subject时, 移除所有来源的成员关系
+			if h.Source == types.SubjectGroupHistorySourceSync {
+				delete(memberships, h.GroupPK)
+				continue
+			}
+			delete(memberships[h.GroupPK], h.TemplateID)
+		}
+	}
+
+	subjectGroups := make([]types.SubjectGroup, 0, len(memberships))
+	for groupPK, templateMemberships := range memberships {
+		var subjectGroup types.SubjectGroup
+		for _, m := range templateMemberships {
+			// 已过期或未生效
+			if m.expiredAt <= at || (m.effectiveAt > 0 && m.effectiveAt > at) {
+				continue
+			}
+
+			if subjectGroup.GroupPK == 0 || m.createdAt.Before(subjectGroup.CreatedAt) {
+				subjectGroup.CreatedAt = m.createdAt
+			}
+			if m.expiredAt > subjectGroup.ExpiredAt {
+				subjectGroup.ExpiredAt = m.expiredAt
+			}
+			subjectGroup.GroupPK = groupPK
+		}
+
+		if subjectGroup.GroupPK != 0 {
+			subjectGroups = append(subjectGroups, subjectGroup)
+		}
+	}
+
+	// 与ListPagingSubjectGroups一致, 最近加入的在前
+	sort.Slice(subjectGroups, func(i, j int) bool {
+		if subjectGroups[i].CreatedAt.Equal(subjectGroups[j].CreatedAt) {
+			return subjectGroups[i].GroupPK > subjectGroups[j].GroupPK
+		}
+		return subjectGroups[i].CreatedAt.After(subjectGroups[j].CreatedAt)
+	})
+	return subjectGroups, nil
+}
+
+// BulkCreateWithTx 与成员变更在同一个事务中记录变更
+func (l *subjectGroupHistoryService) BulkCreateWithTx(tx *sqlx.Tx, histories []types.SubjectGroupHistory) error {
+	daoHistories := make([]dao.SubjectGroupHistory, 0, len(histories))
+	for _, h := range histories {
+		daoHistories = append(daoHistories, dao.SubjectGroupHistory{
+			SubjectPK:   h.SubjectPK,
+			GroupPK:     h.GroupPK,
+			TemplateID:  h.TemplateID,
+			Action:      h.Action,
+			Source:      h.Source,
+			ExpiredAt:   h.ExpiredAt,
+			EffectiveAt: h.EffectiveAt,
+			Actor:       h.Actor,
+		})
+	}
+
+	err := l.manager.BulkCreateWithTx(tx, daoHistories)
+	if err != nil {
+		return errorx.Wrapf(err, SubjectGroupHistorySVC, "BulkCreateWithTx",
+			"manager.BulkCreateWithTx histories=`%+v` fail", daoHistories)
+	}
+	return nil
+}
+
+// BulkCreateSyncRemoveBySubjectPKsWithTx 同步删除subject时, 记录其退出所有用户组
+func (l *subjectGroupHistoryService) BulkCreateSyncRemoveBySubjectPKsWithTx(tx *sqlx.Tx, subjectPKs []int64) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(SubjectGroupHistorySVC, "BulkCreateSyncRemoveBySubjectPKsWithTx")
+
+	now := time.Now().Unix()
+	relations, err := l.subjectGroupManager.ListThinRelationAfterExpiredAtBySubjectPKs(subjectPKs, now, now)
+	if err != nil {
+		return errorWrapf(err, "subjectGroupManager.ListThinRelationAfterExpiredAtBySubjectPKs subjectPKs=`%+v` fail",
+			subjectPKs)
+	}
+
+	daoHistories := make([]dao.SubjectGroupHistory, 0, len(relations))
+	for _, r := range relations {
+		daoHistories = append(daoHistories, dao.SubjectGroupHistory{
+			SubjectPK: r.SubjectPK,
+			GroupPK:   r.GroupPK,
+			Action:    types.SubjectGroupHistoryActionRemove,
+			Source:    types.SubjectGroupHistorySourceSync,
+		})
+	}
+
+	err = l.manager.BulkCreateWithTx(tx, daoHistories)
+	if err != nil {
+		return errorWrapf(err, "manager.BulkCreateWithTx histories=`%+v` fail", daoHistories)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/database/dao"
+	"iam/pkg/database/dao/mock"
+	"iam/pkg/service/types"
+)
+
+var _ = Describe("SubjectGroupHistoryService", func() {
+	var ctl *gomock.Controller
+	var mockManager *mock.MockSubjectGroupHistoryManager
+	var mockSubjectGroupManager *mock.MockSubjectGroupManager
+	var svc *subjectGroupHistoryService
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockManager = mock.NewMockSubjectGroupHistoryManager(ctl)
+		mockSubjectGroupManager = mock.NewMockSubjectGroupManager(ctl)
+		svc = &subjectGroupHistoryService{manager: mockManager, subjectGroupManager: mockSubjectGroupManager}
+	})
+	AfterEach(func() {
+		ctl.Finish()
+	})
+
+	Describe("ListSubjectGroupsAt", func() {
+		It("manager.ListSubjectHistoryBeforeCreatedAt fail", func() {
+			mockManager.EXPECT().ListSubjectHistoryBeforeCreatedAt(int64(1), time.Unix(100, 0)).Return(
+				nil, errors.New("error"),
+			)
+
+			_, err := svc.ListSubjectGroupsAt(1, 100)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListSubjectHistoryBeforeCreatedAt")
+		})
+
+		It("ok", func() {
+			t1 := time.Unix(10, 0)
+			t2 := time.Unix(20, 0)
+			mockManager.EXPECT().ListSubjectHistoryBeforeCreatedAt(int64(1), time.Unix(100, 0)).Return(
+				[]dao.SubjectGroupHistory{
+					// 手动加入后续期
+					{GroupPK: 2, Action: "add", Source: "manual", ExpiredAt: 50, CreatedAt: t1},
+					{GroupPK: 2, Action: "renew", Source: "manual", ExpiredAt: 200, CreatedAt: t2},
+					// 加入后移除
+					{GroupPK: 3, Action: "add", Source: "manual", ExpiredAt: 200, CreatedAt: t1},
+					{GroupPK: 3, Action: "remove", Source: "manual", CreatedAt: t2},
+					// 已过期
+					{GroupPK: 4, Action: "add", Source: "manual", ExpiredAt: 50, CreatedAt: t1},
+					// 未生效
+					{GroupPK: 5, Action: "add", Source: "manual", ExpiredAt: 200, EffectiveAt: 150, CreatedAt: t1},
+					// 手动移除不影响人员模板加入的关系
+					{GroupPK: 6, Action: "add", Source: "template", TemplateID: 1, ExpiredAt: 300, CreatedAt: t2},
+					{GroupPK: 6, Action: "add", Source: "manual", ExpiredAt: 200, CreatedAt: t1},
+					{GroupPK: 6, Action: "remove", Source: "manual", CreatedAt: t2},
+					// 同步删除移除所有来源
+					{GroupPK: 7, Action: "add", Source: "template", TemplateID: 1, ExpiredAt: 300, CreatedAt: t1},
+					{GroupPK: 7, Action: "remove", Source: "sync", CreatedAt: t2},
+				}, nil,
+			)
+
+			subjectGroups, err := svc.ListSubjectGroupsAt(1, 100)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.SubjectGroup{
+				{GroupPK: 6, ExpiredAt: 300, CreatedAt: t2},
+				{GroupPK: 2, ExpiredAt: 200, CreatedAt: t1},
+			}, subjectGroups)
+		})
+	})
+
+	Describe("BulkCreateWithTx", func() {
+		It("ok", func() {
+			mockManager.EXPECT().BulkCreateWithTx(gomock.Any(), []dao.SubjectGroupHistory{{
+				SubjectPK: 1,
+				GroupPK:   2,
+				Action:    "add",
+				Source:    "manual",
+				ExpiredAt: 10,
+				Actor:     "admin",
+			}}).Return(nil)
+
+			err := svc.BulkCreateWithTx(nil, []types.SubjectGroupHistory{{
+				SubjectPK: 1,
+				GroupPK:   2,
+				Action:    "add",
+				Source:    "manual",
+				ExpiredAt: 10,
+				Actor:     "admin",
+			}})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+
+	Describe("BulkCreateSyncRemoveBySubjectPKsWithTx", func() {
+		It("subjectGroupManager fail", func() {
+			mockSubjectGroupManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, gomock.Any(), gomock.Any()).Return(
+				nil, errors.New("error"),
+			)
+
+			err := svc.BulkCreateSyncRemoveBySubjectPKsWithTx(nil, []int64{1})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListThinRelationAfterExpiredAtBySubjectPKs")
+		})
+
+		It("ok", func() {
+			mockSubjectGroupManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, gomock.Any(), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 1, GroupPK: 2, ExpiredAt: 10}}, nil,
+			)
+			mockManager.EXPECT().BulkCreateWithTx(gomock.Any(), []dao.SubjectGroupHistory{{
+				SubjectPK: 1,
+				GroupPK:   2,
+				Action:    "remove",
+				Source:    "sync",
+			}}).Return(nil)
+
+			err := svc.BulkCreateSyncRemoveBySubjectPKsWithTx(nil, []int64{1})
+			assert.NoError(GinkgoT(), err)
+		})
+	})
+})
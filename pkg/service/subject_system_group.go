@@ -229,6 +229,7 @@ func updateGroupsString(
 }
 
 // ListEffectThinSubjectGroups 批量获取 subject 有效的 groups(未过期的)
+// NOTE: 未到生效时间的成员关系不会写入subject system group, 由checker在生效时写入
 func (l *groupService) ListEffectThinSubjectGroups(
 	systemID string,
 	subjectPKs []int64,
@@ -262,6 +263,14 @@ func (l *groupService) ListEffectThinSubjectGroups(
 		}
 	}
 
+	// 合并通过嵌套用户组继承的上级用户组
+	err = l.mergeInheritedSubjectGroups(systemID, subjectPKs, now, subjectGroups)
+	if err != nil {
+		return nil, errorWrapf(
+			err, "mergeInheritedSubjectGroups systemID=`%s`, subjectPKs=`%+v` fail", systemID, subjectPKs,
+		)
+	}
+
 	return subjectGroups, nil
 }
 
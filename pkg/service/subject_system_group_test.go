@@ -396,8 +396,21 @@ var _ = Describe("SubjectService", func() {
 				[]dao.SubjectGroups{{SubjectPK: int64(1), Groups: fmt.Sprintf(`{"2": %d}`, ts)}}, nil,
 			).AnyTimes()
 
+			mockManager := mock.NewMockSubjectGroupManager(ctl)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, gomock.Any(), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 1, GroupPK: 2, ExpiredAt: ts}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{2}, gomock.Any(), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{}, nil,
+			)
+
+			mockDepartmentTreeManager := mock.NewMockDepartmentTreeManager(ctl)
+			mockDepartmentTreeManager.EXPECT().ListByDepartmentPKs([]int64{1}).Return(nil, nil)
+
 			manager := &groupService{
+				manager:                   mockManager,
 				subjectSystemGroupManager: mockSubjectSystemGroupManager,
+				departmentTreeManager:     mockDepartmentTreeManager,
 			}
 
 			groups, err := manager.ListEffectThinSubjectGroups("system", []int64{1})
@@ -407,5 +420,93 @@ var _ = Describe("SubjectService", func() {
 				ExpiredAt: ts,
 			}}}, groups)
 		})
+
+		It("nested groups", func() {
+			ts := time.Now().Unix() + 10
+
+			mockSubjectSystemGroupManager := mock.NewMockSubjectSystemGroupManager(ctl)
+			mockSubjectSystemGroupManager.EXPECT().ListSubjectGroups("system", []int64{1}).Return(
+				[]dao.SubjectGroups{{SubjectPK: int64(1), Groups: fmt.Sprintf(`{"2": %d}`, ts)}}, nil,
+			)
+
+			// subject 1 -> group 3 -> group 4 -> group 5, 只有group 4在系统中有授权
+			mockManager := mock.NewMockSubjectGroupManager(ctl)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, gomock.Any(), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 1, GroupPK: 3, ExpiredAt: ts + 10}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{3}, gomock.Any(), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 3, GroupPK: 4, ExpiredAt: ts + 20}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{4}, gomock.Any(), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 4, GroupPK: 5, ExpiredAt: ts}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{5}, gomock.Any(), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{}, nil,
+			)
+
+			mockAuthTypeManager := mock.NewMockGroupSystemAuthTypeManager(ctl)
+			mockAuthTypeManager.EXPECT().ListAuthTypeBySystemGroups("system", gomock.Any()).Return(
+				[]dao.GroupAuthType{{GroupPK: 4, AuthType: types.AuthTypeABAC}}, nil,
+			)
+
+			mockDepartmentTreeManager := mock.NewMockDepartmentTreeManager(ctl)
+			mockDepartmentTreeManager.EXPECT().ListByDepartmentPKs([]int64{1}).Return(nil, nil)
+
+			manager := &groupService{
+				manager:                   mockManager,
+				authTypeManger:            mockAuthTypeManager,
+				subjectSystemGroupManager: mockSubjectSystemGroupManager,
+				departmentTreeManager:     mockDepartmentTreeManager,
+			}
+
+			groups, err := manager.ListEffectThinSubjectGroups("system", []int64{1})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[int64][]types.ThinSubjectGroup{1: {
+				{GroupPK: 2, ExpiredAt: ts},
+				{GroupPK: 4, ExpiredAt: ts + 10},
+			}}, groups)
+		})
+
+		It("sub departments", func() {
+			ts := time.Now().Unix() + 10
+
+			mockSubjectSystemGroupManager := mock.NewMockSubjectSystemGroupManager(ctl)
+			mockSubjectSystemGroupManager.EXPECT().ListSubjectGroups("system", []int64{1}).Return(
+				[]dao.SubjectGroups{{SubjectPK: int64(1), Groups: fmt.Sprintf(`{"2": %d}`, ts)}}, nil,
+			)
+
+			// department 1 -> department 6, department 6 以包含子部门的方式加入 group 7
+			mockDepartmentTreeManager := mock.NewMockDepartmentTreeManager(ctl)
+			mockDepartmentTreeManager.EXPECT().ListByDepartmentPKs([]int64{1}).Return(
+				[]dao.DepartmentTree{{DepartmentPK: 1, ParentPK: 6}}, nil,
+			)
+			mockDepartmentTreeManager.EXPECT().ListByDepartmentPKs([]int64{6}).Return(nil, nil)
+
+			mockManager := mock.NewMockSubjectGroupManager(ctl)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{1}, gomock.Any(), gomock.Any()).Return(nil, nil)
+			mockManager.EXPECT().ListSubDepartmentRelationAfterExpiredAtBySubjectPKs([]int64{6}, gomock.Any(), gomock.Any()).Return(
+				[]dao.ThinSubjectRelation{{SubjectPK: 6, GroupPK: 7, ExpiredAt: ts + 5}}, nil,
+			)
+			mockManager.EXPECT().ListThinRelationAfterExpiredAtBySubjectPKs([]int64{7}, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+			mockAuthTypeManager := mock.NewMockGroupSystemAuthTypeManager(ctl)
+			mockAuthTypeManager.EXPECT().ListAuthTypeBySystemGroups("system", []int64{7}).Return(
+				[]dao.GroupAuthType{{GroupPK: 7, AuthType: types.AuthTypeRBAC}}, nil,
+			)
+
+			manager := &groupService{
+				manager:                   mockManager,
+				authTypeManger:            mockAuthTypeManager,
+				subjectSystemGroupManager: mockSubjectSystemGroupManager,
+				departmentTreeManager:     mockDepartmentTreeManager,
+			}
+
+			groups, err := manager.ListEffectThinSubjectGroups("system", []int64{1})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), map[int64][]types.ThinSubjectGroup{1: {
+				{GroupPK: 2, ExpiredAt: ts},
+				{GroupPK: 7, ExpiredAt: ts + 5},
+			}}, groups)
+		})
 	})
 })
@@ -32,6 +32,7 @@ type TemporaryPolicyService interface {
 	ListByPKs(pks []int64) ([]types.TemporaryPolicy, error)
 
 	// for saas
+	ListByActionPKsBeforeExpiredAt(actionPKs []int64, expiredAt int64) ([]types.TemporaryPolicy, error)
 	Create(policies []types.TemporaryPolicy) (pks []int64, err error)
 	DeleteByPKs(subjectPK int64, pks []int64) error
 	DeleteBeforeExpiredAt(expiredAt int64) error
@@ -80,6 +81,25 @@ func (s *temporaryPolicyService) ListByPKs(pks []int64) ([]types.TemporaryPolicy
 		return nil, errorWrapf(err, "manager.ListByPKs pks=`%+v`", pks)
 	}
 
+	return convertToTemporaryPolicies(daoPolicies), nil
+}
+
+// ListByActionPKsBeforeExpiredAt 查询操作下未过期的临时权限
+func (s *temporaryPolicyService) ListByActionPKsBeforeExpiredAt(
+	actionPKs []int64, expiredAt int64,
+) ([]types.TemporaryPolicy, error) {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(TemporaryPolicySVC, "ListByActionPKsBeforeExpiredAt")
+	daoPolicies, err := s.manager.ListByActionPKsBeforeExpiredAt(actionPKs, expiredAt)
+	if err != nil {
+		return nil, errorWrapf(
+			err, "manager.ListByActionPKsBeforeExpiredAt actionPKs=`%+v`, expiredAt=`%d`", actionPKs, expiredAt,
+		)
+	}
+
+	return convertToTemporaryPolicies(daoPolicies), nil
+}
+
+func convertToTemporaryPolicies(daoPolicies []dao.TemporaryPolicy) []types.TemporaryPolicy {
 	policies := make([]types.TemporaryPolicy, 0, len(daoPolicies))
 	for _, p := range daoPolicies {
 		policies = append(policies, types.TemporaryPolicy{
@@ -90,7 +110,7 @@ func (s *temporaryPolicyService) ListByPKs(pks []int64) ([]types.TemporaryPolicy
 			ExpiredAt:  p.ExpiredAt,
 		})
 	}
-	return policies, nil
+	return policies
 }
 
 // Create subject temporary policies
@@ -119,6 +119,55 @@ var _ = Describe("TemporaryPolicyService", func() {
 		})
 	})
 
+	Describe("ListByActionPKsBeforeExpiredAt", func() {
+		var ctl *gomock.Controller
+		BeforeEach(func() {
+			ctl = gomock.NewController(GinkgoT())
+		})
+		AfterEach(func() {
+			ctl.Finish()
+		})
+
+		It("manager.ListByActionPKsBeforeExpiredAt fail", func() {
+			mockTemporaryPolicyService := mock.NewMockTemporaryPolicyManager(ctl)
+			mockTemporaryPolicyService.EXPECT().ListByActionPKsBeforeExpiredAt(
+				[]int64{1}, int64(10),
+			).Return(
+				nil, errors.New("list fail"),
+			).AnyTimes()
+
+			manager := &temporaryPolicyService{
+				manager: mockTemporaryPolicyService,
+			}
+
+			_, err := manager.ListByActionPKsBeforeExpiredAt([]int64{1}, 10)
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "ListByActionPKsBeforeExpiredAt")
+		})
+
+		It("ok", func() {
+			mockTemporaryPolicyService := mock.NewMockTemporaryPolicyManager(ctl)
+			mockTemporaryPolicyService.EXPECT().ListByActionPKsBeforeExpiredAt(
+				[]int64{1}, int64(10),
+			).Return(
+				[]dao.TemporaryPolicy{{
+					PK:        1,
+					SubjectPK: 2,
+					ActionPK:  1,
+					ExpiredAt: 11,
+				}}, nil,
+			).AnyTimes()
+
+			manager := &temporaryPolicyService{
+				manager: mockTemporaryPolicyService,
+			}
+
+			ps, err := manager.ListByActionPKsBeforeExpiredAt([]int64{1}, 10)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []types.TemporaryPolicy{{PK: 1, SubjectPK: 2, ActionPK: 1, ExpiredAt: 11}}, ps)
+		})
+	})
+
 	Describe("Create", func() {
 		var ctl *gomock.Controller
 		BeforeEach(func() {
@@ -26,8 +26,14 @@ type ActionResourceType struct {
 }
 
 type ActionEnvironment struct {
-	Type string `json:"type" structs:"type"`
-	// Operators []string `json:"operators" structs:"operators"`
+	Type      string   `json:"type"                structs:"type"`
+	Operators []string `json:"operators,omitempty" structs:"operators"`
+}
+
+// ActionSubjectAttribute 操作的条件中允许使用的subject属性, 即 {system}._bk_iam_subject_.{id}
+type ActionSubjectAttribute struct {
+	ID        string   `json:"id"                  structs:"id"`
+	Operators []string `json:"operators,omitempty" structs:"operators"`
 }
 
 // ReferenceInstanceSelection ...
@@ -41,19 +47,20 @@ type ReferenceInstanceSelection struct {
 type Action struct {
 	AllowEmptyFields
 
-	ID                   string               `json:"id"                     structs:"id"`
-	Name                 string               `json:"name"                   structs:"name"`
-	NameEn               string               `json:"name_en"                structs:"name_en"`
-	Description          string               `json:"description"            structs:"description"`
-	DescriptionEn        string               `json:"description_en"         structs:"description_en"`
-	Sensitivity          int64                `json:"sensitivity"            structs:"sensitivity"`
-	AuthType             string               `json:"auth_type"              structs:"auth_type"`
-	Type                 string               `json:"type"                   structs:"type"`
-	Hidden               bool                 `json:"hidden"                 structs:"hidden"`
-	Version              int64                `json:"version"                structs:"version"`
-	RelatedResourceTypes []ActionResourceType `json:"related_resource_types" structs:"related_resource_types"`
-	RelatedActions       []string             `json:"related_actions"        structs:"related_actions"`
-	RelatedEnvironments  []ActionEnvironment  `json:"related_environments"   structs:"related_environments"`
+	ID                       string                   `json:"id"                         structs:"id"`
+	Name                     string                   `json:"name"                       structs:"name"`
+	NameEn                   string                   `json:"name_en"                    structs:"name_en"`
+	Description              string                   `json:"description"                structs:"description"`
+	DescriptionEn            string                   `json:"description_en"             structs:"description_en"`
+	Sensitivity              int64                    `json:"sensitivity"                structs:"sensitivity"`
+	AuthType                 string                   `json:"auth_type"                  structs:"auth_type"`
+	Type                     string                   `json:"type"                       structs:"type"`
+	Hidden                   bool                     `json:"hidden"                     structs:"hidden"`
+	Version                  int64                    `json:"version"                    structs:"version"`
+	RelatedResourceTypes     []ActionResourceType     `json:"related_resource_types"     structs:"related_resource_types"`
+	RelatedActions           []string                 `json:"related_actions"            structs:"related_actions"`
+	RelatedEnvironments      []ActionEnvironment      `json:"related_environments"       structs:"related_environments"`
+	RelatedSubjectAttributes []ActionSubjectAttribute `json:"related_subject_attributes" structs:"related_subject_attributes"` // nolint:lll
 }
 
 type ActionBaseInfo struct {
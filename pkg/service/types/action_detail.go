@@ -32,4 +32,10 @@ type ActionDetail struct {
 
 	// action resource types
 	ResourceTypes []ThinActionResourceType
+
+	// action related environment types
+	EnvironmentTypes []string
+
+	// action related subject attributes
+	SubjectAttributes []string
 }
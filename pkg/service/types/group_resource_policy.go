@@ -17,4 +17,35 @@ type ResourceChangedContent struct {
 	ActionRelatedResourceTypePK int64
 	CreatedActionPKs            []int64
 	DeletedActionPKs            []int64
+
+	// 操作关联多个资源类型时, 除第一个资源类型外其它资源类型授权的资源实例, 按操作关联资源类型的顺序
+	RelatedResources []RelatedResource
+}
+
+// GroupResourcePolicy 用户组授权的资源实例及操作
+type GroupResourcePolicy struct {
+	PK         int64
+	GroupPK    int64
+	TemplateID int64
+
+	ActionPKs                   []int64
+	ActionRelatedResourceTypePK int64
+
+	ResourceTypePK   int64
+	ResourceID       string
+	RelatedResources []RelatedResource
+}
+
+// RelatedResource RBAC授权资源实例组合中, 第一个资源实例之外的资源实例
+type RelatedResource struct {
+	ActionRelatedResourceTypePK int64  `json:"action_related_resource_type_pk" msgpack:"a"`
+	ResourceTypePK              int64  `json:"resource_type_pk"                msgpack:"t"`
+	ResourceID                  string `json:"resource_id"                     msgpack:"i"`
+}
+
+// AuthorizedGroup 资源实例授权的用户组
+// NOTE: RelatedResources不为空时, 需要同时匹配其它的资源实例才有权限
+type AuthorizedGroup struct {
+	GroupPK          int64             `msgpack:"g"`
+	RelatedResources []RelatedResource `msgpack:"r"`
 }
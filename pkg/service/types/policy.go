@@ -20,6 +20,8 @@ type AuthPolicy struct {
 	SubjectPK    int64 `msgpack:"s"`
 	ExpressionPK int64 `msgpack:"e1"`
 	ExpiredAt    int64 `msgpack:"e2"`
+	Effect       int64 `msgpack:"e3"`
+	EffectiveAt  int64 `msgpack:"e4"`
 }
 
 // AuthExpression for auth
@@ -43,6 +45,9 @@ type OpenAbacPolicy struct {
 	ActionPK     int64
 	ExpressionPK int64
 	ExpiredAt    int64
+	TemplateID   int64
+	Effect       int64
+	EffectiveAt  int64
 }
 
 type OpenRbacPolicy struct {
@@ -60,8 +65,10 @@ type EngineAbacPolicy struct {
 	ActionPK     int64
 	ExpressionPK int64
 
-	ExpiredAt  int64
-	TemplateID int64
+	ExpiredAt   int64
+	EffectiveAt int64
+	TemplateID  int64
+	Effect      int64
 
 	UpdatedAt time.Time
 }
@@ -77,8 +84,9 @@ type EngineRbacPolicy struct {
 	ActionRelatedResourceTypePK int64
 
 	// 授权的资源实例
-	ResourceTypePK int64
-	ResourceID     string
+	ResourceTypePK   int64
+	ResourceID       string
+	RelatedResources []RelatedResource
 
 	UpdatedAt time.Time
 }
@@ -93,8 +101,10 @@ type Policy struct {
 	Expression string
 	Signature  string
 
-	ExpiredAt  int64
-	TemplateID int64
+	ExpiredAt   int64
+	EffectiveAt int64
+	TemplateID  int64
+	Effect      int64
 }
 
 // ThinPolicy ...
@@ -102,6 +112,8 @@ type ThinPolicy struct {
 	Version string
 	ID      int64
 
-	ActionPK  int64
-	ExpiredAt int64
+	ActionPK    int64
+	ExpiredAt   int64
+	EffectiveAt int64
+	Effect      int64
 }
@@ -0,0 +1,62 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package types
+
+import "time"
+
+// 策略变更类型
+const (
+	PolicyHistoryOperationCreate = "create"
+	PolicyHistoryOperationUpdate = "update"
+	PolicyHistoryOperationDelete = "delete"
+)
+
+// PolicyResource RBAC授权的资源实例
+type PolicyResource struct {
+	System string `json:"system"`
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+}
+
+// PolicySnapshot 策略在某一版本的内容
+type PolicySnapshot struct {
+	Expression  string `json:"expression"`
+	ExpiredAt   int64  `json:"expired_at"`
+	Effect      int64  `json:"effect"`
+	EffectiveAt int64  `json:"effective_at"`
+	// RBAC授权的资源实例, 按操作关联资源类型的顺序, 为空表示ABAC策略
+	Resources []PolicyResource `json:"resources,omitempty"`
+}
+
+// PolicyHistory 策略变更记录, Before/After为nil表示策略不存在
+type PolicyHistory struct {
+	Version    int64           `json:"version"`
+	SubjectPK  int64           `json:"subject_pk"`
+	System     string          `json:"system"`
+	ActionPK   int64           `json:"action_pk"`
+	TemplateID int64           `json:"template_id"`
+	PolicyPK   int64           `json:"policy_pk"`
+	Operation  string          `json:"operation"`
+	Before     *PolicySnapshot `json:"before"`
+	After      *PolicySnapshot `json:"after"`
+	ClientID   string          `json:"client_id"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// PolicyChange 两个版本之间同一操作(模板)下策略的变化, 按effect区分, RBAC授权按资源实例区分
+type PolicyChange struct {
+	ActionPK   int64           `json:"action_pk"`
+	TemplateID int64           `json:"template_id"`
+	Before     *PolicySnapshot `json:"before"`
+	After      *PolicySnapshot `json:"after"`
+}
@@ -0,0 +1,35 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package types
+
+// SoD(Separation of Duties) 约束类型
+const (
+	SoDConstraintTypeGroup  = "group"
+	SoDConstraintTypeAction = "action"
+)
+
+// SoDConstraint 职责分离约束, 同一个subject不能同时拥有ItemPKs中的任意两个用户组/操作
+type SoDConstraint struct {
+	PK          int64   `json:"pk"`
+	Type        string  `json:"type"`
+	SystemID    string  `json:"system_id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	ItemPKs     []int64 `json:"item_pks"`
+}
+
+// SoDViolation 已存在的违反约束的授权
+type SoDViolation struct {
+	Constraint SoDConstraint `json:"constraint"`
+	SubjectPK  int64         `json:"subject_pk"`
+	// subject同时拥有的互斥的用户组/操作
+	ItemPKs []int64 `json:"item_pks"`
+}
@@ -26,12 +26,35 @@ type SubjectDepartment struct {
 	DepartmentPKs []int64 `json:"department_pks"`
 }
 
+// SubjectAttribute subject的属性, 用于条件中的 {system}._bk_iam_subject_.{name}
+type SubjectAttribute struct {
+	SubjectPK  int64                  `json:"subject_pk"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// SubjectDelegation 权限委托, delegatee在过期前拥有delegator在ActionPKs上的权限
+type SubjectDelegation struct {
+	PK          int64   `json:"pk"`
+	DelegatorPK int64   `json:"delegator_pk"`
+	DelegateePK int64   `json:"delegatee_pk"`
+	SystemID    string  `json:"system_id"`
+	ActionPKs   []int64 `json:"action_pks"`
+	ExpiredAt   int64   `json:"expired_at"`
+}
+
+// DepartmentParent 部门的上级部门, ParentPK为0表示根部门
+type DepartmentParent struct {
+	DepartmentPK int64 `json:"department_pk"`
+	ParentPK     int64 `json:"parent_pk"`
+}
+
 // GroupMember ...
 type GroupMember struct {
-	PK        int64     `json:"pk"`
-	SubjectPK int64     `json:"subject_pk"`
-	ExpiredAt int64     `json:"expired_at"`
-	CreatedAt time.Time `json:"created_at"`
+	PK          int64     `json:"pk"`
+	SubjectPK   int64     `json:"subject_pk"`
+	ExpiredAt   int64     `json:"expired_at"`
+	EffectiveAt int64     `json:"effective_at"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // SubjectGroup subject关联的组
@@ -144,6 +167,8 @@ type SubjectTemplateGroup struct {
 	TemplateID int64
 	GroupPK    int64
 	ExpiredAt  int64
+	// 生效时间, 大于0表示未生效, 到达生效时间后由checker激活
+	EffectiveAt int64
 
 	NeedUpdate bool // 是否需要更新subject system group, 默认为false
 }
@@ -0,0 +1,41 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package types
+
+import "time"
+
+// 用户组成员变更类型
+const (
+	SubjectGroupHistoryActionAdd    = "add"
+	SubjectGroupHistoryActionRemove = "remove"
+	SubjectGroupHistoryActionRenew  = "renew"
+)
+
+// 用户组成员变更来源
+const (
+	SubjectGroupHistorySourceManual   = "manual"
+	SubjectGroupHistorySourceTemplate = "template"
+	SubjectGroupHistorySourceSync     = "sync"
+)
+
+// SubjectGroupHistory 用户组成员变更记录, 通过人员模板变更时TemplateID大于0
+type SubjectGroupHistory struct {
+	PK          int64     `json:"pk"`
+	SubjectPK   int64     `json:"subject_pk"`
+	GroupPK     int64     `json:"group_pk"`
+	TemplateID  int64     `json:"template_id"`
+	Action      string    `json:"action"`
+	Source      string    `json:"source"`
+	ExpiredAt   int64     `json:"expired_at"`
+	EffectiveAt int64     `json:"effective_at"`
+	Actor       string    `json:"actor"`
+	CreatedAt   time.Time `json:"created_at"`
+}
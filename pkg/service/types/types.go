@@ -57,3 +57,43 @@ func ConvertToAuthTypeInt(authTypeStr string) int64 {
 
 	return AuthTypeNone
 }
+
+// ConvertToAuthTypeStr 将存储的auth type int值转换为字符串
+func ConvertToAuthTypeStr(authType int64) string {
+	switch authType {
+	case AuthTypeABAC:
+		return AuthTypeABACStr
+	case AuthTypeRBAC:
+		return AuthTypeRBACStr
+	case AuthTypeAll:
+		return AuthTypeAllStr
+	}
+
+	return AuthTypeNoneStr
+}
+
+const (
+	PolicyEffectAllow int64 = 0
+	PolicyEffectDeny  int64 = 1
+
+	PolicyEffectAllowStr = "allow"
+	PolicyEffectDenyStr  = "deny"
+)
+
+// ConvertToPolicyEffectInt 将策略的effect转换为存储的int值, 默认为allow
+func ConvertToPolicyEffectInt(effectStr string) int64 {
+	if effectStr == PolicyEffectDenyStr {
+		return PolicyEffectDeny
+	}
+
+	return PolicyEffectAllow
+}
+
+// ConvertToPolicyEffectStr 将存储的effect int值转换为字符串
+func ConvertToPolicyEffectStr(effect int64) string {
+	if effect == PolicyEffectDeny {
+		return PolicyEffectDenyStr
+	}
+
+	return PolicyEffectAllowStr
+}
@@ -24,3 +24,20 @@ func TestAllowEmptyFields(t *testing.T) {
 	a.AddKey("hello")
 	assert.True(t, a.HasKey("hello"))
 }
+
+func TestConvertToPolicyEffect(t *testing.T) {
+	assert.Equal(t, PolicyEffectAllow, ConvertToPolicyEffectInt(""))
+	assert.Equal(t, PolicyEffectAllow, ConvertToPolicyEffectInt(PolicyEffectAllowStr))
+	assert.Equal(t, PolicyEffectDeny, ConvertToPolicyEffectInt(PolicyEffectDenyStr))
+
+	assert.Equal(t, PolicyEffectAllowStr, ConvertToPolicyEffectStr(PolicyEffectAllow))
+	assert.Equal(t, PolicyEffectDenyStr, ConvertToPolicyEffectStr(PolicyEffectDeny))
+	assert.Equal(t, PolicyEffectAllowStr, ConvertToPolicyEffectStr(3))
+}
+
+func TestConvertToAuthType(t *testing.T) {
+	for _, authType := range []int64{AuthTypeNone, AuthTypeABAC, AuthTypeRBAC, AuthTypeAll} {
+		assert.Equal(t, authType, ConvertToAuthTypeInt(ConvertToAuthTypeStr(authType)))
+	}
+	assert.Equal(t, AuthTypeNoneStr, ConvertToAuthTypeStr(3))
+}
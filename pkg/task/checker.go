@@ -57,6 +57,9 @@ func (c *Checker) Run(ctx context.Context) {
 		producer.NewRedisProducer(rbacEventQueue),
 	).Run()
 
+	// Start subject group effective checker
+	go NewSubjectGroupEffectiveChecker().Run()
+
 	// Start rmq cleaner
 	go StartClean()
 
@@ -0,0 +1,191 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package task
+
+import (
+	"time"
+
+	"github.com/TencentBlueKing/gopkg/errorx"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/logging"
+	"iam/pkg/service"
+	"iam/pkg/service/types"
+	"iam/pkg/task/stats"
+	"iam/pkg/util"
+)
+
+const (
+	effectiveCheckerLayer = "EffectiveChecker"
+
+	effectiveLimit int64 = 1000
+)
+
+// SubjectGroupEffectiveChecker 定时激活已到生效时间的成员关系, 并创建group_alter_event
+// NOTE: 读路径按 effective_at <= now 判断成员关系是否生效, 不依赖本任务,
+// 本任务只负责同步subject system group, 清理缓存以及创建group_alter_event
+type SubjectGroupEffectiveChecker struct {
+	groupService           service.GroupService
+	groupAlterEventService service.GroupAlterEventService
+
+	stats *stats.Stats
+}
+
+func NewSubjectGroupEffectiveChecker() *SubjectGroupEffectiveChecker {
+	return &SubjectGroupEffectiveChecker{
+		groupService:           service.NewGroupService(),
+		groupAlterEventService: service.NewGroupAlterEventService(),
+
+		stats: stats.NewStats(effectiveCheckerLayer),
+	}
+}
+
+func (c *SubjectGroupEffectiveChecker) Run() {
+	logger := logging.GetWorkerLogger().WithField("layer", effectiveCheckerLayer)
+
+	for range time.Tick(1 * time.Minute) {
+		c.stats.TotalCount += 1
+
+		err := c.check()
+		if err != nil {
+			c.stats.FailCount += 1
+			logger.WithError(err).Error("check fail")
+
+			// report to sentry
+			util.ReportToSentry("SubjectGroupEffectiveChecker.check fail",
+				map[string]interface{}{
+					"layer": effectiveCheckerLayer,
+					"error": err.Error(),
+				},
+			)
+		} else {
+			c.stats.SuccessCount += 1
+		}
+
+		c.stats.Log(logger)
+	}
+}
+
+func (c *SubjectGroupEffectiveChecker) check() error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(effectiveCheckerLayer, "check")
+
+	now := time.Now().Unix()
+	for {
+		relations, err := c.groupService.ListPendingSubjectGroupsBeforeEffectiveAt(now, effectiveLimit)
+		if err != nil {
+			return errorWrapf(
+				err, "groupService.ListPendingSubjectGroupsBeforeEffectiveAt effectiveAt=`%d` fail", now,
+			)
+		}
+
+		if len(relations) == 0 {
+			return nil
+		}
+
+		err = c.groupService.ActivateSubjectGroups(relations)
+		if err != nil {
+			return errorWrapf(err, "groupService.ActivateSubjectGroups relations=`%+v` fail", relations)
+		}
+
+		err = c.alterSubjectGroups(relations)
+		if err != nil {
+			return errorWrapf(err, "alterSubjectGroups relations=`%+v` fail", relations)
+		}
+
+		logger := logging.GetWorkerLogger().WithField("layer", effectiveCheckerLayer)
+		logger.Infof("activated subject group relations: %d", len(relations))
+
+		// 每次查询用户组成员与人员模版成员各最多limit条, 都不足limit条时说明已处理完
+		if int64(len(relations)) < effectiveLimit {
+			return nil
+		}
+	}
+}
+
+// alterSubjectGroups 清理生效成员的缓存, 并创建group_alter_event, 包括通过嵌套用户组/子部门继承的subject
+func (c *SubjectGroupEffectiveChecker) alterSubjectGroups(relations []types.SubjectTemplateGroup) error {
+	errorWrapf := errorx.NewLayerFunctionErrorWrapf(effectiveCheckerLayer, "alterSubjectGroups")
+
+	groupSubjectPKs := map[int64][]int64{}
+	for _, r := range relations {
+		groupSubjectPKs[r.GroupPK] = append(groupSubjectPKs[r.GroupPK], r.SubjectPK)
+	}
+
+	for groupPK, pks := range groupSubjectPKs {
+		subjects, err := cacheimpls.BatchGetSubjectByPKs(pks)
+		if err != nil {
+			return errorWrapf(err, "cacheimpls.BatchGetSubjectByPKs pks=`%+v` fail", pks)
+		}
+
+		subjectPKs := make([]int64, 0, len(subjects))
+		departmentPKs := make([]int64, 0, len(subjects))
+		memberGroupPKs := make([]int64, 0, len(subjects))
+		for _, s := range subjects {
+			switch s.Type {
+			case types.GroupType:
+				memberGroupPKs = append(memberGroupPKs, s.PK)
+			case types.DepartmentType:
+				departmentPKs = append(departmentPKs, s.PK)
+				subjectPKs = append(subjectPKs, s.PK)
+			default:
+				subjectPKs = append(subjectPKs, s.PK)
+			}
+		}
+
+		cacheimpls.BatchDeleteSubjectAuthSystemGroupCache(pks, groupPK)
+
+		ancestorGroupPKs, err := c.groupService.ListAncestorGroupPKs(groupPK)
+		if err != nil {
+			return errorWrapf(err, "groupService.ListAncestorGroupPKs groupPK=`%d` fail", groupPK)
+		}
+
+		// 1. 通过嵌套用户组成员继承的subject
+		if len(memberGroupPKs) != 0 {
+			nestedSubjectPKs, err := c.groupService.ListNestedGroupMemberSubjectPKs(memberGroupPKs)
+			if err != nil {
+				return errorWrapf(
+					err, "groupService.ListNestedGroupMemberSubjectPKs groupPKs=`%+v` fail", memberGroupPKs,
+				)
+			}
+			subjectPKs = append(subjectPKs, nestedSubjectPKs...)
+		}
+
+		// 2. 通过部门成员(包含子部门)继承的子部门
+		if len(departmentPKs) != 0 {
+			subDepartmentPKs, err := c.groupService.ListSubDepartmentMemberPKs(groupPK, departmentPKs)
+			if err != nil {
+				return errorWrapf(
+					err, "groupService.ListSubDepartmentMemberPKs groupPK=`%d`, departmentPKs=`%+v` fail",
+					groupPK, departmentPKs,
+				)
+			}
+			subjectPKs = append(subjectPKs, subDepartmentPKs...)
+		}
+
+		if len(subjectPKs) == 0 {
+			continue
+		}
+
+		cacheimpls.BatchDeleteSubjectAllSystemGroupCache(subjectPKs)
+
+		for _, pk := range append([]int64{groupPK}, ancestorGroupPKs...) {
+			err = c.groupAlterEventService.CreateByGroupSubject(pk, subjectPKs)
+			if err != nil {
+				return errorWrapf(
+					err, "groupAlterEventService.CreateByGroupSubject groupPK=`%d`, subjectPKs=`%+v` fail",
+					pk, subjectPKs,
+				)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making 蓝鲸智云-权限中心(BlueKing-IAM) available.
+ * Copyright (C) 2017-2021 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package task
+
+import (
+	"errors"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+
+	"iam/pkg/cacheimpls"
+	"iam/pkg/service/mock"
+	"iam/pkg/service/types"
+	"iam/pkg/task/stats"
+)
+
+var _ = Describe("SubjectGroupEffectiveChecker", func() {
+	var ctl *gomock.Controller
+	var patches *gomonkey.Patches
+	var mockGroupService *mock.MockGroupService
+	var mockGroupAlterEventService *mock.MockGroupAlterEventService
+	var checker *SubjectGroupEffectiveChecker
+
+	BeforeEach(func() {
+		ctl = gomock.NewController(GinkgoT())
+		mockGroupService = mock.NewMockGroupService(ctl)
+		mockGroupAlterEventService = mock.NewMockGroupAlterEventService(ctl)
+		checker = &SubjectGroupEffectiveChecker{
+			groupService:           mockGroupService,
+			groupAlterEventService: mockGroupAlterEventService,
+			stats:                  stats.NewStats(effectiveCheckerLayer),
+		}
+
+		patches = gomonkey.ApplyFunc(cacheimpls.BatchDeleteSubjectAuthSystemGroupCache,
+			func(subjectPKs []int64, groupPK int64) {})
+		patches.ApplyFunc(cacheimpls.BatchDeleteSubjectAllSystemGroupCache, func(subjectPKs []int64) {})
+	})
+	AfterEach(func() {
+		ctl.Finish()
+		patches.Reset()
+	})
+
+	It("list fail", func() {
+		mockGroupService.EXPECT().ListPendingSubjectGroupsBeforeEffectiveAt(gomock.Any(), effectiveLimit).Return(
+			nil, errors.New("error"),
+		)
+
+		err := checker.check()
+		assert.Error(GinkgoT(), err)
+		assert.Contains(GinkgoT(), err.Error(), "ListPendingSubjectGroupsBeforeEffectiveAt")
+	})
+
+	It("empty", func() {
+		mockGroupService.EXPECT().ListPendingSubjectGroupsBeforeEffectiveAt(gomock.Any(), effectiveLimit).Return(
+			[]types.SubjectTemplateGroup{}, nil,
+		)
+
+		err := checker.check()
+		assert.NoError(GinkgoT(), err)
+	})
+
+	It("activate fail", func() {
+		relations := []types.SubjectTemplateGroup{{SubjectPK: 1, GroupPK: 10, EffectiveAt: 1}}
+		mockGroupService.EXPECT().ListPendingSubjectGroupsBeforeEffectiveAt(gomock.Any(), effectiveLimit).Return(
+			relations, nil,
+		)
+		mockGroupService.EXPECT().ActivateSubjectGroups(relations).Return(errors.New("error"))
+
+		err := checker.check()
+		assert.Error(GinkgoT(), err)
+		assert.Contains(GinkgoT(), err.Error(), "ActivateSubjectGroups")
+	})
+
+	It("ok", func() {
+		relations := []types.SubjectTemplateGroup{
+			{SubjectPK: 1, GroupPK: 10, EffectiveAt: 1},
+			{SubjectPK: 2, GroupPK: 10, EffectiveAt: 1},
+			{SubjectPK: 3, GroupPK: 10, TemplateID: 5, EffectiveAt: 1},
+		}
+		mockGroupService.EXPECT().ListPendingSubjectGroupsBeforeEffectiveAt(gomock.Any(), effectiveLimit).Return(
+			relations, nil,
+		)
+		mockGroupService.EXPECT().ActivateSubjectGroups(relations).Return(nil)
+
+		patches.ApplyFunc(cacheimpls.BatchGetSubjectByPKs, func(pks []int64) ([]types.Subject, error) {
+			return []types.Subject{
+				{PK: 1, Type: types.UserType},
+				{PK: 2, Type: types.DepartmentType},
+				{PK: 3, Type: types.GroupType},
+			}, nil
+		})
+		mockGroupService.EXPECT().ListAncestorGroupPKs(int64(10)).Return([]int64{20}, nil)
+		mockGroupService.EXPECT().ListNestedGroupMemberSubjectPKs([]int64{3}).Return([]int64{7}, nil)
+		mockGroupService.EXPECT().ListSubDepartmentMemberPKs(int64(10), []int64{2}).Return([]int64{8}, nil)
+		mockGroupAlterEventService.EXPECT().CreateByGroupSubject(int64(10), []int64{1, 2, 7, 8}).Return(nil)
+		mockGroupAlterEventService.EXPECT().CreateByGroupSubject(int64(20), []int64{1, 2, 7, 8}).Return(nil)
+
+		err := checker.check()
+		assert.NoError(GinkgoT(), err)
+	})
+})
@@ -162,6 +162,19 @@ func (h *groupAlterMessageHandler) alterSubjectActionGroupResource(subjectPK, ac
 		// FIXME (nan): 待底层 GetMaxExpiredAtBySubjectGroup 修复后，这里也对应进行修复
 		found := !errors.Is(err, service.ErrGroupMemberNotFound) && expiredAt != 0
 
+		// 通过嵌套用户组继承的关系, 取过期时间更大的
+		nestedExpiredAt, err := h.groupService.GetNestedExpiredAtBySubjectGroup(subjectPK, groupPK)
+		if err != nil {
+			return errorWrapf(err,
+				"groupService.GetNestedExpiredAtBySubjectGroup fail, subjectPK=`%d`, groupPK=`%d`",
+				subjectPK, groupPK,
+			)
+		}
+		if nestedExpiredAt > expiredAt {
+			expiredAt = nestedExpiredAt
+			found = true
+		}
+
 		// 查询group action授权资源实例
 		resourceMap, err := cacheimpls.GetGroupActionAuthorizedResource(
 			groupPK,
@@ -114,6 +114,9 @@ var _ = Describe("Handler", func() {
 			mockGroupService.EXPECT().
 				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0)).
 				Return(int64(0), service.ErrGroupMemberNotFound)
+			mockGroupService.EXPECT().
+				GetNestedExpiredAtBySubjectGroup(int64(1), int64(2)).
+				Return(int64(0), nil)
 
 			handler := &groupAlterMessageHandler{
 				subjectActionGroupResourceService: mockSubjectActionGroupResourceService,
@@ -144,6 +147,9 @@ var _ = Describe("Handler", func() {
 			mockGroupService.EXPECT().
 				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0)).
 				Return(int64(10), nil)
+			mockGroupService.EXPECT().
+				GetNestedExpiredAtBySubjectGroup(int64(1), int64(2)).
+				Return(int64(0), nil)
 
 			mockSubjectActionGroupResourceService.EXPECT().
 				CreateOrUpdateWithTx(gomock.Any(), types.SubjectActionGroupResource{
@@ -170,6 +176,54 @@ var _ = Describe("Handler", func() {
 			assert.Contains(GinkgoT(), err.Error(), "CreateOrUpdateWithTx")
 		})
 
+		It("nested group relation", func() {
+			patches.ApplyFunc(
+				cacheimpls.GetGroupActionAuthorizedResource,
+				func(_, _ int64) (map[int64][]string, error) {
+					return map[int64][]string{
+						1: {"1", "2"},
+					}, nil
+				},
+			)
+
+			mockSubjectActionGroupResourceService := mock.NewMockSubjectActionGroupResourceService(ctl)
+			mockSubjectActionGroupResourceService.EXPECT().
+				Get(int64(1), int64(3)).
+				Return(types.SubjectActionGroupResource{}, sql.ErrNoRows)
+
+			mockGroupService := mock.NewMockGroupService(ctl)
+			mockGroupService.EXPECT().
+				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0)).
+				Return(int64(0), service.ErrGroupMemberNotFound)
+			mockGroupService.EXPECT().
+				GetNestedExpiredAtBySubjectGroup(int64(1), int64(2)).
+				Return(int64(20), nil)
+
+			mockSubjectActionGroupResourceService.EXPECT().
+				CreateOrUpdateWithTx(gomock.Any(), types.SubjectActionGroupResource{
+					SubjectPK: 1,
+					ActionPK:  3,
+					GroupResource: map[int64]types.ResourceExpiredAt{
+						2: {
+							ExpiredAt: int64(20),
+							Resources: map[int64][]string{
+								1: {"1", "2"},
+							},
+						},
+					},
+				}).
+				Return(errors.New("error"))
+
+			handler := &groupAlterMessageHandler{
+				groupService:                      mockGroupService,
+				subjectActionGroupResourceService: mockSubjectActionGroupResourceService,
+				locker:                            locker.NewDistributedSubjectActionLocker(),
+			}
+			err := handler.alterSubjectActionGroupResource(1, 3, []int64{2})
+			assert.Error(GinkgoT(), err)
+			assert.Contains(GinkgoT(), err.Error(), "CreateOrUpdateWithTx")
+		})
+
 		It("subjectActionExpressionService.CreateOrUpdateWithTx error", func() {
 			patches.ApplyFunc(
 				cacheimpls.GetGroupActionAuthorizedResource,
@@ -195,6 +249,9 @@ var _ = Describe("Handler", func() {
 			mockGroupService.EXPECT().
 				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0)).
 				Return(int64(10), nil)
+			mockGroupService.EXPECT().
+				GetNestedExpiredAtBySubjectGroup(int64(1), int64(2)).
+				Return(int64(0), nil)
 
 			mockSubjectActionGroupResourceService.EXPECT().
 				CreateOrUpdateWithTx(gomock.Any(), types.SubjectActionGroupResource{
@@ -255,6 +312,9 @@ var _ = Describe("Handler", func() {
 			mockGroupService.EXPECT().
 				GetMaxExpiredAtBySubjectGroup(int64(1), int64(2), int64(0)).
 				Return(int64(10), nil)
+			mockGroupService.EXPECT().
+				GetNestedExpiredAtBySubjectGroup(int64(1), int64(2)).
+				Return(int64(0), nil)
 
 			mockSubjectActionGroupResourceService.EXPECT().
 				CreateOrUpdateWithTx(gomock.Any(), types.SubjectActionGroupResource{
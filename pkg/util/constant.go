@@ -17,6 +17,9 @@ const (
 
 	ClientIDKey = "client_id"
 
+	// OperatorHeaderKey 调用方传入的实际操作人
+	OperatorHeaderKey = "X-Bk-IAM-Operator"
+
 	ErrorIDKey = "err"
 
 	// NeverExpiresUnixTime 永久有效期，使用2100.01.01 00:00:00 的unix time作为永久有效期的表示，单位秒
@@ -55,6 +55,15 @@ func SetClientID(c *gin.Context, clientID string) {
 	c.Set(ClientIDKey, clientID)
 }
 
+// GetOperator 获取操作人, 调用方未传入时使用调用方的client_id
+func GetOperator(c *gin.Context) string {
+	operator := c.GetHeader(OperatorHeaderKey)
+	if operator == "" {
+		return GetClientID(c)
+	}
+	return operator
+}
+
 // GetError ...
 func GetError(c *gin.Context) (interface{}, bool) {
 	return c.Get(ErrorIDKey)
@@ -104,6 +104,23 @@ var _ = Describe("Request", func() {
 		})
 	})
 
+	Describe("Operator", func() {
+		var c *gin.Context
+		BeforeEach(func() {
+			c = &gin.Context{Request: &http.Request{Header: http.Header{}}}
+			util.SetClientID(c, "bk_iam_app")
+		})
+
+		It("from client id", func() {
+			assert.Equal(GinkgoT(), "bk_iam_app", util.GetOperator(c))
+		})
+
+		It("from header", func() {
+			c.Request.Header.Set(util.OperatorHeaderKey, "admin")
+			assert.Equal(GinkgoT(), "admin", util.GetOperator(c))
+		})
+	})
+
 	Describe("Error", func() {
 		var c *gin.Context
 		BeforeEach(func() {
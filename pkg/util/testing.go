@@ -235,6 +235,20 @@ func (g *GinAPIRequest) JSON(data interface{}) *GinAPIRequest {
 	return g
 }
 
+// Query ...
+func (g *GinAPIRequest) Query(key, value string) *GinAPIRequest {
+	g.request.Query(key, value)
+
+	return g
+}
+
+// Body ...
+func (g *GinAPIRequest) Body(body string) *GinAPIRequest {
+	g.request.Body(body)
+
+	return g
+}
+
 // NoJSON ...
 func (g *GinAPIRequest) NoJSON() {
 	g.request.
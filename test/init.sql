@@ -167,10 +167,10 @@ INSERT INTO `action_resource_type` VALUES
 (2,'demo','view_app','demo','app','2021-06-24 07:31:28','2021-06-24 07:31:28'),
 (3,'demo','edit_app','demo','app','2021-06-24 07:31:28','2021-06-24 07:31:28');
 INSERT INTO `saas_action` VALUES
-(1,'demo','access_developer_center','访问开发者中心','access developer center','一个用户是否能访问开发者中心','Is allowed to access the developer center','null','','create', 0, 0, 1,'2021-06-24 07:23:13','2021-06-24 07:23:13','null'),
-(2,'demo','develop_app','开发SaaS应用','develop app','一个用户是否能够开发SaaS','Is allowed to develop SaaS app','[\"access_developer_center\"]','','', 0, 0, 1,'2021-06-24 07:31:28','2021-06-24 07:31:28','null'),
-(3,'demo','view_app','查看应用','view app','一个用户是否能够查看应用','Is allowed to view app','[\"access_developer_center\"]','rbac','', 0, 0, 1,'2021-06-24 07:31:28','2021-06-24 07:31:28','null'),
-(4,'demo','edit_app','编辑应用','view app','一个用户是否能够编辑应用','Is allowed to edit app','[\"access_developer_center\"]','rbac','', 0, 0, 1,'2021-06-24 07:31:28','2021-06-24 07:31:28','null');
+(1,'demo','access_developer_center','访问开发者中心','access developer center','一个用户是否能访问开发者中心','Is allowed to access the developer center','null','','create', 0, 0, 1,'2021-06-24 07:23:13','2021-06-24 07:23:13','null',''),
+(2,'demo','develop_app','开发SaaS应用','develop app','一个用户是否能够开发SaaS','Is allowed to develop SaaS app','[\"access_developer_center\"]','','', 0, 0, 1,'2021-06-24 07:31:28','2021-06-24 07:31:28','null',''),
+(3,'demo','view_app','查看应用','view app','一个用户是否能够查看应用','Is allowed to view app','[\"access_developer_center\"]','rbac','', 0, 0, 1,'2021-06-24 07:31:28','2021-06-24 07:31:28','null',''),
+(4,'demo','edit_app','编辑应用','view app','一个用户是否能够编辑应用','Is allowed to edit app','[\"access_developer_center\"]','rbac','', 0, 0, 1,'2021-06-24 07:31:28','2021-06-24 07:31:28','null','');
 INSERT INTO `saas_action_resource_type` VALUES
 (1,'demo','develop_app','demo','app','','','instance','[{\"id\":\"app_view\",\"ignore_iam_path\":false,\"system_id\":\"demo\"}]','2021-06-24 07:31:28','2021-06-24 07:31:28'),
 (2,'demo','view_app','demo','app','','','instance','[{\"id\":\"app_view\",\"ignore_iam_path\":false,\"system_id\":\"demo\"},{\"id\":\"project_view\",\"ignore_iam_path\":false,\"system_id\":\"demo\"}]','2021-06-24 07:31:28','2021-06-24 07:31:28'),
@@ -342,17 +342,17 @@ INSERT INTO `group_system_auth_type` (`system_id`, `group_pk`, `auth_type`) VALU
 
 
 -- rbac_group_resource_policy
-INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`) VALUES 
-("b43770b386441505ad200bf14b85ec44", 2105, 0, "demo", "[3]", 1, 1, "002");
-INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`) VALUES 
-("ec4c2b529af94e255ab578055ddc91ae", 2106, 0, "demo", "[3]", 1, 1, "002");
-INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`) VALUES 
-("063890f02170124bad493a0335847275", 2107, 0, "demo", "[3]", 1, 1, "002");
-INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`) VALUES 
-("7d053f20b1afc58db479f07eaffafd50", 2125, 0, "demo", "[3]", 1, 1, "002");
-INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`) VALUES 
-("c99c79d3321cb5c289e72b7fbd876758", 2126, 0, "demo", "[3]", 1, 1, "002");
-INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`) VALUES 
-("e848ca9bc61dfe3dce55a3a17f46e2b9", 2127, 0, "demo", "[3]", 1, 1, "002");
-INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`) VALUES 
-("647fd04120c00f5ebca00131f3636719", 2105, 0, "demo", "[3]", 1, 2, "002");
+INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`, `related_resources`) VALUES 
+("b43770b386441505ad200bf14b85ec44", 2105, 0, "demo", "[3]", 1, 1, "002", "");
+INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`, `related_resources`) VALUES 
+("ec4c2b529af94e255ab578055ddc91ae", 2106, 0, "demo", "[3]", 1, 1, "002", "");
+INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`, `related_resources`) VALUES 
+("063890f02170124bad493a0335847275", 2107, 0, "demo", "[3]", 1, 1, "002", "");
+INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`, `related_resources`) VALUES 
+("7d053f20b1afc58db479f07eaffafd50", 2125, 0, "demo", "[3]", 1, 1, "002", "");
+INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`, `related_resources`) VALUES 
+("c99c79d3321cb5c289e72b7fbd876758", 2126, 0, "demo", "[3]", 1, 1, "002", "");
+INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`, `related_resources`) VALUES 
+("e848ca9bc61dfe3dce55a3a17f46e2b9", 2127, 0, "demo", "[3]", 1, 1, "002", "");
+INSERT INTO `rbac_group_resource_policy` (`signature`, `group_pk`, `template_id`, `system_id`, `action_pks`, `action_related_resource_type_pk`, `resource_type_pk`, `resource_id`, `related_resources`) VALUES 
+("647fd04120c00f5ebca00131f3636719", 2105, 0, "demo", "[3]", 1, 2, "002", "");